@@ -0,0 +1,432 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generatedNotice marca los artefactos para que nadie los edite a mano.
+const generatedNotice = "Code generated by eventgen. DO NOT EDIT."
+
+// contract es un struct del paquete de eventos que se serializa a JSON: todos sus campos
+// exportados llevan etiqueta json. Los demás (EventMetadata, Schema...) no son contratos.
+type contract struct {
+	Name   string
+	Doc    string
+	Fields []field
+}
+
+type field struct {
+	JSONName string
+	Doc      string
+	Type     fieldType
+	Optional bool // omitempty: puede no venir
+}
+
+// fieldType es el tipo JSON de un campo.
+type fieldType struct {
+	Kind     string // string, integer, number, boolean, any, array, object o ref
+	Format   string // uuid o date-time, para los string
+	Elem     *fieldType
+	Ref      string // el contrato referenciado, para ref
+	Nullable bool
+}
+
+// parseContracts lee los contratos del paquete Go de 'dir', ordenados por nombre.
+func parseContracts(dir string) ([]contract, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	fset := token.NewFileSet()
+	var specs []*ast.TypeSpec
+	docs := make(map[*ast.TypeSpec]*ast.CommentGroup)
+	pkg := packageTypes{contracts: make(map[string]bool), named: make(map[string]ast.Expr)}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if !ts.Name.IsExported() {
+					continue
+				}
+				if _, ok := ts.Type.(*ast.StructType); !ok {
+					pkg.named[ts.Name.Name] = ts.Type
+					continue
+				}
+				specs = append(specs, ts)
+				docs[ts] = ts.Doc
+				if docs[ts] == nil && len(gd.Specs) == 1 {
+					docs[ts] = gd.Doc
+				}
+			}
+		}
+	}
+
+	// Primero los nombres, para resolver los campos que referencian otro contrato.
+	for _, ts := range specs {
+		if isContract(ts.Type.(*ast.StructType)) {
+			pkg.contracts[ts.Name.Name] = true
+		}
+	}
+
+	var contracts []contract
+	for _, ts := range specs {
+		if !pkg.contracts[ts.Name.Name] {
+			continue
+		}
+		c := contract{Name: ts.Name.Name, Doc: docs[ts].Text()}
+		for _, f := range ts.Type.(*ast.StructType).Fields.List {
+			if len(f.Names) == 0 || !f.Names[0].IsExported() {
+				continue
+			}
+			name, opts := jsonTag(f)
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Names[0].Name
+			}
+			t, err := pkg.typeOf(f.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", c.Name, f.Names[0].Name, err)
+			}
+			doc := f.Doc.Text()
+			if doc == "" {
+				doc = f.Comment.Text()
+			}
+			c.Fields = append(c.Fields, field{JSONName: name, Doc: doc, Type: t, Optional: strings.Contains(opts, "omitempty")})
+		}
+		contracts = append(contracts, c)
+	}
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i].Name < contracts[j].Name })
+	return contracts, nil
+}
+
+// isContract dice si todos los campos exportados del struct llevan etiqueta json.
+func isContract(st *ast.StructType) bool {
+	exported := 0
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 || !f.Names[0].IsExported() {
+			continue
+		}
+		exported++
+		if name, _ := jsonTag(f); name == "" {
+			return false
+		}
+	}
+	return exported > 0
+}
+
+// jsonTag devuelve el nombre y las opciones de la etiqueta json del campo.
+func jsonTag(f *ast.Field) (name, opts string) {
+	if f.Tag == nil {
+		return "", ""
+	}
+	raw, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return "", ""
+	}
+	name, opts, _ = strings.Cut(reflect.StructTag(raw).Get("json"), ",")
+	return name, opts
+}
+
+// packageTypes son los tipos del paquete que pueden aparecer en los campos de un contrato.
+type packageTypes struct {
+	contracts map[string]bool     // los structs que se pueden referenciar
+	named     map[string]ast.Expr // los demás tipos con nombre y su definición (type PayloadMode string)
+}
+
+// typeOf traduce el tipo Go de un campo a su tipo JSON.
+func (pkg packageTypes) typeOf(expr ast.Expr) (fieldType, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			return fieldType{Kind: "string"}, nil
+		case "bool":
+			return fieldType{Kind: "boolean"}, nil
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return fieldType{Kind: "integer"}, nil
+		case "float32", "float64":
+			return fieldType{Kind: "number"}, nil
+		case "any":
+			return fieldType{Kind: "any"}, nil
+		}
+		if pkg.contracts[e.Name] {
+			return fieldType{Kind: "ref", Ref: e.Name}, nil
+		}
+		if def, ok := pkg.named[e.Name]; ok {
+			return pkg.typeOf(def)
+		}
+	case *ast.SelectorExpr:
+		switch fmt.Sprintf("%s.%s", e.X, e.Sel.Name) {
+		case "uuid.UUID":
+			return fieldType{Kind: "string", Format: "uuid"}, nil
+		case "time.Time":
+			return fieldType{Kind: "string", Format: "date-time"}, nil
+		case "json.RawMessage":
+			return fieldType{Kind: "any"}, nil
+		}
+	case *ast.InterfaceType:
+		return fieldType{Kind: "any"}, nil
+	case *ast.StarExpr:
+		t, err := pkg.typeOf(e.X)
+		t.Nullable = true
+		return t, err
+	case *ast.ArrayType:
+		elem, err := pkg.typeOf(e.Elt)
+		return fieldType{Kind: "array", Elem: &elem, Nullable: e.Len == nil}, err
+	case *ast.MapType:
+		if key, ok := e.Key.(*ast.Ident); !ok || key.Name != "string" {
+			break
+		}
+		elem, err := pkg.typeOf(e.Value)
+		return fieldType{Kind: "object", Elem: &elem, Nullable: true}, err
+	}
+	return fieldType{}, fmt.Errorf("unsupported type %T", expr)
+}
+
+// render devuelve los artefactos por ruta relativa: schema/<Contrato>.json y
+// typescript/events.ts.
+func render(contracts []contract) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(contracts)+1)
+	for _, c := range contracts {
+		schema, err := json.MarshalIndent(jsonSchema(c), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join("schema", c.Name+".json")] = append(schema, '\n')
+	}
+	files[filepath.Join("typescript", "events.ts")] = typeScript(contracts)
+	return files, nil
+}
+
+// object es un objeto JSON que conserva el orden de sus claves.
+type object []member
+
+type member struct {
+	Key   string
+	Value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.Key)
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonSchema es el JSON Schema (draft 2020-12) del contrato. Los campos sin omitempty son
+// obligatorios; se admiten campos adicionales para que añadir uno no rompa a los consumidores.
+func jsonSchema(c contract) object {
+	properties := make(object, 0, len(c.Fields))
+	required := []string{}
+	for _, f := range c.Fields {
+		schema := schemaOf(f.Type)
+		if f.Doc != "" {
+			schema = append(object{{"description", oneLine(f.Doc)}}, schema...)
+		}
+		properties = append(properties, member{f.JSONName, schema})
+		if !f.Optional {
+			required = append(required, f.JSONName)
+		}
+	}
+
+	schema := object{
+		{"$schema", "https://json-schema.org/draft/2020-12/schema"},
+		{"$id", c.Name + ".json"},
+		{"$comment", generatedNotice},
+		{"title", c.Name},
+	}
+	if c.Doc != "" {
+		schema = append(schema, member{"description", oneLine(c.Doc)})
+	}
+	return append(schema,
+		member{"type", "object"},
+		member{"properties", properties},
+		member{"required", required},
+	)
+}
+
+func schemaOf(t fieldType) object {
+	var schema object
+	switch t.Kind {
+	case "any":
+		return object{}
+	case "ref":
+		schema = object{{"$ref", t.Ref + ".json"}}
+		if t.Nullable {
+			return object{{"anyOf", []object{schema, {{"type", "null"}}}}}
+		}
+		return schema
+	case "array":
+		schema = object{{"type", jsonType(t)}, {"items", schemaOf(*t.Elem)}}
+	case "object":
+		schema = object{{"type", jsonType(t)}, {"additionalProperties", schemaOf(*t.Elem)}}
+	default:
+		schema = object{{"type", jsonType(t)}}
+		if t.Format != "" {
+			schema = append(schema, member{"format", t.Format})
+		}
+	}
+	return schema
+}
+
+// jsonType es el "type" de JSON Schema: el tipo, o [tipo, "null"] si admite null.
+func jsonType(t fieldType) any {
+	if t.Nullable {
+		return []string{t.Kind, "null"}
+	}
+	return t.Kind
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScript declara una interfaz por contrato. Los uuid y las fechas son string con alias
+// propios, para que se vea qué formato llevan.
+func typeScript(contracts []contract) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", generatedNotice)
+	b.WriteString("/** Identificador UUID en su forma canónica (8-4-4-4-12). */\n")
+	b.WriteString("export type UUID = string;\n\n")
+	b.WriteString("/** Fecha y hora en RFC 3339. */\n")
+	b.WriteString("export type DateTime = string;\n")
+
+	for _, c := range contracts {
+		b.WriteString("\n")
+		writeDoc(&b, "", c.Doc)
+		fmt.Fprintf(&b, "export interface %s {\n", c.Name)
+		for _, f := range c.Fields {
+			writeDoc(&b, "  ", f.Doc)
+			name := f.JSONName
+			if !tsIdentifier.MatchString(name) {
+				name = strconv.Quote(name)
+			}
+			optional := ""
+			if f.Optional {
+				optional = "?"
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", name, optional, tsType(f.Type))
+		}
+		b.WriteString("}\n")
+	}
+	return []byte(b.String())
+}
+
+func tsType(t fieldType) string {
+	var s string
+	switch t.Kind {
+	case "string":
+		switch t.Format {
+		case "uuid":
+			s = "UUID"
+		case "date-time":
+			s = "DateTime"
+		default:
+			s = "string"
+		}
+	case "integer", "number":
+		s = "number"
+	case "boolean":
+		s = "boolean"
+	case "ref":
+		s = t.Ref
+	case "array":
+		s = tsType(*t.Elem)
+		if strings.Contains(s, " ") {
+			s = "(" + s + ")"
+		}
+		s += "[]"
+	case "object":
+		s = "Record<string, " + tsType(*t.Elem) + ">"
+	default:
+		s = "unknown"
+	}
+	if t.Nullable {
+		s += " | null"
+	}
+	return s
+}
+
+func writeDoc(b *strings.Builder, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s *%s\n", indent, strings.TrimRight(" "+line, " "))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// oneLine junta en una línea un comentario de varias.
+func oneLine(doc string) string {
+	return strings.Join(strings.Fields(doc), " ")
+}
+
+// writeFiles escribe los artefactos en 'out'. Antes borra los JSON Schema que haya, para que no
+// queden los de contratos que ya no existen.
+func writeFiles(out string, files map[string][]byte) error {
+	stale, err := filepath.Glob(filepath.Join(out, "schema", "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	for name, content := range files {
+		path := filepath.Join(out, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// eventgen genera los contratos tipados de los eventos de integración para quien los consume
+// fuera de Go: un JSON Schema por struct del paquete de eventos y un fichero de tipos de
+// TypeScript con todos. Se ejecuta con go generate desde internal/shared/domain/events.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "eventgen:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run lee los contratos del paquete 'src' y escribe los artefactos en 'out'.
+func run(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("eventgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	src := fs.String("src", ".", "Directory of the Go package with the event contracts")
+	out := fs.String("out", "gen/events", "Output directory for the JSON Schemas and the TypeScript typings")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	contracts, err := parseContracts(*src)
+	if err != nil {
+		return err
+	}
+	files, err := render(contracts)
+	if err != nil {
+		return err
+	}
+	return writeFiles(*out, files)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/internal/shared/domain/events"
+)
+
+const (
+	eventsDir    = "../../internal/shared/domain/events"
+	artifactsDir = "../../gen/events"
+)
+
+// TestArtifactsUpToDate falla si alguien cambia un contrato sin volver a ejecutar go generate.
+func TestArtifactsUpToDate(t *testing.T) {
+	// Arrange
+	contracts, err := parseContracts(eventsDir)
+	require.NoError(t, err)
+
+	// Act
+	files, err := render(contracts)
+	require.NoError(t, err)
+
+	// Assert
+	var want []string
+	for name, content := range files {
+		want = append(want, name)
+		got, err := os.ReadFile(filepath.Join(artifactsDir, name))
+		require.NoError(t, err, "missing artifact; run go generate ./internal/shared/domain/events")
+		assert.Equal(t, string(content), string(got), "%s is stale; run go generate ./internal/shared/domain/events", name)
+	}
+	schemas, err := filepath.Glob(filepath.Join(artifactsDir, "schema", "*.json"))
+	require.NoError(t, err)
+	got := []string{filepath.Join("typescript", "events.ts")}
+	for _, path := range schemas {
+		got = append(got, filepath.Join("schema", filepath.Base(path)))
+	}
+	sort.Strings(want)
+	sort.Strings(got)
+	assert.Equal(t, want, got, "artifacts without a contract; run go generate ./internal/shared/domain/events")
+}
+
+func TestParseContracts(t *testing.T) {
+	// Act
+	contracts, err := parseContracts(eventsDir)
+	require.NoError(t, err)
+
+	// Assert: solo los structs serializables; EventMetadata, Schema o SchemaError no lo son.
+	byName := make(map[string]contract)
+	for _, c := range contracts {
+		byName[c.Name] = c
+	}
+	assert.Contains(t, byName, "IntegrationEvent")
+	assert.Contains(t, byName, "UserCreated")
+	assert.NotContains(t, byName, "EventMetadata")
+	assert.NotContains(t, byName, "SchemaError")
+
+	envelope := byName["IntegrationEvent"]
+	require.NotEmpty(t, envelope.Fields)
+	assert.Equal(t, field{JSONName: "event_id", Type: fieldType{Kind: "string", Format: "uuid"}}, envelope.Fields[0])
+	for _, f := range envelope.Fields {
+		if f.JSONName == "actor" {
+			assert.True(t, f.Optional, "omitempty")
+		}
+	}
+}
+
+// TestSchemasMatchGoValues comprueba que los esquemas generados aceptan lo que produce Go.
+func TestSchemasMatchGoValues(t *testing.T) {
+	values := map[string]any{
+		"IntegrationEvent": events.IntegrationEvent{Type: "user.created", Data: json.RawMessage(`{"id": "1"}`)},
+		"UserCreated":      events.UserCreated{Email: "ana@example.com"},
+		"TaskUpdated":      events.TaskUpdated{Status: "completed"},
+	}
+	contracts, err := parseContracts(eventsDir)
+	require.NoError(t, err)
+
+	for _, c := range contracts {
+		value, ok := values[c.Name]
+		if !ok {
+			continue
+		}
+		t.Run(c.Name, func(t *testing.T) {
+			// Arrange
+			raw, err := json.Marshal(jsonSchema(c))
+			require.NoError(t, err)
+			schema, err := events.CompileSchema(raw)
+			require.NoError(t, err)
+			payload, err := json.Marshal(value)
+			require.NoError(t, err)
+
+			// Act
+			err = schema.Validate(payload)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Error(t, schema.Validate([]byte(`{}`)), "Los campos sin omitempty son obligatorios")
+		})
+	}
+}
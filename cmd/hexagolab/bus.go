@@ -0,0 +1,471 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	config "github.com/davicafu/hexagolab/internal/config"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/audit"
+	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	sharedPostgres "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/postgres"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/health"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+	infraRelayer "github.com/davicafu/hexagolab/internal/shared/infra/relayer"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskEvents "github.com/davicafu/hexagolab/internal/task/infra/inbound/events"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userEvents "github.com/davicafu/hexagolab/internal/user/infra/inbound/events"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+)
+
+// publishers agrupa el bus en el que publica el outbox de cada dominio y el de los eventos de
+// seguridad (nil si no se publican).
+type publishers struct {
+	user     sharedBus.EventBus
+	task     sharedBus.EventBus
+	security sharedBus.EventBus
+}
+
+// ---------------- Kafka ----------------
+
+// kafkaModule aporta la configuración de Kafka y su health check. Los productores y los
+// consumidores se añaden aparte, según el subcomando.
+var kafkaModule = fx.Module("kafka",
+	fx.Provide(kafkaOptions),
+	fx.Invoke(registerKafkaHealth),
+)
+
+func kafkaOptions(cfg *config.Config) infraEvents.KafkaOptions {
+	k := cfg.Bus.Kafka
+	return infraEvents.KafkaOptions{
+		Brokers:               k.Brokers,
+		SASLMechanism:         k.SASL.Mechanism,
+		SASLUsername:          k.SASL.Username,
+		SASLPassword:          k.SASL.Password,
+		TLSEnabled:            k.TLS.Enabled,
+		TLSCAFile:             k.TLS.CAFile,
+		TLSCertFile:           k.TLS.CertFile,
+		TLSKeyFile:            k.TLS.KeyFile,
+		TLSInsecureSkipVerify: k.TLS.InsecureSkipVerify,
+		BatchSize:             k.Producer.BatchSize,
+		BatchTimeout:          k.Producer.Linger,
+		Compression:           k.Producer.Compression,
+		Async:                 k.Producer.Async,
+		RequiredAcks:          k.Producer.RequiredAcks,
+		Idempotent:            k.Producer.Idempotent,
+		TransactionalID:       k.Producer.TransactionalID,
+		MinBytes:              k.Consumer.MinBytes,
+		MaxBytes:              k.Consumer.MaxBytes,
+		StartOffset:           k.Consumer.StartOffset,
+		Tenants:               k.Tenants,
+	}
+}
+
+func registerKafkaHealth(opts infraEvents.KafkaOptions, registry *health.Registry) error {
+	kafkaCheck, err := infraEvents.NewKafkaHealthCheck(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka health check: %w", err)
+	}
+	registry.Register("kafka", kafkaCheck)
+	return nil
+}
+
+// newKafkaPublishers crea el productor de Kafka que comparten los outbox de todos los dominios:
+// cada evento va al topic de su tipo en el registro de eventos. Se cierra (vaciando sus lotes) al
+// parar. Los mensajes grandes se comprimen según outbox.compression, recargable en caliente, y los
+// borrados van seguidos de un tombstone si bus.kafka.producer.tombstones está activo. Con
+// bus.kafka.tenant_topics cada inquilino publica en su propio topic. Con
+// bus.kafka.producer.idempotent el productor es el idempotente de franz-go, transaccional si hay
+// transactional_id, y cada evento lleva su marca de procesado en marker_topic.
+func newKafkaPublishers(lc fx.Lifecycle, cfg *config.Config, runtime *config.Runtime, opts infraEvents.KafkaOptions, hooks *infraEvents.Hooks, log *zap.Logger) (publishers, error) {
+	log = log.Named("bus")
+	writer, err := newKafkaMessageWriter(opts)
+	if err != nil {
+		return publishers{}, fmt.Errorf("failed to create Kafka writer: %w", err)
+	}
+	lc.Append(fx.StopHook(writer.Close))
+
+	publisher := infraEvents.NewKafkaPublisher(writer, log)
+	publisher.SetEventRegistry(newEventRegistry())
+	publisher.SetTombstones(cfg.Bus.Kafka.Producer.Tombstones)
+	publisher.SetProcessedMarkers(cfg.Bus.Kafka.Producer.MarkerTopic)
+	publisher.SetTenantTopics(cfg.Bus.Kafka.TenantTopics)
+	applyCompression := func(c *config.Config) {
+		publisher.SetPayloadCompression(c.Outbox.PayloadCompression())
+	}
+	applyCompression(cfg)
+	runtime.OnReload(applyCompression)
+
+	// Las métricas de cada outbox se etiquetan con el topic de su dominio.
+	return publishers{
+		user:     hooked(hooks, validated(infraEvents.NewInstrumentedPublisher(publisher, "kafka", userDomain.UserTopic))),
+		task:     hooked(hooks, validated(infraEvents.NewInstrumentedPublisher(publisher, "kafka", taskDomain.TaskTopic))),
+		security: hooked(hooks, validated(infraEvents.NewInstrumentedPublisher(publisher, "kafka", audit.Topic))),
+	}, nil
+}
+
+// newKafkaMessageWriter crea el productor de Kafka: el idempotente de franz-go si
+// opts.Idempotent, o el writer de kafka-go.
+func newKafkaMessageWriter(opts infraEvents.KafkaOptions) (interface {
+	infraEvents.KafkaMessageWriter
+	Close() error
+}, error) {
+	if opts.Idempotent {
+		writer, err := infraEvents.NewIdempotentKafkaWriter(opts)
+		if err != nil {
+			return nil, err
+		}
+		return writer, nil
+	}
+	writer, err := infraEvents.NewKafkaWriter(opts, "")
+	if err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// validated envuelve un publicador para que rechace los eventos que no cumplen el esquema de su
+// tipo en el registro de eventos.
+func validated(pub sharedBus.EventBus) sharedBus.EventBus {
+	return infraEvents.NewValidatingPublisher(pub, sharedEvents.Schemas(newEventRegistry()))
+}
+
+// hooked envuelve un publicador con los hooks de publicación del bus. Van por fuera de la
+// validación, para que se valide el evento que los hooks dejan.
+func hooked(hooks *infraEvents.Hooks, pub sharedBus.EventBus) sharedBus.EventBus {
+	return infraEvents.NewHookedPublisher(pub, hooks)
+}
+
+// startKafkaConsumers consume, mientras la aplicación está arrancada, los topics de los dominios
+// habilitados en components.consumers. Cada servicio consume con su propio grupo para recibir
+// todos los eventos de su topic y descarta los que ya vio en bus.kafka.consumer.dedup_window.
+// Cada mensaje pasa por los hooks de consumo del bus.
+func startKafkaConsumers(lc fx.Lifecycle, opts infraEvents.KafkaOptions, cfg *config.Config, userService *userApp.UserService, taskService *taskApp.TaskService, counters *taskApp.TaskCounterService, hooks *infraEvents.Hooks, clock sharedDomain.Clock, log *zap.Logger) error {
+	log = log.Named("consumer")
+	type kafkaConsumer struct {
+		topic, group string
+		handler      infraEvents.MessageHandler
+	}
+	var consumers []kafkaConsumer
+	if cfg.Components.Consumes("user") {
+		consumers = append(consumers, kafkaConsumer{userDomain.UserTopic, cfg.Bus.Kafka.Consumer.GroupUser, userEvents.NewUserConsumer(userService, log)})
+	}
+	if cfg.Components.Consumes("task") {
+		taskConsumer := taskEvents.NewTaskConsumer(taskService, log)
+		taskConsumer.SetCounters(counters)
+		consumers = append(consumers, kafkaConsumer{taskDomain.TaskTopic, cfg.Bus.Kafka.Consumer.GroupTask, taskConsumer})
+	}
+
+	var readers []*kafka.Reader
+	closeReaders := func() {
+		for _, reader := range readers {
+			reader.Close()
+		}
+	}
+	var adapters []*infraEvents.ConsumerAdapter
+	for _, c := range consumers {
+		reader, err := infraEvents.NewKafkaReader(opts, c.topic, c.group)
+		if err != nil {
+			closeReaders()
+			return fmt.Errorf("failed to create Kafka reader for %s: %w", c.topic, err)
+		}
+		readers = append(readers, reader)
+
+		handler := infraEvents.InstrumentHandler(infraEvents.HookHandler(c.handler, hooks, log), "kafka", c.topic)
+		adapter := infraEvents.NewConsumerAdapter(reader, handler, log)
+		if consumerCfg := cfg.Bus.Kafka.Consumer; consumerCfg.DedupSize > 0 {
+			adapter.SetDeduplicator(infraEvents.NewDeduplicator(consumerCfg.DedupSize, consumerCfg.DedupWindow, clock))
+		}
+		if err := adapter.RegisterLagMetric(); err != nil {
+			log.Warn("⚠️ No se pudo registrar la métrica de lag del consumidor", zap.Error(err))
+		}
+		adapters = append(adapters, adapter)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			for _, adapter := range adapters {
+				adapter.Start(ctx)
+			}
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			closeReaders()
+			return nil
+		},
+	})
+	return nil
+}
+
+// ---------------- Bus en memoria ----------------
+
+// inMemoryBuses son los buses en memoria de cada topic; publicadores y consumidores deben
+// compartir la misma instancia.
+type inMemoryBuses struct {
+	user *infraEvents.InMemoryEventBus
+	task *infraEvents.InMemoryEventBus
+}
+
+// inMemoryBusModule sustituye a Kafka cuando está deshabilitado: publica y consume en este
+// mismo proceso, así que solo sirve para el subcomando all.
+var inMemoryBusModule = fx.Module("memory-bus",
+	fx.Provide(
+		func() inMemoryBuses {
+			return inMemoryBuses{
+				user: infraEvents.NewInMemoryEventBus(userDomain.UserTopic),
+				task: infraEvents.NewInMemoryEventBus(taskDomain.TaskTopic),
+			}
+		},
+		func(buses inMemoryBuses, hooks *infraEvents.Hooks) publishers {
+			return publishers{
+				user: hooked(hooks, validated(infraEvents.NewInstrumentedPublisher(buses.user, "memory", userDomain.UserTopic))),
+				task: hooked(hooks, validated(infraEvents.NewInstrumentedPublisher(buses.task, "memory", taskDomain.TaskTopic))),
+			}
+		},
+	),
+	fx.Invoke(startInMemoryConsumers),
+)
+
+// startInMemoryConsumers consume los buses en memoria de los dominios habilitados en
+// components.consumers. Los buses sin suscriptores descartan lo que se publica en ellos.
+func startInMemoryConsumers(lc fx.Lifecycle, cfg *config.Config, buses inMemoryBuses, pubs publishers, userService *userApp.UserService, taskService *taskApp.TaskService, counters *taskApp.TaskCounterService, hooks *infraEvents.Hooks, log *zap.Logger) {
+	log = log.Named("consumer")
+	for topic, bus := range map[string]*infraEvents.InMemoryEventBus{userDomain.UserTopic: buses.user, taskDomain.TaskTopic: buses.task} {
+		if err := metrics.RegisterQueueDepth("memory", topic, bus.QueueDepth); err != nil {
+			log.Warn("⚠️ No se pudo registrar la métrica de cola del bus", zap.String("topic", topic), zap.Error(err))
+		}
+	}
+
+	consumeUsers, consumeTasks := cfg.Components.Consumes("user"), cfg.Components.Consumes("task")
+	var userEventsChannel, taskEventsChannel <-chan interface{}
+	if consumeUsers {
+		userEventsChannel = buses.user.Subscribe(10)
+	}
+	if consumeTasks {
+		taskEventsChannel = buses.task.Subscribe(10)
+	}
+
+	userConsumer := userEvents.NewUserConsumer(userService, log)
+	taskConsumer := taskEvents.NewTaskConsumer(taskService, log)
+	taskConsumer.SetCounters(counters)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			if consumeUsers {
+				log.Info("🎧 Iniciando listener en memoria para eventos de usuario")
+				userEvents.BackgroundConsumerChan(ctx, userEventsChannel, userConsumer, hooks)
+			}
+			if consumeTasks {
+				log.Info("🎧 Iniciando listener en memoria para eventos de tarea")
+				taskEvents.BackgroundConsumerChan(ctx, taskEventsChannel, taskConsumer, hooks)
+			}
+
+			publishSimulatedUser(pubs.user, log) // Simulamos la publicación de un evento de usuario
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// publishSimulatedUser publica un evento de usuario de ejemplo en el bus en memoria.
+func publishSimulatedUser(pub sharedBus.EventBus, log *zap.Logger) {
+	userCreatedEvent := sharedEvents.UserCreated{
+		ID:        uuid.New(),
+		Email:     "simulated.user@example.com",
+		Nombre:    "Usuario Simulado",
+		BirthDate: time.Now().AddDate(-30, 0, 0),
+	}
+
+	payloadBytes, _ := json.Marshal(userCreatedEvent)
+	now := time.Now().UTC()
+	simulatedEvent := sharedEvents.IntegrationEvent{
+		EventID:    uuid.New(),
+		Type:       userDomain.UserCreated,
+		Timestamp:  now,
+		OccurredAt: now,
+		Actor:      "simulator",
+		Data:       payloadBytes,
+	}.WithPartitionKey(userCreatedEvent.ID.String())
+	if err := pub.Publish(context.Background(), simulatedEvent); err != nil {
+		log.Error("Fallo al publicar el evento simulado", zap.Error(err))
+	} else {
+		log.Info("✅ Evento 'UserCreated' simulado y publicado correctamente")
+	}
+}
+
+// ---------------- Outbox relayer ----------------
+
+// newEventRegistry une los registros de eventos de todos los dominios y los de seguridad.
+func newEventRegistry() map[string]sharedEvents.EventMetadata {
+	eventRegistry := make(map[string]sharedEvents.EventMetadata)
+	for k, v := range userDomain.NewEventRegistry() {
+		eventRegistry[k] = v
+	}
+	for k, v := range taskDomain.NewEventRegistry() {
+		eventRegistry[k] = v
+	}
+	for k, v := range audit.NewEventRegistry() {
+		eventRegistry[k] = v
+	}
+	return eventRegistry
+}
+
+// publishSecurityEvents publica los eventos de seguridad en Kafka. Con el bus en memoria, que no
+// tiene consumidores de seguridad, solo quedan en el log.
+func publishSecurityEvents(auditor *audit.Auditor, pubs publishers) {
+	if pubs.security != nil {
+		auditor.SetPublisher(pubs.security)
+	}
+}
+
+// startRelayer arranca un worker de outbox por dominio, cada uno sobre el outbox de su propio
+// almacenamiento, y expone su backlog en /metrics. Periodo y lote se recargan en caliente.
+// El servicio no se da por listo hasta que cada worker ha leído su outbox una vez. Con
+// encryption.keys, los workers descifran los datos personales de los payloads antes de publicarlos.
+func startRelayer(lc fx.Lifecycle, cfg *config.Config, runtime *config.Runtime, startup *health.Startup, userStores userStore.Stores, taskStores taskStore.Stores, pubs publishers, keys *encryption.Keyring, clock sharedDomain.Clock, log *zap.Logger) {
+	log = log.Named("relayer")
+	eventRegistry := newEventRegistry()
+
+	workers := map[string]*infraRelayer.Worker{
+		"user": infraRelayer.NewOutboxWorker(userStores.Outbox, pubs.user, eventRegistry, cfg.Outbox.Period, cfg.Outbox.Limit, clock, log),
+		"task": infraRelayer.NewOutboxWorker(taskStores.Outbox, pubs.task, eventRegistry, cfg.Outbox.Period, cfg.Outbox.Limit, clock, log),
+	}
+	if keys != nil {
+		for _, w := range workers {
+			w.SetDecryption(keys)
+		}
+	}
+	polled := make(map[string]func(), len(workers))
+	for name := range workers {
+		polled[name] = startup.Add("first poll of the " + name + " outbox")
+	}
+
+	// El backlog de cada outbox se consulta en cada scrape de /metrics.
+	for name, outbox := range map[string]sharedDomain.OutboxRepository{"user": userStores.Outbox, "task": taskStores.Outbox} {
+		counter, ok := outbox.(sharedDomain.OutboxBacklogCounter)
+		if !ok {
+			continue
+		}
+		if err := metrics.RegisterOutboxBacklog(name, counter.CountPendingOutbox); err != nil {
+			log.Warn("⚠️ No se pudo registrar la métrica de backlog del outbox", zap.String("outbox", name), zap.Error(err))
+		}
+	}
+
+	runtime.OnReload(func(c *config.Config) {
+		for _, w := range workers {
+			w.SetInterval(c.Outbox.Period)
+			w.SetBatchSize(c.Outbox.Limit)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			for name, w := range workers {
+				go w.Start(ctx)
+				go func(w *infraRelayer.Worker, done func()) {
+					select {
+					case <-w.Polled():
+						done()
+					case <-ctx.Done():
+					}
+				}(w, polled[name])
+			}
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// newOutboxPartitioner devuelve el mantenimiento de las particiones del outbox de Postgres, o nil
+// si outbox.partitioning está desactivado. Recibe los almacenamientos para que la tabla outbox ya
+// exista.
+func newOutboxPartitioner(cfg *config.Config, conns *platformDB.Connections, _ userStore.Stores, _ taskStore.Stores) (*sharedPostgres.OutboxPartitioner, error) {
+	p := cfg.Outbox.Partitioning
+	if !p.Enabled {
+		return nil, nil
+	}
+	db, err := conns.Postgres(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Postgres for outbox partitioning: %w", err)
+	}
+	return sharedPostgres.NewOutboxPartitioner(db, sharedPostgres.PartitionOptions{Ahead: p.Ahead, Retention: p.Retention}), nil
+}
+
+// maintainOutboxPartitions hace una pasada de mantenimiento de las particiones del outbox y deja
+// en el log lo que ha cambiado.
+func maintainOutboxPartitions(ctx context.Context, partitioner *sharedPostgres.OutboxPartitioner, clock sharedDomain.Clock, log *zap.Logger) error {
+	report, err := partitioner.Maintain(ctx, clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to maintain outbox partitions: %w", err)
+	}
+	if report.Converted || len(report.Created) > 0 || len(report.Archived) > 0 {
+		log.Info("🗂️ Particiones del outbox actualizadas",
+			zap.Bool("converted", report.Converted),
+			zap.Strings("created", report.Created),
+			zap.Strings("archived", report.Archived),
+		)
+	}
+	return nil
+}
+
+// startOutboxPartitioning mantiene las particiones del outbox junto al relayer: una pasada al
+// arrancar, que impide el arranque si falla (sin particiones no se puede escribir en el outbox),
+// y otra cada outbox.partitioning.period, cuyos fallos solo quedan en el log.
+func startOutboxPartitioning(lc fx.Lifecycle, cfg *config.Config, partitioner *sharedPostgres.OutboxPartitioner, clock sharedDomain.Clock, log *zap.Logger) {
+	if partitioner == nil {
+		return
+	}
+	log = log.Named("relayer")
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(startCtx context.Context) error {
+			if err := maintainOutboxPartitions(startCtx, partitioner, clock, log); err != nil {
+				return err
+			}
+			go func() {
+				ticker := clock.NewTicker(cfg.Outbox.Partitioning.Period)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C():
+						if err := maintainOutboxPartitions(ctx, partitioner, clock, log); err != nil {
+							log.Error("❌ Fallo al mantener las particiones del outbox", zap.Error(err))
+						}
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	config "github.com/davicafu/hexagolab/internal/config"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedPostgres "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/postgres"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+)
+
+// command es un subcomando del binario. Todos parten de coreModule y añaden solo los módulos que
+// necesitan, para poder desplegar y escalar cada pieza por separado.
+type command struct {
+	name    string
+	summary string
+	// oneShot indica que el comando hace su trabajo al arrancar y termina, sin esperar a una señal.
+	oneShot bool
+	// flags son los flags propios del comando (no de configuración), si los tiene.
+	flags   *flag.FlagSet
+	options func(cfg *config.Config) (fx.Option, error)
+}
+
+var commands = []command{
+	{name: "all", summary: "The components enabled in the configuration in one process (default)", options: allOptions},
+	{name: "serve", summary: "Enabled APIs only; events stay in the outbox until a relayer publishes them", options: serveOptions},
+	{name: "relayer", summary: "Outbox relayer publishing pending events to Kafka", options: relayerOptions},
+	{name: "consumer", summary: "Enabled Kafka event consumers", options: consumerOptions},
+	{name: "migrate", summary: "Create or upgrade the database schemas and exit", oneShot: true, options: migrateOptions},
+	{name: "seed", summary: "Insert deterministic demo users and tasks and exit", oneShot: true, flags: seedFlagSet(&seedArgs), options: seedOptions},
+	{name: "replay", summary: "Rebuild the per-user task counters by replaying the task outbox and exit", oneShot: true, flags: replayFlagSet(&replayArgs), options: replayOptions},
+}
+
+// errKafkaRequired se devuelve desde los subcomandos que solo tienen sentido con un bus externo:
+// el bus en memoria vive dentro del proceso y no se puede repartir entre procesos.
+var errKafkaRequired = errors.New("this command needs Kafka (bus.kafka.enabled); with the in-memory bus run 'hexagolab all'")
+
+// allOptions arranca en un mismo proceso los componentes habilitados en components. Es el único
+// modo posible con el bus en memoria.
+func allOptions(cfg *config.Config) (fx.Option, error) {
+	c := cfg.Components
+	options := []fx.Option{coreModule, servicesModule, demoOptions(cfg), httpModule, apiOptions(c)}
+	if c.Relayer || len(c.Consumers) > 0 {
+		if cfg.Bus.Kafka.Enabled {
+			// Los productores solo se crean si el relayer los pide.
+			options = append(options, kafkaModule, fx.Provide(newKafkaPublishers))
+			if len(c.Consumers) > 0 {
+				options = append(options, fx.Invoke(startKafkaConsumers))
+			}
+		} else {
+			options = append(options, inMemoryBusModule)
+		}
+	}
+	if c.Relayer {
+		options = append(options, fx.Invoke(startRelayer, startOutboxPartitioning, publishSecurityEvents))
+	}
+	return fx.Options(options...), nil
+}
+
+// serveOptions arranca solo las APIs habilitadas en components.
+func serveOptions(cfg *config.Config) (fx.Option, error) {
+	if !cfg.Components.HTTP && !cfg.Components.GRPC {
+		return nil, errors.New("serve needs components.http or components.grpc enabled")
+	}
+	return fx.Options(coreModule, servicesModule, demoOptions(cfg), httpModule, apiOptions(cfg.Components)), nil
+}
+
+// apiOptions añade las APIs habilitadas y, si hay alguna, la precarga de la caché que leen; los
+// endpoints de operación los sirve httpModule siempre.
+func apiOptions(c config.ComponentsConfig) fx.Option {
+	var options []fx.Option
+	if c.HTTP {
+		options = append(options, apiModule)
+	}
+	if c.GRPC {
+		options = append(options, grpcModule)
+	}
+	if c.HTTP || c.GRPC {
+		options = append(options, fx.Invoke(startCacheWarmup))
+	}
+	return fx.Options(options...)
+}
+
+// relayerOptions publica el outbox en Kafka. Solo expone los endpoints de operación.
+func relayerOptions(cfg *config.Config) (fx.Option, error) {
+	if !cfg.Bus.Kafka.Enabled {
+		return nil, errKafkaRequired
+	}
+	return fx.Options(coreModule, kafkaModule, fx.Provide(newKafkaPublishers), fx.Invoke(startRelayer, startOutboxPartitioning, publishSecurityEvents), httpModule), nil
+}
+
+// consumerOptions consume los topics de Kafka de los dominios habilitados en components.consumers.
+// Solo expone los endpoints de operación.
+func consumerOptions(cfg *config.Config) (fx.Option, error) {
+	if !cfg.Bus.Kafka.Enabled {
+		return nil, errKafkaRequired
+	}
+	if len(cfg.Components.Consumers) == 0 {
+		return nil, errors.New("consumer needs at least one domain in components.consumers")
+	}
+	return fx.Options(coreModule, servicesModule, kafkaModule, fx.Invoke(startKafkaConsumers), httpModule), nil
+}
+
+// migrateOptions no necesita más que construir los almacenamientos, que inicializan sus esquemas,
+// y, con outbox.partitioning, particionar el outbox. El resto de subcomandos también lo hace al
+// arrancar; migrate permite ejecutarlo como paso previo al despliegue.
+func migrateOptions(cfg *config.Config) (fx.Option, error) {
+	return fx.Options(coreModule, fx.Invoke(func(_ userStore.Stores, _ taskStore.Stores, partitioner *sharedPostgres.OutboxPartitioner, clock sharedDomain.Clock, log *zap.Logger) error {
+		if partitioner != nil {
+			if err := maintainOutboxPartitions(context.Background(), partitioner, clock, log); err != nil {
+				return err
+			}
+		}
+		log.Info("✅ Esquemas actualizados",
+			zap.String("user_backend", cfg.DB.User),
+			zap.String("task_backend", cfg.DB.Task),
+		)
+		return nil
+	})), nil
+}
+
+func seedOptions(cfg *config.Config) (fx.Option, error) {
+	return fx.Options(coreModule, servicesModule, seedOnStart(seedArgs)), nil
+}
+
+// demoOptions siembra los datos de demostración al arrancar en modo demo: el almacenamiento en
+// memoria empieza vacío en cada arranque.
+func demoOptions(cfg *config.Config) fx.Option {
+	if !cfg.App.Demo {
+		return fx.Options()
+	}
+	return seedOnStart(defaultSeedParams)
+}
+
+// seedOnStart siembra los datos a través de los servicios al arrancar la aplicación.
+func seedOnStart(p seedParams) fx.Option {
+	return fx.Invoke(func(lc fx.Lifecycle, userService *userApp.UserService, taskService *taskApp.TaskService, log *zap.Logger) {
+		lc.Append(fx.StartHook(func(ctx context.Context) error {
+			return seedDemoData(ctx, p, userService, taskService, log)
+		}))
+	})
+}
+
+// splitFlags aplica los flags propios del comando que aparezcan en args (--name valor o
+// --name=valor) y devuelve el resto, que son los de configuración.
+func splitFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || fs.Lookup(name) == nil {
+			rest = append(rest, args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("flag needs an argument: --%s", name)
+			}
+			i++
+			value = args[i]
+		}
+		if err := fs.Set(name, value); err != nil {
+			return nil, fmt.Errorf("invalid value %q for flag --%s: %w", value, name, err)
+		}
+	}
+	return rest, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	config "github.com/davicafu/hexagolab/internal/config"
+	"github.com/davicafu/hexagolab/pkg/logger"
+)
+
+// TestCommands_DependencyGraph comprueba sin arrancar nada que el cableado de cada subcomando
+// está completo: cada dependencia tiene un proveedor y no hay ciclos.
+func TestCommands_DependencyGraph(t *testing.T) {
+	for _, kafka := range []bool{false, true} {
+		cfg := config.Default()
+		cfg.Bus.Kafka.Enabled = kafka
+
+		for _, cmd := range commands {
+			options, err := cmd.options(cfg)
+			if !kafka && (cmd.name == "relayer" || cmd.name == "consumer") {
+				assert.ErrorIs(t, err, errKafkaRequired, cmd.name)
+				continue
+			}
+			require.NoError(t, err, cmd.name)
+
+			err = fx.ValidateApp(fx.Supply(cfg, config.NewRuntime(cfg, nil), zap.NewNop(), logger.NewLevels()), fx.NopLogger, options)
+			assert.NoError(t, err, "%s (kafka=%t)", cmd.name, kafka)
+		}
+	}
+}
+
+// TestCommands_ComponentsDependencyGraph valida el grafo de all, serve y consumer con cada
+// componente deshabilitado por separado.
+func TestCommands_ComponentsDependencyGraph(t *testing.T) {
+	shapes := map[string]func(c *config.ComponentsConfig){
+		"no http":        func(c *config.ComponentsConfig) { c.HTTP = false },
+		"grpc":           func(c *config.ComponentsConfig) { c.GRPC = true },
+		"no relayer":     func(c *config.ComponentsConfig) { c.Relayer = false },
+		"no consumers":   func(c *config.ComponentsConfig) { c.Consumers = nil },
+		"only user":      func(c *config.ComponentsConfig) { c.Consumers = []string{"user"} },
+		"only relayer":   func(c *config.ComponentsConfig) { *c = config.ComponentsConfig{Relayer: true} },
+		"only grpc":      func(c *config.ComponentsConfig) { *c = config.ComponentsConfig{GRPC: true} },
+		"only consumers": func(c *config.ComponentsConfig) { *c = config.ComponentsConfig{Consumers: []string{"user", "task"}} },
+	}
+	for name, shape := range shapes {
+		for _, kafka := range []bool{false, true} {
+			cfg := config.Default()
+			cfg.Bus.Kafka.Enabled = kafka
+			shape(&cfg.Components)
+
+			for _, options := range []func(*config.Config) (fx.Option, error){allOptions, serveOptions, consumerOptions} {
+				opts, err := options(cfg)
+				if err != nil {
+					continue // Combinaciones sin nada que arrancar, o consumer sin Kafka
+				}
+				err = fx.ValidateApp(fx.Supply(cfg, config.NewRuntime(cfg, nil), zap.NewNop(), logger.NewLevels()), fx.NopLogger, opts)
+				assert.NoError(t, err, "%s (kafka=%t)", name, kafka)
+			}
+		}
+	}
+}
+
+// TestCommands_DemoDependencyGraph valida all y serve con la siembra del modo demo.
+func TestCommands_DemoDependencyGraph(t *testing.T) {
+	cfg, err := config.Load([]string{"--demo"})
+	require.NoError(t, err)
+
+	for _, options := range []func(*config.Config) (fx.Option, error){allOptions, serveOptions} {
+		opts, err := options(cfg)
+		require.NoError(t, err)
+		err = fx.ValidateApp(fx.Supply(cfg, config.NewRuntime(cfg, nil), zap.NewNop(), logger.NewLevels()), fx.NopLogger, opts)
+		assert.NoError(t, err)
+	}
+}
+
+func TestServeOptions_NeedsAnAPI(t *testing.T) {
+	cfg := config.Default()
+	cfg.Components.HTTP = false
+	cfg.Components.GRPC = false
+
+	_, err := serveOptions(cfg)
+
+	assert.ErrorContains(t, err, "components.http or components.grpc")
+}
+
+func TestParseCommand(t *testing.T) {
+	cmd, args, ok := parseCommand(nil)
+	assert.True(t, ok)
+	assert.Equal(t, "all", cmd.name, "Sin subcomando se arranca todo")
+	assert.Empty(t, args)
+
+	cmd, args, ok = parseCommand([]string{"--port", "9000"})
+	assert.True(t, ok)
+	assert.Equal(t, "all", cmd.name)
+	assert.Equal(t, []string{"--port", "9000"}, args)
+
+	cmd, args, ok = parseCommand([]string{"relayer", "--env", "prod"})
+	assert.True(t, ok)
+	assert.Equal(t, "relayer", cmd.name)
+	assert.Equal(t, []string{"--env", "prod"}, args)
+
+	_, _, ok = parseCommand([]string{"deploy"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	config "github.com/davicafu/hexagolab/internal/config"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/background"
+	sharedCache "github.com/davicafu/hexagolab/internal/shared/infra/platform/cache"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/querylog"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/health"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/quota"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+	"github.com/davicafu/hexagolab/pkg/logger"
+)
+
+// coreModule es la base de todos los subcomandos: registro de health checks y pasos de arranque,
+// recarga de la configuración, el registro de hooks del bus (donde cada funcionalidad añade sus
+// plugins con un fx.Invoke) y el almacenamiento de cada dominio (con sus esquemas ya inicializados).
+// Espera que se hayan suministrado *config.Config, *config.Runtime y *zap.Logger.
+var coreModule = fx.Module("core",
+	fx.Provide(
+		newClock,
+		newIDGenerator,
+		newHealth,
+		newQueryLog,
+		newConnections,
+		newKeyring,
+		infraEvents.NewHooks,
+		newUserStores,
+		newTaskStores,
+		newOutboxPartitioner,
+	),
+	fx.Invoke(watchReloads),
+)
+
+// servicesModule añade la caché y los servicios de aplicación.
+var servicesModule = fx.Module("services",
+	fx.Provide(
+		newRedis,
+		newCache,
+		newCachePool,
+		newQuotas,
+		func(s userStore.Stores) userDomain.UserRepository { return s.Users },
+		func(s taskStore.Stores) taskDomain.TaskRepository { return s.Tasks },
+		func(s taskStore.Stores) taskDomain.TaskCounterRepository { return s.Counters },
+		userApp.NewUserService,
+		taskApp.NewTaskService,
+		taskApp.NewTaskCounterService,
+	),
+	fx.Invoke(configureOutboxPayloads, configureQuotas),
+)
+
+// shapedEvents son los tipos de evento cuyo payload admite otra forma que la entidad completa
+// (outbox.payloads); los borrados llevan siempre solo el id.
+var shapedEvents = []string{userDomain.UserCreated, userDomain.UserUpdated, taskDomain.TaskCreated, taskDomain.TaskUpdated}
+
+// configureOutboxPayloads aplica a los servicios la forma y la compresión de los payloads del
+// outbox, recargables en caliente.
+func configureOutboxPayloads(cfg *config.Config, runtime *config.Runtime, userService *userApp.UserService, taskService *taskApp.TaskService, log *zap.Logger) {
+	apply := func(c *config.Config) {
+		modes, _ := c.Outbox.PayloadModes() // Ya validado al cargar la configuración
+		for eventType := range modes {
+			if !slices.Contains(shapedEvents, eventType) {
+				log.Warn("⚠️ outbox.payloads: el tipo de evento no admite otra forma, se ignora", zap.String("type", eventType))
+			}
+		}
+		userService.SetPayloadModes(modes)
+		taskService.SetPayloadModes(modes)
+		userService.SetPayloadCompression(c.Outbox.PayloadCompression())
+		taskService.SetPayloadCompression(c.Outbox.PayloadCompression())
+	}
+	apply(cfg)
+	runtime.OnReload(apply)
+}
+
+// configureQuotas aplica a los servicios las cuotas por usuario de las altas.
+func configureQuotas(quotas *quota.Quotas, userService *userApp.UserService, taskService *taskApp.TaskService) {
+	userService.SetQuotas(quotas)
+	taskService.SetQuotas(quotas)
+}
+
+// newClock da la hora real a servicios, workers y caché; los tests la sustituyen por un reloj falso.
+func newClock() sharedDomain.Clock {
+	return sharedDomain.SystemClock{}
+}
+
+// newIDGenerator da los identificadores aleatorios de usuarios, tareas y eventos del outbox.
+func newIDGenerator() sharedDomain.IDGenerator {
+	return sharedDomain.RandomIDGenerator{}
+}
+
+// newHealth crea el registro de health checks y los pasos de arranque pendientes, que también
+// cuentan para /readyz.
+func newHealth() (*health.Registry, *health.Startup) {
+	registry := health.NewRegistry(0)
+	startup := health.NewStartup()
+	registry.Register("startup", startup.Check)
+	return registry, startup
+}
+
+// newQueryLog crea el log de consultas lentas; su umbral se puede recargar en caliente.
+func newQueryLog(cfg *config.Config, runtime *config.Runtime, log *zap.Logger) *querylog.Logger {
+	log = log.Named("db")
+	slowQueries := querylog.NewLogger(log, cfg.DB.SlowQueryThreshold)
+	runtime.OnReload(func(c *config.Config) { slowQueries.SetThreshold(c.DB.SlowQueryThreshold) })
+	return slowQueries
+}
+
+// newConnections comparte las conexiones entre dominios: si coinciden en backend, usan el mismo pool.
+// Con encryption.keys, el fichero del backend file se guarda cifrado.
+func newConnections(lc fx.Lifecycle, cfg *config.Config, slowQueries *querylog.Logger, keys *encryption.Keyring, registry *health.Registry, log *zap.Logger) *platformDB.Connections {
+	log = log.Named("db")
+	conns := platformDB.NewConnections(platformDB.Options{
+		SQLitePath:    cfg.DB.SQLite.Path,
+		PostgresDSN:   cfg.DB.Postgres.DSN,
+		MongoURI:      cfg.DB.Mongo.URI,
+		MongoDatabase: cfg.DB.Mongo.Database,
+		FilePath:      cfg.DB.File.Path,
+		FileKeys:      keys,
+		SQLitePool:    platformDB.SQLPool{MaxOpenConns: cfg.DB.SQLite.MaxOpenConns},
+		PostgresPool: platformDB.SQLPool{
+			MaxOpenConns:    cfg.DB.Postgres.MaxOpenConns,
+			MaxIdleConns:    cfg.DB.Postgres.MaxIdleConns,
+			ConnMaxLifetime: cfg.DB.Postgres.ConnMaxLifetime,
+			ConnMaxIdleTime: cfg.DB.Postgres.ConnMaxIdleTime,
+		},
+		MongoMaxPoolSize: uint64(cfg.DB.Mongo.MaxPoolSize),
+		MongoMinPoolSize: uint64(cfg.DB.Mongo.MinPoolSize),
+		QueryLog:         slowQueries,
+	})
+	lc.Append(fx.Hook{
+		// Al arrancar ya se han construido todos los adaptadores, así que están abiertos
+		// exactamente los backends que se usan.
+		OnStart: func(context.Context) error {
+			conns.RegisterHealthChecks(registry)
+			if err := conns.RegisterPoolMetrics(); err != nil {
+				log.Warn("⚠️ No se pudieron registrar las métricas de los pools de conexiones", zap.Error(err))
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			conns.Close(ctx)
+			return nil
+		},
+	})
+	return conns
+}
+
+// newKeyring devuelve el anillo de claves de encryption.keys, o nil si el cifrado está desactivado.
+func newKeyring(cfg *config.Config) (*encryption.Keyring, error) {
+	keys, err := cfg.Encryption.Keyring()
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption keys: %w", err)
+	}
+	return keys, nil
+}
+
+// newUserStores crea los adaptadores de usuarios; con encryption.keys, el repositorio cifra los
+// datos personales y, antes, se cifran las fechas de nacimiento guardadas sin cifrar.
+func newUserStores(cfg *config.Config, conns *platformDB.Connections, keys *encryption.Keyring, log *zap.Logger) (userStore.Stores, error) {
+	stores, err := userStore.NewStores(context.Background(), cfg.DB.User, conns)
+	if err != nil {
+		return userStore.Stores{}, fmt.Errorf("failed to initialize user storage (%s): %w", cfg.DB.User, err)
+	}
+	if keys != nil {
+		sealed, err := userStore.EncryptBirthDates(context.Background(), cfg.DB.User, conns, keys)
+		if err != nil {
+			return userStore.Stores{}, fmt.Errorf("failed to encrypt stored birth dates: %w", err)
+		}
+		if sealed > 0 {
+			log.Info("🔐 Fechas de nacimiento cifradas", zap.Int("users", sealed))
+		}
+		stores.Users = userStore.NewEncryptedUserRepository(stores.Users, keys)
+	}
+	log.Info("🗄️ Almacenamiento de usuarios", zap.String("backend", cfg.DB.User), zap.Bool("encrypted", keys != nil))
+	return stores, nil
+}
+
+func newTaskStores(cfg *config.Config, conns *platformDB.Connections, log *zap.Logger) (taskStore.Stores, error) {
+	stores, err := taskStore.NewStores(context.Background(), cfg.DB.Task, conns)
+	if err != nil {
+		return taskStore.Stores{}, fmt.Errorf("failed to initialize task storage (%s): %w", cfg.DB.Task, err)
+	}
+	log.Info("🗄️ Almacenamiento de tareas", zap.String("backend", cfg.DB.Task))
+	return stores, nil
+}
+
+// newRedis conecta con Redis, que comparten la caché y las cuotas. Devuelve nil si no hay
+// dirección (p.ej. en modo demo, donde ni siquiera se intenta conectar) o Redis no responde.
+func newRedis(lc fx.Lifecycle, cfg *config.Config, registry *health.Registry, log *zap.Logger) *redis.Client {
+	log = log.Named("cache")
+	if cfg.Cache.Redis.Addr == "" {
+		return nil
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: cfg.Cache.Redis.Addr, PoolSize: cfg.Cache.Redis.PoolSize, MinIdleConns: cfg.Cache.Redis.MinIdleConns})
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		log.Warn("⚠️ Redis no disponible, cache en memoria:", zap.Error(err))
+		rdb.Close()
+		return nil
+	}
+	registry.Register("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() })
+	if err := metrics.RegisterPoolStats("redis", func() metrics.PoolStats { return redisPoolStats(rdb) }); err != nil {
+		log.Warn("⚠️ No se pudo registrar la métrica del pool de Redis", zap.Error(err))
+	}
+	lc.Append(fx.StopHook(rdb.Close))
+	return rdb
+}
+
+// newCache usa Redis si responde al arrancar y, si no, una caché en memoria. Si Redis deja de
+// responder en marcha, la caché pasa a memoria hasta que vuelve (ver sharedCache.FailoverCache).
+// El TTL por defecto se recarga en caliente.
+func newCache(lc fx.Lifecycle, cfg *config.Config, runtime *config.Runtime, rdb *redis.Client, clock sharedDomain.Clock, log *zap.Logger) sharedCache.Cache {
+	log = log.Named("cache")
+	memory := func() sharedCache.Cache {
+		mem := sharedCache.NewInMemoryCache(cfg.Cache.TTL, 3*cfg.Cache.TTL, clock)
+		lc.Append(fx.StopHook(mem.Stop))
+		return sharedCache.NewInstrumentedCache(mem, "memory")
+	}
+
+	var cache sharedCache.Cache
+	if rdb == nil {
+		cache = memory()
+	} else {
+		failover := sharedCache.NewFailoverCache(
+			sharedCache.NewInstrumentedCache(sharedCache.NewRedisCache(rdb, cfg.Cache.TTL), "redis"),
+			memory(),
+			func(ctx context.Context) error { return rdb.Ping(ctx).Err() },
+			sharedCache.FailoverOptions{Threshold: cfg.Cache.Failover.Threshold, ProbeInterval: cfg.Cache.Failover.ProbeInterval},
+			clock, log,
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				go failover.Start(ctx)
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+		cache = failover
+		log.Info("✅ Redis conectado, cache habilitado")
+	}
+
+	if ttlCache, ok := cache.(sharedCache.DefaultTTLSetter); ok {
+		runtime.OnReload(func(c *config.Config) { ttlCache.SetDefaultTTL(c.Cache.TTL) })
+	}
+	return cache
+}
+
+// newQuotas crea las cuotas configuradas, contadas en Redis si está disponible. Sin cuotas
+// devuelve nil, que no limita nada.
+func newQuotas(cfg *config.Config, rdb *redis.Client, clock sharedDomain.Clock, log *zap.Logger) *quota.Quotas {
+	limits, _ := cfg.Quotas.Parsed() // Ya validado al cargar la configuración
+	if len(limits) == 0 {
+		return nil
+	}
+	var counter quota.Counter = quota.NewMemoryCounter(clock)
+	if rdb != nil {
+		counter = quota.NewRedisCounter(rdb)
+	} else {
+		log.Warn("⚠️ Cuotas contadas en memoria: cada instancia lleva las suyas")
+	}
+	return quota.New(counter, limits, clock, log)
+}
+
+// newCachePool crea el pool de las escrituras en caché en segundo plano; al parar ejecuta las
+// que queden encoladas.
+func newCachePool(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) *background.Pool {
+	log = log.Named("cache")
+	pool := background.NewPool("cache", background.Options{
+		Workers:   cfg.Cache.Async.Workers,
+		QueueSize: cfg.Cache.Async.QueueSize,
+		Policy:    background.Policy(cfg.Cache.Async.Policy),
+	}, log)
+	if err := pool.RegisterMetrics(); err != nil {
+		log.Warn("⚠️ No se pudo registrar la métrica del pool de caché", zap.Error(err))
+	}
+	lc.Append(fx.StopHook(pool.Close))
+	return pool
+}
+
+// startCacheWarmup precarga la caché según cache.warmup al arrancar, después de la siembra del
+// modo demo. Se hace en segundo plano, pero el servicio no se da por listo hasta que termina o
+// se agota cache.warmup.timeout; sus fallos solo quedan en el log.
+func startCacheWarmup(lc fx.Lifecycle, cfg *config.Config, startup *health.Startup, userService *userApp.UserService, taskService *taskApp.TaskService, log *zap.Logger) {
+	warmup := cfg.Cache.Warmup
+	if !warmup.Enabled() {
+		return
+	}
+	log = log.Named("cache")
+	done := startup.Add("cache warm-up")
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer done()
+				warmCtx, cancelWarm := context.WithTimeout(ctx, warmup.Timeout)
+				defer cancelWarm()
+				warmCache(warmCtx, warmup, userService, taskService, log)
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// warmCache precarga los usuarios y tareas más recientes del inquilino por defecto y de cada uno
+// de cache.warmup.tenants, con las claves de su inquilino.
+func warmCache(ctx context.Context, warmup config.CacheWarmupConfig, userService *userApp.UserService, taskService *taskApp.TaskService, log *zap.Logger) {
+	for _, tenant := range append([]string{""}, warmup.Tenants...) {
+		tenantCtx := ctx
+		if tenant != "" {
+			tenantCtx = tracing.NewContext(ctx, tracing.Context{TenantID: tenant})
+		}
+		start := time.Now()
+		users, err := userService.WarmCache(tenantCtx, warmup.Users)
+		if err != nil {
+			log.Warn("⚠️ No se pudo precargar la caché de usuarios", zap.String("tenant", tenant), zap.Error(err))
+		}
+		tasks, err := taskService.WarmCache(tenantCtx, warmup.Tasks)
+		if err != nil {
+			log.Warn("⚠️ No se pudo precargar la caché de tareas", zap.String("tenant", tenant), zap.Error(err))
+		}
+		log.Info("🔥 Caché precargada",
+			zap.String("tenant", tenant),
+			zap.Int("users", users),
+			zap.Int("tasks", tasks),
+			zap.Duration("elapsed", time.Since(start)),
+		)
+	}
+}
+
+// redisPoolStats traduce las estadísticas del pool de Redis. El cliente no cuenta las esperas,
+// solo las que agotan el timeout.
+func redisPoolStats(rdb *redis.Client) metrics.PoolStats {
+	s := rdb.PoolStats()
+	return metrics.PoolStats{
+		MaxOpen:  rdb.Options().PoolSize,
+		Open:     int(s.TotalConns),
+		InUse:    int(s.TotalConns - s.IdleConns),
+		Idle:     int(s.IdleConns),
+		Timeouts: int64(s.Timeouts),
+	}
+}
+
+// watchReloads aplica el nivel de log recargado y atiende SIGHUP. El resto de ajustes recargables
+// los registra el componente al que afectan (caché, log de consultas lentas, relayer).
+func watchReloads(lc fx.Lifecycle, runtime *config.Runtime, levels *logger.Levels, log *zap.Logger) {
+	runtime.OnReload(func(c *config.Config) {
+		if err := levels.SetLevel(c.Log.Level); err != nil {
+			log.Warn("⚠️ Nivel de log inválido", zap.Error(err))
+		}
+		if err := levels.SetModuleLevels(c.Log.Modules); err != nil {
+			log.Warn("⚠️ Niveles de log por módulo inválidos", zap.Error(err))
+		}
+	})
+
+	hup := make(chan os.Signal, 1)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			signal.Notify(hup, syscall.SIGHUP)
+			go func() {
+				for range hup {
+					log.Info("📥 SIGHUP recibido, recargando configuración")
+					sharedHttp.ReloadConfig(runtime, log)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			signal.Stop(hup)
+			close(hup)
+			return nil
+		},
+	})
+}
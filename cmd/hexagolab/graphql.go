@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	config "github.com/davicafu/hexagolab/internal/config"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	sharedGraphql "github.com/davicafu/hexagolab/internal/shared/infra/inbound/graphql"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskGraphql "github.com/davicafu/hexagolab/internal/task/infra/inbound/graphql"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userGraphql "github.com/davicafu/hexagolab/internal/user/infra/inbound/graphql"
+)
+
+// graphqlModule sirve la API GraphQL en /graphql, si http.graphql.enabled, y crea el feed de
+// eventos que comparte con el long polling de tareas. Forma parte de apiModule.
+var graphqlModule = fx.Module("graphql",
+	fx.Provide(newEventFeed),
+	fx.Invoke(registerGraphQLRoutes),
+)
+
+// Alias para embeber los resolvers de los módulos, que se llaman igual.
+type (
+	userResolver = userGraphql.Resolver
+	taskResolver = taskGraphql.Resolver
+)
+
+// graphqlResolver es el resolver raíz del esquema: los campos de cada módulo los resuelve el
+// resolver de ese módulo.
+type graphqlResolver struct {
+	*userResolver
+	*taskResolver
+}
+
+// registerGraphQLRoutes sirve el esquema de usuarios y tareas en GET y POST /graphql. Un esquema
+// que no se corresponde con los resolvers impide el arranque.
+func registerGraphQLRoutes(router *gin.Engine, cfg *config.Config, userService *userApp.UserService, taskService *taskApp.TaskService, feed *infraEvents.Feed, clock sharedDomain.Clock, log *zap.Logger) error {
+	if !cfg.HTTP.GraphQL.Enabled {
+		return nil
+	}
+	resolver := &graphqlResolver{
+		userResolver: userGraphql.NewResolver(userService, feed, clock),
+		taskResolver: taskGraphql.NewResolver(taskService, feed),
+	}
+	schema, err := sharedGraphql.NewSchema(resolver, cfg.HTTP.GraphQL.MaxDepth, log.Named("graphql"), userGraphql.Schema, taskGraphql.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
+	handler := sharedGraphql.NewHandler(schema)
+	router.GET("/graphql", handler.Serve)
+	router.POST("/graphql", handler.Serve)
+	log.Info("🧬 API GraphQL en /graphql")
+	return nil
+}
+
+// eventFeedParams son las dependencias de newEventFeed. Los buses en memoria solo existen con el
+// subcomando all y sin Kafka.
+type eventFeedParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Buses     inMemoryBuses `optional:"true"`
+	Log       *zap.Logger
+}
+
+// newEventFeed crea el feed de las suscripciones GraphQL y de GET /tasks/updates y, si alguno de
+// los dos está habilitado, lo alimenta con los eventos de usuario y de tarea: de los buses en memoria si los hay o, con Kafka, desde el final de cada topic en un
+// grupo de consumo propio de este proceso, para que cada réplica reciba todos los eventos. Si no
+// hay bus (subcomando serve con el bus en memoria), las suscripciones no reciben nada.
+func newEventFeed(p eventFeedParams) (*infraEvents.Feed, error) {
+	log := p.Log.Named("graphql")
+	feed := infraEvents.NewFeed(log)
+	if !p.Config.HTTP.GraphQL.Enabled && !p.Config.HTTP.LongPoll.Enabled {
+		return feed, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	switch {
+	case p.Buses.user != nil:
+		channels := map[string]<-chan interface{}{
+			userDomain.UserTopic: p.Buses.user.Subscribe(feedBufferSize),
+			taskDomain.TaskTopic: p.Buses.task.Subscribe(feedBufferSize),
+		}
+		p.Lifecycle.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				for topic, ch := range channels {
+					go forwardToFeed(ctx, ch, feed.Handler(topic), log)
+				}
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+	case p.Config.Bus.Kafka.Enabled:
+		opts := kafkaOptions(p.Config)
+		opts.StartOffset = "last"
+		group := "hexagolab-graphql-" + uuid.NewString()
+		var readers []*kafka.Reader
+		var adapters []*infraEvents.ConsumerAdapter
+		for _, topic := range []string{userDomain.UserTopic, taskDomain.TaskTopic} {
+			reader, err := infraEvents.NewKafkaReader(opts, topic, group)
+			if err != nil {
+				cancel()
+				for _, r := range readers {
+					r.Close()
+				}
+				return nil, fmt.Errorf("failed to create Kafka reader for %s: %w", topic, err)
+			}
+			readers = append(readers, reader)
+			adapters = append(adapters, infraEvents.NewConsumerAdapter(reader, feed.Handler(topic), log))
+		}
+		p.Lifecycle.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				for _, adapter := range adapters {
+					adapter.Start(ctx)
+				}
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				for _, r := range readers {
+					r.Close()
+				}
+				return nil
+			},
+		})
+	default:
+		cancel()
+		log.Info("Sin bus de eventos en este proceso: las suscripciones GraphQL y /tasks/updates no recibirán eventos")
+	}
+	return feed, nil
+}
+
+// feedBufferSize es el buffer de las suscripciones del feed a los buses en memoria.
+const feedBufferSize = 64
+
+// forwardToFeed entrega al feed los eventos de un bus en memoria hasta que se cancela ctx.
+func forwardToFeed(ctx context.Context, ch <-chan interface{}, handler infraEvents.MessageHandler, log *zap.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-ch:
+			if payload, ok := msg.([]byte); ok {
+				infraEvents.HandleWithRecovery(ctx, handler, "", payload, log)
+			}
+		}
+	}
+}
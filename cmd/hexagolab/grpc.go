@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	adminpb "github.com/davicafu/hexagolab/gen/go/admin"
+	taskpb "github.com/davicafu/hexagolab/gen/go/task"
+	userpb "github.com/davicafu/hexagolab/gen/go/user"
+	config "github.com/davicafu/hexagolab/internal/config"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedGrpc "github.com/davicafu/hexagolab/internal/shared/infra/inbound/grpc"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskGrpc "github.com/davicafu/hexagolab/internal/task/infra/inbound/grpc"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+	userGrpc "github.com/davicafu/hexagolab/internal/user/infra/inbound/grpc"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+)
+
+// grpcModule sirve la API gRPC de usuarios, tareas y administración (la de hexagolabctl).
+var grpcModule = fx.Module("grpc",
+	fx.Provide(newGRPCServer),
+	fx.Invoke(serveGRPC),
+)
+
+func newGRPCServer(cfg *config.Config, userService *userApp.UserService, taskService *taskApp.TaskService, userStores userStore.Stores, taskStores taskStore.Stores, clock sharedDomain.Clock, log *zap.Logger) *grpc.Server {
+	log = log.Named("grpc")
+	// Con autenticación el inquilino sale del token, como en HTTP. Reencolar o borrar eventos
+	// apartados exige además el rol de administrador; sin él se rechazan.
+	var verifier sharedGrpc.TokenVerifier
+	if cfg.Auth.Mode == "oidc" {
+		verifier = newOIDCVerifier(cfg.Auth.OIDC, clock)
+	}
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		sharedGrpc.TracingUnaryInterceptor(verifier),
+		sharedGrpc.RecoveryUnaryInterceptor(log),
+		sharedGrpc.TimeoutUnaryInterceptor(cfg.GRPC.RequestTimeout),
+		sharedGrpc.RequireRoleUnaryInterceptor(verifier, cfg.Auth.AdminRole,
+			adminpb.AdminService_RequeueDeadLetters_FullMethodName,
+			adminpb.AdminService_PurgeDeadLetters_FullMethodName,
+		),
+	))
+	userpb.RegisterUserServiceServer(srv, userGrpc.NewGrpcUserServer(userService))
+	taskpb.RegisterTaskServiceServer(srv, taskGrpc.NewGrpcTaskServer(taskService))
+
+	// Igual que en las métricas, solo se cuentan los outbox que saben hacerlo.
+	backlogs := make(map[string]sharedDomain.OutboxBacklogCounter)
+	deadLetters := make(map[string]sharedDomain.OutboxDeadLetterStore)
+	for name, outbox := range map[string]sharedDomain.OutboxRepository{"user": userStores.Outbox, "task": taskStores.Outbox} {
+		if counter, ok := outbox.(sharedDomain.OutboxBacklogCounter); ok {
+			backlogs[name] = counter
+		}
+		if store, ok := outbox.(sharedDomain.OutboxDeadLetterStore); ok {
+			deadLetters[name] = store
+		}
+	}
+	adminpb.RegisterAdminServiceServer(srv, sharedGrpc.NewGrpcAdminServer(backlogs, deadLetters))
+	return srv
+}
+
+// serveGRPC abre el puerto al arrancar y, al parar, espera a que terminen las llamadas en curso
+// (o corta las que queden si se agota el plazo de parada). Si el servidor cae, se pide el
+// apagado de toda la aplicación.
+func serveGRPC(lc fx.Lifecycle, shutdowner fx.Shutdowner, cfg *config.Config, srv *grpc.Server, log *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			ln, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+					log.Error("❌ El servidor gRPC ha fallado", zap.Error(err))
+					shutdowner.Shutdown(fx.ExitCode(1))
+				}
+			}()
+			log.Info("🔌 Servidor gRPC escuchando", zap.String("addr", "localhost:"+cfg.GRPC.Port))
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("🛑 Parando el servidor gRPC")
+			stopped := make(chan struct{})
+			go func() {
+				srv.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				srv.Stop()
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
+
+	config "github.com/davicafu/hexagolab/internal/config"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/audit"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/background"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/health"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+	infraRelayer "github.com/davicafu/hexagolab/internal/shared/infra/relayer"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskHttp "github.com/davicafu/hexagolab/internal/task/infra/inbound/http"
+	taskAnalytics "github.com/davicafu/hexagolab/internal/task/infra/outbound/analytics/clickhouse"
+	taskAnalyticsSQL "github.com/davicafu/hexagolab/internal/task/infra/outbound/analytics/postgres"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+	taskStorage "github.com/davicafu/hexagolab/internal/task/infra/outbound/filesystem"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+	userHttp "github.com/davicafu/hexagolab/internal/user/infra/inbound/http"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+)
+
+// httpModule sirve los endpoints de operación (/health, /readyz, /metrics y /admin), que
+// exponen todos los subcomandos de larga duración.
+var httpModule = fx.Module("http",
+	fx.Provide(newAuditor, newRouter),
+	fx.Invoke(serveHTTP),
+)
+
+// apiModule añade al router la API de usuarios, tareas, sus change feeds, el long polling de
+// tareas, el catálogo de eventos y, si hay backend, analítica.
+var apiModule = fx.Module("api",
+	fx.Provide(newAnalyticsRepo),
+	fx.Invoke(registerAPIRoutes, registerChangeFeedRoutes, registerTaskUpdatesRoutes),
+	graphqlModule,
+)
+
+func newRouter(cfg *config.Config, runtime *config.Runtime, registry *health.Registry, auditor *audit.Auditor, userStores userStore.Stores, taskStores taskStore.Stores, clock sharedDomain.Clock, log *zap.Logger) *gin.Engine {
+	log = log.Named("http")
+	if cfg.App.Env != "dev" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	// gin.New en lugar de gin.Default: el log de acceso y la recuperación de pánicos son los nuestros.
+	router := gin.New()
+	router.Use(sharedHttp.TracingMiddleware())
+	if cfg.HTTP.AccessLog.Enabled {
+		sampling, _ := cfg.HTTP.AccessLog.SampleRates() // Ya validado al cargar la configuración
+		router.Use(sharedHttp.AccessLogMiddleware(log, sampling))
+	}
+	router.Use(sharedHttp.MetricsMiddleware(), sharedHttp.RecoveryMiddleware(log))
+	router.Use(sharedHttp.BodyLimitMiddleware(sanitize.MaxBodyBytes))
+	timeouts, _ := cfg.HTTP.Timeouts() // Ya validado al cargar la configuración
+	router.Use(sharedHttp.TimeoutMiddleware(cfg.HTTP.RequestTimeout, timeouts))
+	var adminMiddlewares []gin.HandlerFunc
+	if cfg.Auth.Mode == "oidc" {
+		router.Use(sharedHttp.AuthMiddleware(newOIDCVerifier(cfg.Auth.OIDC, clock), cfg.Auth.PublicPaths, auditor))
+		// Con autenticación el inquilino sale siempre del token: sin tenant_claim, el de por defecto.
+		router.Use(sharedHttp.TenantMiddleware())
+		if cfg.Auth.AdminRole != "" {
+			adminMiddlewares = append(adminMiddlewares, sharedHttp.RequireRole(cfg.Auth.AdminRole, auditor))
+		}
+		log.Info("🔐 Autenticación OIDC habilitada", zap.String("issuer", cfg.Auth.OIDC.Issuer))
+	}
+	cacheControl, _ := cfg.HTTP.CacheControl() // Ya validado al cargar la configuración
+	router.Use(sharedHttp.CacheControlMiddleware(cacheControl))
+
+	admin := sharedHttp.NewAdminHandler(runtime, newAdminSources(userStores, taskStores), log)
+	sharedHttp.RegisterAdminRoutes(router, admin, adminMiddlewares...)
+	// Reencolar o borrar eventos apartados cambia el outbox: solo con el rol de administrador.
+	if len(adminMiddlewares) > 0 {
+		sharedHttp.RegisterDeadLetterChangeRoutes(router, admin, adminMiddlewares...)
+	} else {
+		log.Warn("⚠️ Sin auth.mode=oidc y auth.admin_role no se exponen /admin/dead-letters/requeue ni /admin/dead-letters/purge")
+	}
+	sharedHttp.RegisterMetricsRoutes(router)
+	sharedHttp.RegisterHealthRoutes(router, sharedHttp.NewHealthHandler(registry))
+	return router
+}
+
+// newAdminSources cuenta para /admin/stats los usuarios y las tareas, resume los outbox que saben
+// hacerlo y expone en /admin/dead-letters los que guardan sus eventos apartados.
+func newAdminSources(userStores userStore.Stores, taskStores taskStore.Stores) sharedHttp.AdminSources {
+	all := sharedDomain.CompositeCriteria{Operator: sharedDomain.OpAnd}
+	sources := sharedHttp.AdminSources{
+		Entities: map[string]func(context.Context) (int, error){
+			"users": func(ctx context.Context) (int, error) { return userStores.Users.CountByCriteria(ctx, all) },
+			"tasks": func(ctx context.Context) (int, error) { return taskStores.Tasks.CountByCriteria(ctx, all) },
+		},
+		Outboxes:    make(map[string]sharedDomain.OutboxStatsReader),
+		DeadLetters: make(map[string]sharedDomain.OutboxDeadLetterStore),
+	}
+	for name, outbox := range map[string]sharedDomain.OutboxRepository{"user": userStores.Outbox, "task": taskStores.Outbox} {
+		if reader, ok := outbox.(sharedDomain.OutboxStatsReader); ok {
+			sources.Outboxes[name] = reader
+		}
+		if store, ok := outbox.(sharedDomain.OutboxDeadLetterStore); ok {
+			sources.DeadLetters[name] = store
+		}
+	}
+	return sources
+}
+
+// newAuditor emite los eventos de seguridad de la API. Solo van al log hasta que
+// publishSecurityEvents le da el bus.
+func newAuditor(lc fx.Lifecycle, clock sharedDomain.Clock, ids sharedDomain.IDGenerator, log *zap.Logger) *audit.Auditor {
+	pool := background.NewPool("security", background.Options{Workers: 2}, log)
+	if err := pool.RegisterMetrics(); err != nil {
+		log.Warn("⚠️ No se pudo registrar la métrica del pool de eventos de seguridad", zap.Error(err))
+	}
+	lc.Append(fx.StopHook(pool.Close))
+	return audit.New(pool, clock, ids, log)
+}
+
+// newOIDCVerifier valida los tokens del proveedor de identidad configurado.
+func newOIDCVerifier(cfg config.OIDCConfig, clock sharedDomain.Clock) *oidc.Verifier {
+	roles, _ := cfg.Roles() // Ya validado al cargar la configuración
+	return oidc.NewVerifier(oidc.Options{
+		Issuer:      cfg.Issuer,
+		Audience:    cfg.Audience,
+		UserClaim:   cfg.UserClaim,
+		RolesClaim:  cfg.RolesClaim,
+		RoleMap:     roles,
+		KeysTTL:     cfg.KeysTTL,
+		TenantClaim: cfg.TenantClaim,
+	}, clock)
+}
+
+// serveHTTP abre el puerto al arrancar y, al parar, deja terminar las peticiones en curso.
+// Si el servidor cae, se pide el apagado de toda la aplicación. Con http.tls sirve HTTPS, que
+// net/http negocia como HTTP/2 por ALPN, y, si hay redirect_port, un segundo servidor en HTTP
+// plano que redirige a HTTPS.
+func serveHTTP(lc fx.Lifecycle, shutdowner fx.Shutdowner, cfg *config.Config, router *gin.Engine, log *zap.Logger) error {
+	tlsConfig, redirect, err := newTLSConfig(cfg.HTTP)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Addr: ":" + cfg.HTTP.Port, Handler: router, TLSConfig: tlsConfig}
+	servers := []*http.Server{srv}
+	if redirect != nil && cfg.HTTP.TLS.RedirectPort != "" {
+		servers = append(servers, &http.Server{
+			Addr:              ":" + cfg.HTTP.TLS.RedirectPort,
+			Handler:           redirect,
+			ReadHeaderTimeout: 10 * time.Second,
+		})
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			listeners := make([]net.Listener, 0, len(servers))
+			for _, s := range servers {
+				ln, err := net.Listen("tcp", s.Addr)
+				if err != nil {
+					for _, opened := range listeners {
+						_ = opened.Close()
+					}
+					return err
+				}
+				listeners = append(listeners, ln)
+			}
+			for i, s := range servers {
+				go func(s *http.Server, ln net.Listener) {
+					var err error
+					if s.TLSConfig != nil {
+						err = s.ServeTLS(ln, "", "") // Los certificados vienen en TLSConfig
+					} else {
+						err = s.Serve(ln)
+					}
+					if err != nil && !errors.Is(err, http.ErrServerClosed) {
+						log.Error("❌ El servidor HTTP ha fallado", zap.String("addr", s.Addr), zap.Error(err))
+						shutdowner.Shutdown(fx.ExitCode(1))
+					}
+				}(s, listeners[i])
+			}
+			scheme := "http"
+			if tlsConfig != nil {
+				scheme = "https"
+			}
+			log.Info("🔌 Servidor HTTP escuchando", zap.String("url", scheme+"://localhost:"+cfg.HTTP.Port))
+			if len(servers) > 1 {
+				log.Info("↪️ Redirigiendo HTTP a HTTPS", zap.String("port", cfg.HTTP.TLS.RedirectPort))
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("🛑 Parando el servidor HTTP")
+			var errs []error
+			for _, s := range servers {
+				errs = append(errs, s.Shutdown(ctx))
+			}
+			return errors.Join(errs...)
+		},
+	})
+	return nil
+}
+
+// newTLSConfig devuelve la configuración TLS del servidor según http.tls.mode (nil si es off) y
+// el handler del puerto de redirección: con autocert, además de redirigir, responde a los retos
+// http-01 de ACME.
+func newTLSConfig(cfg config.HTTPConfig) (*tls.Config, http.Handler, error) {
+	redirect := sharedHttp.HTTPSRedirect(cfg.Port)
+	switch cfg.TLS.Mode {
+	case "files":
+		cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading TLS certificate: %w", err)
+		}
+		return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, redirect, nil
+	case "autocert":
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLS.Domains...),
+			Cache:      autocert.DirCache(cfg.TLS.CacheDir),
+			Email:      cfg.TLS.Email,
+		}
+		tlsConfig := manager.TLSConfig() // Incluye h2 y acme-tls/1 en NextProtos
+		tlsConfig.MinVersion = tls.VersionTLS12
+		return tlsConfig, manager.HTTPHandler(redirect), nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// newAnalyticsRepo devuelve el repositorio del backend de analítica configurado, o nil si no hay
+// ninguno (o ClickHouse no responde).
+func newAnalyticsRepo(cfg *config.Config, conns *platformDB.Connections, registry *health.Registry, log *zap.Logger) (taskDomain.TaskAnalyticsRepository, error) {
+	switch cfg.Analytics.Backend {
+	case "clickhouse":
+		chRepo, err := taskAnalytics.NewTaskAnalyticsRepo(taskAnalytics.Options{
+			Addr:               cfg.Analytics.ClickHouse.Addr,
+			Database:           cfg.Analytics.ClickHouse.DB,
+			RetentionMonths:    cfg.Analytics.RetentionMonths,
+			MaxExecutionTime:   cfg.Analytics.ClickHouse.MaxExecutionTime,
+			DialTimeout:        cfg.Analytics.ClickHouse.DialTimeout,
+			Compression:        cfg.Analytics.ClickHouse.Compression,
+			AsyncInsert:        cfg.Analytics.ClickHouse.AsyncInsert,
+			WaitForAsyncInsert: cfg.Analytics.ClickHouse.WaitForAsyncInsert,
+		})
+		if err != nil {
+			log.Warn("⚠️ ClickHouse no disponible, analítica deshabilitada", zap.Error(err))
+			return nil, nil
+		}
+		if err := chRepo.InitSchema(); err != nil {
+			return nil, fmt.Errorf("failed to initialize ClickHouse schema: %w", err)
+		}
+		registry.Register("clickhouse", chRepo.Ping)
+		return chRepo, nil
+	case "postgres":
+		log.Info("📊 Analítica servida desde la base de datos transaccional")
+		pg, err := conns.Postgres(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Postgres for analytics: %w", err)
+		}
+		return taskAnalyticsSQL.NewTaskAnalyticsRepoPostgres(pg), nil
+	}
+	return nil, nil
+}
+
+// registerChangeFeedRoutes sirve en /users/changes y /tasks/changes los eventos del outbox de cada
+// dominio, con el mismo sobre que publica el relayer (y, con encryption.keys, descifrados como
+// él). Los outbox que no conservan los eventos publicados no tienen change feed.
+func registerChangeFeedRoutes(router *gin.Engine, userStores userStore.Stores, taskStores taskStore.Stores, keys *encryption.Keyring, clock sharedDomain.Clock, log *zap.Logger) {
+	encoder := infraRelayer.NewEncoder(newEventRegistry())
+	if keys != nil {
+		encoder.SetDecryption(keys)
+	}
+	for path, outbox := range map[string]sharedDomain.OutboxRepository{"/users/changes": userStores.Outbox, "/tasks/changes": taskStores.Outbox} {
+		reader, ok := outbox.(sharedDomain.OutboxChangeReader)
+		if !ok {
+			log.Info("Sin change feed: el outbox no conserva los eventos publicados", zap.String("path", path))
+			continue
+		}
+		router.GET(path, sharedHttp.NewChangeFeedHandler(reader, encoder, clock).Changes)
+	}
+}
+
+// registerTaskUpdatesRoutes sirve en /tasks/updates el long polling de las tareas de un usuario,
+// con los eventos del feed, si http.long_poll.enabled.
+func registerTaskUpdatesRoutes(router *gin.Engine, cfg *config.Config, taskService *taskApp.TaskService, feed *infraEvents.Feed) {
+	if !cfg.HTTP.LongPoll.Enabled {
+		return
+	}
+	taskHttp.RegisterTaskUpdatesRoutes(router, taskHttp.NewTaskUpdatesHandler(taskService, feed, cfg.HTTP.LongPoll.MaxWait))
+}
+
+func registerAPIRoutes(router *gin.Engine, cfg *config.Config, userService *userApp.UserService, taskService *taskApp.TaskService, counters *taskApp.TaskCounterService, analyticsRepo taskDomain.TaskAnalyticsRepository, clock sharedDomain.Clock, log *zap.Logger) {
+	userHttp.RegisterUserRoutes(router, userHttp.NewUserHandler(userService, clock))
+	taskHttp.RegisterTaskRoutes(router, taskHttp.NewTaskHandler(taskService, clock))
+	taskHttp.RegisterTaskSummaryRoutes(router, taskHttp.NewTaskSummaryHandler(counters))
+	sharedHttp.RegisterEventRoutes(router, sharedHttp.NewEventCatalogHandler(newEventRegistry()))
+
+	if analyticsRepo != nil {
+		analyticsService := taskApp.NewTaskAnalyticsService(analyticsRepo, taskStorage.NewFileBlobStorage(cfg.Analytics.ExportDir), log)
+		taskHttp.RegisterAnalyticsRoutes(router, taskHttp.NewAnalyticsHandler(analyticsService, log))
+	}
+}
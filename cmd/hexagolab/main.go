@@ -2,220 +2,169 @@ package main
 
 import (
 	"context"
-	"database/sql"
-	"encoding/json"
-	"time"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	config "github.com/davicafu/hexagolab/internal/config"
-	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
-	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/postgres"
-	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlite"
-	infraRelayer "github.com/davicafu/hexagolab/internal/shared/infra/relayer"
-	taskApp "github.com/davicafu/hexagolab/internal/task/application"
-	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
-	taskEvents "github.com/davicafu/hexagolab/internal/task/infra/inbound/events"
-	taskHttp "github.com/davicafu/hexagolab/internal/task/infra/inbound/http"
-	taskRepo "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/postgre"
-	userApp "github.com/davicafu/hexagolab/internal/user/application"
-	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
-	userEvents "github.com/davicafu/hexagolab/internal/user/infra/inbound/events"
-	userHttp "github.com/davicafu/hexagolab/internal/user/infra/inbound/http"
-	userCache "github.com/davicafu/hexagolab/internal/user/infra/outbound/cache"
-	userRepo "github.com/davicafu/hexagolab/internal/user/infra/outbound/db/sqlite"
-	"github.com/google/uuid"
-
-	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
-	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
-	sharedCache "github.com/davicafu/hexagolab/internal/shared/infra/platform/cache"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/redact"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/reporting"
 	"github.com/davicafu/hexagolab/pkg/logger"
-
-	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis/v8"
-	"github.com/segmentio/kafka-go"
-	"go.uber.org/zap"
-
-	// _ "github.com/mattn/go-sqlite3" // requires gcc
-	_ "modernc.org/sqlite"
 )
 
 // ---------------- Main ----------------
 func main() {
-	logger.Init()          // inicializa zap
-	log := logger.Logger() // obtiene logger estructurado
-	defer log.Sync()       // flush buffers al salir
-
-	ctx := context.Background()
-	cfg := config.LoadConfig()
+	cmd, args, ok := parseCommand(os.Args[1:])
+	if !ok {
+		printCommands(os.Stderr)
+		os.Exit(2)
+	}
+	if cmd.name == "help" {
+		printCommands(os.Stdout)
+		return
+	}
+	if cmd.flags != nil {
+		var err error
+		if args, err = splitFlags(cmd.flags, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
 
-	// ---------------- DB ----------------
-	db, err := sql.Open("sqlite", cfg.SQLitePath)
+	levels := logger.NewLevels()
+	log, err := logger.New(levels)
 	if err != nil {
-		log.Fatal("failed to open SQLite", zap.Error(err))
+		fmt.Fprintln(os.Stderr, "failed to create logger:", err)
+		os.Exit(1)
 	}
-	defer db.Close()
+	defer log.Sync() // flush buffers al salir
 
-	if err := userRepo.InitSQLite(db); err != nil {
-		log.Fatal("failed to initialize SQLite", zap.Error(err))
+	cfg, err := config.Load(args)
+	if errors.Is(err, config.ErrHelp) {
+		return // --help ya ha impreso la ayuda
 	}
-
-	userRepoSQLite := userRepo.NewUserRepoSQLite(db)
-
-	if err := db.PingContext(ctx); err != nil {
-		log.Fatal("failed to ping SQLite", zap.Error(err))
+	if err != nil {
+		log.Fatal("failed to load config", zap.Error(err))
 	}
-
-	taskRepoPostgres := taskRepo.NewTaskRepoPostgres(db)
-
-	// ---------------- Cache ----------------
-	var cacheInstance sharedCache.Cache
-	rdb := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		log.Warn("⚠️ Redis no disponible, cache en memoria:", zap.Error(err))
-		cacheInstance = userCache.NewInMemoryCache(cfg.CacheTTL, 3*cfg.CacheTTL)
-	} else {
-		cacheInstance = userCache.NewRedisCache(rdb, cfg.CacheTTL)
-		log.Info("✅ Redis conectado, cache habilitado")
+	if err := levels.SetLevel(cfg.Log.Level); err != nil {
+		log.Fatal("invalid log level", zap.Error(err))
 	}
+	if err := levels.SetModuleLevels(cfg.Log.Modules); err != nil {
+		log.Fatal("invalid module log levels", zap.Error(err))
+	}
+	log.Info("🌍 Perfil de entorno", zap.String("app_env", cfg.App.Env), zap.String("command", cmd.name))
 
-	// --------------- Servicio --------------
-	userService := userApp.NewUserService(userRepoSQLite, cacheInstance, log)
-	taskService := taskApp.NewTaskService(taskRepoPostgres, cacheInstance, log)
-
-	// ---------------- Events ---------------
-	var eventUserPublisher sharedBus.EventBus
-	var eventTaskPublisher sharedBus.EventBus
-
-	if cfg.UseKafka {
-		log.Info("🚀 Usando Kafka como bus de eventos")
-
-		// El writer ya no necesita un topic específico, es genérico.
-		// Puede escribir en cualquier topic que se le pase en el mensaje.
-		userWriter := kafka.NewWriter(kafka.WriterConfig{
-			Brokers: cfg.KafkaBrokers,
-			Topic:   userDomain.UserTopic, // Topic por defecto, pero puede ser cualquier otro.
-		})
-
-		taskWriter := kafka.NewWriter(kafka.WriterConfig{
-			Brokers: cfg.KafkaBrokers,
-			Topic:   taskDomain.TaskTopic, // Topic por defecto, pero puede ser cualquier otro.
-		})
-
-		defer userWriter.Close()
-		defer taskWriter.Close()
-
-		eventUserPublisher = infraEvents.NewKafkaPublisher(userWriter, log)
-		eventTaskPublisher = infraEvents.NewKafkaPublisher(taskWriter, log)
-
-		userConsumer := userEvents.NewUserConsumer(userService, log)
-		taskConsumer := taskEvents.NewTaskConsumer(taskService, log)
-
-		userKafkaReader := kafka.NewReader(kafka.ReaderConfig{
-			Brokers:  cfg.KafkaBrokers,
-			Topic:    userDomain.UserTopic,
-			GroupID:  "hexagolab-user-service",
-			MinBytes: 10e3, // 10KB
-			MaxBytes: 10e6, // 10MB
-		})
-		defer userKafkaReader.Close()
-
-		taskKafkaReader := kafka.NewReader(kafka.ReaderConfig{
-			Brokers:  cfg.KafkaBrokers,
-			Topic:    taskDomain.TaskTopic,
-			GroupID:  "hexagolab-user-service",
-			MinBytes: 10e3, // 10KB
-			MaxBytes: 10e6, // 10MB
-		})
-		defer userKafkaReader.Close()
-
-		userConsumerAdapter := infraEvents.NewConsumerAdapter(userKafkaReader, userConsumer, log)
-		taskConsumerAdapter := infraEvents.NewConsumerAdapter(taskKafkaReader, taskConsumer, log)
-
-		userConsumerAdapter.Start(ctx)
-		taskConsumerAdapter.Start(ctx)
-
-	} else {
-		log.Info("⚡️Usando bus de eventos en memoria (canales de Go)")
-
-		inMemoryUserBus := infraEvents.NewInMemoryEventBus(userDomain.UserTopic)
-		inMemoryTaskBus := infraEvents.NewInMemoryEventBus(taskDomain.TaskTopic)
-
-		eventUserPublisher = inMemoryUserBus
-		eventTaskPublisher = infraEvents.NewInMemoryEventBus(taskDomain.TaskTopic)
-
-		userConsumer := userEvents.NewUserConsumer(userService, log)
-		taskConsumer := taskEvents.NewTaskConsumer(taskService, log)
-
-		userEventsChannel := inMemoryUserBus.Subscribe(10)
-		taskEventsChannel := inMemoryTaskBus.Subscribe(10)
-
-		log.Info("🎧 Iniciando listener en memoria para eventos de usuario")
-		userEvents.BackgroundConsumerChan(ctx, userEventsChannel, userConsumer)
-
-		log.Info("🎧 Iniciando listener en memoria para eventos de tarea")
-		taskEvents.BackgroundConsumerChan(ctx, taskEventsChannel, taskConsumer)
-
-		// Simulamos la publicación de un evento de usuario
-		userCreatedEvent := sharedEvents.UserCreated{
-			ID:        uuid.New(),
-			Email:     "simulated.user@example.com",
-			Nombre:    "Usuario Simulado",
-			BirthDate: time.Now().AddDate(-30, 0, 0),
-		}
+	// ---------------- Sentry ----------------
+	// Opcional: sin DSN no se envía nada. Los logs de error y los pánicos recuperados llegan a Sentry.
+	sentryEnabled, err := reporting.Init(reporting.Options{DSN: cfg.Sentry.DSN, Environment: cfg.App.Env, Release: cfg.Sentry.Release})
+	if err != nil {
+		log.Fatal("failed to initialize error reporting", zap.Error(err))
+	}
+	if sentryEnabled {
+		log = log.WithOptions(reporting.ZapOption())
+		defer reporting.Flush()
+		log.Info("🛰️ Envío de errores a Sentry habilitado", zap.String("release", cfg.Sentry.Release))
+	}
+	// Después de Sentry, para que los eventos que se le envían también salgan sin datos personales.
+	if cfg.Log.Redact {
+		log = log.WithOptions(redact.ZapOption())
+	}
 
-		payloadBytes, _ := json.Marshal(userCreatedEvent)
-		simulatedEvent := sharedEvents.IntegrationEvent{
-			Type: userDomain.UserCreated,
-			Data: payloadBytes,
-		}
-		err := eventUserPublisher.Publish(context.Background(), simulatedEvent)
-		if err != nil {
-			log.Error("Fallo al publicar el evento simulado", zap.Error(err))
-		} else {
-			log.Info("✅ Evento 'UserCreated' simulado y publicado correctamente")
-		}
+	options, err := cmd.options(cfg)
+	if err != nil {
+		log.Fatal("invalid command", zap.String("command", cmd.name), zap.Error(err))
+	}
+	ready := readiness{readyFile: cfg.App.ReadyFile, log: log}
+	app := fx.New(
+		fx.Supply(cfg, config.NewRuntime(cfg, args), log, levels),
+		fx.WithLogger(func() fxevent.Logger {
+			fxLog := &fxevent.ZapLogger{Logger: log}
+			fxLog.UseLogLevel(zapcore.DebugLevel) // El detalle del cableado solo interesa al depurar
+			return fxLog
+		}),
+		options,
+		fx.Populate(&ready.registry, &ready.startup),
+	)
+	if err := run(app, cmd.oneShot, ready); err != nil {
+		log.Fatal("command failed", zap.String("command", cmd.name), zap.Error(err))
+	}
+}
 
+// run arranca la aplicación (los hooks OnStart de cada adaptador, en orden de dependencias) y la
+// para (los OnStop, en orden inverso) al terminar un comando de una sola ejecución, al recibir
+// SIGINT/SIGTERM o si un componente pide el apagado. Los comandos de larga duración avisan de que
+// están listos (ver readiness) cuando terminan de arrancar, no al abrir los puertos.
+func run(app *fx.App, oneShot bool, ready readiness) error {
+	if err := app.Err(); err != nil {
+		return err
 	}
 
-	// ------------ Outbox Worker ------------
-	// Se podría ejecutar externamente
-	eventRegistry := make(map[string]sharedEvents.EventMetadata)
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		return err
+	}
 
-	// Merge de los registros de cada dominio
+	exitCode := 0
+	if !oneShot {
+		readyCtx, cancelReady := context.WithCancel(context.Background())
+		go ready.await(readyCtx)
+		exitCode = (<-app.Wait()).ExitCode
+		cancelReady()
+		ready.stopping()
+	}
 
-	for k, v := range userDomain.NewEventRegistry() {
-		eventRegistry[k] = v
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancel()
+	if err := app.Stop(stopCtx); err != nil {
+		return err
 	}
-	for k, v := range taskDomain.NewEventRegistry() {
-		eventRegistry[k] = v
+	if exitCode != 0 {
+		return fmt.Errorf("stopped after a component failure (exit code %d)", exitCode)
 	}
+	return nil
+}
 
-	if cfg.LocalDeployment {
-		outboxRepoSQLite := sqlite.NewOutboxRepoSQLite(db)
-		outboxUserWorker := infraRelayer.NewOutboxWorker(outboxRepoSQLite, eventUserPublisher, eventRegistry, cfg.OutboxPeriod, cfg.OutboxLimit, log)
-		outboxUserWorker.Start(ctx)
-		outboxTaskWorker := infraRelayer.NewOutboxWorker(outboxRepoSQLite, eventTaskPublisher, eventRegistry, cfg.OutboxPeriod, cfg.OutboxLimit, log)
-		outboxTaskWorker.Start(ctx)
-	} else {
-		outboxRepoPostgres := postgres.NewOutboxRepoPostgres(db)
-		outboxUserWorker := infraRelayer.NewOutboxWorker(outboxRepoPostgres, eventUserPublisher, eventRegistry, cfg.OutboxPeriod, cfg.OutboxLimit, log)
-		outboxUserWorker.Start(ctx)
+// parseCommand separa el subcomando de los flags. Sin subcomando (o si el primer argumento es un
+// flag) se ejecuta "all", como antes de existir los subcomandos.
+func parseCommand(args []string) (command, []string, bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return commands[0], args, true
 	}
+	if args[0] == "help" {
+		return command{name: "help"}, nil, true
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd, args[1:], true
+		}
+	}
+	return command{}, nil, false
+}
 
-	// ---------------- HTTP ----------------
-	userHandler := userHttp.NewUserHandler(userService)
-	taskHandler := taskHttp.NewTaskHandler(taskService)
-	router := gin.Default()
-	userHttp.RegisterUserRoutes(router, userHandler)
-	taskHttp.RegisterTaskRoutes(router, taskHandler)
-
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
-
-	log.Info("🚀 Server running",
-		zap.String("url", "http://localhost:"+cfg.HTTPPort),
-	)
-	if err := router.Run(":" + cfg.HTTPPort); err != nil {
-		log.Fatal("failed to start server: %v", zap.Error(err))
+func printCommands(w io.Writer) {
+	fmt.Fprintln(w, "Usage: hexagolab [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.summary)
+		if cmd.flags != nil {
+			cmd.flags.VisitAll(func(f *flag.Flag) {
+				fmt.Fprintf(tw, "    --%s\t%s (default %s)\n", f.Name, f.Usage, f.DefValue)
+			})
+		}
 	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'hexagolab [command] --help' to list the configuration flags.")
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/health"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/systemd"
+)
+
+// readinessInterval es la pausa entre comprobaciones de las dependencias mientras no están listas.
+const readinessInterval = time.Second
+
+// readiness avisa de que el proceso está listo (sd_notify y, si se configura, un fichero) solo
+// cuando ha terminado de arrancar: esquemas inicializados, servidores escuchando, primer polling
+// de cada outbox, precarga de la caché y todas las dependencias registradas (bases de datos,
+// Kafka...) respondiendo.
+type readiness struct {
+	registry  *health.Registry
+	startup   *health.Startup
+	readyFile string
+	log       *zap.Logger
+}
+
+// await espera a que el servicio esté listo y lo notifica. Mientras tanto registra qué falta
+// cada vez que cambia. Se abandona si se cancela ctx.
+func (r readiness) await(ctx context.Context) {
+	start := time.Now()
+	if err := r.startup.Wait(ctx); err != nil {
+		return
+	}
+
+	var lastDown []string
+	for {
+		report := r.registry.Check(ctx)
+		if report.Ready() {
+			break
+		}
+		var down []string
+		for name, component := range report.Components {
+			if component.Status != health.StatusUp {
+				down = append(down, name)
+			}
+		}
+		slices.Sort(down)
+		if !slices.Equal(down, lastDown) {
+			r.log.Warn("⏳ Esperando a las dependencias antes de declarar el servicio listo", zap.Strings("down", down))
+			lastDown = down
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(readinessInterval):
+		}
+	}
+
+	if sent, err := systemd.Notify(systemd.Ready); err != nil {
+		r.log.Warn("⚠️ No se pudo notificar a systemd", zap.Error(err))
+	} else if sent {
+		r.log.Debug("📣 systemd notificado", zap.String("state", systemd.Ready))
+	}
+	if r.readyFile != "" {
+		if err := os.WriteFile(r.readyFile, []byte("ready\n"), 0o644); err != nil {
+			r.log.Warn("⚠️ No se pudo crear el fichero de readiness", zap.String("path", r.readyFile), zap.Error(err))
+		}
+	}
+	r.log.Info("✅ Servicio listo", zap.Strings("dependencies", r.registry.Names()), zap.Duration("elapsed", time.Since(start)))
+}
+
+// stopping avisa de que el proceso empieza a pararse, antes de cerrar los servidores.
+func (r readiness) stopping() {
+	if _, err := systemd.Notify(systemd.Stopping); err != nil {
+		r.log.Warn("⚠️ No se pudo notificar a systemd", zap.Error(err))
+	}
+	if r.readyFile != "" {
+		if err := os.Remove(r.readyFile); err != nil && !os.IsNotExist(err) {
+			r.log.Warn("⚠️ No se pudo borrar el fichero de readiness", zap.String("path", r.readyFile), zap.Error(err))
+		}
+	}
+}
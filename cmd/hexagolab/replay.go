@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	config "github.com/davicafu/hexagolab/internal/config"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	infraRelayer "github.com/davicafu/hexagolab/internal/shared/infra/relayer"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskEvents "github.com/davicafu/hexagolab/internal/task/infra/inbound/events"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+)
+
+// replayBatch es el número de eventos que se leen del outbox en cada página.
+const replayBatch = 500
+
+// replayParams son los flags propios de `hexagolab replay`.
+type replayParams struct {
+	tenants string
+}
+
+var replayArgs replayParams
+
+func replayFlagSet(p *replayParams) *flag.FlagSet {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.StringVar(&p.tenants, "tenants", p.tenants, "Comma-separated tenants rebuilt besides the default one")
+	return fs
+}
+
+// errReplayUnsupported se devuelve si el outbox de tareas no permite leer su histórico (backend file).
+var errReplayUnsupported = errors.New("the task outbox of this backend cannot be replayed")
+
+// replayOptions reconstruye las proyecciones derivadas de los eventos de tareas releyendo el
+// outbox, incluidos los eventos ya publicados.
+func replayOptions(cfg *config.Config) (fx.Option, error) {
+	tenants, err := replayTenants(replayArgs.tenants)
+	if err != nil {
+		return nil, err
+	}
+	return fx.Options(coreModule, servicesModule, fx.Invoke(func(lc fx.Lifecycle, taskStores taskStore.Stores, counters *taskApp.TaskCounterService, keys *encryption.Keyring, clock sharedDomain.Clock, log *zap.Logger) {
+		lc.Append(fx.StartHook(func(ctx context.Context) error {
+			reader, ok := taskStores.Outbox.(sharedDomain.OutboxChangeReader)
+			if !ok {
+				return fmt.Errorf("%w (%s)", errReplayUnsupported, cfg.DB.Task)
+			}
+			encoder := infraRelayer.NewEncoder(newEventRegistry())
+			if keys != nil {
+				encoder.SetDecryption(keys)
+			}
+			for _, tenant := range tenants {
+				tenantCtx := ctx
+				if tenant != "" {
+					tenantCtx = tracing.NewContext(ctx, tracing.Context{TenantID: tenant})
+				}
+				start := time.Now()
+				applied, err := replayTaskCounters(tenantCtx, reader, encoder, taskStores.Tasks.GetByID, counters, clock)
+				if err != nil {
+					return fmt.Errorf("failed to rebuild task counters of tenant %q: %w", tenant, err)
+				}
+				log.Info("✅ Contadores de tareas reconstruidos",
+					zap.String("tenant", tenant),
+					zap.Int("events", applied),
+					zap.Duration("elapsed", time.Since(start)),
+				)
+			}
+			return nil
+		}))
+	})), nil
+}
+
+// replayTenants devuelve el inquilino por defecto seguido de los de --tenants.
+func replayTenants(raw string) ([]string, error) {
+	tenants := []string{""}
+	for _, tenant := range strings.Split(raw, ",") {
+		tenant = strings.TrimSpace(tenant)
+		if tenant == "" {
+			continue
+		}
+		if !tracing.ValidTenantID(tenant) {
+			return nil, fmt.Errorf("invalid tenant %q in --tenants", tenant)
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, nil
+}
+
+// replayTaskCounters vacía los contadores de tareas del inquilino de ctx y les vuelve a aplicar
+// todos sus eventos del outbox, en el orden del change feed. Los eventos con payload parcial se
+// completan con el estado actual de la tarea según 'lookup'. Devuelve cuántos eventos han
+// cambiado la proyección.
+func replayTaskCounters(ctx context.Context, reader sharedDomain.OutboxChangeReader, encoder *infraRelayer.Encoder, lookup taskEvents.TaskLookup, counters *taskApp.TaskCounterService, clock sharedDomain.Clock) (int, error) {
+	return counters.Rebuild(ctx, func(apply func(taskDomain.TaskCounterChange) error) error {
+		var after sharedDomain.OutboxPosition
+		for {
+			events, err := reader.ReadChanges(ctx, after, replayBatch)
+			if err != nil {
+				return fmt.Errorf("failed to read task outbox: %w", err)
+			}
+			for _, evt := range events {
+				after = evt.Position()
+				base, err := encoder.Encode(evt, clock.Now())
+				if err != nil {
+					return fmt.Errorf("failed to decode outbox event %s: %w", evt.ID, err)
+				}
+				change, ok, err := taskEvents.CounterChange(ctx, base, lookup)
+				if err != nil {
+					return fmt.Errorf("outbox event %s: %w", evt.ID, err)
+				}
+				if !ok {
+					continue
+				}
+				if err := apply(change); err != nil {
+					return err
+				}
+			}
+			if len(events) < replayBatch {
+				return nil
+			}
+		}
+	})
+}
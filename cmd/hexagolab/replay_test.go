@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/payload"
+	infraRelayer "github.com/davicafu/hexagolab/internal/shared/infra/relayer"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+func TestReplayTaskCounters(t *testing.T) {
+	tests := []struct {
+		name  string
+		modes payload.Modes
+	}{
+		{name: "snapshot"},
+		{name: "delta", modes: payload.Modes{taskDomain.TaskUpdated: sharedEvents.PayloadDelta}},
+		{name: "id", modes: payload.Modes{taskDomain.TaskUpdated: sharedEvents.PayloadID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange: tareas de Ana cuyos eventos ya se han publicado y unos contadores desfasados.
+			ctx := context.Background()
+			conns := platformDB.NewConnections(platformDB.Options{})
+			t.Cleanup(func() { conns.Close(ctx) })
+			stores, err := taskStore.NewStores(ctx, platformDB.BackendMemory, conns)
+			require.NoError(t, err)
+			service := taskApp.NewTaskService(stores.Tasks, mocks.NewDummyCache(), nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+			service.SetPayloadModes(tt.modes)
+			counters := taskApp.NewTaskCounterService(stores.Counters)
+
+			ana := uuid.New()
+			done, err := service.CreateTask(ctx, "Revisar el backlog", "", &ana)
+			require.NoError(t, err)
+			_, err = service.CreateTask(ctx, "Preparar la demo", "", &ana)
+			require.NoError(t, err)
+			deleted, err := service.CreateTask(ctx, "Documentar la API", "", &ana)
+			require.NoError(t, err)
+			_, err = service.BulkTransitionStatus(ctx, []uuid.UUID{done.ID}, taskDomain.TaskCompleted)
+			require.NoError(t, err)
+			require.NoError(t, service.DeleteTask(ctx, deleted.ID))
+			pending, err := stores.Outbox.FetchPendingOutbox(ctx, 100)
+			require.NoError(t, err)
+			for _, evt := range pending {
+				require.NoError(t, stores.Outbox.MarkOutboxProcessed(ctx, evt.ID))
+			}
+			require.NoError(t, counters.Apply(ctx, taskDomain.TaskCounterChange{TaskID: uuid.New(), AssigneeID: &ana, Status: taskDomain.TaskPending}))
+
+			// Act
+			applied, err := replayTaskCounters(ctx, stores.Outbox.(sharedDomain.OutboxChangeReader), infraRelayer.NewEncoder(newEventRegistry()), stores.Tasks.GetByID, counters, sharedDomain.SystemClock{})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, 5, applied)
+			summary, err := counters.TaskSummary(ctx, ana)
+			require.NoError(t, err)
+			assert.Equal(t, taskDomain.TaskCounts{Pending: 1, Completed: 1}, summary.TaskCounts)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+)
+
+// seedParams son los flags propios de `hexagolab seed`.
+type seedParams struct {
+	users int
+	tasks int
+	seed  int64
+}
+
+// defaultSeedParams son los valores por defecto de `hexagolab seed`, que usa también el modo demo.
+var defaultSeedParams = seedParams{users: 10, tasks: 50, seed: 42}
+
+var seedArgs = defaultSeedParams
+
+func seedFlagSet(p *seedParams) *flag.FlagSet {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.IntVar(&p.users, "users", p.users, "Number of demo users to create")
+	fs.IntVar(&p.tasks, "tasks", p.tasks, "Number of demo tasks, assigned at random among the new users")
+	fs.Int64Var(&p.seed, "seed", p.seed, "Random seed; the same seed always generates the same data")
+	return fs
+}
+
+// seedReferenceDate fija las fechas de nacimiento generadas para que no dependan del día en que se ejecuta.
+var seedReferenceDate = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	seedFirstNames = []string{"Ana", "Luis", "Marta", "Carlos", "Lucía", "Javier", "Elena", "Pablo", "Sara", "Diego", "Laura", "Andrés", "Irene", "Sergio", "Paula", "Raúl"}
+	seedLastNames  = []string{"García", "Martín", "López", "Sánchez", "Pérez", "Gómez", "Fernández", "Díaz", "Ruiz", "Moreno", "Romero", "Navarro"}
+	seedTaskVerbs  = []string{"Revisar", "Preparar", "Actualizar", "Documentar", "Migrar", "Probar", "Desplegar", "Optimizar", "Corregir", "Diseñar"}
+	seedTaskThings = []string{"el backlog", "la demo", "las dependencias", "la API de usuarios", "el esquema de tareas", "los tests de integración", "el dashboard", "el pipeline de CI", "la caché", "el outbox"}
+	seedTaskWhy    = []string{"antes de la próxima release", "a petición de soporte", "para el sprint actual", "tras la incidencia del lunes", "según lo acordado en la retro"}
+
+	// unaccent deja los nombres en ASCII para construir los emails.
+	unaccent = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ñ", "n")
+)
+
+type demoUser struct {
+	email     string
+	nombre    string
+	birthDate time.Time
+}
+
+type demoTask struct {
+	title       string
+	description string
+	assignee    int // Índice en la lista de usuarios generados
+	status      taskDomain.TaskStatus
+}
+
+// generateDemoData genera los datos de forma determinista a partir de p.seed. Los emails incluyen
+// la semilla, así que semillas distintas se pueden sembrar en la misma base de datos.
+func generateDemoData(p seedParams) ([]demoUser, []demoTask) {
+	rng := rand.New(rand.NewSource(p.seed))
+
+	users := make([]demoUser, p.users)
+	for i := range users {
+		first := seedFirstNames[rng.Intn(len(seedFirstNames))]
+		last := seedLastNames[rng.Intn(len(seedLastNames))]
+		age := 16 + rng.Intn(55)
+		users[i] = demoUser{
+			email:     fmt.Sprintf("%s.%s+%d.%d@example.com", unaccent.Replace(strings.ToLower(first)), unaccent.Replace(strings.ToLower(last)), p.seed, i),
+			nombre:    first + " " + last,
+			birthDate: seedReferenceDate.AddDate(-age, 0, -rng.Intn(365)),
+		}
+	}
+
+	tasks := make([]demoTask, p.tasks)
+	for i := range tasks {
+		thing := seedTaskThings[rng.Intn(len(seedTaskThings))]
+		status := taskDomain.TaskPending
+		switch n := rng.Intn(10); {
+		case n >= 9:
+			status = taskDomain.TaskFailed
+		case n >= 6:
+			status = taskDomain.TaskCompleted
+		}
+		tasks[i] = demoTask{
+			title:       seedTaskVerbs[rng.Intn(len(seedTaskVerbs))] + " " + thing,
+			description: fmt.Sprintf("Tarea sobre %s %s.", thing, seedTaskWhy[rng.Intn(len(seedTaskWhy))]),
+			assignee:    rng.Intn(max(p.users, 1)),
+			status:      status,
+		}
+	}
+	return users, tasks
+}
+
+// seedDemoData crea los datos a través de los servicios, así que sus eventos quedan en el outbox
+// y los publica el relayer como cualquier otro. Las tareas que no quedan pendientes se crean y
+// después se completan o fallan, igual que en la API.
+func seedDemoData(ctx context.Context, p seedParams, userService *userApp.UserService, taskService *taskApp.TaskService, log *zap.Logger) error {
+	if p.users < 0 || p.tasks < 0 {
+		return fmt.Errorf("--users and --tasks must not be negative")
+	}
+	if p.tasks > 0 && p.users == 0 {
+		return fmt.Errorf("--tasks needs at least one user to assign them to")
+	}
+
+	start := time.Now()
+	users, tasks := generateDemoData(p)
+
+	ids := make([]uuid.UUID, len(users))
+	for i, demo := range users {
+		user, err := userService.CreateUser(ctx, demo.email, demo.nombre, demo.birthDate)
+		if err != nil {
+			return fmt.Errorf("failed to create demo user %s (already seeded with --seed %d?): %w", demo.email, p.seed, err)
+		}
+		ids[i] = user.ID
+	}
+	log.Info("👥 Usuarios de demostración creados", zap.Int("users", len(users)))
+
+	for i, demo := range tasks {
+		assigneeID := ids[demo.assignee]
+		task, err := taskService.CreateTask(ctx, demo.title, demo.description, &assigneeID)
+		if err != nil {
+			return fmt.Errorf("failed to create demo task %q: %w", demo.title, err)
+		}
+		if demo.status != taskDomain.TaskPending {
+			if demo.status == taskDomain.TaskCompleted {
+				task.Complete()
+			} else {
+				task.Fail()
+			}
+			if err := taskService.UpdateTask(ctx, task); err != nil {
+				return fmt.Errorf("failed to update demo task %s: %w", task.ID, err)
+			}
+		}
+		if (i+1)%1000 == 0 {
+			log.Info("🌱 Sembrando tareas", zap.Int("done", i+1), zap.Int("total", len(tasks)))
+		}
+	}
+
+	log.Info("🌱 Datos de demostración creados",
+		zap.Int("users", len(users)),
+		zap.Int("tasks", len(tasks)),
+		zap.Int64("seed", p.seed),
+		zap.Duration("elapsed", time.Since(start)),
+	)
+	return nil
+}
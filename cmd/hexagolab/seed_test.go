@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+func TestGenerateDemoData_IsDeterministic(t *testing.T) {
+	// Arrange
+	params := seedParams{users: 20, tasks: 200, seed: 7}
+
+	// Act
+	users, tasks := generateDemoData(params)
+	usersAgain, tasksAgain := generateDemoData(params)
+	otherUsers, _ := generateDemoData(seedParams{users: 20, tasks: 200, seed: 8})
+
+	// Assert
+	assert.Equal(t, users, usersAgain, "La misma semilla genera los mismos datos")
+	assert.Equal(t, tasks, tasksAgain)
+	assert.NotEqual(t, users[0].email, otherUsers[0].email, "Los emails de semillas distintas no chocan")
+
+	require.Len(t, users, 20)
+	require.Len(t, tasks, 200)
+	emails := map[string]bool{}
+	for _, u := range users {
+		assert.False(t, emails[u.email], "Email repetido: %s", u.email)
+		emails[u.email] = true
+		assert.Regexp(t, `^[a-z]+\.[a-z]+\+7\.\d+@example\.com$`, u.email)
+	}
+	statuses := map[taskDomain.TaskStatus]int{}
+	for _, task := range tasks {
+		assert.Less(t, task.assignee, 20)
+		statuses[task.status]++
+	}
+	assert.Len(t, statuses, 3, "Hay tareas pendientes, completadas y fallidas")
+}
+
+func TestSplitFlags(t *testing.T) {
+	// Arrange
+	params := seedParams{users: 10, tasks: 50, seed: 42}
+	fs := seedFlagSet(&params)
+
+	// Act
+	rest, err := splitFlags(fs, []string{"--users", "3", "--db-path", "/tmp/demo.db", "--tasks=12", "-seed", "9", "--bus.kafka.enabled"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, seedParams{users: 3, tasks: 12, seed: 9}, params)
+	assert.Equal(t, []string{"--db-path", "/tmp/demo.db", "--bus.kafka.enabled"}, rest, "Los flags de configuración se mantienen")
+
+	_, err = splitFlags(fs, []string{"--users", "many"})
+	assert.ErrorContains(t, err, `invalid value "many" for flag --users`)
+	_, err = splitFlags(fs, []string{"--tasks"})
+	assert.ErrorContains(t, err, "flag needs an argument: --tasks")
+}
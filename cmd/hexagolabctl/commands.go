@@ -0,0 +1,395 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	adminpb "github.com/davicafu/hexagolab/gen/go/admin"
+	taskpb "github.com/davicafu/hexagolab/gen/go/task"
+	userpb "github.com/davicafu/hexagolab/gen/go/user"
+	"github.com/davicafu/hexagolab/pkg/client"
+	"github.com/davicafu/hexagolab/pkg/dataio"
+)
+
+// ctl es el contexto de ejecución de un comando: el cliente y dónde escribir la salida.
+type ctl struct {
+	client *client.Client
+	out    io.Writer
+	errOut io.Writer
+	json   bool
+}
+
+// ctlCommand es una acción sobre un recurso, p. ej. "users list".
+type ctlCommand struct {
+	resource string
+	action   string
+	summary  string
+	run      func(ctx context.Context, c *ctl, args []string) error
+}
+
+var ctlCommands = []ctlCommand{
+	{resource: "users", action: "list", summary: "List users (--email, --name, --limit, --offset)", run: listUsers},
+	{resource: "users", action: "create", summary: "Create a user (--email, --name, --birth-date YYYY-MM-DD)", run: createUser},
+	{resource: "users", action: "delete", summary: "Delete the users with the given ids", run: deleteUsers},
+	{resource: "users", action: "import", summary: "Create a user per row of a CSV or NDJSON file (--file, --format)", run: importUsers},
+	{resource: "tasks", action: "list", summary: "List tasks (--status, --assignee, --limit, --offset)", run: listTasks},
+	{resource: "tasks", action: "create", summary: "Create a task (--title, --description, --assignee)", run: createTask},
+	{resource: "tasks", action: "delete", summary: "Delete the tasks with the given ids", run: deleteTasks},
+	{resource: "tasks", action: "import", summary: "Create a task per row of a CSV or NDJSON file (--file, --format)", run: importTasks},
+	{resource: "outbox", action: "backlog", summary: "Show the events pending publication in each outbox", run: outboxBacklog},
+	{resource: "outbox", action: "dead-letters", summary: "List the dead letters of an outbox with their reasons (--outbox, --limit)", run: listDeadLetters},
+	{resource: "outbox", action: "requeue", summary: "Queue the given dead letters again, or all with --all (--outbox)", run: requeueDeadLetters},
+	{resource: "outbox", action: "purge", summary: "Delete the given dead letters, or all with --all (--outbox)", run: purgeDeadLetters},
+}
+
+// execute busca el comando "<recurso> <acción>" y lo ejecuta con el resto de argumentos.
+func execute(ctx context.Context, c *ctl, args []string) error {
+	for _, cmd := range ctlCommands {
+		if cmd.resource == args[0] && cmd.action == args[1] {
+			return describe(cmd.run(ctx, c, args[2:]))
+		}
+	}
+	return fmt.Errorf("unknown command %q, run 'hexagolabctl --help' for the list", args[0]+" "+args[1])
+}
+
+// describe deja solo el mensaje de los errores gRPC, precedido de su código (NotFound, ...).
+func describe(err error) error {
+	if st, ok := status.FromError(err); ok && err != nil {
+		return fmt.Errorf("%s: %s", st.Code(), st.Message())
+	}
+	return err
+}
+
+// actionFlags crea el FlagSet de una acción; los errores de parseo los imprime el propio FlagSet.
+func (c *ctl) actionFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	return fs
+}
+
+// ---------------- Usuarios ----------------
+
+func listUsers(ctx context.Context, c *ctl, args []string) error {
+	req := &userpb.ListUsersRequest{}
+	fs := c.actionFlags("users list")
+	fs.StringVar(&req.Email, "email", "", "Exact email")
+	nameFlag(fs, &req.Nombre, "Part of the name")
+	limit := fs.Int("limit", 50, "Page size")
+	offset := fs.Int("offset", 0, "Users to skip")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	req.Limit, req.Offset = int32(*limit), int32(*offset)
+
+	resp, err := c.client.Users.ListUsers(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tEMAIL\tNAME\tBIRTH DATE\tCREATED AT")
+		for _, u := range resp.GetUsers() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", u.GetId(), u.GetEmail(), u.GetNombre(), u.GetBirthDate(), formatTime(u.GetCreatedAt()))
+		}
+		printPageFooter(w, len(resp.GetUsers()), resp.GetTotal(), resp.GetHasMore(), *offset)
+	})
+}
+
+func createUser(ctx context.Context, c *ctl, args []string) error {
+	req := &userpb.CreateUserRequest{}
+	fs := c.actionFlags("users create")
+	fs.StringVar(&req.Email, "email", "", "Email (required)")
+	nameFlag(fs, &req.Nombre, "Full name (required)")
+	fs.StringVar(&req.BirthDate, "birth-date", "", "Birth date as YYYY-MM-DD (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	user, err := c.client.Users.CreateUser(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.print(user, func(w io.Writer) {
+		fmt.Fprintf(w, "Created user %s\n", user.GetId())
+	})
+}
+
+// nameFlag declara --name y su alias obsoleto --nombre, que escriben en el mismo campo.
+func nameFlag(fs *flag.FlagSet, value *string, usage string) {
+	fs.StringVar(value, "name", "", usage)
+	fs.StringVar(value, "nombre", "", "Deprecated alias of --name")
+}
+
+func deleteUsers(ctx context.Context, c *ctl, args []string) error {
+	return deleteEach(c, "user", args, func(id string) error {
+		_, err := c.client.Users.DeleteUser(ctx, &userpb.DeleteUserRequest{Id: id})
+		return err
+	})
+}
+
+// importUsers da de alta un usuario por fila, con las columnas de GET /users/export.
+func importUsers(ctx context.Context, c *ctl, args []string) error {
+	return importFile(ctx, c, "users import", args, func(ctx context.Context, rec dataio.Record) error {
+		_, err := c.client.Users.CreateUser(ctx, &userpb.CreateUserRequest{
+			Email:     rec.Get("email"),
+			Nombre:    rec.Get("name", "nombre"),
+			BirthDate: rec.Get("birth_date"),
+		})
+		return describe(err)
+	})
+}
+
+// ---------------- Tareas ----------------
+
+func listTasks(ctx context.Context, c *ctl, args []string) error {
+	req := &taskpb.ListTasksRequest{}
+	fs := c.actionFlags("tasks list")
+	fs.StringVar(&req.Status, "status", "", "pending, completed or failed")
+	fs.StringVar(&req.AssigneeId, "assignee", "", "Assignee user id")
+	limit := fs.Int("limit", 50, "Page size")
+	offset := fs.Int("offset", 0, "Tasks to skip")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	req.Limit, req.Offset = int32(*limit), int32(*offset)
+
+	resp, err := c.client.Tasks.ListTasks(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tASSIGNEE\tCREATED AT")
+		for _, t := range resp.GetTasks() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.GetId(), t.GetTitle(), t.GetStatus(), t.GetAssigneeId(), formatTime(t.GetCreatedAt()))
+		}
+		printPageFooter(w, len(resp.GetTasks()), resp.GetTotal(), resp.GetHasMore(), *offset)
+	})
+}
+
+func createTask(ctx context.Context, c *ctl, args []string) error {
+	req := &taskpb.CreateTaskRequest{}
+	fs := c.actionFlags("tasks create")
+	fs.StringVar(&req.Title, "title", "", "Title (required)")
+	fs.StringVar(&req.Description, "description", "", "Description")
+	fs.StringVar(&req.AssigneeId, "assignee", "", "Assignee user id (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	task, err := c.client.Tasks.CreateTask(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.print(task, func(w io.Writer) {
+		fmt.Fprintf(w, "Created task %s (%s)\n", task.GetId(), task.GetStatus())
+	})
+}
+
+func deleteTasks(ctx context.Context, c *ctl, args []string) error {
+	return deleteEach(c, "task", args, func(id string) error {
+		_, err := c.client.Tasks.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: id})
+		return err
+	})
+}
+
+// importTasks crea una tarea por fila, con las columnas de GET /tasks/export.
+func importTasks(ctx context.Context, c *ctl, args []string) error {
+	return importFile(ctx, c, "tasks import", args, func(ctx context.Context, rec dataio.Record) error {
+		_, err := c.client.Tasks.CreateTask(ctx, &taskpb.CreateTaskRequest{
+			Title:       rec.Get("title"),
+			Description: rec.Fields["description"],
+			AssigneeId:  rec.Get("assignee_id", "assigneeId"),
+		})
+		return describe(err)
+	})
+}
+
+// ---------------- Outbox ----------------
+
+func outboxBacklog(ctx context.Context, c *ctl, _ []string) error {
+	resp, err := c.client.Admin.GetOutboxBacklog(ctx, &adminpb.GetOutboxBacklogRequest{})
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "OUTBOX\tPENDING")
+		for _, o := range resp.GetOutboxes() {
+			fmt.Fprintf(w, "%s\t%d\n", o.GetOutbox(), o.GetPending())
+		}
+	})
+}
+
+func listDeadLetters(ctx context.Context, c *ctl, args []string) error {
+	req := &adminpb.ListDeadLettersRequest{}
+	fs := c.actionFlags("outbox dead-letters")
+	fs.StringVar(&req.Outbox, "outbox", "", "Outbox: user or task (required)")
+	limit := fs.Int("limit", 100, "Dead letters to show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	req.Limit = int32(*limit)
+
+	resp, err := c.client.Admin.ListDeadLetters(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tEVENT TYPE\tAGGREGATE ID\tCREATED AT\tREASON")
+		for _, dl := range resp.GetDeadLetters() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", dl.GetId(), dl.GetEventType(), dl.GetAggregateId(), formatTime(dl.GetCreatedAt()), dl.GetReason())
+		}
+	})
+}
+
+func requeueDeadLetters(ctx context.Context, c *ctl, args []string) error {
+	return changeDeadLetters(ctx, c, "outbox requeue", "Requeued", args, c.client.Admin.RequeueDeadLetters)
+}
+
+func purgeDeadLetters(ctx context.Context, c *ctl, args []string) error {
+	return changeDeadLetters(ctx, c, "outbox purge", "Purged", args, c.client.Admin.PurgeDeadLetters)
+}
+
+// changeDeadLetters envía a 'call' los ids de dead letters que siguen a los flags, o todos los
+// del outbox con --all.
+func changeDeadLetters(ctx context.Context, c *ctl, name, verb string, args []string, call func(context.Context, *adminpb.DeadLettersRequest, ...grpc.CallOption) (*adminpb.DeadLettersResponse, error)) error {
+	req := &adminpb.DeadLettersRequest{}
+	fs := c.actionFlags(name)
+	fs.StringVar(&req.Outbox, "outbox", "", "Outbox: user or task (required)")
+	fs.BoolVar(&req.All, "all", false, "All the dead letters of the outbox instead of the given ids")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	req.Ids = fs.Args()
+
+	resp, err := call(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintf(w, "%s %d dead letters of the %s outbox\n", verb, resp.GetCount(), req.GetOutbox())
+	})
+}
+
+// ---------------- Salida ----------------
+
+// printPageFooter indica, si la página no es la última, cuántos registros hay y con qué --offset
+// se pide la siguiente.
+func printPageFooter(w io.Writer, shown int, total int32, hasMore bool, offset int) {
+	if !hasMore {
+		return
+	}
+	fmt.Fprintf(w, "\nShowing %d of %d; next page: --offset %d\n", shown, total, offset+shown)
+}
+
+// print escribe msg como JSON con --json o, si no, la tabla que genera table.
+func (c *ctl) print(msg proto.Message, table func(w io.Writer)) error {
+	if c.json {
+		b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		// protojson varía los espacios a propósito; json.Indent los normaliza para que la
+		// salida se pueda comparar y procesar con herramientas de texto.
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, b, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		_, err = buf.WriteTo(c.out)
+		return err
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+// deleteEach borra los ids uno a uno y sigue aunque alguno falle; al final informa de los fallos.
+func deleteEach(c *ctl, kind string, ids []string, del func(id string) error) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no %s ids given", kind)
+	}
+	var failed int
+	for _, id := range ids {
+		if err := del(id); err != nil {
+			failed++
+			fmt.Fprintf(c.errOut, "failed to delete %s %s: %v\n", kind, id, describe(err))
+			continue
+		}
+		fmt.Fprintf(c.out, "Deleted %s %s\n", kind, id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %ss could not be deleted", failed, len(ids), kind)
+	}
+	return nil
+}
+
+// importFile lee el fichero de --file, en el formato de --format o, si no se da, el de su
+// extensión, y llama a 'create' con cada fila. Sigue aunque alguna falle y al final informa de
+// cuántas se crearon y del error de cada fila que no.
+func importFile(ctx context.Context, c *ctl, name string, args []string, create func(ctx context.Context, rec dataio.Record) error) error {
+	fs := c.actionFlags(name)
+	path := fs.String("file", "", "CSV or NDJSON file to import (required)")
+	formatName := fs.String("format", "", "csv or ndjson (default: from the file extension)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("--file is required")
+	}
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(*path), ".")
+	}
+	format, err := dataio.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	report, err := dataio.Import(ctx, dataio.NewReader(f, format), create)
+	if err != nil {
+		return fmt.Errorf("import stopped after %d rows: %w", report.Created+report.Failed, err)
+	}
+	if c.json {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, string(b))
+	} else {
+		for _, rowErr := range report.Errors {
+			fmt.Fprintf(c.errOut, "line %d: %s\n", rowErr.Line, rowErr.Reason)
+		}
+		fmt.Fprintf(c.out, "Imported %d of %d rows\n", report.Created, report.Created+report.Failed)
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d rows could not be imported", report.Failed)
+	}
+	return nil
+}
+
+func formatTime(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().Local().Format(time.DateTime)
+}
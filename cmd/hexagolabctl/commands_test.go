@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	adminpb "github.com/davicafu/hexagolab/gen/go/admin"
+	userpb "github.com/davicafu/hexagolab/gen/go/user"
+	"github.com/davicafu/hexagolab/pkg/client"
+)
+
+type fakeAdminServer struct {
+	adminpb.UnimplementedAdminServiceServer
+}
+
+func (fakeAdminServer) GetOutboxBacklog(context.Context, *adminpb.GetOutboxBacklogRequest) (*adminpb.GetOutboxBacklogResponse, error) {
+	return &adminpb.GetOutboxBacklogResponse{Outboxes: []*adminpb.OutboxBacklog{
+		{Outbox: "task", Pending: 3},
+		{Outbox: "user", Pending: 0},
+	}}, nil
+}
+
+func (fakeAdminServer) ListDeadLetters(_ context.Context, req *adminpb.ListDeadLettersRequest) (*adminpb.ListDeadLettersResponse, error) {
+	if req.GetOutbox() != "task" {
+		return nil, status.Errorf(codes.NotFound, "unknown outbox %q", req.GetOutbox())
+	}
+	return &adminpb.ListDeadLettersResponse{DeadLetters: []*adminpb.DeadLetter{
+		{Id: "e1", EventType: "task.created", AggregateId: "t1", Reason: "schema violation"},
+	}}, nil
+}
+
+func (fakeAdminServer) RequeueDeadLetters(_ context.Context, req *adminpb.DeadLettersRequest) (*adminpb.DeadLettersResponse, error) {
+	if len(req.GetIds()) == 0 && !req.GetAll() {
+		return nil, status.Error(codes.InvalidArgument, "ids are required unless all is true")
+	}
+	return &adminpb.DeadLettersResponse{Count: int64(len(req.GetIds()))}, nil
+}
+
+type fakeUserServer struct {
+	userpb.UnimplementedUserServiceServer
+	deleted []string
+	created []*userpb.CreateUserRequest
+}
+
+func (s *fakeUserServer) CreateUser(_ context.Context, req *userpb.CreateUserRequest) (*userpb.User, error) {
+	if req.GetBirthDate() == "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid birth_date format, use YYYY-MM-DD")
+	}
+	s.created = append(s.created, req)
+	return &userpb.User{Id: "u" + req.GetEmail()}, nil
+}
+
+func (s *fakeUserServer) ListUsers(_ context.Context, req *userpb.ListUsersRequest) (*userpb.ListUsersResponse, error) {
+	return &userpb.ListUsersResponse{
+		Users:   []*userpb.User{{Id: "u1", Email: "ana@example.com", Nombre: "Ana", BirthDate: "1990-05-17"}},
+		HasMore: req.GetLimit() < 3,
+		Total:   3,
+	}, nil
+}
+
+func (s *fakeUserServer) DeleteUser(_ context.Context, req *userpb.DeleteUserRequest) (*userpb.DeleteUserResponse, error) {
+	if req.GetId() == "missing" {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	s.deleted = append(s.deleted, req.GetId())
+	return &userpb.DeleteUserResponse{}, nil
+}
+
+// newTestCtl sirve los fakes en memoria (bufconn) y devuelve un ctl conectado a ellos.
+func newTestCtl(t *testing.T, users *fakeUserServer, asJSON bool) (*ctl, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	adminpb.RegisterAdminServiceServer(srv, fakeAdminServer{})
+	userpb.RegisterUserServiceServer(srv, users)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	c, err := client.New("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+
+	var out, errOut bytes.Buffer
+	return &ctl{client: c, out: &out, errOut: &errOut, json: asJSON}, &out, &errOut
+}
+
+func TestOutboxBacklog_PrintsTable(t *testing.T) {
+	// Arrange
+	c, out, _ := newTestCtl(t, &fakeUserServer{}, false)
+
+	// Act
+	err := execute(context.Background(), c, []string{"outbox", "backlog"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "OUTBOX  PENDING\ntask    3\nuser    0\n", out.String())
+}
+
+func TestOutboxBacklog_PrintsJSON(t *testing.T) {
+	// Arrange
+	c, out, _ := newTestCtl(t, &fakeUserServer{}, true)
+
+	// Act
+	err := execute(context.Background(), c, []string{"outbox", "backlog"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `"outbox": "task"`)
+	assert.Contains(t, out.String(), `"pending": "3"`) // protojson codifica los int64 como string
+}
+
+func TestOutboxDeadLetters_PrintsReasons(t *testing.T) {
+	// Arrange
+	c, out, _ := newTestCtl(t, &fakeUserServer{}, false)
+
+	// Act
+	err := execute(context.Background(), c, []string{"outbox", "dead-letters", "--outbox", "task"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "REASON")
+	assert.Contains(t, out.String(), "task.created")
+	assert.Contains(t, out.String(), "schema violation")
+	assert.EqualError(t, execute(context.Background(), c, []string{"outbox", "dead-letters", "--outbox", "billing"}), `NotFound: unknown outbox "billing"`)
+}
+
+func TestOutboxRequeue_SendsTheGivenIDs(t *testing.T) {
+	// Arrange
+	c, out, _ := newTestCtl(t, &fakeUserServer{}, false)
+
+	// Act
+	err := execute(context.Background(), c, []string{"outbox", "requeue", "--outbox", "task", "e1", "e2"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Requeued 2 dead letters of the task outbox\n", out.String())
+	assert.EqualError(t, execute(context.Background(), c, []string{"outbox", "requeue", "--outbox", "task"}), "InvalidArgument: ids are required unless all is true")
+}
+
+func TestListUsers_PrintsNextPageHint(t *testing.T) {
+	// Arrange
+	c, out, _ := newTestCtl(t, &fakeUserServer{}, false)
+
+	// Act
+	err := execute(context.Background(), c, []string{"users", "list", "--limit", "1", "--offset", "1"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "ana@example.com")
+	assert.Contains(t, out.String(), "Showing 1 of 3; next page: --offset 2\n")
+}
+
+func TestListUsers_LastPageHasNoHint(t *testing.T) {
+	// Arrange
+	c, out, _ := newTestCtl(t, &fakeUserServer{}, false)
+
+	// Act
+	err := execute(context.Background(), c, []string{"users", "list", "--limit", "3"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotContains(t, out.String(), "next page")
+}
+
+func TestDeleteUsers_ContinuesAfterFailures(t *testing.T) {
+	// Arrange
+	users := &fakeUserServer{}
+	c, out, errOut := newTestCtl(t, users, false)
+
+	// Act
+	err := execute(context.Background(), c, []string{"users", "delete", "a", "missing", "b"})
+
+	// Assert
+	require.EqualError(t, err, "1 of 3 users could not be deleted")
+	assert.Equal(t, []string{"a", "b"}, users.deleted)
+	assert.Equal(t, "Deleted user a\nDeleted user b\n", out.String())
+	assert.Equal(t, "failed to delete user missing: NotFound: user not found\n", errOut.String())
+}
+
+func TestImportUsers_ReportsFailedRows(t *testing.T) {
+	// Arrange
+	users := &fakeUserServer{}
+	c, out, errOut := newTestCtl(t, users, false)
+	path := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(path, []byte("email,name,birth_date\nana@example.com,Ana,1990-05-17\nluis@example.com,Luis,\n"), 0o600))
+
+	// Act
+	err := execute(context.Background(), c, []string{"users", "import", "--file", path})
+
+	// Assert
+	require.EqualError(t, err, "1 rows could not be imported")
+	require.Len(t, users.created, 1)
+	assert.Equal(t, "Ana", users.created[0].GetNombre())
+	assert.Equal(t, "Imported 1 of 2 rows\n", out.String())
+	assert.Equal(t, "line 3: InvalidArgument: invalid birth_date format, use YYYY-MM-DD\n", errOut.String())
+}
+
+func TestExecute_UnknownCommand(t *testing.T) {
+	c, _, _ := newTestCtl(t, &fakeUserServer{}, false)
+
+	err := execute(context.Background(), c, []string{"users", "rename"})
+
+	assert.ErrorContains(t, err, `unknown command "users rename"`)
+}
@@ -0,0 +1,77 @@
+// hexagolabctl es la CLI de operación de hexagolab: habla con la API gRPC (pkg/client) para
+// gestionar usuarios y tareas e inspeccionar el outbox sin tener que montar peticiones a mano.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/davicafu/hexagolab/pkg/client"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "hexagolabctl:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run interpreta los flags globales, se conecta al servidor y ejecuta el comando pedido.
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("hexagolabctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", envOr("HEXAGOLAB_GRPC_ADDR", client.DefaultAddr), "gRPC server address (env HEXAGOLAB_GRPC_ADDR)")
+	timeout := fs.Duration("timeout", 10*time.Second, "Timeout for each call")
+	asJSON := fs.Bool("json", false, "Print the responses as JSON instead of a table")
+	token := fs.String("token", os.Getenv("HEXAGOLAB_TOKEN"), "Bearer token of a user with auth.admin_role, required by outbox requeue and purge (env HEXAGOLAB_TOKEN)")
+	fs.Usage = func() { printUsage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	c, err := client.New(*addr)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if *token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+	}
+	return execute(ctx, &ctl{client: c, out: stdout, errOut: stderr, json: *asJSON}, fs.Args())
+}
+
+func printUsage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "Usage: hexagolabctl [flags] <resource> <action> [args]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	for _, cmd := range ctlCommands {
+		fmt.Fprintf(out, "  %-16s %s\n", cmd.resource+" "+cmd.action, cmd.summary)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Flags:")
+	fs.PrintDefaults()
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
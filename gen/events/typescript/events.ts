@@ -0,0 +1,85 @@
+// Code generated by eventgen. DO NOT EDIT.
+
+/** Identificador UUID en su forma canónica (8-4-4-4-12). */
+export type UUID = string;
+
+/** Fecha y hora en RFC 3339. */
+export type DateTime = string;
+
+/** AuthFailed es el payload de auth.failed: una petición rechazada por no traer un token válido. */
+export interface AuthFailed {
+  /** Plantilla de la ruta (/users/:id) o, si no hay, la ruta pedida */
+  route: string;
+  method: string;
+  /** missing_token o invalid_token */
+  reason: string;
+  detail?: string;
+  client_ip: string;
+}
+
+/** AuthzDenied es el payload de authz.denied: un usuario autenticado sin permiso para la ruta. */
+export interface AuthzDenied {
+  route: string;
+  method: string;
+  /** missing_role */
+  reason: string;
+  required_role: string;
+  client_ip: string;
+}
+
+/**
+ * Base de todos los eventos de integración. Además del tipo y el payload, el sobre identifica el
+ * evento y la cadena causal que lo produjo, para que consumidores y auditoría la reconstruyan:
+ * CorrelationID es la petición de origen de toda la cadena, CausationID el evento o la petición
+ * que provocó directamente este y Actor quién hizo la petición de origen. TenantID es el
+ * inquilino al que pertenece el evento (vacío, el inquilino por defecto). PayloadMode es la forma
+ * de Data en las altas y actualizaciones; vacío, la entidad completa.
+ */
+export interface IntegrationEvent {
+  event_id: UUID;
+  type: string;
+  /** cuándo se publicó */
+  timestamp: DateTime;
+  /** cuándo ocurrió (su alta en el outbox) */
+  occurred_at: DateTime;
+  correlation_id?: string;
+  causation_id?: string;
+  actor?: string;
+  tenant_id?: string;
+  payload_mode?: string;
+  /** contenido específico del evento */
+  data: unknown;
+}
+
+export interface TaskCreated {
+  id: UUID;
+  title: string;
+  description: string;
+  /** AssigneeID es null si la tarea se crea sin asignar. */
+  assigneeId: UUID | null;
+}
+
+export interface TaskUpdated {
+  id: UUID;
+  title: string;
+  description: string;
+  status: string;
+}
+
+/**
+ * Estos son contratos de integración, NO entidades del dominio
+ * Se definen planos para intercambio entre contextos.
+ */
+export interface UserCreated {
+  id: UUID;
+  email: string;
+  nombre: string;
+  birth_date: DateTime;
+}
+
+export interface UserUpdated {
+  id: UUID;
+  email: string;
+  nombre: string;
+  birth_date: DateTime;
+}
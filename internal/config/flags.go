@@ -0,0 +1,110 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// ErrHelp se devuelve desde Load cuando se pide la ayuda (-h, --help); la ayuda ya se ha impreso.
+var ErrHelp = flag.ErrHelp
+
+// flagAliases son atajos para las opciones que más se tocan al lanzar el binario.
+var flagAliases = map[string]string{
+	"env":       "app.env",
+	"demo":      "app.demo",
+	"port":      "http.port",
+	"db-path":   "db.sqlite.path",
+	"brokers":   "bus.kafka.brokers",
+	"log-level": "log.level",
+}
+
+// settingFlag guarda el valor de un flag sin interpretarlo; se aplica con setting.set
+// para que flags, entorno y fichero compartan el mismo parseo.
+type settingFlag struct {
+	value  string
+	seen   bool
+	isBool bool
+}
+
+func (f *settingFlag) String() string { return f.value }
+
+func (f *settingFlag) Set(v string) error {
+	f.value, f.seen = v, true
+	return nil
+}
+
+// IsBoolFlag permite escribir --bus.kafka.enabled sin valor, como con los flags booleanos de la stdlib.
+func (f *settingFlag) IsBoolFlag() bool { return f.isBool }
+
+// newFlagSet registra --config, un flag por opción (con su clave como nombre) y los atajos.
+func newFlagSet() (*flag.FlagSet, *string, map[string]*settingFlag) {
+	fs := flag.NewFlagSet("hexagolab", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv("CONFIG_FILE"), "Path to a YAML or TOML config file")
+
+	values := make(map[string]*settingFlag, len(settings))
+	for _, s := range settings {
+		values[s.key] = &settingFlag{isBool: s.kind == "bool"}
+		fs.Var(values[s.key], s.key, s.usage)
+	}
+	for alias, key := range flagAliases {
+		fs.Var(values[key], alias, "Shortcut for --"+key)
+	}
+
+	fs.Usage = func() { printUsage(fs.Output()) }
+	return fs, configPath, values
+}
+
+// printUsage escribe la ayuda de --help: todas las opciones agrupadas por sección, con su
+// variable de entorno y su valor por defecto (el del perfil dev).
+func printUsage(w io.Writer) {
+	defaults := Default()
+	_ = applyProfile(defaults, "dev")
+
+	fmt.Fprintln(w, "Usage: hexagolab [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Each option can be set, from lowest to highest precedence, by the profile (APP_ENV),")
+	fmt.Fprintln(w, "the config file, its environment variable or its flag. Defaults are those of the dev profile.")
+	fmt.Fprintln(w, "(*) can be reloaded without restarting; (secret) accepts vault:// and awssm:// references.")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "  --config string\tPath to a YAML or TOML config file\t(env CONFIG_FILE)")
+
+	section := ""
+	for _, s := range settings {
+		if name, _, _ := strings.Cut(s.key, "."); name != section {
+			section = name
+			fmt.Fprintf(tw, "\n%s:\n", section)
+		}
+
+		usage := s.usage
+		if s.reloadable {
+			usage += " (*)"
+		}
+		if s.secret {
+			usage += " (secret)"
+		}
+		details := "(env " + s.env
+		if def := s.get(defaults); def != "" {
+			details += fmt.Sprintf(", default %q", def)
+		}
+		details += ")"
+		fmt.Fprintf(tw, "  --%s %s\t%s\t%s\n", s.key, s.kind, usage, details)
+	}
+
+	aliases := make([]string, 0, len(flagAliases))
+	for alias := range flagAliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	fmt.Fprintln(tw, "\nshortcuts:")
+	for _, alias := range aliases {
+		fmt.Fprintf(tw, "  --%s\tSame as --%s\t\n", alias, flagAliases[alias])
+	}
+	tw.Flush()
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_FlagShortcuts(t *testing.T) {
+	// Act
+	cfg, err := Load([]string{
+		"--port", "9090",
+		"--db-path", "/tmp/hexagolab.db",
+		"--brokers", "kafka-1:9092,kafka-2:9092",
+		"--log-level", "warn",
+		"--bus.kafka.enabled", // Los booleanos no necesitan valor
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.HTTP.Port)
+	assert.Equal(t, "/tmp/hexagolab.db", cfg.DB.SQLite.Path)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Bus.Kafka.Brokers)
+	assert.Equal(t, "warn", cfg.Log.Level)
+	assert.True(t, cfg.Bus.Kafka.Enabled)
+}
+
+func TestLoad_Help(t *testing.T) {
+	_, err := Load([]string{"--help"})
+
+	assert.ErrorIs(t, err, ErrHelp)
+}
+
+func TestPrintUsage_DescribesEverySetting(t *testing.T) {
+	// Act
+	var buf bytes.Buffer
+	printUsage(&buf)
+
+	// Assert
+	out := buf.String()
+	for _, s := range settings {
+		assert.Contains(t, out, "--"+s.key+" "+s.kind)
+		assert.Contains(t, out, "env "+s.env)
+	}
+	for alias, key := range flagAliases {
+		assert.Contains(t, out, "Same as --"+key, alias)
+	}
+	assert.Contains(t, out, `default "debug"`, "Los valores por defecto son los del perfil dev")
+}
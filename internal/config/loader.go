@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Load construye la configuración por capas, de menor a mayor precedencia:
+//
+//	valores por defecto < perfil (APP_ENV) < fichero (--config o CONFIG_FILE) < variables de entorno < flags
+//
+// 'args' son los argumentos de línea de comandos sin el nombre del binario (os.Args[1:]).
+// Con app.demo activo, los valores del modo demo se imponen a todas las capas (ver demoValues).
+// Las opciones que lo admiten pueden referenciar un secreto (ver resolveSecrets).
+// El resultado se valida antes de devolverlo (ver Config.Validate).
+func Load(args []string) (*Config, error) {
+	fs, configPath, flagValues := newFlagSet()
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var fileValues map[string]string
+	if *configPath != "" {
+		values, err := readFile(*configPath)
+		if err != nil {
+			return nil, err
+		}
+		fileValues = values
+	}
+
+	// El perfil se aplica antes que el resto de capas, así que hay que resolverlo primero
+	// con su propia precedencia: flag > entorno > fichero > dev.
+	env := "dev"
+	if v, ok := fileValues["app.env"]; ok {
+		env = v
+	}
+	if v := os.Getenv("APP_ENV"); v != "" {
+		env = v
+	}
+	if f := flagValues["app.env"]; f.seen {
+		env = f.value
+	}
+
+	cfg := Default()
+	if err := applyProfile(cfg, env); err != nil {
+		return nil, err
+	}
+	if err := applyValues(cfg, fileValues, *configPath); err != nil {
+		return nil, err
+	}
+
+	for _, s := range settings {
+		if v, ok := s.lookupEnv(); ok {
+			if err := s.set(cfg, v); err != nil {
+				return nil, fmt.Errorf("env %s: %w", s.env, err)
+			}
+		}
+	}
+
+	// Solo se aplican los flags pasados explícitamente; el resto no pisa las capas anteriores.
+	for _, s := range settings {
+		if f := flagValues[s.key]; f.seen {
+			if err := s.set(cfg, f.value); err != nil {
+				return nil, fmt.Errorf("flag --%s: %w", s.key, err)
+			}
+		}
+	}
+
+	if cfg.App.Demo {
+		if err := applyValues(cfg, demoValues, "demo mode"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := resolveSecrets(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readFile lee un fichero YAML o TOML (según su extensión) y devuelve sus valores.
+// Las secciones anidadas se aplanan a claves con puntos: [outbox] period = "5s" -> outbox.period.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flatten("", raw, values)
+	return values, nil
+}
+
+// applyValues aplica sobre cfg un conjunto de valores por clave. 'source' identifica
+// su origen (fichero o perfil) en los mensajes de error.
+func applyValues(cfg *Config, values map[string]string, source string) error {
+	// Orden estable para que los errores sean reproducibles.
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s, ok := findSetting(k)
+		if !ok {
+			return fmt.Errorf("%s: unknown config key %q", source, k)
+		}
+		if err := s.set(cfg, values[k]); err != nil {
+			return fmt.Errorf("%s: %s: %w", source, k, err)
+		}
+	}
+	return nil
+}
+
+// flatten convierte un mapa anidado en claves con puntos y valores en texto.
+// Las listas se unen con comas, igual que en las variables de entorno.
+func flatten(prefix string, in map[string]any, out map[string]string) {
+	for k, v := range in {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]any:
+			flatten(key, val, out)
+		case []any:
+			items := make([]string, len(val))
+			for i, item := range val {
+				items[i] = fmt.Sprint(item)
+			}
+			out[key] = strings.Join(items, ",")
+		default:
+			out[key] = fmt.Sprint(val)
+		}
+	}
+}
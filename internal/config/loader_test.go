@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	// Act
+	cfg, err := Load(nil)
+
+	// Assert: sin APP_ENV se usa el perfil dev.
+	require.NoError(t, err)
+	want := Default()
+	want.Log.Level = "debug"
+	want.DB.Task = "sqlite"
+	assert.Equal(t, want, cfg)
+}
+
+func TestLoad_Profiles(t *testing.T) {
+	t.Run("prod profile", func(t *testing.T) {
+		// Arrange
+		t.Setenv("APP_ENV", "prod")
+
+		// Act
+		cfg, err := Load(nil)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "prod", cfg.App.Env)
+		assert.True(t, cfg.Bus.Kafka.Enabled)
+		assert.Equal(t, "postgres", cfg.DB.User)
+		assert.Equal(t, "postgres", cfg.DB.Task)
+		assert.Equal(t, "info", cfg.Log.Level)
+	})
+
+	t.Run("profile from file, overridden by env", func(t *testing.T) {
+		// Arrange: el fichero elige staging y ajusta una opción del perfil; el entorno pisa otra.
+		path := writeConfigFile(t, "hexagolab.yaml", `
+app:
+  env: staging
+db:
+  task: mongo
+`)
+		t.Setenv("USE_KAFKA", "false")
+
+		// Act
+		cfg, err := Load([]string{"--config", path})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "staging", cfg.App.Env)
+		assert.Equal(t, "postgres", cfg.DB.User, "Valor del perfil")
+		assert.Equal(t, "mongo", cfg.DB.Task, "El fichero pisa al perfil")
+		assert.False(t, cfg.Bus.Kafka.Enabled, "El entorno pisa al perfil")
+	})
+
+	t.Run("flag wins over APP_ENV", func(t *testing.T) {
+		// Arrange
+		t.Setenv("APP_ENV", "prod")
+
+		// Act
+		cfg, err := Load([]string{"--app.env", "dev"})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "dev", cfg.App.Env)
+		assert.False(t, cfg.Bus.Kafka.Enabled)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		t.Setenv("APP_ENV", "qa")
+		_, err := Load(nil)
+		assert.ErrorContains(t, err, `unknown environment profile "qa"`)
+	})
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	// Arrange: el fichero define los tres valores, el entorno pisa dos y el flag uno.
+	path := writeConfigFile(t, "hexagolab.yaml", `
+http:
+  port: "7000"
+outbox:
+  period: 10s
+  limit: 50
+bus:
+  kafka:
+    brokers: [kafka-1:9092, kafka-2:9092]
+`)
+	t.Setenv("OUTBOX_PERIOD", "20s")
+	t.Setenv("HTTP_PORT", "7001")
+
+	// Act
+	cfg, err := Load([]string{"--config", path, "--http.port", "7002"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "7002", cfg.HTTP.Port, "Los flags tienen la mayor precedencia")
+	assert.Equal(t, 20*time.Second, cfg.Outbox.Period, "El entorno pisa al fichero")
+	assert.Equal(t, 50, cfg.Outbox.Limit, "El fichero pisa a los valores por defecto")
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Bus.Kafka.Brokers)
+}
+
+func TestLoad_Demo(t *testing.T) {
+	// Arrange: el modo demo se impone incluso a lo que pidan explícitamente el entorno y los flags.
+	t.Setenv("APP_ENV", "prod")
+	t.Setenv("REDIS_ADDR", "redis:6379")
+
+	// Act
+	cfg, err := Load([]string{"--demo", "--db.task", "postgres", "--port", "7000"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, cfg.App.Demo)
+	assert.Equal(t, "memory", cfg.DB.User)
+	assert.Equal(t, "memory", cfg.DB.Task)
+	assert.False(t, cfg.Bus.Kafka.Enabled)
+	assert.Empty(t, cfg.Cache.Redis.Addr)
+	assert.Empty(t, cfg.Analytics.Backend)
+	assert.Equal(t, "7000", cfg.HTTP.Port, "Las opciones que no toca el modo demo se respetan")
+}
+
+func TestLoad_TOMLFile(t *testing.T) {
+	// Arrange
+	path := writeConfigFile(t, "hexagolab.toml", `
+[cache]
+ttl = "90s"
+
+[analytics.clickhouse]
+addr = "clickhouse:9000"
+dial_timeout = 3
+async_insert = true
+`)
+	t.Setenv("CONFIG_FILE", path)
+
+	// Act
+	cfg, err := Load(nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, cfg.Cache.TTL)
+	assert.Equal(t, "clickhouse:9000", cfg.Analytics.ClickHouse.Addr)
+	assert.Equal(t, 3*time.Second, cfg.Analytics.ClickHouse.DialTimeout, "Los enteros se interpretan como segundos")
+	assert.True(t, cfg.Analytics.ClickHouse.AsyncInsert)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Run("unknown key", func(t *testing.T) {
+		path := writeConfigFile(t, "hexagolab.yaml", "outbox:\n  perod: 5s\n")
+		_, err := Load([]string{"--config", path})
+		assert.ErrorContains(t, err, `unknown config key "outbox.perod"`)
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		t.Setenv("OUTBOX_PERIOD", "soon")
+		_, err := Load(nil)
+		assert.ErrorContains(t, err, "OUTBOX_PERIOD")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		path := writeConfigFile(t, "hexagolab.json", "{}")
+		_, err := Load([]string{"--config", path})
+		assert.ErrorContains(t, err, "unsupported config file format")
+	})
+}
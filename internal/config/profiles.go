@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// profiles son los valores base de cada entorno, expresados con las mismas claves que el fichero.
+// Se aplican sobre Default y por debajo del fichero, el entorno y los flags, de modo que
+// APP_ENV basta para arrancar y cualquier opción se puede seguir ajustando por separado.
+var profiles = map[string]map[string]string{
+	// dev: todo en local, sin servicios externos (SQLite + bus en memoria).
+	"dev": {
+		"log.level":         "debug",
+		"db.user":           "sqlite",
+		"db.task":           "sqlite",
+		"bus.kafka.enabled": "false",
+	},
+	// staging y prod: Postgres + Kafka.
+	"staging": {
+		"log.level":         "info",
+		"db.user":           "postgres",
+		"db.task":           "postgres",
+		"bus.kafka.enabled": "true",
+	},
+	"prod": {
+		"log.level":         "info",
+		"db.user":           "postgres",
+		"db.task":           "postgres",
+		"bus.kafka.enabled": "true",
+	},
+}
+
+// demoValues se imponen sobre todas las capas cuando app.demo está activo: todo vive en el proceso
+// (repositorios, bus y caché en memoria) y la analítica se deshabilita, así que no hace falta
+// ningún fichero ni servicio externo. El resto de opciones (puertos, componentes...) se respetan.
+var demoValues = map[string]string{
+	"db.user":           "memory",
+	"db.task":           "memory",
+	"bus.kafka.enabled": "false",
+	"cache.redis.addr":  "",
+	"analytics.backend": "",
+}
+
+// applyProfile aplica sobre cfg los valores base del perfil 'name'.
+func applyProfile(cfg *Config, name string) error {
+	values, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown environment profile %q (use one of: %v)", name, profileNames())
+	}
+	cfg.App.Env = name
+	return applyValues(cfg, values, "profile "+name)
+}
+
+// profileNames devuelve los perfiles disponibles en orden alfabético.
+func profileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
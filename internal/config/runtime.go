@@ -0,0 +1,87 @@
+package config
+
+import (
+	"sync"
+)
+
+// ReloadResult resume el efecto de una recarga de configuración.
+type ReloadResult struct {
+	// Applied son las claves recargables que han cambiado y ya se han aplicado.
+	Applied []string `json:"applied"`
+	// RequiresRestart son las claves que han cambiado pero solo se leen al arrancar.
+	RequiresRestart []string `json:"requires_restart"`
+}
+
+// Runtime mantiene la configuración en vigor y permite recargarla en caliente.
+// Solo se aplican las opciones marcadas como recargables; el resto se ignora hasta el próximo arranque.
+type Runtime struct {
+	args []string
+
+	mu        sync.Mutex
+	current   *Config
+	listeners []func(*Config)
+}
+
+// NewRuntime es el constructor. 'args' son los mismos argumentos con los que se llamó a Load,
+// para que la recarga respete los flags de la invocación original.
+func NewRuntime(cfg *Config, args []string) *Runtime {
+	return &Runtime{args: args, current: cfg}
+}
+
+// Current devuelve una copia de la configuración en vigor.
+func (r *Runtime) Current() Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.current
+}
+
+// OnReload registra una función que se invoca con la nueva configuración tras cada recarga con cambios.
+func (r *Runtime) OnReload(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload vuelve a cargar la configuración (fichero, entorno y flags) y aplica los cambios recargables.
+// Si la nueva configuración no es válida se devuelve el error y se mantiene la actual.
+// Los secretos se vuelven a pedir a su backend, de modo que una recarga recoge los rotados.
+func (r *Runtime) Reload() (ReloadResult, error) {
+	secretCache.Purge()
+	loaded, err := Load(r.args)
+	if err != nil {
+		return ReloadResult{}, err
+	}
+
+	r.mu.Lock()
+	next := *r.current
+	result := ReloadResult{Applied: []string{}, RequiresRestart: []string{}}
+	for _, s := range settings {
+		v := s.get(loaded)
+		if v == s.get(r.current) {
+			continue
+		}
+		if !s.reloadable {
+			result.RequiresRestart = append(result.RequiresRestart, s.key)
+			continue
+		}
+		if err := s.set(&next, v); err != nil {
+			r.mu.Unlock()
+			return ReloadResult{}, err
+		}
+		result.Applied = append(result.Applied, s.key)
+	}
+
+	if len(result.Applied) == 0 {
+		r.mu.Unlock()
+		return result, nil
+	}
+	r.current = &next
+	listeners := append([]func(*Config){}, r.listeners...)
+	r.mu.Unlock()
+
+	// Los listeners se llaman fuera del lock para que puedan consultar Current().
+	for _, fn := range listeners {
+		fn(&next)
+	}
+	return result, nil
+}
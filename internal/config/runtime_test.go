@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntime_Reload(t *testing.T) {
+	// Arrange
+	path := writeConfigFile(t, "hexagolab.yaml", "outbox:\n  period: 2s\nhttp:\n  port: \"8080\"\n")
+	args := []string{"--config", path}
+	cfg, err := Load(args)
+	require.NoError(t, err)
+
+	rt := NewRuntime(cfg, args)
+	var notified *Config
+	rt.OnReload(func(c *Config) { notified = c })
+
+	// Act: se cambia un ajuste recargable y otro que requiere reinicio
+	require.NoError(t, os.WriteFile(path, []byte("outbox:\n  period: 7s\nhttp:\n  port: \"9090\"\n"), 0o600))
+	result, err := rt.Reload()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outbox.period"}, result.Applied)
+	assert.Equal(t, []string{"http.port"}, result.RequiresRestart)
+	require.NotNil(t, notified)
+	assert.Equal(t, 7*time.Second, notified.Outbox.Period)
+	assert.Equal(t, "8080", rt.Current().HTTP.Port, "Los ajustes no recargables mantienen su valor hasta reiniciar")
+}
+
+func TestRuntime_Reload_InvalidConfigKeepsCurrent(t *testing.T) {
+	// Arrange
+	path := writeConfigFile(t, "hexagolab.yaml", "outbox:\n  limit: 10\n")
+	args := []string{"--config", path}
+	cfg, err := Load(args)
+	require.NoError(t, err)
+	rt := NewRuntime(cfg, args)
+
+	// Act
+	require.NoError(t, os.WriteFile(path, []byte("outbox:\n  limit: 0\n"), 0o600))
+	_, err = rt.Reload()
+
+	// Assert
+	assert.ErrorContains(t, err, "outbox.limit")
+	assert.Equal(t, 10, rt.Current().Outbox.Limit)
+}
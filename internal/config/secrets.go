@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/secrets"
+)
+
+// Esquemas de referencia a secretos admitidos en los valores de configuración.
+const (
+	schemeVault = "vault"
+	schemeAWS   = "awssm"
+)
+
+// secretCache se comparte entre cargas para no pedir de nuevo al backend secretos aún vigentes.
+var secretCache = secrets.NewCache(5 * time.Minute)
+
+// resolveSecrets sustituye las referencias a secretos por su valor en las opciones que lo admiten
+// (DSNs, credenciales...). Por ejemplo, POSTGRES_DSN=vault://secret/hexagolab/db#dsn.
+// Solo se crea el cliente de los backends que realmente se usan.
+func resolveSecrets(cfg *Config) error {
+	var pending []setting
+	used := map[string]bool{}
+	for _, s := range settings {
+		if !s.secret {
+			continue
+		}
+		if ref, ok := secrets.ParseReference(s.get(cfg), schemeVault, schemeAWS); ok {
+			pending = append(pending, s)
+			used[ref.Scheme] = true
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	secretCache.SetTTL(cfg.Secrets.CacheTTL)
+	resolver := secrets.NewResolver(secretCache)
+	if used[schemeVault] {
+		p, err := secrets.NewVaultProvider(cfg.Secrets.Vault.Addr, cfg.Secrets.Vault.Token)
+		if err != nil {
+			return err
+		}
+		resolver.Register(schemeVault, p)
+	}
+	if used[schemeAWS] {
+		p, err := secrets.NewAWSSecretsManagerProvider(ctx, cfg.Secrets.AWS.Region)
+		if err != nil {
+			return err
+		}
+		resolver.Register(schemeAWS, p)
+	}
+
+	for _, s := range pending {
+		v, err := resolver.Resolve(ctx, s.get(cfg))
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.key, err)
+		}
+		if err := s.set(cfg, v); err != nil {
+			return fmt.Errorf("%s: %w", s.key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting describe una opción configurable: su clave en el fichero (y nombre del flag),
+// su variable de entorno y cómo se lee y escribe sobre el Config.
+type setting struct {
+	key   string // Clave con puntos, p.ej. "outbox.period"
+	env   string
+	usage string
+	kind  string // Tipo del valor, tal y como se muestra en --help
+	def   string // Valor por defecto, en el mismo formato que el entorno
+	set   func(c *Config, v string) error
+	get   func(c *Config) string
+	// reloadable indica que el valor se puede cambiar en caliente (ver Runtime.Reload).
+	reloadable bool
+	// secret indica que el valor puede ser una referencia a un gestor de secretos (ver resolveSecrets).
+	secret bool
+}
+
+func (s setting) lookupEnv() (string, bool) {
+	if s.env == "" {
+		return "", false
+	}
+	v := os.Getenv(s.env)
+	return v, v != ""
+}
+
+// settings es el catálogo de opciones, generado a partir de las etiquetas de Config.
+// Es la única fuente de verdad para el fichero, el entorno y los flags, así que una opción
+// nueva solo hay que añadirla como campo etiquetado en su sección.
+var settings = collectSettings(reflect.TypeOf(Config{}), "", nil)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// collectSettings recorre las secciones de Config y crea un setting por cada campo hoja.
+// 'index' es el camino de índices desde Config hasta el struct actual.
+func collectSettings(t reflect.Type, prefix string, index []int) []setting {
+	var out []setting
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := f.Tag.Get("key")
+		if key == "" {
+			panic(fmt.Sprintf("config: field %s.%s has no key tag", t.Name(), f.Name))
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		path := append(append([]int{}, index...), i)
+
+		if f.Type.Kind() == reflect.Struct {
+			out = append(out, collectSettings(f.Type, key, path)...)
+			continue
+		}
+		out = append(out, newSetting(f, key, path))
+	}
+	return out
+}
+
+func newSetting(f reflect.StructField, key string, path []int) setting {
+	parse, format, kind := codecFor(f.Type)
+	if parse == nil {
+		panic(fmt.Sprintf("config: unsupported type %s for %s", f.Type, key))
+	}
+	field := func(c *Config) reflect.Value { return reflect.ValueOf(c).Elem().FieldByIndex(path) }
+
+	return setting{
+		key:        key,
+		env:        f.Tag.Get("envconfig"),
+		usage:      f.Tag.Get("desc"),
+		kind:       kind,
+		def:        f.Tag.Get("default"),
+		reloadable: f.Tag.Get("reload") == "true",
+		secret:     f.Tag.Get("secret") == "true",
+		set: func(c *Config, v string) error {
+			parsed, err := parse(v)
+			if err != nil {
+				return err
+			}
+			field(c).Set(parsed)
+			return nil
+		},
+		get: func(c *Config) string { return format(field(c)) },
+	}
+}
+
+// codecFor devuelve cómo parsear y formatear los valores de un tipo de campo soportado.
+func codecFor(t reflect.Type) (parse func(string) (reflect.Value, error), format func(reflect.Value) string, kind string) {
+	switch {
+	case t == durationType:
+		return parseDuration, func(v reflect.Value) string { return time.Duration(v.Int()).String() }, "duration"
+	case t.Kind() == reflect.String:
+		return func(v string) (reflect.Value, error) { return reflect.ValueOf(v), nil },
+			func(v reflect.Value) string { return v.String() }, "string"
+	case t.Kind() == reflect.Int:
+		return func(v string) (reflect.Value, error) {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("invalid integer %q", v)
+			}
+			return reflect.ValueOf(n), nil
+		}, func(v reflect.Value) string { return strconv.FormatInt(v.Int(), 10) }, "int"
+	case t.Kind() == reflect.Bool:
+		return func(v string) (reflect.Value, error) {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("invalid boolean %q", v)
+			}
+			return reflect.ValueOf(b), nil
+		}, func(v reflect.Value) string { return strconv.FormatBool(v.Bool()) }, "bool"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String:
+		return parseList, func(v reflect.Value) string { return strings.Join(v.Interface().([]string), ",") }, "list"
+	default:
+		return nil, nil, ""
+	}
+}
+
+// parseDuration acepta duraciones de Go ("90s", "5m") o un número entero de segundos.
+func parseDuration(v string) (reflect.Value, error) {
+	if secs, err := strconv.Atoi(v); err == nil {
+		return reflect.ValueOf(time.Duration(secs) * time.Second), nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid duration %q (use e.g. 30s, 5m)", v)
+	}
+	return reflect.ValueOf(d), nil
+}
+
+// parseList parsea una lista separada por comas.
+func parseList(v string) (reflect.Value, error) {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return reflect.ValueOf(items), nil
+}
+
+func findSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings_CatalogFromTags(t *testing.T) {
+	keys := map[string]bool{}
+	envs := map[string]bool{}
+	for _, s := range settings {
+		assert.NotEmpty(t, s.env, "%s no tiene etiqueta envconfig", s.key)
+		assert.NotEmpty(t, s.usage, "%s no tiene etiqueta desc", s.key)
+		assert.False(t, keys[s.key], "clave duplicada %s", s.key)
+		assert.False(t, envs[s.env], "variable de entorno duplicada %s", s.env)
+		keys[s.key], envs[s.env] = true, true
+	}
+
+	// Las claves se forman anidando las etiquetas 'key' de cada sección.
+	s, ok := findSetting("bus.kafka.producer.linger")
+	assert.True(t, ok)
+	assert.Equal(t, "KAFKA_LINGER", s.env)
+	assert.Equal(t, "duration", s.kind)
+
+	s, _ = findSetting("db.postgres.dsn")
+	assert.True(t, s.secret)
+	s, _ = findSetting("outbox.period")
+	assert.True(t, s.reloadable)
+}
+
+func TestDefault_FromTags(t *testing.T) {
+	// Act
+	cfg := Default()
+
+	// Assert
+	assert.Equal(t, "dev", cfg.App.Env)
+	assert.Equal(t, "8080", cfg.HTTP.Port)
+	assert.Equal(t, 5*time.Minute, cfg.Cache.TTL)
+	assert.Equal(t, []string{"localhost:9092"}, cfg.Bus.Kafka.Brokers)
+	assert.Equal(t, 10, cfg.Outbox.Limit)
+	assert.True(t, cfg.Analytics.ClickHouse.WaitForAsyncInsert)
+	assert.Empty(t, cfg.Analytics.Backend, "Sin etiqueta default queda el valor cero")
+}
+
+func TestSetting_GetSetRoundTrip(t *testing.T) {
+	// Arrange
+	cfg := Default()
+	s, _ := findSetting("bus.kafka.brokers")
+
+	// Act
+	err := s.set(cfg, "kafka-1:9092, kafka-2:9092")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Bus.Kafka.Brokers)
+	assert.Equal(t, "kafka-1:9092,kafka-2:9092", s.get(cfg))
+
+	limit, _ := findSetting("outbox.limit")
+	assert.ErrorContains(t, limit.set(cfg, "ten"), `invalid integer "ten"`)
+}
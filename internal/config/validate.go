@@ -0,0 +1,480 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	"github.com/davicafu/hexagolab/pkg/logger"
+)
+
+// ValidationError agrupa todas las violaciones encontradas en la configuración,
+// para poder corregirlas de una vez en lugar de una por arranque.
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems):\n  - %s",
+		len(e.Violations), strings.Join(e.Violations, "\n  - "))
+}
+
+// Validate comprueba la coherencia de la configuración. Devuelve un *ValidationError
+// con todas las violaciones, o nil si la configuración es válida.
+// Cada sección valida sus propios campos; aquí solo se añaden las reglas que cruzan secciones.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if _, ok := profiles[c.App.Env]; !ok {
+		v.fail("app.env", "must be one of %s, got %q", strings.Join(profileNames(), ", "), c.App.Env)
+	}
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		v.fail("log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	if _, err := logger.ParseModuleLevels(c.Log.Modules); err != nil {
+		v.fail("log.modules", "%v", err)
+	}
+	c.Components.validate(v)
+	c.HTTP.validate(v)
+	c.GRPC.validate(v)
+	c.DB.validate(v, c.usesBackend)
+	c.Cache.validate(v)
+	c.Bus.validate(v)
+	c.Outbox.validate(v, c.DB.User == "postgres" || c.DB.Task == "postgres")
+	c.Analytics.validate(v)
+	if _, err := c.Encryption.Keyring(); err != nil {
+		v.fail("encryption.keys", "%v", err)
+	}
+	c.Auth.validate(v)
+	if _, err := c.Quotas.Parsed(); err != nil {
+		v.fail("quotas.limits", "%v", err)
+	}
+
+	return v.err()
+}
+
+// usesBackend indica si algún dominio (o la analítica) está configurado sobre 'backend'.
+func (c *Config) usesBackend(backend string) bool {
+	return c.DB.User == backend || c.DB.Task == backend || c.Analytics.Backend == backend
+}
+
+func (c ComponentsConfig) validate(v *validator) {
+	for _, consumer := range c.Consumers {
+		if consumer != "user" && consumer != "task" {
+			v.fail("components.consumers", "must only contain user and task, got %q", consumer)
+		}
+	}
+	if !c.HTTP && !c.GRPC && !c.Relayer && len(c.Consumers) == 0 {
+		v.fail("components", "must enable at least one of http, grpc, relayer or consumers")
+	}
+}
+
+func (a AuthConfig) validate(v *validator) {
+	switch a.Mode {
+	case "none":
+		return
+	case "oidc":
+	default:
+		v.fail("auth.mode", "must be none or oidc, got %q", a.Mode)
+		return
+	}
+	if u, err := url.Parse(a.OIDC.Issuer); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		v.fail("auth.oidc.issuer", "must be an http(s) URL with auth.mode=oidc, got %q", a.OIDC.Issuer)
+	}
+	if a.OIDC.Audience == "" {
+		v.fail("auth.oidc.audience", "is required with auth.mode=oidc")
+	}
+	if a.OIDC.UserClaim == "" {
+		v.fail("auth.oidc.user_claim", "is required with auth.mode=oidc")
+	}
+	if _, err := a.OIDC.Roles(); err != nil {
+		v.fail("auth.oidc.role_map", "%v", err)
+	}
+	if a.OIDC.KeysTTL <= 0 {
+		v.fail("auth.oidc.keys_ttl", "must be positive, got %s", a.OIDC.KeysTTL)
+	}
+}
+
+func (h HTTPConfig) validate(v *validator) {
+	if port, err := strconv.Atoi(h.Port); err != nil || port < 1 || port > 65535 {
+		v.fail("http.port", "must be a port number between 1 and 65535, got %q", h.Port)
+	}
+	if _, err := h.AccessLog.SampleRates(); err != nil {
+		v.fail("http.access_log.sampling", "%v", err)
+	}
+	if _, err := h.CacheControl(); err != nil {
+		v.fail("http.cache_max_age", "%v", err)
+	}
+	if h.RequestTimeout < 0 {
+		v.fail("http.request_timeout", "must not be negative, got %s", h.RequestTimeout)
+	}
+	if _, err := h.Timeouts(); err != nil {
+		v.fail("http.route_timeouts", "%v", err)
+	}
+	h.TLS.validate(v, h.Port)
+	if h.GraphQL.MaxDepth <= 0 {
+		v.fail("http.graphql.max_depth", "must be positive, got %d", h.GraphQL.MaxDepth)
+	}
+	if h.LongPoll.MaxWait <= 0 {
+		v.fail("http.long_poll.max_wait", "must be positive, got %s", h.LongPoll.MaxWait)
+	}
+}
+
+func (t TLSConfig) validate(v *validator, httpPort string) {
+	switch t.Mode {
+	case "off":
+		return
+	case "files":
+		if t.CertFile == "" || t.KeyFile == "" {
+			v.fail("http.tls.cert_file", "cert_file and key_file are required with http.tls.mode=files")
+		}
+	case "autocert":
+		if len(t.Domains) == 0 {
+			v.fail("http.tls.domains", "is required with http.tls.mode=autocert")
+		}
+		if t.CacheDir == "" {
+			v.fail("http.tls.cache_dir", "is required with http.tls.mode=autocert")
+		}
+	default:
+		v.fail("http.tls.mode", "must be off, files or autocert, got %q", t.Mode)
+		return
+	}
+	if t.RedirectPort == "" {
+		return
+	}
+	if port, err := strconv.Atoi(t.RedirectPort); err != nil || port < 1 || port > 65535 {
+		v.fail("http.tls.redirect_port", "must be a port number between 1 and 65535, got %q", t.RedirectPort)
+	} else if t.RedirectPort == httpPort {
+		v.fail("http.tls.redirect_port", "must differ from http.port")
+	}
+}
+
+func (g GRPCConfig) validate(v *validator) {
+	if port, err := strconv.Atoi(g.Port); err != nil || port < 1 || port > 65535 {
+		v.fail("grpc.port", "must be a port number between 1 and 65535, got %q", g.Port)
+	}
+	if g.RequestTimeout < 0 {
+		v.fail("grpc.request_timeout", "must not be negative, got %s", g.RequestTimeout)
+	}
+}
+
+// validate comprueba los backends elegidos y exige los datos de conexión de los que se usan,
+// también cuando los usa la analítica (de ahí 'uses').
+func (d DBConfig) validate(v *validator, uses func(backend string) bool) {
+	switch d.User {
+	case "sqlite", "postgres", "memory":
+	default:
+		v.fail("db.user", "must be sqlite, postgres or memory, got %q", d.User)
+	}
+	switch d.Task {
+	case "sqlite", "postgres", "mongo", "memory", "file":
+	default:
+		v.fail("db.task", "must be sqlite, postgres, mongo, memory or file, got %q", d.Task)
+	}
+	if d.SlowQueryThreshold < 0 {
+		v.fail("db.slow_query_threshold", "must not be negative, got %s", d.SlowQueryThreshold)
+	}
+	if d.SQLite.MaxOpenConns < 0 {
+		v.fail("db.sqlite.max_open_conns", "must not be negative, got %d", d.SQLite.MaxOpenConns)
+	}
+	d.Postgres.validate(v)
+	if d.Mongo.MaxPoolSize < 0 {
+		v.fail("db.mongo.max_pool_size", "must not be negative, got %d", d.Mongo.MaxPoolSize)
+	}
+	if d.Mongo.MinPoolSize < 0 || (d.Mongo.MaxPoolSize > 0 && d.Mongo.MinPoolSize > d.Mongo.MaxPoolSize) {
+		v.fail("db.mongo.min_pool_size", "must be between 0 and db.mongo.max_pool_size, got %d", d.Mongo.MinPoolSize)
+	}
+	if uses("sqlite") && d.SQLite.Path == "" {
+		v.fail("db.sqlite.path", "is required when a domain uses sqlite")
+	}
+	if uses("file") && d.File.Path == "" {
+		v.fail("db.file.path", "is required when db.task is file")
+	}
+	if uses("postgres") && d.Postgres.DSN == "" {
+		v.fail("db.postgres.dsn", "is required when a domain or analytics uses postgres")
+	}
+	if uses("mongo") {
+		if d.Mongo.URI == "" {
+			v.fail("db.mongo.uri", "is required when db.task is mongo")
+		}
+		if d.Mongo.Database == "" {
+			v.fail("db.mongo.database", "is required when db.task is mongo")
+		}
+	}
+}
+
+// validate comprueba los límites del pool de Postgres.
+func (p PostgresConfig) validate(v *validator) {
+	if p.MaxOpenConns < 0 {
+		v.fail("db.postgres.max_open_conns", "must not be negative, got %d", p.MaxOpenConns)
+	}
+	if p.MaxIdleConns < 0 || (p.MaxOpenConns > 0 && p.MaxIdleConns > p.MaxOpenConns) {
+		v.fail("db.postgres.max_idle_conns", "must be between 0 and db.postgres.max_open_conns, got %d", p.MaxIdleConns)
+	}
+	if p.ConnMaxLifetime < 0 {
+		v.fail("db.postgres.conn_max_lifetime", "must not be negative, got %s", p.ConnMaxLifetime)
+	}
+	if p.ConnMaxIdleTime < 0 {
+		v.fail("db.postgres.conn_max_idle_time", "must not be negative, got %s", p.ConnMaxIdleTime)
+	}
+}
+
+func (c CacheConfig) validate(v *validator) {
+	if c.Redis.Addr != "" && !isHostPort(c.Redis.Addr) {
+		v.fail("cache.redis.addr", "must be host:port, got %q", c.Redis.Addr)
+	}
+	if c.Redis.PoolSize < 0 {
+		v.fail("cache.redis.pool_size", "must not be negative, got %d", c.Redis.PoolSize)
+	}
+	if c.Redis.MinIdleConns < 0 {
+		v.fail("cache.redis.min_idle_conns", "must not be negative, got %d", c.Redis.MinIdleConns)
+	}
+	if c.TTL <= 0 {
+		v.fail("cache.ttl", "must be positive, got %s", c.TTL)
+	}
+	if c.Async.Workers <= 0 {
+		v.fail("cache.async.workers", "must be positive, got %d", c.Async.Workers)
+	}
+	if c.Async.QueueSize <= 0 {
+		v.fail("cache.async.queue_size", "must be positive, got %d", c.Async.QueueSize)
+	}
+	switch c.Async.Policy {
+	case "drop", "wait":
+	default:
+		v.fail("cache.async.policy", "must be drop or wait, got %q", c.Async.Policy)
+	}
+	if c.Failover.Threshold <= 0 {
+		v.fail("cache.failover.threshold", "must be positive, got %d", c.Failover.Threshold)
+	}
+	if c.Failover.ProbeInterval <= 0 {
+		v.fail("cache.failover.probe_interval", "must be positive, got %s", c.Failover.ProbeInterval)
+	}
+	if c.Warmup.Users < 0 || c.Warmup.Users > maxCacheWarmup {
+		v.fail("cache.warmup.users", "must be between 0 and %d, got %d", maxCacheWarmup, c.Warmup.Users)
+	}
+	if c.Warmup.Tasks < 0 || c.Warmup.Tasks > maxCacheWarmup {
+		v.fail("cache.warmup.tasks", "must be between 0 and %d, got %d", maxCacheWarmup, c.Warmup.Tasks)
+	}
+	for _, tenant := range c.Warmup.Tenants {
+		if !tracing.ValidTenantID(tenant) {
+			v.fail("cache.warmup.tenants", "must only contain letters, digits, '-' and '_' (up to 64), got %q", tenant)
+		}
+	}
+	if c.Warmup.Enabled() && c.Warmup.Timeout <= 0 {
+		v.fail("cache.warmup.timeout", "must be positive, got %s", c.Warmup.Timeout)
+	}
+}
+
+// maxCacheWarmup limita los usuarios o tareas precargados por inquilino: más no caben con
+// holgura en una caché en memoria y retrasan demasiado el arranque.
+const maxCacheWarmup = 100000
+
+// validate solo comprueba Kafka si está habilitado; el bus en memoria no tiene opciones.
+func (b BusConfig) validate(v *validator) {
+	k := b.Kafka
+	if !k.Enabled {
+		return
+	}
+
+	if len(k.Brokers) == 0 {
+		v.fail("bus.kafka.brokers", "at least one broker is required when bus.kafka.enabled is true")
+	}
+	for _, broker := range k.Brokers {
+		if !isHostPort(broker) {
+			v.fail("bus.kafka.brokers", "broker must be host:port, got %q", broker)
+		}
+	}
+	if k.TopicUser == "" {
+		v.fail("bus.kafka.topic_user", "is required when bus.kafka.enabled is true")
+	}
+
+	switch strings.ToLower(k.SASL.Mechanism) {
+	case "":
+	case "plain", "scram-sha-256", "scram-sha-512":
+		if k.SASL.Username == "" {
+			v.fail("bus.kafka.sasl.username", "is required when bus.kafka.sasl.mechanism is set")
+		}
+		if k.SASL.Password == "" {
+			v.fail("bus.kafka.sasl.password", "is required when bus.kafka.sasl.mechanism is set")
+		}
+	default:
+		v.fail("bus.kafka.sasl.mechanism", "must be plain, scram-sha-256, scram-sha-512 or empty, got %q", k.SASL.Mechanism)
+	}
+	if k.TLS.CAFile != "" && !k.TLS.Enabled {
+		v.fail("bus.kafka.tls.ca_file", "requires bus.kafka.tls.enabled")
+	}
+	if (k.TLS.CertFile != "" || k.TLS.KeyFile != "") && !k.TLS.Enabled {
+		v.fail("bus.kafka.tls.cert_file", "requires bus.kafka.tls.enabled")
+	}
+	if (k.TLS.CertFile == "") != (k.TLS.KeyFile == "") {
+		v.fail("bus.kafka.tls.key_file", "must be set together with bus.kafka.tls.cert_file")
+	}
+
+	for _, tenant := range k.Tenants {
+		if !tracing.ValidTenantID(tenant) {
+			v.fail("bus.kafka.tenants", "must only contain letters, digits, '-' and '_' (up to 64), got %q", tenant)
+		}
+	}
+	if len(k.Tenants) > 0 && !k.TenantTopics {
+		v.fail("bus.kafka.tenants", "requires bus.kafka.tenant_topics")
+	}
+
+	if k.Producer.BatchSize <= 0 {
+		v.fail("bus.kafka.producer.batch_size", "must be positive, got %d", k.Producer.BatchSize)
+	}
+	if k.Producer.Linger < 0 {
+		v.fail("bus.kafka.producer.linger", "must not be negative, got %s", k.Producer.Linger)
+	}
+	switch strings.ToLower(k.Producer.Compression) {
+	case "", "none", "gzip", "snappy", "lz4", "zstd":
+	default:
+		v.fail("bus.kafka.producer.compression", "must be one of none, gzip, snappy, lz4, zstd, got %q", k.Producer.Compression)
+	}
+	switch strings.ToLower(k.Producer.RequiredAcks) {
+	case "", "all", "one", "none":
+	default:
+		v.fail("bus.kafka.producer.required_acks", "must be one of all, one, none, got %q", k.Producer.RequiredAcks)
+	}
+	if k.Producer.Idempotent {
+		if acks := strings.ToLower(k.Producer.RequiredAcks); acks != "" && acks != "all" {
+			v.fail("bus.kafka.producer.required_acks", "must be all with bus.kafka.producer.idempotent, got %q", k.Producer.RequiredAcks)
+		}
+		if k.Producer.Async {
+			v.fail("bus.kafka.producer.async", "is not supported with bus.kafka.producer.idempotent")
+		}
+	}
+	if k.Producer.TransactionalID != "" && !k.Producer.Idempotent {
+		v.fail("bus.kafka.producer.transactional_id", "requires bus.kafka.producer.idempotent")
+	}
+	if k.Producer.MarkerTopic != "" && k.Producer.TransactionalID == "" {
+		v.fail("bus.kafka.producer.marker_topic", "requires bus.kafka.producer.transactional_id")
+	}
+
+	if k.Consumer.MinBytes <= 0 {
+		v.fail("bus.kafka.consumer.min_bytes", "must be positive, got %d", k.Consumer.MinBytes)
+	}
+	if k.Consumer.MaxBytes < k.Consumer.MinBytes {
+		v.fail("bus.kafka.consumer.max_bytes", "must be >= bus.kafka.consumer.min_bytes, got %d", k.Consumer.MaxBytes)
+	}
+	switch strings.ToLower(k.Consumer.StartOffset) {
+	case "first", "earliest", "last", "latest":
+	default:
+		v.fail("bus.kafka.consumer.start_offset", "must be first or last, got %q", k.Consumer.StartOffset)
+	}
+	if k.Consumer.DedupSize < 0 {
+		v.fail("bus.kafka.consumer.dedup_size", "must not be negative, got %d", k.Consumer.DedupSize)
+	}
+	if k.Consumer.DedupSize > 0 && k.Consumer.DedupWindow <= 0 {
+		v.fail("bus.kafka.consumer.dedup_window", "must be positive when bus.kafka.consumer.dedup_size is set, got %s", k.Consumer.DedupWindow)
+	}
+	if k.Consumer.GroupUser == "" {
+		v.fail("bus.kafka.consumer.group_user", "is required when bus.kafka.enabled is true")
+	}
+	if k.Consumer.GroupTask == "" {
+		v.fail("bus.kafka.consumer.group_task", "is required when bus.kafka.enabled is true")
+	}
+}
+
+// 'postgresOutbox' indica si algún dominio guarda su outbox en Postgres, el único que se particiona.
+func (o OutboxConfig) validate(v *validator, postgresOutbox bool) {
+	if o.Period <= 0 {
+		v.fail("outbox.period", "must be positive, got %s", o.Period)
+	}
+	if o.Limit <= 0 {
+		v.fail("outbox.limit", "must be positive, got %d", o.Limit)
+	}
+	if _, err := compression.ParseEncoding(o.Compression); err != nil {
+		v.fail("outbox.compression", "%v", err)
+	}
+	if o.CompressionThreshold < 0 {
+		v.fail("outbox.compression_threshold", "must not be negative, got %d", o.CompressionThreshold)
+	}
+	if _, err := o.PayloadModes(); err != nil {
+		v.fail("outbox.payloads", "%v", err)
+	}
+	if p := o.Partitioning; p.Enabled {
+		if !postgresOutbox {
+			v.fail("outbox.partitioning.enabled", "needs db.user or db.task on postgres")
+		}
+		if p.Ahead < 1 {
+			v.fail("outbox.partitioning.ahead", "must be at least 1, got %d", p.Ahead)
+		}
+		if p.Period <= 0 {
+			v.fail("outbox.partitioning.period", "must be positive, got %s", p.Period)
+		} else if p.Period >= time.Duration(p.Ahead)*24*time.Hour {
+			v.fail("outbox.partitioning.period", "must be shorter than the %d days of partitions created ahead, got %s", p.Ahead, p.Period)
+		}
+		if p.Retention < 0 || (p.Retention > 0 && p.Retention < 24*time.Hour) {
+			v.fail("outbox.partitioning.retention", "must be 0 or at least 24h, got %s", p.Retention)
+		}
+	}
+}
+
+func (a AnalyticsConfig) validate(v *validator) {
+	switch a.Backend {
+	case "":
+	case "clickhouse":
+		ch := a.ClickHouse
+		if ch.Addr == "" {
+			v.fail("analytics.clickhouse.addr", "is required when analytics.backend is clickhouse")
+		} else if !isHostPort(ch.Addr) {
+			v.fail("analytics.clickhouse.addr", "must be host:port, got %q", ch.Addr)
+		}
+		switch strings.ToLower(ch.Compression) {
+		case "", "none", "lz4", "lz4hc", "zstd":
+		default:
+			v.fail("analytics.clickhouse.compression", "must be one of none, lz4, lz4hc, zstd, got %q", ch.Compression)
+		}
+		if ch.MaxExecutionTime < 0 {
+			v.fail("analytics.clickhouse.max_execution_time", "must not be negative, got %s", ch.MaxExecutionTime)
+		}
+		if ch.DialTimeout < 0 {
+			v.fail("analytics.clickhouse.dial_timeout", "must not be negative, got %s", ch.DialTimeout)
+		}
+	case "postgres":
+	default:
+		v.fail("analytics.backend", "must be clickhouse, postgres or empty, got %q", a.Backend)
+	}
+	if a.Backend != "" && a.ExportDir == "" {
+		v.fail("analytics.export_dir", "is required when analytics is enabled")
+	}
+	if a.RetentionMonths < 0 {
+		v.fail("analytics.retention_months", "must not be negative, got %d", a.RetentionMonths)
+	}
+}
+
+type validator struct {
+	violations []string
+}
+
+// fail registra una violación indicando la clave y, si la tiene, su variable de entorno.
+func (v *validator) fail(key, format string, args ...any) {
+	name := key
+	if s, ok := findSetting(key); ok && s.env != "" {
+		name = fmt.Sprintf("%s (%s)", key, s.env)
+	}
+	v.violations = append(v.violations, name+" "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+func isHostPort(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
@@ -0,0 +1,304 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/quota"
+)
+
+func TestValidate_DefaultsAreValid(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+}
+
+func TestValidate_ReportsAllViolations(t *testing.T) {
+	// Arrange
+	cfg := Default()
+	cfg.HTTP.Port = "99999"
+	cfg.Outbox.Period = 0
+	cfg.Outbox.Limit = -1
+	cfg.Bus.Kafka.Enabled = true
+	cfg.Bus.Kafka.Brokers = []string{"kafka-1"}
+	cfg.Analytics.Backend = "clickhouse"
+
+	// Act
+	err := cfg.Validate()
+
+	// Assert
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Len(t, verr.Violations, 5)
+	assert.Contains(t, err.Error(), "http.port (HTTP_PORT)")
+	assert.Contains(t, err.Error(), "outbox.period (OUTBOX_PERIOD) must be positive")
+	assert.Contains(t, err.Error(), "outbox.limit (OUTBOX_LIMIT) must be positive")
+	assert.Contains(t, err.Error(), `broker must be host:port, got "kafka-1"`)
+	assert.Contains(t, err.Error(), "analytics.clickhouse.addr (CLICKHOUSE_ADDR) is required")
+}
+
+func TestValidate_OutboxPartitioning(t *testing.T) {
+	cfg := Default() // Las tareas están en Postgres.
+	cfg.Outbox.Partitioning.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Outbox.Partitioning.Ahead = 1
+	cfg.Outbox.Partitioning.Period = 48 * time.Hour
+	cfg.Outbox.Partitioning.Retention = time.Hour
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, "outbox.partitioning.period (OUTBOX_PARTITIONING_PERIOD) must be shorter than the 1 days of partitions created ahead")
+	assert.ErrorContains(t, err, "outbox.partitioning.retention (OUTBOX_PARTITIONING_RETENTION) must be 0 or at least 24h")
+
+	cfg = Default()
+	cfg.DB.Task = "sqlite"
+	cfg.Outbox.Partitioning.Enabled = true
+	assert.ErrorContains(t, cfg.Validate(), "outbox.partitioning.enabled (OUTBOX_PARTITIONING_ENABLED) needs db.user or db.task on postgres")
+}
+
+func TestLoad_FailsOnInvalidConfig(t *testing.T) {
+	t.Setenv("ANALYTICS_BACKEND", "druid")
+
+	_, err := Load(nil)
+
+	assert.ErrorContains(t, err, "analytics.backend (ANALYTICS_BACKEND) must be clickhouse, postgres or empty")
+}
+
+func TestValidate_Components(t *testing.T) {
+	cfg := Default()
+	cfg.Components.Consumers = []string{"user", "billing"}
+
+	assert.ErrorContains(t, cfg.Validate(), `components.consumers (COMPONENTS_CONSUMERS) must only contain user and task, got "billing"`)
+
+	cfg.Components = ComponentsConfig{}
+	assert.ErrorContains(t, cfg.Validate(), "components must enable at least one of http, grpc, relayer or consumers")
+}
+
+func TestValidate_StorageBackends(t *testing.T) {
+	cfg := Default()
+	cfg.DB.User = "mongo"
+	cfg.DB.Task = "mongo"
+	cfg.DB.Mongo.URI = ""
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, `db.user (USER_DB) must be sqlite, postgres or memory, got "mongo"`)
+	assert.ErrorContains(t, err, "db.mongo.uri (MONGO_URI) is required when db.task is mongo")
+}
+
+func TestValidate_ConnectionPools(t *testing.T) {
+	cfg := Default()
+	cfg.DB.Postgres.MaxOpenConns = 10
+	cfg.DB.Postgres.MaxIdleConns = 20
+	cfg.DB.Postgres.ConnMaxLifetime = -time.Minute
+	cfg.DB.Mongo.MaxPoolSize = 5
+	cfg.DB.Mongo.MinPoolSize = 10
+	cfg.Cache.Redis.PoolSize = -1
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, "db.postgres.max_idle_conns (POSTGRES_MAX_IDLE_CONNS) must be between 0 and db.postgres.max_open_conns, got 20")
+	assert.ErrorContains(t, err, "db.postgres.conn_max_lifetime (POSTGRES_CONN_MAX_LIFETIME) must not be negative, got -1m0s")
+	assert.ErrorContains(t, err, "db.mongo.min_pool_size (MONGO_MIN_POOL_SIZE) must be between 0 and db.mongo.max_pool_size, got 10")
+	assert.ErrorContains(t, err, "cache.redis.pool_size (REDIS_POOL_SIZE) must not be negative, got -1")
+}
+
+func TestValidate_CacheAsync(t *testing.T) {
+	cfg := Default()
+	cfg.Cache.Async.Workers = 0
+	cfg.Cache.Async.Policy = "block"
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, "cache.async.workers (CACHE_ASYNC_WORKERS) must be positive, got 0")
+	assert.ErrorContains(t, err, `cache.async.policy (CACHE_ASYNC_POLICY) must be drop or wait, got "block"`)
+}
+
+func TestValidate_CacheWarmup(t *testing.T) {
+	cfg := Default()
+	cfg.Cache.Warmup.Users = -1
+	cfg.Cache.Warmup.Tasks = 1000
+	cfg.Cache.Warmup.Tenants = []string{"acme.eu"}
+	cfg.Cache.Warmup.Timeout = 0
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, "cache.warmup.users (CACHE_WARMUP_USERS) must be between 0 and 100000, got -1")
+	assert.ErrorContains(t, err, `cache.warmup.tenants (CACHE_WARMUP_TENANTS) must only contain letters, digits, '-' and '_' (up to 64), got "acme.eu"`)
+	assert.ErrorContains(t, err, "cache.warmup.timeout (CACHE_WARMUP_TIMEOUT) must be positive, got 0s")
+	assert.NotContains(t, err.Error(), "cache.warmup.tasks")
+}
+
+func TestValidate_OutboxCompression(t *testing.T) {
+	cfg := Default()
+	cfg.Outbox.Compression = "brotli"
+	cfg.Outbox.CompressionThreshold = -1
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, `outbox.compression (OUTBOX_COMPRESSION) unknown content encoding: "brotli"`)
+	assert.ErrorContains(t, err, "outbox.compression_threshold (OUTBOX_COMPRESSION_THRESHOLD) must not be negative, got -1")
+}
+
+func TestValidate_OutboxPayloads(t *testing.T) {
+	cfg := Default()
+	cfg.Outbox.Payloads = []string{"task.updated=delta", "user.updated=diff"}
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, `outbox.payloads (OUTBOX_PAYLOADS) invalid payload entry "user.updated=diff": unknown payload mode "diff", must be snapshot, delta or id`)
+
+	cfg.Outbox.Payloads = []string{"task.updated=delta", "user.created=id"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_EncryptionKeys(t *testing.T) {
+	cfg := Default()
+	cfg.Encryption.Keys = []string{"k1:not-base64"}
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, "encryption.keys (ENCRYPTION_KEYS) invalid encryption key: key \"k1\" must be 32 bytes encoded in base64")
+}
+
+func TestValidate_AuthOIDC(t *testing.T) {
+	cfg := Default()
+	cfg.Auth.Mode = "oidc"
+	cfg.Auth.OIDC.Issuer = "idp.example.com"
+	cfg.Auth.OIDC.RoleMap = []string{"admin"}
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, `auth.oidc.issuer (OIDC_ISSUER) must be an http(s) URL with auth.mode=oidc, got "idp.example.com"`)
+	assert.ErrorContains(t, err, "auth.oidc.audience (OIDC_AUDIENCE) is required with auth.mode=oidc")
+	assert.ErrorContains(t, err, `auth.oidc.role_map (OIDC_ROLE_MAP) invalid role entry "admin", expected idp_role=role`)
+
+	cfg.Auth.OIDC.Issuer = "https://idp.example.com/realms/hexagolab"
+	cfg.Auth.OIDC.Audience = "hexagolab-api"
+	cfg.Auth.OIDC.RoleMap = []string{"hexagolab-admin=admin"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_HTTPTLS(t *testing.T) {
+	cfg := Default()
+	cfg.HTTP.TLS.Mode = "autocert"
+	cfg.HTTP.TLS.RedirectPort = cfg.HTTP.Port
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, "http.tls.domains (HTTP_TLS_DOMAINS) is required with http.tls.mode=autocert")
+	assert.ErrorContains(t, err, "http.tls.redirect_port (HTTP_TLS_REDIRECT_PORT) must differ from http.port")
+
+	cfg.HTTP.TLS.Mode = "files"
+	assert.ErrorContains(t, cfg.Validate(), "http.tls.cert_file (HTTP_TLS_CERT_FILE) cert_file and key_file are required with http.tls.mode=files")
+
+	cfg.HTTP.TLS.CertFile, cfg.HTTP.TLS.KeyFile = "/etc/hexagolab/tls.crt", "/etc/hexagolab/tls.key"
+	cfg.HTTP.TLS.RedirectPort = "80"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestQuotasConfig_Parsed(t *testing.T) {
+	limits, err := QuotasConfig{Limits: []string{"tasks.create=100/day", " users.create = 5/month"}}.Parsed()
+	require.NoError(t, err)
+	assert.Equal(t, []quota.Limit{
+		{Action: quota.ActionCreateTask, Max: 100, Period: quota.Daily},
+		{Action: quota.ActionCreateUser, Max: 5, Period: quota.Monthly},
+	}, limits)
+
+	cfg := Default()
+	cfg.Quotas.Limits = []string{"tasks.delete=1/day"}
+	assert.ErrorContains(t, cfg.Validate(), `quotas.limits (QUOTA_LIMITS) invalid quota "tasks.delete=1/day", the action must be one of tasks.create, users.create`)
+}
+
+func TestHTTPConfig_CacheControl(t *testing.T) {
+	policies, err := HTTPConfig{CacheMaxAge: []string{"/users/:id=0s", " /tasks/:id = 30s "}}.CacheControl()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/users/:id": "private, no-cache", "/tasks/:id": "private, max-age=30"}, policies)
+
+	cfg := Default()
+	cfg.HTTP.CacheMaxAge = []string{"/tasks/:id=forever"}
+	assert.ErrorContains(t, cfg.Validate(), `http.cache_max_age (HTTP_CACHE_MAX_AGE) invalid max-age in "/tasks/:id=forever"`)
+}
+
+func TestHTTPConfig_Timeouts(t *testing.T) {
+	timeouts, err := HTTPConfig{RouteTimeouts: []string{"/tasks/export=0s", " /tasks/ = 5s "}}.Timeouts()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"/tasks/export": 0, "/tasks/": 5 * time.Second}, timeouts)
+
+	cfg := Default()
+	cfg.HTTP.RouteTimeouts = []string{"/tasks/export"}
+	cfg.GRPC.RequestTimeout = -time.Second
+	err = cfg.Validate()
+	assert.ErrorContains(t, err, `http.route_timeouts (HTTP_ROUTE_TIMEOUTS) invalid timeout entry "/tasks/export"`)
+	assert.ErrorContains(t, err, `grpc.request_timeout (GRPC_REQUEST_TIMEOUT) must not be negative`)
+}
+
+func TestValidate_KafkaSettings(t *testing.T) {
+	cfg := Default()
+	cfg.Bus.Kafka.Enabled = true
+	cfg.Bus.Kafka.SASL.Mechanism = "scram-sha-512"
+	cfg.Bus.Kafka.SASL.Username = "hexagolab"
+	cfg.Bus.Kafka.Producer.Compression = "brotli"
+	cfg.Bus.Kafka.Producer.RequiredAcks = "two"
+	cfg.Bus.Kafka.Consumer.StartOffset = "middle"
+	cfg.Bus.Kafka.Consumer.DedupWindow = 0
+	cfg.Bus.Kafka.TLS.Enabled = true
+	cfg.Bus.Kafka.TLS.CertFile = "/etc/kafka/client.pem"
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, "bus.kafka.sasl.password (KAFKA_SASL_PASSWORD) is required when bus.kafka.sasl.mechanism is set")
+	assert.ErrorContains(t, err, `bus.kafka.producer.compression (KAFKA_COMPRESSION) must be one of none, gzip, snappy, lz4, zstd, got "brotli"`)
+	assert.ErrorContains(t, err, `bus.kafka.producer.required_acks (KAFKA_REQUIRED_ACKS) must be one of all, one, none, got "two"`)
+	assert.ErrorContains(t, err, `bus.kafka.consumer.start_offset (KAFKA_START_OFFSET) must be first or last, got "middle"`)
+	assert.ErrorContains(t, err, "bus.kafka.consumer.dedup_window (KAFKA_DEDUP_WINDOW) must be positive when bus.kafka.consumer.dedup_size is set, got 0s")
+	assert.ErrorContains(t, err, "bus.kafka.tls.key_file (KAFKA_TLS_KEY_FILE) must be set together with bus.kafka.tls.cert_file")
+}
+
+func TestValidate_KafkaIdempotentProducer(t *testing.T) {
+	cfg := Default()
+	cfg.Bus.Kafka.Enabled = true
+	cfg.Bus.Kafka.Producer.Idempotent = true
+	cfg.Bus.Kafka.Producer.TransactionalID = "hexagolab-1"
+	cfg.Bus.Kafka.Producer.MarkerTopic = "outbox-processed"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Bus.Kafka.Producer.RequiredAcks = "one"
+	cfg.Bus.Kafka.Producer.Async = true
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, `bus.kafka.producer.required_acks (KAFKA_REQUIRED_ACKS) must be all with bus.kafka.producer.idempotent, got "one"`)
+	assert.ErrorContains(t, err, "bus.kafka.producer.async (KAFKA_ASYNC) is not supported with bus.kafka.producer.idempotent")
+
+	cfg = Default()
+	cfg.Bus.Kafka.Enabled = true
+	cfg.Bus.Kafka.Producer.TransactionalID = "hexagolab-1"
+	cfg.Bus.Kafka.Producer.MarkerTopic = "outbox-processed"
+	err = cfg.Validate()
+	assert.ErrorContains(t, err, "bus.kafka.producer.transactional_id (KAFKA_TRANSACTIONAL_ID) requires bus.kafka.producer.idempotent")
+
+	cfg.Bus.Kafka.Producer.TransactionalID = ""
+	err = cfg.Validate()
+	assert.ErrorContains(t, err, "bus.kafka.producer.marker_topic (KAFKA_MARKER_TOPIC) requires bus.kafka.producer.transactional_id")
+}
+
+func TestAccessLogConfig_SampleRates(t *testing.T) {
+	rates, err := AccessLogConfig{Sampling: []string{"/health=0.01", " /metrics = 0 "}}.SampleRates()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"/health": 0.01, "/metrics": 0}, rates)
+
+	cfg := Default()
+	cfg.HTTP.AccessLog.Sampling = []string{"/health=2"}
+	assert.ErrorContains(t, cfg.Validate(), `http.access_log.sampling (HTTP_ACCESS_LOG_SAMPLING) invalid sampling rate in "/health=2"`)
+}
+
+func TestValidate_KafkaTenants(t *testing.T) {
+	cfg := Default()
+	cfg.Bus.Kafka.Enabled = true
+	cfg.Bus.Kafka.Tenants = []string{"acme", "acme.eu"}
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, `bus.kafka.tenants (KAFKA_TENANTS) must only contain letters, digits, '-' and '_' (up to 64), got "acme.eu"`)
+	assert.ErrorContains(t, err, "bus.kafka.tenants (KAFKA_TENANTS) requires bus.kafka.tenant_topics")
+}
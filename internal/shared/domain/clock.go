@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Clock es el puerto para leer la hora y programar esperas periódicas. Los servicios y workers
+// lo reciben en su constructor en lugar de llamar a time.Now, para que los tests fijen la hora.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker es el equivalente a time.Ticker que devuelve un Clock.
+type Ticker interface {
+	C() <-chan time.Time
+	Reset(d time.Duration)
+	Stop()
+}
+
+// SystemClock es el Clock de la hora real del sistema.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+func (SystemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{t: time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (s systemTicker) C() <-chan time.Time   { return s.t.C }
+func (s systemTicker) Reset(d time.Duration) { s.t.Reset(d) }
+func (s systemTicker) Stop()                 { s.t.Stop() }
+
+// Verificación estática
+var _ Clock = SystemClock{}
@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
 // ---------------- Operadores ----------------
 
 type Operator string
@@ -12,8 +18,26 @@ const (
 	OpLte   Operator = "<="
 	OpLike  Operator = "LIKE"
 	OpILike Operator = "ILIKE"
+	// OpIsNull cumple los registros sin valor en el campo. No lleva valor: Value se ignora.
+	OpIsNull Operator = "IS NULL"
+	// OpIsNotNull cumple los registros con valor en el campo. Tampoco lleva valor.
+	OpIsNotNull Operator = "IS NOT NULL"
 )
 
+// Valid indica si el operador es uno de los anteriores; los traductores rechazan el resto.
+func (o Operator) Valid() bool {
+	switch o {
+	case OpEq, OpGt, OpGte, OpLt, OpLte, OpLike, OpILike, OpIsNull, OpIsNotNull:
+		return true
+	}
+	return false
+}
+
+// HasValue indica si el operador compara con un valor; OpIsNull y OpIsNotNull no lo hacen.
+func (o Operator) HasValue() bool {
+	return o != OpIsNull && o != OpIsNotNull
+}
+
 type LogicalOperator string
 
 const (
@@ -52,6 +76,22 @@ func (c CompositeCriteria) ToConditions() []Criterion {
 	return all
 }
 
+// ---------------- Not Criteria ----------------
+
+// NotCriteria niega un criterio: cumple los registros que no cumplen 'Criteria'. ToConditions
+// devuelve las condiciones del criterio negado (para validar sus campos), así que los
+// traductores tienen que reconocer el tipo y no limitarse a aplanar condiciones.
+type NotCriteria struct {
+	Criteria Criteria
+}
+
+func (c NotCriteria) ToConditions() []Criterion {
+	if c.Criteria == nil {
+		return nil
+	}
+	return c.Criteria.ToConditions()
+}
+
 // ---------------- Helpers ----------------
 
 // And crea un CompositeCriteria con operador AND
@@ -63,3 +103,79 @@ func And(criterias ...Criteria) CompositeCriteria {
 func Or(criterias ...Criteria) CompositeCriteria {
 	return CompositeCriteria{Operator: OpOr, Criterias: criterias}
 }
+
+// Not crea un NotCriteria. Negar un criterio sin condiciones no filtra nada.
+func Not(criteria Criteria) NotCriteria {
+	return NotCriteria{Criteria: criteria}
+}
+
+// ---------------- Validación ----------------
+
+// ErrInvalidCriteria indica que un listado filtra u ordena por un campo que el agregado no declara
+// o con un operador que ese campo no admite.
+var ErrInvalidCriteria = NewError(CodeInvalidArgument, "invalid criteria")
+
+// CriteriaError es el error de validación de un campo concreto. Envuelve ErrInvalidCriteria.
+type CriteriaError struct {
+	Field  string
+	Reason string
+}
+
+func (e *CriteriaError) Error() string {
+	return fmt.Sprintf("%s: field %q: %s", ErrInvalidCriteria, e.Field, e.Reason)
+}
+
+func (e *CriteriaError) Unwrap() error { return ErrInvalidCriteria }
+
+// FieldRegistry declara los campos por los que se puede filtrar y ordenar un agregado y los
+// operadores que admite cada uno. Los servicios validan los criterios con él antes de llegar al
+// repositorio, y los repositorios construyen su lista blanca de columnas con Names.
+type FieldRegistry map[string][]Operator
+
+// Names devuelve los campos declarados, ordenados.
+func (r FieldRegistry) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Nullable devuelve, ordenados, los campos que pueden no tener valor: los que admiten OpIsNull.
+func (r FieldRegistry) Nullable() []string {
+	var names []string
+	for name, ops := range r {
+		if slices.Contains(ops, OpIsNull) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate devuelve un *CriteriaError con la primera condición de 'criteria' que usa un campo no
+// declarado o un operador que el campo no admite. Un criteria nil no filtra y siempre es válido.
+func (r FieldRegistry) Validate(criteria Criteria) error {
+	if criteria == nil {
+		return nil
+	}
+	for _, c := range criteria.ToConditions() {
+		ops, ok := r[c.Field]
+		if !ok {
+			return &CriteriaError{Field: c.Field, Reason: "unknown field"}
+		}
+		if !slices.Contains(ops, c.Op) {
+			return &CriteriaError{Field: c.Field, Reason: fmt.Sprintf("operator %q is not supported", c.Op)}
+		}
+	}
+	return nil
+}
+
+// ValidateSort comprueba que se puede ordenar por 'field'. Un campo vacío es el orden por defecto.
+func (r FieldRegistry) ValidateSort(field string) error {
+	if _, ok := r[field]; field != "" && !ok {
+		return &CriteriaError{Field: field, Reason: "unknown sort field"}
+	}
+	return nil
+}
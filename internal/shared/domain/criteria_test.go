@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// condition es un criterio de una sola condición, para los tests.
+type condition Criterion
+
+func (c condition) ToConditions() []Criterion { return []Criterion{Criterion(c)} }
+
+var testFields = FieldRegistry{
+	"nombre":     {OpEq, OpILike},
+	"created_at": {OpGte, OpLte},
+}
+
+func TestFieldRegistry_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		criteria   Criteria
+		wantField  string
+		wantReason string
+	}{
+		{"nil criteria", nil, "", ""},
+		{"empty composite", And(), "", ""},
+		{"declared field and operator", And(condition{Field: "nombre", Op: OpILike, Value: "%a%"}, condition{Field: "created_at", Op: OpGte}), "", ""},
+		{"typo in the field", And(condition{Field: "nombre", Op: OpEq}, condition{Field: "nmbre", Op: OpEq}), "nmbre", "unknown field"},
+		{"operator not allowed for the field", condition{Field: "created_at", Op: OpLike}, "created_at", `operator "LIKE" is not supported`},
+		{"unknown operator", condition{Field: "nombre", Op: "$where"}, "nombre", `operator "$where" is not supported`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := testFields.Validate(tt.criteria)
+
+			// Assert
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var criteriaErr *CriteriaError
+			require.ErrorAs(t, err, &criteriaErr)
+			assert.ErrorIs(t, err, ErrInvalidCriteria)
+			assert.Equal(t, tt.wantField, criteriaErr.Field)
+			assert.Equal(t, tt.wantReason, criteriaErr.Reason)
+		})
+	}
+}
+
+func TestFieldRegistry_ValidateSort(t *testing.T) {
+	assert.NoError(t, testFields.ValidateSort("created_at"))
+	assert.NoError(t, testFields.ValidateSort(""), "Sin campo se usa el orden por defecto")
+	assert.ErrorIs(t, testFields.ValidateSort("birth_date"), ErrInvalidCriteria)
+}
+
+func TestFieldRegistry_Names(t *testing.T) {
+	assert.Equal(t, []string{"created_at", "nombre"}, testFields.Names())
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Code clasifica un error de la aplicación con independencia del transporte: los adaptadores de
+// entrada lo traducen a un estado HTTP o a un código gRPC (ver sharedHttp.WriteError y
+// sharedGrpc.Error) en lugar de comparar cada error a mano.
+type Code string
+
+const (
+	CodeInvalidArgument    Code = "invalid_argument"    // Petición incorrecta: 400 / InvalidArgument
+	CodeNotFound           Code = "not_found"           // 404 / NotFound
+	CodeAlreadyExists      Code = "already_exists"      // 409 / AlreadyExists
+	CodeFailedPrecondition Code = "failed_precondition" // El estado actual no lo permite: 409 / FailedPrecondition
+	CodeAborted            Code = "aborted"             // Escritura concurrente (la versión no coincide): 412 / Aborted
+	CodeResourceExhausted  Code = "resource_exhausted"  // Cuota agotada: 429 / ResourceExhausted
+	CodeUnavailable        Code = "unavailable"         // Dependencia no disponible: 503 / Unavailable
+	CodeDeadlineExceeded   Code = "deadline_exceeded"   // Vence el plazo de la petición: 504 / DeadlineExceeded
+	CodeInternal           Code = "internal"            // Cualquier otro error: 500 / Internal
+)
+
+// Error es un error con código. Los errores centinela de los dominios (ErrUserNotFound,
+// ErrInvalidCriteria...) son *Error, y los errores tipados que los envuelven (CriteriaError...)
+// heredan su código: errors.Is sigue funcionando como con errors.New.
+type Error struct {
+	Code    Code
+	Message string
+	// Details indica el motivo por campo o parámetro, para que el cliente los corrija todos de
+	// una vez.
+	Details map[string]string
+	// Err es la causa, si la hay.
+	Err error
+}
+
+// NewError crea un error con código, para declarar errores centinela.
+func NewError(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// WrapError le da el código 'code' a 'err', que conserva su mensaje y sigue en la cadena de errores.
+func WrapError(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+// Errorf crea un error con código y el mensaje de fmt.Errorf, que admite %w.
+func Errorf(code Code, format string, args ...any) *Error {
+	return WrapError(code, fmt.Errorf(format, args...))
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// WithDetails devuelve una copia del error con 'details'.
+func (e *Error) WithDetails(details map[string]string) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// AsError devuelve 'err' como *Error: el código y los detalles son los del primer *Error de su
+// cadena que los tenga, y el mensaje el de 'err' completo. Un error sin código es
+// CodeDeadlineExceeded si es (o envuelve) context.DeadlineExceeded y CodeInternal si no. nil
+// devuelve nil.
+func AsError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	out := &Error{Message: err.Error(), Err: err}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		coded, ok := e.(*Error)
+		if !ok {
+			continue
+		}
+		if out.Code == "" {
+			out.Code = coded.Code
+		}
+		if out.Details == nil {
+			out.Details = coded.Details
+		}
+	}
+	switch {
+	case out.Code != "":
+	case errors.Is(err, context.DeadlineExceeded):
+		out.Code = CodeDeadlineExceeded
+	default:
+		out.Code = CodeInternal
+	}
+	return out
+}
+
+// ErrorCode devuelve el código de 'err' (ver AsError).
+func ErrorCode(err error) Code {
+	if err == nil {
+		return ""
+	}
+	return AsError(err).Code
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsError(t *testing.T) {
+	errNotFound := NewError(CodeNotFound, "task not found")
+	invalidFilters := NewError(CodeInvalidArgument, "invalid filters").WithDetails(map[string]string{"status": "must be one of pending, completed"})
+
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    Code
+		wantMessage string
+		wantDetails map[string]string
+	}{
+		{"sentinel", errNotFound, CodeNotFound, "task not found", nil},
+		{"wrapped sentinel keeps the full message", fmt.Errorf("could not delete task: %w", errNotFound), CodeNotFound, "could not delete task: task not found", nil},
+		{"typed error wrapping a sentinel", &CriteriaError{Field: "nmbre", Reason: "unknown field"}, CodeInvalidArgument, `invalid criteria: field "nmbre": unknown field`, nil},
+		{"details", fmt.Errorf("listing: %w", invalidFilters), CodeInvalidArgument, "listing: invalid filters", invalidFilters.Details},
+		{"the outermost code wins", WrapError(CodeUnavailable, fmt.Errorf("export: %w", errNotFound)), CodeUnavailable, "export: task not found", nil},
+		{"uncoded error", errors.New("connection reset"), CodeInternal, "connection reset", nil},
+		{"expired deadline", fmt.Errorf("counting tasks: %w", context.DeadlineExceeded), CodeDeadlineExceeded, "counting tasks: context deadline exceeded", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := AsError(tt.err)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.Equal(t, tt.wantMessage, got.Message)
+			assert.Equal(t, tt.wantDetails, got.Details)
+			assert.ErrorIs(t, got, tt.err)
+		})
+	}
+	assert.Nil(t, AsError(nil))
+	assert.ErrorIs(t, fmt.Errorf("get: %w", errNotFound), errNotFound, "Los centinela siguen funcionando con errors.Is")
+}
@@ -4,16 +4,90 @@ import (
 	"encoding/json"
 	"reflect"
 	"time"
+
+	"github.com/google/uuid"
 )
 
-// Base de todos los eventos de integración
+// Base de todos los eventos de integración. Además del tipo y el payload, el sobre identifica el
+// evento y la cadena causal que lo produjo, para que consumidores y auditoría la reconstruyan:
+// CorrelationID es la petición de origen de toda la cadena, CausationID el evento o la petición
+// que provocó directamente este y Actor quién hizo la petición de origen. TenantID es el
+// inquilino al que pertenece el evento (vacío, el inquilino por defecto). PayloadMode es la forma
+// de Data en las altas y actualizaciones; vacío, la entidad completa.
 type IntegrationEvent struct {
-	Type      string          `json:"type"`
-	Timestamp time.Time       `json:"timestamp"`
-	Data      json.RawMessage `json:"data"` // contenido específico del evento
+	EventID       uuid.UUID       `json:"event_id"`
+	Type          string          `json:"type"`
+	Timestamp     time.Time       `json:"timestamp"`   // cuándo se publicó
+	OccurredAt    time.Time       `json:"occurred_at"` // cuándo ocurrió (su alta en el outbox)
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	CausationID   string          `json:"causation_id,omitempty"`
+	Actor         string          `json:"actor,omitempty"`
+	TenantID      string          `json:"tenant_id,omitempty"`
+	PayloadMode   PayloadMode     `json:"payload_mode,omitempty"`
+	Data          json.RawMessage `json:"data"` // contenido específico del evento
+
+	// key es la clave de partición del mensaje y tombstone si borra su agregado; no viajan en
+	// el JSON.
+	key       string
+	tombstone bool
+}
+
+// PayloadMode es la forma del payload de un evento de alta o actualización, configurable por tipo
+// de evento (outbox.payloads). Los borrados llevan siempre solo el id.
+type PayloadMode string
+
+const (
+	// PayloadSnapshot es la entidad completa tras el cambio; es la forma por defecto.
+	PayloadSnapshot PayloadMode = "snapshot"
+	// PayloadDelta son el id, la versión y los campos que cambian. En las altas cambian todos.
+	PayloadDelta PayloadMode = "delta"
+	// PayloadID es solo el id: una notificación tras la que el consumidor lee la entidad si la
+	// necesita.
+	PayloadID PayloadMode = "id"
+)
+
+// Partial indica si el payload lleva solo parte de la entidad. "" es PayloadSnapshot, la forma
+// de los eventos anteriores a los modos.
+func (m PayloadMode) Partial() bool {
+	return m == PayloadDelta || m == PayloadID
+}
+
+// WithPartitionKey devuelve el evento con 'key' como clave de partición, normalmente el id del
+// agregado, para que los eventos de un mismo agregado se consuman en orden.
+func (e IntegrationEvent) WithPartitionKey(key string) IntegrationEvent {
+	e.key = key
+	return e
+}
+
+// PartitionKey devuelve la clave de partición del evento ("" si no tiene).
+func (e IntegrationEvent) PartitionKey() string {
+	return e.key
+}
+
+// EventType devuelve el tipo del evento, para que los publicadores lo enruten a su topic.
+func (e IntegrationEvent) EventType() string {
+	return e.Type
+}
+
+// AsTombstone devuelve el evento marcado como borrado de su agregado: los publicadores de topics
+// compactados lo hacen seguir de un tombstone con su clave de partición.
+func (e IntegrationEvent) AsTombstone() IntegrationEvent {
+	e.tombstone = true
+	return e
+}
+
+// Tombstone indica si el evento borra su agregado (ver AsTombstone).
+func (e IntegrationEvent) Tombstone() bool {
+	return e.tombstone
 }
 
 type EventMetadata struct {
 	Type  reflect.Type
 	Topic string
+	// Version es la versión actual del contrato del evento. Sube cuando el payload cambia de forma
+	// incompatible para los consumidores.
+	Version int
+	// Schema es el JSON Schema del payload. El outbox worker y los publicadores validan contra
+	// él antes de enviar; nil no valida.
+	Schema *Schema
 }
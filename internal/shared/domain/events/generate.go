@@ -0,0 +1,8 @@
+package events
+
+// Los contratos tipados de estos eventos (JSON Schema y TypeScript) para consumidores que no son
+// Go se generan en gen/events con:
+//
+//	go generate ./internal/shared/domain/events
+//
+//go:generate go run ../../../../cmd/eventgen -src . -out ../../../../gen/events
@@ -0,0 +1,360 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSchemaViolation indica que el payload de un evento no cumple el JSON Schema de su tipo: un
+// fallo del productor, que no se arregla reintentando la publicación.
+var ErrSchemaViolation = errors.New("event payload does not match its schema")
+
+// SchemaError enumera los incumplimientos de un payload, uno por campo, con la ruta del campo
+// (ej. "$.email: must be an email"). Es un ErrSchemaViolation.
+type SchemaError struct {
+	EventType  string
+	Violations []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrSchemaViolation, e.EventType, strings.Join(e.Violations, "; "))
+}
+
+func (e *SchemaError) Unwrap() error { return ErrSchemaViolation }
+
+// SchemaRegistry valida el payload de cada tipo de evento contra el JSON Schema que registró.
+// Los tipos sin esquema no se validan.
+type SchemaRegistry interface {
+	Validate(eventType string, payload []byte) error
+	// ValidatePartial valida un payload parcial (ver PayloadMode.Partial): como Validate, pero
+	// sin exigir las propiedades obligatorias del objeto raíz.
+	ValidatePartial(eventType string, payload []byte) error
+}
+
+// Schemas es el SchemaRegistry de un registro de eventos: el esquema de cada tipo es el de su
+// EventMetadata.
+func Schemas(registry map[string]EventMetadata) SchemaRegistry {
+	return registrySchemas(registry)
+}
+
+type registrySchemas map[string]EventMetadata
+
+func (r registrySchemas) Validate(eventType string, payload []byte) error {
+	return r.validate(eventType, payload, (*Schema).Validate)
+}
+
+func (r registrySchemas) ValidatePartial(eventType string, payload []byte) error {
+	return r.validate(eventType, payload, (*Schema).ValidatePartial)
+}
+
+func (r registrySchemas) validate(eventType string, payload []byte, validate func(*Schema, []byte) error) error {
+	metadata, ok := r[eventType]
+	if !ok || metadata.Schema == nil {
+		return nil
+	}
+	if err := validate(metadata.Schema, payload); err != nil {
+		var schemaErr *SchemaError
+		if errors.As(err, &schemaErr) {
+			schemaErr.EventType = eventType
+		}
+		return err
+	}
+	return nil
+}
+
+// Schema es un JSON Schema compilado. Admite el subconjunto que usan los contratos de eventos:
+// type, properties, required, additionalProperties (booleano), items, enum, format (uuid,
+// date-time, date, email), minLength, maxLength, minimum, maximum y pattern. Las anotaciones
+// ($schema, $id, title, description...) se ignoran, salvo examples, que se comprueban contra el
+// propio esquema al compilarlo (ver Example); las palabras que combinan o
+// referencian esquemas ($ref, allOf, anyOf, oneOf, not...) no se admiten y fallan al compilar.
+type Schema struct {
+	types                []string
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *bool
+	items                *Schema
+	enum                 []any
+	format               string
+	minLength, maxLength *int
+	minimum, maximum     *float64
+	pattern              *regexp.Regexp
+	examples             []json.RawMessage
+
+	raw json.RawMessage
+}
+
+// schemaAnnotations son las palabras que no validan nada y se aceptan sin más.
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+var schemaTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+var schemaFormats = []string{"uuid", "date-time", "date", "email"}
+
+// CompileSchema compila un JSON Schema. Un esquema con palabras no admitidas o mal formadas es
+// un error: mejor fallar al registrarlo que validar a medias.
+func CompileSchema(raw []byte) (*Schema, error) {
+	return compileSchema(raw, "$")
+}
+
+// MustCompileSchema es CompileSchema para los esquemas fijos de los registros de eventos: un
+// esquema inválido es un error de programación y entra en pánico.
+func MustCompileSchema(raw string) *Schema {
+	s, err := CompileSchema([]byte(raw))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Example devuelve el primer ejemplo del esquema (la palabra examples), o nil si no tiene.
+func (s *Schema) Example() json.RawMessage {
+	if len(s.examples) == 0 {
+		return nil
+	}
+	return s.examples[0]
+}
+
+// MarshalJSON devuelve el esquema tal como se registró.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	return s.raw, nil
+}
+
+func compileSchema(raw []byte, path string) (*Schema, error) {
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &keywords); err != nil {
+		return nil, fmt.Errorf("schema %s: must be a JSON object: %w", path, err)
+	}
+	s := &Schema{raw: append(json.RawMessage(nil), raw...)}
+
+	for key, value := range keywords {
+		var err error
+		switch key {
+		case "type":
+			err = s.compileType(value)
+		case "properties":
+			var props map[string]json.RawMessage
+			if err = json.Unmarshal(value, &props); err == nil {
+				s.properties = make(map[string]*Schema, len(props))
+				for name, prop := range props {
+					if s.properties[name], err = compileSchema(prop, path+"."+name); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case "required":
+			err = json.Unmarshal(value, &s.required)
+		case "additionalProperties":
+			err = json.Unmarshal(value, &s.additionalProperties)
+		case "items":
+			s.items, err = compileSchema(value, path+"[]")
+			if err != nil {
+				return nil, err
+			}
+		case "enum":
+			err = json.Unmarshal(value, &s.enum)
+		case "format":
+			if err = json.Unmarshal(value, &s.format); err == nil && !slices.Contains(schemaFormats, s.format) {
+				err = fmt.Errorf("unsupported format %q", s.format)
+			}
+		case "minLength":
+			err = json.Unmarshal(value, &s.minLength)
+		case "maxLength":
+			err = json.Unmarshal(value, &s.maxLength)
+		case "minimum":
+			err = json.Unmarshal(value, &s.minimum)
+		case "maximum":
+			err = json.Unmarshal(value, &s.maximum)
+		case "pattern":
+			var expr string
+			if err = json.Unmarshal(value, &expr); err == nil {
+				s.pattern, err = regexp.Compile(expr)
+			}
+		case "examples":
+			err = json.Unmarshal(value, &s.examples)
+		default:
+			if !schemaAnnotations[key] {
+				err = fmt.Errorf("unsupported keyword")
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %s: %w", path, key, err)
+		}
+	}
+
+	// Un ejemplo que no cumple su esquema engañaría a quien lo lea en el catálogo.
+	for i, example := range s.examples {
+		if err := s.Validate(example); err != nil {
+			return nil, fmt.Errorf("schema %s: examples[%d]: %w", path, i, err)
+		}
+	}
+	return s, nil
+}
+
+// compileType lee "type", que puede ser un tipo o una lista de tipos.
+func (s *Schema) compileType(value json.RawMessage) error {
+	var one string
+	if err := json.Unmarshal(value, &one); err == nil {
+		s.types = []string{one}
+	} else if err := json.Unmarshal(value, &s.types); err != nil {
+		return err
+	}
+	for _, t := range s.types {
+		if !slices.Contains(schemaTypes, t) {
+			return fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return nil
+}
+
+// Validate comprueba que 'payload' (un documento JSON) cumple el esquema. Devuelve un
+// *SchemaError con todos los incumplimientos, no solo el primero.
+func (s *Schema) Validate(payload []byte) error {
+	var doc any
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return &SchemaError{Violations: []string{"$: invalid JSON: " + err.Error()}}
+	}
+	var violations []string
+	s.validate(doc, "$", &violations)
+	if len(violations) > 0 {
+		return &SchemaError{Violations: violations}
+	}
+	return nil
+}
+
+// ValidatePartial es Validate para los payloads que llevan solo parte de la entidad: las
+// propiedades presentes deben cumplir el esquema, pero las obligatorias del objeto raíz pueden
+// faltar.
+func (s *Schema) ValidatePartial(payload []byte) error {
+	partial := *s
+	partial.required = nil
+	return partial.Validate(payload)
+}
+
+func (s *Schema) validate(v any, path string, violations *[]string) {
+	fail := func(format string, args ...any) {
+		*violations = append(*violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return hasType(v, t) }) {
+		fail("must be %s", strings.Join(s.types, " or "))
+		return
+	}
+	if len(s.enum) > 0 && !slices.ContainsFunc(s.enum, func(e any) bool { return jsonEqual(e, v) }) {
+		fail("must be one of %s", enumList(s.enum))
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, path+"."+name+": is required")
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names) // Para que los incumplimientos salgan siempre en el mismo orden
+		for _, name := range names {
+			if prop, ok := s.properties[name]; ok {
+				prop.validate(v[name], path+"."+name, violations)
+			} else if s.additionalProperties != nil && !*s.additionalProperties {
+				*violations = append(*violations, path+"."+name+": is not allowed")
+			}
+		}
+	case []any:
+		if s.items != nil {
+			for i, item := range v {
+				s.items.validate(item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	case string:
+		n := len([]rune(v))
+		if s.minLength != nil && n < *s.minLength {
+			fail("must be at least %d characters long", *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			fail("must be at most %d characters long", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("must match %s", s.pattern)
+		}
+		if s.format != "" && !hasFormat(v, s.format) {
+			fail("must be a valid %s", s.format)
+		}
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			fail("must be >= %v", *s.minimum)
+		}
+		if s.maximum != nil && v > *s.maximum {
+			fail("must be <= %v", *s.maximum)
+		}
+	}
+}
+
+// hasType dice si 'v', decodificado de JSON, es del tipo 't' de JSON Schema.
+func hasType(v any, t string) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		return t == "object"
+	case []any:
+		return t == "array"
+	case string:
+		return t == "string"
+	case float64:
+		return t == "number" || (t == "integer" && v == math.Trunc(v))
+	case bool:
+		return t == "boolean"
+	case nil:
+		return t == "null"
+	}
+	return false
+}
+
+func hasFormat(v, format string) bool {
+	switch format {
+	case "uuid":
+		_, err := uuid.Parse(v)
+		return err == nil && len(v) == 36
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, v)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, v)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(v)
+		return err == nil && addr.Address == v
+	}
+	return true
+}
+
+// jsonEqual compara dos valores decodificados de JSON por su representación.
+func jsonEqual(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+func enumList(enum []any) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		b, _ := json.Marshal(e)
+		values[i] = string(b)
+	}
+	return strings.Join(values, ", ")
+}
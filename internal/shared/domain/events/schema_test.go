@@ -0,0 +1,131 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = MustCompileSchema(`{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "pedido",
+	"type": "object",
+	"required": ["id", "status"],
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"status": {"type": "string", "enum": ["open", "closed"]},
+		"email": {"type": ["string", "null"], "format": "email"},
+		"items": {"type": "array", "items": {"type": "integer", "minimum": 1, "maximum": 10}},
+		"code": {"type": "string", "minLength": 2, "maxLength": 4, "pattern": "^[A-Z]+$"}
+	},
+	"additionalProperties": false
+}`)
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    string
+		violations []string
+	}{
+		{
+			name:    "válido",
+			payload: `{"id": "6f1c3a52-9a0e-4d2c-8a4e-2b1f0c9d7e65", "status": "open", "email": null, "items": [1, 10], "code": "AB"}`,
+		},
+		{
+			name:       "campos obligatorios",
+			payload:    `{}`,
+			violations: []string{"$.id: is required", "$.status: is required"},
+		},
+		{
+			name:    "tipos, formatos y límites",
+			payload: `{"id": "1", "status": "lost", "email": "no", "items": [0, 2.5], "code": "abcde", "extra": true}`,
+			violations: []string{
+				`$.code: must be at most 4 characters long`,
+				`$.code: must match ^[A-Z]+$`,
+				`$.email: must be a valid email`,
+				`$.extra: is not allowed`,
+				`$.id: must be a valid uuid`,
+				`$.items[0]: must be >= 1`,
+				`$.items[1]: must be integer`,
+				`$.status: must be one of "open", "closed"`,
+			},
+		},
+		{
+			name:       "no es un objeto",
+			payload:    `[]`,
+			violations: []string{"$: must be object"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testSchema.Validate([]byte(tt.payload))
+
+			if tt.violations == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var schemaErr *SchemaError
+			require.ErrorAs(t, err, &schemaErr)
+			assert.ErrorIs(t, err, ErrSchemaViolation)
+			assert.Equal(t, tt.violations, schemaErr.Violations)
+		})
+	}
+}
+
+func TestCompileSchema_Unsupported(t *testing.T) {
+	for _, raw := range []string{
+		`{"$ref": "#/definitions/x"}`,
+		`{"oneOf": [{"type": "string"}]}`,
+		`{"type": "text"}`,
+		`{"type": "string", "format": "hostname"}`,
+		`{"properties": {"id": {"anyOf": []}}}`,
+		`[]`,
+	} {
+		_, err := CompileSchema([]byte(raw))
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestCompileSchema_Examples(t *testing.T) {
+	schema, err := CompileSchema([]byte(`{"type": "object", "examples": [{"id": 1}]}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": 1}`, string(schema.Example()))
+	assert.Nil(t, testSchema.Example())
+
+	_, err = CompileSchema([]byte(`{"type": "object", "examples": [[]]}`))
+	assert.ErrorIs(t, err, ErrSchemaViolation, "Un ejemplo que no cumple el esquema no compila")
+}
+
+func TestSchema_ValidatePartial(t *testing.T) {
+	// Act
+	missing := testSchema.ValidatePartial([]byte(`{"status": "closed"}`))
+	invalid := testSchema.ValidatePartial([]byte(`{"status": "lost", "extra": true}`))
+
+	// Assert: pueden faltar las obligatorias, pero las presentes se validan igual.
+	assert.NoError(t, missing)
+	var schemaErr *SchemaError
+	require.True(t, errors.As(invalid, &schemaErr))
+	assert.Equal(t, []string{`$.extra: is not allowed`, `$.status: must be one of "open", "closed"`}, schemaErr.Violations)
+	assert.Error(t, testSchema.Validate([]byte(`{"status": "closed"}`)), "Validate sigue exigiéndolas")
+}
+
+func TestSchemas(t *testing.T) {
+	// Arrange
+	schemas := Schemas(map[string]EventMetadata{
+		"order.created": {Topic: "order", Schema: testSchema},
+		"order.noted":   {Topic: "order"},
+	})
+
+	// Act
+	err := schemas.Validate("order.created", []byte(`{"status": "open"}`))
+
+	// Assert: el error lleva el tipo; los tipos sin esquema o desconocidos no se validan.
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	assert.Equal(t, "order.created", schemaErr.EventType)
+	assert.NoError(t, schemas.Validate("order.noted", []byte(`{}`)))
+	assert.NoError(t, schemas.Validate("order.unknown", []byte(`{}`)))
+}
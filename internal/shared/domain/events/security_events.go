@@ -0,0 +1,19 @@
+package events
+
+// AuthFailed es el payload de auth.failed: una petición rechazada por no traer un token válido.
+type AuthFailed struct {
+	Route    string `json:"route"` // Plantilla de la ruta (/users/:id) o, si no hay, la ruta pedida
+	Method   string `json:"method"`
+	Reason   string `json:"reason"` // missing_token o invalid_token
+	Detail   string `json:"detail,omitempty"`
+	ClientIP string `json:"client_ip"`
+}
+
+// AuthzDenied es el payload de authz.denied: un usuario autenticado sin permiso para la ruta.
+type AuthzDenied struct {
+	Route        string `json:"route"`
+	Method       string `json:"method"`
+	Reason       string `json:"reason"` // missing_role
+	RequiredRole string `json:"required_role"`
+	ClientIP     string `json:"client_ip"`
+}
@@ -8,7 +8,8 @@ type TaskCreated struct {
 	ID          uuid.UUID `json:"id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
-	AssigneeID  uuid.UUID `json:"assigneeId"`
+	// AssigneeID es null si la tarea se crea sin asignar.
+	AssigneeID *uuid.UUID `json:"assigneeId"`
 }
 
 type TaskUpdated struct {
@@ -10,14 +10,14 @@ import (
 // Se definen planos para intercambio entre contextos.
 type UserCreated struct {
 	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Nombre    string    `json:"nombre"`
-	BirthDate time.Time `json:"birth_date"`
+	Email     string    `json:"email" log:"redact"`
+	Nombre    string    `json:"nombre" log:"redact"`
+	BirthDate time.Time `json:"birth_date" log:"redact"`
 }
 
 type UserUpdated struct {
 	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Nombre    string    `json:"nombre"`
-	BirthDate time.Time `json:"birth_date"`
+	Email     string    `json:"email" log:"redact"`
+	Nombre    string    `json:"nombre" log:"redact"`
+	BirthDate time.Time `json:"birth_date" log:"redact"`
 }
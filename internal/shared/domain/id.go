@@ -0,0 +1,18 @@
+package domain
+
+import "github.com/google/uuid"
+
+// IDGenerator es el puerto para generar identificadores de agregados y de eventos del outbox.
+// Los servicios lo reciben en su constructor en lugar de llamar a uuid.New, para que los tests
+// conozcan los IDs de antemano.
+type IDGenerator interface {
+	NewID() uuid.UUID
+}
+
+// RandomIDGenerator genera UUID v4 aleatorios con crypto/rand.
+type RandomIDGenerator struct{}
+
+func (RandomIDGenerator) NewID() uuid.UUID { return uuid.New() }
+
+// Verificación estática
+var _ IDGenerator = RandomIDGenerator{}
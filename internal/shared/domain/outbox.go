@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"bytes"
 	"context"
 	"time"
 
@@ -16,6 +17,47 @@ type OutboxEvent struct {
 	Payload       interface{} `json:"payload"`    // JSON serializable
 	CreatedAt     time.Time   `json:"created_at"`
 	Processed     bool        `json:"processed"` // si ya se publicó
+	// Metadata guarda las cabeceras de traza (correlation-id, traceparent) de la petición que
+	// originó el evento, para propagarlas al publicarlo.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// MetadataTombstone marca en Metadata los eventos que borran su agregado: en los topics
+// compactados se publican seguidos de un tombstone con la clave del agregado.
+const MetadataTombstone = "tombstone"
+
+// IsTombstone indica si el evento borra su agregado (ver MetadataTombstone).
+func (e OutboxEvent) IsTombstone() bool {
+	return e.Metadata[MetadataTombstone] == "true"
+}
+
+// MetadataPayloadMode marca en Metadata la forma del payload ("delta" o "id") de los eventos
+// que no llevan la entidad completa; sin ella, el payload es la entidad completa.
+const MetadataPayloadMode = "payload-mode"
+
+// OutboxPosition es la posición de un evento en el change feed, que ordena los eventos por su
+// alta y, con la misma fecha, por id.
+type OutboxPosition struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Position devuelve la posición del evento en el change feed.
+func (e OutboxEvent) Position() OutboxPosition {
+	return OutboxPosition{CreatedAt: e.CreatedAt, ID: e.ID}
+}
+
+// IsZero indica si es la posición anterior a todos los eventos.
+func (p OutboxPosition) IsZero() bool {
+	return p.CreatedAt.IsZero() && p.ID == uuid.Nil
+}
+
+// Before indica si 'p' va antes que 'other' en el change feed.
+func (p OutboxPosition) Before(other OutboxPosition) bool {
+	if !p.CreatedAt.Equal(other.CreatedAt) {
+		return p.CreatedAt.Before(other.CreatedAt)
+	}
+	return bytes.Compare(p.ID[:], other.ID[:]) < 0
 }
 
 // OutboxRepository define el contrato para acceder a la tabla outbox.
@@ -25,3 +67,69 @@ type OutboxRepository interface {
 	FetchPendingOutbox(ctx context.Context, limit int) ([]OutboxEvent, error)
 	MarkOutboxProcessed(ctx context.Context, id uuid.UUID) error
 }
+
+// OutboxBacklogCounter lo implementan los outbox que saben contar sus eventos pendientes.
+// Queda fuera de OutboxRepository porque el worker no lo necesita; se usa para métricas.
+type OutboxBacklogCounter interface {
+	CountPendingOutbox(ctx context.Context) (int, error)
+}
+
+// OutboxDeadLetterer lo implementan los outbox que pueden apartar un evento que nunca se podrá
+// publicar (ej. su payload no cumple el esquema): deja de estar pendiente y guarda el motivo.
+// El worker lo usa si está disponible; si no, el evento sigue pendiente.
+type OutboxDeadLetterer interface {
+	MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error
+}
+
+// DeadLetter es un evento apartado con MarkOutboxDeadLetter y el motivo.
+type DeadLetter struct {
+	OutboxEvent
+	Reason string `json:"reason"`
+}
+
+// OutboxDeadLetterStore lo implementan los outbox que conservan sus dead letters y permiten
+// revisarlas. ListDeadLetters devuelve hasta 'limit' (todas si es negativo), de la más antigua a
+// la más reciente. RequeueDeadLetters las devuelve a pendientes, sin motivo, para que el relayer
+// las vuelva a intentar en el siguiente polling, y PurgeDeadLetters las borra. Ambos actúan
+// sobre 'ids' (sobre todas si está vacío), ignoran los que no son dead letters y devuelven
+// cuántas cambiaron.
+type OutboxDeadLetterStore interface {
+	ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error)
+	RequeueDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error)
+	PurgeDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error)
+}
+
+// OutboxStats resume un outbox por tipo de evento: los eventos pendientes de publicar y los
+// apartados como dead letter.
+type OutboxStats struct {
+	Pending     map[string]int `json:"pending"`
+	DeadLetters map[string]int `json:"dead_letters"`
+}
+
+// NewOutboxStats crea un resumen vacío.
+func NewOutboxStats() OutboxStats {
+	return OutboxStats{Pending: map[string]int{}, DeadLetters: map[string]int{}}
+}
+
+// Add suma 'n' eventos de 'eventType', pendientes o apartados según 'deadLetter'.
+func (s OutboxStats) Add(eventType string, deadLetter bool, n int) {
+	if deadLetter {
+		s.DeadLetters[eventType] += n
+	} else {
+		s.Pending[eventType] += n
+	}
+}
+
+// OutboxChangeReader lo implementan los outbox que conservan los eventos publicados, para servir
+// el change feed. ReadChanges devuelve hasta 'limit' eventos del inquilino de ctx, publicados o
+// no, que van detrás de 'after' (desde el primero con la posición cero), en el orden de
+// OutboxPosition. Las dead letters no forman parte del feed.
+type OutboxChangeReader interface {
+	ReadChanges(ctx context.Context, after OutboxPosition, limit int) ([]OutboxEvent, error)
+}
+
+// OutboxStatsReader lo implementan los outbox que saben resumir su contenido por tipo de
+// evento. Como OutboxBacklogCounter, queda fuera de OutboxRepository; lo usa /admin/stats.
+type OutboxStatsReader interface {
+	OutboxStats(ctx context.Context) (OutboxStats, error)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// ---------------- Criterios de tiempo relativo ----------------
+
+// CreatedWithinCriteria busca los registros creados en la última 'Duration' (ej. 24h), contada
+// desde la hora de 'Clock'. Sin Clock se usa la hora del sistema.
+type CreatedWithinCriteria struct {
+	Duration time.Duration
+	Clock    Clock
+}
+
+func (c CreatedWithinCriteria) ToConditions() []Criterion {
+	return []Criterion{{Field: "created_at", Op: OpGte, Value: relativeTo(c.Clock, c.Duration)}}
+}
+
+// UpdatedBeforeCriteria busca los registros que no se han modificado en la última 'Duration'
+// (ej. tareas sin tocar en 72h), contada desde la hora de 'Clock'. Sin Clock se usa la hora del
+// sistema.
+type UpdatedBeforeCriteria struct {
+	Duration time.Duration
+	Clock    Clock
+}
+
+func (c UpdatedBeforeCriteria) ToConditions() []Criterion {
+	return []Criterion{{Field: "updated_at", Op: OpLt, Value: relativeTo(c.Clock, c.Duration)}}
+}
+
+// relativeTo devuelve el instante de hace 'd' según 'clock', en UTC como el resto de fechas.
+func relativeTo(clock Clock, d time.Duration) time.Time {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return clock.Now().UTC().Add(-d)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedClock es un Clock parado en una hora, para los tests.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time                 { return time.Time(c) }
+func (c fixedClock) NewTicker(time.Duration) Ticker { return nil }
+
+func TestRelativeTimeCriteria(t *testing.T) {
+	// Arrange: la hora del reloj inyectado, en otra zona horaria.
+	madrid := time.FixedZone("CET", 3600)
+	clock := fixedClock(time.Date(2025, 3, 10, 13, 0, 0, 0, madrid))
+
+	// Act
+	created := CreatedWithinCriteria{Duration: 24 * time.Hour, Clock: clock}.ToConditions()
+	updated := UpdatedBeforeCriteria{Duration: 72 * time.Hour, Clock: clock}.ToConditions()
+
+	// Assert: los límites se calculan desde el reloj y en UTC.
+	assert.Equal(t, []Criterion{{Field: "created_at", Op: OpGte, Value: time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC)}}, created)
+	assert.Equal(t, []Criterion{{Field: "updated_at", Op: OpLt, Value: time.Date(2025, 3, 7, 12, 0, 0, 0, time.UTC)}}, updated)
+}
+
+func TestRelativeTimeCriteria_SystemClockByDefault(t *testing.T) {
+	before := time.Now().UTC().Add(-time.Hour)
+
+	got := CreatedWithinCriteria{Duration: time.Hour}.ToConditions()[0].Value.(time.Time)
+
+	assert.False(t, got.Before(before))
+	assert.WithinDuration(t, before, got, time.Second)
+}
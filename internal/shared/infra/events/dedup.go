@@ -0,0 +1,78 @@
+package events
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// Deduplicator recuerda los ids de los últimos eventos consumidos para descartar las entregas
+// repetidas dentro de una ventana de tiempo: Kafka entrega al menos una vez y un rebalanceo o un
+// reintento del productor repite mensajes. Es una LRU acotada en memoria, así que no sobrevive a
+// un reinicio ni se comparte entre réplicas; solo absorbe las repeticiones baratas.
+type Deduplicator struct {
+	mu      sync.Mutex
+	size    int
+	window  time.Duration
+	clock   sharedDomain.Clock
+	order   *list.List // Más reciente delante
+	entries map[string]*list.Element
+}
+
+type dedupEntry struct {
+	id     string
+	seenAt time.Time
+}
+
+// NewDeduplicator recuerda como mucho 'size' eventos, cada uno durante 'window'.
+func NewDeduplicator(size int, window time.Duration, clock sharedDomain.Clock) *Deduplicator {
+	return &Deduplicator{
+		size:    size,
+		window:  window,
+		clock:   clock,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// Seen registra el evento 'id' y dice si ya se había visto dentro de la ventana. La ventana
+// cuenta desde la primera vez que se vio, no desde la última repetición.
+func (d *Deduplicator) Seen(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	if elem, ok := d.entries[id]; ok {
+		d.order.MoveToFront(elem)
+		entry := elem.Value.(*dedupEntry)
+		if now.Sub(entry.seenAt) < d.window {
+			return true
+		}
+		entry.seenAt = now
+		return false
+	}
+
+	d.entries[id] = d.order.PushFront(&dedupEntry{id: id, seenAt: now})
+	if d.order.Len() > d.size {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.entries, oldest.Value.(*dedupEntry).id)
+	}
+	return false
+}
+
+// envelopeEventID devuelve el event_id del sobre IntegrationEvent del mensaje, o "" si no lo lleva.
+func envelopeEventID(payload []byte) string {
+	var envelope struct {
+		EventID uuid.UUID `json:"event_id"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil || envelope.EventID == uuid.Nil {
+		return ""
+	}
+	return envelope.EventID.String()
+}
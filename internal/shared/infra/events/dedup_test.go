@@ -0,0 +1,72 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// stepClock es un reloj que solo avanza a mano (tests/mocks importa este paquete).
+type stepClock struct {
+	sharedDomain.SystemClock
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func TestDeduplicator_Window(t *testing.T) {
+	// Arrange
+	clock := &stepClock{now: time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)}
+	dedup := NewDeduplicator(10, time.Minute, clock)
+
+	// Act
+	first := dedup.Seen("evt-1")
+	clock.now = clock.now.Add(30 * time.Second)
+	repeated := dedup.Seen("evt-1")
+	clock.now = clock.now.Add(31 * time.Second)
+	afterWindow := dedup.Seen("evt-1")
+
+	// Assert
+	assert.False(t, first)
+	assert.True(t, repeated)
+	assert.False(t, afterWindow, "La ventana cuenta desde la primera vez que se vio")
+}
+
+func TestDeduplicator_EvictsLeastRecentlySeen(t *testing.T) {
+	// Arrange
+	dedup := NewDeduplicator(2, time.Hour, &stepClock{now: time.Now()})
+	dedup.Seen("evt-1")
+	dedup.Seen("evt-2")
+	dedup.Seen("evt-1") // evt-2 pasa a ser el menos reciente
+
+	// Act
+	dedup.Seen("evt-3")
+
+	// Assert
+	assert.True(t, dedup.Seen("evt-1"))
+	assert.False(t, dedup.Seen("evt-2"), "Se olvidó al superar el tamaño")
+}
+
+func TestConsumerAdapter_Duplicate(t *testing.T) {
+	// Arrange
+	id := uuid.New()
+	payload := []byte(fmt.Sprintf(`{"event_id": %q, "type": "user.created"}`, id))
+	adapter := &ConsumerAdapter{}
+	withDedup := &ConsumerAdapter{dedup: NewDeduplicator(10, time.Minute, &stepClock{now: time.Now()})}
+
+	// Act
+	firstDelivery := withDedup.duplicate(payload)
+	redelivery := withDedup.duplicate(payload)
+
+	// Assert
+	assert.False(t, adapter.duplicate(payload), "Sin deduplicador no se descarta nada")
+	assert.False(t, firstDelivery)
+	assert.True(t, redelivery)
+	assert.False(t, withDedup.duplicate([]byte(`{"type": "legacy"}`)))
+	assert.False(t, withDedup.duplicate([]byte(`{"type": "legacy"}`)), "Sin event_id no se descarta")
+}
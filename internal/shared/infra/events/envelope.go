@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// EnvelopeContext asocia a ctx la cadena causal del evento recibido: lo que haga el consumidor
+// al tratarlo, incluidos los eventos que genere, queda causado por él y conserva el
+// correlation-id y el actor de la petición de origen. Los que ya traiga ctx (de las cabeceras
+// del mensaje) mandan sobre los del sobre. El inquilino también se conserva.
+func EnvelopeContext(ctx context.Context, evt sharedEvents.IntegrationEvent) context.Context {
+	tc, _ := tracing.FromContext(ctx)
+	if tc.CorrelationID == "" {
+		tc.CorrelationID = evt.CorrelationID
+	}
+	if tc.Actor == "" {
+		tc.Actor = evt.Actor
+	}
+	if tc.TenantID == "" {
+		tc.TenantID = evt.TenantID
+	}
+	if evt.EventID != uuid.Nil {
+		tc.CausationID = evt.EventID.String()
+	}
+	if tc.IsZero() {
+		return ctx
+	}
+	return tracing.NewContext(ctx, tc)
+}
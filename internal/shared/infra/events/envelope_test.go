@@ -0,0 +1,38 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func TestEnvelopeContext(t *testing.T) {
+	// Arrange
+	evt := sharedEvents.IntegrationEvent{
+		EventID:       uuid.New(),
+		CorrelationID: "req-1",
+		CausationID:   "req-0",
+		Actor:         "ana",
+		TenantID:      "acme",
+	}
+	fromHeaders := tracing.New()
+
+	// Act
+	bare, _ := tracing.FromContext(EnvelopeContext(context.Background(), evt))
+	withTrace, _ := tracing.FromContext(EnvelopeContext(tracing.NewContext(context.Background(), fromHeaders), evt))
+
+	// Assert
+	assert.Equal(t, "req-1", bare.CorrelationID)
+	assert.Equal(t, "ana", bare.Actor)
+	assert.Equal(t, "acme", bare.TenantID)
+	assert.Equal(t, evt.EventID.String(), bare.CausationID, "Lo que se haga al tratarlo lo causa este evento")
+
+	assert.Equal(t, fromHeaders.CorrelationID, withTrace.CorrelationID, "Las cabeceras mandan")
+	assert.Equal(t, fromHeaders.TraceID, withTrace.TraceID)
+	assert.Equal(t, evt.EventID.String(), withTrace.CausationID)
+}
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"go.uber.org/zap"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// feedBuffer es cuántos eventos puede tener pendientes cada suscriptor de un Feed; los que
+// lleguen mientras está lleno se le descartan.
+const feedBuffer = 64
+
+// Feed reparte entre los suscriptores de este proceso (p.ej. las suscripciones GraphQL) los
+// eventos que recibe de un consumidor del bus. Cada suscriptor recibe solo los de su topic y su
+// inquilino, y uno que no lee a tiempo pierde eventos en lugar de frenar al resto.
+type Feed struct {
+	mu   sync.Mutex
+	subs map[*feedSubscriber]struct{}
+	log  *zap.Logger
+}
+
+type feedSubscriber struct {
+	topic  string
+	tenant string
+	ch     chan sharedEvents.IntegrationEvent
+}
+
+func NewFeed(log *zap.Logger) *Feed {
+	return &Feed{subs: make(map[*feedSubscriber]struct{}), log: log}
+}
+
+// Handler devuelve el MessageHandler que entrega al feed los mensajes de 'topic'.
+func (f *Feed) Handler(topic string) MessageHandler {
+	return feedHandler{feed: f, topic: topic}
+}
+
+// Subscribe devuelve los eventos de 'topic' del inquilino de ctx que lleguen desde ahora. El
+// canal se cierra al cancelar ctx.
+func (f *Feed) Subscribe(ctx context.Context, topic string) <-chan sharedEvents.IntegrationEvent {
+	sub := &feedSubscriber{
+		topic:  topic,
+		tenant: tracing.TenantID(ctx),
+		ch:     make(chan sharedEvents.IntegrationEvent, feedBuffer),
+	}
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.subs, sub)
+		close(sub.ch)
+	}()
+	return sub.ch
+}
+
+func (f *Feed) publish(ctx context.Context, topic string, payload []byte) {
+	var evt sharedEvents.IntegrationEvent
+	if err := json.Unmarshal(payload, &evt); err != nil {
+		tracing.Logger(ctx, f.log).Debug("Evento no válido descartado del feed", zap.String("topic", topic), zap.Error(err))
+		return
+	}
+	tenant := tracing.TenantID(EnvelopeContext(ctx, evt))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subs {
+		if sub.topic != topic || sub.tenant != tenant {
+			continue
+		}
+		select {
+		case sub.ch <- evt:
+		default:
+			f.log.Debug("Suscriptor lento, evento descartado", zap.String("topic", topic), zap.String("type", evt.Type))
+		}
+	}
+}
+
+type feedHandler struct {
+	feed  *Feed
+	topic string
+}
+
+func (h feedHandler) HandleMessage(ctx context.Context, _ string, payload []byte) {
+	h.feed.publish(ctx, h.topic, payload)
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func feedPayload(t *testing.T, evtType, tenant string) []byte {
+	t.Helper()
+	payload, err := json.Marshal(sharedEvents.IntegrationEvent{Type: evtType, TenantID: tenant, Data: json.RawMessage(`{}`)})
+	require.NoError(t, err)
+	return payload
+}
+
+func TestFeed_DeliversByTopicAndTenant(t *testing.T) {
+	// Arrange
+	feed := NewFeed(zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	acme := tracing.NewContext(ctx, tracing.Context{TenantID: "acme"})
+	users := feed.Subscribe(ctx, "user")
+	acmeUsers := feed.Subscribe(acme, "user")
+	tasks := feed.Subscribe(ctx, "task")
+
+	// Act
+	feed.Handler("user").HandleMessage(context.Background(), "", feedPayload(t, "user.created", ""))
+	feed.Handler("user").HandleMessage(context.Background(), "", feedPayload(t, "user.updated", "acme"))
+	feed.Handler("user").HandleMessage(context.Background(), "", []byte("not json"))
+	cancel()
+
+	// Assert: el canal se cierra al cancelar el contexto, tras los eventos ya entregados.
+	var got []string
+	for evt := range users {
+		got = append(got, evt.Type)
+	}
+	assert.Equal(t, []string{"user.created"}, got)
+	got = nil
+	for evt := range acmeUsers {
+		got = append(got, evt.Type)
+	}
+	assert.Equal(t, []string{"user.updated"}, got)
+	_, open := <-tasks
+	assert.False(t, open)
+}
+
+func TestFeed_DropsEventsForSlowSubscribers(t *testing.T) {
+	// Arrange
+	feed := NewFeed(zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	events := feed.Subscribe(ctx, "task")
+
+	// Act
+	for i := 0; i < feedBuffer+10; i++ {
+		feed.Handler("task").HandleMessage(context.Background(), "", feedPayload(t, "task.created", ""))
+	}
+	cancel()
+
+	// Assert
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, feedBuffer, received)
+}
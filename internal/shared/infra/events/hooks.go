@@ -0,0 +1,205 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// ErrSkipEvent lo devuelven BeforePublish y BeforeHandle para descartar un evento sin que sea un
+// error: no se publica (y Publish devuelve nil) o no llega al handler.
+var ErrSkipEvent = errors.New("event skipped by hook")
+
+// BeforePublishHook se ejecuta antes de publicar cada evento. Devuelve el evento que se publica,
+// que puede ser otro (p.ej. enriquecido o con el inquilino sellado), o ErrSkipEvent para
+// filtrarlo; cualquier otro error impide la publicación y es el resultado de Publish.
+type BeforePublishHook interface {
+	BeforePublish(ctx context.Context, event interface{}) (interface{}, error)
+}
+
+// AfterPublishHook se ejecuta con el resultado de la entrega de cada evento que llega a
+// publicarse, o con el error de un BeforePublish.
+type AfterPublishHook interface {
+	AfterPublish(ctx context.Context, event interface{}, err error)
+}
+
+// BeforeHandleHook se ejecuta antes de entregar cada mensaje a un consumidor. Devuelve el
+// contexto y el payload con los que se entrega, o ErrSkipEvent para descartarlo; con cualquier
+// otro error el mensaje también se descarta, pero queda en el log.
+type BeforeHandleHook interface {
+	BeforeHandle(ctx context.Context, key string, payload []byte) (context.Context, []byte, error)
+}
+
+// AfterHandleHook se ejecuta cuando el consumidor termina con cada mensaje que se le entregó,
+// con el contexto y el payload que devolvieron los BeforeHandle.
+type AfterHandleHook interface {
+	AfterHandle(ctx context.Context, key string, payload []byte)
+}
+
+// Hooks es el registro de plugins del bus: funcionalidades transversales (enriquecimiento,
+// filtrado, sellado del inquilino, métricas...) que se aplican a todos los publicadores y
+// consumidores envueltos con NewHookedPublisher y HookHandler sin tocar cada uno. Los hooks se
+// ejecutan en el orden en que se registran y se pueden registrar en cualquier momento.
+type Hooks struct {
+	mu            sync.RWMutex
+	beforePublish []BeforePublishHook
+	afterPublish  []AfterPublishHook
+	beforeHandle  []BeforeHandleHook
+	afterHandle   []AfterHandleHook
+}
+
+func NewHooks() *Hooks {
+	return &Hooks{}
+}
+
+// Register añade 'plugin' a los puntos del bus cuyas interfaces implementa (BeforePublishHook,
+// AfterPublishHook, BeforeHandleHook, AfterHandleHook). Falla si no implementa ninguna.
+func (h *Hooks) Register(plugin interface{}) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	registered := false
+	if hook, ok := plugin.(BeforePublishHook); ok {
+		h.beforePublish = append(h.beforePublish, hook)
+		registered = true
+	}
+	if hook, ok := plugin.(AfterPublishHook); ok {
+		h.afterPublish = append(h.afterPublish, hook)
+		registered = true
+	}
+	if hook, ok := plugin.(BeforeHandleHook); ok {
+		h.beforeHandle = append(h.beforeHandle, hook)
+		registered = true
+	}
+	if hook, ok := plugin.(AfterHandleHook); ok {
+		h.afterHandle = append(h.afterHandle, hook)
+		registered = true
+	}
+	if !registered {
+		return fmt.Errorf("bus hook %T implements no hook interface", plugin)
+	}
+	return nil
+}
+
+// beforePublishing pasa 'event' por los BeforePublish; se detiene en el primer error.
+func (h *Hooks) beforePublishing(ctx context.Context, event interface{}) (interface{}, error) {
+	h.mu.RLock()
+	hooks := h.beforePublish
+	h.mu.RUnlock()
+	for _, hook := range hooks {
+		var err error
+		if event, err = hook.BeforePublish(ctx, event); err != nil {
+			return event, err
+		}
+	}
+	return event, nil
+}
+
+func (h *Hooks) afterPublishing(ctx context.Context, event interface{}, err error) {
+	h.mu.RLock()
+	hooks := h.afterPublish
+	h.mu.RUnlock()
+	for _, hook := range hooks {
+		hook.AfterPublish(ctx, event, err)
+	}
+}
+
+// beforeHandling pasa el mensaje por los BeforeHandle; se detiene en el primer error.
+func (h *Hooks) beforeHandling(ctx context.Context, key string, payload []byte) (context.Context, []byte, error) {
+	h.mu.RLock()
+	hooks := h.beforeHandle
+	h.mu.RUnlock()
+	for _, hook := range hooks {
+		var err error
+		if ctx, payload, err = hook.BeforeHandle(ctx, key, payload); err != nil {
+			return ctx, payload, err
+		}
+	}
+	return ctx, payload, nil
+}
+
+func (h *Hooks) afterHandling(ctx context.Context, key string, payload []byte) {
+	h.mu.RLock()
+	hooks := h.afterHandle
+	h.mu.RUnlock()
+	for _, hook := range hooks {
+		hook.AfterHandle(ctx, key, payload)
+	}
+}
+
+// hookedPublisher decora un EventBus ejecutando los hooks de publicación de un registro.
+type hookedPublisher struct {
+	next  sharedBus.EventBus
+	hooks *Hooks
+}
+
+// NewHookedPublisher envuelve 'next' para ejecutar los BeforePublish y AfterPublish de 'hooks'.
+func NewHookedPublisher(next sharedBus.EventBus, hooks *Hooks) sharedBus.EventBus {
+	return &hookedPublisher{next: next, hooks: hooks}
+}
+
+func (p *hookedPublisher) Publish(ctx context.Context, event interface{}) error {
+	event, err := p.hooks.beforePublishing(ctx, event)
+	if errors.Is(err, ErrSkipEvent) {
+		return nil
+	}
+	if err == nil {
+		err = p.next.Publish(ctx, event)
+	}
+	p.hooks.afterPublishing(ctx, event, err)
+	return err
+}
+
+// PublishAsync ejecuta los AfterPublish cuando se conoce el resultado de la entrega.
+func (p *hookedPublisher) PublishAsync(ctx context.Context, event interface{}, done func(error)) {
+	event, err := p.hooks.beforePublishing(ctx, event)
+	if errors.Is(err, ErrSkipEvent) {
+		done(nil)
+		return
+	}
+	if err != nil {
+		p.hooks.afterPublishing(ctx, event, err)
+		done(err)
+		return
+	}
+	sharedBus.PublishAsync(ctx, p.next, event, func(err error) {
+		p.hooks.afterPublishing(ctx, event, err)
+		done(err)
+	})
+}
+
+// hookedHandler decora un MessageHandler ejecutando los hooks de consumo de un registro.
+type hookedHandler struct {
+	next  MessageHandler
+	hooks *Hooks
+	log   *zap.Logger
+}
+
+// HookHandler envuelve 'next' para ejecutar los BeforeHandle y AfterHandle de 'hooks'.
+func HookHandler(next MessageHandler, hooks *Hooks, log *zap.Logger) MessageHandler {
+	return &hookedHandler{next: next, hooks: hooks, log: log}
+}
+
+func (h *hookedHandler) HandleMessage(ctx context.Context, key string, payload []byte) {
+	ctx, payload, err := h.hooks.beforeHandling(ctx, key, payload)
+	if err != nil {
+		if !errors.Is(err, ErrSkipEvent) {
+			tracing.Logger(ctx, h.log).Warn("Mensaje descartado por un hook del bus", zap.String("key", key), zap.Error(err))
+		}
+		return
+	}
+	h.next.HandleMessage(ctx, key, payload)
+	h.hooks.afterHandling(ctx, key, payload)
+}
+
+// Verificación estática
+var (
+	_ sharedBus.EventBus       = (*hookedPublisher)(nil)
+	_ sharedBus.AsyncPublisher = (*hookedPublisher)(nil)
+	_ MessageHandler           = (*hookedHandler)(nil)
+)
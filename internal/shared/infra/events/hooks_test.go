@@ -0,0 +1,141 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+)
+
+// actorStamper sella el actor de los IntegrationEvent y descarta los de tipo "item.ignored".
+type actorStamper struct{}
+
+func (actorStamper) BeforePublish(_ context.Context, event interface{}) (interface{}, error) {
+	evt, ok := event.(sharedEvents.IntegrationEvent)
+	if !ok {
+		return event, nil
+	}
+	if evt.Type == "item.ignored" {
+		return nil, ErrSkipEvent
+	}
+	evt.Actor = "stamped"
+	return evt, nil
+}
+
+// publishRecorder anota el resultado de cada publicación.
+type publishRecorder struct{ results []error }
+
+func (r *publishRecorder) AfterPublish(_ context.Context, _ interface{}, err error) {
+	r.results = append(r.results, err)
+}
+
+type ctxKey struct{}
+
+// handleRecorder marca el contexto, descarta los payload vacíos y anota los que se entregan.
+type handleRecorder struct{ handled []string }
+
+func (r *handleRecorder) BeforeHandle(ctx context.Context, key string, payload []byte) (context.Context, []byte, error) {
+	switch string(payload) {
+	case "":
+		return ctx, payload, ErrSkipEvent
+	case "broken":
+		return ctx, payload, errors.New("broken payload")
+	}
+	return context.WithValue(ctx, ctxKey{}, key), append([]byte("hooked:"), payload...), nil
+}
+
+func (r *handleRecorder) AfterHandle(_ context.Context, _ string, payload []byte) {
+	r.handled = append(r.handled, string(payload))
+}
+
+// messageRecorder es un MessageHandler que anota lo que recibe.
+type messageRecorder struct{ messages []string }
+
+func (r *messageRecorder) HandleMessage(ctx context.Context, _ string, payload []byte) {
+	r.messages = append(r.messages, ctx.Value(ctxKey{}).(string)+"="+string(payload))
+}
+
+func TestHookedPublisher(t *testing.T) {
+	// Arrange
+	bus := NewInMemoryEventBus("test-topic")
+	ch := bus.Subscribe(10)
+	hooks := NewHooks()
+	recorder := &publishRecorder{}
+	require.NoError(t, hooks.Register(actorStamper{}))
+	require.NoError(t, hooks.Register(recorder))
+	pub := NewHookedPublisher(bus, hooks)
+
+	// Act
+	stampedErr := pub.Publish(context.Background(), sharedEvents.IntegrationEvent{Type: "item.created", Actor: "ada"})
+	skippedErr := pub.Publish(context.Background(), sharedEvents.IntegrationEvent{Type: "item.ignored"})
+	var asyncErr error
+	pub.(sharedBus.AsyncPublisher).PublishAsync(context.Background(), sharedEvents.IntegrationEvent{Type: "item.ignored"}, func(err error) { asyncErr = err })
+
+	// Assert: el descartado no se publica ni se anota
+	require.NoError(t, stampedErr)
+	require.NoError(t, skippedErr)
+	require.NoError(t, asyncErr)
+	assert.Equal(t, []error{nil}, recorder.results)
+	assert.Eventually(t, func() bool { return bus.QueueDepth() == 1 }, time.Second, 5*time.Millisecond)
+	var published sharedEvents.IntegrationEvent
+	require.NoError(t, json.Unmarshal((<-ch).([]byte), &published))
+	assert.Equal(t, "stamped", published.Actor)
+}
+
+func TestHookedPublisher_HookErrorStopsPublishing(t *testing.T) {
+	// Arrange
+	bus := NewInMemoryEventBus("test-topic")
+	hooks := NewHooks()
+	recorder := &publishRecorder{}
+	failure := errors.New("enrichment failed")
+	require.NoError(t, hooks.Register(failingHook{failure}))
+	require.NoError(t, hooks.Register(recorder))
+
+	// Act
+	err := NewHookedPublisher(bus, hooks).Publish(context.Background(), sharedEvents.IntegrationEvent{Type: "item.created"})
+
+	// Assert
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []error{failure}, recorder.results)
+	assert.Zero(t, bus.QueueDepth())
+}
+
+type failingHook struct{ err error }
+
+func (h failingHook) BeforePublish(_ context.Context, event interface{}) (interface{}, error) {
+	return event, h.err
+}
+
+func TestHookHandler(t *testing.T) {
+	// Arrange
+	hooks := NewHooks()
+	hook := &handleRecorder{}
+	require.NoError(t, hooks.Register(hook))
+	next := &messageRecorder{}
+	handler := HookHandler(next, hooks, zap.NewNop())
+
+	// Act
+	handler.HandleMessage(context.Background(), "k1", []byte("a"))
+	handler.HandleMessage(context.Background(), "k2", nil)
+	handler.HandleMessage(context.Background(), "k3", []byte("broken"))
+
+	// Assert: solo se entrega el primero, con el contexto y el payload del hook
+	assert.Equal(t, []string{"k1=hooked:a"}, next.messages)
+	assert.Equal(t, []string{"hooked:a"}, hook.handled)
+}
+
+func TestHooks_RegisterRejectsPluginsWithoutHooks(t *testing.T) {
+	// Act
+	err := NewHooks().Register(struct{}{})
+
+	// Assert
+	assert.Error(t, err)
+}
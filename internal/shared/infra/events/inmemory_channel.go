@@ -55,6 +55,18 @@ func (b *InMemoryEventBus) distribute(subs []chan interface{}, event interface{}
 	}
 }
 
+// QueueDepth devuelve los eventos encolados en los suscriptores que aún no se han consumido.
+func (b *InMemoryEventBus) QueueDepth() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	depth := 0
+	for _, subChan := range b.subscribers {
+		depth += len(subChan)
+	}
+	return depth
+}
+
 // Subscribe suscribe un nuevo oyente a este bus.
 // Ya no necesita el parámetro 'bufferSize' si no se va a configurar dinámicamente.
 func (b *InMemoryEventBus) Subscribe(bufferSize int) <-chan interface{} {
@@ -0,0 +1,26 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryEventBus_QueueDepth(t *testing.T) {
+	// Arrange
+	bus := NewInMemoryEventBus("test-topic")
+	first := bus.Subscribe(10)
+	bus.Subscribe(10)
+
+	// Act: cada evento se encola en los dos suscriptores (la distribución es asíncrona).
+	require.NoError(t, bus.Publish(context.Background(), map[string]string{"id": "1"}))
+	require.NoError(t, bus.Publish(context.Background(), map[string]string{"id": "2"}))
+
+	// Assert
+	assert.Eventually(t, func() bool { return bus.QueueDepth() == 4 }, time.Second, 5*time.Millisecond)
+	<-first
+	assert.Equal(t, 3, bus.QueueDepth())
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+)
+
+// instrumentedPublisher decora un EventBus contando los mensajes publicados en /metrics.
+type instrumentedPublisher struct {
+	next  sharedBus.EventBus
+	bus   string
+	topic string
+}
+
+// NewInstrumentedPublisher envuelve 'next'; 'bus' (kafka, memory) y 'topic' etiquetan sus métricas.
+func NewInstrumentedPublisher(next sharedBus.EventBus, bus, topic string) sharedBus.EventBus {
+	return &instrumentedPublisher{next: next, bus: bus, topic: topic}
+}
+
+func (p *instrumentedPublisher) Publish(ctx context.Context, event interface{}) error {
+	err := p.next.Publish(ctx, event)
+	metrics.ObserveBusMessage(p.bus, p.topic, metrics.DirectionPublished, err)
+	return err
+}
+
+// PublishAsync cuenta el mensaje cuando se conoce el resultado de la entrega.
+func (p *instrumentedPublisher) PublishAsync(ctx context.Context, event interface{}, done func(error)) {
+	sharedBus.PublishAsync(ctx, p.next, event, func(err error) {
+		metrics.ObserveBusMessage(p.bus, p.topic, metrics.DirectionPublished, err)
+		done(err)
+	})
+}
+
+// instrumentedHandler decora un MessageHandler contando los mensajes consumidos.
+type instrumentedHandler struct {
+	next  MessageHandler
+	bus   string
+	topic string
+}
+
+// InstrumentHandler envuelve 'next' para contar en /metrics cada mensaje que recibe y lo que
+// tarda en procesarlo. HandleMessage no devuelve error, así que los mensajes consumidos se
+// cuentan siempre como ok.
+func InstrumentHandler(next MessageHandler, bus, topic string) MessageHandler {
+	return &instrumentedHandler{next: next, bus: bus, topic: topic}
+}
+
+func (h *instrumentedHandler) HandleMessage(ctx context.Context, key string, payload []byte) {
+	start := time.Now()
+	h.next.HandleMessage(ctx, key, payload)
+	metrics.ObserveBusHandler(h.bus, h.topic, start)
+	metrics.ObserveBusMessage(h.bus, h.topic, metrics.DirectionConsumed, nil)
+}
+
+// Verificación estática
+var (
+	_ sharedBus.EventBus       = (*instrumentedPublisher)(nil)
+	_ sharedBus.AsyncPublisher = (*instrumentedPublisher)(nil)
+	_ MessageHandler           = (*instrumentedHandler)(nil)
+)
@@ -0,0 +1,232 @@
+package events
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// KafkaOptions agrupa los ajustes de conexión, productor y consumidor de Kafka.
+// Los valores cero dejan el comportamiento por defecto de kafka-go.
+type KafkaOptions struct {
+	Brokers []string
+
+	// Autenticación: "" (sin SASL), "plain", "scram-sha-256" o "scram-sha-512".
+	SASLMechanism string
+	SASLUsername  string
+	SASLPassword  string
+
+	TLSEnabled bool
+	// TLSCAFile es un fichero PEM con la CA de los brokers; vacío usa las del sistema.
+	TLSCAFile string
+	// TLSCertFile y TLSKeyFile son el certificado de cliente y su clave (PEM) para los brokers
+	// que exigen TLS mutuo; vacíos no se presenta certificado.
+	TLSCertFile           string
+	TLSKeyFile            string
+	TLSInsecureSkipVerify bool
+
+	// Productor: mensajes por lote, espera máxima antes de enviar un lote incompleto (linger)
+	// y compresión ("none", "gzip", "snappy", "lz4", "zstd").
+	BatchSize    int
+	BatchTimeout time.Duration
+	Compression  string
+	// Async encola los mensajes sin esperar al lote; KafkaPublisher avisa de la entrega con
+	// PublishAsync. RequiredAcks: "all" (por defecto), "one" o "none".
+	Async        bool
+	RequiredAcks string
+	// Idempotent publica con el productor idempotente de franz-go (ver NewIdempotentKafkaWriter)
+	// en vez del writer de kafka-go. Exige RequiredAcks "all" y no admite Async.
+	Idempotent bool
+	// TransactionalID hace que el productor idempotente envíe cada publicación en una transacción.
+	// Debe ser distinto en cada instancia: el broker aborta la transacción abierta del anterior
+	// productor con el mismo id.
+	TransactionalID string
+
+	// Consumidor: bytes mínimos y máximos por fetch y offset inicial de un grupo
+	// sin offsets confirmados ("first" o "last").
+	MinBytes    int
+	MaxBytes    int
+	StartOffset string
+
+	// Tenants son los inquilinos con topic propio (ver KafkaPublisher.SetTenantTopics): los
+	// consumidores leen, además del topic compartido, el de cada uno.
+	Tenants []string
+}
+
+// NewKafkaWriter crea un productor para 'topic' con los ajustes indicados.
+func NewKafkaWriter(opts KafkaOptions, topic string) (*kafka.Writer, error) {
+	mechanism, err := saslMechanismFor(opts)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := tlsConfigFor(opts)
+	if err != nil {
+		return nil, err
+	}
+	compression, err := kafkaCompressionFor(opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+	acks, err := requiredAcksFor(opts.RequiredAcks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafka.Writer{
+		Addr:         kafka.TCP(opts.Brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{}, // Mismo PartitionKey -> misma partición
+		BatchSize:    opts.BatchSize,
+		BatchTimeout: opts.BatchTimeout,
+		Compression:  compression,
+		RequiredAcks: acks,
+		Async:        opts.Async,
+		Transport: &kafka.Transport{
+			SASL: mechanism,
+			TLS:  tlsConfig,
+		},
+	}, nil
+}
+
+// NewKafkaReader crea un consumidor de 'topic' dentro del grupo 'groupID'. Con Tenants lee
+// también el topic de cada inquilino.
+func NewKafkaReader(opts KafkaOptions, topic, groupID string) (*kafka.Reader, error) {
+	mechanism, err := saslMechanismFor(opts)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := tlsConfigFor(opts)
+	if err != nil {
+		return nil, err
+	}
+	startOffset, err := startOffsetFor(opts.StartOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	var groupTopics []string
+	if len(opts.Tenants) > 0 {
+		groupTopics = append(groupTopics, topic)
+		for _, tenant := range opts.Tenants {
+			groupTopics = append(groupTopics, TenantTopic(tenant, topic))
+		}
+		topic = ""
+	}
+
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     opts.Brokers,
+		Topic:       topic,
+		GroupTopics: groupTopics,
+		GroupID:     groupID,
+		MinBytes:    opts.MinBytes,
+		MaxBytes:    opts.MaxBytes,
+		StartOffset: startOffset,
+		// Los mensajes de transacciones abortadas (ver KafkaOptions.TransactionalID) no se entregan.
+		IsolationLevel: kafka.ReadCommitted,
+		Dialer: &kafka.Dialer{
+			Timeout:       10 * time.Second,
+			DualStack:     true,
+			SASLMechanism: mechanism,
+			TLS:           tlsConfig,
+		},
+	}), nil
+}
+
+// saslMechanismFor traduce el nombre del mecanismo SASL al de kafka-go.
+func saslMechanismFor(opts KafkaOptions) (sasl.Mechanism, error) {
+	switch strings.ToLower(opts.SASLMechanism) {
+	case "":
+		return nil, nil
+	case "plain":
+		return plain.Mechanism{Username: opts.SASLUsername, Password: opts.SASLPassword}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, opts.SASLUsername, opts.SASLPassword)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, opts.SASLUsername, opts.SASLPassword)
+	default:
+		return nil, fmt.Errorf("unsupported kafka SASL mechanism %q", opts.SASLMechanism)
+	}
+}
+
+// tlsConfigFor construye la configuración TLS, o nil si TLS no está habilitado.
+func tlsConfigFor(opts KafkaOptions) (*tls.Config, error) {
+	if !opts.TLSEnabled {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.TLSInsecureSkipVerify, // Solo para entornos de pruebas
+	}
+	if opts.TLSCAFile != "" {
+		pem, err := os.ReadFile(opts.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kafka CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates found in kafka CA file %s", opts.TLSCAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if opts.TLSCertFile != "" || opts.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.TLSCertFile, opts.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kafka client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// kafkaCompressionFor traduce el nombre del códec al de kafka-go.
+func kafkaCompressionFor(name string) (kafka.Compression, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return 0, nil
+	case "gzip":
+		return kafka.Gzip, nil
+	case "snappy":
+		return kafka.Snappy, nil
+	case "lz4":
+		return kafka.Lz4, nil
+	case "zstd":
+		return kafka.Zstd, nil
+	default:
+		return 0, fmt.Errorf("unsupported kafka compression %q", name)
+	}
+}
+
+// requiredAcksFor traduce las confirmaciones exigidas por lote a las de kafka-go.
+func requiredAcksFor(name string) (kafka.RequiredAcks, error) {
+	switch strings.ToLower(name) {
+	case "", "all":
+		return kafka.RequireAll, nil
+	case "one":
+		return kafka.RequireOne, nil
+	case "none":
+		return kafka.RequireNone, nil
+	default:
+		return 0, fmt.Errorf("unsupported kafka required acks %q", name)
+	}
+}
+
+// startOffsetFor traduce el offset inicial al de kafka-go.
+func startOffsetFor(name string) (int64, error) {
+	switch strings.ToLower(name) {
+	case "", "first", "earliest":
+		return kafka.FirstOffset, nil
+	case "last", "latest":
+		return kafka.LastOffset, nil
+	default:
+		return 0, fmt.Errorf("unsupported kafka start offset %q", name)
+	}
+}
@@ -0,0 +1,146 @@
+package events
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSASLMechanismFor(t *testing.T) {
+	m, err := saslMechanismFor(KafkaOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, m, "Sin mecanismo no se autentica")
+
+	m, err = saslMechanismFor(KafkaOptions{SASLMechanism: "PLAIN", SASLUsername: "u", SASLPassword: "p"})
+	require.NoError(t, err)
+	assert.Equal(t, "PLAIN", m.Name())
+
+	m, err = saslMechanismFor(KafkaOptions{SASLMechanism: "scram-sha-512", SASLUsername: "u", SASLPassword: "p"})
+	require.NoError(t, err)
+	assert.Equal(t, "SCRAM-SHA-512", m.Name())
+
+	_, err = saslMechanismFor(KafkaOptions{SASLMechanism: "gssapi"})
+	assert.Error(t, err)
+}
+
+func TestTLSConfigFor(t *testing.T) {
+	cfg, err := tlsConfigFor(KafkaOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "Sin TLS habilitado no hay configuración")
+
+	cfg, err = tlsConfigFor(KafkaOptions{TLSEnabled: true})
+	require.NoError(t, err)
+	assert.Nil(t, cfg.RootCAs, "Sin fichero de CA se usan las del sistema")
+
+	_, err = tlsConfigFor(KafkaOptions{TLSEnabled: true, TLSCAFile: "/no/existe.pem"})
+	assert.Error(t, err)
+
+	_, err = tlsConfigFor(KafkaOptions{TLSEnabled: true, TLSCertFile: "/no/existe.pem", TLSKeyFile: "/no/existe.key"})
+	assert.ErrorContains(t, err, "failed to load kafka client certificate")
+}
+
+func TestTLSConfigFor_ClientCertificate(t *testing.T) {
+	// Arrange
+	certFile, keyFile := writeClientCertificate(t)
+
+	// Act
+	cfg, err := tlsConfigFor(KafkaOptions{TLSEnabled: true, TLSCertFile: certFile, TLSKeyFile: keyFile})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, cfg.Certificates, 1, "Se presenta el certificado de cliente para TLS mutuo")
+}
+
+// writeClientCertificate escribe un certificado autofirmado y su clave en ficheros PEM temporales.
+func writeClientCertificate(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "hexagolab"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestNewKafkaWriter(t *testing.T) {
+	// Arrange
+	opts := KafkaOptions{
+		Brokers:      []string{"kafka-1:9092"},
+		BatchSize:    500,
+		BatchTimeout: 50 * time.Millisecond,
+		Compression:  "zstd",
+		Async:        true,
+		RequiredAcks: "one",
+	}
+
+	// Act
+	w, err := NewKafkaWriter(opts, "user-events")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "user-events", w.Topic)
+	assert.Equal(t, 500, w.BatchSize)
+	assert.Equal(t, 50*time.Millisecond, w.BatchTimeout)
+	assert.Equal(t, kafka.Zstd, w.Compression)
+	assert.True(t, w.Async)
+	assert.Equal(t, kafka.RequireOne, w.RequiredAcks)
+
+	_, err = NewKafkaWriter(KafkaOptions{Compression: "brotli"}, "user-events")
+	assert.Error(t, err)
+
+	_, err = NewKafkaWriter(KafkaOptions{RequiredAcks: "two"}, "user-events")
+	assert.Error(t, err)
+}
+
+func TestNewKafkaReader_Tenants(t *testing.T) {
+	// Act
+	shared, err := NewKafkaReader(KafkaOptions{Brokers: []string{"kafka-1:9092"}}, "user-events", "group")
+	require.NoError(t, err)
+	tenants, err := NewKafkaReader(KafkaOptions{Brokers: []string{"kafka-1:9092"}, Tenants: []string{"acme", "globex"}}, "user-events", "group")
+	require.NoError(t, err)
+	defer shared.Close()
+	defer tenants.Close()
+
+	// Assert
+	assert.Equal(t, "user-events", shared.Config().Topic)
+	assert.Equal(t, []string{"user-events", "acme.user-events", "globex.user-events"}, tenants.Config().GroupTopics)
+	assert.Equal(t, "user-events", readerTopic(tenants.Config()))
+}
+
+func TestStartOffsetFor(t *testing.T) {
+	offset, err := startOffsetFor("")
+	require.NoError(t, err)
+	assert.Equal(t, kafka.FirstOffset, offset)
+
+	offset, err = startOffsetFor("latest")
+	require.NoError(t, err)
+	assert.Equal(t, kafka.LastOffset, offset)
+
+	_, err = startOffsetFor("middle")
+	assert.Error(t, err)
+}
@@ -5,6 +5,11 @@ import (
 
 	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/reporting"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 )
 
 // MessageHandler define la interfaz que debe cumplir cualquier consumidor de eventos (como UserConsumer).
@@ -17,6 +22,9 @@ type ConsumerAdapter struct {
 	reader  *kafka.Reader
 	handler MessageHandler
 	log     *zap.Logger
+
+	// dedup descarta los eventos repetidos (ver SetDeduplicator); nil no descarta ninguno.
+	dedup *Deduplicator
 }
 
 func NewConsumerAdapter(reader *kafka.Reader, handler MessageHandler, log *zap.Logger) *ConsumerAdapter {
@@ -27,10 +35,16 @@ func NewConsumerAdapter(reader *kafka.Reader, handler MessageHandler, log *zap.L
 	}
 }
 
+// SetDeduplicator descarta, antes de entregarlos al handler, los eventos cuyo event_id ya vio
+// 'dedup'. Se llama antes de Start.
+func (c *ConsumerAdapter) SetDeduplicator(dedup *Deduplicator) {
+	c.dedup = dedup
+}
+
 // Start inicia el bucle de consumo de mensajes en una goroutine.
 func (c *ConsumerAdapter) Start(ctx context.Context) {
 	c.log.Info("🎧 Iniciando consumidor de Kafka...",
-		zap.String("topic", c.reader.Config().Topic),
+		zap.String("topic", readerTopic(c.reader.Config())),
 		zap.Strings("brokers", c.reader.Config().Brokers),
 	)
 
@@ -41,15 +55,110 @@ func (c *ConsumerAdapter) Start(ctx context.Context) {
 			if err != nil {
 				// Si el contexto se cancela, el error es normal y salimos limpiamente.
 				if ctx.Err() != nil {
-					c.log.Info("Consumidor de Kafka detenido.", zap.String("topic", c.reader.Config().Topic))
+					c.log.Info("Consumidor de Kafka detenido.", zap.String("topic", readerTopic(c.reader.Config())))
 					return
 				}
 				c.log.Error("Error al leer mensaje de Kafka", zap.Error(err))
 				continue // Continuamos con el siguiente mensaje
 			}
 
-			// Pasamos el mensaje al cerebro (UserConsumer) para que lo procese.
-			c.handler.HandleMessage(ctx, string(msg.Key), msg.Value)
+			// Pasamos el mensaje al cerebro (UserConsumer) para que lo procese, con la traza
+			// de la petición de origen en el contexto para que sus logs se puedan correlacionar.
+			msgCtx := contextFromHeaders(ctx, msg.Headers)
+			tracing.Logger(msgCtx, c.log).Debug("Mensaje de Kafka recibido",
+				zap.String("topic", msg.Topic),
+				zap.Int("partition", msg.Partition),
+				zap.Int64("offset", msg.Offset),
+			)
+			// Un tombstone solo sirve a la compactación del topic; el borrado ya llegó como
+			// evento justo antes.
+			if msg.Value == nil {
+				continue
+			}
+			payload, err := compression.Decompress(msg.Value, headerValue(msg.Headers, compression.Header))
+			if err != nil {
+				tracing.Logger(msgCtx, c.log).Error("Mensaje de Kafka descartado: no se pudo descomprimir",
+					zap.String("topic", msg.Topic),
+					zap.Int64("offset", msg.Offset),
+					zap.Error(err),
+				)
+				continue
+			}
+			if c.duplicate(payload) {
+				tracing.Logger(msgCtx, c.log).Info("Evento de Kafka repetido descartado",
+					zap.String("topic", msg.Topic),
+					zap.Int64("offset", msg.Offset),
+				)
+				continue
+			}
+			HandleWithRecovery(msgCtx, c.handler, string(msg.Key), payload, c.log)
+		}
+	}()
+}
+
+// RegisterLagMetric expone en /metrics el lag del grupo de consumo, tomado de las estadísticas
+// del reader. Reader.Stats reinicia sus contadores en cada llamada; aquí solo se usa el lag.
+func (c *ConsumerAdapter) RegisterLagMetric() error {
+	cfg := c.reader.Config()
+	return metrics.RegisterConsumerLag(readerTopic(cfg), cfg.GroupID, func() int64 {
+		return c.reader.Stats().Lag
+	})
+}
+
+// duplicate dice si el evento del mensaje ya se consumió hace poco. Los mensajes sin event_id
+// no se descartan nunca.
+func (c *ConsumerAdapter) duplicate(payload []byte) bool {
+	if c.dedup == nil {
+		return false
+	}
+	id := envelopeEventID(payload)
+	return id != "" && c.dedup.Seen(id)
+}
+
+// readerTopic devuelve el topic del reader o, si consume varios (los de cada inquilino, ver
+// KafkaOptions.Tenants), el primero: el compartido.
+func readerTopic(cfg kafka.ReaderConfig) string {
+	if cfg.Topic == "" && len(cfg.GroupTopics) > 0 {
+		return cfg.GroupTopics[0]
+	}
+	return cfg.Topic
+}
+
+// HandleWithRecovery entrega el mensaje a 'handler' recuperando un posible pánico, que se envía a
+// Sentry (si está habilitado) y se registra; así un mensaje defectuoso no tumba el consumidor.
+func HandleWithRecovery(ctx context.Context, handler MessageHandler, key string, payload []byte, log *zap.Logger) {
+	defer func() {
+		if r := recover(); r != nil {
+			reporting.ReportPanic(ctx, "consumer", r)
+			tracing.Logger(ctx, log).Error("Pánico al procesar mensaje",
+				zap.String("key", key),
+				zap.Any("panic", r),
+				zap.Stack("stack"),
+			)
 		}
 	}()
+	handler.HandleMessage(ctx, key, payload)
+}
+
+// contextFromHeaders asocia a ctx la traza que viaja en las cabeceras del mensaje; cada
+// consumo es un nuevo span de la misma traza.
+func contextFromHeaders(ctx context.Context, headers []kafka.Header) context.Context {
+	tc := tracing.FromHeaders(func(key string) string { return headerValue(headers, key) })
+	if tc.IsZero() {
+		return ctx
+	}
+	if tc.TraceID != "" {
+		tc = tc.Child()
+	}
+	return tracing.NewContext(ctx, tc)
+}
+
+// headerValue devuelve el valor de la cabecera 'key' del mensaje, o "" si no la lleva.
+func headerValue(headers []kafka.Header, key string) string {
+	for _, h := range headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
 }
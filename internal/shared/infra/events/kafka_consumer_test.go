@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type panickingHandler struct{}
+
+func (panickingHandler) HandleMessage(ctx context.Context, key string, payload []byte) {
+	panic("payload inesperado")
+}
+
+func TestHandleWithRecovery_RecoversPanic(t *testing.T) {
+	// Arrange
+	core, logs := observer.New(zapcore.ErrorLevel)
+
+	// Act
+	assert.NotPanics(t, func() {
+		HandleWithRecovery(context.Background(), panickingHandler{}, "k1", []byte("{}"), zap.New(core))
+	})
+
+	// Assert
+	entries := logs.FilterMessage("Pánico al procesar mensaje").AllUntimed()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "k1", entries[0].ContextMap()["key"])
+	}
+}
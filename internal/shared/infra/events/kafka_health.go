@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/health"
+)
+
+// NewKafkaHealthCheck devuelve una comprobación que conecta con los brokers (con los mismos
+// SASL y TLS que productores y consumidores) y pide los metadatos del clúster.
+// Basta con que responda un broker.
+func NewKafkaHealthCheck(opts KafkaOptions) (health.CheckFunc, error) {
+	mechanism, err := saslMechanismFor(opts)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := tlsConfigFor(opts)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &kafka.Dialer{DualStack: true, SASLMechanism: mechanism, TLS: tlsConfig}
+
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, broker := range opts.Brokers {
+			conn, err := dialer.DialContext(ctx, "tcp", broker)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", broker, err))
+				continue
+			}
+			if deadline, ok := ctx.Deadline(); ok {
+				conn.SetDeadline(deadline)
+			}
+			_, err = conn.Brokers()
+			conn.Close()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", broker, err))
+				continue
+			}
+			return nil
+		}
+		if len(errs) == 0 {
+			return errors.New("no kafka brokers configured")
+		}
+		return errors.Join(errs...)
+	}, nil
+}
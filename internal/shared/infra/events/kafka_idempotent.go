@@ -0,0 +1,189 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// KafkaMessageWriter es lo que KafkaPublisher necesita de un productor: enviar mensajes (cada uno
+// con su topic) y esperar a que el broker los confirme. Lo cumplen *kafka.Writer e
+// IdempotentKafkaWriter.
+type KafkaMessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// IdempotentKafkaWriter es un productor idempotente de Kafka sobre franz-go: el broker reconoce
+// por el id de productor y la secuencia de cada partición los lotes que ya escribió, así que los
+// reintentos tras un timeout no duplican eventos. kafka-go no lo permite: siempre escribe los
+// lotes sin id de productor.
+//
+// Con KafkaOptions.TransactionalID cada llamada a WriteMessages es además una transacción: los
+// mensajes de una publicación (el evento, su tombstone y su marca de procesado, ver
+// KafkaPublisher.SetProcessedMarkers) se confirman todos o ninguno.
+type IdempotentKafkaWriter struct {
+	client        *kgo.Client
+	transactional bool
+	// mu serializa las transacciones: un productor solo puede tener una abierta.
+	mu sync.Mutex
+}
+
+// NewIdempotentKafkaWriter crea el productor con los ajustes de conexión de 'opts'. Los mensajes
+// con clave van a la misma partición que con NewKafkaWriter. BatchSize no se aplica: franz-go
+// limita los lotes por bytes, no por mensajes.
+func NewIdempotentKafkaWriter(opts KafkaOptions) (*IdempotentKafkaWriter, error) {
+	kopts, err := idempotentProducerOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kgo.NewClient(kopts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+	return &IdempotentKafkaWriter{client: client, transactional: opts.TransactionalID != ""}, nil
+}
+
+// WriteMessages envía los mensajes y espera a su confirmación. En modo transaccional los envía
+// en una transacción y la aborta si alguno falla.
+func (w *IdempotentKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	records := kafkaRecords(msgs)
+	if !w.transactional {
+		return w.client.ProduceSync(ctx, records...).FirstErr()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.client.BeginTransaction(); err != nil {
+		return fmt.Errorf("failed to begin Kafka transaction: %w", err)
+	}
+	produceErr := w.client.ProduceSync(ctx, records...).FirstErr()
+
+	// Cancelar el fin de la transacción dejaría sin saber si se confirmó: se termina aunque el
+	// contexto de la publicación haya vencido.
+	endCtx := context.WithoutCancel(ctx)
+	if produceErr != nil {
+		return errors.Join(produceErr, w.client.EndTransaction(endCtx, kgo.TryAbort))
+	}
+	err := w.client.EndTransaction(endCtx, kgo.TryCommit)
+	if errors.Is(err, kerr.OperationNotAttempted) {
+		// No se llegó a pedir el commit: la transacción sigue abierta y hay que abortarla.
+		return errors.Join(err, w.client.EndTransaction(endCtx, kgo.TryAbort))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit Kafka transaction: %w", err)
+	}
+	return nil
+}
+
+// Close espera a los envíos pendientes y cierra las conexiones.
+func (w *IdempotentKafkaWriter) Close() error {
+	w.client.Close()
+	return nil
+}
+
+// idempotentProducerOptions traduce 'opts' a las opciones de franz-go. La idempotencia está
+// activa por defecto en franz-go, pero exige que confirmen todas las réplicas.
+func idempotentProducerOptions(opts KafkaOptions) ([]kgo.Opt, error) {
+	if acks := strings.ToLower(opts.RequiredAcks); acks != "" && acks != "all" {
+		return nil, fmt.Errorf("idempotent kafka producer requires required acks all, got %q", opts.RequiredAcks)
+	}
+	if opts.Async {
+		return nil, errors.New("idempotent kafka producer does not support async writes")
+	}
+
+	kopts := []kgo.Opt{
+		kgo.SeedBrokers(opts.Brokers...),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		// El mismo reparto por clave que kafka.Hash (FNV-1a, como Sarama), para no cambiar la
+		// partición de los agregados al activar el productor idempotente.
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(kgo.SaramaCompatHasher(fnv32a))),
+	}
+
+	switch strings.ToLower(opts.SASLMechanism) {
+	case "":
+	case "plain":
+		kopts = append(kopts, kgo.SASL(plain.Auth{User: opts.SASLUsername, Pass: opts.SASLPassword}.AsMechanism()))
+	case "scram-sha-256":
+		kopts = append(kopts, kgo.SASL(scram.Auth{User: opts.SASLUsername, Pass: opts.SASLPassword}.AsSha256Mechanism()))
+	case "scram-sha-512":
+		kopts = append(kopts, kgo.SASL(scram.Auth{User: opts.SASLUsername, Pass: opts.SASLPassword}.AsSha512Mechanism()))
+	default:
+		return nil, fmt.Errorf("unsupported kafka SASL mechanism %q", opts.SASLMechanism)
+	}
+
+	tlsConfig, err := tlsConfigFor(opts)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		kopts = append(kopts, kgo.DialTLSConfig(tlsConfig))
+	}
+
+	codec, err := franzCompressionFor(opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+	kopts = append(kopts, kgo.ProducerBatchCompression(codec))
+
+	if opts.BatchTimeout > 0 {
+		kopts = append(kopts, kgo.ProducerLinger(opts.BatchTimeout))
+	}
+	if opts.TransactionalID != "" {
+		kopts = append(kopts, kgo.TransactionalID(opts.TransactionalID))
+	}
+	return kopts, nil
+}
+
+// franzCompressionFor traduce el nombre del códec al de franz-go.
+func franzCompressionFor(name string) (kgo.CompressionCodec, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return kgo.NoCompression(), nil
+	case "gzip":
+		return kgo.GzipCompression(), nil
+	case "snappy":
+		return kgo.SnappyCompression(), nil
+	case "lz4":
+		return kgo.Lz4Compression(), nil
+	case "zstd":
+		return kgo.ZstdCompression(), nil
+	default:
+		return kgo.CompressionCodec{}, fmt.Errorf("unsupported kafka compression %q", name)
+	}
+}
+
+// kafkaRecords convierte los mensajes de kafka-go en registros de franz-go. Un valor nil sigue
+// siendo un tombstone.
+func kafkaRecords(msgs []kafka.Message) []*kgo.Record {
+	records := make([]*kgo.Record, len(msgs))
+	for i, msg := range msgs {
+		var headers []kgo.RecordHeader
+		for _, h := range msg.Headers {
+			headers = append(headers, kgo.RecordHeader{Key: h.Key, Value: h.Value})
+		}
+		records[i] = &kgo.Record{Topic: msg.Topic, Key: msg.Key, Value: msg.Value, Headers: headers}
+	}
+	return records
+}
+
+// fnv32a es el hash de clave de kafka.Hash.
+func fnv32a(key []byte) uint32 {
+	h := fnv.New32a()
+	h.Write(key)
+	return h.Sum32()
+}
+
+// Verificación estática
+var (
+	_ KafkaMessageWriter = (*kafka.Writer)(nil)
+	_ KafkaMessageWriter = (*IdempotentKafkaWriter)(nil)
+)
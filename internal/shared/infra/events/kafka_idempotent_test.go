@@ -0,0 +1,86 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestNewIdempotentKafkaWriter(t *testing.T) {
+	// Arrange
+	opts := KafkaOptions{
+		Brokers:         []string{"kafka-1:9092"},
+		Compression:     "zstd",
+		SASLMechanism:   "scram-sha-512",
+		SASLUsername:    "hexagolab",
+		SASLPassword:    "secret",
+		Idempotent:      true,
+		TransactionalID: "hexagolab-1",
+	}
+
+	// Act: franz-go no conecta con los brokers hasta el primer envío.
+	w, err := NewIdempotentKafkaWriter(opts)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, w.transactional)
+	assert.NoError(t, w.Close())
+}
+
+func TestNewIdempotentKafkaWriter_InvalidOptions(t *testing.T) {
+	for name, opts := range map[string]KafkaOptions{
+		"acks":        {RequiredAcks: "one"},
+		"async":       {Async: true},
+		"sasl":        {SASLMechanism: "gssapi"},
+		"compression": {Compression: "brotli"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts.Brokers = []string{"kafka-1:9092"}
+
+			_, err := NewIdempotentKafkaWriter(opts)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKafkaRecords(t *testing.T) {
+	// Arrange
+	msgs := []kafka.Message{
+		{Topic: "user-events", Key: []byte("u-1"), Value: []byte(`{"id":"u-1"}`), Headers: []kafka.Header{{Key: "x-tenant-id", Value: []byte("acme")}}},
+		{Topic: "user-events", Key: []byte("u-1")},
+	}
+
+	// Act
+	records := kafkaRecords(msgs)
+
+	// Assert
+	require.Len(t, records, 2)
+	assert.Equal(t, "user-events", records[0].Topic)
+	assert.Equal(t, []byte("u-1"), records[0].Key)
+	assert.Equal(t, []byte(`{"id":"u-1"}`), records[0].Value)
+	assert.Equal(t, []kgo.RecordHeader{{Key: "x-tenant-id", Value: []byte("acme")}}, records[0].Headers)
+	assert.Nil(t, records[1].Value, "El tombstone sigue teniendo valor nulo")
+}
+
+func TestIdempotentKafkaWriter_SamePartitionsAsKafkaHash(t *testing.T) {
+	// Arrange
+	balancer := &kafka.Hash{}
+	hasher := kgo.SaramaCompatHasher(fnv32a)
+	partitions := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+
+	for i := 0; i < 200; i++ {
+		key := []byte(fmt.Sprintf("aggregate-%d", i))
+
+		// Act
+		expected := balancer.Balance(kafka.Message{Key: key}, partitions...)
+		actual := hasher(key, len(partitions))
+
+		// Assert: activar el productor idempotente no mueve ningún agregado de partición.
+		assert.Equal(t, expected, actual, "key %s", key)
+	}
+}
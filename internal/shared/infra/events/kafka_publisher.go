@@ -3,47 +3,291 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 
 	"github.com/segmentio/kafka-go"
 
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
 	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/redact"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 )
 
 type KafkaPublisher struct {
-	writer *kafka.Writer
-	log    *zap.Logger
+	writer KafkaMessageWriter
+	// async indica que el writer encola los mensajes y avisa de la entrega con su Completion.
+	async bool
+	log   *zap.Logger
+	// topic es el topic del writer: el de los eventos que el registro no enruta (ver
+	// SetEventRegistry). Cada mensaje lleva puesto el suyo.
+	topic string
+	// topics es el topic de cada tipo de evento (ver SetEventRegistry).
+	topics map[string]string
+	// markerTopic es el topic de las marcas de procesado (ver SetProcessedMarkers).
+	markerTopic string
+
+	// compression comprime los mensajes grandes (ver SetPayloadCompression).
+	compression atomic.Pointer[compression.Options]
+	// tombstones publica un tombstone tras cada evento de borrado (ver SetTombstones).
+	tombstones atomic.Bool
+	// tenantTopics separa los eventos de cada inquilino en su topic (ver SetTenantTopics).
+	tenantTopics atomic.Bool
+}
+
+// NewKafkaPublisher es el constructor. Si 'writer' es un *kafka.Writer asíncrono (ver
+// KafkaOptions.Async), el publicador instala su Completion para avisar a cada mensaje del
+// resultado de su lote. El topic del writer pasa a ir en cada mensaje, para poder enrutarlo por
+// tipo de evento y por inquilino; un writer sin topic (como IdempotentKafkaWriter) solo publica
+// los eventos que enruta el registro.
+func NewKafkaPublisher(writer KafkaMessageWriter, log *zap.Logger) *KafkaPublisher {
+	p := &KafkaPublisher{writer: writer, log: log}
+	if w, ok := writer.(*kafka.Writer); ok {
+		if w.Async && w.Completion == nil {
+			w.Completion = completeMessages
+		}
+		p.async, p.topic = w.Async, w.Topic
+		w.Topic = ""
+	}
+	return p
 }
 
-func NewKafkaPublisher(writer *kafka.Writer, log *zap.Logger) *KafkaPublisher {
-	return &KafkaPublisher{writer: writer, log: log}
+// SetPayloadCompression comprime a partir de ahora el valor de los mensajes que superen el
+// umbral de 'opts', marcando el códec en la cabecera compression.Header para que el consumidor
+// lo descomprima. Es independiente de la compresión por lotes del writer (KafkaOptions.Compression).
+func (p *KafkaPublisher) SetPayloadCompression(opts compression.Options) {
+	p.compression.Store(&opts)
 }
 
+// SetEventRegistry enruta cada evento (un sharedBus.Typed) al Topic de su tipo en 'registry',
+// de modo que un solo publicador sirve a todos los dominios. Los tipos que el registro no conoce
+// van al topic del writer. Se llama antes de publicar.
+func (p *KafkaPublisher) SetEventRegistry(registry map[string]sharedEvents.EventMetadata) {
+	p.topics = make(map[string]string, len(registry))
+	for eventType, metadata := range registry {
+		if metadata.Topic != "" {
+			p.topics[eventType] = metadata.Topic
+		}
+	}
+}
+
+// SetTenantTopics hace que los eventos de un inquilino (el TenantID de la traza del contexto) se
+// publiquen en su propio topic, TenantTopic(inquilino, topic), en vez de en el compartido. Los
+// del inquilino por defecto siguen en el compartido. Desactivado, el inquilino solo viaja en la
+// cabecera tracing.HeaderTenantID y en el sobre del evento.
+func (p *KafkaPublisher) SetTenantTopics(enabled bool) {
+	p.tenantTopics.Store(enabled)
+}
+
+// SetProcessedMarkers hace que cada evento del outbox (un sharedEvents.IntegrationEvent) se
+// publique junto a una marca de procesado en 'topic', con su event_id como clave. Con un
+// IdempotentKafkaWriter transaccional el evento y su marca se confirman en la misma transacción,
+// así que la marca prueba que el evento llegó a Kafka una sola vez. Vacío las desactiva. Se
+// llama antes de publicar.
+func (p *KafkaPublisher) SetProcessedMarkers(topic string) {
+	p.markerTopic = topic
+}
+
+// SetTombstones hace que cada evento que borra su agregado (un sharedBus.Tombstoner) vaya
+// seguido de un tombstone: un mensaje de valor nulo con la misma clave, para que en los topics
+// con log compaction la clave desaparezca y las vistas materializadas de los consumidores
+// converjan. Los dos mensajes van en el mismo envío, así que llegan en orden a la partición.
+func (p *KafkaPublisher) SetTombstones(enabled bool) {
+	p.tombstones.Store(enabled)
+}
+
+// Publish envía el evento y espera a la confirmación del broker, también con un writer asíncrono.
 func (p *KafkaPublisher) Publish(ctx context.Context, event interface{}) error {
+	if !p.async {
+		msgs, err := p.messages(ctx, event)
+		if err != nil {
+			return err
+		}
+		return p.report(ctx, event, p.writer.WriteMessages(ctx, msgs...))
+	}
+
+	result := make(chan error, 1)
+	p.PublishAsync(ctx, event, func(err error) { result <- err })
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PublishAsync encola el evento en el writer y llama a 'done' cuando el lote en el que va se ha
+// entregado (o ha fallado). Con un writer síncrono equivale a Publish.
+func (p *KafkaPublisher) PublishAsync(ctx context.Context, event interface{}, done func(error)) {
+	msgs, err := p.messages(ctx, event)
+	if err != nil {
+		done(err)
+		return
+	}
+	if !p.async {
+		done(p.report(ctx, event, p.writer.WriteMessages(ctx, msgs...)))
+		return
+	}
+
+	// Con tombstone son dos mensajes; la entrega se da por hecha cuando se confirman los dos.
+	complete := completeAll(len(msgs), func(err error) { done(p.report(ctx, event, err)) })
+	for i := range msgs {
+		msgs[i].WriterData = complete
+	}
+	// Solo falla si los mensajes no llegan a encolarse; en ese caso Completion no se llama.
+	if err := p.writer.WriteMessages(ctx, msgs...); err != nil {
+		done(p.report(ctx, event, err))
+	}
+}
+
+// messages construye los mensajes de Kafka del evento: el suyo; si borra su agregado y los
+// tombstones están activos, el tombstone que le sigue; y con SetProcessedMarkers, su marca.
+func (p *KafkaPublisher) messages(ctx context.Context, event interface{}) ([]kafka.Message, error) {
+	msg, err := p.message(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	msgs := []kafka.Message{msg}
+	if tombstoner, ok := event.(sharedBus.Tombstoner); ok && tombstoner.Tombstone() && p.tombstones.Load() && msg.Key != nil {
+		msgs = append(msgs, kafka.Message{Topic: msg.Topic, Key: msg.Key, Value: nil, Headers: traceHeaders(ctx)})
+	}
+	if evt, ok := event.(sharedEvents.IntegrationEvent); ok && p.markerTopic != "" {
+		marker, err := json.Marshal(processedMarker{EventID: evt.EventID.String(), Type: evt.Type, Topic: msg.Topic})
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, kafka.Message{Topic: p.markerTopic, Key: []byte(evt.EventID.String()), Value: marker, Headers: traceHeaders(ctx)})
+	}
+	return msgs, nil
+}
+
+// processedMarker es el valor de una marca de procesado: qué evento se publicó y en qué topic.
+type processedMarker struct {
+	EventID string `json:"event_id"`
+	Type    string `json:"type"`
+	Topic   string `json:"topic"`
+}
+
+// topicFor devuelve el topic del evento publicado con ctx: el de su tipo y, con SetTenantTopics,
+// el de su inquilino.
+func (p *KafkaPublisher) topicFor(ctx context.Context, event interface{}) (string, error) {
+	topic := p.topic
+	if typed, ok := event.(sharedBus.Typed); ok {
+		if routed, ok := p.topics[typed.EventType()]; ok {
+			topic = routed
+		}
+	}
+	if topic == "" {
+		return "", fmt.Errorf("no Kafka topic for event %T", event)
+	}
+	if tenant := tracing.TenantID(ctx); tenant != "" && p.tenantTopics.Load() {
+		return TenantTopic(tenant, topic), nil
+	}
+	return topic, nil
+}
+
+// TenantTopic es el topic de 'topic' propio del inquilino 'tenant': "<tenant>.<topic>".
+func TenantTopic(tenant, topic string) string {
+	return tenant + "." + topic
+}
+
+// message construye el mensaje de Kafka del evento, con su clave de partición y la traza.
+func (p *KafkaPublisher) message(ctx context.Context, event interface{}) (kafka.Message, error) {
+	topic, err := p.topicFor(ctx, event)
+	if err != nil {
+		return kafka.Message{}, err
+	}
 	data, err := json.Marshal(event)
 	if err != nil {
-		return err
+		return kafka.Message{}, err
 	}
 
 	var key []byte
-	if keyer, ok := event.(sharedBus.Keyer); ok {
+	if keyer, ok := event.(sharedBus.Keyer); ok && keyer.PartitionKey() != "" {
 		key = []byte(keyer.PartitionKey())
 	}
 
-	msg := kafka.Message{
-		Key:   key,
-		Value: data,
+	headers := traceHeaders(ctx)
+	if opts := p.compression.Load(); opts != nil {
+		var encoding string
+		if data, encoding, err = opts.Compress(data); err != nil {
+			return kafka.Message{}, err
+		}
+		if encoding != "" {
+			headers = append(headers, kafka.Header{Key: compression.Header, Value: []byte(encoding)})
+		}
 	}
 
-	if err := p.writer.WriteMessages(ctx, msg); err != nil {
-		p.log.Error("Error publishing to Kafka", zap.Error(err))
+	return kafka.Message{
+		Topic:   topic,
+		Key:     key,
+		Value:   data,
+		Headers: headers,
+	}, nil
+}
+
+// report registra el resultado de la entrega y lo devuelve.
+func (p *KafkaPublisher) report(ctx context.Context, event interface{}, err error) error {
+	log := tracing.Logger(ctx, p.log)
+	if err != nil {
+		log.Error("Error publishing to Kafka", zap.Error(err))
 		return err
 	}
-
-	p.log.Debug("Event published successfully", zap.Any("event", event))
+	log.Debug("Event published successfully", redact.Any("event", event))
 	return nil
 }
 
+// completeAll devuelve el 'done' que comparten 'n' mensajes de un mismo evento: llama a 'done'
+// una sola vez, cuando se han confirmado todos, con el primer error.
+func completeAll(n int, done func(error)) func(error) {
+	var (
+		mu      sync.Mutex
+		pending = n
+		first   error
+	)
+	return func(err error) {
+		mu.Lock()
+		if first == nil {
+			first = err
+		}
+		pending--
+		last, result := pending == 0, first
+		mu.Unlock()
+		if last {
+			done(result)
+		}
+	}
+}
+
+// completeMessages es el Completion de los writers asíncronos: avisa a cada mensaje del lote
+// con el resultado del envío.
+func completeMessages(messages []kafka.Message, err error) {
+	for _, msg := range messages {
+		if done, ok := msg.WriterData.(func(error)); ok {
+			done(err)
+		}
+	}
+}
+
+// traceHeaders convierte el contexto de traza de ctx en cabeceras del mensaje.
+func traceHeaders(ctx context.Context) []kafka.Header {
+	tc, ok := tracing.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	var headers []kafka.Header
+	for k, v := range tc.Headers() {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+	return headers
+}
+
 // Verificación estática
-var _ sharedBus.EventBus = (*KafkaPublisher)(nil)
+var (
+	_ sharedBus.EventBus       = (*KafkaPublisher)(nil)
+	_ sharedBus.AsyncPublisher = (*KafkaPublisher)(nil)
+)
@@ -0,0 +1,177 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func TestKafkaPublisher_PayloadCompression(t *testing.T) {
+	// Arrange
+	p := NewKafkaPublisher(&kafka.Writer{Topic: "user-events"}, zap.NewNop())
+	p.SetPayloadCompression(compression.Options{Encoding: compression.Gzip, Threshold: 100})
+	small := map[string]string{"id": "1"}
+	large := map[string]string{"description": strings.Repeat("x", 1000)}
+
+	// Act
+	smallMsg, err := p.message(context.Background(), small)
+	require.NoError(t, err)
+	largeMsg, err := p.message(context.Background(), large)
+	require.NoError(t, err)
+
+	// Assert: solo el grande va comprimido y marcado; el consumidor recupera el JSON.
+	assert.Empty(t, headerValue(smallMsg.Headers, compression.Header))
+	assert.JSONEq(t, `{"id": "1"}`, string(smallMsg.Value))
+	assert.Equal(t, compression.Gzip, headerValue(largeMsg.Headers, compression.Header))
+	value, err := compression.Decompress(largeMsg.Value, headerValue(largeMsg.Headers, compression.Header))
+	require.NoError(t, err)
+	expected, _ := json.Marshal(large)
+	assert.Equal(t, expected, value)
+}
+
+func TestKafkaPublisher_Tombstones(t *testing.T) {
+	// Arrange
+	p := NewKafkaPublisher(&kafka.Writer{Topic: "user-events"}, zap.NewNop())
+	deleted := sharedEvents.IntegrationEvent{Type: "user.deleted", Data: json.RawMessage(`{"id":"u-1"}`)}.
+		WithPartitionKey("u-1").AsTombstone()
+	updated := sharedEvents.IntegrationEvent{Type: "user.updated", Data: json.RawMessage(`{"id":"u-1"}`)}.
+		WithPartitionKey("u-1")
+
+	// Act
+	disabled, err := p.messages(context.Background(), deleted)
+	require.NoError(t, err)
+	p.SetTombstones(true)
+	enabled, err := p.messages(context.Background(), deleted)
+	require.NoError(t, err)
+	notDeleted, err := p.messages(context.Background(), updated)
+	require.NoError(t, err)
+
+	// Assert: solo el borrado, y solo con la opción activa, va seguido de un valor nulo con su clave.
+	assert.Len(t, disabled, 1)
+	assert.Len(t, notDeleted, 1)
+	require.Len(t, enabled, 2)
+	assert.Equal(t, []byte("u-1"), enabled[0].Key)
+	assert.NotNil(t, enabled[0].Value)
+	assert.Equal(t, []byte("u-1"), enabled[1].Key)
+	assert.Nil(t, enabled[1].Value)
+}
+
+func TestCompleteAll(t *testing.T) {
+	// Arrange
+	var calls []error
+	done := completeAll(2, func(err error) { calls = append(calls, err) })
+	failure := errors.New("broker down")
+
+	// Act
+	done(failure)
+	afterFirst := len(calls)
+	done(nil)
+
+	// Assert
+	assert.Equal(t, 0, afterFirst, "Espera a que se confirmen los dos mensajes")
+	assert.Equal(t, []error{failure}, calls)
+}
+
+func TestKafkaPublisher_TenantTopics(t *testing.T) {
+	// Arrange
+	p := NewKafkaPublisher(&kafka.Writer{Topic: "user-events"}, zap.NewNop())
+	acme := tracing.NewContext(context.Background(), tracing.Context{TenantID: "acme"})
+
+	// Act
+	shared, err := p.message(acme, map[string]string{"id": "1"})
+	require.NoError(t, err)
+	p.SetTenantTopics(true)
+	tenant, err := p.message(acme, map[string]string{"id": "1"})
+	require.NoError(t, err)
+	defaultTenant, err := p.message(context.Background(), map[string]string{"id": "1"})
+	require.NoError(t, err)
+
+	// Assert: el topic va en cada mensaje y solo se separa con la opción activa.
+	assert.Equal(t, "user-events", shared.Topic)
+	assert.Equal(t, "acme.user-events", tenant.Topic)
+	assert.Equal(t, "user-events", defaultTenant.Topic)
+}
+
+func TestKafkaPublisher_EventRegistryRouting(t *testing.T) {
+	// Arrange: un solo writer sin topic para los dos dominios.
+	p := NewKafkaPublisher(&kafka.Writer{}, zap.NewNop())
+	p.SetEventRegistry(map[string]sharedEvents.EventMetadata{
+		"user.created": {Topic: "user-events"},
+		"task.created": {Topic: "task-events"},
+	})
+	p.SetTenantTopics(true)
+	acme := tracing.NewContext(context.Background(), tracing.Context{TenantID: "acme"})
+
+	// Act
+	user, userErr := p.message(context.Background(), sharedEvents.IntegrationEvent{Type: "user.created"})
+	task, taskErr := p.message(acme, sharedEvents.IntegrationEvent{Type: "task.created"})
+	_, unknownErr := p.message(context.Background(), sharedEvents.IntegrationEvent{Type: "invoice.paid"})
+
+	// Assert
+	require.NoError(t, userErr)
+	require.NoError(t, taskErr)
+	assert.Equal(t, "user-events", user.Topic)
+	assert.Equal(t, "acme.task-events", task.Topic, "El topic del tipo se separa también por inquilino")
+	assert.ErrorContains(t, unknownErr, "no Kafka topic for event")
+}
+
+// recordingWriter guarda los mensajes de cada llamada a WriteMessages.
+type recordingWriter struct {
+	calls [][]kafka.Message
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.calls = append(w.calls, msgs)
+	return nil
+}
+
+func TestKafkaPublisher_ProcessedMarkers(t *testing.T) {
+	// Arrange
+	writer := &recordingWriter{}
+	p := NewKafkaPublisher(writer, zap.NewNop())
+	p.SetEventRegistry(map[string]sharedEvents.EventMetadata{"user.deleted": {Topic: "user-events"}})
+	p.SetTombstones(true)
+	p.SetProcessedMarkers("outbox-processed")
+	deleted := sharedEvents.IntegrationEvent{EventID: uuid.New(), Type: "user.deleted", Data: json.RawMessage(`{"id":"u-1"}`)}.
+		WithPartitionKey("u-1").AsTombstone()
+
+	// Act
+	err := p.Publish(context.Background(), deleted)
+
+	// Assert: el evento, su tombstone y su marca van en el mismo envío (la misma transacción).
+	require.NoError(t, err)
+	require.Len(t, writer.calls, 1)
+	msgs := writer.calls[0]
+	require.Len(t, msgs, 3)
+	assert.Equal(t, "user-events", msgs[0].Topic)
+	assert.Nil(t, msgs[1].Value)
+	assert.Equal(t, "outbox-processed", msgs[2].Topic)
+	assert.Equal(t, []byte(deleted.EventID.String()), msgs[2].Key)
+	assert.JSONEq(t, `{"event_id":"`+deleted.EventID.String()+`","type":"user.deleted","topic":"user-events"}`, string(msgs[2].Value))
+}
+
+func TestKafkaPublisher_ProcessedMarkersOnlyForOutboxEvents(t *testing.T) {
+	// Arrange
+	p := NewKafkaPublisher(&kafka.Writer{Topic: "security-events"}, zap.NewNop())
+	p.SetProcessedMarkers("outbox-processed")
+
+	// Act
+	msgs, err := p.messages(context.Background(), map[string]string{"id": "1"})
+
+	// Assert: un evento que no viene del outbox no tiene event_id con el que marcarlo.
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "security-events", msgs[0].Topic)
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func TestTraceHeadersRoundTrip(t *testing.T) {
+	// Arrange: contexto de la petición que publica el evento.
+	tc := tracing.New()
+	ctx := tracing.NewContext(context.Background(), tc)
+
+	// Act
+	headers := traceHeaders(ctx)
+	consumerCtx := contextFromHeaders(context.Background(), headers)
+
+	// Assert: el consumidor continúa la misma traza con un span propio.
+	assert.Len(t, headers, 2)
+	got, ok := tracing.FromContext(consumerCtx)
+	require.True(t, ok)
+	assert.Equal(t, tc.CorrelationID, got.CorrelationID)
+	assert.Equal(t, tc.TraceID, got.TraceID)
+	assert.NotEqual(t, tc.SpanID, got.SpanID)
+}
+
+func TestTraceHeaders_WithoutTrace(t *testing.T) {
+	assert.Nil(t, traceHeaders(context.Background()))
+
+	ctx := contextFromHeaders(context.Background(), nil)
+	_, ok := tracing.FromContext(ctx)
+	assert.False(t, ok)
+}
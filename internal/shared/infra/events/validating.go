@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+)
+
+// validatingPublisher decora un EventBus rechazando los IntegrationEvent cuyo Data no cumple el
+// esquema de su tipo, antes de que lleguen al broker. El resto de eventos pasan sin más.
+type validatingPublisher struct {
+	next    sharedBus.EventBus
+	schemas sharedEvents.SchemaRegistry
+}
+
+// NewValidatingPublisher envuelve 'next' para validar contra 'schemas'. Un evento rechazado
+// devuelve un error que es sharedEvents.ErrSchemaViolation.
+func NewValidatingPublisher(next sharedBus.EventBus, schemas sharedEvents.SchemaRegistry) sharedBus.EventBus {
+	return &validatingPublisher{next: next, schemas: schemas}
+}
+
+func (p *validatingPublisher) validate(event interface{}) error {
+	var evt sharedEvents.IntegrationEvent
+	switch e := event.(type) {
+	case sharedEvents.IntegrationEvent:
+		evt = e
+	case *sharedEvents.IntegrationEvent:
+		evt = *e
+	default:
+		return nil
+	}
+	validate := p.schemas.Validate
+	if evt.PayloadMode.Partial() {
+		validate = p.schemas.ValidatePartial
+	}
+	if err := validate(evt.Type, evt.Data); err != nil {
+		return fmt.Errorf("rejected %s event: %w", evt.Type, err)
+	}
+	return nil
+}
+
+func (p *validatingPublisher) Publish(ctx context.Context, event interface{}) error {
+	if err := p.validate(event); err != nil {
+		return err
+	}
+	return p.next.Publish(ctx, event)
+}
+
+// PublishAsync rechaza el evento sin llegar a enviarlo, avisando a 'done' con el error.
+func (p *validatingPublisher) PublishAsync(ctx context.Context, event interface{}, done func(error)) {
+	if err := p.validate(event); err != nil {
+		done(err)
+		return
+	}
+	sharedBus.PublishAsync(ctx, p.next, event, done)
+}
+
+// Verificación estática
+var (
+	_ sharedBus.EventBus       = (*validatingPublisher)(nil)
+	_ sharedBus.AsyncPublisher = (*validatingPublisher)(nil)
+)
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+)
+
+func TestValidatingPublisher(t *testing.T) {
+	// Arrange
+	bus := NewInMemoryEventBus("test-topic")
+	ch := bus.Subscribe(10)
+	schemas := sharedEvents.Schemas(map[string]sharedEvents.EventMetadata{
+		"item.created": {Topic: "test-topic", Schema: sharedEvents.MustCompileSchema(`{"type": "object", "required": ["name"]}`)},
+	})
+	pub := NewValidatingPublisher(bus, schemas)
+
+	// Act
+	invalidErr := pub.Publish(context.Background(), sharedEvents.IntegrationEvent{Type: "item.created", Data: json.RawMessage(`{}`)})
+	validErr := pub.Publish(context.Background(), sharedEvents.IntegrationEvent{Type: "item.created", Data: json.RawMessage(`{"name": "a"}`)})
+	otherErr := pub.Publish(context.Background(), map[string]string{"name": "b"}) // No es un IntegrationEvent
+
+	// Assert: solo el evento inválido se queda sin publicar.
+	assert.ErrorIs(t, invalidErr, sharedEvents.ErrSchemaViolation)
+	require.NoError(t, validErr)
+	require.NoError(t, otherErr)
+	assert.Eventually(t, func() bool { return bus.QueueDepth() == 2 }, time.Second, 5*time.Millisecond)
+	var first sharedEvents.IntegrationEvent
+	require.NoError(t, json.Unmarshal((<-ch).([]byte), &first))
+	assert.JSONEq(t, `{"name": "a"}`, string(first.Data))
+}
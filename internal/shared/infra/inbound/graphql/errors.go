@@ -0,0 +1,32 @@
+package graphql
+
+import (
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// Error convierte 'err' en un error de GraphQL con el mensaje del error de la aplicación y su
+// código en extensions.code (not_found, invalid_argument...), como el campo code de la API REST.
+// Los detalles por campo o parámetro van en extensions.details. nil devuelve nil.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{err: sharedDomain.AsError(err)}
+}
+
+type codedError struct {
+	err *sharedDomain.Error
+}
+
+func (e *codedError) Error() string { return e.err.Message }
+
+func (e *codedError) Unwrap() error { return e.err }
+
+// Extensions la usa graphql-go para rellenar las extensions del error en la respuesta.
+func (e *codedError) Extensions() map[string]any {
+	ext := map[string]any{"code": string(e.err.Code)}
+	if len(e.err.Details) > 0 {
+		ext["details"] = e.err.Details
+	}
+	return ext
+}
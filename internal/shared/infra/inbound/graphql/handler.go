@@ -0,0 +1,116 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// keepAliveInterval es cada cuánto se envía un comentario por una suscripción sin eventos, para
+// que los proxies no cierren la conexión por inactividad.
+const keepAliveInterval = 15 * time.Second
+
+// Handler sirve la API GraphQL. Las consultas y mutaciones llegan por POST con el cuerpo estándar
+// {"query", "operationName", "variables"} (o por GET, con esos parámetros en la URL) y se
+// responden en JSON. Con Accept: text/event-stream la respuesta es un stream de Server-Sent
+// Events (protocolo graphql-sse, una conexión por operación): un evento "next" por resultado y
+// "complete" al terminar, lo que permite recibir las suscripciones.
+type Handler struct {
+	schema *graphql.Schema
+}
+
+func NewHandler(schema *graphql.Schema) *Handler {
+	return &Handler{schema: schema}
+}
+
+// request es el cuerpo de una petición GraphQL.
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Serve endpoint GET/POST /graphql.
+func (h *Handler) Serve(c *gin.Context) {
+	req, err := bindRequest(c)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	if strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		h.stream(c, req)
+		return
+	}
+	c.JSON(http.StatusOK, h.schema.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables))
+}
+
+// stream ejecuta la operación y envía cada resultado como un evento SSE hasta que termina o el
+// cliente se desconecta.
+func (h *Handler) stream(c *gin.Context, req request) {
+	results, err := h.schema.Subscribe(c.Request.Context(), req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Sin buffer en nginx
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-keepAlive.C:
+			fmt.Fprint(c.Writer, ":\n\n")
+		case result, ok := <-results:
+			if !ok {
+				fmt.Fprint(c.Writer, "event: complete\ndata:\n\n")
+				c.Writer.Flush()
+				return
+			}
+			data, err := json.Marshal(result)
+			if err != nil {
+				_ = c.Error(err)
+				return
+			}
+			fmt.Fprintf(c.Writer, "event: next\ndata: %s\n\n", data)
+		}
+		c.Writer.Flush()
+	}
+}
+
+// badRequest responde 400 con 'err' en el formato de errores de GraphQL, para las peticiones que
+// no llegan a ejecutarse.
+func badRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, &graphql.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}})
+}
+
+func bindRequest(c *gin.Context) (request, error) {
+	var req request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if vars := c.Query("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				return req, fmt.Errorf("variables must be a JSON object: %w", err)
+			}
+		}
+	} else if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		return req, fmt.Errorf("invalid request body: %w", err)
+	}
+	if req.Query == "" {
+		return req, errors.New("query is required")
+	}
+	return req, nil
+}
@@ -0,0 +1,97 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/reporting"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// Module es la parte del esquema de un módulo: sus tipos y los campos que añade a cada raíz.
+// NewSchema los junta en un solo esquema, que resuelve un único resolver con los métodos de
+// todos los módulos (normalmente un struct que embebe el resolver de cada uno).
+type Module struct {
+	// Types declara los tipos, inputs y enums del módulo.
+	Types string
+	// Query, Mutation y Subscription son las definiciones de campo que el módulo añade a cada raíz.
+	Query        string
+	Mutation     string
+	Subscription string
+}
+
+// commonTypes son los tipos que comparten los módulos.
+const commonTypes = `
+scalar Time
+
+"Posición de una página en un listado por cursor."
+type PageInfo {
+	"Cursor de la última entrada, para pedir la página siguiente con after."
+	endCursor: String
+	hasNextPage: Boolean!
+}
+`
+
+// PageInfo es el tipo PageInfo del esquema.
+type PageInfo struct {
+	EndCursor   *string
+	HasNextPage bool
+}
+
+// NewPageInfo crea el PageInfo de una página con 'nextCursor' ("" en la última).
+func NewPageInfo(nextCursor string, hasMore bool) PageInfo {
+	info := PageInfo{HasNextPage: hasMore}
+	if nextCursor != "" {
+		info.EndCursor = &nextCursor
+	}
+	return info
+}
+
+// NewSchema compone el esquema de 'modules' y lo enlaza con 'resolver'. Los campos de los tipos
+// se resuelven por nombre con los de los structs que devuelven los resolvers, sin mayúsculas ni
+// guiones bajos. Una consulta no puede anidarse más de 'maxDepth' niveles.
+func NewSchema(resolver any, maxDepth int, log *zap.Logger, modules ...Module) (*graphql.Schema, error) {
+	var sdl strings.Builder
+	sdl.WriteString("schema {\n\tquery: Query\n\tmutation: Mutation\n\tsubscription: Subscription\n}\n")
+	sdl.WriteString(commonTypes)
+	for _, m := range modules {
+		sdl.WriteString(m.Types)
+	}
+	for _, root := range []struct {
+		name   string
+		fields func(Module) string
+	}{
+		{"Query", func(m Module) string { return m.Query }},
+		{"Mutation", func(m Module) string { return m.Mutation }},
+		{"Subscription", func(m Module) string { return m.Subscription }},
+	} {
+		sdl.WriteString("\ntype " + root.name + " {\n")
+		for _, m := range modules {
+			sdl.WriteString(root.fields(m))
+		}
+		sdl.WriteString("}\n")
+	}
+
+	return graphql.ParseSchema(sdl.String(), resolver,
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(maxDepth),
+		graphql.Logger(panicLogger{log: log}),
+	)
+}
+
+// panicLogger registra los pánicos de los resolvers, que graphql-go recupera y devuelve como un
+// error del campo, y los envía a Sentry (si está habilitado).
+type panicLogger struct {
+	log *zap.Logger
+}
+
+func (l panicLogger) LogPanic(ctx context.Context, value interface{}) {
+	reporting.ReportPanic(ctx, "graphql", value)
+	tracing.Logger(ctx, l.log).Error("Pánico en resolver GraphQL",
+		zap.Any("panic", value),
+		zap.Stack("stack"),
+	)
+}
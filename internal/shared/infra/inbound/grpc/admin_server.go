@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/davicafu/hexagolab/gen/go/admin"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
+)
+
+// GrpcAdminServer expone por gRPC las operaciones de mantenimiento que usa hexagolabctl.
+type GrpcAdminServer struct {
+	pb.UnsafeAdminServiceServer
+	outboxes    map[string]sharedDomain.OutboxBacklogCounter
+	deadLetters map[string]sharedDomain.OutboxDeadLetterStore
+}
+
+// defaultDeadLettersLimit es cuántos eventos apartados lista ListDeadLetters si no se indica limit.
+const defaultDeadLettersLimit = 100
+
+// NewGrpcAdminServer recibe, indexados por dominio, los outbox que saben contar su backlog y los
+// que guardan sus eventos apartados.
+func NewGrpcAdminServer(outboxes map[string]sharedDomain.OutboxBacklogCounter, deadLetters map[string]sharedDomain.OutboxDeadLetterStore) *GrpcAdminServer {
+	return &GrpcAdminServer{outboxes: outboxes, deadLetters: deadLetters}
+}
+
+// GetOutboxBacklog cuenta los eventos pendientes de cada outbox, ordenados por dominio.
+func (s *GrpcAdminServer) GetOutboxBacklog(ctx context.Context, _ *pb.GetOutboxBacklogRequest) (*pb.GetOutboxBacklogResponse, error) {
+	names := make([]string, 0, len(s.outboxes))
+	for name := range s.outboxes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := &pb.GetOutboxBacklogResponse{}
+	for _, name := range names {
+		pending, err := s.outboxes[name].CountPendingOutbox(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "could not count %s outbox: %v", name, err)
+		}
+		resp.Outboxes = append(resp.Outboxes, &pb.OutboxBacklog{Outbox: name, Pending: int64(pending)})
+	}
+	return resp, nil
+}
+
+// ListDeadLetters lista los eventos apartados de un outbox, del más antiguo al más reciente.
+func (s *GrpcAdminServer) ListDeadLetters(ctx context.Context, req *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	store, err := s.deadLetterStore(req.GetOutbox())
+	if err != nil {
+		return nil, err
+	}
+	deadLetters, err := store.ListDeadLetters(ctx, sanitize.Limit(int(req.GetLimit()), defaultDeadLettersLimit))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not list %s dead letters: %v", req.GetOutbox(), err)
+	}
+
+	resp := &pb.ListDeadLettersResponse{}
+	for _, dl := range deadLetters {
+		resp.DeadLetters = append(resp.DeadLetters, &pb.DeadLetter{
+			Id:            dl.ID.String(),
+			AggregateType: dl.AggregateType,
+			AggregateId:   dl.AggregateID,
+			EventType:     dl.EventType,
+			Reason:        dl.Reason,
+			CreatedAt:     timestamppb.New(dl.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+// RequeueDeadLetters devuelve a pendientes los eventos apartados indicados.
+func (s *GrpcAdminServer) RequeueDeadLetters(ctx context.Context, req *pb.DeadLettersRequest) (*pb.DeadLettersResponse, error) {
+	return s.changeDeadLetters(ctx, req, sharedDomain.OutboxDeadLetterStore.RequeueDeadLetters)
+}
+
+// PurgeDeadLetters borra los eventos apartados indicados.
+func (s *GrpcAdminServer) PurgeDeadLetters(ctx context.Context, req *pb.DeadLettersRequest) (*pb.DeadLettersResponse, error) {
+	return s.changeDeadLetters(ctx, req, sharedDomain.OutboxDeadLetterStore.PurgeDeadLetters)
+}
+
+// changeDeadLetters aplica 'change' a los eventos apartados de la petición: los ids indicados o,
+// con all, todos.
+func (s *GrpcAdminServer) changeDeadLetters(ctx context.Context, req *pb.DeadLettersRequest, change func(sharedDomain.OutboxDeadLetterStore, context.Context, []uuid.UUID) (int, error)) (*pb.DeadLettersResponse, error) {
+	if len(req.GetIds()) == 0 && !req.GetAll() {
+		return nil, status.Error(codes.InvalidArgument, "ids are required unless all is true")
+	}
+	if len(req.GetIds()) > 0 && req.GetAll() {
+		return nil, status.Error(codes.InvalidArgument, "ids and all are mutually exclusive")
+	}
+	ids := make([]uuid.UUID, 0, len(req.GetIds()))
+	for _, raw := range req.GetIds() {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid id format: %q", raw)
+		}
+		ids = append(ids, id)
+	}
+	store, err := s.deadLetterStore(req.GetOutbox())
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := change(store, ctx, ids)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not change %s dead letters: %v", req.GetOutbox(), err)
+	}
+	return &pb.DeadLettersResponse{Count: int64(n)}, nil
+}
+
+// deadLetterStore devuelve el outbox 'name' o un error NotFound.
+func (s *GrpcAdminServer) deadLetterStore(name string) (sharedDomain.OutboxDeadLetterStore, error) {
+	store, ok := s.deadLetters[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown outbox %q", name)
+	}
+	return store, nil
+}
+
+// Verificación estática
+var _ pb.AdminServiceServer = (*GrpcAdminServer)(nil)
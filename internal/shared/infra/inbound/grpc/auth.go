@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+)
+
+// TokenVerifier valida un token de acceso y devuelve la identidad de quien lo presenta.
+type TokenVerifier interface {
+	Verify(ctx context.Context, raw string) (oidc.Principal, error)
+}
+
+// RequireRoleUnaryInterceptor exige en los métodos 'methods' (nombres completos, ej.
+// "/admin.AdminService/PurgeDeadLetters") un token "authorization: Bearer" válido según
+// 'verifier' de un usuario con el rol 'role'. Sin token, o con uno inválido, devuelve
+// codes.Unauthenticated; sin el rol, codes.PermissionDenied. Con 'verifier' nil (la API no tiene
+// autenticación) esos métodos se rechazan siempre. El resto de métodos pasan sin más.
+func RequireRoleUnaryInterceptor(verifier TokenVerifier, role string, methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if verifier == nil || role == "" {
+			return nil, status.Error(codes.PermissionDenied, "this method requires auth.mode=oidc and auth.admin_role")
+		}
+
+		raw, ok := bearerToken(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+		principal, err := verifier.Verify(ctx, raw)
+		if errors.Is(err, oidc.ErrUnavailable) {
+			return nil, status.Error(codes.Unavailable, "authentication is temporarily unavailable")
+		}
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		if !principal.HasRole(role) {
+			return nil, status.Error(codes.PermissionDenied, "forbidden")
+		}
+		return handler(oidc.NewContext(ctx, principal), req)
+	}
+}
+
+// bearerToken devuelve el token de la cabecera "authorization: Bearer" de la llamada.
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if raw, ok := strings.CutPrefix(value, "Bearer "); ok && strings.TrimSpace(raw) != "" {
+			return strings.TrimSpace(raw), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+)
+
+// fakeVerifier acepta los tokens de 'principals' y rechaza el resto.
+type fakeVerifier map[string]oidc.Principal
+
+func (v fakeVerifier) Verify(_ context.Context, raw string) (oidc.Principal, error) {
+	if p, ok := v[raw]; ok {
+		return p, nil
+	}
+	return oidc.Principal{}, errors.New("unknown token")
+}
+
+func TestRequireRoleUnaryInterceptor(t *testing.T) {
+	const purge = "/admin.AdminService/PurgeDeadLetters"
+	verifier := fakeVerifier{
+		"admin-token": {User: "ana", Roles: []string{"admin"}},
+		"user-token":  {User: "luis", Roles: []string{"viewer"}},
+	}
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	tests := []struct {
+		name     string
+		verifier TokenVerifier
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{"other method", verifier, context.Background(), "/admin.AdminService/ListDeadLetters", codes.OK},
+		{"admin", verifier, withToken("admin-token"), purge, codes.OK},
+		{"missing token", verifier, context.Background(), purge, codes.Unauthenticated},
+		{"invalid token", verifier, withToken("forged"), purge, codes.Unauthenticated},
+		{"missing role", verifier, withToken("user-token"), purge, codes.PermissionDenied},
+		{"no authentication", nil, withToken("admin-token"), purge, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			interceptor := RequireRoleUnaryInterceptor(tt.verifier, "admin", purge)
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return req, nil
+			}
+
+			// Act
+			_, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, called)
+		})
+	}
+}
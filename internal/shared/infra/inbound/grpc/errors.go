@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"sort"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// codeByCode traduce los códigos de error de la aplicación a códigos gRPC.
+var codeByCode = map[sharedDomain.Code]codes.Code{
+	sharedDomain.CodeInvalidArgument:    codes.InvalidArgument,
+	sharedDomain.CodeNotFound:           codes.NotFound,
+	sharedDomain.CodeAlreadyExists:      codes.AlreadyExists,
+	sharedDomain.CodeFailedPrecondition: codes.FailedPrecondition,
+	sharedDomain.CodeAborted:            codes.Aborted,
+	sharedDomain.CodeResourceExhausted:  codes.ResourceExhausted,
+	sharedDomain.CodeUnavailable:        codes.Unavailable,
+	sharedDomain.CodeDeadlineExceeded:   codes.DeadlineExceeded,
+	sharedDomain.CodeInternal:           codes.Internal,
+}
+
+// CodeOf devuelve el código gRPC del código de 'err' (ver sharedDomain.AsError).
+func CodeOf(err error) codes.Code {
+	if code, ok := codeByCode[sharedDomain.ErrorCode(err)]; ok {
+		return code
+	}
+	return codes.Internal
+}
+
+// Error convierte 'err' en el error de estado gRPC de su código, con su mensaje. Los detalles por
+// campo o parámetro viajan como un errdetails.BadRequest. nil devuelve nil.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	coded := sharedDomain.AsError(err)
+	st := status.New(CodeOf(err), coded.Message)
+	if len(coded.Details) == 0 {
+		return st.Err()
+	}
+	fields := make([]string, 0, len(coded.Details))
+	for field := range coded.Details {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: coded.Details[field],
+		})
+	}
+	if withDetails, err := st.WithDetails(badRequest); err == nil {
+		return withDetails.Err()
+	}
+	return st.Err()
+}
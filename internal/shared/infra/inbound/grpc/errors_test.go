@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{"not found", fmt.Errorf("could not delete user: %w", sharedDomain.NewError(sharedDomain.CodeNotFound, "user not found")), codes.NotFound, "could not delete user: user not found"},
+		{"failed precondition", sharedDomain.NewError(sharedDomain.CodeFailedPrecondition, "task cannot be marked as completed"), codes.FailedPrecondition, "task cannot be marked as completed"},
+		{"version mismatch", sharedDomain.NewError(sharedDomain.CodeAborted, "user version does not match"), codes.Aborted, "user version does not match"},
+		{"uncoded", errors.New("connection refused"), codes.Internal, "connection refused"},
+		{"deadline exceeded", fmt.Errorf("could not list tasks: %w", context.DeadlineExceeded), codes.DeadlineExceeded, "could not list tasks: context deadline exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			st := status.Convert(Error(tt.err))
+
+			// Assert
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.wantMessage, st.Message())
+		})
+	}
+	assert.NoError(t, Error(nil))
+}
+
+func TestError_Details(t *testing.T) {
+	// Arrange
+	err := sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid filters").
+		WithDetails(map[string]string{"status": "must be one of pending", "assignee_id": "must be a UUID"})
+
+	// Act
+	st := status.Convert(Error(err))
+
+	// Assert
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 2)
+	assert.Equal(t, "assignee_id", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, "must be a UUID", badRequest.FieldViolations[0].Description)
+}
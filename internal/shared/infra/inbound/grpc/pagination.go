@@ -0,0 +1,14 @@
+package grpc
+
+import (
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+)
+
+// Pagination devuelve la paginación de un listado, como en la API HTTP: por cursor si la
+// petición trae el campo cursor, aunque esté vacío (primera página), y si no, por offset.
+func Pagination(limit int, offset int32, cursor *string) sharedQuery.Pagination {
+	if cursor != nil {
+		return sharedQuery.CursorPagination{Limit: limit, Cursor: *cursor}
+	}
+	return sharedQuery.OffsetPagination{Limit: limit, Offset: int(offset)}
+}
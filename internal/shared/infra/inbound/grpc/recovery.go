@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/reporting"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// RecoveryUnaryInterceptor recupera los pánicos de los métodos unarios, los envía a Sentry
+// (si está habilitado) y los devuelve al cliente como codes.Internal.
+func RecoveryUnaryInterceptor(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				reporting.ReportPanic(ctx, "grpc", r)
+				tracing.Logger(ctx, log).Error("Pánico en método gRPC",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.Stack("stack"),
+				)
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
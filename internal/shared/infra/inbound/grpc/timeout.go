@@ -0,0 +1,22 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// TimeoutUnaryInterceptor pone un plazo de 'timeout' a los métodos unarios, salvo que el del
+// cliente venza antes. Con 0 no pone ninguno. Al vencer, los servicios devuelven
+// context.DeadlineExceeded, que Error traduce a codes.DeadlineExceeded.
+func TimeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// TracingUnaryInterceptor asocia a cada llamada un contexto de traza con los metadatos del
+// cliente (x-correlation-id, traceparent, x-actor, x-tenant-id...), como
+// http.TracingMiddleware. Sin 'verifier' (la API no tiene autenticación) el inquilino es el de
+// x-tenant-id, y uno mal formado es codes.InvalidArgument. Con 'verifier' sale del token
+// "authorization: Bearer", como en http.TenantMiddleware: un x-tenant-id distinto es
+// codes.PermissionDenied, un token inválido codes.Unauthenticated y, sin token, se ignora.
+func TracingUnaryInterceptor(verifier TokenVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		tc := tracing.FromHeadersOrNew(func(key string) string {
+			if values := md.Get(key); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		})
+		if verifier == nil {
+			if tc.TenantID != "" && !tracing.ValidTenantID(tc.TenantID) {
+				return nil, status.Error(codes.InvalidArgument, "invalid "+tracing.HeaderTenantID+" metadata")
+			}
+			return handler(tracing.NewContext(ctx, tc), req)
+		}
+
+		raw, ok := bearerToken(ctx)
+		if !ok {
+			tc.TenantID = ""
+			return handler(tracing.NewContext(ctx, tc), req)
+		}
+		principal, err := verifier.Verify(ctx, raw)
+		if errors.Is(err, oidc.ErrUnavailable) {
+			return nil, status.Error(codes.Unavailable, "authentication is temporarily unavailable")
+		}
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		if principal.Tenant != "" && !tracing.ValidTenantID(principal.Tenant) {
+			return nil, status.Error(codes.PermissionDenied, "invalid tenant in token")
+		}
+		if tc.TenantID != "" && tc.TenantID != principal.Tenant {
+			return nil, status.Error(codes.PermissionDenied, tracing.HeaderTenantID+" does not match the token's tenant")
+		}
+		tc.TenantID = principal.Tenant
+		return handler(oidc.NewContext(tracing.NewContext(ctx, tc), principal), req)
+	}
+}
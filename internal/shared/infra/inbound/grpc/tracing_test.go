@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func TestTracingUnaryInterceptor(t *testing.T) {
+	verifier := fakeVerifier{
+		"acme-token":   {User: "ana", Tenant: "acme"},
+		"no-tenant":    {User: "luis"},
+		"invalid-name": {User: "eva", Tenant: "a b"},
+	}
+	call := func(pairs ...string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+	}
+	tests := []struct {
+		name       string
+		verifier   TokenVerifier
+		ctx        context.Context
+		wantCode   codes.Code
+		wantTenant string
+	}{
+		{"header without auth", nil, call("x-tenant-id", "globex"), codes.OK, "globex"},
+		{"malformed header without auth", nil, call("x-tenant-id", "a b"), codes.InvalidArgument, ""},
+		{"tenant from token", verifier, call("authorization", "Bearer acme-token"), codes.OK, "acme"},
+		{"matching header", verifier, call("authorization", "Bearer acme-token", "x-tenant-id", "acme"), codes.OK, "acme"},
+		{"forged header", verifier, call("authorization", "Bearer acme-token", "x-tenant-id", "globex"), codes.PermissionDenied, ""},
+		{"forged header without tenant claim", verifier, call("authorization", "Bearer no-tenant", "x-tenant-id", "globex"), codes.PermissionDenied, ""},
+		{"invalid tenant in token", verifier, call("authorization", "Bearer invalid-name"), codes.PermissionDenied, ""},
+		{"invalid token", verifier, call("authorization", "Bearer forged", "x-tenant-id", "globex"), codes.Unauthenticated, ""},
+		{"header ignored without token", verifier, call("x-tenant-id", "globex"), codes.OK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			interceptor := TracingUnaryInterceptor(tt.verifier)
+			var tenant string
+			var principal oidc.Principal
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				tenant = tracing.TenantID(ctx)
+				principal, _ = oidc.FromContext(ctx)
+				return req, nil
+			}
+
+			// Act
+			_, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/task.TaskService/ListTasks"}, handler)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantTenant, tenant)
+			if tt.wantCode == codes.OK && tt.verifier != nil && tt.wantTenant != "" {
+				assert.Equal(t, tt.wantTenant, principal.Tenant)
+			}
+		})
+	}
+}
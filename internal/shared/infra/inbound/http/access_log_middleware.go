@@ -0,0 +1,71 @@
+package http
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// AuthUserKey es la clave del gin.Context donde el middleware de autenticación deja el
+// identificador del usuario autenticado, para que el log de acceso lo incluya.
+const AuthUserKey = "auth.user"
+
+// AccessLogMiddleware registra cada petición como una entrada estructurada: método, ruta,
+// estado, latencia, tamaño de la respuesta, usuario autenticado y los identificadores de la
+// traza (correlation_id, que es el request id devuelto al cliente, y trace_id). Sustituye al
+// logger en texto plano de gin.Default.
+//
+// 'sampling' asigna a una ruta (plantilla de gin) la fracción de peticiones que se registran;
+// las rutas sin entrada se registran siempre y los 5xx nunca se descartan.
+func AccessLogMiddleware(log *zap.Logger, sampling map[string]float64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		route := c.FullPath()
+		if rate, ok := sampling[route]; ok && status < http.StatusInternalServerError && !sampled(rate) {
+			return
+		}
+
+		level := zapcore.InfoLevel
+		if status >= http.StatusInternalServerError {
+			level = zapcore.WarnLevel
+		}
+		ce := tracing.Logger(c.Request.Context(), log).Check(level, "HTTP request")
+		if ce == nil {
+			return
+		}
+
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0 // Sin cuerpo
+		}
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("route", route),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("size", size),
+			zap.String("client_ip", c.ClientIP()),
+		}
+		if user := c.GetString(AuthUserKey); user != "" {
+			fields = append(fields, zap.String("user", user))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+		ce.Write(fields...)
+	}
+}
+
+func sampled(rate float64) bool {
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func newAccessLogRouter(sampling map[string]float64) (*gin.Engine, *observer.ObservedLogs) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	r := gin.New()
+	r.Use(TracingMiddleware(), AccessLogMiddleware(zap.New(core), sampling))
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.Set(AuthUserKey, "admin")
+		c.String(http.StatusOK, "hola")
+	})
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/broken", func(c *gin.Context) { c.Status(http.StatusServiceUnavailable) })
+	return r, logs
+}
+
+func TestAccessLogMiddleware_StructuredFields(t *testing.T) {
+	// Arrange
+	r, logs := newAccessLogRouter(nil)
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set(tracing.HeaderCorrelationID, "req-123")
+
+	// Act
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "GET", fields["method"])
+	assert.Equal(t, "/users/42", fields["path"])
+	assert.Equal(t, "/users/:id", fields["route"])
+	assert.EqualValues(t, http.StatusOK, fields["status"])
+	assert.EqualValues(t, 4, fields["size"])
+	assert.Equal(t, "admin", fields["user"])
+	assert.Equal(t, "req-123", fields["correlation_id"])
+	assert.Contains(t, fields, "latency")
+}
+
+func TestAccessLogMiddleware_Sampling(t *testing.T) {
+	// Arrange: /health nunca se registra, salvo que falle; /broken siempre por ser 5xx.
+	r, logs := newAccessLogRouter(map[string]float64{"/health": 0, "/broken": 0})
+
+	// Act
+	for i := 0; i < 5; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/broken", nil))
+
+	// Assert
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "/broken", entries[0].ContextMap()["route"])
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+}
@@ -0,0 +1,203 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/internal/config"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+	response "github.com/davicafu/hexagolab/pkg/utils"
+)
+
+// AdminHandler encapsula los endpoints de operación del proceso.
+type AdminHandler struct {
+	runtime *config.Runtime
+	sources AdminSources
+	log     *zap.Logger
+}
+
+// AdminSources son las fuentes de los endpoints de /admin que dependen de los dominios. El lag
+// de los consumidores y las lecturas de caché de /admin/stats salen de las métricas del proceso.
+type AdminSources struct {
+	// Entities cuenta las entidades de cada tipo, p.ej. "users".
+	Entities map[string]func(ctx context.Context) (int, error)
+	// Outboxes son los outbox de cada dominio que saben resumirse.
+	Outboxes map[string]sharedDomain.OutboxStatsReader
+	// DeadLetters son los outbox de cada dominio cuyos eventos apartados se pueden gestionar.
+	DeadLetters map[string]sharedDomain.OutboxDeadLetterStore
+}
+
+// NewAdminHandler crea un nuevo AdminHandler.
+func NewAdminHandler(runtime *config.Runtime, sources AdminSources, log *zap.Logger) *AdminHandler {
+	return &AdminHandler{runtime: runtime, sources: sources, log: log}
+}
+
+// Stats es la respuesta de /admin/stats.
+type Stats struct {
+	Entities  map[string]int                      `json:"entities"`
+	Outboxes  map[string]sharedDomain.OutboxStats `json:"outboxes"`
+	Consumers []metrics.ConsumerLag               `json:"consumers"`
+	Caches    map[string]metrics.CacheStats       `json:"caches"`
+}
+
+// Stats endpoint GET /admin/stats
+// Resume en un documento el estado de operación del proceso: entidades por tipo, eventos
+// pendientes y dead letters de cada outbox por tipo de evento, lag de los consumidores de Kafka
+// y lecturas de cada caché, para un panel de operación que no quiera leer /metrics.
+func (h *AdminHandler) Stats(c *gin.Context) {
+	ctx := c.Request.Context()
+	stats := Stats{
+		Entities:  make(map[string]int, len(h.sources.Entities)),
+		Outboxes:  make(map[string]sharedDomain.OutboxStats, len(h.sources.Outboxes)),
+		Consumers: metrics.ConsumerLags(),
+		Caches:    metrics.CacheLookups(),
+	}
+	for name, count := range h.sources.Entities {
+		n, err := count(ctx)
+		if err != nil {
+			WriteError(c, fmt.Errorf("counting %s: %w", name, err))
+			return
+		}
+		stats.Entities[name] = n
+	}
+	for name, outbox := range h.sources.Outboxes {
+		summary, err := outbox.OutboxStats(ctx)
+		if err != nil {
+			WriteError(c, fmt.Errorf("reading %s outbox: %w", name, err))
+			return
+		}
+		stats.Outboxes[name] = summary
+	}
+	response.SendSuccess(c, http.StatusOK, stats)
+}
+
+// defaultDeadLettersLimit es cuántos eventos apartados lista /admin/dead-letters por outbox si no
+// se indica limit.
+const defaultDeadLettersLimit = 100
+
+// DeadLetters endpoint GET /admin/dead-letters?outbox=user&limit=100
+// Lista los eventos apartados de cada outbox (o solo del indicado), del más antiguo al más
+// reciente, con el motivo por el que no se pudieron publicar.
+func (h *AdminHandler) DeadLetters(c *gin.Context) {
+	limit := defaultDeadLettersLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if v, err := strconv.Atoi(limitStr); err == nil {
+			limit = sanitize.Limit(v, limit)
+		}
+	}
+	stores := h.sources.DeadLetters
+	if name := c.Query("outbox"); name != "" {
+		store, err := h.deadLetterStore(name)
+		if err != nil {
+			WriteError(c, err)
+			return
+		}
+		stores = map[string]sharedDomain.OutboxDeadLetterStore{name: store}
+	}
+
+	deadLetters := make(map[string][]sharedDomain.DeadLetter, len(stores))
+	for name, store := range stores {
+		list, err := store.ListDeadLetters(c.Request.Context(), limit)
+		if err != nil {
+			WriteError(c, fmt.Errorf("listing %s dead letters: %w", name, err))
+			return
+		}
+		deadLetters[name] = append([]sharedDomain.DeadLetter{}, list...)
+	}
+	response.SendSuccess(c, http.StatusOK, deadLetters)
+}
+
+// deadLettersRequest es el cuerpo de los endpoints que actúan sobre eventos apartados: los ids
+// de un outbox, o todos sus eventos apartados con all.
+type deadLettersRequest struct {
+	Outbox string      `json:"outbox" binding:"required"`
+	IDs    []uuid.UUID `json:"ids"`
+	All    bool        `json:"all"`
+}
+
+// RequeueDeadLetters endpoint POST /admin/dead-letters/requeue
+// Devuelve a pendientes los eventos apartados indicados, sin motivo, para que el relayer vuelva
+// a publicarlos.
+func (h *AdminHandler) RequeueDeadLetters(c *gin.Context) {
+	h.changeDeadLetters(c, "requeued", sharedDomain.OutboxDeadLetterStore.RequeueDeadLetters)
+}
+
+// PurgeDeadLetters endpoint POST /admin/dead-letters/purge
+// Borra los eventos apartados indicados.
+func (h *AdminHandler) PurgeDeadLetters(c *gin.Context) {
+	h.changeDeadLetters(c, "purged", sharedDomain.OutboxDeadLetterStore.PurgeDeadLetters)
+}
+
+// changeDeadLetters aplica 'change' a los eventos apartados de la petición y responde cuántos
+// cambiaron en el campo 'field'.
+func (h *AdminHandler) changeDeadLetters(c *gin.Context, field string, change func(sharedDomain.OutboxDeadLetterStore, context.Context, []uuid.UUID) (int, error)) {
+	var req deadLettersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		WriteError(c, sharedDomain.WrapError(sharedDomain.CodeInvalidArgument, err))
+		return
+	}
+	if len(req.IDs) == 0 && !req.All {
+		WriteError(c, sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "ids are required unless all is true"))
+		return
+	}
+	if len(req.IDs) > 0 && req.All {
+		WriteError(c, sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "ids and all are mutually exclusive"))
+		return
+	}
+	store, err := h.deadLetterStore(req.Outbox)
+	if err != nil {
+		WriteError(c, err)
+		return
+	}
+
+	n, err := change(store, c.Request.Context(), req.IDs)
+	if err != nil {
+		WriteError(c, fmt.Errorf("%s dead letters: %w", req.Outbox, err))
+		return
+	}
+	h.log.Info("📮 Dead letters del outbox cambiados", zap.String("outbox", req.Outbox), zap.String("action", field), zap.Int("count", n))
+	response.SendSuccess(c, http.StatusOK, gin.H{"outbox": req.Outbox, field: n})
+}
+
+// deadLetterStore devuelve el outbox 'name' o un error not_found.
+func (h *AdminHandler) deadLetterStore(name string) (sharedDomain.OutboxDeadLetterStore, error) {
+	store, ok := h.sources.DeadLetters[name]
+	if !ok {
+		return nil, sharedDomain.Errorf(sharedDomain.CodeNotFound, "unknown outbox %q", name)
+	}
+	return store, nil
+}
+
+// ReloadConfig endpoint POST /admin/config/reload
+// Vuelve a leer la configuración y aplica los ajustes recargables, igual que SIGHUP.
+func (h *AdminHandler) ReloadConfig(c *gin.Context) {
+	result, err := ReloadConfig(h.runtime, h.log)
+	if err != nil {
+		response.SendBadRequest(c, err.Error())
+		return
+	}
+	response.SendSuccess(c, http.StatusOK, result)
+}
+
+// ReloadConfig recarga la configuración y registra el resultado. Lo comparten el endpoint y el manejador de SIGHUP.
+func ReloadConfig(runtime *config.Runtime, log *zap.Logger) (config.ReloadResult, error) {
+	result, err := runtime.Reload()
+	if err != nil {
+		log.Error("❌ Recarga de configuración rechazada, se mantiene la actual", zap.Error(err))
+		return result, err
+	}
+
+	log.Info("🔧 Configuración recargada",
+		zap.Strings("applied", result.Applied),
+		zap.Strings("requires_restart", result.RequiresRestart),
+	)
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+)
+
+func TestAdminHandler_Stats(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	outbox := memory.NewOutboxRepoMemory()
+	for _, eventType := range []string{"user.created", "user.created", "user.updated"} {
+		require.NoError(t, outbox.Add(ctx, sharedDomain.OutboxEvent{ID: uuid.New(), EventType: eventType, Payload: map[string]any{}, CreatedAt: time.Now()}))
+	}
+	pending, err := outbox.FetchPendingOutbox(ctx, 1)
+	require.NoError(t, err)
+	require.NoError(t, outbox.MarkOutboxDeadLetter(ctx, pending[0].ID, "schema violation"))
+	metrics.ObserveCacheLookup("stats-test", true, nil)
+
+	entities := map[string]func(context.Context) (int, error){
+		"users": func(context.Context) (int, error) { return 3, nil },
+	}
+	r := gin.New()
+	RegisterAdminRoutes(r, NewAdminHandler(nil, AdminSources{
+		Entities: entities,
+		Outboxes: map[string]sharedDomain.OutboxStatsReader{"user": outbox},
+	}, zap.NewNop()))
+
+	t.Run("summary", func(t *testing.T) {
+		// Act
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct{ Data Stats }
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, map[string]int{"users": 3}, body.Data.Entities)
+		assert.Equal(t, map[string]int{"user.created": 1, "user.updated": 1}, body.Data.Outboxes["user"].Pending)
+		assert.Equal(t, map[string]int{"user.created": 1}, body.Data.Outboxes["user"].DeadLetters)
+		assert.Equal(t, metrics.CacheStats{Hits: 1, HitRatio: 1}, body.Data.Caches["stats-test"])
+	})
+
+	t.Run("a source fails", func(t *testing.T) {
+		// Arrange
+		entities["tasks"] = func(context.Context) (int, error) { return 0, errors.New("db down") }
+		w := httptest.NewRecorder()
+
+		// Act
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestAdminHandler_DeadLetters(t *testing.T) {
+	// Arrange: dos eventos apartados en el outbox de usuarios
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	outbox := memory.NewOutboxRepoMemory()
+	var ids []uuid.UUID
+	for i := 0; i < 2; i++ {
+		evt := sharedDomain.OutboxEvent{ID: uuid.New(), EventType: "user.created", Payload: map[string]any{}, CreatedAt: time.Now().Add(time.Duration(i) * time.Second)}
+		require.NoError(t, outbox.Add(ctx, evt))
+		require.NoError(t, outbox.MarkOutboxDeadLetter(ctx, evt.ID, "schema violation"))
+		ids = append(ids, evt.ID)
+	}
+	r := gin.New()
+	handler := NewAdminHandler(nil, AdminSources{
+		DeadLetters: map[string]sharedDomain.OutboxDeadLetterStore{"user": outbox},
+	}, zap.NewNop())
+	RegisterAdminRoutes(r, handler)
+	RegisterDeadLetterChangeRoutes(r, handler)
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("list", func(t *testing.T) {
+		// Act
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/dead-letters?outbox=user&limit=1", nil))
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data map[string][]sharedDomain.DeadLetter
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Data["user"], 1)
+		assert.Equal(t, ids[0], body.Data["user"][0].ID)
+		assert.Equal(t, "schema violation", body.Data["user"][0].Reason)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		// Act + Assert
+		assert.Equal(t, http.StatusBadRequest, post("/admin/dead-letters/requeue", `{"outbox":"user"}`).Code, "Sin ids ni all")
+		assert.Equal(t, http.StatusBadRequest, post("/admin/dead-letters/purge", `{"outbox":"user","ids":["`+ids[0].String()+`"],"all":true}`).Code)
+		assert.Equal(t, http.StatusNotFound, post("/admin/dead-letters/purge", `{"outbox":"billing","all":true}`).Code)
+	})
+
+	t.Run("requeue and purge", func(t *testing.T) {
+		// Act
+		requeue := post("/admin/dead-letters/requeue", `{"outbox":"user","ids":["`+ids[0].String()+`"]}`)
+		purge := post("/admin/dead-letters/purge", `{"outbox":"user","all":true}`)
+
+		// Assert
+		assert.JSONEq(t, `{"data":{"outbox":"user","requeued":1}}`, requeue.Body.String())
+		assert.JSONEq(t, `{"data":{"outbox":"user","purged":1}}`, purge.Body.String())
+		pending, err := outbox.FetchPendingOutbox(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, ids[0], pending[0].ID)
+	})
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/audit"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// TokenVerifier valida un token de acceso y devuelve la identidad de quien lo presenta.
+type TokenVerifier interface {
+	Verify(ctx context.Context, raw string) (oidc.Principal, error)
+}
+
+// AuthMiddleware exige un token "Authorization: Bearer" válido en todas las rutas salvo las que
+// empiezan por uno de 'public' (sondas y métricas). Sin token, o con uno inválido, responde 401;
+// si no se puede validar porque el proveedor de identidad no responde, 503. Cada 401 emite un
+// auth.failed en 'auditor'.
+//
+// El usuario autenticado queda en AuthUserKey para el log de acceso, en el contexto de la
+// petición (oidc.FromContext) y como actor de la traza, en lugar del X-Actor que envíe el
+// cliente. Debe ir después de TracingMiddleware.
+func AuthMiddleware(verifier TokenVerifier, public []string, auditor *audit.Auditor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, prefix := range public {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		raw, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(raw) == "" {
+			auditor.AuthFailed(c.Request.Context(), sharedEvents.AuthFailed{
+				Route: route(c), Method: c.Request.Method, Reason: audit.ReasonMissingToken, ClientIP: c.ClientIP(),
+			})
+			c.Header("WWW-Authenticate", `Bearer realm="hexagolab"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+		ctx := c.Request.Context()
+		principal, err := verifier.Verify(ctx, strings.TrimSpace(raw))
+		if errors.Is(err, oidc.ErrUnavailable) {
+			_ = c.Error(err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "authentication is temporarily unavailable"})
+			return
+		}
+		if err != nil {
+			_ = c.Error(err)
+			auditor.AuthFailed(ctx, sharedEvents.AuthFailed{
+				Route: route(c), Method: c.Request.Method, Reason: audit.ReasonInvalidToken, Detail: err.Error(), ClientIP: c.ClientIP(),
+			})
+			c.Header("WWW-Authenticate", `Bearer realm="hexagolab", error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		if tc, ok := tracing.FromContext(ctx); ok {
+			tc.Actor = principal.User
+			ctx = tracing.NewContext(ctx, tc)
+		}
+		c.Request = c.Request.WithContext(oidc.NewContext(ctx, principal))
+		c.Set(AuthUserKey, principal.User)
+		c.Next()
+	}
+}
+
+// RequireRole deja pasar solo a los usuarios autenticados con el rol 'role' (ver AuthMiddleware);
+// al resto les responde 403 y emite un authz.denied en 'auditor'.
+func RequireRole(role string, auditor *audit.Auditor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal, ok := oidc.FromContext(c.Request.Context()); ok && principal.HasRole(role) {
+			c.Next()
+			return
+		}
+		auditor.AuthzDenied(c.Request.Context(), sharedEvents.AuthzDenied{
+			Route: route(c), Method: c.Request.Method, Reason: audit.ReasonMissingRole, RequiredRole: role, ClientIP: c.ClientIP(),
+		})
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	}
+}
+
+// route es la plantilla de la ruta de la petición (/users/:id), o la ruta pedida si no casa con
+// ninguna.
+func route(c *gin.Context) string {
+	if r := c.FullPath(); r != "" {
+		return r
+	}
+	return c.Request.URL.Path
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/audit"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+type fakeVerifier map[string]error
+
+func (f fakeVerifier) Verify(_ context.Context, raw string) (oidc.Principal, error) {
+	if err, ok := f[raw]; ok {
+		return oidc.Principal{}, err
+	}
+	if raw == "viewer" {
+		return oidc.Principal{User: "user-7", Roles: []string{"viewer"}}, nil
+	}
+	if tenant, ok := strings.CutPrefix(raw, "tenant:"); ok {
+		return oidc.Principal{User: "user-9", Tenant: tenant}, nil
+	}
+	return oidc.Principal{User: "user-42", Roles: []string{"admin"}}, nil
+}
+
+// newAuditLog devuelve un Auditor sin publicador y los logs de seguridad que escribe.
+func newAuditLog() (*audit.Auditor, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.WarnLevel)
+	return audit.New(nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.New(core)), logs
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	auditor, auditLogs := newAuditLog()
+	router.Use(TracingMiddleware(), AuthMiddleware(fakeVerifier{
+		"expired":  oidc.ErrInvalidToken,
+		"idp-down": oidc.ErrUnavailable,
+	}, []string{"/health"}, auditor))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/users", func(c *gin.Context) {
+		principal, _ := oidc.FromContext(c.Request.Context())
+		tc, _ := tracing.FromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"user": principal.User, "actor": tc.Actor, "log_user": c.GetString(AuthUserKey)})
+	})
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+		wantReason    string // Motivo del auth.failed emitido, si lo hay
+	}{
+		{"public path", "/health", "", http.StatusOK, "", ""},
+		{"missing token", "/users", "", http.StatusUnauthorized, `{"error":"missing bearer token"}`, "missing_token"},
+		{"not a bearer token", "/users", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, `{"error":"missing bearer token"}`, "missing_token"},
+		{"invalid token", "/users", "Bearer expired", http.StatusUnauthorized, `{"error":"invalid token"}`, "invalid_token"},
+		{"provider down", "/users", "Bearer idp-down", http.StatusServiceUnavailable, `{"error":"authentication is temporarily unavailable"}`, ""},
+		{"valid token", "/users", "Bearer good", http.StatusOK, `{"actor":"user-42","log_user":"user-42","user":"user-42"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLogs.TakeAll()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(tracing.HeaderActor, "spoofed")
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+			if tt.wantReason == "" {
+				assert.Zero(t, auditLogs.Len())
+				return
+			}
+			require.Equal(t, 1, auditLogs.Len())
+			fields := auditLogs.All()[0].ContextMap()
+			assert.Equal(t, audit.AuthFailed, fields["security_event"])
+			assert.Equal(t, tt.wantReason, fields["reason"])
+			assert.Equal(t, "/users", fields["route"])
+			assert.Equal(t, "spoofed", fields["actor"]) // El X-Actor declarado, sin verificar
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	auditor, auditLogs := newAuditLog()
+	router.Use(TracingMiddleware(), AuthMiddleware(fakeVerifier{}, nil, auditor))
+	router.POST("/admin/config/reload", RequireRole("admin", auditor), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Act
+	adminStatus := send("good")
+	viewerStatus := send("viewer")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, adminStatus)
+	assert.Equal(t, http.StatusForbidden, viewerStatus)
+	require.Equal(t, 1, auditLogs.Len())
+	fields := auditLogs.All()[0].ContextMap()
+	assert.Equal(t, audit.AuthzDenied, fields["security_event"])
+	assert.Equal(t, "/admin/config/reload", fields["route"])
+	assert.Equal(t, "admin", fields["required_role"])
+	assert.Equal(t, "user-7", fields["actor"])
+}
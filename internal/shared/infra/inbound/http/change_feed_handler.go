@@ -0,0 +1,116 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/relayer"
+	response "github.com/davicafu/hexagolab/pkg/utils"
+)
+
+// defaultChangesLimit es cuántos eventos devuelve el change feed si no se indica limit.
+const defaultChangesLimit = 100
+
+// changeFeedSettle es la antigüedad mínima de los eventos que sirve el change feed. Un evento
+// toma su fecha antes de que su transacción confirme, así que otro más reciente puede verse
+// antes que él; esperar a que se asienten evita que un cursor lo deje atrás.
+const changeFeedSettle = 2 * time.Second
+
+// ChangeFeedHandler sirve el change feed de un dominio: los eventos de su outbox en orden, con el
+// mismo sobre que se publica en el bus, para que los consumidores que no pueden conectarse a
+// Kafka se sincronicen por lotes.
+type ChangeFeedHandler struct {
+	reader  sharedDomain.OutboxChangeReader
+	encoder *relayer.Encoder
+	clock   sharedDomain.Clock
+}
+
+// NewChangeFeedHandler crea el handler sobre el outbox 'reader'; 'encoder' construye los sobres
+// como el relayer.
+func NewChangeFeedHandler(reader sharedDomain.OutboxChangeReader, encoder *relayer.Encoder, clock sharedDomain.Clock) *ChangeFeedHandler {
+	return &ChangeFeedHandler{reader: reader, encoder: encoder, clock: clock}
+}
+
+// ChangeFeedPage es la respuesta del change feed.
+type ChangeFeedPage struct {
+	Events []sharedEvents.IntegrationEvent `json:"events"`
+	// NextCursor es el since de la siguiente petición. Sin eventos nuevos es el mismo since.
+	NextCursor string `json:"next_cursor"`
+	// HasMore indica si ya hay más eventos detrás de NextCursor.
+	HasMore bool `json:"has_more"`
+}
+
+// Changes endpoint GET /users/changes?since=<cursor>&limit=100 (y /tasks/changes)
+// Devuelve los eventos del inquilino de la petición que siguen al cursor since, o desde el
+// primero si no se indica, publicados o no en el bus. Las dead letters y los eventos que no se
+// pueden publicar no forman parte del feed.
+func (h *ChangeFeedHandler) Changes(c *gin.Context) {
+	limit := defaultChangesLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if v, err := strconv.Atoi(limitStr); err == nil {
+			limit = sanitize.Limit(v, limit)
+		}
+	}
+	since := c.Query("since")
+	after, err := decodeChangeCursor(since)
+	if err != nil {
+		WriteError(c, err)
+		return
+	}
+
+	// Se pide uno más para saber si quedan eventos.
+	events, err := h.reader.ReadChanges(c.Request.Context(), after, limit+1)
+	if err != nil {
+		WriteError(c, fmt.Errorf("reading changes: %w", err))
+		return
+	}
+	now := h.clock.Now()
+	page := ChangeFeedPage{Events: []sharedEvents.IntegrationEvent{}, NextCursor: since}
+	for i, evt := range events {
+		if i == limit || evt.CreatedAt.After(now.Add(-changeFeedSettle)) {
+			page.HasMore = i == limit
+			break
+		}
+		envelope, err := h.encoder.Encode(evt, now)
+		var payloadErr *relayer.PayloadError
+		switch {
+		case errors.Is(err, relayer.ErrUnknownEventType), errors.As(err, &payloadErr):
+			// El relayer tampoco lo publicará: se salta.
+		case err != nil:
+			WriteError(c, fmt.Errorf("encoding event %s: %w", evt.ID, err))
+			return
+		default:
+			page.Events = append(page.Events, envelope)
+		}
+		page.NextCursor = sharedQuery.NewCursor(evt.ID, evt.CreatedAt).Encode()
+	}
+	response.SendSuccess(c, http.StatusOK, page)
+}
+
+// decodeChangeCursor traduce el cursor since a una posición del outbox; vacío es el principio.
+func decodeChangeCursor(since string) (sharedDomain.OutboxPosition, error) {
+	if since == "" {
+		return sharedDomain.OutboxPosition{}, nil
+	}
+	cursor, err := sharedQuery.DecodeCursor(since)
+	if err != nil {
+		return sharedDomain.OutboxPosition{}, err
+	}
+	if len(cursor.Values) != 1 {
+		return sharedDomain.OutboxPosition{}, fmt.Errorf("%w: invalid cursor", sharedQuery.ErrInvalidQuery)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, cursor.Values[0])
+	if err != nil {
+		return sharedDomain.OutboxPosition{}, fmt.Errorf("%w: invalid cursor", sharedQuery.ErrInvalidQuery)
+	}
+	return sharedDomain.OutboxPosition{CreatedAt: createdAt, ID: cursor.ID}, nil
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// FilterType es el tipo de valor que admite un parámetro de filtro.
+type FilterType int
+
+const (
+	FilterString FilterType = iota
+	FilterInt
+	FilterUUID
+	FilterTime     // RFC 3339 o solo la fecha (YYYY-MM-DD), en UTC
+	FilterDuration // Duración positiva de Go (ej. "90m", "24h") o en días (ej. "7d")
+)
+
+// Filter declara un parámetro de query por el que se puede filtrar un listado.
+type Filter struct {
+	Param string     // Nombre del parámetro, ej. "status"
+	Type  FilterType // Un valor que no se puede convertir es un 400
+	// Operadores que admite el parámetro: "param=v" es OpEq y el resto se escriben con el
+	// operador entre corchetes, ej. "created_at[gte]=v". Sin Ops solo se admite OpEq.
+	Ops []sharedDomain.Operator
+	// Valores admitidos, para los FilterString que son una enumeración.
+	Enum []string
+	// Criteria construye el criterio con el operador y el valor ya convertido (string, int,
+	// uuid.UUID, time.Time o time.Duration según Type). Si es nil, el criterio es {Param, op, valor}.
+	Criteria func(op sharedDomain.Operator, value any) sharedDomain.Criteria
+}
+
+// filterOps son los operadores que se pueden escribir entre corchetes en un parámetro.
+var filterOps = map[string]sharedDomain.Operator{
+	"eq":    sharedDomain.OpEq,
+	"gt":    sharedDomain.OpGt,
+	"gte":   sharedDomain.OpGte,
+	"lt":    sharedDomain.OpLt,
+	"lte":   sharedDomain.OpLte,
+	"like":  sharedDomain.OpLike,
+	"ilike": sharedDomain.OpILike,
+}
+
+// FilterError reúne los parámetros de filtro inválidos de una petición, con el motivo de cada
+// uno, para que el cliente los corrija todos de una vez.
+type FilterError struct {
+	Params map[string]string
+}
+
+func (e *FilterError) Error() string {
+	params := make([]string, 0, len(e.Params))
+	for param := range e.Params {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+	details := make([]string, 0, len(params))
+	for _, param := range params {
+		details = append(details, param+": "+e.Params[param])
+	}
+	return "invalid filters: " + strings.Join(details, "; ")
+}
+
+// Unwrap le da al error el código CodeInvalidArgument y el motivo de cada parámetro como detalles.
+func (e *FilterError) Unwrap() error {
+	return sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid filters").WithDetails(e.Params)
+}
+
+// CriteriaBinder traduce los parámetros de query de un listado a criterios, a partir de los
+// filtros que declara cada handler. Los parámetros que no son filtros (limit, sort...) se ignoran.
+type CriteriaBinder struct {
+	filters []Filter
+}
+
+// NewCriteriaBinder es el constructor.
+func NewCriteriaBinder(filters ...Filter) *CriteriaBinder {
+	return &CriteriaBinder{filters: filters}
+}
+
+// Bind devuelve el AND de los filtros no vacíos de 'query' o un *FilterError con todos los
+// parámetros inválidos: un valor del tipo equivocado o fuera de la enumeración, un operador
+// no admitido o un parámetro repetido.
+func (b *CriteriaBinder) Bind(query url.Values) (sharedDomain.CompositeCriteria, error) {
+	var criterias []sharedDomain.Criteria
+	invalid := map[string]string{}
+
+	for _, filter := range b.filters {
+		for _, key := range filterKeys(query, filter.Param) {
+			values := query[key]
+			if len(values) == 1 && values[0] == "" {
+				continue // Un parámetro vacío es lo mismo que no filtrar
+			}
+			if len(values) > 1 {
+				invalid[key] = "must be given once"
+				continue
+			}
+
+			op, err := filter.operator(key)
+			if err != nil {
+				invalid[key] = err.Error()
+				continue
+			}
+			value, err := filter.parse(values[0])
+			if err != nil {
+				invalid[key] = err.Error()
+				continue
+			}
+
+			if filter.Criteria != nil {
+				criterias = append(criterias, filter.Criteria(op, value))
+			} else {
+				criterias = append(criterias, criterion{Field: filter.Param, Op: op, Value: value})
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return sharedDomain.CompositeCriteria{}, &FilterError{Params: invalid}
+	}
+	return sharedDomain.And(criterias...), nil
+}
+
+// filterKeys devuelve, ordenadas, las claves de 'query' que corresponden a 'param': el propio
+// parámetro y sus variantes con operador ("param[...]").
+func filterKeys(query url.Values, param string) []string {
+	var keys []string
+	for key := range query {
+		if key == param || (strings.HasPrefix(key, param+"[") && strings.HasSuffix(key, "]")) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// operator devuelve el operador de la clave 'key' si el filtro lo admite.
+func (f Filter) operator(key string) (sharedDomain.Operator, error) {
+	op := sharedDomain.OpEq
+	if name, ok := strings.CutPrefix(key, f.Param+"["); ok {
+		name = strings.TrimSuffix(name, "]")
+		if op, ok = filterOps[name]; !ok {
+			return "", fmt.Errorf("unknown operator %q", name)
+		}
+	}
+	allowed := f.Ops
+	if len(allowed) == 0 {
+		allowed = []sharedDomain.Operator{sharedDomain.OpEq}
+	}
+	if !slices.Contains(allowed, op) {
+		return "", fmt.Errorf("operator %s is not allowed", op)
+	}
+	return op, nil
+}
+
+// parse convierte el texto del parámetro al tipo del filtro.
+func (f Filter) parse(text string) (any, error) {
+	switch f.Type {
+	case FilterInt:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return n, nil
+	case FilterUUID:
+		id, err := uuid.Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("must be a UUID")
+		}
+		return id, nil
+	case FilterTime:
+		if t, err := time.Parse(time.RFC3339, text); err == nil {
+			return t.UTC(), nil
+		}
+		t, err := time.Parse(time.DateOnly, text)
+		if err != nil {
+			return nil, fmt.Errorf("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		return t, nil
+	case FilterDuration:
+		d, err := parseDuration(text)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("must be a positive duration such as 24h or 7d")
+		}
+		return d, nil
+	}
+	if len(f.Enum) > 0 && !slices.Contains(f.Enum, text) {
+		return nil, fmt.Errorf("must be one of %s", strings.Join(f.Enum, ", "))
+	}
+	return text, nil
+}
+
+// parseDuration acepta las duraciones de time.ParseDuration y un número entero de días con "d".
+func parseDuration(text string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(text, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(text)
+}
+
+// criterion es el criterio de un filtro sin constructor propio.
+type criterion sharedDomain.Criterion
+
+func (c criterion) ToConditions() []sharedDomain.Criterion {
+	return []sharedDomain.Criterion{sharedDomain.Criterion(c)}
+}
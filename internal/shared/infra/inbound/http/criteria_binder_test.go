@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// nameLike es un criterio de dominio de prueba, para comprobar que Filter.Criteria se usa.
+type nameLike struct{ name string }
+
+func (c nameLike) ToConditions() []sharedDomain.Criterion {
+	return []sharedDomain.Criterion{{Field: "nombre", Op: sharedDomain.OpILike, Value: "%" + c.name + "%"}}
+}
+
+var testBinder = NewCriteriaBinder(
+	Filter{Param: "nombre", Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria { return nameLike{name: v.(string)} }},
+	Filter{Param: "status", Enum: []string{"pending", "completed"}},
+	Filter{Param: "owner", Type: FilterUUID},
+	Filter{Param: "age", Type: FilterInt, Ops: []sharedDomain.Operator{sharedDomain.OpEq, sharedDomain.OpGte}},
+	Filter{Param: "created_at", Type: FilterTime, Ops: []sharedDomain.Operator{sharedDomain.OpGte, sharedDomain.OpLte}},
+	Filter{Param: "within", Type: FilterDuration},
+)
+
+func TestCriteriaBinder_Bind(t *testing.T) {
+	// Arrange
+	owner := uuid.New()
+	query := url.Values{
+		"nombre":          {"ana"},
+		"status":          {"pending"},
+		"owner":           {owner.String()},
+		"age[gte]":        {"18"},
+		"created_at[lte]": {"2025-03-01"},
+		"created_at[gte]": {"2025-01-01T10:00:00+01:00"},
+		"within":          {"7d"},
+		"limit":           {"10"},
+		"email":           {""},
+	}
+
+	// Act
+	criteria, err := testBinder.Bind(query)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, sharedDomain.OpAnd, criteria.Operator)
+	assert.ElementsMatch(t, []sharedDomain.Criterion{
+		{Field: "nombre", Op: sharedDomain.OpILike, Value: "%ana%"},
+		{Field: "status", Op: sharedDomain.OpEq, Value: "pending"},
+		{Field: "owner", Op: sharedDomain.OpEq, Value: owner},
+		{Field: "age", Op: sharedDomain.OpGte, Value: 18},
+		{Field: "created_at", Op: sharedDomain.OpGte, Value: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)},
+		{Field: "created_at", Op: sharedDomain.OpLte, Value: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Field: "within", Op: sharedDomain.OpEq, Value: 7 * 24 * time.Hour},
+	}, criteria.ToConditions())
+}
+
+func TestCriteriaBinder_EmptyValuesDoNotFilter(t *testing.T) {
+	criteria, err := testBinder.Bind(url.Values{"nombre": {""}, "owner": {""}})
+
+	require.NoError(t, err)
+	assert.Empty(t, criteria.ToConditions())
+}
+
+func TestCriteriaBinder_InvalidParams(t *testing.T) {
+	// Arrange
+	query := url.Values{
+		"status":         {"archived"},
+		"owner":          {"nobody"},
+		"age":            {"old"},
+		"age[lt]":        {"30"},
+		"age[between]":   {"1,2"},
+		"created_at":     {"2025-01-01"},
+		"created_at[gt]": {"yesterday"},
+		"nombre":         {"ana", "luis"},
+		"within":         {"0s"},
+	}
+
+	// Act
+	_, err := testBinder.Bind(query)
+
+	// Assert: se informa de todos los parámetros a la vez.
+	var filterErr *FilterError
+	require.ErrorAs(t, err, &filterErr)
+	assert.Equal(t, map[string]string{
+		"status":         "must be one of pending, completed",
+		"owner":          "must be a UUID",
+		"age":            "must be an integer",
+		"age[lt]":        "operator < is not allowed",
+		"age[between]":   `unknown operator "between"`,
+		"created_at":     "operator = is not allowed",
+		"created_at[gt]": "operator > is not allowed",
+		"nombre":         "must be given once",
+		"within":         "must be a positive duration such as 24h or 7d",
+	}, filterErr.Params)
+	assert.Contains(t, err.Error(), "age: must be an integer; age[between]")
+}
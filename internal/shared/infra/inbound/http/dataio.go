@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/pkg/dataio"
+)
+
+// exportFlushEvery es cada cuántos registros se vacía el buffer de una exportación.
+const exportFlushEvery = 100
+
+// DataFormat lee el formato de una exportación o importación de ?format (ndjson, por defecto, o
+// csv). En una importación sin ?format, un cuerpo text/csv es CSV. Si el formato no es válido
+// responde 400 y devuelve false.
+func DataFormat(c *gin.Context) (dataio.Format, bool) {
+	name := c.Query("format")
+	if name == "" && c.ContentType() == dataio.FormatCSV.ContentType() {
+		name = string(dataio.FormatCSV)
+	}
+	format, err := dataio.ParseFormat(name)
+	if err != nil {
+		WriteError(c, sharedDomain.WrapError(sharedDomain.CodeInvalidArgument, err))
+		return "", false
+	}
+	return format, true
+}
+
+// Export responde con los registros que escribe 'stream', con 'columns', como el fichero adjunto
+// '<name>.<formato>'. Las cabeceras se envían con el primer registro: hasta entonces un error de
+// 'stream' aún es un 4xx/5xx; después solo queda cortar la respuesta y dejar el error en el
+// access log.
+func Export(c *gin.Context, format dataio.Format, name string, columns []string, stream func(write func(values []string) error) error) {
+	started := false
+	start := func() {
+		c.Header("Content-Type", format.ContentType())
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+		c.Status(http.StatusOK)
+		started = true
+	}
+	w := dataio.NewWriter(c.Writer, format, columns)
+	written := 0
+	err := stream(func(values []string) error {
+		if !started {
+			start()
+		}
+		if err := w.Write(values); err != nil {
+			return err
+		}
+		if written++; written%exportFlushEvery == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		if !started {
+			start()
+		}
+		if err := w.Flush(); err != nil {
+			_ = c.Error(err)
+		}
+		c.Writer.WriteHeaderNow()
+	case started:
+		_ = c.Error(err)
+	default:
+		WriteError(c, err)
+	}
+}
+
+// Import crea con 'create' un registro por fila del cuerpo, en el formato de DataFormat, y
+// responde 200 con el dataio.Report: las filas creadas, las que fallaron y el motivo de cada una.
+// Solo un cuerpo que no se puede leer hasta el final es un error de la petición.
+func Import(c *gin.Context, create func(ctx context.Context, rec dataio.Record) error) {
+	format, ok := DataFormat(c)
+	if !ok {
+		return
+	}
+	report, err := dataio.Import(c.Request.Context(), dataio.NewReader(c.Request.Body, format), create)
+	if err != nil {
+		WriteError(c, sharedDomain.WrapError(sharedDomain.CodeInvalidArgument, err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	response "github.com/davicafu/hexagolab/pkg/utils"
+)
+
+// statusByCode traduce los códigos de error de la aplicación a estados HTTP.
+var statusByCode = map[sharedDomain.Code]int{
+	sharedDomain.CodeInvalidArgument:    http.StatusBadRequest,
+	sharedDomain.CodeNotFound:           http.StatusNotFound,
+	sharedDomain.CodeAlreadyExists:      http.StatusConflict,
+	sharedDomain.CodeFailedPrecondition: http.StatusConflict,
+	sharedDomain.CodeAborted:            http.StatusPreconditionFailed,
+	sharedDomain.CodeResourceExhausted:  http.StatusTooManyRequests,
+	sharedDomain.CodeUnavailable:        http.StatusServiceUnavailable,
+	sharedDomain.CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	sharedDomain.CodeInternal:           http.StatusInternalServerError,
+}
+
+// StatusOf devuelve el estado HTTP del código de 'err' (ver sharedDomain.AsError).
+func StatusOf(err error) int {
+	if status, ok := statusByCode[sharedDomain.ErrorCode(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteError responde 'err' con su estado HTTP y el cuerpo de error estándar:
+//
+//	{"error": {"code": "not_found", "message": "task not found"}}
+//
+// con los detalles por campo o parámetro, si los hay. Una cuota agotada lleva además sus
+// cabeceras (ver setQuotaHeaders).
+func WriteError(c *gin.Context, err error) {
+	setQuotaHeaders(c, err)
+	c.JSON(StatusOf(err), gin.H{"error": ErrorBody(err)})
+}
+
+// ErrorBody es el cuerpo de error estándar de 'err', para las respuestas que informan de un
+// error por elemento en lugar de fallar enteras.
+func ErrorBody(err error) response.ErrorResponse {
+	coded := sharedDomain.AsError(err)
+	return response.ErrorResponse{
+		Code:    string(coded.Code),
+		Message: coded.Message,
+		Details: coded.Details,
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/quota"
+)
+
+func TestWriteError(t *testing.T) {
+	errNotFound := sharedDomain.NewError(sharedDomain.CodeNotFound, "task not found")
+	exceeded := &quota.ExceededError{Limit: quota.Limit{Action: "task.create", Max: 100, Period: quota.Daily}, Reset: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"not found", fmt.Errorf("could not get task: %w", errNotFound), http.StatusNotFound, `{"error":{"code":"not_found","message":"could not get task: task not found"}}`},
+		{"invalid filters", &FilterError{Params: map[string]string{"status": "must be one of pending"}}, http.StatusBadRequest, `{"error":{"code":"invalid_argument","message":"invalid filters: status: must be one of pending","details":{"status":"must be one of pending"}}}`},
+		{"conflict", sharedDomain.NewError(sharedDomain.CodeAlreadyExists, "user already exists"), http.StatusConflict, `{"error":{"code":"already_exists","message":"user already exists"}}`},
+		{"version mismatch", sharedDomain.NewError(sharedDomain.CodeAborted, "task version does not match"), http.StatusPreconditionFailed, `{"error":{"code":"aborted","message":"task version does not match"}}`},
+		{"uncoded", fmt.Errorf("database is locked"), http.StatusInternalServerError, `{"error":{"code":"internal","message":"database is locked"}}`},
+		{"deadline exceeded", fmt.Errorf("listing tasks: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, `{"error":{"code":"deadline_exceeded","message":"listing tasks: context deadline exceeded"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+
+			// Act
+			WriteError(c, tt.err)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+
+		// Act
+		WriteError(c, exceeded)
+
+		// Assert
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "100/day", rec.Header().Get(HeaderQuotaLimit))
+		assert.Equal(t, "0", rec.Header().Get(HeaderQuotaRemaining))
+		assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+	})
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+)
+
+// EventCatalogEntry describe el contrato de un tipo de evento para quien lo consume.
+type EventCatalogEntry struct {
+	Name    string          `json:"name"`
+	Topic   string          `json:"topic"`
+	Version int             `json:"version"`
+	Schema  json.RawMessage `json:"schema,omitempty"`
+	Example json.RawMessage `json:"example,omitempty"`
+}
+
+// EventCatalogHandler expone el catálogo de eventos publicados, para descubrir sus contratos sin
+// leer el código.
+type EventCatalogHandler struct {
+	catalog []EventCatalogEntry
+}
+
+// NewEventCatalogHandler crea el handler a partir del registro de eventos de todos los dominios.
+// El registro es fijo, así que el catálogo se construye una sola vez.
+func NewEventCatalogHandler(registry map[string]sharedEvents.EventMetadata) *EventCatalogHandler {
+	catalog := make([]EventCatalogEntry, 0, len(registry))
+	for name, metadata := range registry {
+		entry := EventCatalogEntry{Name: name, Topic: metadata.Topic, Version: metadata.Version}
+		if metadata.Schema != nil {
+			entry.Schema, _ = metadata.Schema.MarshalJSON()
+			entry.Example = metadata.Schema.Example()
+		}
+		catalog = append(catalog, entry)
+	}
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].Name < catalog[j].Name })
+	return &EventCatalogHandler{catalog: catalog}
+}
+
+// Catalog endpoint GET /events/catalog
+// Lista los tipos de evento ordenados por nombre, con su topic, versión, esquema y un ejemplo.
+func (h *EventCatalogHandler) Catalog(c *gin.Context) {
+	c.JSON(http.StatusOK, h.catalog)
+}
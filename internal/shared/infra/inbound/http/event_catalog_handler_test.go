@@ -0,0 +1,40 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+)
+
+func TestEventCatalogHandler_Catalog(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	schema := sharedEvents.MustCompileSchema(`{"type": "object", "required": ["id"], "examples": [{"id": "1"}]}`)
+	r := gin.New()
+	RegisterEventRoutes(r, NewEventCatalogHandler(map[string]sharedEvents.EventMetadata{
+		"order.deleted": {Topic: "order", Version: 1},
+		"order.created": {Topic: "order", Version: 2, Schema: schema},
+	}))
+	w := httptest.NewRecorder()
+
+	// Act
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/catalog", nil))
+
+	// Assert: ordenados por nombre; sin esquema no hay ni esquema ni ejemplo.
+	require.Equal(t, http.StatusOK, w.Code)
+	var catalog []EventCatalogEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &catalog))
+	require.Len(t, catalog, 2)
+	assert.Equal(t, "order.created", catalog[0].Name)
+	assert.Equal(t, 2, catalog[0].Version)
+	assert.JSONEq(t, `{"type": "object", "required": ["id"], "examples": [{"id": "1"}]}`, string(catalog[0].Schema))
+	assert.JSONEq(t, `{"id": "1"}`, string(catalog[0].Example))
+	assert.Equal(t, EventCatalogEntry{Name: "order.deleted", Topic: "order", Version: 1}, catalog[1])
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/health"
+)
+
+// HealthHandler expone las sondas de vida y de disponibilidad del proceso.
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+// NewHealthHandler crea un nuevo HealthHandler.
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{registry: registry}
+}
+
+// Live endpoint GET /health
+// Solo indica que el proceso responde; no comprueba dependencias.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready endpoint GET /readyz
+// Comprueba cada dependencia registrada y devuelve su estado, latencia y último error.
+// Responde 503 si alguna no está disponible, para que el orquestador deje de enviar tráfico.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.registry.Check(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
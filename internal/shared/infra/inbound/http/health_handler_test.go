@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/health"
+)
+
+func TestHealthHandler_Ready(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := health.NewRegistry(0)
+	registry.Register("postgres", func(ctx context.Context) error { return nil })
+	r := gin.New()
+	RegisterHealthRoutes(r, NewHealthHandler(registry))
+
+	t.Run("all components up", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("a component down", func(t *testing.T) {
+		// Arrange
+		registry.Register("kafka", func(ctx context.Context) error { return errors.New("dial tcp: connection refused") })
+		w := httptest.NewRecorder()
+
+		// Act
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var report health.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusNotReady, report.Status)
+		assert.Equal(t, health.StatusUp, report.Components["postgres"].Status)
+		assert.Equal(t, health.StatusDown, report.Components["kafka"].Status)
+		assert.Equal(t, "dial tcp: connection refused", report.Components["kafka"].LastError)
+	})
+
+	t.Run("liveness ignores dependencies", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+var (
+	errIfMatchFailed   = sharedDomain.NewError(sharedDomain.CodeAborted, "If-Match does not name a version of the resource")
+	errIfMatchMultiple = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "If-Match must name a single version")
+)
+
+// ETag devuelve un ETag fuerte calculado a partir de 'parts', que deben cambiar siempre que
+// cambie la entidad (ej. su id y su fecha de última modificación).
+func ETag(parts ...string) string {
+	h := fnv.New64a()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0}) // Separador: ("ab", "c") y ("a", "bc") no deben coincidir
+	}
+	return `"` + strconv.FormatUint(h.Sum64(), 16) + `"`
+}
+
+// VersionETag devuelve el ETag fuerte de la versión 'version' de una entidad ("3"), el que los
+// clientes devuelven en If-Match (ver IfMatch).
+func VersionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// IfMatch devuelve la versión que exige la cabecera If-Match de una escritura, en el formato de
+// VersionETag. 'conditional' es false si la petición no lleva la cabecera o lleva "*": basta con
+// que la entidad exista. Un If-Match que no nombra ninguna versión (p.ej. solo ETags débiles, que
+// no valen para escribir) no puede coincidir y devuelve un error 412; uno con varias versiones,
+// un error 400.
+func IfMatch(c *gin.Context) (version int64, conditional bool, err error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, false, nil
+	}
+	found := false
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		unquoted, ok := strings.CutPrefix(candidate, `"`)
+		if !ok || !strings.HasSuffix(unquoted, `"`) {
+			continue
+		}
+		v, err := strconv.ParseInt(strings.TrimSuffix(unquoted, `"`), 10, 64)
+		if err != nil {
+			continue
+		}
+		if found && v != version {
+			return 0, false, errIfMatchMultiple
+		}
+		version, found = v, true
+	}
+	if !found {
+		return 0, false, errIfMatchFailed
+	}
+	return version, true, nil
+}
+
+// NotModified pone la cabecera ETag de la respuesta y, si el cliente ya tiene esa versión
+// (If-None-Match), responde 304 sin cuerpo y devuelve true: el handler no debe escribir nada más.
+func NotModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// etagMatches aplica la comparación débil de If-None-Match: coincide "*" o cualquier ETag de
+// la lista, con o sin el prefijo W/.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheControlMiddleware pone la cabecera Cache-Control de 'policies' en las respuestas a GET y
+// HEAD de cada ruta (plantilla de gin, ej. /users/:id). Las rutas sin entrada no la llevan.
+func CacheControlMiddleware(policies map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			if policy, ok := policies[c.FullPath()]; ok {
+				c.Header("Cache-Control", policy)
+			}
+		}
+		c.Next()
+	}
+}
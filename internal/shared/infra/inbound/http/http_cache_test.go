@@ -0,0 +1,103 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, ETag("a", "2025-03-01"), ETag("a", "2025-03-01"))
+	assert.NotEqual(t, ETag("a", "2025-03-01"), ETag("a", "2025-03-02"))
+	assert.NotEqual(t, ETag("ab", "c"), ETag("a", "bc"))
+	assert.Regexp(t, `^"[0-9a-f]+"$`, ETag("a"))
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		ifMatch         string
+		wantVersion     int64
+		wantConditional bool
+		wantStatus      int
+	}{
+		{"without header", "", 0, false, 0},
+		{"any", "*", 0, false, 0},
+		{"version", VersionETag(3), 3, true, 0},
+		{"in a list with other etags", `W/"3", "abc", "3"`, 3, true, 0},
+		{"weak", `W/"3"`, 0, false, http.StatusPreconditionFailed},
+		{"not a version", `"a1b2c3"`, 0, false, http.StatusPreconditionFailed},
+		{"several versions", `"3", "4"`, 0, false, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/tasks/1", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			// Act
+			version, conditional, err := IfMatch(c)
+
+			// Assert
+			assert.Equal(t, tt.wantVersion, version)
+			assert.Equal(t, tt.wantConditional, conditional)
+			if tt.wantStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.wantStatus, StatusOf(err))
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	etag := ETag("task", "v1")
+	router := gin.New()
+	router.Use(CacheControlMiddleware(map[string]string{"/tasks/:id": "private, no-cache"}))
+	router.GET("/tasks/:id", func(c *gin.Context) {
+		if NotModified(c, etag) {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{"without validator", "", http.StatusOK},
+		{"same etag", etag, http.StatusNotModified},
+		{"weak and in a list", `"other", W/` + etag, http.StatusNotModified},
+		{"any", "*", http.StatusNotModified},
+		{"stale etag", ETag("task", "v0"), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, etag, rec.Header().Get("ETag"))
+			assert.Equal(t, "private, no-cache", rec.Header().Get("Cache-Control"))
+			if tt.wantStatus == http.StatusNotModified {
+				assert.Empty(t, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HTTPSRedirect redirige cualquier petición HTTP plana a la misma URL en HTTPS, en 'httpsPort'.
+// Usa 308 para que los clientes repitan el método y el cuerpo (un POST sigue siendo un POST).
+func HTTPSRedirect(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]" // Las IPv6 van entre corchetes en la URL
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirect(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		host      string
+		target    string
+		want      string
+	}{
+		{"default port", "443", "api.example.com", "/tasks?page=2", "https://api.example.com/tasks?page=2"},
+		{"drops the http port", "443", "api.example.com:80", "/users/42", "https://api.example.com/users/42"},
+		{"custom https port", "8443", "api.example.com:8080", "/health", "https://api.example.com:8443/health"},
+		{"ipv6 host", "443", "[2001:db8::1]:80", "/", "https://[2001:db8::1]/"},
+		{"ipv6 host and custom port", "8443", "[2001:db8::1]", "/", "https://[2001:db8::1]:8443/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+
+			// Act
+			HTTPSRedirect(tt.httpsPort).ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+			assert.Equal(t, tt.want, rec.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// ParseIDs lee el parámetro de una lectura múltiple (ej. ?ids=a,b,c): ids separados por comas,
+// sin vacíos. El primer id que no es un UUID es un error con su posición.
+func ParseIDs(raw string) ([]uuid.UUID, error) {
+	parts := strings.Split(raw, ",")
+	ids := make([]uuid.UUID, 0, len(parts))
+	for i, part := range parts {
+		id, err := uuid.Parse(strings.TrimSpace(part))
+		if err != nil {
+			return nil, sharedDomain.Errorf(sharedDomain.CodeInvalidArgument, "ids[%d] must be a UUID", i)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDs(t *testing.T) {
+	// Arrange
+	a, b := uuid.New(), uuid.New()
+
+	// Act
+	ids, err := ParseIDs(a.String() + ", " + b.String())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{a, b}, ids)
+}
+
+func TestParseIDs_Invalid(t *testing.T) {
+	for _, raw := range []string{"not-a-uuid", uuid.NewString() + ",", uuid.NewString() + ",,x"} {
+		_, err := ParseIDs(raw)
+		assert.Error(t, err, raw)
+	}
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+)
+
+// MetricsMiddleware mide la duración de cada petición por método, ruta y código de respuesta.
+// Se etiqueta con la plantilla de la ruta (c.FullPath) para acotar la cardinalidad; las
+// peticiones que no casan con ninguna ruta se agrupan como "unmatched".
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, strconv.Itoa(c.Writer.Status()), time.Since(start))
+	}
+}
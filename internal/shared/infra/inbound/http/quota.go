@@ -0,0 +1,33 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/quota"
+)
+
+// Cabeceras de la respuesta a una cuota agotada.
+const (
+	HeaderQuotaLimit     = "X-Quota-Limit"
+	HeaderQuotaRemaining = "X-Quota-Remaining"
+	HeaderQuotaReset     = "X-Quota-Reset"
+)
+
+// setQuotaHeaders describe en la respuesta la cuota agotada si 'err' lo es: la cuota en
+// X-Quota-Limit ("<máximo>/<periodo>"), X-Quota-Remaining a 0, su renovación en X-Quota-Reset
+// (Unix) y Retry-After. WriteError la responde como un 429.
+func setQuotaHeaders(c *gin.Context, err error) {
+	var exceeded *quota.ExceededError
+	if !errors.As(err, &exceeded) {
+		return
+	}
+	retryAfter := int(time.Until(exceeded.Reset).Seconds()) + 1
+	c.Header(HeaderQuotaLimit, strconv.FormatInt(exceeded.Limit.Max, 10)+"/"+string(exceeded.Limit.Period))
+	c.Header(HeaderQuotaRemaining, "0")
+	c.Header(HeaderQuotaReset, strconv.FormatInt(exceeded.Reset.Unix(), 10))
+	c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+}
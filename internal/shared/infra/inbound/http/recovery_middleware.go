@@ -0,0 +1,33 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/reporting"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// RecoveryMiddleware recupera los pánicos de los handlers, los envía a Sentry (si está
+// habilitado) y responde 500 en lugar de cortar la conexión. Debe ir después de
+// TracingMiddleware para que el evento lleve el correlation-id de la petición.
+func RecoveryMiddleware(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				ctx := c.Request.Context()
+				reporting.ReportPanic(ctx, "http", r)
+				tracing.Logger(ctx, log).Error("Pánico en handler HTTP",
+					zap.String("method", c.Request.Method),
+					zap.String("route", c.FullPath()),
+					zap.Any("panic", r),
+					zap.Stack("stack"),
+				)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+)
+
+// RegisterAdminRoutes expone los endpoints de operación bajo /admin, tras 'middlewares' (ej.
+// RequireRole).
+func RegisterAdminRoutes(r *gin.Engine, handler *AdminHandler, middlewares ...gin.HandlerFunc) {
+	admin := r.Group("/admin", middlewares...)
+	{
+		admin.POST("/config/reload", handler.ReloadConfig) // Recarga en caliente (equivale a SIGHUP)
+		admin.GET("/stats", handler.Stats)                 // Resumen para un panel de operación
+		admin.GET("/dead-letters", handler.DeadLetters)
+	}
+}
+
+// RegisterDeadLetterChangeRoutes expone bajo /admin los endpoints que reencolan o borran eventos
+// apartados. 'middlewares' deben exigir el rol de administrador (RequireRole): sin él no se montan.
+func RegisterDeadLetterChangeRoutes(r *gin.Engine, handler *AdminHandler, middlewares ...gin.HandlerFunc) {
+	admin := r.Group("/admin", middlewares...)
+	{
+		admin.POST("/dead-letters/requeue", handler.RequeueDeadLetters)
+		admin.POST("/dead-letters/purge", handler.PurgeDeadLetters)
+	}
+}
+
+// RegisterHealthRoutes expone la sonda de vida (/health) y la de disponibilidad (/readyz).
+func RegisterHealthRoutes(r *gin.Engine, handler *HealthHandler) {
+	r.GET("/health", handler.Live)
+	r.GET("/readyz", handler.Ready)
+}
+
+// RegisterEventRoutes expone el catálogo de eventos publicados.
+func RegisterEventRoutes(r *gin.Engine, handler *EventCatalogHandler) {
+	r.GET("/events/catalog", handler.Catalog)
+}
+
+// RegisterMetricsRoutes expone las métricas en formato Prometheus.
+func RegisterMetricsRoutes(r *gin.Engine) {
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
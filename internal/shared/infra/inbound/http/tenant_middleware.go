@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// TenantMiddleware resuelve el inquilino de las peticiones a partir del token
+// (oidc.Principal.Tenant, vacío sin auth.oidc.tenant_claim) en lugar del X-Tenant-ID que envíe el
+// cliente: los repositorios limitan todas las lecturas y escrituras a ese inquilino, así que
+// cambiar la cabecera no debe dar acceso a los datos de otro. Un X-Tenant-ID distinto del del
+// token, o un token con un inquilino mal formado, es un 403. En las rutas públicas, sin token, la
+// cabecera se ignora. Debe ir después de AuthMiddleware.
+func TenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		principal, ok := oidc.FromContext(ctx)
+		if !ok {
+			tc, _ := tracing.FromContext(ctx)
+			tc.TenantID = ""
+			c.Request = c.Request.WithContext(tracing.NewContext(ctx, tc))
+			c.Next()
+			return
+		}
+		if principal.Tenant != "" && !tracing.ValidTenantID(principal.Tenant) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "invalid tenant in token"})
+			return
+		}
+		tc, _ := tracing.FromContext(ctx)
+		if tc.TenantID != "" && tc.TenantID != principal.Tenant {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": tracing.HeaderTenantID + " does not match the token's tenant"})
+			return
+		}
+		tc.TenantID = principal.Tenant
+		c.Request = c.Request.WithContext(tracing.NewContext(ctx, tc))
+		c.Next()
+	}
+}
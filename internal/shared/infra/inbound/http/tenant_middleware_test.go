@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	auditor, _ := newAuditLog()
+	router.Use(TracingMiddleware(), AuthMiddleware(fakeVerifier{}, []string{"/health"}, auditor), TenantMiddleware())
+	tenant := func(c *gin.Context) { c.String(http.StatusOK, tracing.TenantID(c.Request.Context())) }
+	router.GET("/health", tenant)
+	router.GET("/tasks", tenant)
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"tenant from token", "/tasks", "tenant:acme", "", http.StatusOK, "acme"},
+		{"matching header", "/tasks", "tenant:acme", "acme", http.StatusOK, "acme"},
+		{"spoofed header", "/tasks", "tenant:acme", "globex", http.StatusForbidden, ""},
+		{"token without tenant", "/tasks", "good", "globex", http.StatusForbidden, ""},
+		{"invalid tenant in token", "/tasks", "tenant:a b", "", http.StatusForbidden, ""},
+		{"public path ignores header", "/health", "", "globex", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.header != "" {
+				req.Header.Set(tracing.HeaderTenantID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantTenant, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutMiddleware pone un plazo al contexto de cada petición: 'timeout' o, en las rutas de
+// 'routes' (plantilla de gin), el suyo. Con 0 la petición no tiene plazo, y tampoco las que piden
+// un stream de eventos (Accept: text/event-stream). Los servicios y los repositorios reciben ese
+// contexto, así que al vencer se cancelan las consultas en curso y los reintentos pendientes, y
+// el error resultante (context.DeadlineExceeded) se responde con un 504. El middleware no corta
+// la respuesta: un handler que no consulta el contexto termina igualmente.
+func TimeoutMiddleware(timeout time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, ok := routes[c.FullPath()]
+		if !ok {
+			limit = timeout
+		}
+		if limit <= 0 || strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), limit)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	// Arrange: cada ruta espera a que venza su contexto, como una consulta lenta
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TimeoutMiddleware(10*time.Millisecond, map[string]time.Duration{"/export": 0}))
+	slow := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			WriteError(c, c.Request.Context().Err())
+		case <-time.After(100 * time.Millisecond):
+			c.Status(http.StatusOK)
+		}
+	}
+	router.GET("/tasks", slow)
+	router.GET("/export", slow)
+
+	tests := []struct {
+		name       string
+		path       string
+		accept     string
+		wantStatus int
+	}{
+		{"default deadline", "/tasks", "", http.StatusGatewayTimeout},
+		{"route without deadline", "/export", "", http.StatusOK},
+		{"event streams have no deadline", "/tasks", "text/event-stream", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// TracingMiddleware asocia a cada petición un contexto de traza: respeta el correlation-id y el
+// traceparent recibidos y genera los que falten. El correlation-id se devuelve en la respuesta
+// y viaja en el contexto hasta el outbox y los mensajes del bus, junto con el actor (X-Actor) y
+// la causa (X-Causation-ID) que indique el cliente. También el inquilino (X-Tenant-ID), que
+// separa los flujos de eventos de cada uno; uno mal formado es un 400.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tc := tracing.FromHeadersOrNew(c.GetHeader)
+		if tc.TenantID != "" && !tracing.ValidTenantID(tc.TenantID) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + tracing.HeaderTenantID + " header"})
+			return
+		}
+		c.Request = c.Request.WithContext(tracing.NewContext(c.Request.Context(), tc))
+		c.Header(tracing.HeaderCorrelationID, tc.CorrelationID)
+		c.Next()
+	}
+}
@@ -0,0 +1,101 @@
+// Package sanitize limpia y acota los datos que llegan por los adaptadores de entrada (HTTP y
+// gRPC) antes de pasarlos a la aplicación: normaliza el texto, rechaza caracteres de control y
+// longitudes excesivas y limita el tamaño de página de los listados.
+package sanitize
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// Longitudes máximas, en caracteres, de los campos de texto que se guardan.
+const (
+	MaxEmailLength       = 254 // El máximo de una dirección según RFC 5321
+	MaxNameLength        = 100
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 5000
+)
+
+// MaxListLimit es el tamaño de página máximo de los listados: un limit mayor se recorta.
+const MaxListLimit = 500
+
+// MaxBodyBytes es el tamaño máximo del cuerpo de una petición HTTP.
+const MaxBodyBytes = 1 << 20
+
+// ErrInvalidInput agrupa los errores de los campos rechazados (ver FieldError).
+var ErrInvalidInput = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid input")
+
+// FieldError indica el campo rechazado y el motivo.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Reason
+}
+
+func (e *FieldError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+// Line limpia un texto de una sola línea (nombre, título, email): lo normaliza a NFC, quita los
+// espacios de los extremos y lo rechaza si tiene caracteres de control o más de 'maxLen'
+// caracteres.
+func Line(field, value string, maxLen int) (string, error) {
+	return clean(field, value, maxLen, false)
+}
+
+// RequiredLine es Line para un campo obligatorio: un texto que queda vacío tras limpiarlo (solo
+// espacios, por ejemplo) también se rechaza.
+func RequiredLine(field, value string, maxLen int) (string, error) {
+	value, err := Line(field, value, maxLen)
+	if err == nil && value == "" {
+		return "", &FieldError{Field: field, Reason: "is required"}
+	}
+	return value, err
+}
+
+// Text limpia un texto de varias líneas (descripción) igual que Line, pero admite saltos de
+// línea y tabuladores. Los "\r\n" se convierten en "\n".
+func Text(field, value string, maxLen int) (string, error) {
+	return clean(field, strings.ReplaceAll(value, "\r\n", "\n"), maxLen, true)
+}
+
+func clean(field, value string, maxLen int, multiline bool) (string, error) {
+	if !utf8.ValidString(value) {
+		return "", &FieldError{Field: field, Reason: "must be valid UTF-8"}
+	}
+	// La longitud se comprueba antes de normalizar para no procesar textos enormes.
+	if len(value) > maxLen*utf8.UTFMax {
+		return "", &FieldError{Field: field, Reason: fmt.Sprintf("must be at most %d characters", maxLen)}
+	}
+	value = strings.TrimSpace(norm.NFC.String(value))
+	for _, r := range value {
+		if multiline && (r == '\n' || r == '\t') {
+			continue
+		}
+		if unicode.IsControl(r) {
+			return "", &FieldError{Field: field, Reason: "must not contain control characters"}
+		}
+	}
+	if utf8.RuneCountInString(value) > maxLen {
+		return "", &FieldError{Field: field, Reason: fmt.Sprintf("must be at most %d characters", maxLen)}
+	}
+	return value, nil
+}
+
+// Limit devuelve el tamaño de página de un listado: 'def' si no se pidió (o no es positivo) y
+// como mucho MaxListLimit.
+func Limit(requested, def int) int {
+	if requested <= 0 {
+		return def
+	}
+	return min(requested, MaxListLimit)
+}
@@ -0,0 +1,74 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLine_NormalizesAndTrims(t *testing.T) {
+	// Arrange: "José" con la tilde como carácter combinante (NFD)
+	decomposed := "  José "
+
+	// Act
+	value, err := Line("name", decomposed, MaxNameLength)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "José", value)
+}
+
+func TestLine_Rejects(t *testing.T) {
+	cases := map[string]string{
+		"control character": "Ana\x00",
+		"newline":           "Ana\nBelén",
+		"invalid UTF-8":     "Ana\xff",
+		"too long":          strings.Repeat("a", MaxNameLength+1),
+		"far too long":      strings.Repeat("a", 10*MaxNameLength),
+	}
+	for name, value := range cases {
+		_, err := Line("name", value, MaxNameLength)
+
+		assert.ErrorIs(t, err, ErrInvalidInput, name)
+		var fieldErr *FieldError
+		if assert.ErrorAs(t, err, &fieldErr, name) {
+			assert.Equal(t, "name", fieldErr.Field)
+		}
+	}
+}
+
+func TestLine_CountsCharactersNotBytes(t *testing.T) {
+	// Act: 100 caracteres de dos bytes cada uno
+	value, err := Line("name", strings.Repeat("ñ", MaxNameLength), MaxNameLength)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, []rune(value), MaxNameLength)
+}
+
+func TestRequiredLine_RejectsBlank(t *testing.T) {
+	_, err := RequiredLine("title", "   ", MaxTitleLength)
+
+	assert.EqualError(t, err, "title is required")
+}
+
+func TestText_AllowsLineBreaksAndTabs(t *testing.T) {
+	// Act
+	value, err := Text("description", "primera línea\r\n\tsegunda\n", MaxDescriptionLength)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "primera línea\n\tsegunda", value)
+
+	_, err = Text("description", "texto\x1b[31m", MaxDescriptionLength)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestLimit(t *testing.T) {
+	assert.Equal(t, 50, Limit(0, 50))
+	assert.Equal(t, 50, Limit(-3, 50))
+	assert.Equal(t, 20, Limit(20, 50))
+	assert.Equal(t, MaxListLimit, Limit(100000, 50))
+}
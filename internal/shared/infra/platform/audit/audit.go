@@ -0,0 +1,179 @@
+// Package audit emite los eventos de seguridad de la API (autenticaciones fallidas y accesos
+// denegados) para que los recojan la auditoría y las herramientas SIEM. Cada evento se escribe
+// siempre en el log y, si hay bus, se publica además en el topic Topic.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/background"
+	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// Tipos de los eventos de seguridad.
+const (
+	AuthFailed  = "auth.failed"
+	AuthzDenied = "authz.denied"
+)
+
+// Topic es el topic de los eventos de seguridad.
+const Topic = "security"
+
+// Motivos de los eventos.
+const (
+	ReasonMissingToken = "missing_token"
+	ReasonInvalidToken = "invalid_token"
+	ReasonMissingRole  = "missing_role"
+)
+
+// publishTimeout es lo que puede tardar en publicarse un evento antes de darlo por perdido.
+const publishTimeout = 5 * time.Second
+
+var authFailedSchema = sharedEvents.MustCompileSchema(`{
+	"type": "object",
+	"required": ["route", "method", "reason", "client_ip"],
+	"properties": {
+		"route": {"type": "string"},
+		"method": {"type": "string"},
+		"reason": {"type": "string", "enum": ["missing_token", "invalid_token"]},
+		"detail": {"type": "string"},
+		"client_ip": {"type": "string"}
+	},
+	"additionalProperties": false,
+	"examples": [{
+		"route": "/users/:id",
+		"method": "GET",
+		"reason": "invalid_token",
+		"detail": "invalid token: go-jose/go-jose/jwt: validation failed, token is expired (exp)",
+		"client_ip": "203.0.113.7"
+	}]
+}`)
+
+var authzDeniedSchema = sharedEvents.MustCompileSchema(`{
+	"type": "object",
+	"required": ["route", "method", "reason", "required_role", "client_ip"],
+	"properties": {
+		"route": {"type": "string"},
+		"method": {"type": "string"},
+		"reason": {"type": "string", "enum": ["missing_role"]},
+		"required_role": {"type": "string"},
+		"client_ip": {"type": "string"}
+	},
+	"additionalProperties": false,
+	"examples": [{
+		"route": "/admin/config/reload",
+		"method": "POST",
+		"reason": "missing_role",
+		"required_role": "admin",
+		"client_ip": "203.0.113.7"
+	}]
+}`)
+
+// NewEventRegistry devuelve los eventos de seguridad, para enrutarlos a su topic y validarlos
+// como los de los dominios.
+func NewEventRegistry() map[string]sharedEvents.EventMetadata {
+	return map[string]sharedEvents.EventMetadata{
+		AuthFailed: {
+			Type:    reflect.TypeOf(sharedEvents.AuthFailed{}),
+			Topic:   Topic,
+			Version: 1,
+			Schema:  authFailedSchema,
+		},
+		AuthzDenied: {
+			Type:    reflect.TypeOf(sharedEvents.AuthzDenied{}),
+			Topic:   Topic,
+			Version: 1,
+			Schema:  authzDeniedSchema,
+		},
+	}
+}
+
+// Auditor emite los eventos de seguridad. Las publicaciones van a un pool en segundo plano para
+// no retrasar la respuesta: una ráfaga de peticiones rechazadas no debe frenar la API, así que
+// si el pool está lleno el evento solo queda en el log. Un Auditor nil no emite nada.
+type Auditor struct {
+	pool      *background.Pool
+	publisher sharedBus.EventBus
+	clock     sharedDomain.Clock
+	ids       sharedDomain.IDGenerator
+	log       *zap.Logger
+}
+
+// New crea un Auditor que solo escribe en el log hasta que se le da un publicador.
+func New(pool *background.Pool, clock sharedDomain.Clock, ids sharedDomain.IDGenerator, log *zap.Logger) *Auditor {
+	return &Auditor{pool: pool, clock: clock, ids: ids, log: log}
+}
+
+// SetPublisher publica a partir de ahora los eventos en 'publisher'. Se llama antes de arrancar.
+func (a *Auditor) SetPublisher(publisher sharedBus.EventBus) {
+	a.publisher = publisher
+}
+
+// AuthFailed emite auth.failed. El actor del evento es el de la traza de ctx: el X-Actor que
+// declaró el cliente, sin verificar.
+func (a *Auditor) AuthFailed(ctx context.Context, payload sharedEvents.AuthFailed) {
+	a.emit(ctx, AuthFailed, payload.ClientIP, payload,
+		zap.String("route", payload.Route), zap.String("method", payload.Method),
+		zap.String("reason", payload.Reason), zap.String("detail", payload.Detail),
+		zap.String("client_ip", payload.ClientIP))
+}
+
+// AuthzDenied emite authz.denied. El actor del evento es el usuario autenticado.
+func (a *Auditor) AuthzDenied(ctx context.Context, payload sharedEvents.AuthzDenied) {
+	a.emit(ctx, AuthzDenied, payload.ClientIP, payload,
+		zap.String("route", payload.Route), zap.String("method", payload.Method),
+		zap.String("reason", payload.Reason), zap.String("required_role", payload.RequiredRole),
+		zap.String("client_ip", payload.ClientIP))
+}
+
+// emit escribe el evento en el log y, si hay publicador, lo publica particionado por 'key' (la
+// IP del cliente), para que los intentos de un mismo origen se consuman en orden.
+func (a *Auditor) emit(ctx context.Context, eventType, key string, payload interface{}, fields ...zap.Field) {
+	if a == nil {
+		return
+	}
+	log := tracing.Logger(ctx, a.log)
+	log.Warn("🚨 Evento de seguridad", append([]zap.Field{zap.String("security_event", eventType)}, fields...)...)
+	if a.publisher == nil {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Error("❌ No se pudo serializar el evento de seguridad", zap.String("security_event", eventType), zap.Error(err))
+		return
+	}
+	tc, _ := tracing.FromContext(ctx)
+	now := a.clock.Now().UTC()
+	event := sharedEvents.IntegrationEvent{
+		EventID:       a.ids.NewID(),
+		Type:          eventType,
+		Timestamp:     now,
+		OccurredAt:    now,
+		CorrelationID: tc.CorrelationID,
+		CausationID:   tc.CausationID,
+		Actor:         tc.Actor,
+		TenantID:      tc.TenantID,
+		Data:          data,
+	}.WithPartitionKey(key)
+
+	submitted := a.pool.Submit(ctx, func(bg context.Context) {
+		// La traza viaja en las cabeceras del mensaje, pero sin la cancelación de la petición.
+		pubCtx, cancel := context.WithTimeout(tracing.NewContext(bg, tc), publishTimeout)
+		defer cancel()
+		if err := a.publisher.Publish(pubCtx, event); err != nil {
+			log.Error("❌ No se pudo publicar el evento de seguridad", zap.String("security_event", eventType), zap.Error(err))
+		}
+	})
+	if !submitted {
+		log.Warn("⚠️ Cola de eventos de seguridad llena, el evento solo queda en el log", zap.String("security_event", eventType))
+	}
+}
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+type recordingBus struct {
+	events []sharedEvents.IntegrationEvent
+}
+
+func (b *recordingBus) Publish(_ context.Context, event interface{}) error {
+	b.events = append(b.events, event.(sharedEvents.IntegrationEvent))
+	return nil
+}
+
+func TestAuditor_AuthFailed(t *testing.T) {
+	// Arrange: un pool nil publica en la goroutine del llamante
+	now := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	core, logs := observer.New(zap.WarnLevel)
+	bus := &recordingBus{}
+	auditor := New(nil, mocks.NewFakeClock(now), mocks.NewSequentialIDGenerator(), zap.New(core))
+	auditor.SetPublisher(bus)
+	tc := tracing.New()
+	tc.Actor = "mallory"
+	ctx := tracing.NewContext(context.Background(), tc)
+	payload := sharedEvents.AuthFailed{Route: "/users/:id", Method: "GET", Reason: ReasonInvalidToken, Detail: "token is expired", ClientIP: "203.0.113.7"}
+
+	// Act
+	auditor.AuthFailed(ctx, payload)
+
+	// Assert
+	require.Len(t, bus.events, 1)
+	event := bus.events[0]
+	assert.Equal(t, mocks.SequentialID(1), event.EventID)
+	assert.Equal(t, AuthFailed, event.Type)
+	assert.Equal(t, now, event.OccurredAt)
+	assert.Equal(t, tc.CorrelationID, event.CorrelationID)
+	assert.Equal(t, "mallory", event.Actor)
+	assert.Equal(t, "203.0.113.7", event.PartitionKey())
+	var got sharedEvents.AuthFailed
+	require.NoError(t, json.Unmarshal(event.Data, &got))
+	assert.Equal(t, payload, got)
+	assert.NoError(t, sharedEvents.Schemas(NewEventRegistry()).Validate(event.Type, event.Data))
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, AuthFailed, fields["security_event"])
+	assert.Equal(t, "invalid_token", fields["reason"])
+	assert.Equal(t, tc.CorrelationID, fields["correlation_id"])
+}
+
+func TestAuditor_LogOnlyWithoutPublisher(t *testing.T) {
+	// Arrange
+	core, logs := observer.New(zap.WarnLevel)
+	auditor := New(nil, mocks.NewFakeClock(time.Now()), mocks.NewSequentialIDGenerator(), zap.New(core))
+
+	// Act
+	auditor.AuthzDenied(context.Background(), sharedEvents.AuthzDenied{
+		Route: "/admin/config/reload", Method: "POST", Reason: ReasonMissingRole, RequiredRole: "admin", ClientIP: "203.0.113.7",
+	})
+
+	// Assert
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, AuthzDenied, logs.All()[0].ContextMap()["security_event"])
+	assert.Equal(t, "admin", logs.All()[0].ContextMap()["required_role"])
+}
+
+func TestAuditor_Nil(t *testing.T) {
+	var auditor *Auditor
+
+	assert.NotPanics(t, func() { auditor.AuthFailed(context.Background(), sharedEvents.AuthFailed{}) })
+}
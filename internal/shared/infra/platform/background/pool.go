@@ -0,0 +1,178 @@
+package background
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+)
+
+// Valores por defecto de Options.
+const (
+	DefaultWorkers   = 8
+	DefaultQueueSize = 1000
+)
+
+// Policy decide qué hace Submit cuando la cola está llena.
+type Policy string
+
+const (
+	// PolicyDrop descarta la tarea: para trabajo prescindible, como rellenar la caché.
+	PolicyDrop Policy = "drop"
+	// PolicyWait espera a que haya hueco, como mucho hasta que se cancele el contexto de Submit.
+	PolicyWait Policy = "wait"
+)
+
+// Options dimensiona un Pool. Los valores cero usan los valores por defecto.
+type Options struct {
+	Workers   int    // Goroutines que ejecutan las tareas
+	QueueSize int    // Tareas encoladas a la espera de una goroutine libre
+	Policy    Policy // "" es PolicyDrop
+}
+
+// Task es una tarea en segundo plano. 'ctx' no depende de la petición que la lanzó: solo se
+// cancela si Close agota su plazo con la tarea en curso.
+type Task func(ctx context.Context)
+
+// Pool ejecuta tareas de "dispara y olvida" con un número fijo de goroutines y una cola acotada,
+// para que un pico de peticiones no lance una goroutine por petición.
+type Pool struct {
+	name   string
+	policy Policy
+	log    *zap.Logger
+
+	tasks   chan Task
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	// mu protege el cierre de 'tasks': Submit envía con el lock de lectura y Close cierra con el
+	// de escritura. 'closing' despierta a los Submit que esperan hueco.
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{}
+}
+
+// NewPool arranca las goroutines del pool 'name' (el nombre de sus métricas).
+func NewPool(name string, opts Options, log *zap.Logger) *Pool {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.Policy == "" {
+		opts.Policy = PolicyDrop
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		name:    name,
+		policy:  opts.Policy,
+		log:     log,
+		tasks:   make(chan Task, opts.QueueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		closing: make(chan struct{}),
+	}
+	p.workers.Add(opts.Workers)
+	for range opts.Workers {
+		go p.work()
+	}
+	return p
+}
+
+// Submit encola 'task' y devuelve false si la descarta: la cola está llena con PolicyDrop, se
+// canceló 'ctx' esperando hueco con PolicyWait o el pool está cerrado. Un Pool nil ejecuta la
+// tarea en el momento, en la goroutine del llamante, que es lo que quieren los tests.
+func (p *Pool) Submit(ctx context.Context, task Task) bool {
+	if p == nil {
+		task(context.Background())
+		return true
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return p.drop()
+	}
+
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+	}
+	if p.policy != PolicyWait {
+		return p.drop()
+	}
+	select {
+	case p.tasks <- task:
+		return true
+	case <-ctx.Done():
+	case <-p.closing:
+	}
+	return p.drop()
+}
+
+// Pending devuelve las tareas encoladas que aún no ha cogido ninguna goroutine.
+func (p *Pool) Pending() int {
+	return len(p.tasks)
+}
+
+// RegisterMetrics expone la profundidad de la cola del pool.
+func (p *Pool) RegisterMetrics() error {
+	return metrics.RegisterBackgroundQueue(p.name, p.Pending)
+}
+
+// Close deja de aceptar tareas y espera a que se ejecuten las encoladas. Si 'ctx' vence antes,
+// cancela el contexto de las tareas en curso y devuelve su error sin esperar más.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		close(p.closing)
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return fmt.Errorf("background pool %s: %d tasks not run: %w", p.name, p.Pending(), ctx.Err())
+	}
+}
+
+func (p *Pool) work() {
+	defer p.workers.Done()
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+// run ejecuta una tarea sin dejar que un pánico tumbe la goroutine del pool.
+func (p *Pool) run(task Task) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.log.Error("💥 Pánico en una tarea en segundo plano", zap.String("pool", p.name), zap.Any("panic", r))
+			metrics.ObserveBackgroundTask(p.name, metrics.TaskPanicked)
+		}
+	}()
+	task(p.ctx)
+	metrics.ObserveBackgroundTask(p.name, metrics.TaskDone)
+}
+
+func (p *Pool) drop() bool {
+	metrics.ObserveBackgroundTask(p.name, metrics.TaskDropped)
+	return false
+}
@@ -0,0 +1,111 @@
+package background
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPool_RunsQueuedTasksBeforeClosing(t *testing.T) {
+	// Arrange
+	pool := NewPool("test", Options{Workers: 2, QueueSize: 100}, zap.NewNop())
+	var ran atomic.Int64
+
+	// Act
+	for range 50 {
+		require.True(t, pool.Submit(context.Background(), func(context.Context) { ran.Add(1) }))
+	}
+	err := pool.Close(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), ran.Load())
+	assert.False(t, pool.Submit(context.Background(), func(context.Context) {}), "Un pool cerrado no acepta tareas")
+}
+
+func TestPool_DropPolicy(t *testing.T) {
+	// Arrange: la única goroutine queda ocupada y la cola, llena.
+	pool := NewPool("test", Options{Workers: 1, QueueSize: 1, Policy: PolicyDrop}, zap.NewNop())
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(context.Background(), func(context.Context) { close(started); <-release })
+	<-started
+	require.True(t, pool.Submit(context.Background(), func(context.Context) {}))
+
+	// Act
+	accepted := pool.Submit(context.Background(), func(context.Context) {})
+
+	// Assert
+	assert.False(t, accepted)
+	assert.Equal(t, 1, pool.Pending())
+	close(release)
+	require.NoError(t, pool.Close(context.Background()))
+}
+
+func TestPool_WaitPolicy(t *testing.T) {
+	// Arrange
+	pool := NewPool("test", Options{Workers: 1, QueueSize: 1, Policy: PolicyWait}, zap.NewNop())
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(context.Background(), func(context.Context) { close(started); <-release })
+	<-started
+	require.True(t, pool.Submit(context.Background(), func(context.Context) {}))
+
+	// Act + Assert: espera hueco mientras dure su contexto...
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.False(t, pool.Submit(ctx, func(context.Context) {}))
+
+	// ...y entra en cuanto lo hay.
+	accepted := make(chan bool)
+	go func() { accepted <- pool.Submit(context.Background(), func(context.Context) {}) }()
+	close(release)
+	assert.True(t, <-accepted)
+	require.NoError(t, pool.Close(context.Background()))
+}
+
+func TestPool_CloseTimeoutCancelsRunningTasks(t *testing.T) {
+	// Arrange
+	pool := NewPool("test", Options{Workers: 1}, zap.NewNop())
+	cancelled := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(context.Background(), func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+	})
+	<-started
+
+	// Act
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := pool.Close(ctx)
+
+	// Assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	<-cancelled
+}
+
+func TestPool_RecoversFromPanics(t *testing.T) {
+	pool := NewPool("test", Options{Workers: 1}, zap.NewNop())
+	var ran atomic.Bool
+
+	pool.Submit(context.Background(), func(context.Context) { panic("boom") })
+	pool.Submit(context.Background(), func(context.Context) { ran.Store(true) })
+	require.NoError(t, pool.Close(context.Background()))
+
+	assert.True(t, ran.Load(), "El pánico no debe tumbar la goroutine del pool")
+}
+
+func TestPool_NilRunsInline(t *testing.T) {
+	var pool *Pool
+	ran := false
+
+	assert.True(t, pool.Submit(context.Background(), func(context.Context) { ran = true }))
+	assert.True(t, ran)
+}
@@ -6,7 +6,37 @@ type Keyer interface {
 	PartitionKey() string
 }
 
+// Tombstoner lo implementan los eventos que pueden borrar su agregado. En los topics compactados
+// un evento cuyo Tombstone es true va seguido de un tombstone (mensaje de valor nulo con su
+// PartitionKey), para que la compactación elimine la clave.
+type Tombstoner interface {
+	Tombstone() bool
+}
+
+// Typed lo implementan los eventos que declaran su tipo (ej. "user.created"); los publicadores
+// lo usan para elegir el topic de cada evento.
+type Typed interface {
+	EventType() string
+}
+
 // La semántica de topic/nombre y formato del payload la decides en los adapters.
 type EventBus interface {
 	Publish(ctx context.Context, event interface{}) error
 }
+
+// AsyncPublisher lo implementan los buses que pueden encolar un envío y avisar cuando el broker
+// lo confirma, para no esperar a un mensaje antes de enviar el siguiente.
+type AsyncPublisher interface {
+	// PublishAsync encola 'event' y llama una vez a 'done' con el resultado de la entrega.
+	PublishAsync(ctx context.Context, event interface{}, done func(error))
+}
+
+// PublishAsync publica 'event' con PublishAsync si 'bus' lo admite y, si no, con Publish; en
+// los dos casos 'done' recibe el resultado de la entrega.
+func PublishAsync(ctx context.Context, bus EventBus, event interface{}, done func(error)) {
+	if async, ok := bus.(AsyncPublisher); ok {
+		async.PublishAsync(ctx, event, done)
+		return
+	}
+	done(bus.Publish(ctx, event))
+}
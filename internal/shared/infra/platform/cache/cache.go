@@ -2,8 +2,12 @@ package cache
 
 import (
 	"context"
+	"time"
 )
 
+// DefaultTTL indica a Set que use el TTL por defecto de la caché (configurable con cache.ttl).
+const DefaultTTL = 0
+
 // Cache define la interfaz para una caché de clave-valor genérica.
 type Cache interface {
 	// Get intenta poblar 'dest' (que debe ser un puntero) con el valor asociado a la 'key'.
@@ -12,8 +16,25 @@ type Cache interface {
 	Get(ctx context.Context, key string, dest interface{}) (bool, error)
 
 	// Set serializa y guarda el valor con un TTL (Time To Live) en segundos.
+	// Con ttlSecs <= 0 se usa el TTL por defecto de la caché.
 	Set(ctx context.Context, key string, val interface{}, ttlSecs int) error
 
 	// Delete elimina la 'key' de la caché.
 	Delete(ctx context.Context, key string) error
+
+	// GetMulti lee varias claves de una vez (una sola ida y vuelta en Redis). Por cada 'hit'
+	// rellena el puntero que devuelve dest(key) y devuelve las claves encontradas; las que faltan
+	// son 'miss'.
+	GetMulti(ctx context.Context, keys []string, dest func(key string) interface{}) ([]string, error)
+
+	// SetMulti guarda varios valores con el mismo TTL, con la misma semántica que Set.
+	SetMulti(ctx context.Context, items map[string]interface{}, ttlSecs int) error
+
+	// DeleteMulti elimina varias claves de la caché.
+	DeleteMulti(ctx context.Context, keys []string) error
+}
+
+// DefaultTTLSetter lo implementan las cachés cuyo TTL por defecto se puede cambiar en caliente.
+type DefaultTTLSetter interface {
+	SetDefaultTTL(ttl time.Duration)
 }
@@ -0,0 +1,271 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// maxFailoverKeys es el máximo de claves escritas durante una conmutación que FailoverCache
+// recuerda para invalidarlas en la caché principal al volver a ella.
+const maxFailoverKeys = 10000
+
+// probeTimeout acota cada sondeo de la caché principal.
+const probeTimeout = 2 * time.Second
+
+// FailoverOptions configura cuándo conmuta FailoverCache y cada cuánto sondea la principal.
+type FailoverOptions struct {
+	// Threshold es el número de errores seguidos de la principal que provoca la conmutación.
+	Threshold int
+	// ProbeInterval es cada cuánto se comprueba, mientras se usa la de respaldo, si la principal
+	// ha vuelto.
+	ProbeInterval time.Duration
+}
+
+// FailoverCache usa la caché principal (Redis) mientras responde y, tras Threshold errores
+// seguidos, pasa a la de respaldo (en memoria) para que las peticiones no paguen el error de
+// cada operación. Start sondea entonces la principal y vuelve a ella cuando responde. Cada
+// cambio se registra en el log.
+//
+// Las claves escritas o borradas durante la conmutación, y las que la principal no pudo escribir
+// en los errores que la provocaron, se borran de las dos cachés al volver: de la principal,
+// porque pueden tener un valor anterior a los cambios, y de la de respaldo, para que empiece
+// vacía la próxima vez.
+type FailoverCache struct {
+	primary  Cache
+	fallback Cache
+	probe    func(ctx context.Context) error
+	opts     FailoverOptions
+	clock    sharedDomain.Clock
+	log      *zap.Logger
+
+	mu         sync.Mutex
+	failedOver bool
+	failures   int
+	// touched son las claves escritas desde el primer error seguido de la principal; overflow
+	// indica que eran más de maxFailoverKeys y que la principal puede servir valores antiguos
+	// hasta que caduquen.
+	touched  map[string]struct{}
+	overflow bool
+}
+
+// NewFailoverCache crea la caché. 'probe' comprueba si la principal responde (p.ej. un PING).
+func NewFailoverCache(primary, fallback Cache, probe func(ctx context.Context) error, opts FailoverOptions, clock sharedDomain.Clock, log *zap.Logger) *FailoverCache {
+	return &FailoverCache{
+		primary:  primary,
+		fallback: fallback,
+		probe:    probe,
+		opts:     opts,
+		clock:    clock,
+		log:      log,
+	}
+}
+
+// FailedOver indica si se está usando la caché de respaldo.
+func (c *FailoverCache) FailedOver() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failedOver
+}
+
+// Start sondea la caché principal cada ProbeInterval mientras se usa la de respaldo, hasta que
+// se cancela 'ctx'.
+func (c *FailoverCache) Start(ctx context.Context) {
+	ticker := c.clock.NewTicker(c.opts.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			c.Probe(ctx)
+		}
+	}
+}
+
+// Probe comprueba, si se está usando la caché de respaldo, si la principal responde y, en ese
+// caso, vuelve a ella tras invalidar en las dos las claves escritas entretanto.
+func (c *FailoverCache) Probe(ctx context.Context) {
+	if !c.FailedOver() {
+		return
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	if err := c.probe(probeCtx); err != nil {
+		c.log.Debug("Caché principal aún no disponible", zap.Error(err))
+		return
+	}
+
+	// Las claves se invalidan sin bloquear la caché; las que se escriban mientras tanto quedan
+	// para el siguiente sondeo.
+	keys := c.touchedKeys()
+	if len(keys) > 0 {
+		if err := c.primary.DeleteMulti(probeCtx, keys); err != nil {
+			c.log.Warn("⚠️ No se pudieron invalidar las claves en la caché principal, se sigue en la de respaldo", zap.Error(err))
+			return
+		}
+		if err := c.fallback.DeleteMulti(ctx, keys); err != nil {
+			c.log.Warn("⚠️ No se pudieron vaciar las claves de la caché de respaldo", zap.Error(err))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.touched, key)
+	}
+	if len(c.touched) > 0 {
+		return
+	}
+	if c.overflow {
+		c.log.Warn("⚠️ Demasiadas claves escritas durante la conmutación: la caché principal puede servir valores antiguos hasta que caduquen",
+			zap.Int("max_keys", maxFailoverKeys))
+	}
+	c.failedOver, c.failures, c.touched, c.overflow = false, 0, nil, false
+	c.log.Info("✅ Caché principal recuperada, se deja la de respaldo", zap.Int("invalidated_keys", len(keys)))
+}
+
+func (c *FailoverCache) touchedKeys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.touched))
+	for key := range c.touched {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// current devuelve la caché que atiende la operación y si es la principal.
+func (c *FailoverCache) current() (Cache, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failedOver {
+		return c.fallback, false
+	}
+	return c.primary, true
+}
+
+// observe cuenta el resultado de una operación de la principal y conmuta al llegar a Threshold
+// errores seguidos. Los errores por cancelación de la propia petición no cuentan.
+func (c *FailoverCache) observe(ctx context.Context, err error) {
+	if err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled)) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failedOver {
+		return
+	}
+	if err == nil {
+		c.failures, c.touched, c.overflow = 0, nil, false
+		return
+	}
+	if c.failures++; c.failures < c.opts.Threshold {
+		return
+	}
+	c.failedOver = true
+	c.log.Warn("⚠️ Caché principal fallando, se pasa a la de respaldo",
+		zap.Int("consecutive_errors", c.failures), zap.Duration("probe_interval", c.opts.ProbeInterval), zap.Error(err))
+}
+
+// touch recuerda las claves escritas durante la conmutación o en una escritura fallida de la
+// principal.
+func (c *FailoverCache) touch(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.failedOver && c.failures == 0 {
+		return
+	}
+	if c.touched == nil {
+		c.touched = make(map[string]struct{})
+	}
+	for _, key := range keys {
+		if len(c.touched) >= maxFailoverKeys {
+			c.overflow = true
+			return
+		}
+		c.touched[key] = struct{}{}
+	}
+}
+
+// written registra el resultado de una escritura de 'keys': en la de respaldo, o fallida en la
+// principal, las claves quedan pendientes de invalidar al volver a la principal.
+func (c *FailoverCache) written(ctx context.Context, primary bool, err error, keys ...string) {
+	if primary {
+		c.observe(ctx, err)
+		if err == nil {
+			return
+		}
+	}
+	c.touch(keys...)
+}
+
+func (c *FailoverCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	cache, primary := c.current()
+	hit, err := cache.Get(ctx, key, dest)
+	if primary {
+		c.observe(ctx, err)
+	}
+	return hit, err
+}
+
+func (c *FailoverCache) Set(ctx context.Context, key string, val interface{}, ttlSecs int) error {
+	cache, primary := c.current()
+	err := cache.Set(ctx, key, val, ttlSecs)
+	c.written(ctx, primary, err, key)
+	return err
+}
+
+func (c *FailoverCache) Delete(ctx context.Context, key string) error {
+	cache, primary := c.current()
+	err := cache.Delete(ctx, key)
+	c.written(ctx, primary, err, key)
+	return err
+}
+
+func (c *FailoverCache) GetMulti(ctx context.Context, keys []string, dest func(key string) interface{}) ([]string, error) {
+	cache, primary := c.current()
+	found, err := cache.GetMulti(ctx, keys, dest)
+	if primary {
+		c.observe(ctx, err)
+	}
+	return found, err
+}
+
+func (c *FailoverCache) SetMulti(ctx context.Context, items map[string]interface{}, ttlSecs int) error {
+	cache, primary := c.current()
+	err := cache.SetMulti(ctx, items, ttlSecs)
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	c.written(ctx, primary, err, keys...)
+	return err
+}
+
+func (c *FailoverCache) DeleteMulti(ctx context.Context, keys []string) error {
+	cache, primary := c.current()
+	err := cache.DeleteMulti(ctx, keys)
+	c.written(ctx, primary, err, keys...)
+	return err
+}
+
+// SetDefaultTTL reenvía el cambio de TTL a las dos cachés, si lo admiten.
+func (c *FailoverCache) SetDefaultTTL(ttl time.Duration) {
+	for _, cache := range []Cache{c.primary, c.fallback} {
+		if setter, ok := cache.(DefaultTTLSetter); ok {
+			setter.SetDefaultTTL(ttl)
+		}
+	}
+}
+
+// Verificación estática de las interfaces.
+var (
+	_ Cache            = (*FailoverCache)(nil)
+	_ DefaultTTLSetter = (*FailoverCache)(nil)
+)
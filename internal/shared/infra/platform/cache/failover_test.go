@@ -0,0 +1,135 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedCache "github.com/davicafu/hexagolab/internal/shared/infra/platform/cache"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+var errRedisDown = errors.New("dial tcp: connection refused")
+
+// flakyCache simula una caché remota que se puede tirar y levantar.
+type flakyCache struct {
+	*mocks.DummyCache
+	down bool
+}
+
+func (c *flakyCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	if c.down {
+		return false, errRedisDown
+	}
+	return c.DummyCache.Get(ctx, key, dest)
+}
+
+func (c *flakyCache) Set(ctx context.Context, key string, val interface{}, ttlSecs int) error {
+	if c.down {
+		return errRedisDown
+	}
+	return c.DummyCache.Set(ctx, key, val, ttlSecs)
+}
+
+func (c *flakyCache) DeleteMulti(ctx context.Context, keys []string) error {
+	if c.down {
+		return errRedisDown
+	}
+	return c.DummyCache.DeleteMulti(ctx, keys)
+}
+
+func (c *flakyCache) ping(context.Context) error {
+	if c.down {
+		return errRedisDown
+	}
+	return nil
+}
+
+func newFailoverCache(primary *flakyCache, fallback sharedCache.Cache) *sharedCache.FailoverCache {
+	return sharedCache.NewFailoverCache(primary, fallback, primary.ping,
+		sharedCache.FailoverOptions{Threshold: 3, ProbeInterval: time.Second},
+		mocks.NewFakeClock(time.Now()), zap.NewNop())
+}
+
+func TestFailoverCache_FailsOverAfterConsecutiveErrors(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	primary := &flakyCache{DummyCache: mocks.NewDummyCache()}
+	fallback := mocks.NewDummyCache()
+	cache := newFailoverCache(primary, fallback)
+	primary.down = true
+
+	// Act: dos errores no bastan; el tercero conmuta y la siguiente escritura va a memoria.
+	var got item
+	_, err1 := cache.Get(ctx, "item:1", &got)
+	_, err2 := cache.Get(ctx, "item:1", &got)
+	failedOverEarly := cache.FailedOver()
+	_, err3 := cache.Get(ctx, "item:1", &got)
+	setErr := cache.Set(ctx, "item:1", item{Name: "uno"}, sharedCache.DefaultTTL)
+	hit, getErr := cache.Get(ctx, "item:1", &got)
+
+	// Assert
+	assert.ErrorIs(t, err1, errRedisDown)
+	assert.ErrorIs(t, err2, errRedisDown)
+	assert.ErrorIs(t, err3, errRedisDown)
+	assert.False(t, failedOverEarly)
+	assert.True(t, cache.FailedOver())
+	require.NoError(t, setErr)
+	require.NoError(t, getErr)
+	assert.True(t, hit)
+	assert.Equal(t, item{Name: "uno"}, got)
+}
+
+func TestFailoverCache_SuccessResetsTheErrorCount(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	primary := &flakyCache{DummyCache: mocks.NewDummyCache()}
+	cache := newFailoverCache(primary, mocks.NewDummyCache())
+	var got item
+
+	// Act
+	for i := 0; i < 4; i++ {
+		primary.down = i%2 == 0
+		_, _ = cache.Get(ctx, "item:1", &got)
+	}
+
+	// Assert
+	assert.False(t, cache.FailedOver())
+}
+
+func TestFailoverCache_RecoversAndInvalidatesKeysWrittenMeanwhile(t *testing.T) {
+	// Arrange: Redis guarda un valor que cambia mientras está caído.
+	ctx := context.Background()
+	primary := &flakyCache{DummyCache: mocks.NewDummyCache()}
+	fallback := mocks.NewDummyCache()
+	cache := newFailoverCache(primary, fallback)
+	require.NoError(t, cache.Set(ctx, "item:1", item{Name: "antiguo"}, sharedCache.DefaultTTL))
+	primary.down = true
+	for i := 0; i < 3; i++ {
+		_ = cache.Set(ctx, "item:2", item{Name: "dos"}, sharedCache.DefaultTTL)
+	}
+	require.True(t, cache.FailedOver())
+	require.NoError(t, cache.Delete(ctx, "item:1"))
+
+	// Act: mientras Redis no responde el sondeo no hace nada; después vuelve a él.
+	cache.Probe(ctx)
+	stillFailedOver := cache.FailedOver()
+	primary.down = false
+	cache.Probe(ctx)
+
+	// Assert
+	assert.True(t, stillFailedOver)
+	assert.False(t, cache.FailedOver())
+	var got item
+	hit, err := cache.Get(ctx, "item:1", &got)
+	require.NoError(t, err)
+	assert.False(t, hit, "el valor antiguo se borró de Redis al volver")
+	hit, err = fallback.Get(ctx, "item:2", &got)
+	require.NoError(t, err)
+	assert.False(t, hit, "la caché en memoria empieza vacía la próxima vez")
+}
@@ -5,19 +5,25 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/background"
 )
 
-// AsyncCacheSet actualiza caché en background sin bloquear
-func AsyncCacheSet(ctx context.Context, cache Cache, key string, value interface{}, ttl int, log *zap.Logger) {
+// asyncTimeout limita cada escritura en background: si la caché va lenta, se abandona.
+const asyncTimeout = 200 * time.Millisecond
+
+// AsyncCacheSet actualiza caché en background sin bloquear, con una tarea de 'pool'. Si el pool
+// está lleno la actualización se descarta: la siguiente lectura la volverá a intentar.
+func AsyncCacheSet(ctx context.Context, pool *background.Pool, cache Cache, key string, value interface{}, ttl int, log *zap.Logger) {
 	if cache == nil {
 		return
 	}
 
-	go func() {
-		// Usamos context.Background() deliberadamente. Esta es una operación de "dispara y olvida".
-		// Queremos que la actualización de la caché tenga éxito incluso si el contexto de la
-		// petición original ya ha sido cancelado.
-		cacheCtx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	// La tarea no usa el contexto de la petición, deliberadamente. Esta es una operación de
+	// "dispara y olvida": queremos que la actualización de la caché tenga éxito incluso si el
+	// contexto de la petición original ya ha sido cancelado.
+	pool.Submit(ctx, func(taskCtx context.Context) {
+		cacheCtx, cancel := context.WithTimeout(taskCtx, asyncTimeout)
 		defer cancel()
 
 		if err := cache.Set(cacheCtx, key, value, ttl); err != nil {
@@ -25,20 +31,77 @@ func AsyncCacheSet(ctx context.Context, cache Cache, key string, value interface
 				zap.String("key", key),
 				zap.Error(err))
 		}
-	}()
+	})
 }
 
-// AsyncCacheDelete elimina de caché en background
-func AsyncCacheDelete(ctx context.Context, cache Cache, key string, log *zap.Logger) {
+// AsyncCacheDelete elimina de caché en background (ver AsyncCacheSet)
+func AsyncCacheDelete(ctx context.Context, pool *background.Pool, cache Cache, key string, log *zap.Logger) {
 	if cache == nil {
 		return
 	}
 
-	go func() {
-		if err := cache.Delete(ctx, key); err != nil {
+	pool.Submit(ctx, func(taskCtx context.Context) {
+		cacheCtx, cancel := context.WithTimeout(taskCtx, asyncTimeout)
+		defer cancel()
+
+		if err := cache.Delete(cacheCtx, key); err != nil {
 			log.Warn("Cache deletion failed",
 				zap.String("key", key),
 				zap.Error(err))
 		}
-	}()
+	})
+}
+
+// AsyncCacheSetMulti actualiza varias claves en background con un solo SetMulti (ver AsyncCacheSet).
+func AsyncCacheSetMulti(ctx context.Context, pool *background.Pool, cache Cache, items map[string]interface{}, ttl int, log *zap.Logger) {
+	if cache == nil || len(items) == 0 {
+		return
+	}
+
+	pool.Submit(ctx, func(taskCtx context.Context) {
+		cacheCtx, cancel := context.WithTimeout(taskCtx, asyncTimeout)
+		defer cancel()
+
+		if err := cache.SetMulti(cacheCtx, items, ttl); err != nil {
+			log.Warn("Cache update failed",
+				zap.Int("keys", len(items)),
+				zap.Error(err))
+		}
+	})
+}
+
+// AsyncCacheDeleteMulti elimina varias claves de caché en background (ver AsyncCacheSet)
+func AsyncCacheDeleteMulti(ctx context.Context, pool *background.Pool, cache Cache, keys []string, log *zap.Logger) {
+	if cache == nil || len(keys) == 0 {
+		return
+	}
+
+	pool.Submit(ctx, func(taskCtx context.Context) {
+		cacheCtx, cancel := context.WithTimeout(taskCtx, asyncTimeout)
+		defer cancel()
+
+		if err := cache.DeleteMulti(cacheCtx, keys); err != nil {
+			log.Warn("Cache deletion failed",
+				zap.Strings("keys", keys),
+				zap.Error(err))
+		}
+	})
+}
+
+// GetMany lee 'keys' con un solo GetMulti y devuelve los valores encontrados, por clave.
+func GetMany[T any](ctx context.Context, cache Cache, keys []string) (map[string]T, error) {
+	found := make(map[string]*T, len(keys))
+	if _, err := cache.GetMulti(ctx, keys, func(key string) interface{} {
+		value := new(T)
+		found[key] = value
+		return value
+	}); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]T, len(found))
+	for key, value := range found {
+		values[key] = *value
+	}
+	return values, nil
 }
@@ -0,0 +1,56 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedCache "github.com/davicafu/hexagolab/internal/shared/infra/platform/cache"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+type item struct {
+	Name string `json:"name"`
+}
+
+func TestGetMany(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	cache := sharedCache.NewInstrumentedCache(mocks.NewDummyCache(), "test")
+	require.NoError(t, cache.SetMulti(ctx, map[string]interface{}{
+		"item:1": item{Name: "uno"},
+		"item:2": item{Name: "dos"},
+		"item:3": item{Name: "tres"},
+	}, sharedCache.DefaultTTL))
+	require.NoError(t, cache.DeleteMulti(ctx, []string{"item:3"}))
+
+	// Act
+	found, err := sharedCache.GetMany[item](ctx, cache, []string{"item:1", "item:2", "item:3", "item:4"})
+
+	// Assert: solo vuelven los hits.
+	require.NoError(t, err)
+	assert.Equal(t, map[string]item{"item:1": {Name: "uno"}, "item:2": {Name: "dos"}}, found)
+}
+
+func TestGetMany_NoKeys(t *testing.T) {
+	found, err := sharedCache.GetMany[item](context.Background(), mocks.NewDummyCache(), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestTenantKey(t *testing.T) {
+	// Arrange
+	acme := tracing.NewContext(context.Background(), tracing.Context{TenantID: "acme"})
+
+	// Act
+	defaultKey := sharedCache.TenantKey(context.Background(), "task:id:1")
+	acmeKey := sharedCache.TenantKey(acme, "task:id:1")
+
+	// Assert
+	assert.Equal(t, "task:id:1", defaultKey)
+	assert.Equal(t, "tenant:acme:task:id:1", acmeKey)
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+)
+
+// InstrumentedCache decora una Cache contando hits, misses y errores de Get en /metrics.
+type InstrumentedCache struct {
+	next Cache
+	name string
+}
+
+// NewInstrumentedCache envuelve 'next'; 'name' (redis, memory) etiqueta sus métricas.
+func NewInstrumentedCache(next Cache, name string) *InstrumentedCache {
+	return &InstrumentedCache{next: next, name: name}
+}
+
+func (c *InstrumentedCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	hit, err := c.next.Get(ctx, key, dest)
+	metrics.ObserveCacheLookup(c.name, hit, err)
+	return hit, err
+}
+
+func (c *InstrumentedCache) Set(ctx context.Context, key string, val interface{}, ttlSecs int) error {
+	return c.next.Set(ctx, key, val, ttlSecs)
+}
+
+func (c *InstrumentedCache) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+// GetMulti cuenta cada clave como una lectura: hit si se encontró, miss si no, o error.
+func (c *InstrumentedCache) GetMulti(ctx context.Context, keys []string, dest func(key string) interface{}) ([]string, error) {
+	found, err := c.next.GetMulti(ctx, keys, dest)
+	hits := make(map[string]bool, len(found))
+	for _, key := range found {
+		hits[key] = true
+	}
+	for _, key := range keys {
+		metrics.ObserveCacheLookup(c.name, hits[key], err)
+	}
+	return found, err
+}
+
+func (c *InstrumentedCache) SetMulti(ctx context.Context, items map[string]interface{}, ttlSecs int) error {
+	return c.next.SetMulti(ctx, items, ttlSecs)
+}
+
+func (c *InstrumentedCache) DeleteMulti(ctx context.Context, keys []string) error {
+	return c.next.DeleteMulti(ctx, keys)
+}
+
+// SetDefaultTTL reenvía el cambio de TTL si la caché decorada lo admite.
+func (c *InstrumentedCache) SetDefaultTTL(ttl time.Duration) {
+	if setter, ok := c.next.(DefaultTTLSetter); ok {
+		setter.SetDefaultTTL(ttl)
+	}
+}
+
+// Verificación estática de las interfaces.
+var (
+	_ Cache            = (*InstrumentedCache)(nil)
+	_ DefaultTTLSetter = (*InstrumentedCache)(nil)
+)
@@ -6,8 +6,7 @@ import (
 	"sync"
 	"time"
 
-	// Importamos la interfaz de caché compartida para asegurar la compatibilidad.
-	sharedCache "github.com/davicafu/hexagolab/shared/platform/cache"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
 )
 
 // cacheItem guarda el valor y el tiempo de expiración.
@@ -21,19 +20,25 @@ type InMemoryCache struct {
 	store      map[string]cacheItem
 	mu         sync.RWMutex // RWMutex permite múltiples lectores o un solo escritor.
 	defaultTTL time.Duration
-	stopChan   chan struct{} // Canal para detener la goroutine de limpieza.
+	clock      sharedDomain.Clock // Decide cuándo expiran las claves; un reloj falso permite probar el TTL.
+	stopChan   chan struct{}      // Canal para detener la goroutine de limpieza.
 }
 
-// Verificación estática: asegura en tiempo de compilación que InMemoryCache implementa la interfaz compartida.
-var _ sharedCache.Cache = (*InMemoryCache)(nil)
+// Verificación estática: asegura en tiempo de compilación que InMemoryCache implementa Cache.
+var (
+	_ Cache            = (*InMemoryCache)(nil)
+	_ DefaultTTLSetter = (*InMemoryCache)(nil)
+)
 
 // NewInMemoryCache crea una nueva instancia de la caché en memoria.
 // - defaultTTL: El tiempo de vida por defecto para las claves si no se especifica otro.
 // - cleanupInterval: Cada cuánto tiempo se revisarán y eliminarán las claves expiradas.
-func NewInMemoryCache(defaultTTL, cleanupInterval time.Duration) *InMemoryCache {
+// - clock: El reloj con el que se calculan y comprueban las expiraciones.
+func NewInMemoryCache(defaultTTL, cleanupInterval time.Duration, clock sharedDomain.Clock) *InMemoryCache {
 	c := &InMemoryCache{
 		store:      make(map[string]cacheItem),
 		defaultTTL: defaultTTL,
+		clock:      clock,
 		stopChan:   make(chan struct{}),
 	}
 
@@ -54,7 +59,7 @@ func (c *InMemoryCache) Get(ctx context.Context, key string, dest interface{}) (
 	}
 
 	// Comprueba si el item ha expirado.
-	if time.Now().UTC().After(item.expiresAt) {
+	if c.clock.Now().UTC().After(item.expiresAt) {
 		return false, nil // Expirado, se trata como un cache miss.
 	}
 
@@ -83,12 +88,20 @@ func (c *InMemoryCache) Set(ctx context.Context, key string, val interface{}, tt
 
 	c.store[key] = cacheItem{
 		value:     data,
-		expiresAt: time.Now().UTC().Add(ttl),
+		expiresAt: c.clock.Now().UTC().Add(ttl),
 	}
 
 	return nil
 }
 
+// SetDefaultTTL cambia el TTL usado cuando Set recibe ttlSecs <= 0.
+// Las claves ya guardadas conservan su expiración.
+func (c *InMemoryCache) SetDefaultTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultTTL = ttl
+}
+
 // Delete elimina un valor de la caché. Es seguro para uso concurrente.
 func (c *InMemoryCache) Delete(ctx context.Context, key string) error {
 	c.mu.Lock() // Bloqueo de escritura.
@@ -98,6 +111,63 @@ func (c *InMemoryCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// GetMulti lee varias claves con un solo bloqueo de lectura.
+func (c *InMemoryCache) GetMulti(ctx context.Context, keys []string, dest func(key string) interface{}) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.clock.Now().UTC()
+	var found []string
+	for _, key := range keys {
+		item, ok := c.store[key]
+		if !ok || now.After(item.expiresAt) {
+			continue // Miss: no existe o ha expirado.
+		}
+		if err := json.Unmarshal(item.value, dest(key)); err != nil {
+			return nil, err
+		}
+		found = append(found, key)
+	}
+	return found, nil
+}
+
+// SetMulti guarda varios valores con un solo bloqueo de escritura. Si alguno no se puede
+// serializar no se guarda ninguno.
+func (c *InMemoryCache) SetMulti(ctx context.Context, items map[string]interface{}, ttlSecs int) error {
+	payloads := make(map[string][]byte, len(items))
+	for key, val := range items {
+		data, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		payloads[key] = data
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl := c.defaultTTL
+	if ttlSecs > 0 {
+		ttl = time.Duration(ttlSecs) * time.Second
+	}
+	expiresAt := c.clock.Now().UTC().Add(ttl)
+	for key, data := range payloads {
+		c.store[key] = cacheItem{value: data, expiresAt: expiresAt}
+	}
+	return nil
+}
+
+// DeleteMulti elimina varias claves con un solo bloqueo de escritura.
+func (c *InMemoryCache) DeleteMulti(ctx context.Context, keys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.store, key)
+	}
+	return nil
+}
+
 // Stop detiene la goroutine de limpieza. Deberías llamarlo al apagar la aplicación.
 func (c *InMemoryCache) Stop() {
 	close(c.stopChan)
@@ -105,16 +175,17 @@ func (c *InMemoryCache) Stop() {
 
 // cleanupLoop es la goroutine que se ejecuta periódicamente para limpiar claves expiradas.
 func (c *InMemoryCache) cleanupLoop(interval time.Duration) {
-	ticker := time.NewTicker(interval)
+	ticker := c.clock.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
 			// Momento de limpiar.
 			c.mu.Lock() // Necesitamos un bloqueo de escritura para poder eliminar claves.
+			now := c.clock.Now().UTC()
 			for key, item := range c.store {
-				if time.Now().UTC().After(item.expiresAt) {
+				if now.After(item.expiresAt) {
 					delete(c.store, key)
 				}
 			}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisCache implementa Cache sobre Redis, guardando los valores serializados en JSON.
+type RedisCache struct {
+	client *redis.Client
+	ttl    atomic.Int64 // TTL por defecto, en nanosegundos
+}
+
+// NewRedisCache crea la caché con 'ttl' como TTL por defecto.
+func NewRedisCache(client *redis.Client, ttl time.Duration) *RedisCache {
+	c := &RedisCache{client: client}
+	c.ttl.Store(int64(ttl))
+	return c
+}
+
+// SetDefaultTTL cambia el TTL usado cuando Set recibe ttlSecs <= 0.
+func (c *RedisCache) SetDefaultTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil // cache miss
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, val interface{}, ttlSecs int) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(c.ttl.Load())
+	if ttlSecs > 0 {
+		ttl = time.Duration(ttlSecs) * time.Second
+	}
+	return c.client.Set(ctx, key, data, ttl).Err()
+}
+
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, key).Err()
+}
+
+// GetMulti lee todas las claves con un solo MGET.
+func (c *RedisCache) GetMulti(ctx context.Context, keys []string, dest func(key string) interface{}) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	values, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	var found []string
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue // nil: cache miss
+		}
+		if err := json.Unmarshal([]byte(data), dest(keys[i])); err != nil {
+			return nil, err
+		}
+		found = append(found, keys[i])
+	}
+	return found, nil
+}
+
+// SetMulti envía un SET con TTL por clave en un pipeline: una sola ida y vuelta (MSET no admite TTL).
+func (c *RedisCache) SetMulti(ctx context.Context, items map[string]interface{}, ttlSecs int) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ttl := time.Duration(c.ttl.Load())
+	if ttlSecs > 0 {
+		ttl = time.Duration(ttlSecs) * time.Second
+	}
+	payloads := make(map[string][]byte, len(items))
+	for key, val := range items {
+		data, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		payloads[key] = data
+	}
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, data := range payloads {
+			pipe.Set(ctx, key, data, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// DeleteMulti elimina todas las claves con un solo DEL.
+func (c *RedisCache) DeleteMulti(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// Verificación estática de las interfaces.
+var (
+	_ Cache            = (*RedisCache)(nil)
+	_ DefaultTTLSetter = (*RedisCache)(nil)
+)
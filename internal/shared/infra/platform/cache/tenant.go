@@ -0,0 +1,20 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// TenantKey limita 'key' al inquilino de ctx. Los repositorios filtran por inquilino, pero una
+// lectura servida desde caché no pasa por ellos: sin el prefijo, un inquilino leería la entidad
+// de otro con solo conocer su ID. El inquilino por defecto conserva las claves sin prefijo.
+//
+// La clave se forma antes de encolar las escrituras en segundo plano (AsyncCacheSet...), cuyas
+// tareas no llevan el contexto de la petición.
+func TenantKey(ctx context.Context, key string) string {
+	if tenant := tracing.TenantID(ctx); tenant != "" {
+		return "tenant:" + tenant + ":" + key
+	}
+	return key
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// WarmupBatch es el tamaño de las páginas que Warm lee y guarda de una vez.
+const WarmupBatch = 500
+
+// Warm precarga en 'cache' hasta 'limit' valores leídos con 'list', por páginas de WarmupBatch y
+// con un SetMulti por página, para que tras un despliegue las primeras lecturas no fallen todas a
+// la vez y vayan a la base de datos. 'list' devuelve la página que empieza en 'offset', en el
+// orden en que conviene precargar (los más activos primero); 'key' es la clave de cada valor.
+// Devuelve cuántos valores se han guardado, también si falla a medias.
+func Warm[T any](ctx context.Context, cache Cache, limit int, list func(offset, limit int) ([]T, error), key func(T) string) (int, error) {
+	if cache == nil {
+		return 0, nil
+	}
+	warmed := 0
+	for warmed < limit {
+		page, err := list(warmed, min(WarmupBatch, limit-warmed))
+		if err != nil {
+			return warmed, fmt.Errorf("failed to list cache warm-up values: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		items := make(map[string]interface{}, len(page))
+		for _, v := range page {
+			items[key(v)] = v
+		}
+		if err := cache.SetMulti(ctx, items, DefaultTTL); err != nil {
+			return warmed, fmt.Errorf("failed to warm cache: %w", err)
+		}
+		warmed += len(page)
+		if len(page) < WarmupBatch {
+			break
+		}
+	}
+	return warmed, nil
+}
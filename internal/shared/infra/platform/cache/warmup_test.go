@@ -0,0 +1,75 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedCache "github.com/davicafu/hexagolab/internal/shared/infra/platform/cache"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// pagedItems simula un listado paginado por offset sobre 'total' elementos y cuenta las páginas.
+func pagedItems(total int, pages *int) func(offset, limit int) ([]item, error) {
+	return func(offset, limit int) ([]item, error) {
+		*pages++
+		var page []item
+		for i := offset; i < total && i < offset+limit; i++ {
+			page = append(page, item{Name: fmt.Sprint(i)})
+		}
+		return page, nil
+	}
+}
+
+func itemKey(i item) string { return "item:" + i.Name }
+
+func TestWarm(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		limit     int
+		wantCount int
+		wantPages int
+	}{
+		{name: "menos valores que el límite", total: 3, limit: 10, wantCount: 3, wantPages: 1},
+		{name: "límite dentro de una página", total: 1000, limit: 10, wantCount: 10, wantPages: 1},
+		{name: "varias páginas", total: 2 * sharedCache.WarmupBatch, limit: 2*sharedCache.WarmupBatch + 1, wantCount: 2 * sharedCache.WarmupBatch, wantPages: 3},
+		{name: "límite cero", total: 3, limit: 0, wantCount: 0, wantPages: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			cache := mocks.NewDummyCache()
+			pages := 0
+
+			// Act
+			warmed, err := sharedCache.Warm(ctx, cache, tt.limit, pagedItems(tt.total, &pages), itemKey)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCount, warmed)
+			assert.Equal(t, tt.wantPages, pages)
+			if tt.wantCount > 0 {
+				var got item
+				hit, err := cache.Get(ctx, itemKey(item{Name: fmt.Sprint(tt.wantCount - 1)}), &got)
+				require.NoError(t, err)
+				assert.True(t, hit, "El último valor precargado está en caché")
+			}
+		})
+	}
+}
+
+func TestWarm_ListError(t *testing.T) {
+	failure := errors.New("database down")
+
+	warmed, err := sharedCache.Warm(context.Background(), mocks.NewDummyCache(), 10,
+		func(offset, limit int) ([]item, error) { return nil, failure }, itemKey)
+
+	assert.ErrorIs(t, err, failure)
+	assert.Zero(t, warmed)
+}
@@ -0,0 +1,153 @@
+// Package compression comprime los payloads grandes de los eventos (filas del outbox y mensajes
+// de Kafka) a partir de un umbral, y marca el códec usado para que el worker y los consumidores
+// los descompriman.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/golang/snappy"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// Códecs admitidos. None (o "") no comprime.
+const (
+	None   = "none"
+	Gzip   = "gzip"
+	Snappy = "snappy"
+)
+
+// Header es la marca del códec: la cabecera de los mensajes de Kafka y la clave en los
+// metadatos de un OutboxEvent. Sin ella, el payload va sin comprimir.
+const Header = "content-encoding"
+
+// ErrUnknownEncoding indica un códec que no se sabe descomprimir.
+var ErrUnknownEncoding = errors.New("unknown content encoding")
+
+// Options elige el códec y el tamaño (en bytes del JSON) a partir del cual se comprime.
+type Options struct {
+	Encoding  string
+	Threshold int
+}
+
+// ParseEncoding normaliza el nombre de un códec ("" es none).
+func ParseEncoding(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", None:
+		return None, nil
+	case Gzip:
+		return Gzip, nil
+	case Snappy:
+		return Snappy, nil
+	}
+	return "", fmt.Errorf("%w: %q (use none, gzip or snappy)", ErrUnknownEncoding, name)
+}
+
+// Compress comprime 'data' si supera el umbral y devuelve el códec usado, o "" si lo deja como
+// está.
+func (o Options) Compress(data []byte) ([]byte, string, error) {
+	encoding, err := ParseEncoding(o.Encoding)
+	if err != nil {
+		return nil, "", err
+	}
+	if encoding == None || len(data) <= o.Threshold {
+		return data, "", nil
+	}
+
+	switch encoding {
+	case Snappy:
+		return snappy.Encode(nil, data), Snappy, nil
+	default:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, "", fmt.Errorf("gzip payload: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, "", fmt.Errorf("gzip payload: %w", err)
+		}
+		return buf.Bytes(), Gzip, nil
+	}
+}
+
+// Decompress deshace Compress según el códec marcado; sin códec devuelve 'data' tal cual.
+func Decompress(data []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", None:
+		return data, nil
+	case Snappy:
+		out, err := snappy.Decode(nil, data)
+		if err != nil {
+			return nil, fmt.Errorf("snappy payload: %w", err)
+		}
+		return out, nil
+	case Gzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gzip payload: %w", err)
+		}
+		defer zr.Close()
+		out, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("gzip payload: %w", err)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownEncoding, encoding)
+}
+
+// CompressOutbox comprime el payload de 'evt' si su JSON supera el umbral. El payload
+// comprimido se guarda como un string en base64, para que siga siendo JSON en cualquier
+// almacenamiento, y el códec en Metadata[Header]. Como los repositorios solo añaden la traza a
+// los eventos sin metadatos, aquí se añade la de 'ctx' antes de marcarlo.
+func (o Options) CompressOutbox(ctx context.Context, evt sharedDomain.OutboxEvent) (sharedDomain.OutboxEvent, error) {
+	data, err := json.Marshal(evt.Payload)
+	if err != nil {
+		return evt, fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+	compressed, encoding, err := o.Compress(data)
+	if err != nil || encoding == "" {
+		return evt, err
+	}
+
+	metadata := maps.Clone(evt.Metadata)
+	if metadata == nil {
+		metadata = tracing.Metadata(ctx)
+	}
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[Header] = encoding
+	evt.Metadata = metadata
+	evt.Payload = base64.StdEncoding.EncodeToString(compressed)
+	return evt, nil
+}
+
+// OutboxPayload devuelve el JSON del payload de 'evt', descomprimido si CompressOutbox lo
+// comprimió.
+func OutboxPayload(evt sharedDomain.OutboxEvent) ([]byte, error) {
+	encoding := evt.Metadata[Header]
+	if encoding == "" {
+		return json.Marshal(evt.Payload)
+	}
+	encoded, ok := evt.Payload.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s payload must be a base64 string, got %T", encoding, evt.Payload)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s payload: %w", encoding, err)
+	}
+	return Decompress(compressed, encoding)
+}
@@ -0,0 +1,81 @@
+package compression
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func TestOptions_Compress(t *testing.T) {
+	large := []byte(`{"description":"` + strings.Repeat("lorem ipsum ", 200) + `"}`)
+
+	for _, encoding := range []string{Gzip, Snappy} {
+		t.Run(encoding, func(t *testing.T) {
+			// Act
+			compressed, used, err := Options{Encoding: encoding, Threshold: 100}.Compress(large)
+
+			// Assert: comprime por encima del umbral y se recupera el original.
+			require.NoError(t, err)
+			assert.Equal(t, encoding, used)
+			assert.Less(t, len(compressed), len(large))
+			out, err := Decompress(compressed, used)
+			require.NoError(t, err)
+			assert.Equal(t, large, out)
+		})
+	}
+
+	t.Run("below threshold", func(t *testing.T) {
+		out, used, err := Options{Encoding: Gzip, Threshold: len(large)}.Compress(large)
+
+		require.NoError(t, err)
+		assert.Empty(t, used)
+		assert.Equal(t, large, out)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, used, err := Options{Threshold: 0}.Compress(large)
+
+		require.NoError(t, err)
+		assert.Empty(t, used)
+	})
+}
+
+func TestDecompress_UnknownEncoding(t *testing.T) {
+	_, err := Decompress([]byte("x"), "br")
+
+	assert.ErrorIs(t, err, ErrUnknownEncoding)
+}
+
+func TestCompressOutbox(t *testing.T) {
+	// Arrange
+	ctx := tracing.NewContext(context.Background(), tracing.Context{CorrelationID: "req-1"})
+	evt := sharedDomain.OutboxEvent{
+		ID:        uuid.New(),
+		EventType: "task.created",
+		Payload:   map[string]string{"Description": strings.Repeat("a", 1000)},
+	}
+
+	// Act
+	compressed, err := Options{Encoding: Gzip, Threshold: 100}.CompressOutbox(ctx, evt)
+
+	// Assert: el payload es un string, la marca convive con la traza y se recupera el JSON.
+	require.NoError(t, err)
+	assert.IsType(t, "", compressed.Payload)
+	assert.Equal(t, Gzip, compressed.Metadata[Header])
+	assert.Equal(t, "req-1", tracing.FromMap(compressed.Metadata).CorrelationID)
+	assert.Nil(t, evt.Metadata, "El evento original no cambia")
+	payload, err := OutboxPayload(compressed)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Description": "`+strings.Repeat("a", 1000)+`"}`, string(payload))
+
+	plain, err := OutboxPayload(evt)
+	require.NoError(t, err)
+	assert.Equal(t, payload, plain)
+}
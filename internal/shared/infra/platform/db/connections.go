@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/querylog"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/health"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // Driver de PostgreSQL
+	_ "modernc.org/sqlite"
+)
+
+// Backends de almacenamiento soportados por las factorías de repositorios.
+const (
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+	BackendMongo    = "mongo"
+	// BackendMemory guarda los datos en el propio proceso (modo demo); no abre ninguna conexión.
+	BackendMemory = "memory"
+	// BackendFile guarda los datos en un fichero JSON local (ver File); no abre ninguna conexión.
+	BackendFile = "file"
+)
+
+// Options agrupa los datos de conexión de cada backend.
+type Options struct {
+	SQLitePath    string
+	PostgresDSN   string
+	MongoURI      string
+	MongoDatabase string
+	// FilePath es el fichero del backend file; FileKeys, si no es nil, lo cifra.
+	FilePath string
+	FileKeys *encryption.Keyring
+	// Límites de los pools; los ceros dejan los valores por defecto de cada cliente.
+	SQLitePool       SQLPool
+	PostgresPool     SQLPool
+	MongoMaxPoolSize uint64
+	MongoMinPoolSize uint64
+	// QueryLog, si no es nil, registra las consultas SQL lentas de SQLite y Postgres.
+	QueryLog *querylog.Logger
+}
+
+// Connections abre cada backend como mucho una vez y la comparte entre dominios,
+// de modo que dos dominios configurados sobre Postgres usan el mismo pool.
+type Connections struct {
+	opts Options
+
+	mu       sync.Mutex
+	sqlite   *sql.DB
+	postgres *sql.DB
+	mongo    *mongo.Client
+	// mongoPool sigue el pool del cliente de MongoDB para exponerlo en /metrics.
+	mongoPool *mongoPoolMonitor
+}
+
+// NewConnections es el constructor. No abre ninguna conexión hasta que se pide.
+func NewConnections(opts Options) *Connections {
+	return &Connections{opts: opts}
+}
+
+// SQLite devuelve la conexión a SQLite, abriéndola si es necesario.
+func (c *Connections) SQLite(ctx context.Context) (*sql.DB, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sqlite == nil {
+		db, err := c.openSQL(ctx, "sqlite", c.opts.SQLitePath, BackendSQLite, c.opts.SQLitePool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open SQLite: %w", err)
+		}
+		c.sqlite = db
+	}
+	return c.sqlite, nil
+}
+
+// Postgres devuelve la conexión a Postgres, abriéndola si es necesario.
+func (c *Connections) Postgres(ctx context.Context) (*sql.DB, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.postgres == nil {
+		db, err := c.openSQL(ctx, "pgx", c.opts.PostgresDSN, BackendPostgres, c.opts.PostgresPool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Postgres: %w", err)
+		}
+		c.postgres = db
+	}
+	return c.postgres, nil
+}
+
+// Mongo devuelve la base de datos de MongoDB configurada, conectando el cliente si es necesario.
+func (c *Connections) Mongo(ctx context.Context) (*mongo.Client, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.mongo == nil {
+		pool := &mongoPoolMonitor{maxOpen: int(c.opts.MongoMaxPoolSize)}
+		opts := options.Client().ApplyURI(c.opts.MongoURI).SetPoolMonitor(pool.monitor())
+		if c.opts.MongoMaxPoolSize > 0 {
+			opts.SetMaxPoolSize(c.opts.MongoMaxPoolSize)
+		}
+		if c.opts.MongoMinPoolSize > 0 {
+			opts.SetMinPoolSize(c.opts.MongoMinPoolSize)
+		}
+		client, err := mongo.Connect(ctx, opts)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to connect to MongoDB: %w", err)
+		}
+		c.mongo = client
+		c.mongoPool = pool
+	}
+	return c.mongo, c.opts.MongoDatabase, nil
+}
+
+// File devuelve el fichero del backend file y las claves con que se cifra (nil, en claro).
+func (c *Connections) File() (string, *encryption.Keyring) {
+	return c.opts.FilePath, c.opts.FileKeys
+}
+
+// RegisterHealthChecks registra en 'registry' una comprobación por cada backend abierto,
+// así que debe llamarse después de crear los repositorios.
+func (c *Connections) RegisterHealthChecks(registry *health.Registry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sqlite != nil {
+		registry.Register(BackendSQLite, c.sqlite.PingContext)
+	}
+	if c.postgres != nil {
+		registry.Register(BackendPostgres, c.postgres.PingContext)
+	}
+	if c.mongo != nil {
+		client := c.mongo
+		registry.Register(BackendMongo, func(ctx context.Context) error {
+			return client.Ping(ctx, readpref.Primary())
+		})
+	}
+}
+
+// RegisterPoolMetrics expone en /metrics el pool de cada backend abierto (ver
+// metrics.RegisterPoolStats), así que, como RegisterHealthChecks, debe llamarse después de crear
+// los repositorios.
+func (c *Connections) RegisterPoolMetrics() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	if c.sqlite != nil {
+		db := c.sqlite
+		errs = append(errs, metrics.RegisterPoolStats(BackendSQLite, func() metrics.PoolStats { return metrics.SQLPoolStats(db.Stats()) }))
+	}
+	if c.postgres != nil {
+		db := c.postgres
+		errs = append(errs, metrics.RegisterPoolStats(BackendPostgres, func() metrics.PoolStats { return metrics.SQLPoolStats(db.Stats()) }))
+	}
+	if c.mongoPool != nil {
+		errs = append(errs, metrics.RegisterPoolStats(BackendMongo, c.mongoPool.stats))
+	}
+	return errors.Join(errs...)
+}
+
+// Close cierra todas las conexiones abiertas.
+func (c *Connections) Close(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sqlite != nil {
+		c.sqlite.Close()
+	}
+	if c.postgres != nil {
+		c.postgres.Close()
+	}
+	if c.mongo != nil {
+		c.mongo.Disconnect(ctx)
+	}
+}
+
+func (c *Connections) openSQL(ctx context.Context, driver, dsn, backend string, pool SQLPool) (*sql.DB, error) {
+	var db *sql.DB
+	var err error
+	if c.opts.QueryLog != nil {
+		db, err = querylog.Open(driver, dsn, backend, c.opts.QueryLog)
+	} else {
+		db, err = sql.Open(driver, dsn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pool.apply(db)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
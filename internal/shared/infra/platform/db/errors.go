@@ -0,0 +1,28 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.mongodb.org/mongo-driver/mongo"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// pgUniqueViolation es el SQLSTATE de Postgres para una clave única o primaria duplicada.
+const pgUniqueViolation = "23505"
+
+// IsUniqueViolation indica si 'err' viene de insertar una clave única o primaria repetida en
+// SQLite, Postgres o MongoDB, para que los repositorios la traduzcan a su error "ya existe".
+func IsUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgUniqueViolation
+	}
+	return mongo.IsDuplicateKeyError(err)
+}
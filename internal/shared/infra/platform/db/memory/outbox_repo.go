@@ -0,0 +1,196 @@
+// Package memory contiene las piezas comunes de los repositorios en memoria del modo demo: un
+// outbox y la evaluación de criterios, orden y paginación que en SQL resuelve la base de datos.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// OutboxRepoMemory implementa shared.OutboxRepository guardando los eventos en memoria.
+// Los eventos publicados se conservan aparte para el change feed, como en los outbox SQL, y los
+// que se apartan como dead letter también.
+type OutboxRepoMemory struct {
+	mu          sync.Mutex
+	events      []domain.OutboxEvent
+	published   []domain.OutboxEvent
+	deadLetters []domain.DeadLetter
+}
+
+func NewOutboxRepoMemory() *OutboxRepoMemory {
+	return &OutboxRepoMemory{}
+}
+
+// Add guarda un evento pendiente. Los repositorios en memoria lo llaman mientras tienen su propio
+// lock, así que el cambio de estado y su evento se ven a la vez, como en una transacción.
+// El payload se guarda serializado y se relee como JSON genérico, igual que con los outbox SQL.
+func (r *OutboxRepoMemory) Add(ctx context.Context, evt domain.OutboxEvent) error {
+	payloadBytes, err := json.Marshal(evt.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+	// Se decodifica en una variable nueva: hacerlo sobre evt.Payload escribiría dentro de la
+	// entidad a la que apunta, que el servicio sigue usando.
+	var payload any
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal outbox payload: %w", err)
+	}
+	evt.Payload = payload
+	if evt.Metadata == nil {
+		evt.Metadata = tracing.Metadata(ctx)
+	}
+	evt.Processed = false
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, evt)
+	return nil
+}
+
+// FetchPendingOutbox devuelve hasta 'limit' eventos pendientes, del más antiguo al más reciente.
+func (r *OutboxRepoMemory) FetchPendingOutbox(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]domain.OutboxEvent, len(r.events))
+	copy(events, r.events)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	if limit >= 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// MarkOutboxProcessed pasa un evento ya publicado a los publicados.
+func (r *OutboxRepoMemory) MarkOutboxProcessed(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, evt := range r.events {
+		if evt.ID == id {
+			evt.Processed = true
+			r.published = append(r.published, evt)
+			r.events = append(r.events[:i], r.events[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("outbox event not found: %s", id)
+}
+
+// MarkOutboxDeadLetter aparta un evento que no se puede publicar, con su motivo.
+func (r *OutboxRepoMemory) MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, evt := range r.events {
+		if evt.ID == id {
+			r.deadLetters = append(r.deadLetters, domain.DeadLetter{OutboxEvent: evt, Reason: reason})
+			r.events = append(r.events[:i], r.events[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("outbox event not found: %s", id)
+}
+
+// ReadChanges devuelve hasta 'limit' eventos del inquilino de ctx, publicados o no, que van
+// detrás de 'after'.
+func (r *OutboxRepoMemory) ReadChanges(ctx context.Context, after domain.OutboxPosition, limit int) ([]domain.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenant := tracing.TenantID(ctx)
+	var changes []domain.OutboxEvent
+	for _, evt := range slices.Concat(r.published, r.events) {
+		if tracing.FromMap(evt.Metadata).TenantID == tenant && after.Before(evt.Position()) {
+			changes = append(changes, evt)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Position().Before(changes[j].Position()) })
+	if limit >= 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+// ListDeadLetters devuelve hasta 'limit' eventos apartados, del más antiguo al más reciente.
+func (r *OutboxRepoMemory) ListDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deadLetters := append([]domain.DeadLetter(nil), r.deadLetters...)
+	sort.SliceStable(deadLetters, func(i, j int) bool { return deadLetters[i].CreatedAt.Before(deadLetters[j].CreatedAt) })
+	if limit >= 0 && len(deadLetters) > limit {
+		deadLetters = deadLetters[:limit]
+	}
+	return deadLetters, nil
+}
+
+// RequeueDeadLetters devuelve a pendientes los eventos apartados 'ids' (todos si está vacío).
+func (r *OutboxRepoMemory) RequeueDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	return r.removeDeadLetters(ids, func(dl domain.DeadLetter) {
+		r.events = append(r.events, dl.OutboxEvent)
+	}), nil
+}
+
+// PurgeDeadLetters borra los eventos apartados 'ids' (todos si está vacío).
+func (r *OutboxRepoMemory) PurgeDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	return r.removeDeadLetters(ids, func(domain.DeadLetter) {}), nil
+}
+
+// removeDeadLetters saca de los apartados los eventos 'ids' (todos si está vacío), pasa cada uno
+// a 'fn' y devuelve cuántos sacó.
+func (r *OutboxRepoMemory) removeDeadLetters(ids []uuid.UUID, fn func(domain.DeadLetter)) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.deadLetters[:0]
+	removed := 0
+	for _, dl := range r.deadLetters {
+		if len(ids) > 0 && !slices.Contains(ids, dl.ID) {
+			kept = append(kept, dl)
+			continue
+		}
+		fn(dl)
+		removed++
+	}
+	r.deadLetters = kept
+	return removed
+}
+
+// CountPendingOutbox devuelve cuántos eventos quedan por publicar.
+func (r *OutboxRepoMemory) CountPendingOutbox(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events), nil
+}
+
+// OutboxStats cuenta los eventos pendientes y los apartados por tipo de evento.
+func (r *OutboxRepoMemory) OutboxStats(ctx context.Context) (domain.OutboxStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := domain.NewOutboxStats()
+	for _, evt := range r.events {
+		stats.Add(evt.EventType, false, 1)
+	}
+	for _, evt := range r.deadLetters {
+		stats.Add(evt.EventType, true, 1)
+	}
+	return stats, nil
+}
+
+// Verificación estática
+var _ domain.OutboxRepository = (*OutboxRepoMemory)(nil)
+var _ domain.OutboxBacklogCounter = (*OutboxRepoMemory)(nil)
+var _ domain.OutboxDeadLetterer = (*OutboxRepoMemory)(nil)
+var _ domain.OutboxStatsReader = (*OutboxRepoMemory)(nil)
+var _ domain.OutboxDeadLetterStore = (*OutboxRepoMemory)(nil)
+var _ domain.OutboxChangeReader = (*OutboxRepoMemory)(nil)
@@ -0,0 +1,354 @@
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+)
+
+// FieldFunc devuelve el valor de un campo del registro, con el mismo nombre que la columna SQL,
+// y false si el campo no existe.
+type FieldFunc[T any] func(item T, field string) (any, bool)
+
+// Query filtra, ordena y pagina 'items' como lo haría el SELECT de los repositorios SQL: los
+// criterios se evalúan como árbol (AND, OR y NOT, ver matchesCriteria) y se ordena por cada campo de 'sorts', desempatando por id
+// (ver query.OrderBy). Con CursorPagination el cursor (un query.Cursor codificado) indica el
+// último registro de la página anterior.
+func Query[T any](items []T, field FieldFunc[T], criteria domain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]T, error) {
+	var result []T
+	for _, item := range items {
+		ok, err := matchesCriteria(item, field, criteria)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, item)
+		}
+	}
+
+	// Cualquier campo puede ser un puntero nil, que compare pone antes que el resto.
+	order := sharedQuery.OrderBy(sorts)
+	for i := range order {
+		order[i].Nullable = order[i].Field != "id"
+	}
+	if len(order) > 0 {
+		var sortErr error
+		sort.SliceStable(result, func(i, j int) bool {
+			less, err := lessItems(result[i], result[j], field, order)
+			if err != nil {
+				sortErr = err
+			}
+			return less
+		})
+		if sortErr != nil {
+			return nil, sortErr
+		}
+	}
+
+	switch p := pagination.(type) {
+	case sharedQuery.OffsetPagination:
+		if p.Offset >= len(result) {
+			return nil, nil
+		}
+		result = result[max(p.Offset, 0):]
+		if p.Limit >= 0 && len(result) > p.Limit {
+			result = result[:p.Limit]
+		}
+	case sharedQuery.CursorPagination:
+		if len(order) == 0 {
+			return nil, fmt.Errorf("%w: cursor pagination needs a sort field", sharedQuery.ErrInvalidQuery)
+		}
+		if p.Cursor != "" {
+			start, err := afterCursor(result, field, order, p.Cursor)
+			if err != nil {
+				return nil, err
+			}
+			result = result[start:]
+		}
+		if p.Limit >= 0 && len(result) > p.Limit {
+			result = result[:p.Limit]
+		}
+	}
+	return result, nil
+}
+
+// Count devuelve cuántos 'items' cumplen 'criteria', como el SELECT COUNT(*) de los repositorios SQL.
+func Count[T any](items []T, field FieldFunc[T], criteria domain.Criteria) (int, error) {
+	found, err := Query(items, field, criteria, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	return len(found), nil
+}
+
+// lessItems indica si 'a' va antes que 'b' en el orden 'order': compara campo a campo y pasa al
+// siguiente solo si empatan.
+func lessItems[T any](a, b T, field FieldFunc[T], order []sharedQuery.Sort) (bool, error) {
+	for _, s := range order {
+		va, okA := field(a, s.Field)
+		vb, okB := field(b, s.Field)
+		if !okA || !okB {
+			return false, fmt.Errorf("%w: unknown sort field %q", sharedQuery.ErrInvalidQuery, s.Field)
+		}
+		c, err := compare(va, vb)
+		if err != nil {
+			return false, err
+		}
+		if c != 0 {
+			return (c < 0) != s.Desc, nil
+		}
+	}
+	return false, nil
+}
+
+// afterCursor devuelve la posición del primer registro de 'sorted' que va detrás del cursor, con
+// las mismas condiciones que los repositorios SQL (ver query.Keyset).
+func afterCursor[T any](sorted []T, field FieldFunc[T], order []sharedQuery.Sort, cursor string) (int, error) {
+	decoded, err := sharedQuery.DecodeCursor(cursor)
+	if err != nil {
+		return 0, err
+	}
+	branches, err := sharedQuery.Keyset(order, decoded)
+	if err != nil {
+		return 0, err
+	}
+	for i, item := range sorted {
+		for _, branch := range branches {
+			ok, err := matchesCursor(item, field, branch)
+			if err != nil {
+				return 0, err
+			}
+			if ok {
+				return i, nil
+			}
+		}
+	}
+	return len(sorted), nil
+}
+
+// matchesCursor evalúa una rama de query.Keyset, convirtiendo antes los valores del cursor, que
+// son texto, al tipo de cada campo.
+func matchesCursor[T any](item T, field FieldFunc[T], branch []domain.Criterion) (bool, error) {
+	for _, c := range branch {
+		v, ok := field(item, c.Field)
+		if !ok {
+			return false, fmt.Errorf("%w: unknown sort field %q", sharedQuery.ErrInvalidQuery, c.Field)
+		}
+		want := c.Value
+		if text, isText := want.(string); isText {
+			var err error
+			if want, err = parseCursorValue(v, text); err != nil {
+				return false, fmt.Errorf("%w: invalid cursor value: %v", sharedQuery.ErrInvalidQuery, err)
+			}
+		}
+		ok, err := evaluate(v, c.Op, want)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// parseCursorValue convierte el valor del cursor, que siempre es texto, al tipo del campo 'like'.
+func parseCursorValue(like any, text string) (any, error) {
+	switch normalize(like).(type) {
+	case time.Time:
+		return time.Parse(time.RFC3339Nano, text)
+	case int64:
+		return strconv.ParseInt(text, 10, 64)
+	}
+	return text, nil
+}
+
+// matchesCriteria indica si 'item' cumple 'criteria', como el WHERE de query.WhereSQL: los
+// CompositeCriteria con su operador, los NotCriteria negados y el resto como el AND de sus
+// condiciones. Se evalúan todas las ramas, sin cortocircuito, para que un campo desconocido sea
+// un error aunque otra rama ya decida el resultado. Un grupo sin condiciones no filtra.
+func matchesCriteria[T any](item T, field FieldFunc[T], criteria domain.Criteria) (bool, error) {
+	if criteria == nil {
+		return true, nil
+	}
+	if not, ok := criteria.(domain.NotCriteria); ok {
+		if not.Criteria == nil || len(not.Criteria.ToConditions()) == 0 {
+			return true, nil
+		}
+		ok, err := matchesCriteria(item, field, not.Criteria)
+		return !ok, err
+	}
+
+	composite, ok := criteria.(domain.CompositeCriteria)
+	if pointer, isPointer := criteria.(*domain.CompositeCriteria); isPointer && pointer != nil {
+		composite, ok = *pointer, true
+	}
+	if !ok {
+		return matches(item, field, criteria.ToConditions())
+	}
+
+	switch composite.Operator {
+	case domain.OpAnd, domain.OpOr, "":
+	default:
+		return false, fmt.Errorf("%w: unsupported logical operator %q", sharedQuery.ErrInvalidQuery, composite.Operator)
+	}
+	anyMatch, allMatch, empty := false, true, true
+	for _, child := range composite.Criterias {
+		if child == nil || len(child.ToConditions()) == 0 {
+			continue
+		}
+		empty = false
+		ok, err := matchesCriteria(item, field, child)
+		if err != nil {
+			return false, err
+		}
+		anyMatch, allMatch = anyMatch || ok, allMatch && ok
+	}
+	if empty {
+		return true, nil
+	}
+	if composite.Operator == domain.OpOr {
+		return anyMatch, nil
+	}
+	return allMatch, nil
+}
+
+func matches[T any](item T, field FieldFunc[T], conds []domain.Criterion) (bool, error) {
+	for _, c := range conds {
+		v, ok := field(item, c.Field)
+		if !ok {
+			return false, fmt.Errorf("%w: unknown filter field %q", sharedQuery.ErrInvalidQuery, c.Field)
+		}
+		ok, err := evaluate(v, c.Op, c.Value)
+		if err != nil {
+			return false, fmt.Errorf("invalid filter on %s: %w", c.Field, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// evaluate compara el valor 'v' de un campo con 'want' como lo haría SQL: un campo sin valor
+// (nil o un puntero nil) solo cumple OpIsNull, y uno con valor cumple OpIsNotNull.
+func evaluate(v any, op domain.Operator, want any) (bool, error) {
+	switch v = normalize(v); {
+	case op == domain.OpIsNull:
+		return v == nil, nil
+	case op == domain.OpIsNotNull:
+		return v != nil, nil
+	case v == nil:
+		return false, nil
+	}
+	switch op {
+	case domain.OpLike, domain.OpILike:
+		pattern, ok := normalize(want).(string)
+		if !ok {
+			return false, fmt.Errorf("%s needs a text pattern, got %T", op, want)
+		}
+		text, _ := normalize(v).(string)
+		return likeRegexp(pattern, op == domain.OpILike).MatchString(text), nil
+	}
+
+	order, err := compare(v, want)
+	if err != nil {
+		return false, err
+	}
+	switch op {
+	case domain.OpEq:
+		return order == 0, nil
+	case domain.OpGt:
+		return order > 0, nil
+	case domain.OpGte:
+		return order >= 0, nil
+	case domain.OpLt:
+		return order < 0, nil
+	case domain.OpLte:
+		return order <= 0, nil
+	default:
+		return false, fmt.Errorf("%w: unsupported operator %q", sharedQuery.ErrInvalidQuery, op)
+	}
+}
+
+// likeRegexp traduce un patrón LIKE (% y _ como comodines) a una expresión regular anclada.
+func likeRegexp(pattern string, caseInsensitive bool) *regexp.Regexp {
+	var b strings.Builder
+	if caseInsensitive {
+		b.WriteString("(?i)")
+	}
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// compare ordena dos valores del mismo tipo. Los UUID y los tipos basados en string (como los
+// estados) se comparan como texto, igual que en SQL. Un campo sin valor (nil o un puntero nil) va
+// antes que cualquier otro, como en SQLite y MongoDB.
+func compare(a, b any) (int, error) {
+	a, b = normalize(a), normalize(b)
+	if a == nil || b == nil {
+		switch {
+		case a == b:
+			return 0, nil
+		case a == nil:
+			return -1, nil
+		}
+		return 1, nil
+	}
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return cmp.Compare(x, y), nil
+		}
+	case int64:
+		if y, ok := b.(int64); ok {
+			return cmp.Compare(x, y), nil
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %T with %T", a, b)
+}
+
+func normalize(v any) any {
+	switch x := v.(type) {
+	case uuid.UUID:
+		return x.String()
+	case time.Time:
+		return x
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		// Los campos opcionales: un puntero nil es un campo sin valor.
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface())
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	}
+	return v
+}
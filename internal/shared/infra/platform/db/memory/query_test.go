@@ -0,0 +1,366 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+)
+
+type status string
+
+type row struct {
+	id      uuid.UUID
+	name    string
+	status  status
+	created time.Time
+}
+
+func rowField(r row, field string) (any, bool) {
+	switch field {
+	case "id":
+		return r.id, true
+	case "name":
+		return r.name, true
+	case "status":
+		return r.status, true
+	case "created_at":
+		return r.created, true
+	}
+	return nil, false
+}
+
+// criteria es un Criteria fijo para los tests.
+type criteria []domain.Criterion
+
+func (c criteria) ToConditions() []domain.Criterion { return c }
+
+func testRows() []row {
+	base := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	return []row{
+		{id: uuid.New(), name: "Ana García", status: "pending", created: base},
+		{id: uuid.New(), name: "Luis Pérez", status: "completed", created: base.Add(time.Hour)},
+		{id: uuid.New(), name: "Ana López", status: "pending", created: base.Add(2 * time.Hour)},
+	}
+}
+
+func names(rows []row) []string {
+	var out []string
+	for _, r := range rows {
+		out = append(out, r.name)
+	}
+	return out
+}
+
+func TestQuery_FiltersSortsAndPages(t *testing.T) {
+	// Arrange
+	rows := testRows()
+	filter := domain.And(
+		criteria{{Field: "status", Op: domain.OpEq, Value: status("pending")}},
+		criteria{{Field: "name", Op: domain.OpILike, Value: "ana%"}},
+	)
+
+	// Act
+	found, err := Query(rows, rowField, filter, sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "created_at", Desc: true}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ana López", "Ana García"}, names(found))
+}
+
+func TestQuery_Operators(t *testing.T) {
+	rows := testRows()
+	tests := []struct {
+		name string
+		cond domain.Criterion
+		want []string
+	}{
+		{"uuid equality", domain.Criterion{Field: "id", Op: domain.OpEq, Value: rows[1].id}, []string{"Luis Pérez"}},
+		{"time range", domain.Criterion{Field: "created_at", Op: domain.OpGte, Value: rows[1].created}, []string{"Luis Pérez", "Ana López"}},
+		{"like is case sensitive", domain.Criterion{Field: "name", Op: domain.OpLike, Value: "%lópez"}, nil},
+		{"ilike ignores case", domain.Criterion{Field: "name", Op: domain.OpILike, Value: "%lópez"}, []string{"Ana López"}},
+		{"underscore matches one char", domain.Criterion{Field: "name", Op: domain.OpLike, Value: "Lui_ P%"}, []string{"Luis Pérez"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := Query(rows, rowField, criteria{tt.cond}, nil, []sharedQuery.Sort{{Field: "created_at"}})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(found))
+		})
+	}
+}
+
+func TestQuery_LogicalOperators(t *testing.T) {
+	pending := criteria{{Field: "status", Op: domain.OpEq, Value: "pending"}}
+	lopez := criteria{{Field: "name", Op: domain.OpILike, Value: "%lópez"}}
+	luis := criteria{{Field: "name", Op: domain.OpLike, Value: "Luis%"}}
+
+	tests := []struct {
+		name     string
+		criteria domain.Criteria
+		want     []string
+	}{
+		{"and", domain.And(pending, lopez), []string{"Ana López"}},
+		{"or", domain.Or(lopez, luis), []string{"Luis Pérez", "Ana López"}},
+		{"not", domain.Not(pending), []string{"Luis Pérez"}},
+		{"not inside and", domain.And(pending, domain.Not(lopez)), []string{"Ana García"}},
+		{"not of an or", domain.Not(domain.Or(lopez, luis)), []string{"Ana García"}},
+		{"empty groups do not filter", domain.Or(domain.And(), domain.Not(domain.And())), []string{"Ana García", "Luis Pérez", "Ana López"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := Query(testRows(), rowField, tt.criteria, nil, []sharedQuery.Sort{{Field: "created_at"}})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(found))
+		})
+	}
+}
+
+func TestQuery_OffsetPagination(t *testing.T) {
+	rows := testRows()
+	sortByDate := []sharedQuery.Sort{{Field: "created_at"}}
+
+	page, err := Query(rows, rowField, nil, sharedQuery.OffsetPagination{Limit: 2, Offset: 1}, sortByDate)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Luis Pérez", "Ana López"}, names(page))
+
+	page, err = Query(rows, rowField, nil, sharedQuery.OffsetPagination{Limit: 2, Offset: 5}, sortByDate)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+}
+
+func TestQuery_CursorPagination(t *testing.T) {
+	// Arrange: dos filas con la misma fecha, que se desempatan por id.
+	rows := testRows()
+	rows = append(rows, row{id: uuid.New(), name: "Sara Ruiz", status: "failed", created: rows[1].created})
+	cursorOf := func(r row) string { return sharedQuery.NewCursor(r.id, r.created).Encode() }
+
+	for _, desc := range []bool{false, true} {
+		sortBy := []sharedQuery.Sort{{Field: "created_at", Desc: desc}}
+		all, err := Query(rows, rowField, nil, sharedQuery.CursorPagination{Limit: 10}, sortBy)
+		require.NoError(t, err)
+
+		// Act: se recorren páginas de dos en dos.
+		var walked []row
+		page := sharedQuery.CursorPagination{Limit: 2}
+		for {
+			found, err := Query(rows, rowField, nil, page, sortBy)
+			require.NoError(t, err)
+			if len(found) == 0 {
+				break
+			}
+			walked = append(walked, found...)
+			page.Cursor = cursorOf(found[len(found)-1])
+		}
+
+		// Assert
+		assert.Equal(t, names(all), names(walked), "desc=%v", desc)
+		assert.Len(t, walked, len(rows))
+	}
+
+	_, err := Query(rows, rowField, nil, sharedQuery.CursorPagination{Limit: 2, Cursor: "2025-03-01T10:00:00Z|" + rows[0].id.String()}, []sharedQuery.Sort{{Field: "created_at"}})
+	assert.ErrorIs(t, err, sharedQuery.ErrInvalidQuery, "El formato anterior ya no es un cursor")
+}
+
+func TestQuery_SeveralSortFields(t *testing.T) {
+	// Arrange
+	rows := testRows()
+	rows = append(rows, row{id: uuid.New(), name: "Sara Ruiz", status: "completed", created: rows[0].created})
+	sorts := []sharedQuery.Sort{{Field: "status"}, {Field: "created_at", Desc: true}}
+	want := []string{"Luis Pérez", "Sara Ruiz", "Ana López", "Ana García"}
+
+	// Act
+	all, err := Query(rows, rowField, nil, sharedQuery.OffsetPagination{Limit: 10}, sorts)
+	require.NoError(t, err)
+
+	var walked []row
+	page := sharedQuery.CursorPagination{Limit: 1}
+	for range rows {
+		found, err := Query(rows, rowField, nil, page, sorts)
+		require.NoError(t, err)
+		if len(found) == 0 {
+			break
+		}
+		walked = append(walked, found...)
+		last := found[len(found)-1]
+		page.Cursor = sharedQuery.NewCursorFor(sorts, last.id, func(field string) any {
+			v, _ := rowField(last, field)
+			return v
+		}).Encode()
+	}
+
+	// Assert: por estado y, dentro de cada estado, de la más reciente a la más antigua.
+	assert.Equal(t, want, names(all))
+	assert.Equal(t, want, names(walked))
+}
+
+func TestQuery_Errors(t *testing.T) {
+	rows := testRows()
+
+	_, err := Query(rows, rowField, criteria{{Field: "email", Op: domain.OpEq, Value: "a@b.c"}}, nil, nil)
+	assert.ErrorContains(t, err, `unknown filter field "email"`)
+
+	_, err = Query(rows, rowField, criteria{{Field: "created_at", Op: domain.OpGt, Value: "yesterday"}}, nil, nil)
+	assert.ErrorContains(t, err, "cannot compare time.Time with string")
+
+	_, err = Query(rows, rowField, nil, nil, []sharedQuery.Sort{{Field: "email"}})
+	assert.ErrorContains(t, err, `unknown sort field "email"`)
+
+	_, err = Query(rows, rowField, domain.Or(criteria{{Field: "name", Op: domain.OpLike, Value: "%"}}, criteria{{Field: "email", Op: domain.OpEq}}), nil, nil)
+	assert.ErrorContains(t, err, `unknown filter field "email"`, "Todas las ramas de un OR se validan")
+
+	_, err = Query(rows, rowField, domain.CompositeCriteria{Operator: "XOR", Criterias: []domain.Criteria{criteria{{Field: "name", Op: domain.OpEq}}}}, nil, nil)
+	assert.ErrorIs(t, err, sharedQuery.ErrInvalidQuery)
+}
+
+// FuzzQuery comprueba que ningún campo, operador, patrón LIKE o cursor hace entrar en pánico a
+// Query: o devuelve un error o una página de los registros de entrada.
+func FuzzQuery(f *testing.F) {
+	f.Add("name", "ILIKE", "%ana%", "created_at", false, "")
+	f.Add("status", "=", "pending", "name", true, sharedQuery.NewCursor(uuid.New(), "Ana García").Encode())
+	f.Add("name", "LIKE", "(a+)+$[\\", "id", false, "x|y")
+	f.Add("created_at", ">", "yesterday", "created_at", true, sharedQuery.NewCursor(uuid.New(), "2025-03-01T01:00:00Z").Encode())
+	f.Add("created_at", "=", "", "created_at", false, sharedQuery.NewCursor(uuid.New(), "not a date").Encode())
+	f.Add("nope", "~", "", "nope", false, "|")
+
+	rows := testRows()
+	f.Fuzz(func(t *testing.T, field, op, value, sortField string, desc bool, cursor string) {
+		conds := criteria{{Field: field, Op: domain.Operator(op), Value: value}}
+		sortBy := []sharedQuery.Sort{{Field: sortField, Desc: desc}}
+		paginations := []sharedQuery.Pagination{
+			sharedQuery.OffsetPagination{Limit: 2},
+			sharedQuery.CursorPagination{Limit: 2, Cursor: cursor},
+		}
+
+		for _, pagination := range paginations {
+			page, err := Query(rows, rowField, conds, pagination, sortBy)
+			if err != nil {
+				continue
+			}
+			require.LessOrEqual(t, len(page), 2)
+			for _, r := range page {
+				require.Contains(t, rows, r)
+			}
+		}
+	})
+}
+
+func TestOutboxRepoMemory(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewOutboxRepoMemory()
+	now := time.Now()
+	newer := domain.OutboxEvent{ID: uuid.New(), EventType: "task.created", Payload: map[string]string{"title": "b"}, CreatedAt: now}
+	older := domain.OutboxEvent{ID: uuid.New(), EventType: "task.created", Payload: map[string]string{"title": "a"}, CreatedAt: now.Add(-time.Second)}
+	require.NoError(t, repo.Add(ctx, newer))
+	require.NoError(t, repo.Add(ctx, older))
+
+	// Act
+	pending, err := repo.FetchPendingOutbox(ctx, 10)
+
+	// Assert: del más antiguo al más reciente y con el payload como JSON genérico, como en SQL.
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, older.ID, pending[0].ID)
+	assert.Equal(t, map[string]interface{}{"title": "a"}, pending[0].Payload)
+
+	require.NoError(t, repo.MarkOutboxProcessed(ctx, older.ID))
+	count, err := repo.CountPendingOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Error(t, repo.MarkOutboxProcessed(ctx, older.ID), "Ya no está pendiente")
+}
+
+func TestOutboxRepoMemory_DeadLetter(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewOutboxRepoMemory()
+	evt := domain.OutboxEvent{ID: uuid.New(), EventType: "task.created", Payload: map[string]string{"title": "a"}, CreatedAt: time.Now()}
+	require.NoError(t, repo.Add(ctx, evt))
+
+	// Act
+	err := repo.MarkOutboxDeadLetter(ctx, evt.ID, "schema violation")
+
+	// Assert: deja de estar pendiente y se conserva aparte.
+	require.NoError(t, err)
+	count, err := repo.CountPendingOutbox(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	deadLetters, err := repo.ListDeadLetters(ctx, -1)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, evt.ID, deadLetters[0].ID)
+	assert.Equal(t, "schema violation", deadLetters[0].Reason)
+	assert.Error(t, repo.MarkOutboxDeadLetter(ctx, evt.ID, "otra vez"), "Ya no está pendiente")
+	stats, err := repo.OutboxStats(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, stats.Pending)
+	assert.Equal(t, map[string]int{"task.created": 1}, stats.DeadLetters)
+}
+
+func TestOutboxRepoMemory_RequeueAndPurgeDeadLetters(t *testing.T) {
+	// Arrange: tres eventos apartados
+	ctx := context.Background()
+	repo := NewOutboxRepoMemory()
+	var ids []uuid.UUID
+	for i := 0; i < 3; i++ {
+		evt := domain.OutboxEvent{ID: uuid.New(), EventType: "task.created", Payload: map[string]string{}, CreatedAt: time.Now()}
+		require.NoError(t, repo.Add(ctx, evt))
+		require.NoError(t, repo.MarkOutboxDeadLetter(ctx, evt.ID, "schema violation"))
+		ids = append(ids, evt.ID)
+	}
+
+	// Act
+	requeued, err := repo.RequeueDeadLetters(ctx, []uuid.UUID{ids[0], uuid.New()})
+	require.NoError(t, err)
+	purged, err := repo.PurgeDeadLetters(ctx, nil)
+	require.NoError(t, err)
+
+	// Assert: el reencolado vuelve a pendientes; el resto se borra.
+	assert.Equal(t, 1, requeued)
+	assert.Equal(t, 2, purged)
+	pending, err := repo.FetchPendingOutbox(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, ids[0], pending[0].ID)
+	deadLetters, err := repo.ListDeadLetters(ctx, -1)
+	require.NoError(t, err)
+	assert.Empty(t, deadLetters)
+}
+
+func TestOutboxRepoMemory_ReadChanges(t *testing.T) {
+	// Arrange: dos eventos, el primero ya publicado, y una dead letter
+	ctx := context.Background()
+	repo := NewOutboxRepoMemory()
+	now := time.Now()
+	var ids []uuid.UUID
+	for i := 0; i < 3; i++ {
+		evt := domain.OutboxEvent{ID: uuid.New(), EventType: "task.created", Payload: map[string]string{}, CreatedAt: now.Add(time.Duration(i) * time.Second)}
+		require.NoError(t, repo.Add(ctx, evt))
+		ids = append(ids, evt.ID)
+	}
+	require.NoError(t, repo.MarkOutboxProcessed(ctx, ids[0]))
+	require.NoError(t, repo.MarkOutboxDeadLetter(ctx, ids[2], "schema violation"))
+
+	// Act
+	all, err := repo.ReadChanges(ctx, domain.OutboxPosition{}, 10)
+	require.NoError(t, err)
+	after, err := repo.ReadChanges(ctx, all[0].Position(), 10)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, all, 2)
+	assert.Equal(t, ids[0], all[0].ID)
+	assert.True(t, all[0].Processed)
+	assert.Equal(t, ids[1], all[1].ID)
+	require.Len(t, after, 1)
+	assert.Equal(t, ids[1], after[0].ID)
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -32,6 +34,10 @@ type mongoOutboxEvent struct {
 	Payload       interface{} `bson:"payload"`
 	CreatedAt     time.Time   `bson:"createdAt"`
 	Processed     bool        `bson:"processed"`
+	// Metadata lleva las cabeceras de traza de la petición de origen.
+	Metadata map[string]string `bson:"metadata,omitempty"`
+	// DeadLetterReason es el motivo por el que el evento se apartó sin publicar.
+	DeadLetterReason string `bson:"deadLetterReason,omitempty"`
 }
 
 // FetchPendingOutbox obtiene los eventos no procesados de la colección outbox.
@@ -40,7 +46,7 @@ func (r *OutboxRepoMongoDB) FetchPendingOutbox(ctx context.Context, limit int) (
 	filter := bson.M{"processed": false}
 
 	// Opciones para ordenar por fecha y limitar el número de documentos.
-	opts := options.Find().SetSort(bson.D{{"createdAt", 1}}).SetLimit(int64(limit))
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}).SetLimit(int64(limit))
 
 	cursor, err := r.outboxColl.Find(ctx, filter, opts)
 	if err != nil {
@@ -78,6 +84,161 @@ func (r *OutboxRepoMongoDB) MarkOutboxProcessed(ctx context.Context, id uuid.UUI
 	return nil
 }
 
+// MarkOutboxDeadLetter aparta un evento que no se puede publicar: deja de estar pendiente y
+// guarda el motivo en deadLetterReason.
+func (r *OutboxRepoMongoDB) MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{"processed": true, "deadLetterReason": reason}}
+
+	res, err := r.outboxColl.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("outbox event not found: %s", id)
+	}
+
+	return nil
+}
+
+// CountPendingOutbox cuenta los eventos aún no procesados.
+func (r *OutboxRepoMongoDB) CountPendingOutbox(ctx context.Context) (int, error) {
+	n, err := r.outboxColl.CountDocuments(ctx, bson.M{"processed": false})
+	return int(n), err
+}
+
+// OutboxStats cuenta los eventos pendientes y los apartados por tipo de evento.
+func (r *OutboxRepoMongoDB) OutboxStats(ctx context.Context) (sharedDomain.OutboxStats, error) {
+	cursor, err := r.outboxColl.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"processed": false},
+			bson.M{"deadLetterReason": bson.M{"$exists": true}},
+		}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"type": "$eventType", "dead": bson.M{"$gt": bson.A{"$deadLetterReason", nil}}},
+			"n":   bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return sharedDomain.OutboxStats{}, err
+	}
+	defer cursor.Close(ctx)
+
+	stats := sharedDomain.NewOutboxStats()
+	for cursor.Next(ctx) {
+		var row struct {
+			ID struct {
+				Type string `bson:"type"`
+				Dead bool   `bson:"dead"`
+			} `bson:"_id"`
+			N int `bson:"n"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return sharedDomain.OutboxStats{}, err
+		}
+		stats.Add(row.ID.Type, row.ID.Dead, row.N)
+	}
+	return stats, cursor.Err()
+}
+
+// ListDeadLetters devuelve hasta 'limit' eventos apartados (todos si es negativo), del más
+// antiguo al más reciente, con su motivo.
+func (r *OutboxRepoMongoDB) ListDeadLetters(ctx context.Context, limit int) ([]sharedDomain.DeadLetter, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	if limit >= 0 {
+		opts.SetLimit(int64(limit))
+	}
+	cursor, err := r.outboxColl.Find(ctx, deadLettersFilter(nil), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deadLetters []sharedDomain.DeadLetter
+	for cursor.Next(ctx) {
+		var mo mongoOutboxEvent
+		if err := cursor.Decode(&mo); err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, sharedDomain.DeadLetter{OutboxEvent: fromMongoOutboxEvent(&mo), Reason: mo.DeadLetterReason})
+	}
+	return deadLetters, cursor.Err()
+}
+
+// ReadChanges devuelve hasta 'limit' eventos del inquilino de ctx, publicados o no, que van
+// detrás de 'after'.
+func (r *OutboxRepoMongoDB) ReadChanges(ctx context.Context, after sharedDomain.OutboxPosition, limit int) ([]sharedDomain.OutboxEvent, error) {
+	filter := bson.M{
+		"deadLetterReason": bson.M{"$exists": false},
+		"tenantId":         tenantValue(ctx),
+		"$or": bson.A{
+			bson.M{"createdAt": bson.M{"$gt": after.CreatedAt}},
+			bson.M{"createdAt": after.CreatedAt, "_id": bson.M{"$gt": after.ID}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := r.outboxColl.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []sharedDomain.OutboxEvent
+	for cursor.Next(ctx) {
+		var mo mongoOutboxEvent
+		if err := cursor.Decode(&mo); err != nil {
+			return nil, err
+		}
+		events = append(events, fromMongoOutboxEvent(&mo))
+	}
+	return events, cursor.Err()
+}
+
+// tenantValue es la condición sobre tenantId del inquilino de ctx. Los eventos del inquilino por
+// defecto no guardan tenantId.
+func tenantValue(ctx context.Context) any {
+	tenant := tracing.TenantID(ctx)
+	if tenant == "" {
+		return bson.M{"$in": bson.A{"", nil}}
+	}
+	return tenant
+}
+
+// RequeueDeadLetters devuelve a pendientes los eventos apartados 'ids' (todos si está vacío) y
+// borra su motivo.
+func (r *OutboxRepoMongoDB) RequeueDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	update := bson.M{"$set": bson.M{"processed": false}, "$unset": bson.M{"deadLetterReason": ""}}
+	res, err := r.outboxColl.UpdateMany(ctx, deadLettersFilter(ids), update)
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount), nil
+}
+
+// PurgeDeadLetters borra los eventos apartados 'ids' (todos si está vacío).
+func (r *OutboxRepoMongoDB) PurgeDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	res, err := r.outboxColl.DeleteMany(ctx, deadLettersFilter(ids))
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
+
+// deadLettersFilter selecciona los eventos apartados 'ids' (todos si está vacío).
+func deadLettersFilter(ids []uuid.UUID) bson.M {
+	filter := bson.M{"deadLetterReason": bson.M{"$exists": true}}
+	if len(ids) > 0 {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+	return filter
+}
+
+// Preflight comprueba que MongoDB responde y que la colección outbox se puede leer.
+func (r *OutboxRepoMongoDB) Preflight(ctx context.Context) error {
+	return preflight.Mongo(ctx, r.outboxColl)
+}
+
 // fromMongoOutboxEvent es un helper para convertir de BSON a nuestro tipo de dominio.
 func fromMongoOutboxEvent(mo *mongoOutboxEvent) sharedDomain.OutboxEvent {
 	return sharedDomain.OutboxEvent{
@@ -88,8 +249,15 @@ func fromMongoOutboxEvent(mo *mongoOutboxEvent) sharedDomain.OutboxEvent {
 		Payload:       mo.Payload,
 		CreatedAt:     mo.CreatedAt,
 		Processed:     mo.Processed,
+		Metadata:      mo.Metadata,
 	}
 }
 
 // Verificación en tiempo de compilación.
 var _ sharedDomain.OutboxRepository = (*OutboxRepoMongoDB)(nil)
+var _ sharedDomain.OutboxBacklogCounter = (*OutboxRepoMongoDB)(nil)
+var _ sharedDomain.OutboxDeadLetterer = (*OutboxRepoMongoDB)(nil)
+var _ sharedDomain.OutboxStatsReader = (*OutboxRepoMongoDB)(nil)
+var _ sharedDomain.OutboxDeadLetterStore = (*OutboxRepoMongoDB)(nil)
+var _ sharedDomain.OutboxChangeReader = (*OutboxRepoMongoDB)(nil)
+var _ preflight.Checker = (*OutboxRepoMongoDB)(nil)
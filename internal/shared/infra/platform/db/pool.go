@@ -0,0 +1,86 @@
+package db
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+)
+
+// SQLPool limita el pool de conexiones de un *sql.DB. Los ceros dejan el valor por defecto de
+// database/sql (sin límite de abiertas, dos libres y sin caducidad).
+type SQLPool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// apply configura 'db' con los límites no nulos.
+func (p SQLPool) apply(db *sql.DB) {
+	if p.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(p.MaxOpenConns)
+	}
+	if p.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	if p.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	}
+	if p.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+	}
+}
+
+// mongoPoolMonitor lleva la cuenta del pool del cliente de MongoDB a partir de sus eventos, porque
+// el driver no expone estadísticas como sql.DBStats. Cuenta todos los servidores juntos.
+type mongoPoolMonitor struct {
+	maxOpen      int
+	open         atomic.Int64
+	inUse        atomic.Int64
+	checkOuts    atomic.Int64
+	checkOutTime atomic.Int64 // Nanosegundos
+	timeouts     atomic.Int64
+}
+
+// monitor devuelve el PoolMonitor que se pasa a options.Client().SetPoolMonitor.
+func (m *mongoPoolMonitor) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: m.handle}
+}
+
+func (m *mongoPoolMonitor) handle(evt *event.PoolEvent) {
+	switch evt.Type {
+	case event.ConnectionCreated:
+		m.open.Add(1)
+	case event.ConnectionClosed:
+		m.open.Add(-1)
+	case event.GetSucceeded:
+		m.inUse.Add(1)
+		m.checkOuts.Add(1)
+		m.checkOutTime.Add(int64(evt.Duration))
+	case event.ConnectionReturned:
+		m.inUse.Add(-1)
+	case event.GetFailed:
+		if evt.Reason == event.ReasonTimedOut {
+			m.timeouts.Add(1)
+		}
+	}
+}
+
+// stats devuelve la foto del pool. En MongoDB WaitCount y WaitDuration son todos los préstamos
+// de conexión y su duración, hayan tenido que esperar o no.
+func (m *mongoPoolMonitor) stats() metrics.PoolStats {
+	open, inUse := int(m.open.Load()), int(m.inUse.Load())
+	return metrics.PoolStats{
+		MaxOpen:      m.maxOpen,
+		Open:         open,
+		InUse:        inUse,
+		Idle:         max(open-inUse, 0),
+		WaitCount:    m.checkOuts.Load(),
+		WaitDuration: time.Duration(m.checkOutTime.Load()),
+		Timeouts:     m.timeouts.Load(),
+	}
+}
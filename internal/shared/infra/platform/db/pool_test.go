@@ -0,0 +1,55 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestSQLPool_Apply(t *testing.T) {
+	// Arrange
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "pool.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// Act
+	SQLPool{MaxOpenConns: 3, ConnMaxLifetime: time.Minute}.apply(db)
+
+	// Assert
+	assert.Equal(t, 3, db.Stats().MaxOpenConnections)
+}
+
+func TestMongoPoolMonitor(t *testing.T) {
+	// Arrange
+	pool := &mongoPoolMonitor{maxOpen: 10}
+	handle := pool.monitor().Event
+
+	// Act: tres conexiones, dos prestadas y una devuelta, y un préstamo que agota el timeout.
+	for _, evt := range []*event.PoolEvent{
+		{Type: event.ConnectionCreated},
+		{Type: event.ConnectionCreated},
+		{Type: event.ConnectionCreated},
+		{Type: event.GetSucceeded, Duration: 2 * time.Millisecond},
+		{Type: event.GetSucceeded, Duration: 3 * time.Millisecond},
+		{Type: event.ConnectionReturned},
+		{Type: event.GetFailed, Reason: event.ReasonTimedOut},
+		{Type: event.ConnectionClosed},
+	} {
+		handle(evt)
+	}
+
+	// Assert
+	stats := pool.stats()
+	assert.Equal(t, 10, stats.MaxOpen)
+	assert.Equal(t, 2, stats.Open)
+	assert.Equal(t, 1, stats.InUse)
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, int64(2), stats.WaitCount)
+	assert.Equal(t, 5*time.Millisecond, stats.WaitDuration)
+	assert.Equal(t, int64(1), stats.Timeouts)
+}
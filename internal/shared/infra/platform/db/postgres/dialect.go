@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"database/sql"
+
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+)
+
+// Dialect traduce los operadores de los criterios al SQL de Postgres, que los tiene todos,
+// ILIKE incluido.
+var Dialect = sharedQuery.NewDialect(nil)
+
+// Engine es Postgres para los repositorios de sqlrepo: parámetros $n, consultas fijas preparadas
+// (Statements) y el outbox en JSONB (InsertOutbox).
+var Engine = sqlrepo.Engine{
+	Backend:      platformDB.BackendPostgres,
+	Dialect:      Dialect,
+	Placeholder:  Placeholder,
+	NewExecutor:  func(db *sql.DB) sqlrepo.Executor { return NewStatements(db) },
+	InsertOutbox: InsertOutbox,
+}
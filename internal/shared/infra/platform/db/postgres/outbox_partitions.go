@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// outboxPartitionLock es la clave del bloqueo consultivo con el que las instancias se turnan para
+// mantener las particiones del outbox.
+const outboxPartitionLock = 0x6f7574626f78 // "outbox"
+
+const (
+	// outboxPartitionedSQL indica si la tabla outbox ya está particionada; no devuelve filas si no existe.
+	outboxPartitionedSQL = `SELECT relkind = 'p' FROM pg_class WHERE oid = to_regclass('outbox')`
+	// outboxPartitionsSQL lista las particiones del outbox con el límite superior de su rango.
+	outboxPartitionsSQL = `SELECT c.relname,
+		(regexp_match(pg_get_expr(c.relpartbound, c.oid), 'TO \(''([^'']+)''\)'))[1]::timestamptz
+		FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'outbox'::regclass`
+)
+
+// PartitionOptions configura el particionado diario del outbox.
+type PartitionOptions struct {
+	// Ahead son los días por delante de hoy cuyas particiones se crean de antemano. Una escritura
+	// con una fecha sin partición falla, así que deben cubrir de sobra el periodo del mantenimiento.
+	Ahead int
+	// Retention es la antigüedad a partir de la cual una partición sin eventos pendientes se
+	// desengancha como tabla de archivo (0 no archiva ninguna).
+	Retention time.Duration
+}
+
+// PartitionReport resume una pasada de mantenimiento.
+type PartitionReport struct {
+	Converted bool     // La tabla outbox se ha convertido en particionada
+	Created   []string // Particiones creadas
+	Archived  []string // Tablas de archivo creadas con las particiones desenganchadas
+}
+
+// OutboxPartitioner mantiene la tabla outbox particionada por rangos diarios de created_at, para
+// que FetchPendingOutbox siga siendo rápido con mucho tráfico y las particiones antiguas se
+// puedan retirar sin borrar fila a fila: al desengancharlas quedan como tablas de archivo
+// (outbox_archive_*) que se pueden volcar o borrar aparte.
+type OutboxPartitioner struct {
+	db   *sql.DB
+	opts PartitionOptions
+}
+
+func NewOutboxPartitioner(db *sql.DB, opts PartitionOptions) *OutboxPartitioner {
+	return &OutboxPartitioner{db: db, opts: opts}
+}
+
+// Maintain hace una pasada de mantenimiento en una transacción:
+//   - Si la tabla outbox no está particionada, la convierte: la tabla existente pasa a ser la
+//     partición outbox_legacy, con los eventos anteriores a hoy, sin copiar filas.
+//   - Crea las particiones de hoy y de los Ahead días siguientes que falten.
+//   - Desengancha las particiones cuyo rango terminó hace más de Retention y no tienen eventos
+//     pendientes; los eventos apartados que contengan se archivan con ellas.
+//
+// Las instancias se turnan con un bloqueo consultivo, así que se puede ejecutar desde todas.
+func (p *OutboxPartitioner) Maintain(ctx context.Context, now time.Time) (PartitionReport, error) {
+	var report PartitionReport
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return report, fmt.Errorf("failed to begin outbox partitioning: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, outboxPartitionLock); err != nil {
+		return report, fmt.Errorf("failed to lock outbox partitioning: %w", err)
+	}
+	today := now.UTC().Truncate(24 * time.Hour)
+	if report.Converted, err = convertOutbox(ctx, tx, today); err != nil {
+		return report, err
+	}
+	if report.Created, err = createOutboxPartitions(ctx, tx, today, p.opts.Ahead); err != nil {
+		return report, err
+	}
+	if p.opts.Retention > 0 {
+		if report.Archived, err = archiveOutboxPartitions(ctx, tx, now.Add(-p.opts.Retention)); err != nil {
+			return report, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return PartitionReport{}, fmt.Errorf("failed to commit outbox partitioning: %w", err)
+	}
+	return report, nil
+}
+
+// convertOutbox convierte la tabla outbox en particionada si aún no lo está. La clave primaria
+// pasa a ser (id, created_at), porque Postgres exige que incluya la columna de partición.
+func convertOutbox(ctx context.Context, tx *sql.Tx, today time.Time) (bool, error) {
+	var partitioned bool
+	err := tx.QueryRowContext(ctx, outboxPartitionedSQL).Scan(&partitioned)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("outbox table does not exist")
+	}
+	if err != nil || partitioned {
+		return false, err
+	}
+
+	// ATTACH valida que ningún evento existente es de hoy o posterior, y el índice de pendientes
+	// se construye también sobre outbox_legacy: las dos cosas recorren la tabla antigua una vez.
+	_, err = tx.ExecContext(ctx, `
+    LOCK TABLE outbox IN ACCESS EXCLUSIVE MODE;
+    ALTER TABLE outbox RENAME TO outbox_legacy;
+    ALTER INDEX IF EXISTS outbox_pkey RENAME TO outbox_legacy_pkey;
+    CREATE TABLE outbox (LIKE outbox_legacy INCLUDING DEFAULTS, PRIMARY KEY (id, created_at))
+        PARTITION BY RANGE (created_at);
+    ALTER TABLE outbox ATTACH PARTITION outbox_legacy FOR VALUES FROM (MINVALUE) TO (`+timestampLiteral(today)+`);
+    CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (created_at) WHERE NOT processed`)
+	if err != nil {
+		return false, fmt.Errorf("failed to partition outbox table: %w", err)
+	}
+	return true, nil
+}
+
+// createOutboxPartitions crea las particiones diarias que faltan desde 'today' hasta 'ahead'
+// días después.
+func createOutboxPartitions(ctx context.Context, tx *sql.Tx, today time.Time, ahead int) ([]string, error) {
+	existing, err := outboxPartitions(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	var created []string
+	for _, day := range outboxPartitionDays(today, ahead) {
+		name := outboxPartitionName(day)
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE %s PARTITION OF outbox FOR VALUES FROM (%s) TO (%s)`,
+			quoteIdent(name), timestampLiteral(day), timestampLiteral(day.AddDate(0, 0, 1))))
+		if err != nil {
+			return created, fmt.Errorf("failed to create outbox partition %s: %w", name, err)
+		}
+		created = append(created, name)
+	}
+	return created, nil
+}
+
+// archiveOutboxPartitions desengancha las particiones que terminan antes de 'cutoff' y no tienen
+// eventos pendientes, y las renombra como tablas de archivo. La tabla outbox se bloquea para
+// escritura mientras tanto, para que ningún evento vuelva a pendiente (RequeueDeadLetters) entre
+// la comprobación y el desenganche.
+func archiveOutboxPartitions(ctx context.Context, tx *sql.Tx, cutoff time.Time) ([]string, error) {
+	partitions, err := outboxPartitions(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	var expired []string
+	for name, end := range partitions {
+		if !end.IsZero() && !end.After(cutoff) {
+			expired = append(expired, name)
+		}
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+	sort.Strings(expired)
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE outbox IN EXCLUSIVE MODE`); err != nil {
+		return nil, fmt.Errorf("failed to lock outbox table: %w", err)
+	}
+
+	var archived []string
+	for _, name := range expired {
+		var pending bool
+		query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE NOT processed)`, quoteIdent(name))
+		if err := tx.QueryRowContext(ctx, query).Scan(&pending); err != nil {
+			return archived, fmt.Errorf("failed to check outbox partition %s: %w", name, err)
+		}
+		if pending {
+			continue
+		}
+		archive := outboxArchiveName(name)
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE outbox DETACH PARTITION %s; ALTER TABLE %s RENAME TO %s`,
+			quoteIdent(name), quoteIdent(name), quoteIdent(archive)))
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive outbox partition %s: %w", name, err)
+		}
+		archived = append(archived, archive)
+	}
+	return archived, nil
+}
+
+// outboxPartitions devuelve las particiones del outbox con el final de su rango, o el instante
+// cero si no termina (MAXVALUE).
+func outboxPartitions(ctx context.Context, tx *sql.Tx) (map[string]time.Time, error) {
+	rows, err := tx.QueryContext(ctx, outboxPartitionsSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox partitions: %w", err)
+	}
+	defer rows.Close()
+
+	partitions := make(map[string]time.Time)
+	for rows.Next() {
+		var name string
+		var end sql.NullTime
+		if err := rows.Scan(&name, &end); err != nil {
+			return nil, err
+		}
+		partitions[name] = end.Time
+	}
+	return partitions, rows.Err()
+}
+
+// outboxPartitionDays devuelve los días (en UTC) de las particiones que deben existir.
+func outboxPartitionDays(today time.Time, ahead int) []time.Time {
+	days := make([]time.Time, 0, ahead+1)
+	for i := 0; i <= ahead; i++ {
+		days = append(days, today.AddDate(0, 0, i))
+	}
+	return days
+}
+
+// outboxPartitionName es el nombre de la partición de un día: outbox_p20261016.
+func outboxPartitionName(day time.Time) string {
+	return "outbox_p" + day.Format("20060102")
+}
+
+// outboxArchiveName es el nombre de la tabla de archivo de una partición: outbox_archive_p20261016.
+func outboxArchiveName(partition string) string {
+	return "outbox_archive_" + strings.TrimPrefix(partition, "outbox_")
+}
+
+// timestampLiteral escribe 't' como literal de Postgres; los DDL no admiten parámetros.
+func timestampLiteral(t time.Time) string {
+	return "'" + t.UTC().Format(time.RFC3339) + "'"
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxPartitionNaming(t *testing.T) {
+	// Arrange
+	today := time.Date(2026, 12, 30, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	var names []string
+	for _, day := range outboxPartitionDays(today, 2) {
+		names = append(names, outboxPartitionName(day))
+	}
+
+	// Assert: una partición por día, de hoy en adelante, también al cambiar de año.
+	assert.Equal(t, []string{"outbox_p20261230", "outbox_p20261231", "outbox_p20270101"}, names)
+	assert.Equal(t, "outbox_archive_p20261230", outboxArchiveName("outbox_p20261230"))
+	assert.Equal(t, "outbox_archive_legacy", outboxArchiveName("outbox_legacy"))
+	assert.Equal(t, "'2026-12-30T00:00:00Z'", timestampLiteral(today.In(time.FixedZone("CET", 3600))))
+}
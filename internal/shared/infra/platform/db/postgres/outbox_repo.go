@@ -5,27 +5,97 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	"github.com/google/uuid"
 )
 
+// Consultas fijas de la tabla outbox, que se ejecutan como sentencias preparadas (ver Statements).
+const (
+	insertOutboxSQL = `INSERT INTO outbox (id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at, processed, tenant_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, false, $8)`
+	fetchPendingOutboxSQL = `SELECT id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at
+		 FROM outbox WHERE processed=false ORDER BY created_at LIMIT $1`
+	markOutboxProcessedSQL  = `UPDATE outbox SET processed=true WHERE id=$1`
+	markOutboxDeadLetterSQL = `UPDATE outbox SET processed=true, dead_letter_reason=$2 WHERE id=$1`
+	countPendingOutboxSQL   = `SELECT COUNT(*) FROM outbox WHERE processed=false`
+	outboxStatsSQL          = `SELECT event_type, dead_letter_reason IS NOT NULL, COUNT(*) FROM outbox
+		 WHERE processed=false OR dead_letter_reason IS NOT NULL GROUP BY 1, 2`
+	listDeadLettersSQL = `SELECT id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at, dead_letter_reason
+		 FROM outbox WHERE dead_letter_reason IS NOT NULL ORDER BY created_at LIMIT $1`
+	readChangesSQL = `SELECT id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at, processed
+		 FROM outbox WHERE tenant_id=$1 AND dead_letter_reason IS NULL AND (created_at, id) > ($2, $3)
+		 ORDER BY created_at, id LIMIT $4`
+)
+
+// InsertOutbox guarda 'evt' en la tabla outbox dentro de 'tx', la transacción de la escritura que
+// lo origina. Sin metadatos propios, el evento lleva los de la traza de 'ctx'; el inquilino de
+// esos metadatos se guarda además en tenant_id.
+func InsertOutbox(ctx context.Context, stmts sqlrepo.Executor, tx *sql.Tx, evt sharedDomain.OutboxEvent) error {
+	payloadBytes, err := json.Marshal(evt.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+	if evt.Metadata == nil {
+		evt.Metadata = tracing.Metadata(ctx)
+	}
+	metadataBytes, err := json.Marshal(evt.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox metadata: %w", err)
+	}
+
+	_, err = stmts.ExecContext(ctx, tx, insertOutboxSQL,
+		evt.ID, evt.AggregateType, evt.AggregateID, evt.EventType, payloadBytes, metadataBytes, evt.CreatedAt,
+		tracing.FromMap(evt.Metadata).TenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// scanOutboxEvent lee una fila de fetchPendingOutboxSQL. El payload y los metadatos se leen como
+// JSONB; el id y la fecha, en sus tipos nativos. 'extra' recibe las columnas que siguen a
+// created_at.
+func scanOutboxEvent(row interface{ Scan(dest ...any) error }, extra ...any) (sharedDomain.OutboxEvent, error) {
+	var evt sharedDomain.OutboxEvent
+	var payloadBytes, metadataBytes []byte
+	dest := append([]any{&evt.ID, &evt.AggregateType, &evt.AggregateID, &evt.EventType, &payloadBytes, &metadataBytes, &evt.CreatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return evt, err
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return evt, fmt.Errorf("invalid JSON payload in outbox row %s: %w", evt.ID, err)
+	}
+	evt.Payload = payload
+
+	if err := json.Unmarshal(metadataBytes, &evt.Metadata); err != nil {
+		return evt, fmt.Errorf("invalid JSON metadata in outbox row %s: %w", evt.ID, err)
+	}
+	return evt, nil
+}
+
 // OutboxRepoPostgres implementa la interfaz sharedDomain.OutboxRepository.
 type OutboxRepoPostgres struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *Statements
 }
 
 func NewOutboxRepoPostgres(db *sql.DB) *OutboxRepoPostgres {
-	return &OutboxRepoPostgres{db: db}
+	return &OutboxRepoPostgres{db: db, stmts: NewStatements(db)}
 }
 
 // FetchPendingOutbox obtiene los eventos no procesados de la tabla outbox para Postgres.
-// ✅ Nota: Ahora este método pertenece a OutboxRepoPostgres.
 func (r *OutboxRepoPostgres) FetchPendingOutbox(ctx context.Context, limit int) ([]sharedDomain.OutboxEvent, error) {
-	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at
-		 FROM outbox WHERE processed=false ORDER BY created_at LIMIT $1`, limit,
-	)
+	rows, err := r.stmts.QueryContext(ctx, fetchPendingOutboxSQL, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -33,29 +103,19 @@ func (r *OutboxRepoPostgres) FetchPendingOutbox(ctx context.Context, limit int)
 
 	var events []sharedDomain.OutboxEvent
 	for rows.Next() {
-		var evt sharedDomain.OutboxEvent
-		var payloadBytes []byte // El payload se lee como JSONB
-
-		if err := rows.Scan(&evt.ID, &evt.AggregateType, &evt.AggregateID, &evt.EventType, &payloadBytes, &evt.CreatedAt); err != nil {
+		evt, err := scanOutboxEvent(rows)
+		if err != nil {
 			return nil, err
 		}
-
-		var payload map[string]interface{}
-		if err := json.Unmarshal(payloadBytes, &payload); err != nil {
-			return nil, fmt.Errorf("invalid JSON payload in outbox row %s: %w", evt.ID, err)
-		}
-		evt.Payload = payload
-
 		events = append(events, evt)
 	}
 
-	return events, nil
+	return events, rows.Err()
 }
 
 // MarkOutboxProcessed marca un evento como procesado para Postgres.
-// ✅ Nota: Ahora este método pertenece a OutboxRepoPostgres.
 func (r *OutboxRepoPostgres) MarkOutboxProcessed(ctx context.Context, id uuid.UUID) error {
-	res, err := r.db.ExecContext(ctx, `UPDATE outbox SET processed=true WHERE id=$1`, id)
+	res, err := r.stmts.ExecContext(ctx, nil, markOutboxProcessedSQL, id)
 	if err != nil {
 		return fmt.Errorf("db error: %w", err)
 	}
@@ -70,5 +130,158 @@ func (r *OutboxRepoPostgres) MarkOutboxProcessed(ctx context.Context, id uuid.UU
 	return nil
 }
 
+// MarkOutboxDeadLetter aparta un evento que no se puede publicar: deja de estar pendiente y
+// guarda el motivo en dead_letter_reason.
+func (r *OutboxRepoPostgres) MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error {
+	res, err := r.stmts.ExecContext(ctx, nil, markOutboxDeadLetterSQL, id, reason)
+	if err != nil {
+		return fmt.Errorf("db error: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get RowsAffected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("outbox event not found: %s", id)
+	}
+	return nil
+}
+
+// CountPendingOutbox cuenta los eventos aún no procesados.
+func (r *OutboxRepoPostgres) CountPendingOutbox(ctx context.Context) (int, error) {
+	var n int
+	err := r.stmts.QueryRowContext(ctx, countPendingOutboxSQL).Scan(&n)
+	return n, err
+}
+
+// OutboxStats cuenta los eventos pendientes y los apartados por tipo de evento.
+func (r *OutboxRepoPostgres) OutboxStats(ctx context.Context) (sharedDomain.OutboxStats, error) {
+	rows, err := r.stmts.QueryContext(ctx, outboxStatsSQL)
+	if err != nil {
+		return sharedDomain.OutboxStats{}, err
+	}
+	defer rows.Close()
+
+	stats := sharedDomain.NewOutboxStats()
+	for rows.Next() {
+		var eventType string
+		var deadLetter bool
+		var n int
+		if err := rows.Scan(&eventType, &deadLetter, &n); err != nil {
+			return sharedDomain.OutboxStats{}, err
+		}
+		stats.Add(eventType, deadLetter, n)
+	}
+	return stats, rows.Err()
+}
+
+// ListDeadLetters devuelve hasta 'limit' eventos apartados (todos si es negativo), del más
+// antiguo al más reciente, con su motivo.
+func (r *OutboxRepoPostgres) ListDeadLetters(ctx context.Context, limit int) ([]sharedDomain.DeadLetter, error) {
+	var limitArg any = limit
+	if limit < 0 {
+		limitArg = nil // LIMIT NULL no limita
+	}
+	rows, err := r.stmts.QueryContext(ctx, listDeadLettersSQL, limitArg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadLetters []sharedDomain.DeadLetter
+	for rows.Next() {
+		var reason string
+		evt, err := scanOutboxEvent(rows, &reason)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, sharedDomain.DeadLetter{OutboxEvent: evt, Reason: reason})
+	}
+	return deadLetters, rows.Err()
+}
+
+// ReadChanges devuelve hasta 'limit' eventos del inquilino de ctx, publicados o no, que van
+// detrás de 'after'.
+func (r *OutboxRepoPostgres) ReadChanges(ctx context.Context, after sharedDomain.OutboxPosition, limit int) ([]sharedDomain.OutboxEvent, error) {
+	rows, err := r.stmts.QueryContext(ctx, readChangesSQL, tracing.TenantID(ctx), after.CreatedAt, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []sharedDomain.OutboxEvent
+	for rows.Next() {
+		var processed bool
+		evt, err := scanOutboxEvent(rows, &processed)
+		if err != nil {
+			return nil, err
+		}
+		evt.Processed = processed
+		events = append(events, evt)
+	}
+	return events, rows.Err()
+}
+
+// RequeueDeadLetters devuelve a pendientes los eventos apartados 'ids' (todos si está vacío) y
+// borra su motivo.
+func (r *OutboxRepoPostgres) RequeueDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	return r.execDeadLetters(ctx, `UPDATE outbox SET processed=false, dead_letter_reason=NULL`, ids)
+}
+
+// PurgeDeadLetters borra los eventos apartados 'ids' (todos si está vacío).
+func (r *OutboxRepoPostgres) PurgeDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	return r.execDeadLetters(ctx, `DELETE FROM outbox`, ids)
+}
+
+// execDeadLetters ejecuta 'stmt' sobre los eventos apartados 'ids' (todos si está vacío) y
+// devuelve cuántas filas cambió. La lista de ids varía, así que no se prepara.
+func (r *OutboxRepoPostgres) execDeadLetters(ctx context.Context, stmt string, ids []uuid.UUID) (int, error) {
+	var query strings.Builder
+	query.WriteString(stmt + " WHERE dead_letter_reason IS NOT NULL")
+	var args []any
+	if len(ids) > 0 {
+		query.WriteString(" AND ")
+		sharedQuery.WriteInSQL(&query, "id", ids, func(value any) string {
+			args = append(args, value)
+			return Placeholder(len(args))
+		})
+	}
+	res, err := r.db.ExecContext(ctx, query.String(), args...)
+	if err != nil {
+		return 0, fmt.Errorf("db error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get RowsAffected: %w", err)
+	}
+	return int(n), nil
+}
+
+// Preflight comprueba que la conexión es Postgres y que la tabla outbox tiene las columnas que usa el worker.
+func (r *OutboxRepoPostgres) Preflight(ctx context.Context) error {
+	return preflight.SQL(ctx, r.db, platformDB.BackendPostgres,
+		"SELECT id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at, processed FROM outbox LIMIT 0")
+}
+
+// MigrateOutboxSchema añade a una tabla outbox existente las columnas que se incorporaron después
+// de crearla. Es idempotente. Los eventos anteriores al inquilino quedan en el inquilino por defecto.
+func MigrateOutboxSchema(db *sql.DB) error {
+	_, err := db.Exec(`ALTER TABLE outbox
+		ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}',
+		ADD COLUMN IF NOT EXISTS dead_letter_reason TEXT,
+		ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("failed to migrate outbox table: %w", err)
+	}
+	return nil
+}
+
 // Verificación en tiempo de compilación.
 var _ sharedDomain.OutboxRepository = (*OutboxRepoPostgres)(nil)
+var _ sharedDomain.OutboxBacklogCounter = (*OutboxRepoPostgres)(nil)
+var _ sharedDomain.OutboxDeadLetterer = (*OutboxRepoPostgres)(nil)
+var _ sharedDomain.OutboxStatsReader = (*OutboxRepoPostgres)(nil)
+var _ sharedDomain.OutboxDeadLetterStore = (*OutboxRepoPostgres)(nil)
+var _ sharedDomain.OutboxChangeReader = (*OutboxRepoPostgres)(nil)
+var _ preflight.Checker = (*OutboxRepoPostgres)(nil)
@@ -0,0 +1,21 @@
+package postgres
+
+import "strconv"
+
+// placeholders son los placeholders de los primeros argumentos ("$1", "$2"...), calculados una
+// vez para no formatear uno por cada argumento de cada consulta.
+var placeholders = func() [64]string {
+	var p [64]string
+	for i := range p {
+		p[i] = "$" + strconv.Itoa(i+1)
+	}
+	return p
+}()
+
+// Placeholder devuelve el placeholder del argumento 'n' (empezando en 1) de una consulta.
+func Placeholder(n int) string {
+	if n >= 1 && n <= len(placeholders) {
+		return placeholders[n-1]
+	}
+	return "$" + strconv.Itoa(n)
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// Statements prepara las consultas fijas de un repositorio (las que no dependen de los filtros)
+// la primera vez que se usan y reutiliza el *sql.Stmt en las siguientes, así que Postgres no
+// vuelve a analizarlas ni planificarlas. database/sql se encarga de prepararlas en cada conexión
+// del pool. Las sentencias viven lo mismo que el *sql.DB, que las cierra al cerrarse.
+//
+// Si una consulta no se puede preparar, se ejecuta sin preparar: el error real, si lo hay, lo da
+// la propia ejecución.
+type Statements struct {
+	db    *sql.DB
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+func NewStatements(db *sql.DB) *Statements {
+	return &Statements{db: db, stmts: make(map[string]*sql.Stmt)}
+}
+
+// prepare devuelve la sentencia preparada de 'query', preparándola la primera vez.
+func (s *Statements) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stmt, ok := s.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.stmts[query] = stmt
+	return stmt, nil
+}
+
+// QueryRowContext es db.QueryRowContext con la sentencia preparada de 'query'.
+func (s *Statements) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := s.prepare(ctx, query)
+	if err != nil {
+		return s.db.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// QueryContext es db.QueryContext con la sentencia preparada de 'query'.
+func (s *Statements) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := s.prepare(ctx, query)
+	if err != nil {
+		return s.db.QueryContext(ctx, query, args...)
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// ExecContext ejecuta la sentencia preparada de 'query' dentro de 'tx' o, si es nil, fuera de
+// transacción.
+func (s *Statements) ExecContext(ctx context.Context, tx *sql.Tx, query string, args ...any) (sql.Result, error) {
+	stmt, err := s.prepare(ctx, query)
+	switch {
+	case err != nil && tx != nil:
+		return tx.ExecContext(ctx, query, args...)
+	case err != nil:
+		return s.db.ExecContext(ctx, query, args...)
+	case tx != nil:
+		return tx.StmtContext(ctx, stmt).ExecContext(ctx, args...)
+	}
+	return stmt.ExecContext(ctx, args...)
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// Las sentencias preparadas son de database/sql, así que se prueban sobre SQLite, sin un Postgres a mano.
+func newStatementsDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "statements.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestStatements_PreparesOnce(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	stmts := NewStatements(newStatementsDB(t))
+	const insert = `INSERT INTO items (id, name) VALUES (?, ?)`
+
+	// Act
+	_, err := stmts.ExecContext(ctx, nil, insert, 1, "uno")
+	require.NoError(t, err)
+	_, err = stmts.ExecContext(ctx, nil, insert, 2, "dos")
+	require.NoError(t, err)
+	var name string
+	require.NoError(t, stmts.QueryRowContext(ctx, `SELECT name FROM items WHERE id = ?`, 2).Scan(&name))
+
+	// Assert: una sentencia por consulta, reutilizada en cada ejecución.
+	assert.Equal(t, "dos", name)
+	assert.Len(t, stmts.stmts, 2)
+}
+
+func TestStatements_ExecInTx(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db := newStatementsDB(t)
+	stmts := NewStatements(db)
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	// Act: la sentencia preparada se ejecuta dentro de la transacción, que se descarta.
+	_, err = stmts.ExecContext(ctx, tx, `INSERT INTO items (id, name) VALUES (?, ?)`, 1, "uno")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	// Assert
+	var n int
+	require.NoError(t, stmts.QueryRowContext(ctx, `SELECT COUNT(*) FROM items`).Scan(&n))
+	assert.Zero(t, n)
+}
+
+func TestStatements_InvalidQuery(t *testing.T) {
+	stmts := NewStatements(newStatementsDB(t))
+
+	_, err := stmts.QueryContext(context.Background(), `SELECT missing FROM items`)
+
+	assert.Error(t, err, "El error de la consulta llega igual, se prepare o no")
+}
@@ -0,0 +1,95 @@
+// Package preflight comprueba al arrancar que cada adaptador de almacenamiento recibió el
+// almacenamiento que espera (dialecto SQL y tablas con las columnas que usa), para fallar en el
+// arranque con un mensaje claro en lugar de en la primera petición.
+package preflight
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+)
+
+// Timeout limita el tiempo total de las comprobaciones de un dominio.
+const Timeout = 10 * time.Second
+
+// ErrDialectMismatch indica que un adaptador SQL recibió una conexión de otro motor.
+var ErrDialectMismatch = errors.New("database dialect mismatch")
+
+// Checker lo implementan los adaptadores que saben comprobar su almacenamiento.
+type Checker interface {
+	Preflight(ctx context.Context) error
+}
+
+// Run comprueba los adaptadores que implementan Checker, en orden, y devuelve el primer fallo
+// indicando el dominio y el adaptador. Los que no lo implementan se ignoran.
+func Run(ctx context.Context, domain string, adapters ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	for _, adapter := range adapters {
+		checker, ok := adapter.(Checker)
+		if !ok {
+			continue
+		}
+		if err := checker.Preflight(ctx); err != nil {
+			return fmt.Errorf("preflight check of %s storage failed in %T: %w", domain, adapter, err)
+		}
+	}
+	return nil
+}
+
+// SQL comprueba que db responde, que habla el dialecto 'backend' (platformDB.BackendSQLite o
+// platformDB.BackendPostgres) y que la consulta 'probe' se ejecuta. La sonda debería leer las
+// columnas que usa el adaptador con LIMIT 0, para validar el esquema sin leer datos.
+func SQL(ctx context.Context, db *sql.DB, backend, probe string) error {
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+
+	dialect, err := Dialect(ctx, db)
+	if err != nil {
+		return err
+	}
+	if dialect != backend {
+		return fmt.Errorf("%w: the adapter is for %s but the connection is to %s", ErrDialectMismatch, backend, dialect)
+	}
+
+	rows, err := db.QueryContext(ctx, probe)
+	if err != nil {
+		return fmt.Errorf("probe query failed (is the schema initialized and up to date?): %w", err)
+	}
+	return rows.Close()
+}
+
+// Dialect identifica el motor de db con funciones que solo existen en cada uno.
+func Dialect(ctx context.Context, db *sql.DB) (string, error) {
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err == nil {
+		return platformDB.BackendSQLite, nil
+	}
+	if err := db.QueryRowContext(ctx, "SELECT version()").Scan(&version); err == nil && strings.Contains(version, "PostgreSQL") {
+		return platformDB.BackendPostgres, nil
+	}
+	return "", errors.New("could not identify the database engine (expected SQLite or PostgreSQL)")
+}
+
+// Mongo comprueba que el servidor de la colección responde y que se puede leer de ella.
+func Mongo(ctx context.Context, coll *mongo.Collection) error {
+	if err := coll.Database().Client().Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	if _, err := coll.CountDocuments(ctx, bson.D{}, options.Count().SetLimit(1)); err != nil {
+		return fmt.Errorf("probe query on collection %s failed: %w", coll.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package preflight
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1) // Cada conexión a :memory: es una base de datos distinta
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE tasks (id TEXT PRIMARY KEY, title TEXT)`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestSQL_AcceptsMatchingDialectAndSchema(t *testing.T) {
+	db := openSQLite(t)
+
+	err := SQL(context.Background(), db, platformDB.BackendSQLite, "SELECT id, title FROM tasks LIMIT 0")
+
+	assert.NoError(t, err)
+}
+
+func TestSQL_DetectsDialectMismatch(t *testing.T) {
+	// Arrange: un adaptador de Postgres cableado sobre SQLite.
+	db := openSQLite(t)
+
+	// Act
+	err := SQL(context.Background(), db, platformDB.BackendPostgres, "SELECT id, title FROM tasks LIMIT 0")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDialectMismatch)
+	assert.ErrorContains(t, err, "the adapter is for postgres but the connection is to sqlite")
+}
+
+func TestSQL_DetectsMissingColumns(t *testing.T) {
+	db := openSQLite(t)
+
+	err := SQL(context.Background(), db, platformDB.BackendSQLite, "SELECT id, title, status FROM tasks LIMIT 0")
+
+	assert.ErrorContains(t, err, "probe query failed (is the schema initialized and up to date?)")
+	assert.ErrorContains(t, err, "status")
+}
+
+type fakeChecker struct{ err error }
+
+func (f fakeChecker) Preflight(context.Context) error { return f.err }
+
+func TestRun_StopsAtFirstFailureNamingTheAdapter(t *testing.T) {
+	// Arrange
+	boom := errors.New("boom")
+	adapters := []any{"no es un Checker", fakeChecker{}, fakeChecker{err: boom}, fakeChecker{err: errors.New("no se llega")}}
+
+	// Act
+	err := Run(context.Background(), "task", adapters...)
+
+	// Assert
+	assert.ErrorIs(t, err, boom)
+	assert.EqualError(t, err, "preflight check of task storage failed in preflight.fakeChecker: boom")
+}
@@ -0,0 +1,195 @@
+package querylog
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+)
+
+// dsnConnector adapta un driver sin DriverContext a driver.Connector.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+// loggingConnector envuelve las conexiones del driver real.
+type loggingConnector struct {
+	next    driver.Connector
+	backend string
+	log     *Logger
+}
+
+func (c *loggingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.next.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingConn{Conn: conn, backend: c.backend, log: c.log}, nil
+}
+
+func (c *loggingConnector) Driver() driver.Driver { return c.next.Driver() }
+
+// loggingConn mide las consultas y sentencias preparadas. Las interfaces opcionales se reenvían
+// al driver real; si este no las implementa se devuelve driver.ErrSkip para que database/sql
+// use su camino genérico, igual que sin el envoltorio.
+type loggingConn struct {
+	driver.Conn
+	backend string
+	log     *Logger
+}
+
+func (c *loggingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.log.observe(ctx, c.backend, query, len(args), start, err)
+	}
+	return rows, err
+}
+
+func (c *loggingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.log.observe(ctx, c.backend, query, len(args), start, err)
+	}
+	return res, err
+}
+
+func (c *loggingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &loggingStmt{Stmt: stmt, query: query, conn: c}, nil
+}
+
+func (c *loggingConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *loggingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() // Mismo fallback que database/sql
+}
+
+func (c *loggingConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *loggingConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// loggingStmt mide la ejecución de las sentencias preparadas.
+type loggingStmt struct {
+	driver.Stmt
+	query string
+	conn  *loggingConn
+}
+
+func (s *loggingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var res driver.Result
+	var err error
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err != nil {
+			return nil, err
+		}
+		res, err = s.Stmt.Exec(values) // Driver sin StmtExecContext
+	}
+	s.conn.log.observe(ctx, s.conn.backend, s.query, len(args), start, err)
+	return res, err
+}
+
+func (s *loggingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err != nil {
+			return nil, err
+		}
+		rows, err = s.Stmt.Query(values) // Driver sin StmtQueryContext
+	}
+	s.conn.log.observe(ctx, s.conn.backend, s.query, len(args), start, err)
+	return rows, err
+}
+
+func (s *loggingStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, driver.ErrSkip
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// Verificación estática de las interfaces.
+var (
+	_ driver.Connector          = (*loggingConnector)(nil)
+	_ driver.QueryerContext     = (*loggingConn)(nil)
+	_ driver.ExecerContext      = (*loggingConn)(nil)
+	_ driver.ConnPrepareContext = (*loggingConn)(nil)
+	_ driver.ConnBeginTx        = (*loggingConn)(nil)
+	_ driver.Pinger             = (*loggingConn)(nil)
+	_ driver.SessionResetter    = (*loggingConn)(nil)
+	_ driver.Validator          = (*loggingConn)(nil)
+	_ driver.NamedValueChecker  = (*loggingConn)(nil)
+	_ driver.StmtExecContext    = (*loggingStmt)(nil)
+	_ driver.StmtQueryContext   = (*loggingStmt)(nil)
+)
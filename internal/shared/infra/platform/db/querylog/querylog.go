@@ -0,0 +1,161 @@
+package querylog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// maxQueryLen acota la longitud de la sentencia en el log.
+const maxQueryLen = 1000
+
+// ---------------- Etiquetas ----------------
+
+// Tag identifica el método de repositorio que lanza las consultas de un contexto.
+type Tag struct {
+	Repo     string // ej. "task"
+	Method   string // ej. "ListByCriteria"
+	Criteria string // Resumen de filtros y orden, sin valores (ver SummarizeCriteria)
+}
+
+type tagKey struct{}
+
+// WithTag etiqueta las consultas que se hagan con ctx.
+func WithTag(ctx context.Context, tag Tag) context.Context {
+	return context.WithValue(ctx, tagKey{}, tag)
+}
+
+// TagFromContext devuelve la etiqueta de ctx, si la hay.
+func TagFromContext(ctx context.Context) (Tag, bool) {
+	tag, ok := ctx.Value(tagKey{}).(Tag)
+	return tag, ok
+}
+
+// SummarizeCriteria describe los campos y operadores de un filtro y el orden, sin los valores
+// (que pueden ser datos personales), ej. "email ILIKE AND status =; sort status asc, created_at desc".
+func SummarizeCriteria(criteria sharedDomain.Criteria, sorts []sharedQuery.Sort) string {
+	// Se resume con la misma estructura (AND, OR, NOT y paréntesis) que el WHERE de los
+	// repositorios, pero con un dialecto que no escribe los valores.
+	summary, err := sharedQuery.WhereSQL(criteria, summaryDialect, func(sharedDomain.Criterion) error { return nil }, func(any) string { return "" })
+	if err != nil {
+		summary = "(invalid filters)"
+	}
+	if composite, ok := criteria.(sharedDomain.CompositeCriteria); ok && composite.Operator == sharedDomain.OpOr {
+		summary = strings.TrimSuffix(strings.TrimPrefix(summary, "("), ")")
+	}
+	if summary == "" {
+		summary = "(no filters)"
+	}
+	var order []string
+	for _, sort := range sorts {
+		if sort.Field == "" {
+			continue
+		}
+		dir := "asc"
+		if sort.Desc {
+			dir = "desc"
+		}
+		order = append(order, sort.Field+" "+dir)
+	}
+	if len(order) > 0 {
+		summary += "; sort " + strings.Join(order, ", ")
+	}
+	return summary
+}
+
+// summaryDialect escribe cada condición como "campo OP", sin placeholder.
+var summaryDialect = func() sharedQuery.Dialect {
+	d := sharedQuery.NewDialect(nil)
+	for op := range d {
+		d[op] = "%[1]s " + string(op) + "%[2]s"
+	}
+	return d
+}()
+
+// ---------------- Logger ----------------
+
+// Logger registra las consultas SQL que superan un umbral. El umbral se puede cambiar en
+// caliente; con 0 no se registra nada.
+type Logger struct {
+	log       *zap.Logger
+	threshold atomic.Int64
+}
+
+// NewLogger es el constructor.
+func NewLogger(log *zap.Logger, threshold time.Duration) *Logger {
+	l := &Logger{log: log}
+	l.SetThreshold(threshold)
+	return l
+}
+
+// SetThreshold cambia la duración a partir de la cual una consulta se considera lenta.
+func (l *Logger) SetThreshold(threshold time.Duration) {
+	l.threshold.Store(int64(threshold))
+}
+
+func (l *Logger) observe(ctx context.Context, backend, query string, args int, start time.Time, err error) {
+	threshold := time.Duration(l.threshold.Load())
+	elapsed := time.Since(start)
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("backend", backend),
+		zap.Duration("duration", elapsed),
+		zap.Duration("threshold", threshold),
+		zap.String("query", compact(query)),
+		zap.Int("args", args), // Solo el número: los valores pueden ser datos personales
+	}
+	if tag, ok := TagFromContext(ctx); ok {
+		fields = append(fields, zap.String("repo", tag.Repo), zap.String("method", tag.Method))
+		if tag.Criteria != "" {
+			fields = append(fields, zap.String("criteria", tag.Criteria))
+		}
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	tracing.Logger(ctx, l.log).Warn("🐢 Consulta lenta", fields...)
+}
+
+// compact deja la sentencia en una línea y la recorta.
+func compact(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxQueryLen {
+		query = query[:maxQueryLen] + "..."
+	}
+	return query
+}
+
+// ---------------- Apertura ----------------
+
+// Open abre una base de datos como sql.Open, pero interponiendo en el driver la medición de
+// cada consulta, de modo que los repositorios (y sus transacciones) se instrumentan sin cambios.
+func Open(driverName, dsn, backend string, l *Logger) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	base := db.Driver()
+	db.Close() // Solo lo abrimos para obtener el driver registrado; aún no tiene conexiones.
+
+	var connector driver.Connector
+	if dc, ok := base.(driver.DriverContext); ok {
+		if connector, err = dc.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	} else {
+		connector = dsnConnector{dsn: dsn, driver: base}
+	}
+	return sql.OpenDB(&loggingConnector{next: connector, backend: backend, log: l}), nil
+}
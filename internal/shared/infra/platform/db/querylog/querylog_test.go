@@ -0,0 +1,102 @@
+package querylog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	_ "modernc.org/sqlite"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+)
+
+type fieldCriteria struct {
+	field string
+	op    sharedDomain.Operator
+}
+
+func (c fieldCriteria) ToConditions() []sharedDomain.Criterion {
+	return []sharedDomain.Criterion{{Field: c.field, Op: c.op, Value: "secreto"}}
+}
+
+func TestSummarizeCriteria(t *testing.T) {
+	email := fieldCriteria{field: "email", op: sharedDomain.OpILike}
+	status := fieldCriteria{field: "status", op: sharedDomain.OpEq}
+
+	assert.Equal(t, "(no filters)", SummarizeCriteria(nil, nil))
+	assert.Equal(t, "email ILIKE AND status =; sort created_at desc",
+		SummarizeCriteria(sharedDomain.And(email, status), []sharedQuery.Sort{{Field: "created_at", Desc: true}}))
+	assert.Equal(t, "email ILIKE; sort status asc, created_at desc",
+		SummarizeCriteria(email, []sharedQuery.Sort{{Field: "status"}, {}, {Field: "created_at", Desc: true}}))
+	assert.Equal(t, "email ILIKE OR status =", SummarizeCriteria(sharedDomain.Or(email, status), nil))
+	assert.Equal(t, "NOT (status =) AND (email ILIKE OR status =)",
+		SummarizeCriteria(sharedDomain.And(sharedDomain.Not(status), sharedDomain.Or(email, status)), nil))
+}
+
+func openTestDB(t *testing.T, threshold time.Duration) (*Logger, *observer.ObservedLogs, func(ctx context.Context, query string, args ...any) error) {
+	t.Helper()
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := NewLogger(zap.New(core), threshold)
+
+	db, err := Open("sqlite", ":memory:", "sqlite", l)
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1) // Cada conexión a :memory: es una base de datos distinta
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE tasks (id INTEGER PRIMARY KEY, status TEXT)`)
+	require.NoError(t, err)
+
+	query := func(ctx context.Context, query string, args ...any) error {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		return rows.Close()
+	}
+	return l, logs, query
+}
+
+func TestLogger_LogsSlowQueriesWithTag(t *testing.T) {
+	// Arrange: con un umbral de 1ns todas las consultas son lentas.
+	_, logs, query := openTestDB(t, time.Nanosecond)
+	ctx := WithTag(context.Background(), Tag{Repo: "task", Method: "ListByCriteria", Criteria: "status =; sort id asc"})
+
+	// Act
+	err := query(ctx, "SELECT id FROM tasks\n\tWHERE status = ? ORDER BY id", "done")
+
+	// Assert
+	require.NoError(t, err)
+	entries := logs.FilterMessage("🐢 Consulta lenta").FilterField(zap.String("repo", "task")).All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "sqlite", fields["backend"])
+	assert.Equal(t, "SELECT id FROM tasks WHERE status = ? ORDER BY id", fields["query"])
+	assert.Equal(t, int64(1), fields["args"])
+	assert.Equal(t, "task", fields["repo"])
+	assert.Equal(t, "ListByCriteria", fields["method"])
+	assert.Equal(t, "status =; sort id asc", fields["criteria"])
+}
+
+func TestLogger_ThresholdCanBeChanged(t *testing.T) {
+	// Arrange
+	l, logs, query := openTestDB(t, 0)
+	ctx := context.Background()
+
+	// Act + Assert: con umbral 0 no se registra nada (ni siquiera el CREATE TABLE).
+	require.NoError(t, query(ctx, "SELECT id FROM tasks"))
+	assert.Zero(t, logs.FilterMessage("🐢 Consulta lenta").Len())
+
+	l.SetThreshold(time.Hour)
+	require.NoError(t, query(ctx, "SELECT id FROM tasks"))
+	assert.Zero(t, logs.FilterMessage("🐢 Consulta lenta").Len())
+
+	l.SetThreshold(time.Nanosecond)
+	require.NoError(t, query(ctx, "SELECT id FROM tasks"))
+	assert.Equal(t, 1, logs.FilterMessage("🐢 Consulta lenta").Len())
+}
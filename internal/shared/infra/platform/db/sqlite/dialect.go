@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+)
+
+// Dialect traduce los operadores de los criterios al SQL de SQLite. SQLite no tiene ILIKE: se
+// compara en minúsculas a ambos lados. LOWER (como LIKE) solo conoce las mayúsculas ASCII, así
+// que "Á" y "á" siguen siendo letras distintas.
+var Dialect = sharedQuery.NewDialect(map[domain.Operator]string{
+	domain.OpILike: "LOWER(%[1]s) LIKE LOWER(%[2]s)",
+})
+
+// Engine es SQLite para los repositorios de sqlrepo: parámetros ?, consultas sin preparar y el
+// outbox en texto (InsertOutbox). Los Mapper de SQLite guardan los UUID y las fechas como texto.
+var Engine = sqlrepo.Engine{
+	Backend:      platformDB.BackendSQLite,
+	Dialect:      Dialect,
+	Placeholder:  func(int) string { return "?" },
+	NewExecutor:  sqlrepo.Direct,
+	InsertOutbox: InsertOutbox,
+}
@@ -6,11 +6,46 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	"github.com/google/uuid"
 )
 
+// InsertOutbox guarda 'evt' en la tabla outbox dentro de 'tx', la transacción de la escritura que
+// lo origina. Sin metadatos propios, el evento lleva los de la traza de 'ctx'; el inquilino de
+// esos metadatos se guarda además en tenant_id.
+func InsertOutbox(ctx context.Context, exec sqlrepo.Executor, tx *sql.Tx, evt domain.OutboxEvent) error {
+	payloadBytes, err := json.Marshal(evt.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+	if evt.Metadata == nil {
+		evt.Metadata = tracing.Metadata(ctx)
+	}
+	metadataBytes, err := json.Marshal(evt.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox metadata: %w", err)
+	}
+
+	_, err = exec.ExecContext(ctx, tx,
+		`INSERT INTO outbox (id,aggregate_type,aggregate_id,event_type,payload,metadata,created_at,processed,tenant_id)
+		 VALUES (?,?,?,?,?,?,?,0,?)`,
+		evt.ID.String(), evt.AggregateType, evt.AggregateID, evt.EventType, string(payloadBytes), string(metadataBytes), evt.CreatedAt,
+		tracing.FromMap(evt.Metadata).TenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
 // OutboxRepoSQLite implementa la interfaz shared.OutboxRepository.
 type OutboxRepoSQLite struct {
 	db *sql.DB
@@ -24,7 +59,7 @@ func NewOutboxRepoSQLite(db *sql.DB) *OutboxRepoSQLite {
 // ✅ Nota: Ahora este método pertenece a OutboxRepoSQLite.
 func (r *OutboxRepoSQLite) FetchPendingOutbox(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at
+		`SELECT id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at
          FROM outbox
          WHERE processed = 0
          ORDER BY created_at
@@ -37,10 +72,8 @@ func (r *OutboxRepoSQLite) FetchPendingOutbox(ctx context.Context, limit int) ([
 
 	var events []domain.OutboxEvent
 	for rows.Next() {
-		var evt domain.OutboxEvent
-		var payloadStr string // El payload se lee como string en SQLite
-
-		if err := rows.Scan(&evt.ID, &evt.AggregateType, &evt.AggregateID, &evt.EventType, &payloadStr, &evt.CreatedAt); err != nil {
+		evt, err := scanOutboxEvent(rows)
+		if err != nil {
 			return nil, err
 		}
 
@@ -51,16 +84,32 @@ func (r *OutboxRepoSQLite) FetchPendingOutbox(ctx context.Context, limit int) ([
 		}
 		evt.AggregateID = parsedID.String()
 
-		if err := json.Unmarshal([]byte(payloadStr), &evt.Payload); err != nil {
-			return nil, fmt.Errorf("invalid JSON payload in outbox row %s: %w", evt.ID, err)
-		}
-
 		events = append(events, evt)
 	}
 
 	return events, nil
 }
 
+// scanOutboxEvent lee una fila con las columnas id, aggregate_type, aggregate_id, event_type,
+// payload, metadata y created_at; 'extra' recibe las que siguen. El payload y los metadatos se
+// leen como string en SQLite.
+func scanOutboxEvent(row interface{ Scan(dest ...any) error }, extra ...any) (domain.OutboxEvent, error) {
+	var evt domain.OutboxEvent
+	var payloadStr, metadataStr string
+	dest := append([]any{&evt.ID, &evt.AggregateType, &evt.AggregateID, &evt.EventType, &payloadStr, &metadataStr, &evt.CreatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return evt, err
+	}
+
+	if err := json.Unmarshal([]byte(payloadStr), &evt.Payload); err != nil {
+		return evt, fmt.Errorf("invalid JSON payload in outbox row %s: %w", evt.ID, err)
+	}
+	if err := json.Unmarshal([]byte(metadataStr), &evt.Metadata); err != nil {
+		return evt, fmt.Errorf("invalid JSON metadata in outbox row %s: %w", evt.ID, err)
+	}
+	return evt, nil
+}
+
 // MarkOutboxProcessed marca un evento como procesado para SQLite.
 // ✅ Nota: Ahora este método pertenece a OutboxRepoSQLite.
 func (r *OutboxRepoSQLite) MarkOutboxProcessed(ctx context.Context, id uuid.UUID) error {
@@ -79,5 +128,211 @@ func (r *OutboxRepoSQLite) MarkOutboxProcessed(ctx context.Context, id uuid.UUID
 	return nil
 }
 
+// MarkOutboxDeadLetter aparta un evento que no se puede publicar: deja de estar pendiente y
+// guarda el motivo en dead_letter_reason.
+func (r *OutboxRepoSQLite) MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE outbox SET processed = 1, dead_letter_reason = ? WHERE id = ?`, reason, id)
+	if err != nil {
+		return fmt.Errorf("db error: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get RowsAffected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("outbox event not found: %s", id)
+	}
+	return nil
+}
+
+// CountPendingOutbox cuenta los eventos aún no procesados.
+func (r *OutboxRepoSQLite) CountPendingOutbox(ctx context.Context) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM outbox WHERE processed = 0`).Scan(&n)
+	return n, err
+}
+
+// OutboxStats cuenta los eventos pendientes y los apartados por tipo de evento.
+func (r *OutboxRepoSQLite) OutboxStats(ctx context.Context) (domain.OutboxStats, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT event_type, dead_letter_reason IS NOT NULL, COUNT(*) FROM outbox
+		WHERE processed = 0 OR dead_letter_reason IS NOT NULL GROUP BY 1, 2`)
+	if err != nil {
+		return domain.OutboxStats{}, err
+	}
+	defer rows.Close()
+
+	stats := domain.NewOutboxStats()
+	for rows.Next() {
+		var eventType string
+		var deadLetter bool
+		var n int
+		if err := rows.Scan(&eventType, &deadLetter, &n); err != nil {
+			return domain.OutboxStats{}, err
+		}
+		stats.Add(eventType, deadLetter, n)
+	}
+	return stats, rows.Err()
+}
+
+// ListDeadLetters devuelve hasta 'limit' eventos apartados (todos si es negativo), del más
+// antiguo al más reciente, con su motivo.
+func (r *OutboxRepoSQLite) ListDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at, dead_letter_reason
+         FROM outbox
+         WHERE dead_letter_reason IS NOT NULL
+         ORDER BY created_at
+         LIMIT ?`, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadLetters []domain.DeadLetter
+	for rows.Next() {
+		var reason string
+		evt, err := scanOutboxEvent(rows, &reason)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, domain.DeadLetter{OutboxEvent: evt, Reason: reason})
+	}
+	return deadLetters, rows.Err()
+}
+
+// ReadChanges devuelve hasta 'limit' eventos del inquilino de ctx, publicados o no, que van
+// detrás de 'after'. created_at se guarda como el texto de time.Time.String(), que ordena bien
+// pero no se puede comparar por igualdad con otra fecha; por eso SQL filtra por el segundo de
+// 'after' y la posición exacta se compara al leer.
+func (r *OutboxRepoSQLite) ReadChanges(ctx context.Context, after domain.OutboxPosition, limit int) ([]domain.OutboxEvent, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at, processed
+         FROM outbox
+         WHERE tenant_id = ? AND dead_letter_reason IS NULL AND created_at >= ?
+         ORDER BY created_at, id`,
+		tracing.TenantID(ctx), after.CreatedAt.In(time.Local).Format(time.DateTime),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.OutboxEvent
+	for len(events) < limit && rows.Next() {
+		var processed bool
+		evt, err := scanOutboxEvent(rows, &processed)
+		if err != nil {
+			return nil, err
+		}
+		if !after.Before(evt.Position()) {
+			continue
+		}
+		evt.Processed = processed
+		events = append(events, evt)
+	}
+	return events, rows.Err()
+}
+
+// RequeueDeadLetters devuelve a pendientes los eventos apartados 'ids' (todos si está vacío) y
+// borra su motivo.
+func (r *OutboxRepoSQLite) RequeueDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	return r.execDeadLetters(ctx, `UPDATE outbox SET processed = 0, dead_letter_reason = NULL`, ids)
+}
+
+// PurgeDeadLetters borra los eventos apartados 'ids' (todos si está vacío).
+func (r *OutboxRepoSQLite) PurgeDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	return r.execDeadLetters(ctx, `DELETE FROM outbox`, ids)
+}
+
+// execDeadLetters ejecuta 'stmt' sobre los eventos apartados 'ids' (todos si está vacío) y
+// devuelve cuántas filas cambió.
+func (r *OutboxRepoSQLite) execDeadLetters(ctx context.Context, stmt string, ids []uuid.UUID) (int, error) {
+	var query strings.Builder
+	query.WriteString(stmt + " WHERE dead_letter_reason IS NOT NULL")
+	var args []any
+	if len(ids) > 0 {
+		query.WriteString(" AND ")
+		sharedQuery.WriteInSQL(&query, "id", ids, func(value any) string {
+			args = append(args, value.(uuid.UUID).String())
+			return "?"
+		})
+	}
+	res, err := r.db.ExecContext(ctx, query.String(), args...)
+	if err != nil {
+		return 0, fmt.Errorf("db error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get RowsAffected: %w", err)
+	}
+	return int(n), nil
+}
+
+// Preflight comprueba que la conexión es SQLite y que la tabla outbox tiene las columnas que usa el worker.
+func (r *OutboxRepoSQLite) Preflight(ctx context.Context) error {
+	return preflight.SQL(ctx, r.db, platformDB.BackendSQLite,
+		"SELECT id, aggregate_type, aggregate_id, event_type, payload, metadata, created_at, processed FROM outbox LIMIT 0")
+}
+
+// MigrateOutboxSchema añade a una tabla outbox existente las columnas que se incorporaron después
+// de crearla. Los eventos anteriores al inquilino quedan en el inquilino por defecto.
+func MigrateOutboxSchema(db *sql.DB) error {
+	return MigrateColumns(db, "outbox",
+		Column{"metadata", `metadata TEXT NOT NULL DEFAULT '{}'`},
+		Column{"dead_letter_reason", `dead_letter_reason TEXT`},
+		Column{"tenant_id", `tenant_id TEXT NOT NULL DEFAULT ''`},
+	)
+}
+
+// Column es una columna que MigrateColumns añade si falta: su nombre y su definición en ADD COLUMN.
+type Column struct {
+	Name       string
+	Definition string
+}
+
+// MigrateColumns añade a 'table' las columnas de 'columns' que aún no tiene. Es idempotente:
+// SQLite no admite ADD COLUMN IF NOT EXISTS, así que se consulta antes.
+func MigrateColumns(db *sql.DB, table string, columns ...Column) error {
+	for _, column := range columns {
+		var n int
+		err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column.Name).Scan(&n)
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s table: %w", table, err)
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column.Definition); err != nil {
+			return fmt.Errorf("failed to migrate %s table: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// RenameColumn renombra la columna 'from' de 'table' a 'to' si la tabla aún la tiene. Es
+// idempotente: en las tablas ya migradas, o creadas con el nombre nuevo, no hace nada.
+func RenameColumn(db *sql.DB, table, from, to string) error {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, from).Scan(&n)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	if n == 0 {
+		return nil
+	}
+	if _, err := db.Exec(`ALTER TABLE ` + table + ` RENAME COLUMN ` + from + ` TO ` + to); err != nil {
+		return fmt.Errorf("failed to migrate %s table: %w", table, err)
+	}
+	return nil
+}
+
 // Verificación en tiempo de compilación.
 var _ domain.OutboxRepository = (*OutboxRepoSQLite)(nil)
+var _ domain.OutboxBacklogCounter = (*OutboxRepoSQLite)(nil)
+var _ domain.OutboxDeadLetterer = (*OutboxRepoSQLite)(nil)
+var _ domain.OutboxStatsReader = (*OutboxRepoSQLite)(nil)
+var _ domain.OutboxDeadLetterStore = (*OutboxRepoSQLite)(nil)
+var _ domain.OutboxChangeReader = (*OutboxRepoSQLite)(nil)
+var _ preflight.Checker = (*OutboxRepoSQLite)(nil)
@@ -0,0 +1,56 @@
+package sqlrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+)
+
+// EncryptColumn cifra con la clave activa de 'keys' los valores en claro de la columna de texto
+// 'column' de 'table', en todos los inquilinos, y devuelve cuántas filas ha cifrado. Migra los
+// datos guardados antes de activar el cifrado; los valores ya cifrados no se tocan, así que se
+// puede ejecutar en cada arranque.
+func EncryptColumn(ctx context.Context, db *sql.DB, engine Engine, table, column string, keys *encryption.Keyring) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	plain, err := plainValues(ctx, tx, engine, table, column)
+	if err != nil {
+		return 0, err
+	}
+	update := "UPDATE " + table + " SET " + column + " = " + engine.Placeholder(1) +
+		" WHERE id = " + engine.Placeholder(2) + " AND " + column + " = " + engine.Placeholder(3)
+	for id, value := range plain {
+		if _, err := tx.ExecContext(ctx, update, keys.Encrypt(value), id, value); err != nil {
+			return 0, fmt.Errorf("failed to encrypt %s.%s: %w", table, column, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(plain), nil
+}
+
+// plainValues devuelve, por id, los valores de 'column' que no están cifrados.
+func plainValues(ctx context.Context, tx *sql.Tx, engine Engine, table, column string) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id, "+column+" FROM "+table+" WHERE "+column+" NOT LIKE "+engine.Placeholder(1), encryption.Prefix+"%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s.%s: %w", table, column, err)
+	}
+	defer rows.Close()
+
+	plain := map[string]string{}
+	for rows.Next() {
+		var id, value string
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, err
+		}
+		plain[id] = value
+	}
+	return plain, rows.Err()
+}
@@ -0,0 +1,518 @@
+// Package sqlrepo es la base común de los repositorios SQL de los agregados: el CRUD con su
+// evento de outbox en la misma transacción, la lectura por id, los listados con criterios,
+// ordenación y paginación, el recuento y la comprobación de arranque. Cada motor aporta un Engine
+// (sharedPostgres.Engine, sharedSQLite.Engine) y cada agregado, por motor, un Mapper con su tabla:
+// un agregado o un motor nuevos solo necesitan su Mapper y pasar la suite de conformidad
+// (tests/conformance).
+package sqlrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// Scanner es una fila de *sql.Row o de *sql.Rows.
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+// Executor ejecuta las consultas fijas del repositorio (las que no dependen de los filtros):
+// directamente (Direct) o como sentencias preparadas (sharedPostgres.Statements).
+type Executor interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	// ExecContext ejecuta 'query' dentro de 'tx' o, si es nil, fuera de transacción.
+	ExecContext(ctx context.Context, tx *sql.Tx, query string, args ...any) (sql.Result, error)
+}
+
+// Engine reúne lo que cambia de un motor SQL a otro.
+type Engine struct {
+	// Backend es el motor que comprueba Preflight (platformDB.BackendPostgres, ...).
+	Backend string
+	// Dialect traduce los operadores de los criterios.
+	Dialect sharedQuery.Dialect
+	// Placeholder devuelve el parámetro n-ésimo de una consulta, empezando en 1.
+	Placeholder func(n int) string
+	// NewExecutor crea el Executor de las consultas fijas sobre db.
+	NewExecutor func(db *sql.DB) Executor
+	// InsertOutbox guarda 'evt' en la tabla outbox dentro de 'tx'.
+	InsertOutbox func(ctx context.Context, exec Executor, tx *sql.Tx, evt sharedDomain.OutboxEvent) error
+}
+
+// Table describe la tabla de un agregado.
+type Table struct {
+	Name string
+	// Columns son las columnas que se leen e insertan, con el id primero. tenant_id no se incluye:
+	// lo añade el repositorio.
+	Columns []string
+	// Immutable son las columnas que Update no modifica, además del id (ej. created_at).
+	Immutable []string
+	// Fields son las columnas por las que se puede filtrar y ordenar, tenant_id incluida.
+	Fields sharedQuery.Fields
+	// Nullable son las de Fields que admiten NULL, que se ordenan igual en todos los motores (ver
+	// sharedQuery.Sort.Nullable).
+	Nullable sharedQuery.Fields
+	// NotFound es el error de una fila que no existe; Conflict, el de una clave única repetida.
+	NotFound error
+	Conflict error
+	// Version es la columna de Columns con la versión del agregado, si la tiene: Update solo
+	// escribe si la versión guardada es la anterior a la de su valor (un int64) y, si no, devuelve
+	// VersionConflict.
+	Version         string
+	VersionConflict error
+}
+
+// Mapper traduce un agregado a y desde las filas de su tabla en un motor concreto.
+type Mapper[T any] interface {
+	Table() Table
+	// Values devuelve los valores de 't' en el orden de Table.Columns, en los tipos que guarda el motor.
+	Values(t *T) []any
+	// Scan lee una fila de Table.Columns.
+	Scan(row Scanner) (*T, error)
+	// Arg convierte el valor de un criterio, de un cursor o de un id al tipo que guarda el motor.
+	Arg(value any) any
+}
+
+// CursorMapper lo implementa el Mapper que tiene que preparar los valores de los cursores, que
+// llegan como texto, antes de convertirlos con Arg (ej. para parsear sus fechas).
+type CursorMapper interface {
+	CursorValues(branches [][]sharedDomain.Criterion) error
+}
+
+// Direct devuelve un Executor que ejecuta las consultas sin preparar.
+func Direct(db *sql.DB) Executor {
+	return direct{db: db}
+}
+
+type direct struct {
+	db *sql.DB
+}
+
+func (d direct) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.db.QueryRowContext(ctx, query, args...)
+}
+
+func (d direct) ExecContext(ctx context.Context, tx *sql.Tx, query string, args ...any) (sql.Result, error) {
+	if tx != nil {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	return d.db.ExecContext(ctx, query, args...)
+}
+
+// Repository implementa, para el agregado T, los métodos comunes de los puertos de repositorio.
+// Los repositorios de cada agregado lo embeben y añaden lo que sea propio de él. Todas las
+// consultas se limitan al inquilino de ctx (tenant_id).
+type Repository[T any] struct {
+	db     *sql.DB
+	engine Engine
+	mapper Mapper[T]
+	table  Table
+	exec   Executor
+
+	columns    string
+	selectSQL  string
+	getByIDSQL string
+	existsSQL  string
+	insertSQL  string
+	updateSQL  string
+	deleteSQL  string
+	// updated son los índices de Table.Columns que escribe Update.
+	updated []int
+	// version es el índice de Table.Version en Table.Columns, o -1 si no tiene versión.
+	version int
+}
+
+// New crea el repositorio de T sobre 'db' con el motor 'engine'.
+func New[T any](db *sql.DB, engine Engine, mapper Mapper[T]) *Repository[T] {
+	table := mapper.Table()
+	r := &Repository[T]{db: db, engine: engine, mapper: mapper, table: table, columns: strings.Join(table.Columns, ", ")}
+	if db != nil {
+		r.exec = engine.NewExecutor(db)
+	}
+	p := engine.Placeholder
+	id := table.Columns[0]
+
+	r.selectSQL = "SELECT " + r.columns + " FROM " + table.Name
+	r.getByIDSQL = r.selectSQL + " WHERE " + id + "=" + p(1) + " AND " + sharedQuery.TenantColumn + "=" + p(2)
+	r.existsSQL = "SELECT 1 FROM " + table.Name + " WHERE " + id + "=" + p(1) + " AND " + sharedQuery.TenantColumn + "=" + p(2)
+
+	placeholders := make([]string, 0, len(table.Columns)+1)
+	for i := range len(table.Columns) + 1 {
+		placeholders = append(placeholders, p(i+1))
+	}
+	r.insertSQL = "INSERT INTO " + table.Name + " (" + r.columns + ", " + sharedQuery.TenantColumn + ") VALUES (" +
+		strings.Join(placeholders, ", ") + ")"
+
+	var set []string
+	for i, column := range table.Columns[1:] {
+		if !slices.Contains(table.Immutable, column) {
+			r.updated = append(r.updated, i+1)
+			set = append(set, column+"="+p(len(set)+1))
+		}
+	}
+	r.updateSQL = "UPDATE " + table.Name + " SET " + strings.Join(set, ", ") +
+		" WHERE " + id + "=" + p(len(set)+1) + " AND " + sharedQuery.TenantColumn + "=" + p(len(set)+2)
+	r.version = slices.Index(table.Columns, table.Version)
+	if table.Version == "" {
+		r.version = -1
+	} else {
+		r.updateSQL += " AND " + table.Version + "=" + p(len(set)+3)
+	}
+	r.deleteSQL = "DELETE FROM " + table.Name + " WHERE " + id + "=" + p(1) + " AND " + sharedQuery.TenantColumn + "=" + p(2)
+	return r
+}
+
+// ------------------ CRUD + Outbox ------------------
+
+// Create inserta 't' y 'evt' en una transacción, en el inquilino de ctx.
+func (r *Repository[T]) Create(ctx context.Context, t *T, evt sharedDomain.OutboxEvent) error {
+	return r.write(ctx, evt, func(tx *sql.Tx) error {
+		_, err := r.exec.ExecContext(ctx, tx, r.insertSQL, append(r.mapper.Values(t), tracing.TenantID(ctx))...)
+		return err
+	})
+}
+
+// Update actualiza 't' y crea 'evt' en una transacción. Si la tabla tiene versión, 't' trae ya la
+// nueva y solo se escribe sobre la anterior: si la guardada es otra, devuelve Table.VersionConflict.
+func (r *Repository[T]) Update(ctx context.Context, t *T, evt sharedDomain.OutboxEvent) error {
+	values := r.mapper.Values(t)
+	args := make([]any, 0, len(r.updated)+3)
+	for _, i := range r.updated {
+		args = append(args, values[i])
+	}
+	args = append(args, values[0], tracing.TenantID(ctx))
+	if r.version >= 0 {
+		args = append(args, values[r.version].(int64)-1)
+	}
+	return r.write(ctx, evt, func(tx *sql.Tx) error {
+		err := r.affectOne(ctx, tx, r.updateSQL, args...)
+		if r.version >= 0 && errors.Is(err, r.table.NotFound) {
+			// La fila existe con otra versión, o no existe.
+			var one int
+			if tx.QueryRowContext(ctx, r.existsSQL, values[0], tracing.TenantID(ctx)).Scan(&one) == nil {
+				return r.table.VersionConflict
+			}
+		}
+		return err
+	})
+}
+
+// UpdateMany escribe con una sola sentencia las columnas 'columns' de 'items', que tienen en ellas
+// los mismos valores (se escriben los del primero), y guarda en la misma transacción el evento de
+// cada fila escrita: el de 'events' cuyo AggregateID es su id. Si la tabla tiene versión, cada
+// fila solo se escribe sobre la anterior a la de su agregado, y la versión sube en uno; las demás
+// filas, y las que no existen, se omiten. Devuelve los ids de las filas escritas.
+func (r *Repository[T]) UpdateMany(ctx context.Context, columns []string, items []*T, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	p := r.engine.Placeholder
+	id := r.table.Columns[0]
+	first := r.mapper.Values(items[0])
+	args := make([]any, 0, len(columns)+1+2*len(items))
+
+	var query strings.Builder
+	query.WriteString("UPDATE " + r.table.Name + " SET ")
+	for i, column := range columns {
+		index := slices.Index(r.table.Columns, column)
+		if index <= 0 || slices.Contains(r.table.Immutable, column) {
+			return nil, fmt.Errorf("column %s of %s cannot be updated", column, r.table.Name)
+		}
+		args = append(args, first[index])
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString(column + "=" + p(len(args)))
+	}
+	if r.version >= 0 {
+		query.WriteString(", " + r.table.Version + "=" + r.table.Version + "+1")
+	}
+	args = append(args, tracing.TenantID(ctx))
+	query.WriteString(" WHERE " + sharedQuery.TenantColumn + "=" + p(len(args)) + " AND (")
+	for i, item := range items {
+		values := r.mapper.Values(item)
+		if i > 0 {
+			query.WriteString(" OR ")
+		}
+		args = append(args, values[0])
+		query.WriteString("(" + id + "=" + p(len(args)))
+		if r.version >= 0 {
+			args = append(args, values[r.version].(int64)-1)
+			query.WriteString(" AND " + r.table.Version + "=" + p(len(args)))
+		}
+		query.WriteString(")")
+	}
+	query.WriteString(") RETURNING " + id)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // Se ignora si el Commit() es exitoso
+
+	updated, err := scanIDs(tx.QueryContext(ctx, query.String(), args...))
+	if err != nil {
+		return nil, fmt.Errorf("db error: %w", err)
+	}
+	written := make(map[string]bool, len(updated))
+	for _, id := range updated {
+		written[id.String()] = true
+	}
+	for _, evt := range events {
+		if !written[evt.AggregateID] {
+			continue
+		}
+		if err := r.engine.InsertOutbox(ctx, r.exec, tx, evt); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// scanIDs lee los ids que devuelve un RETURNING y cierra las filas.
+func scanIDs(rows *sql.Rows, err error) ([]uuid.UUID, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteByID elimina el agregado 'id' y crea 'evt' en una transacción.
+func (r *Repository[T]) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	return r.write(ctx, evt, func(tx *sql.Tx) error {
+		return r.affectOne(ctx, tx, r.deleteSQL, r.mapper.Arg(id), tracing.TenantID(ctx))
+	})
+}
+
+// write ejecuta 'change' y guarda 'evt' en el outbox en la misma transacción.
+func (r *Repository[T]) write(ctx context.Context, evt sharedDomain.OutboxEvent, change func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // Se ignora si el Commit() es exitoso
+
+	if err := change(tx); err != nil {
+		if r.table.Conflict != nil && platformDB.IsUniqueViolation(err) {
+			return r.table.Conflict
+		}
+		if errors.Is(err, r.table.NotFound) || errors.Is(err, r.table.VersionConflict) {
+			return err
+		}
+		return fmt.Errorf("db error: %w", err)
+	}
+	if err := r.engine.InsertOutbox(ctx, r.exec, tx, evt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// affectOne ejecuta 'query' y devuelve Table.NotFound si no afecta a ninguna fila.
+func (r *Repository[T]) affectOne(ctx context.Context, tx *sql.Tx, query string, args ...any) error {
+	res, err := r.exec.ExecContext(ctx, tx, query, args...)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return r.table.NotFound
+	}
+	return nil
+}
+
+// ------------------ Lectura ------------------
+
+// GetByID recupera el agregado 'id' del inquilino de ctx.
+func (r *Repository[T]) GetByID(ctx context.Context, id uuid.UUID) (*T, error) {
+	t, err := r.mapper.Scan(r.exec.QueryRowContext(ctx, r.getByIDSQL, r.mapper.Arg(id), tracing.TenantID(ctx)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.table.NotFound
+		}
+		return nil, fmt.Errorf("db error: %w", err)
+	}
+	return t, nil
+}
+
+// GetByIDs recupera en una sola consulta (id IN (...)) los agregados de 'ids' que existen en el
+// inquilino de ctx.
+func (r *Repository[T]) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*T, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var query strings.Builder
+	query.WriteString(r.selectSQL + " WHERE " + sharedQuery.TenantColumn + " = " + r.engine.Placeholder(1) + " AND ")
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, tracing.TenantID(ctx))
+	sharedQuery.WriteInSQL(&query, r.table.Columns[0], ids, r.bind(&args))
+	return r.query(ctx, query.String(), args...)
+}
+
+// ListByCriteria recupera los agregados que cumplen 'criteria' en el inquilino de ctx, ordenados
+// y paginados.
+func (r *Repository[T]) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*T, error) {
+	query, args, err := r.ListQuery(sharedQuery.TenantScope(ctx, criteria), pagination, sorts)
+	if err != nil {
+		return nil, err
+	}
+	return r.query(ctx, query, args...)
+}
+
+// CountByCriteria cuenta los agregados que cumplen 'criteria', con el mismo WHERE que ListByCriteria.
+func (r *Repository[T]) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	whereSQL, args, err := r.where(sharedQuery.TenantScope(ctx, criteria))
+	if err != nil {
+		return 0, err
+	}
+	query := "SELECT COUNT(*) FROM " + r.table.Name
+	if whereSQL != "" {
+		query += " WHERE " + whereSQL
+	}
+	var total int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// ListQuery construye el SELECT de ListByCriteria, sin añadir el inquilino. Los campos y
+// operadores se validan contra Table.Fields antes de interpolarlos, y los valores van siempre
+// como parámetros.
+func (r *Repository[T]) ListQuery(criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) (string, []any, error) {
+	if err := r.table.Fields.CheckSorts(sorts); err != nil {
+		return "", nil, err
+	}
+	whereSQL, args, err := r.where(criteria)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var query strings.Builder
+	query.Grow(sharedQuery.QueryCapacity)
+	query.WriteString(r.selectSQL)
+	// Tras el WHERE de los filtros, la condición del cursor se une con AND.
+	where := " WHERE "
+	if whereSQL != "" {
+		query.WriteString(where)
+		query.WriteString(whereSQL)
+		where = " AND "
+	}
+
+	// Los campos de ordenación se desempatan por id para que el orden sea total.
+	order := sharedQuery.MarkNullable(sharedQuery.OrderBy(sorts), r.table.Nullable)
+
+	// --- Paginación según tipo ---
+	switch p := pagination.(type) {
+	case sharedQuery.OffsetPagination:
+		if len(order) > 0 {
+			query.WriteString(" ORDER BY ")
+			sharedQuery.WriteOrderBySQL(&query, order)
+		}
+		args = append(args, p.Limit, p.Offset)
+		query.WriteString(" LIMIT ")
+		query.WriteString(r.engine.Placeholder(len(args) - 1))
+		query.WriteString(" OFFSET ")
+		query.WriteString(r.engine.Placeholder(len(args)))
+	case sharedQuery.CursorPagination:
+		if len(order) == 0 {
+			return "", nil, fmt.Errorf("%w: cursor pagination needs a sort field", sharedQuery.ErrInvalidQuery)
+		}
+		if p.Cursor != "" {
+			cursor, err := sharedQuery.DecodeCursor(p.Cursor)
+			if err != nil {
+				return "", nil, err
+			}
+			branches, err := sharedQuery.Keyset(order, cursor)
+			if err != nil {
+				return "", nil, err
+			}
+			if m, ok := r.mapper.(CursorMapper); ok {
+				if err := m.CursorValues(branches); err != nil {
+					return "", nil, err
+				}
+			}
+
+			// La página siguiente son los registros que van detrás del cursor en 'order'.
+			query.WriteString(where)
+			sharedQuery.WriteKeysetSQL(&query, branches, r.bind(&args))
+		}
+		query.WriteString(" ORDER BY ")
+		sharedQuery.WriteOrderBySQL(&query, order)
+		query.WriteString(" LIMIT ")
+		query.WriteString(strconv.Itoa(p.Limit))
+	}
+	return query.String(), args, nil
+}
+
+// where traduce 'criteria' a SQL, con los AND y OR anidados de los CompositeCriteria (ver
+// sharedQuery.WhereSQL).
+func (r *Repository[T]) where(criteria sharedDomain.Criteria) (string, []any, error) {
+	args := make([]any, 0, sharedQuery.ArgsCapacity)
+	where, err := sharedQuery.WhereSQL(criteria, r.engine.Dialect, r.table.Fields.CheckCriterion, r.bind(&args))
+	if err != nil {
+		return "", nil, err
+	}
+	return where, args, nil
+}
+
+// bind devuelve la función que añade un valor a 'args', convertido con Mapper.Arg, y escribe su
+// parámetro.
+func (r *Repository[T]) bind(args *[]any) func(value any) string {
+	return func(value any) string {
+		*args = append(*args, r.mapper.Arg(value))
+		return r.engine.Placeholder(len(*args))
+	}
+}
+
+// query ejecuta un SELECT de Table.Columns y escanea sus filas.
+func (r *Repository[T]) query(ctx context.Context, query string, args ...any) ([]*T, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*T
+	for rows.Next() {
+		t, err := r.mapper.Scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, t)
+	}
+	return items, rows.Err()
+}
+
+// Preflight comprueba que la conexión es del motor del repositorio y que la tabla tiene las
+// columnas que lee.
+func (r *Repository[T]) Preflight(ctx context.Context) error {
+	return preflight.SQL(ctx, r.db, r.engine.Backend,
+		"SELECT "+r.columns+", "+sharedQuery.TenantColumn+" FROM "+r.table.Name+" LIMIT 0")
+}
+
+// Verificación estática
+var _ preflight.Checker = (*Repository[struct{}])(nil)
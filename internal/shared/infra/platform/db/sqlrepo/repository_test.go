@@ -0,0 +1,139 @@
+package sqlrepo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedSQLite "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlite"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// note es un agregado mínimo: todo lo que necesita para tener un repositorio SQL es noteMapper.
+type note struct {
+	ID    uuid.UUID
+	Text  string
+	Order int
+}
+
+var (
+	errNoteNotFound = errors.New("note not found")
+	errNoteConflict = errors.New("note already exists")
+)
+
+type noteMapper struct{}
+
+func (noteMapper) Table() sqlrepo.Table {
+	return sqlrepo.Table{
+		Name:     "notes",
+		Columns:  []string{"id", "text", "position"},
+		Fields:   sharedQuery.NewFields("id", "text", "position", sharedQuery.TenantColumn),
+		NotFound: errNoteNotFound,
+		Conflict: errNoteConflict,
+	}
+}
+
+func (noteMapper) Values(n *note) []any { return []any{n.ID.String(), n.Text, n.Order} }
+
+func (noteMapper) Scan(row sqlrepo.Scanner) (*note, error) {
+	var n note
+	if err := row.Scan(&n.ID, &n.Text, &n.Order); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (noteMapper) Arg(value any) any {
+	if id, ok := value.(uuid.UUID); ok {
+		return id.String()
+	}
+	return value
+}
+
+func newNoteRepo(t *testing.T) (*sqlrepo.Repository[note], *sql.DB) {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "notes.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`
+        CREATE TABLE notes (id TEXT PRIMARY KEY, text TEXT NOT NULL, position INTEGER NOT NULL, tenant_id TEXT NOT NULL DEFAULT '');
+        CREATE TABLE outbox (
+            id TEXT PRIMARY KEY, aggregate_type TEXT NOT NULL, aggregate_id TEXT NOT NULL, event_type TEXT NOT NULL,
+            payload TEXT NOT NULL, created_at DATETIME NOT NULL, processed BOOLEAN NOT NULL DEFAULT 0
+        )`)
+	require.NoError(t, err)
+	require.NoError(t, sharedSQLite.MigrateOutboxSchema(db))
+	return sqlrepo.New(db, sharedSQLite.Engine, noteMapper{}), db
+}
+
+func noteEvent(n *note) sharedDomain.OutboxEvent {
+	return sharedDomain.OutboxEvent{ID: uuid.New(), AggregateType: "note", AggregateID: n.ID.String(), EventType: "note.changed", Payload: n}
+}
+
+func TestRepository_CRUDWithOutbox(t *testing.T) {
+	// Arrange
+	repo, db := newNoteRepo(t)
+	ctx := tracing.NewContext(context.Background(), tracing.Context{TenantID: "acme"})
+	n := &note{ID: uuid.New(), Text: "first", Order: 1}
+
+	// Act
+	require.NoError(t, repo.Create(ctx, n, noteEvent(n)))
+	duplicateErr := repo.Create(ctx, n, noteEvent(n))
+	n.Text = "edited"
+	require.NoError(t, repo.Update(ctx, n, noteEvent(n)))
+	got, err := repo.GetByID(ctx, n.ID)
+	_, otherTenantErr := repo.GetByID(context.Background(), n.ID)
+	require.NoError(t, repo.DeleteByID(ctx, n.ID, noteEvent(n)))
+	deleteAgainErr := repo.DeleteByID(ctx, n.ID, noteEvent(n))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, n, got)
+	assert.ErrorIs(t, duplicateErr, errNoteConflict)
+	assert.ErrorIs(t, otherTenantErr, errNoteNotFound)
+	assert.ErrorIs(t, deleteAgainErr, errNoteNotFound)
+	var events int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM outbox WHERE aggregate_id = ? AND tenant_id = 'acme'`, n.ID.String()).Scan(&events))
+	assert.Equal(t, 3, events, "create, update and delete, each with its own event")
+}
+
+func TestRepository_ListAndCount(t *testing.T) {
+	// Arrange
+	repo, _ := newNoteRepo(t)
+	ctx := context.Background()
+	for i, text := range []string{"alpha", "beta", "gamma"} {
+		n := &note{ID: uuid.New(), Text: text, Order: i}
+		require.NoError(t, repo.Create(ctx, n, noteEvent(n)))
+	}
+	criteria := mocks.Criteria{{Field: "position", Op: sharedDomain.OpGte, Value: 1}}
+	sorts := []sharedQuery.Sort{{Field: "position", Desc: true}}
+
+	// Act
+	first, err := repo.ListByCriteria(ctx, criteria, sharedQuery.CursorPagination{Limit: 1}, sorts)
+	require.NoError(t, err)
+	cursor := sharedQuery.NewCursor(first[0].ID, first[0].Order).Encode()
+	second, err := repo.ListByCriteria(ctx, criteria, sharedQuery.CursorPagination{Limit: 1, Cursor: cursor}, sorts)
+	require.NoError(t, err)
+	total, err := repo.CountByCriteria(ctx, criteria)
+	require.NoError(t, err)
+	_, unknownErr := repo.ListByCriteria(ctx, mocks.Criteria{{Field: "secret", Op: sharedDomain.OpEq, Value: 1}}, sharedQuery.OffsetPagination{Limit: 1}, nil)
+
+	// Assert
+	require.Len(t, first, 1)
+	require.Len(t, second, 1)
+	assert.Equal(t, "gamma", first[0].Text)
+	assert.Equal(t, "beta", second[0].Text)
+	assert.Equal(t, 2, total)
+	assert.ErrorIs(t, unknownErr, sharedQuery.ErrInvalidQuery)
+}
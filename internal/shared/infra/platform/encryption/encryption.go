@@ -0,0 +1,197 @@
+// Package encryption cifra campos sueltos con datos personales (email, fecha de nacimiento...)
+// antes de guardarlos, con AES-256-GCM y un anillo de claves que permite rotarlas.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Prefix marca los valores cifrados: "enc:v1:<id de clave>:<nonce y texto cifrado en base64>".
+// Un valor sin él es un texto en claro, guardado antes de activar el cifrado.
+const Prefix = "enc:v1:"
+
+var (
+	// ErrInvalidKey indica una clave mal formada en la configuración.
+	ErrInvalidKey = errors.New("invalid encryption key")
+	// ErrUnknownKey indica un valor cifrado con una clave que ya no está en el anillo.
+	ErrUnknownKey = errors.New("unknown encryption key")
+	// ErrMalformed indica un valor con el prefijo de cifrado que no se puede descifrar.
+	ErrMalformed = errors.New("malformed encrypted value")
+)
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Keyring guarda las claves de cifrado por id. La primera es la activa, con la que se cifra; el
+// resto solo descifra los valores escritos antes de rotar. Cifrar es determinista: el nonce
+// sale de un HMAC del texto, así que un mismo valor con la misma clave da siempre el mismo texto
+// cifrado. Eso permite los índices únicos y las búsquedas por igualdad sobre columnas cifradas, a
+// cambio de revelar qué filas comparten valor.
+type Keyring struct {
+	active string
+	ids    []string
+	keys   map[string]keyEntry
+}
+
+type keyEntry struct {
+	aead     cipher.AEAD
+	nonceKey []byte
+}
+
+// NewKeyring construye el anillo a partir de entradas "<id>:<clave de 32 bytes en base64>". El
+// id (letras, dígitos, '-' o '_') viaja en cada valor cifrado para saber con qué clave abrirlo.
+func NewKeyring(specs []string) (*Keyring, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("%w: at least one key is required", ErrInvalidKey)
+	}
+	k := &Keyring{keys: make(map[string]keyEntry, len(specs))}
+	for _, spec := range specs {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(spec), ":")
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("%w: entries must be <id>:<base64 key> with an id of letters, digits, '-' or '_'", ErrInvalidKey)
+		}
+		if _, dup := k.keys[id]; dup {
+			return nil, fmt.Errorf("%w: duplicated key id %q", ErrInvalidKey, id)
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("%w: key %q must be 32 bytes encoded in base64", ErrInvalidKey, id)
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %w", ErrInvalidKey, id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %w", ErrInvalidKey, id, err)
+		}
+		// El nonce se deriva con una subclave propia, no con la clave de cifrado.
+		mac := hmac.New(sha256.New, raw)
+		mac.Write([]byte("hexagolab/encryption/nonce"))
+		k.keys[id] = keyEntry{aead: aead, nonceKey: mac.Sum(nil)}
+		k.ids = append(k.ids, id)
+	}
+	k.active = k.ids[0]
+	return k, nil
+}
+
+// Encrypt cifra 'plaintext' con la clave activa.
+func (k *Keyring) Encrypt(plaintext string) string {
+	return k.encryptWith(k.active, plaintext)
+}
+
+// EncryptAll cifra 'plaintext' con cada clave del anillo, la activa primero: los valores con los
+// que puede estar guardado mientras dura una rotación, para buscarlo por igualdad.
+func (k *Keyring) EncryptAll(plaintext string) []string {
+	values := make([]string, len(k.ids))
+	for i, id := range k.ids {
+		values[i] = k.encryptWith(id, plaintext)
+	}
+	return values
+}
+
+// Rotating indica si hay claves antiguas en el anillo además de la activa.
+func (k *Keyring) Rotating() bool {
+	return len(k.ids) > 1
+}
+
+func (k *Keyring) encryptWith(id, plaintext string) string {
+	entry := k.keys[id]
+	mac := hmac.New(sha256.New, entry.nonceKey)
+	mac.Write([]byte(plaintext))
+	nonce := mac.Sum(nil)[:entry.aead.NonceSize()]
+	// El id va como dato autenticado: un valor no se puede hacer pasar por cifrado con otra clave.
+	sealed := entry.aead.Seal(nonce, nonce, []byte(plaintext), []byte(id))
+	return Prefix + id + ":" + base64.RawURLEncoding.EncodeToString(sealed)
+}
+
+// Decrypt devuelve el texto en claro de 'value', con la clave con la que se cifró. Un valor sin
+// Prefix se devuelve tal cual: es un dato anterior al cifrado.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, Prefix)
+	if !ok {
+		return value, nil
+	}
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	entry, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < entry.aead.NonceSize() {
+		return "", ErrMalformed
+	}
+	nonce, ciphertext := sealed[:entry.aead.NonceSize()], sealed[entry.aead.NonceSize():]
+	plaintext, err := entry.aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted indica si 'value' es un valor cifrado (lleva Prefix).
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// EncryptJSON cifra los campos de primer nivel 'fields' del objeto JSON 'data' que sean
+// strings; los que falten, los que no sean strings y los ya cifrados se dejan como están.
+func (k *Keyring) EncryptJSON(data []byte, fields ...string) ([]byte, error) {
+	return k.mapJSON(data, func(name, value string) (string, error) {
+		for _, field := range fields {
+			if field == name && !IsEncrypted(value) {
+				return k.Encrypt(value), nil
+			}
+		}
+		return value, nil
+	})
+}
+
+// DecryptJSON descifra todos los campos de primer nivel del objeto JSON 'data' que sean valores
+// cifrados. Un documento que no es un objeto se devuelve tal cual.
+func (k *Keyring) DecryptJSON(data []byte) ([]byte, error) {
+	return k.mapJSON(data, func(_, value string) (string, error) {
+		return k.Decrypt(value)
+	})
+}
+
+// mapJSON aplica 'fn' a los campos string de primer nivel del objeto 'data'.
+func (k *Keyring) mapJSON(data []byte, fn func(name, value string) (string, error)) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil || object == nil {
+		return data, nil
+	}
+	changed := false
+	for name, raw := range object {
+		var value string
+		if json.Unmarshal(raw, &value) != nil {
+			continue
+		}
+		mapped, err := fn(name, value)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", name, err)
+		}
+		if mapped == value {
+			continue
+		}
+		if object[name], err = json.Marshal(mapped); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(object)
+}
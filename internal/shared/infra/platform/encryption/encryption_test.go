@@ -0,0 +1,111 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKey devuelve una entrada de clave "<id>:<base64>" con 32 bytes iguales a 'b'.
+func testKey(id string, b byte) string {
+	return id + ":" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	// Arrange
+	keys, err := NewKeyring([]string{testKey("k1", 'a')})
+	require.NoError(t, err)
+
+	// Act
+	sealed := keys.Encrypt("ana@example.com")
+	opened, err := keys.Decrypt(sealed)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", opened)
+	assert.True(t, strings.HasPrefix(sealed, Prefix+"k1:"))
+	assert.NotContains(t, sealed, "ana")
+	assert.Equal(t, sealed, keys.Encrypt("ana@example.com"), "Es determinista para los índices y las búsquedas")
+	assert.NotEqual(t, sealed, keys.Encrypt("bea@example.com"))
+
+	plain, err := keys.Decrypt("legacy@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy@example.com", plain, "Los valores anteriores al cifrado se leen tal cual")
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	// Arrange: k1 cifró los datos antiguos; tras rotar, k2 es la activa.
+	old, err := NewKeyring([]string{testKey("k1", 'a')})
+	require.NoError(t, err)
+	rotated, err := NewKeyring([]string{testKey("k2", 'b'), testKey("k1", 'a')})
+	require.NoError(t, err)
+	stored := old.Encrypt("ana@example.com")
+
+	// Act
+	opened, err := rotated.Decrypt(stored)
+	all := rotated.EncryptAll("ana@example.com")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", opened)
+	assert.True(t, rotated.Rotating())
+	assert.True(t, strings.HasPrefix(rotated.Encrypt("ana@example.com"), Prefix+"k2:"))
+	assert.Equal(t, []string{rotated.Encrypt("ana@example.com"), stored}, all)
+
+	_, err = old.Decrypt(rotated.Encrypt("ana@example.com"))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyring_Decrypt_Tampered(t *testing.T) {
+	keys, err := NewKeyring([]string{testKey("k1", 'a'), testKey("k2", 'b')})
+	require.NoError(t, err)
+	sealed := keys.Encrypt("ana@example.com")
+
+	// Con el id de otra clave del anillo no abre: el id va autenticado.
+	_, err = keys.Decrypt(strings.Replace(sealed, Prefix+"k1:", Prefix+"k2:", 1))
+	assert.ErrorIs(t, err, ErrMalformed)
+	_, err = keys.Decrypt(Prefix + "k1")
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	for name, specs := range map[string][]string{
+		"empty":     nil,
+		"no id":     {base64.StdEncoding.EncodeToString(make([]byte, 32))},
+		"short key": {"k1:" + base64.StdEncoding.EncodeToString(make([]byte, 16))},
+		"not b64":   {"k1:***"},
+		"duplicate": {testKey("k1", 'a'), testKey("k1", 'b')},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewKeyring(specs)
+			assert.ErrorIs(t, err, ErrInvalidKey)
+		})
+	}
+}
+
+func TestKeyring_JSON(t *testing.T) {
+	// Arrange
+	keys, err := NewKeyring([]string{testKey("k1", 'a')})
+	require.NoError(t, err)
+	payload := []byte(`{"id":"u-1","email":"ana@example.com","birth_date":"1990-05-01T00:00:00Z","age":34}`)
+
+	// Act
+	sealed, err := keys.EncryptJSON(payload, "email", "birth_date", "age")
+	require.NoError(t, err)
+	opened, err := keys.DecryptJSON(sealed)
+	require.NoError(t, err)
+
+	// Assert: solo los campos string pedidos se cifran, y se recupera el documento.
+	assert.NotContains(t, string(sealed), "ana@example.com")
+	assert.NotContains(t, string(sealed), "1990-05-01")
+	assert.Contains(t, string(sealed), `"id":"u-1"`)
+	assert.Contains(t, string(sealed), `"age":34`)
+	assert.JSONEq(t, string(payload), string(opened))
+
+	notObject, err := keys.DecryptJSON([]byte(`"texto"`))
+	require.NoError(t, err)
+	assert.Equal(t, `"texto"`, string(notObject))
+}
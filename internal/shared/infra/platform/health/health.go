@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Estados de un componente y del servicio.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// defaultTimeout acota cada comprobación para que una dependencia colgada no bloquee /readyz.
+const defaultTimeout = 2 * time.Second
+
+// CheckFunc comprueba que una dependencia responde. Debe respetar la cancelación de ctx.
+type CheckFunc func(ctx context.Context) error
+
+// ComponentStatus es el resultado de comprobar una dependencia. LastError se conserva aunque
+// la dependencia se haya recuperado, para saber qué falló y cuándo.
+type ComponentStatus struct {
+	Status      string     `json:"status"`
+	LatencyMS   float64    `json:"latency_ms"`
+	CheckedAt   time.Time  `json:"checked_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Report agrupa el estado de todas las dependencias registradas.
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Ready indica si todas las dependencias están disponibles.
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+type component struct {
+	check       CheckFunc
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// Registry guarda las comprobaciones que registra cada adaptador (bases de datos, caché, bus...).
+type Registry struct {
+	timeout time.Duration
+
+	mu         sync.Mutex
+	components map[string]*component
+}
+
+// NewRegistry es el constructor. Con timeout <= 0 se usa el valor por defecto (2s).
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Registry{timeout: timeout, components: make(map[string]*component)}
+}
+
+// Register añade (o sustituye) la comprobación del componente 'name'.
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.components[name] = &component{check: check}
+}
+
+// Names devuelve los componentes registrados, ordenados.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.components))
+	for name := range r.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check ejecuta en paralelo todas las comprobaciones y devuelve el estado de cada componente.
+// El servicio está listo solo si todos están disponibles.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.Lock()
+	checks := make(map[string]CheckFunc, len(r.components))
+	for name, c := range r.components {
+		checks[name] = c.check
+	}
+	r.mu.Unlock()
+
+	type result struct {
+		name    string
+		err     error
+		start   time.Time
+		elapsed time.Duration
+	}
+	results := make(chan result, len(checks))
+	for name, check := range checks {
+		go func(name string, check CheckFunc) {
+			checkCtx, cancel := context.WithTimeout(ctx, r.timeout)
+			defer cancel()
+			start := time.Now()
+			err := check(checkCtx)
+			results <- result{name: name, err: err, start: start, elapsed: time.Since(start)}
+		}(name, check)
+	}
+
+	report := Report{Status: StatusReady, Components: make(map[string]ComponentStatus, len(checks))}
+	for range checks {
+		res := <-results
+		status := ComponentStatus{
+			Status:    StatusUp,
+			LatencyMS: float64(res.elapsed.Microseconds()) / 1000,
+			CheckedAt: res.start.UTC(),
+		}
+		if res.err != nil {
+			status.Status = StatusDown
+			report.Status = StatusNotReady
+		}
+		status.LastError, status.LastErrorAt = r.recordError(res.name, res.err, res.start)
+		report.Components[res.name] = status
+	}
+	return report
+}
+
+// recordError guarda el último error del componente y lo devuelve junto con su fecha.
+func (r *Registry) recordError(name string, err error, at time.Time) (string, *time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.components[name]
+	if !ok {
+		return "", nil
+	}
+	if err != nil {
+		c.lastError, c.lastErrorAt = err.Error(), at.UTC()
+	}
+	if c.lastError == "" {
+		return "", nil
+	}
+	lastErrorAt := c.lastErrorAt
+	return c.lastError, &lastErrorAt
+}
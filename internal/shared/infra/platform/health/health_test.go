@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	// Arrange
+	registry := NewRegistry(0)
+	registry.Register("sqlite", func(ctx context.Context) error { return nil })
+	registry.Register("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	// Act
+	report := registry.Check(context.Background())
+
+	// Assert
+	assert.False(t, report.Ready())
+	assert.Equal(t, StatusNotReady, report.Status)
+	require.Len(t, report.Components, 2)
+	assert.Equal(t, StatusUp, report.Components["sqlite"].Status)
+	assert.Empty(t, report.Components["sqlite"].LastError)
+	assert.Equal(t, StatusDown, report.Components["redis"].Status)
+	assert.Equal(t, "connection refused", report.Components["redis"].LastError)
+	assert.NotNil(t, report.Components["redis"].LastErrorAt)
+	assert.Equal(t, []string{"redis", "sqlite"}, registry.Names())
+}
+
+func TestRegistry_KeepsLastErrorAfterRecovery(t *testing.T) {
+	// Arrange
+	var fail = true
+	registry := NewRegistry(0)
+	registry.Register("kafka", func(ctx context.Context) error {
+		if fail {
+			return errors.New("broker not available")
+		}
+		return nil
+	})
+	registry.Check(context.Background())
+	fail = false
+
+	// Act
+	report := registry.Check(context.Background())
+
+	// Assert
+	assert.True(t, report.Ready())
+	assert.Equal(t, StatusUp, report.Components["kafka"].Status)
+	assert.Equal(t, "broker not available", report.Components["kafka"].LastError)
+}
+
+func TestRegistry_TimesOutHangingChecks(t *testing.T) {
+	// Arrange
+	registry := NewRegistry(20 * time.Millisecond)
+	registry.Register("clickhouse", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	// Act
+	report := registry.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, StatusDown, report.Components["clickhouse"].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Components["clickhouse"].LastError)
+	assert.GreaterOrEqual(t, report.Components["clickhouse"].LatencyMS, float64(20))
+}
+
+func TestStartup(t *testing.T) {
+	// Arrange
+	startup := NewStartup()
+	relayerDone := startup.Add("first poll of the user outbox")
+	httpDone := startup.Add("http server")
+
+	// Act + Assert: mientras quede un paso pendiente, la comprobación falla y Wait bloquea.
+	httpDone()
+	assert.EqualError(t, startup.Check(context.Background()), "waiting for: first poll of the user outbox")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, startup.Wait(ctx), context.DeadlineExceeded)
+
+	relayerDone()
+	relayerDone() // Completar dos veces el mismo paso no tiene efecto
+	assert.NoError(t, startup.Check(context.Background()))
+	assert.NoError(t, startup.Wait(context.Background()))
+	assert.Empty(t, startup.Pending())
+}
+
+func TestStartup_NoSteps(t *testing.T) {
+	assert.NoError(t, NewStartup().Wait(context.Background()), "Sin pasos, el arranque ya está completo")
+}
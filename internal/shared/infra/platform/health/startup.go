@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Startup sigue los pasos de arranque que deben completarse antes de declarar el servicio listo
+// y que no son una dependencia comprobable en cualquier momento (p.ej. el primer polling del
+// outbox). Registrado como comprobación (ver Check), /readyz responde 503 hasta que terminan.
+type Startup struct {
+	mu      sync.Mutex
+	pending map[string]bool
+	done    chan struct{}
+}
+
+// NewStartup es el constructor.
+func NewStartup() *Startup {
+	return &Startup{pending: make(map[string]bool), done: make(chan struct{})}
+}
+
+// Add registra un paso pendiente y devuelve la función que lo da por completado. Los pasos se
+// añaden al construir los componentes, antes de arrancarlos. Llamar varias veces a la función
+// devuelta no tiene efecto.
+func (s *Startup) Add(name string) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 {
+		select {
+		case <-s.done: // Ya se había completado todo; hay que volver a esperar.
+			s.done = make(chan struct{})
+		default:
+		}
+	}
+	s.pending[name] = true
+
+	var once sync.Once
+	return func() { once.Do(func() { s.complete(name) }) }
+}
+
+func (s *Startup) complete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.pending, name)
+	if len(s.pending) == 0 {
+		close(s.done)
+	}
+}
+
+// Pending devuelve los pasos aún no completados, ordenados.
+func (s *Startup) Pending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.pending))
+	for name := range s.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Wait espera a que se completen todos los pasos o a que se cancele ctx.
+func (s *Startup) Wait(ctx context.Context) error {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	done := s.done
+	s.mu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Check es una CheckFunc que falla mientras quede algún paso pendiente.
+func (s *Startup) Check(ctx context.Context) error {
+	if pending := s.Pending(); len(pending) > 0 {
+		return fmt.Errorf("waiting for: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const namespace = "hexagolab"
+
+// Resultados usados como etiqueta 'outcome'/'result'.
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+
+	DirectionPublished = "published"
+	DirectionConsumed  = "consumed"
+
+	TaskDone     = "done"
+	TaskDropped  = "dropped"
+	TaskPanicked = "panicked"
+)
+
+// Los colectores se registran en el registro por defecto de Prometheus, que además expone
+// las métricas del runtime de Go y del proceso. Solo se alimentan desde middlewares y decoradores
+// de infraestructura; el código de negocio no los conoce.
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of repository calls by repository, backend and method.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repo", "backend", "method", "outcome"})
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Cache lookups by result (hit, miss, error).",
+	}, []string{"cache", "result"})
+
+	busMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "bus",
+		Name:      "messages_total",
+		Help:      "Messages published to or consumed from the event bus.",
+	}, []string{"bus", "topic", "direction", "outcome"})
+
+	busHandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "bus",
+		Name:      "handler_duration_seconds",
+		Help:      "Time spent by consumers processing a message.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"bus", "topic"})
+
+	backgroundTasks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "background",
+		Name:      "tasks_total",
+		Help:      "Background tasks by result (done, dropped, panicked).",
+	}, []string{"pool", "result"})
+)
+
+// Handler devuelve el handler HTTP que expone las métricas en formato Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveHTTPRequest registra la duración de una petición HTTP. 'route' debe ser la plantilla
+// de la ruta (/users/:id), no la URL real, para no disparar la cardinalidad.
+func ObserveHTTPRequest(method, route, status string, elapsed time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(elapsed.Seconds())
+}
+
+// ObserveDBQuery registra la duración de una llamada a un repositorio iniciada en 'start'.
+func ObserveDBQuery(repo, backend, method string, start time.Time, err error) {
+	dbQueryDuration.WithLabelValues(repo, backend, method, outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveCacheLookup cuenta una lectura de caché como hit, miss o error.
+func ObserveCacheLookup(cache string, hit bool, err error) {
+	result := CacheMiss
+	switch {
+	case err != nil:
+		result = OutcomeError
+	case hit:
+		result = CacheHit
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// CacheStats son las lecturas de una caché desde que arrancó el proceso. HitRatio es la
+// proporción de aciertos sobre el total (0 sin lecturas).
+type CacheStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	Errors   int64   `json:"errors"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// CacheLookups resume, por caché, las lecturas contadas con ObserveCacheLookup.
+func CacheLookups() map[string]CacheStats {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		cacheRequests.Collect(ch)
+		close(ch)
+	}()
+
+	stats := map[string]CacheStats{}
+	for m := range ch {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			continue
+		}
+		var cache, result string
+		for _, label := range pb.GetLabel() {
+			switch label.GetName() {
+			case "cache":
+				cache = label.GetValue()
+			case "result":
+				result = label.GetValue()
+			}
+		}
+		s, n := stats[cache], int64(pb.GetCounter().GetValue())
+		switch result {
+		case CacheHit:
+			s.Hits += n
+		case CacheMiss:
+			s.Misses += n
+		default:
+			s.Errors += n
+		}
+		stats[cache] = s
+	}
+	for name, s := range stats {
+		if total := s.Hits + s.Misses + s.Errors; total > 0 {
+			s.HitRatio = float64(s.Hits) / float64(total)
+		}
+		stats[name] = s
+	}
+	return stats
+}
+
+// ObserveBusMessage cuenta un mensaje publicado o consumido en el bus 'bus' (kafka, memory).
+func ObserveBusMessage(bus, topic, direction string, err error) {
+	busMessages.WithLabelValues(bus, topic, direction, outcome(err)).Inc()
+}
+
+// ObserveBusHandler registra lo que tarda un consumidor en procesar un mensaje iniciado en 'start'.
+func ObserveBusHandler(bus, topic string, start time.Time) {
+	busHandlerDuration.WithLabelValues(bus, topic).Observe(time.Since(start).Seconds())
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeOK
+}
+
+// ---------------- Consumidores ----------------
+
+// consumerLags son las funciones de lag registradas, para ConsumerLags.
+var consumerLags = struct {
+	sync.Mutex
+	funcs map[[2]string]func() int64
+}{funcs: map[[2]string]func() int64{}}
+
+// RegisterConsumerLag expone el lag (mensajes pendientes de leer) del consumidor de 'topic'
+// en el grupo 'group'. 'lag' se consulta en cada scrape.
+func RegisterConsumerLag(topic, group string, lag func() int64) error {
+	err := prometheus.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Subsystem:   "bus",
+		Name:        "consumer_lag",
+		Help:        "Messages not yet read by the consumer group.",
+		ConstLabels: prometheus.Labels{"topic": topic, "group": group},
+	}, func() float64 { return float64(lag()) }))
+	if err != nil {
+		return err
+	}
+	consumerLags.Lock()
+	defer consumerLags.Unlock()
+	consumerLags.funcs[[2]string{topic, group}] = lag
+	return nil
+}
+
+// ConsumerLag es el lag de un consumidor en un momento dado.
+type ConsumerLag struct {
+	Topic string `json:"topic"`
+	Group string `json:"group"`
+	Lag   int64  `json:"lag"`
+}
+
+// ConsumerLags devuelve el lag actual de los consumidores registrados con RegisterConsumerLag,
+// ordenados por topic y grupo.
+func ConsumerLags() []ConsumerLag {
+	consumerLags.Lock()
+	defer consumerLags.Unlock()
+	lags := make([]ConsumerLag, 0, len(consumerLags.funcs))
+	for key, lag := range consumerLags.funcs {
+		lags = append(lags, ConsumerLag{Topic: key[0], Group: key[1], Lag: lag()})
+	}
+	sort.Slice(lags, func(i, j int) bool {
+		if lags[i].Topic != lags[j].Topic {
+			return lags[i].Topic < lags[j].Topic
+		}
+		return lags[i].Group < lags[j].Group
+	})
+	return lags
+}
+
+// RegisterQueueDepth expone los mensajes encolados y aún no consumidos del bus 'bus' para
+// 'topic'. 'depth' se consulta en cada scrape.
+func RegisterQueueDepth(bus, topic string, depth func() int) error {
+	return prometheus.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Subsystem:   "bus",
+		Name:        "queue_depth",
+		Help:        "Messages queued in the bus waiting for a consumer.",
+		ConstLabels: prometheus.Labels{"bus": bus, "topic": topic},
+	}, func() float64 { return float64(depth()) }))
+}
+
+// ---------------- Tareas en segundo plano ----------------
+
+// ObserveBackgroundTask cuenta una tarea del pool 'pool' ejecutada, descartada o que entró en pánico.
+func ObserveBackgroundTask(pool, result string) {
+	backgroundTasks.WithLabelValues(pool, result).Inc()
+}
+
+// RegisterBackgroundQueue expone las tareas encoladas del pool 'pool' a la espera de una
+// goroutine. 'depth' se consulta en cada scrape.
+func RegisterBackgroundQueue(pool string, depth func() int) error {
+	return prometheus.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Subsystem:   "background",
+		Name:        "queue_depth",
+		Help:        "Background tasks queued waiting for a worker.",
+		ConstLabels: prometheus.Labels{"pool": pool},
+	}, func() float64 { return float64(depth()) }))
+}
+
+// ---------------- Outbox ----------------
+
+// BacklogFunc cuenta los eventos pendientes de un outbox.
+type BacklogFunc func(ctx context.Context) (int, error)
+
+var outboxBacklogDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "outbox", "backlog"),
+	"Outbox events pending to be published.",
+	[]string{"outbox"}, nil,
+)
+
+// backlogCollector consulta el outbox en cada scrape, así el gauge nunca queda desfasado
+// respecto a la tabla aunque el relayer esté parado.
+type backlogCollector struct {
+	name  string
+	count BacklogFunc
+}
+
+func (c backlogCollector) Describe(ch chan<- *prometheus.Desc) { ch <- outboxBacklogDesc }
+
+func (c backlogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	n, err := c.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(outboxBacklogDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(outboxBacklogDesc, prometheus.GaugeValue, float64(n), c.name)
+}
+
+// RegisterOutboxBacklog expone el número de eventos pendientes del outbox 'name'.
+func RegisterOutboxBacklog(name string, count BacklogFunc) error {
+	return prometheus.Register(backlogCollector{name: name, count: count})
+}
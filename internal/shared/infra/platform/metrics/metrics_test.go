@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveCacheLookup(t *testing.T) {
+	// Arrange
+	hits := testutil.ToFloat64(cacheRequests.WithLabelValues("test", CacheHit))
+	misses := testutil.ToFloat64(cacheRequests.WithLabelValues("test", CacheMiss))
+	errs := testutil.ToFloat64(cacheRequests.WithLabelValues("test", OutcomeError))
+
+	// Act
+	ObserveCacheLookup("test", true, nil)
+	ObserveCacheLookup("test", false, nil)
+	ObserveCacheLookup("test", false, errors.New("boom"))
+
+	// Assert
+	assert.Equal(t, hits+1, testutil.ToFloat64(cacheRequests.WithLabelValues("test", CacheHit)))
+	assert.Equal(t, misses+1, testutil.ToFloat64(cacheRequests.WithLabelValues("test", CacheMiss)))
+	assert.Equal(t, errs+1, testutil.ToFloat64(cacheRequests.WithLabelValues("test", OutcomeError)))
+}
+
+func TestCacheLookups(t *testing.T) {
+	// Arrange
+	for _, hit := range []bool{true, true, true, false} {
+		ObserveCacheLookup("ratio-test", hit, nil)
+	}
+
+	// Act
+	stats := CacheLookups()
+
+	// Assert
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 1, HitRatio: 0.75}, stats["ratio-test"])
+}
+
+func TestObserveBusMessage(t *testing.T) {
+	// Arrange
+	published := busMessages.WithLabelValues("kafka", "test-topic", DirectionPublished, OutcomeError)
+	before := testutil.ToFloat64(published)
+
+	// Act
+	ObserveBusMessage("kafka", "test-topic", DirectionPublished, errors.New("broker down"))
+
+	// Assert
+	assert.Equal(t, before+1, testutil.ToFloat64(published))
+}
+
+func TestObserveDBQuery(t *testing.T) {
+	// Arrange
+	before := testutil.CollectAndCount(dbQueryDuration)
+
+	// Act
+	ObserveDBQuery("test", "sqlite", "GetByID", time.Now(), nil)
+	ObserveDBQuery("test", "sqlite", "GetByID", time.Now(), errors.New("timeout"))
+
+	// Assert: una serie por resultado (ok, error).
+	assert.Equal(t, before+2, testutil.CollectAndCount(dbQueryDuration))
+}
+
+func TestBacklogCollector(t *testing.T) {
+	t.Run("reports pending events", func(t *testing.T) {
+		// Arrange
+		collector := backlogCollector{name: "user", count: func(ctx context.Context) (int, error) { return 7, nil }}
+
+		// Act & Assert
+		expected := `
+# HELP hexagolab_outbox_backlog Outbox events pending to be published.
+# TYPE hexagolab_outbox_backlog gauge
+hexagolab_outbox_backlog{outbox="user"} 7
+`
+		assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+	})
+
+	t.Run("count error makes the scrape fail", func(t *testing.T) {
+		// Arrange
+		collector := backlogCollector{name: "task", count: func(ctx context.Context) (int, error) { return 0, errors.New("db down") }}
+		reg := prometheus.NewPedanticRegistry()
+		require.NoError(t, reg.Register(collector))
+
+		// Act
+		_, err := reg.Gather()
+
+		// Assert
+		assert.ErrorContains(t, err, "db down")
+	})
+}
+
+func TestObserveBusHandler(t *testing.T) {
+	// Arrange
+	before := testutil.CollectAndCount(busHandlerDuration)
+
+	// Act
+	ObserveBusHandler("memory", "handler-test-topic", time.Now().Add(-30*time.Millisecond))
+
+	// Assert
+	assert.Equal(t, before+1, testutil.CollectAndCount(busHandlerDuration))
+}
+
+func TestRegisterConsumerGauges(t *testing.T) {
+	// Arrange
+	depth := 3
+	require.NoError(t, RegisterConsumerLag("lag-test-topic", "lag-test-group", func() int64 { return 42 }))
+	require.NoError(t, RegisterQueueDepth("memory", "depth-test-topic", func() int { return depth }))
+	depth = 5
+
+	// Act & Assert: los valores se leen en el momento del scrape.
+	expected := `
+# HELP hexagolab_bus_consumer_lag Messages not yet read by the consumer group.
+# TYPE hexagolab_bus_consumer_lag gauge
+hexagolab_bus_consumer_lag{group="lag-test-group",topic="lag-test-topic"} 42
+# HELP hexagolab_bus_queue_depth Messages queued in the bus waiting for a consumer.
+# TYPE hexagolab_bus_queue_depth gauge
+hexagolab_bus_queue_depth{bus="memory",topic="depth-test-topic"} 5
+`
+	assert.NoError(t, testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected),
+		"hexagolab_bus_consumer_lag", "hexagolab_bus_queue_depth"))
+	assert.Error(t, RegisterQueueDepth("memory", "depth-test-topic", func() int { return 0 }), "No se puede registrar dos veces")
+	assert.Contains(t, ConsumerLags(), ConsumerLag{Topic: "lag-test-topic", Group: "lag-test-group", Lag: 42})
+}
+
+func TestPoolCollector(t *testing.T) {
+	// Arrange
+	stats := sql.DBStats{MaxOpenConnections: 25, OpenConnections: 7, InUse: 5, Idle: 2, WaitCount: 3, WaitDuration: 1500 * time.Millisecond}
+	collector := poolCollector{name: "postgres", stats: func() PoolStats { return SQLPoolStats(stats) }}
+
+	// Act & Assert
+	expected := `
+# HELP hexagolab_pool_connections Connections in the pool by state (in_use, idle).
+# TYPE hexagolab_pool_connections gauge
+hexagolab_pool_connections{pool="postgres",state="idle"} 2
+hexagolab_pool_connections{pool="postgres",state="in_use"} 5
+# HELP hexagolab_pool_max_open_connections Maximum open connections allowed by the pool (0 is unlimited).
+# TYPE hexagolab_pool_max_open_connections gauge
+hexagolab_pool_max_open_connections{pool="postgres"} 25
+# HELP hexagolab_pool_timeouts_total Waits for a connection that gave up without one.
+# TYPE hexagolab_pool_timeouts_total counter
+hexagolab_pool_timeouts_total{pool="postgres"} 0
+# HELP hexagolab_pool_wait_seconds_total Total time spent waiting for a free connection.
+# TYPE hexagolab_pool_wait_seconds_total counter
+hexagolab_pool_wait_seconds_total{pool="postgres"} 1.5
+# HELP hexagolab_pool_waits_total Times a caller waited for a free connection.
+# TYPE hexagolab_pool_waits_total counter
+hexagolab_pool_waits_total{pool="postgres"} 3
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
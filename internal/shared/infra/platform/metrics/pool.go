@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolStats es una foto del pool de conexiones de un backend (Postgres, SQLite, MongoDB, Redis).
+// Los campos que un cliente no expone quedan a cero.
+type PoolStats struct {
+	MaxOpen      int           // Límite de conexiones abiertas; 0 es sin límite
+	Open         int           // Conexiones abiertas, en uso o libres
+	InUse        int           // Conexiones prestadas ahora mismo
+	Idle         int           // Conexiones libres en el pool
+	WaitCount    int64         // Veces que se esperó por una conexión (acumulado)
+	WaitDuration time.Duration // Tiempo total esperando conexión (acumulado)
+	Timeouts     int64         // Esperas que terminaron sin conexión (acumulado)
+}
+
+// SQLPoolStats traduce las estadísticas de un *sql.DB.
+func SQLPoolStats(s sql.DBStats) PoolStats {
+	return PoolStats{
+		MaxOpen:      s.MaxOpenConnections,
+		Open:         s.OpenConnections,
+		InUse:        s.InUse,
+		Idle:         s.Idle,
+		WaitCount:    s.WaitCount,
+		WaitDuration: s.WaitDuration,
+	}
+}
+
+var (
+	poolConnectionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "pool", "connections"),
+		"Connections in the pool by state (in_use, idle).",
+		[]string{"pool", "state"}, nil,
+	)
+	poolMaxOpenDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "pool", "max_open_connections"),
+		"Maximum open connections allowed by the pool (0 is unlimited).",
+		[]string{"pool"}, nil,
+	)
+	poolWaitsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "pool", "waits_total"),
+		"Times a caller waited for a free connection.",
+		[]string{"pool"}, nil,
+	)
+	poolWaitSecondsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "pool", "wait_seconds_total"),
+		"Total time spent waiting for a free connection.",
+		[]string{"pool"}, nil,
+	)
+	poolTimeoutsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "pool", "timeouts_total"),
+		"Waits for a connection that gave up without one.",
+		[]string{"pool"}, nil,
+	)
+)
+
+// poolCollector lee las estadísticas del pool en cada scrape: los clientes ya las llevan, así
+// que no hay nada que actualizar entre scrapes.
+type poolCollector struct {
+	name  string
+	stats func() PoolStats
+}
+
+func (c poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolConnectionsDesc
+	ch <- poolMaxOpenDesc
+	ch <- poolWaitsDesc
+	ch <- poolWaitSecondsDesc
+	ch <- poolTimeoutsDesc
+}
+
+func (c poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	ch <- prometheus.MustNewConstMetric(poolConnectionsDesc, prometheus.GaugeValue, float64(s.InUse), c.name, "in_use")
+	ch <- prometheus.MustNewConstMetric(poolConnectionsDesc, prometheus.GaugeValue, float64(s.Idle), c.name, "idle")
+	ch <- prometheus.MustNewConstMetric(poolMaxOpenDesc, prometheus.GaugeValue, float64(s.MaxOpen), c.name)
+	ch <- prometheus.MustNewConstMetric(poolWaitsDesc, prometheus.CounterValue, float64(s.WaitCount), c.name)
+	ch <- prometheus.MustNewConstMetric(poolWaitSecondsDesc, prometheus.CounterValue, s.WaitDuration.Seconds(), c.name)
+	ch <- prometheus.MustNewConstMetric(poolTimeoutsDesc, prometheus.CounterValue, float64(s.Timeouts), c.name)
+}
+
+// RegisterPoolStats expone el pool de conexiones 'name' (el backend: postgres, sqlite, mongo,
+// redis). 'stats' se consulta en cada scrape.
+func RegisterPoolStats(name string, stats func() PoolStats) error {
+	return prometheus.Register(poolCollector{name: name, stats: stats})
+}
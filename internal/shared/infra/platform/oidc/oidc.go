@@ -0,0 +1,287 @@
+// Package oidc valida los tokens de acceso que emite un proveedor de identidad externo (OpenID
+// Connect): descubre sus claves públicas (JWKS), comprueba firma, emisor, audiencia y vigencia y
+// traduce los claims al usuario y los roles internos.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+var (
+	// ErrInvalidToken indica un token mal formado, con firma incorrecta, caducado o emitido para
+	// otro emisor o audiencia.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrUnavailable indica que no se han podido obtener las claves del proveedor de identidad.
+	ErrUnavailable = errors.New("identity provider unavailable")
+)
+
+const (
+	// DefaultKeysTTL es cada cuánto se vuelven a descargar las claves del proveedor.
+	DefaultKeysTTL = time.Hour
+	// minRefresh es el tiempo mínimo entre intentos de descarga: ni un token con un kid inventado
+	// ni un proveedor caído provocan una descarga por petición.
+	minRefresh = 30 * time.Second
+	// leeway es la tolerancia de reloj con exp, nbf e iat.
+	leeway = time.Minute
+)
+
+// signatureAlgorithms son los algoritmos aceptados: solo asimétricos, el proveedor nunca
+// comparte un secreto con la API.
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512, jose.EdDSA,
+}
+
+// Options configura un Verifier.
+type Options struct {
+	// Issuer es la URL del emisor, que debe coincidir con el claim iss. Las claves se descubren
+	// en <Issuer>/.well-known/openid-configuration.
+	Issuer string
+	// Audience es el valor que debe aparecer en el claim aud (el client id de la API).
+	Audience string
+	// UserClaim es el claim con el identificador del usuario ("sub" si está vacío).
+	UserClaim string
+	// RolesClaim es el claim con los roles, con puntos para los anidados (ej.
+	// realm_access.roles). Vacío si el proveedor no envía roles.
+	RolesClaim string
+	// RoleMap traduce los roles del proveedor a los internos; los que no aparecen se descartan.
+	// Vacío, los roles se usan tal cual.
+	RoleMap map[string]string
+	// TenantClaim es el claim con el inquilino del usuario, con puntos para los anidados. Vacío
+	// si el proveedor no asigna inquilinos.
+	TenantClaim string
+	// KeysTTL es cada cuánto se renuevan las claves (DefaultKeysTTL si es 0).
+	KeysTTL time.Duration
+	// HTTPClient descarga el documento de descubrimiento y las claves (uno con un timeout de 10s
+	// si es nil).
+	HTTPClient *http.Client
+}
+
+// Principal es la identidad interna de quien presenta un token válido. Tenant es su inquilino
+// ("" si el token no lo indica o no se ha configurado TenantClaim).
+type Principal struct {
+	User   string
+	Roles  []string
+	Tenant string
+}
+
+// HasRole indica si el usuario tiene el rol 'role'.
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type ctxKey struct{}
+
+// NewContext asocia a ctx el Principal autenticado.
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext devuelve el Principal autenticado de ctx, si lo hay.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(Principal)
+	return p, ok
+}
+
+// Verifier valida tokens de un proveedor. Descubre las claves la primera vez que las necesita y
+// las guarda en caché; un token firmado con una clave desconocida (el proveedor ha rotado)
+// provoca una nueva descarga. Es seguro para uso concurrente.
+type Verifier struct {
+	opts  Options
+	clock sharedDomain.Clock
+
+	mu          sync.Mutex
+	jwksURI     string
+	keys        jose.JSONWebKeySet
+	fetchedAt   time.Time // Última descarga correcta
+	attemptedAt time.Time // Último intento de descarga
+	refreshErr  error     // Error del último intento
+}
+
+// NewVerifier crea un Verifier para el proveedor de 'opts'. No hace peticiones hasta validar el
+// primer token.
+func NewVerifier(opts Options, clock sharedDomain.Clock) *Verifier {
+	if opts.UserClaim == "" {
+		opts.UserClaim = "sub"
+	}
+	if opts.KeysTTL <= 0 {
+		opts.KeysTTL = DefaultKeysTTL
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	opts.Issuer = strings.TrimSuffix(opts.Issuer, "/")
+	return &Verifier{opts: opts, clock: clock}
+}
+
+// Verify valida 'raw' (el token sin el prefijo "Bearer ") y devuelve su Principal. Los errores
+// son ErrInvalidToken o, si no se pudieron obtener las claves, ErrUnavailable.
+func (v *Verifier) Verify(ctx context.Context, raw string) (Principal, error) {
+	token, err := jwt.ParseSigned(raw, signatureAlgorithms)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	key, err := v.key(ctx, token.Headers[0].KeyID)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	var (
+		claims jwt.Claims
+		all    map[string]interface{}
+	)
+	if err := token.Claims(key, &claims, &all); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	expected := jwt.Expected{Issuer: v.opts.Issuer, AnyAudience: jwt.Audience{v.opts.Audience}, Time: v.clock.Now()}
+	if err := claims.ValidateWithLeeway(expected, leeway); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	user, _ := claim(all, v.opts.UserClaim).(string)
+	if user == "" {
+		return Principal{}, fmt.Errorf("%w: missing claim %q", ErrInvalidToken, v.opts.UserClaim)
+	}
+	principal := Principal{User: user, Roles: v.roles(all)}
+	if v.opts.TenantClaim != "" {
+		principal.Tenant, _ = claim(all, v.opts.TenantClaim).(string)
+	}
+	return principal, nil
+}
+
+// roles lee los roles del token y los traduce con RoleMap.
+func (v *Verifier) roles(all map[string]interface{}) []string {
+	if v.opts.RolesClaim == "" {
+		return nil
+	}
+	var raw []string
+	switch value := claim(all, v.opts.RolesClaim).(type) {
+	case string: // Algunos proveedores los envían separados por espacios, como los scopes
+		raw = strings.Fields(value)
+	case []interface{}:
+		for _, r := range value {
+			if s, ok := r.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+	if len(v.opts.RoleMap) == 0 {
+		return raw
+	}
+	roles := make([]string, 0, len(raw))
+	for _, r := range raw {
+		if mapped, ok := v.opts.RoleMap[r]; ok {
+			roles = append(roles, mapped)
+		}
+	}
+	return roles
+}
+
+// claim devuelve el claim 'path' de 'all', bajando por los objetos anidados en cada punto.
+func claim(all map[string]interface{}, path string) interface{} {
+	var value interface{} = all
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// key devuelve la clave pública 'kid', descargando las del proveedor si han caducado o no está.
+func (v *Verifier) key(ctx context.Context, kid string) (interface{}, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.clock.Now()
+	found := v.lookup(kid)
+	expired := v.fetchedAt.IsZero() || now.Sub(v.fetchedAt) > v.opts.KeysTTL
+	if (expired || found == nil) && (v.attemptedAt.IsZero() || now.Sub(v.attemptedAt) >= minRefresh) {
+		v.attemptedAt = now
+		if v.refreshErr = v.refresh(ctx); v.refreshErr == nil {
+			v.fetchedAt = now
+			found = v.lookup(kid)
+		}
+	}
+	// Con la clave aún en caché, una caída del proveedor no corta el servicio.
+	if found == nil && v.refreshErr != nil {
+		return nil, v.refreshErr
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+	}
+	return found, nil
+}
+
+func (v *Verifier) lookup(kid string) interface{} {
+	if kid == "" && len(v.keys.Keys) == 1 {
+		return v.keys.Keys[0].Key
+	}
+	for _, k := range v.keys.Key(kid) {
+		if k.Use == "" || k.Use == "sig" {
+			return k.Key
+		}
+	}
+	return nil
+}
+
+// refresh descubre (una sola vez) la URL de las claves y las descarga.
+func (v *Verifier) refresh(ctx context.Context) error {
+	if v.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, v.opts.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return err
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != v.opts.Issuer || discovery.JWKSURI == "" {
+			return fmt.Errorf("%w: discovery document for %s does not match the issuer or lacks jwks_uri", ErrUnavailable, v.opts.Issuer)
+		}
+		v.jwksURI = discovery.JWKSURI
+	}
+	var keys jose.JSONWebKeySet
+	if err := v.getJSON(ctx, v.jwksURI, &keys); err != nil {
+		return err
+	}
+	v.keys = keys
+	return nil
+}
+
+func (v *Verifier) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	resp, err := v.opts.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: GET %s returned %d", ErrUnavailable, url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: GET %s: %w", ErrUnavailable, url, err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+type stepClock struct {
+	sharedDomain.SystemClock
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+// provider es un proveedor de identidad de pruebas: sirve el descubrimiento y sus claves, y
+// firma tokens con ellas.
+type provider struct {
+	server    *httptest.Server
+	keys      map[string]*ecdsa.PrivateKey
+	jwksCalls atomic.Int32
+}
+
+func newProvider(t *testing.T, kids ...string) *provider {
+	p := &provider{keys: map[string]*ecdsa.PrivateKey{}}
+	for _, kid := range kids {
+		p.addKey(t, kid)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": p.server.URL, "jwks_uri": p.server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		p.jwksCalls.Add(1)
+		var set jose.JSONWebKeySet
+		for kid, key := range p.keys {
+			set.Keys = append(set.Keys, jose.JSONWebKey{Key: &key.PublicKey, KeyID: kid, Algorithm: string(jose.ES256), Use: "sig"})
+		}
+		json.NewEncoder(w).Encode(set)
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *provider) addKey(t *testing.T, kid string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p.keys[kid] = key
+}
+
+func (p *provider) sign(t *testing.T, kid string, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: p.keys[kid]},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", kid))
+	require.NoError(t, err)
+	raw, err := jwt.Signed(signer).Claims(claims).Serialize()
+	require.NoError(t, err)
+	return raw
+}
+
+func (p *provider) claims(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":          p.server.URL,
+		"aud":          "hexagolab-api",
+		"sub":          "user-42",
+		"exp":          now.Add(5 * time.Minute).Unix(),
+		"iat":          now.Unix(),
+		"realm_access": map[string]interface{}{"roles": []string{"hexagolab-admin", "offline_access"}},
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	// Arrange
+	idp := newProvider(t, "k1")
+	clock := &stepClock{now: time.Now()}
+	verifier := NewVerifier(Options{
+		Issuer:     idp.server.URL + "/",
+		Audience:   "hexagolab-api",
+		RolesClaim: "realm_access.roles",
+		RoleMap:    map[string]string{"hexagolab-admin": "admin"},
+	}, clock)
+
+	// Act
+	principal, err := verifier.Verify(context.Background(), idp.sign(t, "k1", idp.claims(clock.now)))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, Principal{User: "user-42", Roles: []string{"admin"}}, principal)
+	assert.True(t, principal.HasRole("admin"))
+}
+
+func TestVerifier_TenantClaim(t *testing.T) {
+	// Arrange
+	idp := newProvider(t, "k1")
+	clock := &stepClock{now: time.Now()}
+	verifier := NewVerifier(Options{Issuer: idp.server.URL, Audience: "hexagolab-api", TenantClaim: "org.id"}, clock)
+	withTenant := idp.claims(clock.now)
+	withTenant["org"] = map[string]interface{}{"id": "acme"}
+
+	// Act
+	principal, err := verifier.Verify(context.Background(), idp.sign(t, "k1", withTenant))
+	withoutTenant, errWithout := verifier.Verify(context.Background(), idp.sign(t, "k1", idp.claims(clock.now)))
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, errWithout)
+	assert.Equal(t, "acme", principal.Tenant)
+	assert.Empty(t, withoutTenant.Tenant)
+}
+
+func TestVerifier_RejectsInvalidTokens(t *testing.T) {
+	idp := newProvider(t, "k1")
+	other := newProvider(t, "k1", "k9")
+	clock := &stepClock{now: time.Now()}
+	verifier := NewVerifier(Options{Issuer: idp.server.URL, Audience: "hexagolab-api"}, clock)
+
+	expired := idp.claims(clock.now)
+	expired["exp"] = clock.now.Add(-time.Hour).Unix()
+	wrongAudience := idp.claims(clock.now)
+	wrongAudience["aud"] = "another-api"
+	wrongIssuer := idp.claims(clock.now)
+	wrongIssuer["iss"] = other.server.URL
+	noSubject := idp.claims(clock.now)
+	delete(noSubject, "sub")
+
+	tokens := map[string]string{
+		"malformed":      "not-a-jwt",
+		"expired":        idp.sign(t, "k1", expired),
+		"wrong audience": idp.sign(t, "k1", wrongAudience),
+		"wrong issuer":   idp.sign(t, "k1", wrongIssuer),
+		"no subject":     idp.sign(t, "k1", noSubject),
+		"foreign key":    other.sign(t, "k1", idp.claims(clock.now)),
+		"unknown kid":    other.sign(t, "k9", idp.claims(clock.now)),
+	}
+	for name, token := range tokens {
+		_, err := verifier.Verify(context.Background(), token)
+
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+}
+
+func TestVerifier_RefreshesKeysOnRotation(t *testing.T) {
+	// Arrange
+	idp := newProvider(t, "k1")
+	clock := &stepClock{now: time.Now()}
+	verifier := NewVerifier(Options{Issuer: idp.server.URL, Audience: "hexagolab-api"}, clock)
+	_, err := verifier.Verify(context.Background(), idp.sign(t, "k1", idp.claims(clock.now)))
+	require.NoError(t, err)
+	idp.addKey(t, "k2")
+
+	// Act: un kid desconocido no se vuelve a pedir hasta pasado minRefresh
+	_, errEarly := verifier.Verify(context.Background(), idp.sign(t, "k2", idp.claims(clock.now)))
+	clock.now = clock.now.Add(minRefresh)
+	_, errLater := verifier.Verify(context.Background(), idp.sign(t, "k2", idp.claims(clock.now)))
+
+	// Assert
+	assert.ErrorIs(t, errEarly, ErrInvalidToken)
+	assert.NoError(t, errLater)
+	assert.Equal(t, int32(2), idp.jwksCalls.Load())
+}
+
+func TestVerifier_ProviderUnavailable(t *testing.T) {
+	// Arrange
+	idp := newProvider(t, "k1")
+	token := idp.sign(t, "k1", idp.claims(time.Now()))
+	verifier := NewVerifier(Options{Issuer: idp.server.URL, Audience: "hexagolab-api"}, &stepClock{now: time.Now()})
+	idp.server.Close()
+
+	// Act
+	_, err := verifier.Verify(context.Background(), token)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
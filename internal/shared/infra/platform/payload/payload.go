@@ -0,0 +1,107 @@
+// Package payload da a los payloads de los eventos del outbox la forma configurada para su tipo
+// (ver sharedEvents.PayloadMode): la entidad completa, solo los campos que cambian o solo su id.
+// Los servicios lo aplican al crear el evento, antes de comprimirlo.
+package payload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// Modes es la forma del payload de cada tipo de evento. Los tipos que no aparecen llevan la
+// entidad completa.
+type Modes map[string]sharedEvents.PayloadMode
+
+// ParseMode normaliza el nombre de un modo ("" es snapshot).
+func ParseMode(name string) (sharedEvents.PayloadMode, error) {
+	switch mode := sharedEvents.PayloadMode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case "", sharedEvents.PayloadSnapshot:
+		return sharedEvents.PayloadSnapshot, nil
+	case sharedEvents.PayloadDelta, sharedEvents.PayloadID:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown payload mode %q, must be snapshot, delta or id", name)
+	}
+}
+
+// Mode devuelve la forma del payload de 'eventType'.
+func (m Modes) Mode(eventType string) sharedEvents.PayloadMode {
+	if mode, ok := m[eventType]; ok {
+		return mode
+	}
+	return sharedEvents.PayloadSnapshot
+}
+
+// Keys son las claves del JSON de una entidad que identifican el cambio: su id, que llevan todos
+// los modos, y su versión, que llevan también los deltas para que se apliquen en orden.
+type Keys struct {
+	ID      string
+	Version string
+}
+
+// Shape da al payload de 'evt', la entidad tras el cambio, la forma configurada para su tipo, y
+// la marca en Metadata[sharedDomain.MetadataPayloadMode] si no es la entidad completa. 'before'
+// es la entidad antes del cambio, con el mismo tipo que el payload, contra la que se calcula el
+// delta; nil en las altas, cuyo delta es la entidad completa. Como los repositorios solo añaden
+// la traza a los eventos sin metadatos, aquí se añade la de 'ctx' antes de marcarlo.
+func (m Modes) Shape(ctx context.Context, evt sharedDomain.OutboxEvent, before any, keys Keys) (sharedDomain.OutboxEvent, error) {
+	mode := m.Mode(evt.EventType)
+	if !mode.Partial() {
+		return evt, nil
+	}
+	after, err := fields(evt.Payload)
+	if err != nil {
+		return evt, err
+	}
+
+	shaped := make(map[string]json.RawMessage, len(after))
+	switch mode {
+	case sharedEvents.PayloadID:
+		shaped[keys.ID] = after[keys.ID]
+	case sharedEvents.PayloadDelta:
+		var previous map[string]json.RawMessage
+		if before != nil {
+			if previous, err = fields(before); err != nil {
+				return evt, err
+			}
+		}
+		for name, value := range after {
+			if old, ok := previous[name]; !ok || !bytes.Equal(old, value) || name == keys.ID || name == keys.Version {
+				shaped[name] = value
+			}
+		}
+	}
+
+	metadata := maps.Clone(evt.Metadata)
+	if metadata == nil {
+		metadata = tracing.Metadata(ctx)
+	}
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[sharedDomain.MetadataPayloadMode] = string(mode)
+	evt.Metadata = metadata
+	evt.Payload = shaped
+	return evt, nil
+}
+
+// fields devuelve el JSON de cada campo de primer nivel de 'entity'.
+func fields(entity any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("outbox payload must be a JSON object: %w", err)
+	}
+	return out, nil
+}
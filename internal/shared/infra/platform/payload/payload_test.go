@@ -0,0 +1,77 @@
+package payload
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+type item struct {
+	ID      uuid.UUID
+	Title   string
+	Status  string
+	Version int64
+}
+
+var itemKeys = Keys{ID: "ID", Version: "Version"}
+
+func TestModes_Shape(t *testing.T) {
+	id := uuid.MustParse("0b8f4c1e-3d2a-4f6b-9c7e-5a1d2e3f4a5b")
+	before := &item{ID: id, Title: "Revisar", Status: "pending", Version: 1}
+	after := &item{ID: id, Title: "Revisar", Status: "completed", Version: 2}
+	modes := Modes{"item.created": sharedEvents.PayloadDelta, "item.updated": sharedEvents.PayloadDelta, "item.touched": sharedEvents.PayloadID}
+
+	tests := []struct {
+		name      string
+		eventType string
+		before    any
+		want      string
+		wantMode  string
+	}{
+		{name: "snapshot por defecto", eventType: "item.deleted", before: before, want: `{"ID": "` + id.String() + `", "Title": "Revisar", "Status": "completed", "Version": 2}`},
+		{name: "delta", eventType: "item.updated", before: before, want: `{"ID": "` + id.String() + `", "Status": "completed", "Version": 2}`, wantMode: "delta"},
+		{name: "delta de un alta", eventType: "item.created", want: `{"ID": "` + id.String() + `", "Title": "Revisar", "Status": "completed", "Version": 2}`, wantMode: "delta"},
+		{name: "solo el id", eventType: "item.touched", before: before, want: `{"ID": "` + id.String() + `"}`, wantMode: "id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := tracing.NewContext(context.Background(), tracing.Context{CorrelationID: "req-1"})
+			evt := sharedDomain.OutboxEvent{ID: uuid.New(), EventType: tt.eventType, Payload: after}
+
+			// Act
+			shaped, err := modes.Shape(ctx, evt, tt.before, itemKeys)
+
+			// Assert
+			require.NoError(t, err)
+			data, err := json.Marshal(shaped.Payload)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+			if tt.wantMode == "" {
+				assert.Nil(t, shaped.Metadata, "La entidad completa no se marca")
+				return
+			}
+			assert.Equal(t, tt.wantMode, shaped.Metadata[sharedDomain.MetadataPayloadMode])
+			assert.Equal(t, "req-1", tracing.FromMap(shaped.Metadata).CorrelationID)
+			assert.Nil(t, evt.Metadata, "El evento original no cambia")
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for name, want := range map[string]sharedEvents.PayloadMode{"": sharedEvents.PayloadSnapshot, "Delta": sharedEvents.PayloadDelta, " id ": sharedEvents.PayloadID} {
+		mode, err := ParseMode(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, mode)
+	}
+	_, err := ParseMode("diff")
+	assert.ErrorContains(t, err, `unknown payload mode "diff"`)
+}
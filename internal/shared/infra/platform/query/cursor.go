@@ -0,0 +1,164 @@
+package query
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// Cursor identifica el último registro de una página para pedir la siguiente: los valores de sus
+// campos de ordenación, como texto y en el orden de OrderBy sin contar el id, y su id, que
+// desempata registros con los mismos valores. Fuera del repositorio viaja como un token opaco
+// (ver Encode), así que el formato puede cambiar sin romper a los clientes. Nulls son las
+// posiciones de Values de los campos sin valor, que no se pueden confundir con un texto vacío.
+type Cursor struct {
+	Values []string  `json:"v"`
+	Nulls  []int     `json:"n,omitempty"`
+	ID     uuid.UUID `json:"id"`
+}
+
+// NewCursor construye el cursor del registro con id 'id' cuyos campos de ordenación valen
+// 'values'. Las fechas se guardan en UTC y RFC 3339 con nanosegundos, el formato que comparan
+// todos los repositorios. Un valor nil es un campo sin valor (ver Cursor.Nulls).
+func NewCursor(id uuid.UUID, values ...any) Cursor {
+	c := Cursor{Values: make([]string, 0, len(values)), ID: id}
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+			c.Values = append(c.Values, "")
+			c.Nulls = append(c.Nulls, i)
+		case time.Time:
+			c.Values = append(c.Values, v.UTC().Format(time.RFC3339Nano))
+		case fmt.Stringer:
+			c.Values = append(c.Values, v.String())
+		default:
+			c.Values = append(c.Values, fmt.Sprint(v))
+		}
+	}
+	return c
+}
+
+// NewCursorFor construye el cursor del registro con id 'id' para el orden 'sorts', leyendo cada
+// campo con 'valueOf'.
+func NewCursorFor(sorts []Sort, id uuid.UUID, valueOf func(field string) any) Cursor {
+	var values []any
+	for _, s := range OrderBy(sorts) {
+		if s.Field != "id" {
+			values = append(values, valueOf(s.Field))
+		}
+	}
+	return NewCursor(id, values...)
+}
+
+// Keyset traduce "ir detrás del cursor" en el orden 'order' (el que devuelve OrderBy) a una
+// disyunción de conjunciones, que cada repositorio pasa a su lenguaje. Para el orden
+// (status ASC, created_at DESC, id DESC) queda:
+//
+//	status > v1
+//	OR (status = v1 AND created_at < v2)
+//	OR (status = v1 AND created_at = v2 AND id < cursor.ID)
+//
+// Los valores son los textos del cursor, salvo el del id, que es un uuid.UUID. En los campos
+// Nullable los registros sin valor van primero en orden ascendente y últimos en descendente, así
+// que la igualdad con un cursor sin valor es OpIsNull, "detrás" de él en ascendente es
+// OpIsNotNull y, en descendente, detrás de un valor van también los registros sin él:
+//
+//	assignee_id < v1 OR assignee_id IS NULL OR (assignee_id = v1 AND id < cursor.ID)
+//
+// Un cursor con un número de valores distinto al de campos, o sin valor en un campo que no es
+// Nullable, devuelve ErrInvalidQuery.
+func Keyset(order []Sort, cursor Cursor) ([][]sharedDomain.Criterion, error) {
+	nulls := make(map[int]bool, len(cursor.Nulls))
+	for _, i := range cursor.Nulls {
+		if i < 0 || i >= len(cursor.Values) {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+		}
+		nulls[i] = true
+	}
+	values := make([]any, len(order))
+	next := 0
+	for i, s := range order {
+		if s.Field == "id" {
+			values[i] = cursor.ID
+			continue
+		}
+		if next == len(cursor.Values) {
+			return nil, fmt.Errorf("%w: cursor does not match the sort", ErrInvalidQuery)
+		}
+		switch {
+		case !nulls[next]:
+			values[i] = cursor.Values[next]
+		case !s.Nullable:
+			return nil, fmt.Errorf("%w: cursor has no value for %s", ErrInvalidQuery, s.Field)
+		}
+		next++
+	}
+	if next != len(cursor.Values) {
+		return nil, fmt.Errorf("%w: cursor does not match the sort", ErrInvalidQuery)
+	}
+
+	branches := make([][]sharedDomain.Criterion, 0, len(order))
+	prefix := make([]sharedDomain.Criterion, 0, len(order))
+	for i, s := range order {
+		// Cada rama tiene su propia copia del prefijo, para que los append no compartan memoria.
+		branch := func(last sharedDomain.Criterion) []sharedDomain.Criterion {
+			return append(append(make([]sharedDomain.Criterion, 0, i+1), prefix...), last)
+		}
+		switch {
+		case values[i] == nil && !s.Desc:
+			branches = append(branches, branch(sharedDomain.Criterion{Field: s.Field, Op: sharedDomain.OpIsNotNull}))
+		case values[i] == nil:
+			// En descendente no hay nada detrás de un campo sin valor salvo sus empates.
+		default:
+			op := sharedDomain.OpGt
+			if s.Desc {
+				op = sharedDomain.OpLt
+			}
+			branches = append(branches, branch(sharedDomain.Criterion{Field: s.Field, Op: op, Value: values[i]}))
+			if s.Desc && s.Nullable {
+				branches = append(branches, branch(sharedDomain.Criterion{Field: s.Field, Op: sharedDomain.OpIsNull}))
+			}
+		}
+
+		if values[i] == nil {
+			prefix = append(prefix, sharedDomain.Criterion{Field: s.Field, Op: sharedDomain.OpIsNull})
+		} else {
+			prefix = append(prefix, sharedDomain.Criterion{Field: s.Field, Op: sharedDomain.OpEq, Value: values[i]})
+		}
+	}
+	return branches, nil
+}
+
+// Encode devuelve el cursor como token opaco y seguro para URLs (JSON en base64 sin relleno),
+// que es lo que se pasa en CursorPagination.Cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c) // Unos strings y un UUID siempre se serializan.
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor es la inversa de Encode. Un token que no sale de Encode devuelve ErrInvalidQuery.
+func DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: invalid cursor encoding", ErrInvalidQuery)
+	}
+	var c Cursor
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return Cursor{}, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+	}
+	if dec.More() {
+		return Cursor{}, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+	}
+	if len(c.Nulls) == 0 {
+		c.Nulls = nil // "n":[] es lo mismo que no tenerlo, que es como lo escribe Encode.
+	}
+	return c, nil
+}
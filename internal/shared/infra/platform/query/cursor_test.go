@@ -0,0 +1,188 @@
+package query
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	id := uuid.MustParse("6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f")
+	madrid := time.FixedZone("CET", 3600)
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"time in UTC with nanoseconds", time.Date(2025, 3, 1, 11, 0, 0, 5, madrid), "2025-03-01T10:00:00.000000005Z"},
+		{"value with the old separator", "ana|garcia@example.com", "ana|garcia@example.com"},
+		{"uuid", id, id.String()},
+		{"number", 42, "42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			token := NewCursor(id, tt.value).Encode()
+			got, err := DecodeCursor(token)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, Cursor{Values: []string{tt.want}, ID: id}, got)
+			assert.Equal(t, token, url.QueryEscape(token), "El token no necesita escaparse en una URL")
+			assert.NotContains(t, token, tt.want, "El token es opaco")
+		})
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	id := uuid.NewString()
+
+	for _, token := range []string{
+		"",
+		"2025-03-01T10:00:00Z|" + id,
+		"not base64!",
+		encode(`{"v":["a"],"id":"not-a-uuid"}`),
+		encode(`{"v":["a"],"id":"` + id + `","extra":1}`),
+		encode(`{"v":["a"],"id":"` + id + `"}{}`),
+		encode(`{"v":"a","id":"` + id + `"}`),
+		encode(`[]`),
+	} {
+		_, err := DecodeCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidQuery, token)
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	// Arrange
+	id := uuid.MustParse("6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f")
+	order := OrderBy([]Sort{{Field: "status"}, {Field: "created_at", Desc: true}})
+	cursor := NewCursor(id, "pending", "2025-03-01T10:00:00Z")
+
+	// Act
+	branches, err := Keyset(order, cursor)
+	require.NoError(t, err)
+	var args []any
+	sql := KeysetSQL(branches, func(v any) string {
+		args = append(args, v)
+		return "?"
+	})
+
+	// Assert
+	assert.Equal(t, "((status > ?) OR (status = ? AND created_at < ?) OR (status = ? AND created_at = ? AND id < ?))", sql)
+	assert.Equal(t, []any{"pending", "pending", "2025-03-01T10:00:00Z", "pending", "2025-03-01T10:00:00Z", id}, args)
+	assert.Equal(t, "status ASC, created_at DESC, id DESC", OrderBySQL(order))
+
+	_, err = Keyset(order, NewCursor(id, "pending"))
+	assert.ErrorIs(t, err, ErrInvalidQuery, "Faltan valores para el orden")
+	_, err = Keyset(OrderBy([]Sort{{Field: "id"}}), NewCursor(id, "pending"))
+	assert.ErrorIs(t, err, ErrInvalidQuery, "Sobran valores para el orden")
+}
+
+func TestKeyset_Nullable(t *testing.T) {
+	id := uuid.MustParse("6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f")
+	nullable := NewFields("assignee_id")
+	tests := []struct {
+		name      string
+		sorts     []Sort
+		value     any
+		wantSQL   string
+		wantArgs  []any
+		wantOrder string
+	}{
+		{
+			name:      "ascending from a null",
+			sorts:     []Sort{{Field: "assignee_id"}},
+			value:     nil,
+			wantSQL:   "((assignee_id IS NOT NULL) OR (assignee_id IS NULL AND id > ?))",
+			wantArgs:  []any{id},
+			wantOrder: "assignee_id ASC NULLS FIRST, id ASC",
+		},
+		{
+			name:      "ascending from a value",
+			sorts:     []Sort{{Field: "assignee_id"}},
+			value:     "a",
+			wantSQL:   "((assignee_id > ?) OR (assignee_id = ? AND id > ?))",
+			wantArgs:  []any{"a", "a", id},
+			wantOrder: "assignee_id ASC NULLS FIRST, id ASC",
+		},
+		{
+			name:      "descending from a null",
+			sorts:     []Sort{{Field: "assignee_id", Desc: true}},
+			value:     nil,
+			wantSQL:   "((assignee_id IS NULL AND id < ?))",
+			wantArgs:  []any{id},
+			wantOrder: "assignee_id DESC NULLS LAST, id DESC",
+		},
+		{
+			name:      "descending from a value",
+			sorts:     []Sort{{Field: "assignee_id", Desc: true}},
+			value:     "a",
+			wantSQL:   "((assignee_id < ?) OR (assignee_id IS NULL) OR (assignee_id = ? AND id < ?))",
+			wantArgs:  []any{"a", "a", id},
+			wantOrder: "assignee_id DESC NULLS LAST, id DESC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange: el cursor pasa por su token, como llega de un cliente.
+			order := MarkNullable(OrderBy(tt.sorts), nullable)
+			cursor, err := DecodeCursor(NewCursor(id, tt.value).Encode())
+			require.NoError(t, err)
+
+			// Act
+			branches, err := Keyset(order, cursor)
+			require.NoError(t, err)
+			var args []any
+			sql := KeysetSQL(branches, func(v any) string {
+				args = append(args, v)
+				return "?"
+			})
+
+			// Assert
+			assert.Equal(t, tt.wantSQL, sql)
+			assert.Equal(t, tt.wantArgs, args)
+			assert.Equal(t, tt.wantOrder, OrderBySQL(order))
+		})
+	}
+
+	t.Run("null on a field that is not nullable", func(t *testing.T) {
+		_, err := Keyset(OrderBy([]Sort{{Field: "status"}}), NewCursor(id, nil))
+		assert.ErrorIs(t, err, ErrInvalidQuery)
+	})
+
+	t.Run("null position out of range", func(t *testing.T) {
+		_, err := Keyset(MarkNullable(OrderBy([]Sort{{Field: "assignee_id"}}), nullable), Cursor{Values: []string{""}, Nulls: []int{1}, ID: id})
+		assert.ErrorIs(t, err, ErrInvalidQuery)
+	})
+}
+
+// FuzzDecodeCursor comprueba que ningún token hace entrar en pánico al decodificador y que, si
+// se acepta, volver a codificarlo da un cursor equivalente.
+func FuzzDecodeCursor(f *testing.F) {
+	f.Add(NewCursor(uuid.New(), time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)).Encode())
+	f.Add(NewCursor(uuid.Nil, "a|b", "pending").Encode())
+	f.Add(NewCursor(uuid.Nil).Encode())
+	f.Add(NewCursor(uuid.Nil, nil, "pending").Encode())
+	f.Add("2025-03-01T10:00:00Z|" + uuid.NewString())
+	f.Add(base64.RawURLEncoding.EncodeToString([]byte(`{"v":["' OR 1=1 --"],"id":"00000000-0000-0000-0000-000000000000"}`)))
+	f.Add("\x00\xff")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		c, err := DecodeCursor(token)
+		if err != nil {
+			require.ErrorIs(t, err, ErrInvalidQuery)
+			return
+		}
+
+		again, err := DecodeCursor(c.Encode())
+		require.NoError(t, err)
+		require.Equal(t, c, again)
+	})
+}
@@ -0,0 +1,29 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MaxIDs es el máximo de ids de una lectura múltiple (GetByIDs): acota el IN de la consulta y el
+// tamaño de la respuesta, igual que el límite de página en los listados.
+const MaxIDs = 100
+
+// UniqueIDs quita los ids repetidos de una lectura múltiple conservando el orden de la petición,
+// y devuelve ErrInvalidQuery si quedan más de MaxIDs.
+func UniqueIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	if len(unique) > MaxIDs {
+		return nil, fmt.Errorf("%w: at most %d ids per request, got %d", ErrInvalidQuery, MaxIDs, len(unique))
+	}
+	return unique, nil
+}
@@ -0,0 +1,32 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueIDs(t *testing.T) {
+	// Arrange
+	a, b := uuid.New(), uuid.New()
+
+	// Act
+	ids, err := UniqueIDs([]uuid.UUID{b, a, b, a})
+
+	// Assert: sin repetidos y en el orden de la petición.
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{b, a}, ids)
+}
+
+func TestUniqueIDs_TooMany(t *testing.T) {
+	ids := make([]uuid.UUID, MaxIDs+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	_, err := UniqueIDs(ids)
+
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
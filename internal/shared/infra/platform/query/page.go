@@ -0,0 +1,59 @@
+package query
+
+// PagedResult es una página de un listado con lo que el cliente necesita para seguir: si quedan
+// registros detrás (HasMore), el cursor de la página siguiente (solo con CursorPagination) y
+// cuántos registros cumplen el filtro en total.
+type PagedResult[T any] struct {
+	Items      []T
+	NextCursor string
+	HasMore    bool
+	Total      int
+}
+
+// ListPage construye un PagedResult. 'list' lista con la paginación que recibe y 'count' cuenta
+// el total con el mismo filtro. Se pide un registro más del límite para saber, sin adivinar, si
+// hay página siguiente; 'cursorOf' da el cursor del último registro de la página.
+func ListPage[T any](pagination Pagination, list func(Pagination) ([]T, error), count func() (int, error), cursorOf func(T) Cursor) (PagedResult[T], error) {
+	limit, probe, byCursor := pageLimit(pagination)
+	items, err := list(probe)
+	if err != nil {
+		return PagedResult[T]{}, err
+	}
+
+	var result PagedResult[T]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+		result.HasMore = true
+	}
+	result.Items = items
+	if result.HasMore && byCursor {
+		result.NextCursor = cursorOf(items[len(items)-1]).Encode()
+	}
+
+	if result.Total, err = count(); err != nil {
+		return PagedResult[T]{}, err
+	}
+	return result, nil
+}
+
+// pageLimit devuelve el límite de la paginación y la misma paginación pidiendo un registro más.
+// Sin límite no hay forma de saber si quedan registros, así que la paginación no cambia.
+func pageLimit(pagination Pagination) (int, Pagination, bool) {
+	switch p := pagination.(type) {
+	case OffsetPagination:
+		if p.Limit > 0 {
+			probe := p
+			probe.Limit++
+			return p.Limit, probe, false
+		}
+		return p.Limit, p, false
+	case CursorPagination:
+		if p.Limit > 0 {
+			probe := p
+			probe.Limit++
+			return p.Limit, probe, true
+		}
+		return p.Limit, p, true
+	}
+	return 0, pagination, false
+}
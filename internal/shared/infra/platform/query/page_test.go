@@ -0,0 +1,101 @@
+package query
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pageSource simula un repositorio de 'n' enteros que pagina por offset y por cursor.
+func pageSource(n int) (func(Pagination) ([]int, error), func() (int, error), *Pagination) {
+	var requested Pagination
+	list := func(p Pagination) ([]int, error) {
+		requested = p
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		limit := len(all)
+		switch p := p.(type) {
+		case OffsetPagination:
+			all = all[min(p.Offset, len(all)):]
+			if p.Limit > 0 {
+				limit = p.Limit
+			}
+		case CursorPagination:
+			if p.Limit > 0 {
+				limit = p.Limit
+			}
+		}
+		return all[:min(limit, len(all))], nil
+	}
+	count := func() (int, error) { return n, nil }
+	return list, count, &requested
+}
+
+func cursorOfInt(i int) Cursor { return NewCursor(uuid.Nil, i) }
+
+func TestListPage(t *testing.T) {
+	tests := []struct {
+		name        string
+		pagination  Pagination
+		wantItems   []int
+		wantHasMore bool
+		wantCursor  bool
+	}{
+		{"offset with more pages", OffsetPagination{Limit: 2}, []int{0, 1}, true, false},
+		{"offset on the last page", OffsetPagination{Limit: 2, Offset: 3}, []int{3, 4}, false, false},
+		{"exact page", OffsetPagination{Limit: 5}, []int{0, 1, 2, 3, 4}, false, false},
+		{"no limit", OffsetPagination{}, []int{0, 1, 2, 3, 4}, false, false},
+		{"cursor with more pages", CursorPagination{Limit: 3}, []int{0, 1, 2}, true, true},
+		{"cursor on the last page", CursorPagination{Limit: 10}, []int{0, 1, 2, 3, 4}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			list, count, _ := pageSource(5)
+
+			// Act
+			page, err := ListPage(tt.pagination, list, count, cursorOfInt)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantItems, page.Items)
+			assert.Equal(t, tt.wantHasMore, page.HasMore)
+			assert.Equal(t, 5, page.Total)
+			if !tt.wantCursor {
+				assert.Empty(t, page.NextCursor)
+				return
+			}
+			cursor, err := DecodeCursor(page.NextCursor)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"2"}, cursor.Values, "El cursor apunta al último registro de la página")
+		})
+	}
+}
+
+func TestListPage_AsksForOneMore(t *testing.T) {
+	// Arrange
+	list, count, requested := pageSource(5)
+
+	// Act
+	_, err := ListPage(OffsetPagination{Limit: 2, Offset: 1}, list, count, cursorOfInt)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, OffsetPagination{Limit: 3, Offset: 1}, *requested)
+}
+
+func TestListPage_PropagatesErrors(t *testing.T) {
+	boom := errors.New("boom")
+	list, count, _ := pageSource(5)
+
+	_, err := ListPage(OffsetPagination{Limit: 2}, func(Pagination) ([]int, error) { return nil, boom }, count, cursorOfInt)
+	assert.ErrorIs(t, err, boom)
+
+	_, err = ListPage(OffsetPagination{Limit: 2}, list, func() (int, error) { return 0, boom }, cursorOfInt)
+	assert.ErrorIs(t, err, boom)
+}
@@ -1,5 +1,10 @@
 package query
 
+import (
+	"fmt"
+	"strings"
+)
+
 // ---------- Tipos de filtrado / paginación / ordenamiento ----------
 
 // OffsetPagination para paginación clásica
@@ -8,19 +13,87 @@ type OffsetPagination struct {
 	Offset int
 }
 
-// CursorPagination para paginación tipo cursor
+// CursorPagination para paginación tipo cursor. El orden es el []Sort del listado, que tiene que
+// ser el mismo con el que se generó el cursor.
 type CursorPagination struct {
-	Limit     int
-	Cursor    string // puede ser un timestamp o UUID serializado
-	SortField string
-	SortDesc  bool
+	Limit  int
+	Cursor string // token de Cursor.Encode; vacío para la primera página
 }
 
 // Interfaz genérica para paginación
 type Pagination interface{}
 
-// Sort indica campo y dirección.
+// Sort indica campo y dirección. Los listados reciben una lista ([]Sort): se ordena por el primer
+// campo, los empates por el segundo, y así sucesivamente.
 type Sort struct {
-	Field string // ej. "created_at", "nombre", "email"
+	Field string // ej. "created_at", "name", "email"
 	Desc  bool
+	// Nullable lo pone el repositorio en los campos que pueden no tener valor (ver MarkNullable):
+	// esos registros van antes que el resto en orden ascendente y después en descendente, en
+	// todos los motores, y el cursor los distingue de un valor vacío (ver Keyset).
+	Nullable bool
+}
+
+// OrderBy devuelve el orden total que aplican los repositorios para 'sorts': sin entradas vacías
+// y desempatando al final por id, en la dirección del último campo, para que dos listados
+// iguales devuelvan siempre el mismo orden y las páginas no se solapen. Lo que vaya detrás de
+// "id" se descarta, porque el id ya no empata. Sin campos no se ordena y devuelve nil.
+func OrderBy(sorts []Sort) []Sort {
+	var order []Sort
+	for _, s := range sorts {
+		if s.Field == "" {
+			continue
+		}
+		order = append(order, s)
+		if s.Field == "id" {
+			return order
+		}
+	}
+	if len(order) == 0 {
+		return nil
+	}
+	return append(order, Sort{Field: "id", Desc: order[len(order)-1].Desc})
+}
+
+// MarkNullable marca con Nullable los campos de 'order' que están en 'nullable' y se la quita al
+// resto, que no la decide quien pide el listado sino la tabla.
+func MarkNullable(order []Sort, nullable Fields) []Sort {
+	for i := range order {
+		order[i].Nullable = nullable[order[i].Field]
+	}
+	return order
+}
+
+// ParseSorts lee una lista de ordenaciones separadas por comas, como llega en el parámetro
+// "sort" de la API: "-campo" o "campo desc" ordenan de forma descendente y "campo" o
+// "campo asc", ascendente. Ej. "status,-created_at" o "status ASC, created_at DESC". Los nombres
+// de campo no se validan aquí, sino en cada repositorio (ver Fields.CheckSorts).
+func ParseSorts(s string) ([]Sort, error) {
+	var sorts []Sort
+	for _, item := range strings.Split(s, ",") {
+		words := strings.Fields(item)
+		if len(words) == 0 {
+			continue
+		}
+		if len(words) > 2 {
+			return nil, fmt.Errorf("%w: invalid sort %q", ErrInvalidQuery, strings.TrimSpace(item))
+		}
+		var sort Sort
+		sort.Field, sort.Desc = strings.CutPrefix(words[0], "-")
+		if len(words) == 2 {
+			switch dir := strings.ToLower(words[1]); {
+			case sort.Desc:
+				return nil, fmt.Errorf("%w: invalid sort %q", ErrInvalidQuery, strings.TrimSpace(item))
+			case dir == "desc":
+				sort.Desc = true
+			case dir != "asc":
+				return nil, fmt.Errorf("%w: invalid sort direction %q", ErrInvalidQuery, words[1])
+			}
+		}
+		if sort.Field == "" {
+			return nil, fmt.Errorf("%w: invalid sort %q", ErrInvalidQuery, strings.TrimSpace(item))
+		}
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
 }
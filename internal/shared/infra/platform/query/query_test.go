@@ -0,0 +1,51 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderBy(t *testing.T) {
+	tests := []struct {
+		name  string
+		sorts []Sort
+		want  []Sort
+	}{
+		{"no sort", nil, nil},
+		{"only empty fields", []Sort{{}}, nil},
+		{"tiebreaker follows the last field", []Sort{{Field: "status"}, {Field: "created_at", Desc: true}},
+			[]Sort{{Field: "status"}, {Field: "created_at", Desc: true}, {Field: "id", Desc: true}}},
+		{"explicit id ends the order", []Sort{{Field: "id", Desc: true}, {Field: "email"}}, []Sort{{Field: "id", Desc: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, OrderBy(tt.sorts))
+		})
+	}
+}
+
+func TestParseSorts(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Sort
+	}{
+		{"", nil},
+		{"created_at", []Sort{{Field: "created_at"}}},
+		{"status,-created_at", []Sort{{Field: "status"}, {Field: "created_at", Desc: true}}},
+		{"status ASC, created_at DESC", []Sort{{Field: "status"}, {Field: "created_at", Desc: true}}},
+		{" title , ", []Sort{{Field: "title"}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSorts(tt.in)
+
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"-", "status sideways", "-status desc", "status desc now"} {
+		_, err := ParseSorts(in)
+		assert.ErrorIs(t, err, ErrInvalidQuery, in)
+	}
+}
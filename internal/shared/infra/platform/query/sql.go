@@ -0,0 +1,297 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// ArgsCapacity es la capacidad inicial del slice de argumentos de una consulta: da para los
+// filtros y la paginación habituales sin que 'append' tenga que crecerlo.
+const ArgsCapacity = 8
+
+// QueryCapacity es la capacidad inicial del strings.Builder de una consulta de listado: da para
+// el SELECT, los filtros, el cursor y el ORDER BY habituales en una sola reserva.
+const QueryCapacity = 256
+
+// OrderBySQL devuelve la lista de un ORDER BY para 'order' (ej. "status ASC, created_at DESC,
+// id DESC"). Los campos ya tienen que estar validados con Fields.CheckSorts.
+func OrderBySQL(order []Sort) string {
+	var b strings.Builder
+	WriteOrderBySQL(&b, order)
+	return b.String()
+}
+
+// WriteOrderBySQL escribe en 'b' la lista de OrderBySQL, para componer la consulta en un solo
+// strings.Builder.
+func WriteOrderBySQL(b *strings.Builder, order []Sort) {
+	size := 0
+	for _, s := range order {
+		size += len(s.Field) + len(", DESC")
+		if s.Nullable {
+			size += len(" NULLS FIRST")
+		}
+	}
+	b.Grow(size)
+	for i, s := range order {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(s.Field)
+		if s.Desc {
+			b.WriteString(" DESC")
+		} else {
+			b.WriteString(" ASC")
+		}
+		// Los campos sin valor van antes que el resto, como en SQLite y MongoDB (ver
+		// Sort.Nullable); Postgres, sin decirlo, los pondría al final.
+		if s.Nullable {
+			if s.Desc {
+				b.WriteString(" NULLS LAST")
+			} else {
+				b.WriteString(" NULLS FIRST")
+			}
+		}
+	}
+}
+
+// Dialect es la tabla de traducción de los operadores neutrales a SQL de un motor concreto. Cada
+// entrada es una plantilla con el campo (%[1]s) y el placeholder del valor (%[2]s), para los
+// operadores que el motor no tiene tal cual (ej. ILIKE en SQLite). Cada adaptador SQL declara la
+// suya con NewDialect.
+type Dialect map[sharedDomain.Operator]string
+
+// NewDialect devuelve la traducción estándar ("campo OP placeholder", o "campo IS NULL" y
+// "campo IS NOT NULL" para OpIsNull y OpIsNotNull) de todos los operadores, con las plantillas de 'overrides' para los que el motor escribe de otra forma.
+func NewDialect(overrides map[sharedDomain.Operator]string) Dialect {
+	d := Dialect{}
+	for _, op := range []sharedDomain.Operator{
+		sharedDomain.OpEq, sharedDomain.OpGt, sharedDomain.OpGte, sharedDomain.OpLt, sharedDomain.OpLte,
+		sharedDomain.OpLike, sharedDomain.OpILike,
+	} {
+		d[op] = "%[1]s " + string(op) + " %[2]s"
+	}
+	d[sharedDomain.OpIsNull] = "%[1]s IS NULL"
+	d[sharedDomain.OpIsNotNull] = "%[1]s IS NOT NULL"
+	for op, template := range overrides {
+		d[op] = template
+	}
+	return d
+}
+
+// Condition devuelve la condición SQL de 'field op placeholder' en este dialecto. El campo ya
+// tiene que estar validado con Fields.CheckCriterion.
+func (d Dialect) Condition(field string, op sharedDomain.Operator, placeholder string) (string, error) {
+	condition, err := d.appendCondition(nil, field, op, placeholder)
+	if err != nil {
+		return "", err
+	}
+	return string(condition), nil
+}
+
+// appendCondition añade a 'buf' la condición de Condition. Sustituye a mano el campo y el
+// placeholder de la plantilla, sin pasar por fmt, porque se llama por cada condición de cada
+// consulta; una plantilla con otras directivas sí se formatea con fmt.
+func (d Dialect) appendCondition(buf []byte, field string, op sharedDomain.Operator, placeholder string) ([]byte, error) {
+	template, ok := d[op]
+	if !ok {
+		return buf, fmt.Errorf("%w: unsupported operator %q", ErrInvalidQuery, op)
+	}
+	start, rest := len(buf), template
+	for {
+		i := strings.IndexByte(rest, '%')
+		if i < 0 {
+			return append(buf, rest...), nil
+		}
+		buf = append(buf, rest[:i]...)
+		switch {
+		case strings.HasPrefix(rest[i:], "%[1]s"):
+			buf = append(buf, field...)
+		case strings.HasPrefix(rest[i:], "%[2]s"):
+			buf = append(buf, placeholder...)
+		default:
+			return fmt.Appendf(buf[:start], template, field, placeholder), nil
+		}
+		rest = rest[i+len("%[1]s"):]
+	}
+}
+
+// WhereSQL traduce 'criteria' a la expresión de un WHERE respetando el operador y el anidamiento
+// de los CompositeCriteria y las negaciones (NOT) de los NotCriteria. Un grupo va entre
+// paréntesis cuando su operador no es el del grupo que lo contiene; el nivel superior cuenta como
+// AND, porque se puede unir con AND a otras condiciones (como la del cursor). Los criterios que
+// no son compuestos son el AND de sus condiciones. 'check' valida cada condición antes de
+// interpolar su campo y 'arg' añade el valor a los argumentos de la consulta y devuelve su
+// placeholder. Sin condiciones devuelve "".
+func WhereSQL(criteria sharedDomain.Criteria, dialect Dialect, check func(sharedDomain.Criterion) error, arg func(value any) string) (string, error) {
+	if criteria == nil {
+		return "", nil
+	}
+	w := whereWriter{buf: make([]byte, 0, 128), dialect: dialect, check: check, arg: arg}
+	if _, err := w.expr(criteria, sharedDomain.OpAnd); err != nil {
+		return "", err
+	}
+	return string(w.buf), nil
+}
+
+// whereWriter escribe la expresión de WhereSQL en un único buffer, sin construir y unir las
+// expresiones de cada grupo por separado.
+type whereWriter struct {
+	buf     []byte
+	dialect Dialect
+	check   func(sharedDomain.Criterion) error
+	arg     func(value any) string
+}
+
+// expr escribe 'criteria' como parte de un grupo con operador 'parent': entre paréntesis si son
+// varias partes y su operador no es 'parent'. Devuelve si ha escrito algo.
+func (w *whereWriter) expr(criteria sharedDomain.Criteria, parent sharedDomain.LogicalOperator) (bool, error) {
+	start := len(w.buf)
+	n, op, err := w.parts(criteria)
+	if err != nil {
+		return false, err
+	}
+	if n > 1 && op != parent {
+		// Hasta ahora no se sabe si son varias partes: el paréntesis se inserta al principio.
+		w.buf = append(w.buf, 0)
+		copy(w.buf[start+1:], w.buf[start:])
+		w.buf[start] = '('
+		w.buf = append(w.buf, ')')
+	}
+	return n > 0, nil
+}
+
+// parts escribe las partes de 'criteria' separadas por el operador que las une, y devuelve
+// cuántas son y ese operador.
+func (w *whereWriter) parts(criteria sharedDomain.Criteria) (int, sharedDomain.LogicalOperator, error) {
+	if not, ok := criteria.(sharedDomain.NotCriteria); ok {
+		if not.Criteria == nil {
+			return 0, sharedDomain.OpAnd, nil
+		}
+		start := len(w.buf)
+		w.buf = append(w.buf, "NOT ("...)
+		n, _, err := w.parts(not.Criteria)
+		if err != nil || n == 0 {
+			w.buf = w.buf[:start]
+			return 0, sharedDomain.OpAnd, err
+		}
+		w.buf = append(w.buf, ')')
+		return 1, sharedDomain.OpAnd, nil
+	}
+
+	composite, ok := criteria.(sharedDomain.CompositeCriteria)
+	if pointer, isPointer := criteria.(*sharedDomain.CompositeCriteria); isPointer && pointer != nil {
+		composite, ok = *pointer, true
+	}
+	if !ok {
+		conditions := criteria.ToConditions()
+		for i, c := range conditions {
+			if err := w.check(c); err != nil {
+				return 0, "", err
+			}
+			if i > 0 {
+				w.buf = append(w.buf, " AND "...)
+			}
+			// OpIsNull y OpIsNotNull no llevan valor: no se añade a los argumentos.
+			placeholder := ""
+			if c.Op.HasValue() {
+				placeholder = w.arg(c.Value)
+			}
+			var err error
+			if w.buf, err = w.dialect.appendCondition(w.buf, c.Field, c.Op, placeholder); err != nil {
+				return 0, "", err
+			}
+		}
+		return len(conditions), sharedDomain.OpAnd, nil
+	}
+
+	op := sharedDomain.OpAnd
+	switch composite.Operator {
+	case sharedDomain.OpAnd, "":
+	case sharedDomain.OpOr:
+		op = sharedDomain.OpOr
+	default:
+		return 0, "", fmt.Errorf("%w: unsupported logical operator %q", ErrInvalidQuery, composite.Operator)
+	}
+	n := 0
+	for _, child := range composite.Criterias {
+		if child == nil {
+			continue
+		}
+		mark := len(w.buf)
+		if n > 0 {
+			w.buf = append(w.buf, ' ')
+			w.buf = append(w.buf, op...)
+			w.buf = append(w.buf, ' ')
+		}
+		wrote, err := w.expr(child, op)
+		if err != nil {
+			return 0, "", err
+		}
+		if !wrote {
+			w.buf = w.buf[:mark]
+			continue
+		}
+		n++
+	}
+	return n, op, nil
+}
+
+// KeysetSQL traduce las condiciones de Keyset a una expresión SQL entre paréntesis. 'arg' añade
+// un valor a los argumentos de la consulta y devuelve su placeholder ("?" en SQLite, "$n" en
+// Postgres).
+func KeysetSQL(branches [][]sharedDomain.Criterion, arg func(value any) string) string {
+	var b strings.Builder
+	WriteKeysetSQL(&b, branches, arg)
+	return b.String()
+}
+
+// WriteKeysetSQL escribe en 'b' la expresión de KeysetSQL.
+func WriteKeysetSQL(b *strings.Builder, branches [][]sharedDomain.Criterion, arg func(value any) string) {
+	size := len("()")
+	for _, branch := range branches {
+		for _, c := range branch {
+			size += len(c.Field) + len(c.Op) + len(" AND  $00")
+		}
+		size += len(" OR ()")
+	}
+	b.Grow(size)
+	b.WriteByte('(')
+	for i, branch := range branches {
+		if i > 0 {
+			b.WriteString(" OR ")
+		}
+		b.WriteByte('(')
+		for j, c := range branch {
+			if j > 0 {
+				b.WriteString(" AND ")
+			}
+			b.WriteString(c.Field)
+			b.WriteByte(' ')
+			b.WriteString(string(c.Op))
+			if c.Op.HasValue() {
+				b.WriteByte(' ')
+				b.WriteString(arg(c.Value))
+			}
+		}
+		b.WriteByte(')')
+	}
+	b.WriteByte(')')
+}
+
+// WriteInSQL escribe en 'b' la condición "field IN (p1, p2, ...)" con un placeholder por valor.
+// 'arg' añade el valor a los argumentos de la consulta y devuelve su placeholder, como en
+// WhereSQL. 'values' no puede estar vacío: "IN ()" no es SQL válido.
+func WriteInSQL[T any](b *strings.Builder, field string, values []T, arg func(value any) string) {
+	b.Grow(len(field) + len(" IN ()") + len(values)*len(", $000"))
+	b.WriteString(field)
+	b.WriteString(" IN (")
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(arg(v))
+	}
+	b.WriteByte(')')
+}
@@ -0,0 +1,192 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func TestDialect_Condition(t *testing.T) {
+	// Arrange
+	standard := NewDialect(nil)
+	lowered := NewDialect(map[sharedDomain.Operator]string{sharedDomain.OpILike: "LOWER(%[1]s) LIKE LOWER(%[2]s)"})
+	formatted := NewDialect(map[sharedDomain.Operator]string{sharedDomain.OpLike: "%[1]s LIKE %[2]s || '%%'"})
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		op      sharedDomain.Operator
+		want    string
+	}{
+		{"standard operator", standard, sharedDomain.OpGte, "created_at >= $1"},
+		{"native ilike", standard, sharedDomain.OpILike, "created_at ILIKE $1"},
+		{"overridden ilike", lowered, sharedDomain.OpILike, "LOWER(created_at) LIKE LOWER($1)"},
+		{"override keeps the rest", lowered, sharedDomain.OpLike, "created_at LIKE $1"},
+		{"other directives go through fmt", formatted, sharedDomain.OpLike, "created_at LIKE $1 || '%'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := tt.dialect.Condition("created_at", tt.op, "$1")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDialect_UnknownOperator(t *testing.T) {
+	_, err := NewDialect(nil).Condition("nombre", "SIMILAR TO", "?")
+
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+// conds es un criterio simple con condiciones arbitrarias, para los tests.
+type conds []sharedDomain.Criterion
+
+func (c conds) ToConditions() []sharedDomain.Criterion { return c }
+
+func TestWhereSQL(t *testing.T) {
+	// Arrange
+	a := conds{{Field: "a", Op: sharedDomain.OpEq, Value: 1}}
+	b := conds{{Field: "b", Op: sharedDomain.OpEq, Value: 2}}
+	c := conds{{Field: "c", Op: sharedDomain.OpEq, Value: 3}}
+	rangeOfD := conds{{Field: "d", Op: sharedDomain.OpGte, Value: 4}, {Field: "d", Op: sharedDomain.OpLte, Value: 5}}
+
+	tests := []struct {
+		name     string
+		criteria sharedDomain.Criteria
+		want     string
+		wantArgs []any
+	}{
+		{"nil", nil, "", nil},
+		{"empty groups", sharedDomain.Or(sharedDomain.And(), nil), "", nil},
+		{"and", sharedDomain.And(a, b), "a = $1 AND b = $2", []any{1, 2}},
+		{"or at the top is grouped", sharedDomain.Or(a, b), "(a = $1 OR b = $2)", []any{1, 2}},
+		{"or inside and", sharedDomain.And(a, sharedDomain.Or(b, c)), "a = $1 AND (b = $2 OR c = $3)", []any{1, 2, 3}},
+		{"and inside or", sharedDomain.Or(sharedDomain.And(a, b), c), "((a = $1 AND b = $2) OR c = $3)", []any{1, 2, 3}},
+		{"several conditions of one criteria inside or", sharedDomain.Or(rangeOfD, &sharedDomain.CompositeCriteria{Operator: sharedDomain.OpOr, Criterias: []sharedDomain.Criteria{a}}), "((d >= $1 AND d <= $2) OR a = $3)", []any{4, 5, 1}},
+		{"a single child needs no parentheses", sharedDomain.Or(a), "a = $1", []any{1}},
+		{"same operator is flattened", sharedDomain.Or(a, sharedDomain.Or(b, c)), "(a = $1 OR b = $2 OR c = $3)", []any{1, 2, 3}},
+		{"not", sharedDomain.Not(a), "NOT (a = $1)", []any{1}},
+		{"not of an or", sharedDomain.And(sharedDomain.Not(sharedDomain.Or(b, c)), a), "NOT (b = $1 OR c = $2) AND a = $3", []any{2, 3, 1}},
+		{"not of several conditions inside or", sharedDomain.Or(sharedDomain.Not(rangeOfD), a), "(NOT (d >= $1 AND d <= $2) OR a = $3)", []any{4, 5, 1}},
+		{"not of nothing", sharedDomain.And(sharedDomain.Not(sharedDomain.And()), sharedDomain.Not(nil)), "", nil},
+		{"composite without operator is and", sharedDomain.CompositeCriteria{Criterias: []sharedDomain.Criteria{a, b}}, "a = $1 AND b = $2", []any{1, 2}},
+		{"is null takes no argument", sharedDomain.And(conds{{Field: "b", Op: sharedDomain.OpIsNull}}, a), "b IS NULL AND a = $1", []any{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []any
+
+			// Act
+			got, err := WhereSQL(tt.criteria, NewDialect(nil), NewFields("a", "b", "c", "d").CheckCriterion, func(v any) string {
+				args = append(args, v)
+				return fmt.Sprintf("$%d", len(args))
+			})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestWhereSQL_Invalid(t *testing.T) {
+	arg := func(any) string { return "?" }
+	check := NewFields("a").CheckCriterion
+
+	_, err := WhereSQL(sharedDomain.Or(conds{{Field: "a", Op: sharedDomain.OpEq}}, conds{{Field: "x", Op: sharedDomain.OpEq}}), NewDialect(nil), check, arg)
+	assert.ErrorIs(t, err, ErrInvalidQuery, "Los campos de los grupos anidados también se validan")
+
+	_, err = WhereSQL(sharedDomain.CompositeCriteria{Operator: "XOR", Criterias: []sharedDomain.Criteria{conds{{Field: "a", Op: sharedDomain.OpEq}}}}, NewDialect(nil), check, arg)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestWriteInSQL(t *testing.T) {
+	// Arrange
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	// Act
+	var b strings.Builder
+	WriteInSQL(&b, "id", ids, arg)
+
+	// Assert
+	assert.Equal(t, "id IN ($1, $2)", b.String())
+	assert.Equal(t, []any{ids[0], ids[1]}, args)
+}
+
+// benchmarkCriteria es un filtro típico de listado: un rango de fechas y un OR de estados.
+var benchmarkCriteria = sharedDomain.And(
+	conds{{Field: "created_at", Op: sharedDomain.OpGte, Value: 1}, {Field: "created_at", Op: sharedDomain.OpLt, Value: 2}},
+	sharedDomain.Or(
+		conds{{Field: "status", Op: sharedDomain.OpEq, Value: "todo"}},
+		conds{{Field: "status", Op: sharedDomain.OpEq, Value: "doing"}},
+		sharedDomain.Not(conds{{Field: "title", Op: sharedDomain.OpILike, Value: "%draft%"}}),
+	),
+)
+
+func BenchmarkWhereSQL(b *testing.B) {
+	dialect := NewDialect(nil)
+	check := NewFields("created_at", "status", "title").CheckCriterion
+	args := make([]any, 0, 8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		args = args[:0]
+		if _, err := WhereSQL(benchmarkCriteria, dialect, check, func(v any) string {
+			args = append(args, v)
+			return "?"
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkKeysetSQL(b *testing.B) {
+	branches, err := Keyset([]Sort{{Field: "status"}, {Field: "created_at", Desc: true}, {Field: "id"}}, NewCursor(uuid.New(), "todo", "2025-03-01T10:00:00Z"))
+	require.NoError(b, err)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = KeysetSQL(branches, func(any) string { return "?" })
+	}
+}
+
+func TestTenantScope(t *testing.T) {
+	// Arrange
+	tc := tracing.New()
+	tc.TenantID = "acme"
+	ctx := tracing.NewContext(context.Background(), tc)
+	criteria := sharedDomain.Or(
+		conds{{Field: "status", Op: sharedDomain.OpEq, Value: "pending"}},
+		conds{{Field: "status", Op: sharedDomain.OpEq, Value: "failed"}},
+	)
+	fields := NewFields("status", TenantColumn)
+	var args []any
+	arg := func(v any) string { args = append(args, v); return "?" }
+
+	// Act
+	scoped, err := WhereSQL(TenantScope(ctx, criteria), NewDialect(nil), fields.CheckCriterion, arg)
+	require.NoError(t, err)
+	unscoped, err := WhereSQL(TenantScope(context.Background(), nil), NewDialect(nil), fields.CheckCriterion, arg)
+	require.NoError(t, err)
+
+	// Assert: el filtro del cliente no puede escapar del inquilino con su OR
+	assert.Equal(t, "tenant_id = ? AND (status = ? OR status = ?)", scoped)
+	assert.Equal(t, "tenant_id = ?", unscoped)
+	assert.Equal(t, []any{"acme", "pending", "failed", ""}, args)
+}
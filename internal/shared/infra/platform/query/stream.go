@@ -0,0 +1,38 @@
+package query
+
+import "context"
+
+// StreamPageSize es el tamaño de página por defecto de Stream: lo bastante grande para no
+// multiplicar las consultas y lo bastante pequeño para no cargar el listado entero en memoria.
+const StreamPageSize = 500
+
+// Stream recorre un listado entero pidiéndolo por páginas de 'pageSize' registros
+// (StreamPageSize si es 0) con paginación por cursor, y llama a 'fn' con cada registro. 'list'
+// lista la página que recibe y 'cursorOf' da el cursor de un registro en el mismo orden que usa
+// 'list'. Al no mantener abierta una consulta larga, cada página ve los datos confirmados en ese
+// momento. Si 'fn' devuelve un error o se cancela 'ctx', el recorrido se detiene con ese error.
+func Stream[T any](ctx context.Context, pageSize int, list func(CursorPagination) ([]T, error), cursorOf func(T) Cursor, fn func(T) error) error {
+	if pageSize <= 0 {
+		pageSize = StreamPageSize
+	}
+	page := CursorPagination{Limit: pageSize}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		items, err := list(page)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		// Una página incompleta es la última: así no hace falta una consulta más para saberlo.
+		if len(items) < pageSize {
+			return nil
+		}
+		page.Cursor = cursorOf(items[len(items)-1]).Encode()
+	}
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cursorSource simula un repositorio de 'n' enteros que pagina por cursor y guarda las páginas pedidas.
+func cursorSource(n int) (func(CursorPagination) ([]int, error), *[]CursorPagination) {
+	var requested []CursorPagination
+	list := func(p CursorPagination) ([]int, error) {
+		requested = append(requested, p)
+		next := 0
+		if p.Cursor != "" {
+			cursor, err := DecodeCursor(p.Cursor)
+			if err != nil {
+				return nil, err
+			}
+			last, _ := strconv.Atoi(cursor.Values[0])
+			next = last + 1
+		}
+		var items []int
+		for i := next; i < n && len(items) < p.Limit; i++ {
+			items = append(items, i)
+		}
+		return items, nil
+	}
+	return list, &requested
+}
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		wantPages int
+	}{
+		{"several pages", 7, 3},
+		{"exact pages", 6, 3}, // La última página vacía confirma que no quedan registros
+		{"empty", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			list, requested := cursorSource(tt.n)
+			got := []int{}
+
+			// Act
+			err := Stream(context.Background(), 3, list, cursorOfInt, func(i int) error {
+				got = append(got, i)
+				return nil
+			})
+
+			// Assert
+			require.NoError(t, err)
+			want := make([]int, tt.n)
+			for i := range want {
+				want[i] = i
+			}
+			assert.Equal(t, want, got)
+			assert.Len(t, *requested, tt.wantPages)
+		})
+	}
+}
+
+func TestStream_DefaultPageSize(t *testing.T) {
+	list, requested := cursorSource(1)
+
+	require.NoError(t, Stream(context.Background(), 0, list, cursorOfInt, func(int) error { return nil }))
+
+	assert.Equal(t, StreamPageSize, (*requested)[0].Limit)
+}
+
+func TestStream_Stops(t *testing.T) {
+	boom := errors.New("boom")
+
+	t.Run("callback error", func(t *testing.T) {
+		list, requested := cursorSource(10)
+		seen := 0
+		err := Stream(context.Background(), 3, list, cursorOfInt, func(i int) error {
+			seen++
+			if i == 4 {
+				return boom
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, boom)
+		assert.Equal(t, 5, seen)
+		assert.Len(t, *requested, 2)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		err := Stream(context.Background(), 3, func(CursorPagination) ([]int, error) { return nil, boom }, cursorOfInt, func(int) error { return nil })
+		assert.ErrorIs(t, err, boom)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		list, requested := cursorSource(10)
+		err := Stream(ctx, 3, list, cursorOfInt, func(i int) error {
+			if i == 2 {
+				cancel()
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, *requested, 1)
+	})
+}
@@ -0,0 +1,31 @@
+package query
+
+import (
+	"context"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// TenantColumn es la columna con el inquilino de cada fila en los repositorios que comparten
+// base de datos entre inquilinos. El inquilino por defecto se guarda como "".
+const TenantColumn = "tenant_id"
+
+// tenantCriteria es la condición TenantColumn = inquilino.
+type tenantCriteria string
+
+func (t tenantCriteria) ToConditions() []sharedDomain.Criterion {
+	return []sharedDomain.Criterion{{Field: TenantColumn, Op: sharedDomain.OpEq, Value: string(t)}}
+}
+
+// TenantScope restringe 'criteria' al inquilino de la traza de ctx (tracing.TenantID). Los
+// repositorios lo aplican a todos sus listados y recuentos, así que un inquilino nunca ve las
+// filas de otro aunque los criterios no lo pidan; su lista blanca de campos tiene que admitir
+// TenantColumn.
+func TenantScope(ctx context.Context, criteria sharedDomain.Criteria) sharedDomain.Criteria {
+	scope := tenantCriteria(tracing.TenantID(ctx))
+	if criteria == nil {
+		return scope
+	}
+	return sharedDomain.And(scope, criteria)
+}
@@ -0,0 +1,61 @@
+package query
+
+import (
+	"fmt"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// ErrInvalidQuery indica que un listado pide algo que el repositorio no puede traducir: un campo
+// que no existe, un operador desconocido o un cursor mal formado. Los repositorios lo devuelven
+// antes de construir la consulta, y la API lo responde como una petición incorrecta.
+var ErrInvalidQuery = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid query")
+
+// Fields es la lista blanca de campos por los que un repositorio deja filtrar y ordenar. Los
+// nombres de campo se interpolan en el SQL (y son claves en Mongo), así que cualquier nombre que
+// llegue de fuera tiene que pasar por aquí.
+type Fields map[string]bool
+
+func NewFields(names ...string) Fields {
+	fields := make(Fields, len(names))
+	for _, name := range names {
+		fields[name] = true
+	}
+	return fields
+}
+
+// CheckCriterion comprueba que la condición usa un campo conocido y un operador soportado.
+func (f Fields) CheckCriterion(c sharedDomain.Criterion) error {
+	if !f[c.Field] {
+		return fmt.Errorf("%w: unknown filter field %q", ErrInvalidQuery, c.Field)
+	}
+	if !c.Op.Valid() {
+		return fmt.Errorf("%w: unsupported operator %q", ErrInvalidQuery, c.Op)
+	}
+	return nil
+}
+
+// Validate comprueba los filtros y la ordenación de un listado contra los campos que declara el
+// dominio, antes de llegar al repositorio. Devuelve un *sharedDomain.CriteriaError.
+func Validate(registry sharedDomain.FieldRegistry, criteria sharedDomain.Criteria, sorts []Sort) error {
+	if err := registry.Validate(criteria); err != nil {
+		return err
+	}
+	for _, sort := range sorts {
+		if err := registry.ValidateSort(sort.Field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckSorts comprueba que todos los campos de ordenación son conocidos. Las entradas sin campo
+// se ignoran.
+func (f Fields) CheckSorts(sorts []Sort) error {
+	for _, sort := range sorts {
+		if sort.Field != "" && !f[sort.Field] {
+			return fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, sort.Field)
+		}
+	}
+	return nil
+}
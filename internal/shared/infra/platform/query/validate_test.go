@@ -0,0 +1,21 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+func TestFields_Check(t *testing.T) {
+	fields := NewFields("email", "created_at")
+
+	assert.NoError(t, fields.CheckCriterion(sharedDomain.Criterion{Field: "email", Op: sharedDomain.OpILike, Value: "%a%"}))
+	assert.ErrorIs(t, fields.CheckCriterion(sharedDomain.Criterion{Field: "email; DROP TABLE users", Op: sharedDomain.OpEq}), ErrInvalidQuery)
+	assert.ErrorIs(t, fields.CheckCriterion(sharedDomain.Criterion{Field: "email", Op: "= 1 OR 1 ="}), ErrInvalidQuery)
+
+	assert.NoError(t, fields.CheckSorts([]Sort{{Field: "created_at", Desc: true}, {Field: "email"}}))
+	assert.NoError(t, fields.CheckSorts([]Sort{{}}), "Sin campo no se ordena")
+	assert.ErrorIs(t, fields.CheckSorts([]Sort{{Field: "email"}, {Field: "created_at DESC, (SELECT 1)"}}), ErrInvalidQuery)
+}
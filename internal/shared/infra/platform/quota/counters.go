@@ -0,0 +1,108 @@
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// RedisCounter cuenta en Redis, así que todas las instancias comparten las cuotas.
+type RedisCounter struct {
+	client *redis.Client
+}
+
+func NewRedisCounter(client *redis.Client) *RedisCounter {
+	return &RedisCounter{client: client}
+}
+
+// Incr incrementa y fija la caducidad en una transacción (MULTI/EXEC).
+func (c *RedisCounter) Incr(ctx context.Context, key string, expireAt time.Time) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireAt(ctx, key, expireAt)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// decrScript resta uno solo si la clave existe: un DECR sobre una clave caducada la crearía a -1
+// y sin caducidad.
+var decrScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	redis.call("DECR", KEYS[1])
+	redis.call("EXPIREAT", KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// Decr resta uno con un script, para comprobar que existe y restar de forma atómica.
+func (c *RedisCounter) Decr(ctx context.Context, key string, expireAt time.Time) error {
+	return decrScript.Run(ctx, c.client, []string{key}, expireAt.Unix()).Err()
+}
+
+// MemoryCounter cuenta en memoria, para cuando no hay Redis: cada instancia lleva sus propias
+// cuentas, que se pierden al reiniciar.
+type MemoryCounter struct {
+	clock sharedDomain.Clock
+
+	mu       sync.Mutex
+	counters map[string]memoryCount
+}
+
+type memoryCount struct {
+	value    int64
+	expireAt time.Time
+}
+
+func NewMemoryCounter(clock sharedDomain.Clock) *MemoryCounter {
+	return &MemoryCounter{clock: clock, counters: make(map[string]memoryCount)}
+}
+
+func (c *MemoryCounter) Incr(_ context.Context, key string, expireAt time.Time) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	count := c.counters[key]
+	if !now.Before(count.expireAt) {
+		count = memoryCount{}
+		// Las ventanas caducan a la vez, así que se aprovecha para limpiar las anteriores.
+		for k, other := range c.counters {
+			if !now.Before(other.expireAt) {
+				delete(c.counters, k)
+			}
+		}
+	}
+	count.value++
+	count.expireAt = expireAt
+	c.counters[key] = count
+	return count.value, nil
+}
+
+func (c *MemoryCounter) Decr(_ context.Context, key string, expireAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count, ok := c.counters[key]
+	if !ok || !c.clock.Now().Before(count.expireAt) {
+		return nil
+	}
+	count.value--
+	count.expireAt = expireAt
+	c.counters[key] = count
+	return nil
+}
+
+// Verificación estática
+var (
+	_ Counter = (*RedisCounter)(nil)
+	_ Counter = (*MemoryCounter)(nil)
+)
@@ -0,0 +1,195 @@
+// Package quota limita cuántas veces puede hacer cada usuario una acción (crear tareas, crear
+// usuarios...) por día o por mes. A diferencia de un limitador de tasa, cuenta en ventanas de
+// calendario (UTC) y los contadores se comparten entre instancias a través de Redis.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// Acciones con cuota.
+const (
+	ActionCreateTask = "tasks.create"
+	ActionCreateUser = "users.create"
+)
+
+// Actions son las acciones que admiten cuota.
+var Actions = []string{ActionCreateTask, ActionCreateUser}
+
+// Anonymous es el sujeto de las peticiones sin usuario: todas comparten cuota.
+const Anonymous = "anonymous"
+
+// ErrQuotaExceeded indica que el usuario ha agotado la cuota de la acción (ver ExceededError).
+var ErrQuotaExceeded = sharedDomain.NewError(sharedDomain.CodeResourceExhausted, "quota exceeded")
+
+// Period es la ventana de una cuota.
+type Period string
+
+const (
+	Daily   Period = "day"
+	Monthly Period = "month"
+)
+
+// window devuelve el inicio y el fin de la ventana de 'p' que contiene 'now', en UTC.
+func (p Period) window(now time.Time) (start, end time.Time) {
+	now = now.UTC()
+	if p == Monthly {
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// Limit es la cuota de una acción: como mucho Max veces por Period.
+type Limit struct {
+	Action string
+	Max    int64
+	Period Period
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%s=%d/%s", l.Action, l.Max, l.Period)
+}
+
+// ParseLimit lee una cuota con el formato "<acción>=<máximo>/<day|month>", p.ej.
+// "tasks.create=100/day".
+func ParseLimit(spec string) (Limit, error) {
+	action, rest, ok := strings.Cut(spec, "=")
+	action = strings.TrimSpace(action)
+	maxText, period, ok2 := strings.Cut(rest, "/")
+	if !ok || !ok2 {
+		return Limit{}, fmt.Errorf("invalid quota %q, expected action=max/day or action=max/month", spec)
+	}
+	known := false
+	for _, a := range Actions {
+		known = known || a == action
+	}
+	if !known {
+		return Limit{}, fmt.Errorf("invalid quota %q, the action must be one of %s", spec, strings.Join(Actions, ", "))
+	}
+	max, err := strconv.ParseInt(strings.TrimSpace(maxText), 10, 64)
+	if err != nil || max < 0 {
+		return Limit{}, fmt.Errorf("invalid quota %q, the maximum must be a non-negative integer", spec)
+	}
+	p := Period(strings.TrimSpace(period))
+	if p != Daily && p != Monthly {
+		return Limit{}, fmt.Errorf("invalid quota %q, the period must be day or month", spec)
+	}
+	return Limit{Action: action, Max: max, Period: p}, nil
+}
+
+// ExceededError indica la cuota agotada y cuándo se renueva.
+type ExceededError struct {
+	Limit Limit
+	Reset time.Time
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: at most %d %s per %s", e.Limit.Max, e.Limit.Action, e.Limit.Period)
+}
+
+func (e *ExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// Counter guarda los contadores de las cuotas.
+type Counter interface {
+	// Incr suma uno al contador 'key' y devuelve su valor. El contador desaparece en 'expireAt'.
+	Incr(ctx context.Context, key string, expireAt time.Time) (int64, error)
+	// Decr deshace un Incr de 'key' con la misma caducidad. Si el contador ya ha caducado no hace
+	// nada.
+	Decr(ctx context.Context, key string, expireAt time.Time) error
+}
+
+// Release devuelve a las cuotas lo que descontó Consume, para una acción que al final no se ha
+// hecho (ej. el alta ha fallado).
+type Release func(ctx context.Context)
+
+// noRelease es el Release de un Consume que no ha descontado nada.
+func noRelease(context.Context) {}
+
+// counted es un contador que Consume ha incrementado.
+type counted struct {
+	limit    Limit
+	key      string
+	expireAt time.Time
+}
+
+// Quotas aplica las cuotas configuradas. Un Quotas nil no limita nada.
+type Quotas struct {
+	counter Counter
+	limits  map[string][]Limit
+	clock   sharedDomain.Clock
+	log     *zap.Logger
+}
+
+// New crea un Quotas que cuenta en 'counter'.
+func New(counter Counter, limits []Limit, clock sharedDomain.Clock, log *zap.Logger) *Quotas {
+	q := &Quotas{counter: counter, limits: make(map[string][]Limit), clock: clock, log: log}
+	for _, l := range limits {
+		q.limits[l.Action] = append(q.limits[l.Action], l)
+	}
+	return q
+}
+
+// Consume descuenta una vez 'action' de las cuotas del usuario de ctx (ver Subject) y devuelve
+// con qué devolverlo si la acción falla. Si alguna está agotada devuelve un *ExceededError y no
+// descuenta nada. Si no se puede contar
+// (Redis caído), deja pasar la petición: una caída del contador no debe parar la API.
+func (q *Quotas) Consume(ctx context.Context, action string) (Release, error) {
+	if q == nil {
+		return noRelease, nil
+	}
+	subject := Subject(ctx)
+	var done []counted
+	for _, l := range q.limits[action] {
+		start, end := l.Period.window(q.clock.Now())
+		c := counted{limit: l, key: fmt.Sprintf("quota:%s:%s:%s:%d", l.Action, subject, l.Period, start.Unix()), expireAt: end}
+		count, err := q.counter.Incr(ctx, c.key, c.expireAt)
+		if err != nil {
+			tracing.Logger(ctx, q.log).Warn("⚠️ No se pudo contar la cuota, se deja pasar",
+				zap.String("quota", l.String()), zap.Error(err))
+			continue
+		}
+		done = append(done, c)
+		if count > l.Max {
+			q.release(ctx, done)
+			return noRelease, &ExceededError{Limit: l, Reset: end}
+		}
+	}
+	if len(done) == 0 {
+		return noRelease, nil
+	}
+	return func(ctx context.Context) { q.release(ctx, done) }, nil
+}
+
+// release deshace los incrementos de 'done'. Un fallo solo se registra: como mucho, el usuario
+// pierde una unidad de su cuota.
+func (q *Quotas) release(ctx context.Context, done []counted) {
+	for _, c := range done {
+		if err := q.counter.Decr(ctx, c.key, c.expireAt); err != nil {
+			tracing.Logger(ctx, q.log).Warn("⚠️ No se pudo devolver la cuota",
+				zap.String("quota", c.limit.String()), zap.Error(err))
+		}
+	}
+}
+
+// Subject es a quién se cuenta una petición: el usuario autenticado (oidc.Principal.User) o,
+// sin autenticación, Anonymous. El X-Actor de la traza no cuenta, porque lo elige el cliente.
+func Subject(ctx context.Context) string {
+	if principal, ok := oidc.FromContext(ctx); ok && principal.User != "" {
+		return principal.User
+	}
+	return Anonymous
+}
@@ -0,0 +1,157 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/oidc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+type stepClock struct {
+	sharedDomain.SystemClock
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func withUser(user string) context.Context {
+	return oidc.NewContext(context.Background(), oidc.Principal{User: user})
+}
+
+// consume es Consume sin el Release, para los tests que no lo usan.
+func consume(q *Quotas, ctx context.Context, action string) error {
+	_, err := q.Consume(ctx, action)
+	return err
+}
+
+func TestParseLimit(t *testing.T) {
+	l, err := ParseLimit("tasks.create=100/day")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Action: ActionCreateTask, Max: 100, Period: Daily}, l)
+
+	for _, spec := range []string{"tasks.create=100", "tasks.create=-1/day", "tasks.create=1/week", "tasks.delete=1/day", "=1/day"} {
+		_, err := ParseLimit(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestQuotas_Consume(t *testing.T) {
+	// Arrange
+	clock := &stepClock{now: time.Date(2025, time.March, 1, 23, 0, 0, 0, time.UTC)}
+	quotas := New(NewMemoryCounter(clock), []Limit{{Action: ActionCreateTask, Max: 2, Period: Daily}}, clock, zap.NewNop())
+	ana, bob := withUser("ana"), withUser("bob")
+
+	// Act
+	first := consume(quotas, ana, ActionCreateTask)
+	second := consume(quotas, ana, ActionCreateTask)
+	third := consume(quotas, ana, ActionCreateTask)
+	otherUser := consume(quotas, bob, ActionCreateTask)
+	otherAction := consume(quotas, ana, ActionCreateUser)
+	clock.now = clock.now.Add(time.Hour) // Día siguiente
+	nextDay := consume(quotas, ana, ActionCreateTask)
+
+	// Assert
+	assert.NoError(t, first)
+	assert.NoError(t, second)
+	var exceeded *ExceededError
+	require.ErrorAs(t, third, &exceeded)
+	assert.ErrorIs(t, third, ErrQuotaExceeded)
+	assert.Equal(t, time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC), exceeded.Reset)
+	assert.NoError(t, otherUser)
+	assert.NoError(t, otherAction)
+	assert.NoError(t, nextDay)
+}
+
+func TestQuotas_MonthlyWindow(t *testing.T) {
+	start, end := Monthly.window(time.Date(2025, time.December, 31, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestQuotas_Release(t *testing.T) {
+	// Arrange
+	clock := &stepClock{now: time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)}
+	quotas := New(NewMemoryCounter(clock), []Limit{{Action: ActionCreateTask, Max: 1, Period: Daily}}, clock, zap.NewNop())
+	ana := withUser("ana")
+
+	// Act: la primera alta falla y devuelve su unidad.
+	release, err := quotas.Consume(ana, ActionCreateTask)
+	require.NoError(t, err)
+	release(ana)
+	second := consume(quotas, ana, ActionCreateTask)
+	third := consume(quotas, ana, ActionCreateTask)
+
+	// Assert
+	assert.NoError(t, second)
+	assert.ErrorIs(t, third, ErrQuotaExceeded)
+}
+
+func TestQuotas_ExceededDoesNotConsume(t *testing.T) {
+	// Arrange: la cuota mensual se cuenta antes que la diaria, que se agota primero.
+	clock := &stepClock{now: time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)}
+	counter := NewMemoryCounter(clock)
+	quotas := New(counter, []Limit{
+		{Action: ActionCreateTask, Max: 10, Period: Monthly},
+		{Action: ActionCreateTask, Max: 1, Period: Daily},
+	}, clock, zap.NewNop())
+	ana := withUser("ana")
+	require.NoError(t, consume(quotas, ana, ActionCreateTask))
+
+	// Act: los intentos que rechaza la diaria no gastan la mensual.
+	for range 3 {
+		assert.ErrorIs(t, consume(quotas, ana, ActionCreateTask), ErrQuotaExceeded)
+	}
+
+	// Assert: el siguiente incremento de la mensual es el segundo.
+	start, end := Monthly.window(clock.now)
+	monthly, err := counter.Incr(ana, fmt.Sprintf("quota:%s:ana:%s:%d", ActionCreateTask, Monthly, start.Unix()), end)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), monthly)
+}
+
+func TestSubject(t *testing.T) {
+	// Arrange: el cliente manda X-Actor, que no es una identidad verificada.
+	tc := tracing.New()
+	tc.Actor = "ana"
+	spoofed := tracing.NewContext(context.Background(), tc)
+
+	// Act & Assert
+	assert.Equal(t, Anonymous, Subject(spoofed))
+	assert.Equal(t, "bob", Subject(oidc.NewContext(spoofed, oidc.Principal{User: "bob"})))
+}
+
+type failingCounter struct{}
+
+func (failingCounter) Incr(context.Context, string, time.Time) (int64, error) {
+	return 0, errors.New("redis down")
+}
+
+func (failingCounter) Decr(context.Context, string, time.Time) error {
+	return errors.New("redis down")
+}
+
+func TestQuotas_FailsOpen(t *testing.T) {
+	quotas := New(failingCounter{}, []Limit{{Action: ActionCreateTask, Max: 0, Period: Daily}}, sharedDomain.SystemClock{}, zap.NewNop())
+
+	release, err := quotas.Consume(context.Background(), ActionCreateTask)
+	assert.NoError(t, err)
+	release(context.Background())
+}
+
+func TestQuotas_NilDoesNotLimit(t *testing.T) {
+	var quotas *Quotas
+
+	release, err := quotas.Consume(context.Background(), ActionCreateTask)
+	assert.NoError(t, err)
+	release(context.Background())
+}
@@ -0,0 +1,218 @@
+// Package redact oculta los datos personales (emails, nombres, fechas de nacimiento) de los logs.
+// Los structs marcan sus campos sensibles con la etiqueta `log:"redact"`; en los mapas y en los
+// payloads JSON sin tipo se reconocen por el nombre de la clave. Los ids se dejan intactos para
+// poder correlacionar las trazas.
+package redact
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Mask sustituye en los logs el valor de un campo sensible.
+const Mask = "[REDACTED]"
+
+// Tag es la etiqueta de struct que marca un campo sensible: `log:"redact"`.
+const Tag = "log"
+
+// sensitiveKeys son las claves sensibles de los mapas, de los payloads JSON y de los campos de
+// zap: los nombres JSON de los campos etiquetados de los contratos de eventos.
+var sensitiveKeys = map[string]bool{
+	"email":      true,
+	"name":       true,
+	"nombre":     true,
+	"birth_date": true,
+}
+
+// Sensitive indica si 'key' nombra un dato personal.
+func Sensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// Any es zap.Any con los datos personales de 'v' ocultos (ver Value).
+func Any(key string, v interface{}) zap.Field {
+	return zap.Any(key, Value(v))
+}
+
+// Value devuelve una copia de 'v' apta para un log: los structs pasan a mapas con sus nombres
+// JSON y los campos etiquetados con `log:"redact"` valen Mask, igual que las claves sensibles de
+// los mapas y de los []byte que contienen JSON. Los tipos que se serializan solos (uuid.UUID,
+// time.Time) se dejan tal cual.
+func Value(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return value(reflect.ValueOf(v))
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func value(rv reflect.Value) interface{} {
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return value(rv.Elem())
+	case reflect.Slice:
+		// También json.RawMessage, que se serializa sola pero puede llevar datos personales.
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return jsonBytes(rv.Bytes())
+		}
+	}
+	if rv.Type().Implements(jsonMarshaler) || rv.Type().Implements(textMarshaler) {
+		return rv.Interface()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		out := make(map[string]interface{}, rv.NumField())
+		structFields(rv, out)
+		return out
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return rv.Interface()
+		}
+		out := make(map[string]interface{}, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			key := iter.Key().String()
+			if Sensitive(key) {
+				out[key] = Mask
+				continue
+			}
+			out[key] = value(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = value(rv.Index(i))
+		}
+		return out
+	}
+	return rv.Interface()
+}
+
+// structFields copia en 'out' los campos exportados de 'rv' con su nombre JSON, aplanando los
+// structs embebidos como hace encoding/json.
+func structFields(rv reflect.Value, out map[string]interface{}) {
+	for i := 0; i < rv.NumField(); i++ {
+		field, fv := rv.Type().Field(i), rv.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			if fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				structFields(fv, out)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if field.Tag.Get(Tag) == "redact" {
+			out[name] = Mask
+			continue
+		}
+		out[name] = value(fv)
+	}
+}
+
+// jsonBytes oculta las claves sensibles de 'data' si es JSON (un payload sin tipo); si no, lo
+// devuelve tal cual.
+func jsonBytes(data []byte) interface{} {
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return data
+	}
+	return value(reflect.ValueOf(decoded))
+}
+
+// Fields devuelve 'fields' con los datos personales ocultos: los campos con clave sensible
+// valen Mask y los registrados con zap.Any pasan por Value. Si no hay nada que ocultar devuelve
+// el mismo slice.
+func Fields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		redacted, changed := field(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)), fields[:i]...)
+		}
+		out = append(out, redacted)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func field(f zapcore.Field) (zapcore.Field, bool) {
+	switch {
+	case Sensitive(f.Key) && f.Type != zapcore.SkipType:
+		return zap.String(f.Key, Mask), true
+	case f.Type == zapcore.ReflectType:
+		return zap.Reflect(f.Key, Value(f.Interface)), true
+	}
+	return f, false
+}
+
+// ZapOption instala la ocultación en el logger: se aplica a todos sus campos, también a los que
+// heredan los loggers hijos, antes de que lleguen a cualquier salida (consola, Sentry...).
+func ZapOption() zap.Option {
+	return zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return core{c}
+	})
+}
+
+// core oculta los datos personales antes de escribir en el core envuelto. Si este es un tee
+// (consola y Sentry, por ejemplo) solo escribe en los cores que aceptan el nivel de la entrada,
+// como haría el tee por sí mismo.
+type core struct {
+	zapcore.Core
+}
+
+func (c core) With(fields []zapcore.Field) zapcore.Core {
+	return core{c.Core.With(Fields(fields))}
+}
+
+func (c core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	c.Core.Check(entry, nil).Write(Fields(fields)...)
+	return nil
+}
+
+// Verificación estática
+var _ zapcore.Core = core{}
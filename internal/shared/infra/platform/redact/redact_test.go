@@ -0,0 +1,92 @@
+package redact
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type person struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email" log:"redact"`
+	BirthDate time.Time `json:"birth_date" log:"redact"`
+	Title     string    `json:"title,omitempty"`
+	secret    string
+}
+
+type envelope struct {
+	person
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func TestValue_Struct(t *testing.T) {
+	// Arrange
+	id := uuid.New()
+	p := &person{ID: id, Email: "ana@example.com", BirthDate: time.Now(), secret: "x"}
+
+	// Act
+	got := Value(p)
+
+	// Assert: los ids se conservan para correlacionar
+	assert.Equal(t, map[string]interface{}{"id": id, "email": Mask, "birth_date": Mask}, got)
+}
+
+func TestValue_EmbeddedAndRawPayload(t *testing.T) {
+	// Arrange
+	id := uuid.New()
+	evt := envelope{
+		person:  person{ID: id},
+		Type:    "user.created",
+		Payload: json.RawMessage(`{"id":"` + id.String() + `","email":"ana@example.com","nombre":"Ana"}`),
+	}
+
+	// Act
+	got := Value(evt)
+
+	// Assert
+	assert.Equal(t, map[string]interface{}{
+		"id":         id,
+		"email":      Mask,
+		"birth_date": Mask,
+		"type":       "user.created",
+		"payload":    map[string]interface{}{"id": id.String(), "email": Mask, "nombre": Mask},
+	}, got)
+}
+
+func TestValue_Maps(t *testing.T) {
+	got := Value(map[string]interface{}{"Email": "ana@example.com", "items": []interface{}{map[string]string{"nombre": "Ana"}}})
+
+	assert.Equal(t, map[string]interface{}{
+		"Email": Mask,
+		"items": []interface{}{map[string]interface{}{"nombre": Mask}},
+	}, got)
+}
+
+func TestZapOption(t *testing.T) {
+	// Arrange: un tee con un core que solo acepta errores, como el de Sentry
+	infoCore, infoLogs := observer.New(zap.InfoLevel)
+	errorCore, errorLogs := observer.New(zap.ErrorLevel)
+	log := zap.New(zapcore.NewTee(infoCore, errorCore), ZapOption())
+
+	// Act
+	log.With(zap.String("email", "ana@example.com")).Info("user created",
+		zap.Any("user", person{Email: "ana@example.com"}),
+		zap.String("user_id", "42"),
+	)
+
+	// Assert
+	require.Equal(t, 1, infoLogs.Len())
+	assert.Zero(t, errorLogs.Len())
+	fields := infoLogs.All()[0].ContextMap()
+	assert.Equal(t, Mask, fields["email"])
+	assert.Equal(t, "42", fields["user_id"])
+	assert.Equal(t, Mask, fields["user"].(map[string]interface{})["email"])
+}
@@ -0,0 +1,160 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// flushTimeout es lo que se espera a que salgan los eventos pendientes al cerrar o tras un pánico.
+const flushTimeout = 2 * time.Second
+
+// Options configura el envío de errores a Sentry.
+type Options struct {
+	DSN         string
+	Environment string // Perfil de la aplicación (dev, staging, prod)
+	Release     string
+}
+
+// Init configura el cliente global de Sentry. Sin DSN el envío queda deshabilitado y el resto
+// de funciones del paquete no hacen nada; devuelve si quedó habilitado.
+func Init(opts Options) (bool, error) {
+	if opts.DSN == "" {
+		return false, nil
+	}
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              opts.DSN,
+		Environment:      opts.Environment,
+		Release:          opts.Release,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to initialize Sentry: %w", err)
+	}
+	return true, nil
+}
+
+// Flush espera a que se envíen los eventos pendientes.
+func Flush() {
+	sentry.Flush(flushTimeout)
+}
+
+// ZapOption engancha Sentry al logger: cada entrada de nivel error o superior se envía como evento.
+func ZapOption() zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, NewZapCore(sentry.CurrentHub()))
+	})
+}
+
+// ReportPanic envía a Sentry un pánico recuperado, con la traza de ctx como etiquetas.
+// 'where' identifica el punto de entrada (http, grpc, consumer).
+func ReportPanic(ctx context.Context, where string, recovered interface{}) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("entrypoint", where)
+		if tc, ok := tracing.FromContext(ctx); ok {
+			scope.SetTags(traceTags(tc))
+		}
+		hub.RecoverWithContext(ctx, recovered)
+	})
+	hub.Flush(flushTimeout)
+}
+
+func traceTags(tc tracing.Context) map[string]string {
+	tags := make(map[string]string, 2)
+	if tc.CorrelationID != "" {
+		tags["correlation_id"] = tc.CorrelationID
+	}
+	if tc.TraceID != "" {
+		tags["trace_id"] = tc.TraceID
+	}
+	return tags
+}
+
+// ---------------- zap ----------------
+
+// zapCore es un zapcore.Core que convierte las entradas de error en eventos de Sentry.
+// Los campos correlation_id y trace_id (ver tracing.Fields) se envían como etiquetas para poder
+// buscar el evento por petición; el campo "error" como excepción y el resto como datos extra.
+type zapCore struct {
+	hub    *sentry.Hub
+	fields []zapcore.Field
+}
+
+// NewZapCore crea el core que envía a 'hub' las entradas de nivel error o superior.
+func NewZapCore(hub *sentry.Hub) zapcore.Core {
+	return &zapCore{hub: hub}
+}
+
+func (c *zapCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel && c.hub.Client() != nil
+}
+
+func (c *zapCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &zapCore{hub: c.hub, fields: make([]zapcore.Field, 0, len(c.fields)+len(fields))}
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+	return clone
+}
+
+func (c *zapCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *zapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	event := sentry.NewEvent()
+	event.Level = sentryLevel(entry.Level)
+	event.Message = entry.Message
+	event.Logger = entry.LoggerName
+	event.Timestamp = entry.Time
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range append(c.fields[:len(c.fields):len(c.fields)], fields...) {
+		if err, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType {
+			event.SetException(err, 10)
+			continue
+		}
+		f.AddTo(enc)
+	}
+	for k, v := range enc.Fields {
+		switch k {
+		case "correlation_id", "trace_id":
+			event.Tags[k] = fmt.Sprint(v)
+		default:
+			event.Extra[k] = v
+		}
+	}
+
+	c.hub.CaptureEvent(event)
+	if entry.Level > zapcore.ErrorLevel {
+		// Panic/Fatal terminan el proceso: enviamos antes de salir.
+		c.hub.Flush(flushTimeout)
+	}
+	return nil
+}
+
+func (c *zapCore) Sync() error {
+	c.hub.Flush(flushTimeout)
+	return nil
+}
+
+func sentryLevel(level zapcore.Level) sentry.Level {
+	switch level {
+	case zapcore.ErrorLevel:
+		return sentry.LevelError
+	default:
+		return sentry.LevelFatal
+	}
+}
@@ -0,0 +1,83 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+func newTestHub(t *testing.T) (*sentry.Hub, *sentry.MockTransport) {
+	t.Helper()
+	transport := &sentry.MockTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         "https://public@sentry.example.com/1",
+		Transport:   transport,
+		Environment: "test",
+		Release:     "v1.2.3",
+	})
+	require.NoError(t, err)
+	return sentry.NewHub(client, sentry.NewScope()), transport
+}
+
+func TestZapCore_SendsErrorLogs(t *testing.T) {
+	// Arrange
+	hub, transport := newTestHub(t)
+	tc := tracing.New()
+	log := zap.New(NewZapCore(hub)).With(tracing.Fields(tc)...)
+
+	// Act
+	log.Info("no se envía")
+	log.Warn("tampoco")
+	log.Error("falló la publicación", zap.String("event_id", "evt-1"), zap.Error(errors.New("kafka is down")))
+
+	// Assert
+	events := transport.Events()
+	require.Len(t, events, 1)
+	evt := events[0]
+	assert.Equal(t, sentry.LevelError, evt.Level)
+	assert.Equal(t, "falló la publicación", evt.Message)
+	assert.Equal(t, "test", evt.Environment)
+	assert.Equal(t, "v1.2.3", evt.Release)
+	assert.Equal(t, tc.CorrelationID, evt.Tags["correlation_id"])
+	assert.Equal(t, tc.TraceID, evt.Tags["trace_id"])
+	assert.Equal(t, "evt-1", evt.Extra["event_id"])
+	require.NotEmpty(t, evt.Exception)
+	assert.Equal(t, "kafka is down", evt.Exception[len(evt.Exception)-1].Value)
+}
+
+func TestZapCore_DisabledWithoutClient(t *testing.T) {
+	core := NewZapCore(sentry.NewHub(nil, sentry.NewScope()))
+
+	assert.False(t, core.Enabled(zap.ErrorLevel))
+}
+
+func TestReportPanic(t *testing.T) {
+	// Arrange
+	hub, transport := newTestHub(t)
+	tc := tracing.New()
+	ctx := sentry.SetHubOnContext(tracing.NewContext(context.Background(), tc), hub)
+
+	// Act
+	ReportPanic(ctx, "http", "boom")
+
+	// Assert
+	events := transport.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, sentry.LevelFatal, events[0].Level)
+	assert.Equal(t, "http", events[0].Tags["entrypoint"])
+	assert.Equal(t, tc.CorrelationID, events[0].Tags["correlation_id"])
+}
+
+func TestInit_WithoutDSN(t *testing.T) {
+	enabled, err := Init(Options{})
+
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+}
@@ -0,0 +1,64 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// AWSSecretsManagerProvider lee secretos de AWS Secrets Manager.
+// El path de la referencia es el nombre o ARN del secreto: awssm://prod/hexagolab#jwt_key.
+type AWSSecretsManagerProvider struct {
+	client *secretsmanager.Client
+}
+
+// NewAWSSecretsManagerProvider es el constructor. Las credenciales se obtienen con la cadena
+// estándar del SDK (entorno, perfil, rol de la instancia...).
+func NewAWSSecretsManagerProvider(ctx context.Context, region string) (*AWSSecretsManagerProvider, error) {
+	var opts []func(*awsConfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsConfig.WithRegion(region))
+	}
+
+	cfg, err := awsConfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &AWSSecretsManagerProvider{client: secretsmanager.NewFromConfig(cfg)}, nil
+}
+
+func (p *AWSSecretsManagerProvider) GetSecret(ctx context.Context, path, key string) (string, error) {
+	out, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(path)})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return "", ErrSecretNotFound
+		}
+		return "", err
+	}
+
+	value := aws.ToString(out.SecretString)
+	if key == "" {
+		return value, nil
+	}
+
+	// Con clave, el secreto debe ser un objeto JSON (el formato clave-valor de la consola de AWS).
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("aws secret %s is not a JSON object: %w", path, err)
+	}
+	v, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %q in %s", ErrSecretNotFound, key, path)
+	}
+	return fmt.Sprint(v), nil
+}
+
+// Verificación estática de la interfaz.
+var _ Provider = (*AWSSecretsManagerProvider)(nil)
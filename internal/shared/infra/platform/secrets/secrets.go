@@ -0,0 +1,153 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSecretNotFound se devuelve cuando el secreto (o la clave pedida dentro de él) no existe.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Provider obtiene secretos de un backend concreto (Vault, AWS Secrets Manager...).
+type Provider interface {
+	// GetSecret devuelve el valor del secreto 'path'. Si 'key' no está vacío, el secreto
+	// se interpreta como un objeto clave-valor y se devuelve solo ese campo.
+	GetSecret(ctx context.Context, path, key string) (string, error)
+}
+
+// Reference es una referencia a un secreto dentro de un valor de configuración:
+//
+//	<scheme>://<path>[#<key>]    p.ej. vault://secret/hexagolab/db#dsn, awssm://prod/hexagolab#jwt_key
+type Reference struct {
+	Scheme string
+	Path   string
+	Key    string
+}
+
+func (r Reference) String() string {
+	if r.Key == "" {
+		return r.Scheme + "://" + r.Path
+	}
+	return r.Scheme + "://" + r.Path + "#" + r.Key
+}
+
+// ParseReference interpreta 'value' como referencia a un secreto de alguno de los esquemas dados.
+// Devuelve false si el valor es un literal.
+func ParseReference(value string, schemes ...string) (Reference, bool) {
+	for _, scheme := range schemes {
+		rest, ok := strings.CutPrefix(value, scheme+"://")
+		if !ok {
+			continue
+		}
+		path, key, _ := strings.Cut(rest, "#")
+		return Reference{Scheme: scheme, Path: path, Key: key}, true
+	}
+	return Reference{}, false
+}
+
+// Resolver sustituye referencias a secretos por su valor, delegando en el Provider de cada esquema.
+type Resolver struct {
+	providers map[string]Provider
+	cache     *Cache
+}
+
+// NewResolver es el constructor. 'cache' puede ser nil para no cachear.
+func NewResolver(cache *Cache) *Resolver {
+	return &Resolver{providers: map[string]Provider{}, cache: cache}
+}
+
+// Register asocia un esquema (p.ej. "vault") con su Provider.
+func (r *Resolver) Register(scheme string, p Provider) {
+	r.providers[scheme] = p
+}
+
+// IsReference indica si 'value' apunta a un secreto de algún esquema registrado.
+func (r *Resolver) IsReference(value string) bool {
+	_, ok := ParseReference(value, r.schemes()...)
+	return ok
+}
+
+// Resolve devuelve el valor del secreto si 'value' es una referencia, o 'value' tal cual si es un literal.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	ref, ok := ParseReference(value, r.schemes()...)
+	if !ok {
+		return value, nil
+	}
+
+	if r.cache != nil {
+		if v, ok := r.cache.get(ref.String()); ok {
+			return v, nil
+		}
+	}
+
+	v, err := r.providers[ref.Scheme].GetSecret(ctx, ref.Path, ref.Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %s: %w", ref, err)
+	}
+
+	if r.cache != nil {
+		r.cache.set(ref.String(), v)
+	}
+	return v, nil
+}
+
+func (r *Resolver) schemes() []string {
+	schemes := make([]string, 0, len(r.providers))
+	for s := range r.providers {
+		schemes = append(schemes, s)
+	}
+	return schemes
+}
+
+// Cache guarda secretos ya resueltos durante un TTL. Al expirar se vuelven a pedir al backend,
+// de modo que un secreto rotado se recoge en la siguiente resolución.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// NewCache es el constructor.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+// SetTTL cambia el tiempo de vida de las entradas nuevas.
+func (c *Cache) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// Purge descarta todos los secretos cacheados, forzando a releerlos (p.ej. tras una rotación).
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]cacheEntry{}
+}
+
+func (c *Cache) get(ref string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[ref]
+	if !ok || time.Now().After(e.expiresAt) {
+		return "", false
+	}
+	return e.value, true
+}
+
+func (c *Cache) set(ref, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[ref] = cacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
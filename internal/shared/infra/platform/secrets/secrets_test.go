@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	values map[string]string
+	calls  int
+}
+
+func (p *fakeProvider) GetSecret(ctx context.Context, path, key string) (string, error) {
+	p.calls++
+	v, ok := p.values[path+"#"+key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return v, nil
+}
+
+func TestParseReference(t *testing.T) {
+	ref, ok := ParseReference("vault://secret/hexagolab/db#dsn", "vault", "awssm")
+	require.True(t, ok)
+	assert.Equal(t, Reference{Scheme: "vault", Path: "secret/hexagolab/db", Key: "dsn"}, ref)
+
+	ref, ok = ParseReference("awssm://prod/hexagolab", "vault", "awssm")
+	require.True(t, ok)
+	assert.Equal(t, Reference{Scheme: "awssm", Path: "prod/hexagolab"}, ref)
+
+	_, ok = ParseReference("postgres://localhost:5432/db", "vault", "awssm")
+	assert.False(t, ok, "Un literal no es una referencia")
+}
+
+func TestResolver_CachesUntilPurged(t *testing.T) {
+	// Arrange
+	provider := &fakeProvider{values: map[string]string{"db#dsn": "postgres://v1"}}
+	cache := NewCache(time.Minute)
+	resolver := NewResolver(cache)
+	resolver.Register("vault", provider)
+
+	// Act
+	first, err1 := resolver.Resolve(context.Background(), "vault://db#dsn")
+	provider.values["db#dsn"] = "postgres://v2" // Rotación en el backend
+	cached, err2 := resolver.Resolve(context.Background(), "vault://db#dsn")
+	cache.Purge()
+	rotated, err3 := resolver.Resolve(context.Background(), "vault://db#dsn")
+
+	// Assert
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	require.NoError(t, err3)
+	assert.Equal(t, "postgres://v1", first)
+	assert.Equal(t, "postgres://v1", cached, "Mientras no expire se sirve desde caché")
+	assert.Equal(t, "postgres://v2", rotated, "Tras purgar se recoge el secreto rotado")
+	assert.Equal(t, 2, provider.calls)
+}
+
+func TestResolver_LiteralAndMissing(t *testing.T) {
+	resolver := NewResolver(nil)
+	resolver.Register("vault", &fakeProvider{values: map[string]string{}})
+
+	v, err := resolver.Resolve(context.Background(), "plain-value")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-value", v)
+
+	_, err = resolver.Resolve(context.Background(), "vault://missing#key")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+}
+
+func TestVaultProvider_KVv2(t *testing.T) {
+	// Arrange: servidor que imita la API KV v2 de Vault
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/hexagolab/db", r.URL.Path)
+		assert.Equal(t, "test-token", r.Header.Get("X-Vault-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"data":{"dsn":"postgres://vault"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(server.URL, "test-token")
+	require.NoError(t, err)
+
+	// Act
+	v, err := provider.GetSecret(context.Background(), "secret/hexagolab/db", "dsn")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://vault", v)
+}
@@ -0,0 +1,67 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// VaultProvider lee secretos del motor KV v2 de HashiCorp Vault.
+// El path de la referencia es <mount>/<ruta>: vault://secret/hexagolab/db#dsn.
+type VaultProvider struct {
+	client *vault.Client
+}
+
+// NewVaultProvider es el constructor. Si 'token' está vacío se usa VAULT_TOKEN del entorno.
+func NewVaultProvider(addr, token string) (*VaultProvider, error) {
+	cfg := vault.DefaultConfig()
+	if addr != "" {
+		cfg.Address = addr
+	}
+
+	client, err := vault.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault client: %w", err)
+	}
+	if token != "" {
+		client.SetToken(token)
+	}
+	return &VaultProvider{client: client}, nil
+}
+
+func (p *VaultProvider) GetSecret(ctx context.Context, path, key string) (string, error) {
+	mount, secretPath, ok := strings.Cut(path, "/")
+	if !ok {
+		return "", fmt.Errorf("vault path must be <mount>/<path>, got %q", path)
+	}
+
+	secret, err := p.client.KVv2(mount).Get(ctx, secretPath)
+	if err != nil {
+		if errors.Is(err, vault.ErrSecretNotFound) {
+			return "", ErrSecretNotFound
+		}
+		return "", err
+	}
+
+	// Sin clave explícita, se acepta un secreto con un único campo.
+	if key == "" {
+		if len(secret.Data) != 1 {
+			return "", fmt.Errorf("vault secret %s has %d fields, specify one with #key", path, len(secret.Data))
+		}
+		for _, v := range secret.Data {
+			return fmt.Sprint(v), nil
+		}
+	}
+
+	v, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %q in %s", ErrSecretNotFound, key, path)
+	}
+	return fmt.Sprint(v), nil
+}
+
+// Verificación estática de la interfaz.
+var _ Provider = (*VaultProvider)(nil)
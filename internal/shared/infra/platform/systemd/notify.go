@@ -0,0 +1,39 @@
+// Package systemd implementa el protocolo sd_notify, con el que un servicio de tipo notify avisa
+// a systemd de que está listo o de que se está parando. Sin NOTIFY_SOCKET (fuera de systemd) no
+// hace nada, así que se puede llamar siempre.
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// Estados que entiende systemd (ver sd_notify(3)).
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+)
+
+// Notify envía 'state' al socket de NOTIFY_SOCKET. Devuelve false si no hay socket configurado.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	// Los sockets abstractos de Linux se indican con '@' y empiezan por un byte nulo.
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to NOTIFY_SOCKET: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, fmt.Errorf("failed to notify systemd: %w", err)
+	}
+	return true, nil
+}
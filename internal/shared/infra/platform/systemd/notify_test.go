@@ -0,0 +1,48 @@
+package systemd
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	// Arrange: un socket que hace de systemd.
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	// Act
+	sent, err := Notify(Ready)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, sent)
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "READY=1", string(buf[:n]))
+}
+
+func TestNotify_WithoutSystemd(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	sent, err := Notify(Ready)
+
+	assert.NoError(t, err)
+	assert.False(t, sent)
+}
+
+func TestNotify_SocketGone(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing.sock"))
+
+	sent, err := Notify(Stopping)
+
+	assert.ErrorContains(t, err, "NOTIFY_SOCKET")
+	assert.False(t, sent)
+}
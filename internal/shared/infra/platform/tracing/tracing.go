@@ -0,0 +1,213 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/davicafu/hexagolab/pkg/logger"
+)
+
+// Cabeceras con las que viaja el contexto, tanto en HTTP como en los mensajes del bus.
+const (
+	HeaderCorrelationID = "X-Correlation-ID"
+	HeaderRequestID     = "X-Request-ID" // Alternativa aceptada en la entrada
+	HeaderTraceParent   = "traceparent"  // W3C Trace Context
+	HeaderCausationID   = "X-Causation-ID"
+	HeaderActor         = "X-Actor"
+	HeaderTenantID      = "X-Tenant-ID"
+)
+
+// Context identifica la petición que originó una operación: el correlation-id que ven los
+// clientes y la traza W3C (trace-id compartido, span-id propio de cada salto). Lleva además la
+// cadena causal: quién hizo la petición de origen (Actor) y el evento o petición que provocó
+// directamente la operación (CausationID). TenantID es el inquilino al que pertenece la
+// operación en un despliegue compartido; vacío es el inquilino por defecto.
+type Context struct {
+	CorrelationID string
+	TraceID       string
+	SpanID        string
+	CausationID   string
+	Actor         string
+	TenantID      string
+}
+
+type ctxKey struct{}
+
+// New crea un contexto de traza nuevo: correlation-id, trace-id y span-id aleatorios.
+func New() Context {
+	return Context{
+		CorrelationID: uuid.NewString(),
+		TraceID:       randomHex(16),
+		SpanID:        randomHex(8),
+	}
+}
+
+// Child devuelve un nuevo salto de la misma traza: conserva el trace-id y genera un span-id.
+func (tc Context) Child() Context {
+	tc.SpanID = randomHex(8)
+	return tc
+}
+
+// IsZero indica si no hay contexto de traza ni cadena causal.
+func (tc Context) IsZero() bool {
+	return tc.CorrelationID == "" && tc.TraceID == "" && tc.CausationID == "" && tc.Actor == "" && tc.TenantID == ""
+}
+
+// TraceParent formatea la cabecera traceparent (versión 00, muestreado).
+func (tc Context) TraceParent() string {
+	if tc.TraceID == "" || tc.SpanID == "" {
+		return ""
+	}
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-01"
+}
+
+// Headers devuelve las cabeceras a propagar; omite las vacías.
+func (tc Context) Headers() map[string]string {
+	headers := make(map[string]string, 5)
+	if tc.CorrelationID != "" {
+		headers[HeaderCorrelationID] = tc.CorrelationID
+	}
+	if tp := tc.TraceParent(); tp != "" {
+		headers[HeaderTraceParent] = tp
+	}
+	if tc.CausationID != "" {
+		headers[HeaderCausationID] = tc.CausationID
+	}
+	if tc.Actor != "" {
+		headers[HeaderActor] = tc.Actor
+	}
+	if tc.TenantID != "" {
+		headers[HeaderTenantID] = tc.TenantID
+	}
+	return headers
+}
+
+// FromHeaders reconstruye el contexto a partir de las cabeceras recibidas. Las ausentes o
+// mal formadas quedan vacías; usa FromHeadersOrNew para completar lo que falte.
+func FromHeaders(get func(key string) string) Context {
+	var tc Context
+	tc.CorrelationID = get(HeaderCorrelationID)
+	if tc.CorrelationID == "" {
+		tc.CorrelationID = get(HeaderRequestID)
+	}
+	tc.TraceID, tc.SpanID = parseTraceParent(get(HeaderTraceParent))
+	tc.CausationID = get(HeaderCausationID)
+	tc.Actor = get(HeaderActor)
+	tc.TenantID = get(HeaderTenantID)
+	return tc
+}
+
+// FromHeadersOrNew es FromHeaders completando con valores nuevos lo que no venga en la entrada.
+// Si llega una traza se continúa con un span propio.
+func FromHeadersOrNew(get func(key string) string) Context {
+	tc := FromHeaders(get)
+	fresh := New()
+	if tc.CorrelationID == "" {
+		tc.CorrelationID = fresh.CorrelationID
+	}
+	if tc.TraceID == "" {
+		tc.TraceID = fresh.TraceID
+	}
+	tc.SpanID = fresh.SpanID
+	return tc
+}
+
+// FromMap es FromHeaders sobre un mapa de cabeceras (ej. los metadatos del outbox).
+func FromMap(m map[string]string) Context {
+	return FromHeaders(func(key string) string { return m[key] })
+}
+
+// NewContext asocia el contexto de traza a ctx, y sus identificadores a los campos de log del
+// contexto (ver logger.FromContext).
+func NewContext(ctx context.Context, tc Context) context.Context {
+	ctx = logger.WithFields(ctx, Fields(tc)...)
+	return context.WithValue(ctx, ctxKey{}, tc)
+}
+
+// FromContext devuelve el contexto de traza asociado a ctx, si lo hay.
+func FromContext(ctx context.Context) (Context, bool) {
+	tc, ok := ctx.Value(ctxKey{}).(Context)
+	return tc, ok && !tc.IsZero()
+}
+
+// Metadata devuelve las cabeceras de la traza de ctx para guardarlas junto a un evento del
+// outbox; sin traza devuelve un mapa vacío.
+func Metadata(ctx context.Context) map[string]string {
+	tc, _ := FromContext(ctx)
+	return tc.Headers()
+}
+
+// Logger devuelve 'log' con los identificadores de la traza de ctx como campos, para que los
+// logs de consumidores y workers se puedan cruzar con los de la petición HTTP de origen.
+func Logger(ctx context.Context, log *zap.Logger) *zap.Logger {
+	return logger.FromContext(ctx, log)
+}
+
+// Fields devuelve los campos de log que identifican la traza.
+func Fields(tc Context) []zap.Field {
+	fields := make([]zap.Field, 0, 6)
+	if tc.CorrelationID != "" {
+		fields = append(fields, zap.String("correlation_id", tc.CorrelationID))
+	}
+	if tc.TraceID != "" {
+		fields = append(fields, zap.String("trace_id", tc.TraceID), zap.String("span_id", tc.SpanID))
+	}
+	if tc.CausationID != "" {
+		fields = append(fields, zap.String("causation_id", tc.CausationID))
+	}
+	if tc.Actor != "" {
+		fields = append(fields, zap.String("actor", tc.Actor))
+	}
+	if tc.TenantID != "" {
+		fields = append(fields, zap.String("tenant_id", tc.TenantID))
+	}
+	return fields
+}
+
+// TenantID devuelve el inquilino del contexto de traza de ctx ("" es el inquilino por defecto).
+func TenantID(ctx context.Context) string {
+	tc, _ := FromContext(ctx)
+	return tc.TenantID
+}
+
+// ValidTenantID indica si 'id' sirve como identificador de inquilino: de 1 a 64 letras, dígitos,
+// guiones o guiones bajos, para que se pueda usar como prefijo de un topic de Kafka.
+func ValidTenantID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTraceParent extrae trace-id y span-id de "00-<32 hex>-<16 hex>-<2 hex>".
+func parseTraceParent(v string) (traceID, spanID string) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return "", ""
+	}
+	if !isHex(parts[1]) || !isHex(parts[2]) || strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return "", ""
+	}
+	return strings.ToLower(parts[1]), strings.ToLower(parts[2])
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
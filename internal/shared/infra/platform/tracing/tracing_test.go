@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func headers(m map[string]string) func(string) string {
+	return func(key string) string { return m[key] }
+}
+
+func TestFromHeaders(t *testing.T) {
+	t.Run("valid traceparent", func(t *testing.T) {
+		tc := FromHeaders(headers(map[string]string{
+			HeaderCorrelationID: "req-1",
+			HeaderTraceParent:   "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		}))
+
+		assert.Equal(t, "req-1", tc.CorrelationID)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", tc.SpanID)
+	})
+
+	t.Run("request id fallback and invalid traceparent", func(t *testing.T) {
+		tc := FromHeaders(headers(map[string]string{
+			HeaderRequestID:   "req-2",
+			HeaderTraceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		}))
+
+		assert.Equal(t, "req-2", tc.CorrelationID)
+		assert.Empty(t, tc.TraceID, "Un trace-id de ceros no es válido")
+	})
+}
+
+func TestFromHeadersOrNew(t *testing.T) {
+	// Arrange
+	incoming := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	// Act
+	fresh := FromHeadersOrNew(headers(nil))
+	continued := FromHeadersOrNew(headers(map[string]string{HeaderTraceParent: incoming}))
+
+	// Assert
+	assert.NotEmpty(t, fresh.CorrelationID)
+	assert.Len(t, fresh.TraceID, 32)
+	assert.Len(t, fresh.SpanID, 16)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", continued.TraceID, "Se continúa la traza recibida")
+	assert.NotEqual(t, "00f067aa0ba902b7", continued.SpanID, "Con un span propio")
+	assert.NotEmpty(t, continued.CorrelationID)
+}
+
+func TestHeadersRoundTrip(t *testing.T) {
+	// Arrange
+	tc := New()
+	tc.CausationID = "evt-1"
+	tc.Actor = "ana"
+	tc.TenantID = "acme"
+
+	// Act
+	got := FromMap(tc.Headers())
+
+	// Assert
+	assert.Equal(t, tc, got)
+	assert.Equal(t, tc.TraceID, tc.Child().TraceID)
+	assert.NotEqual(t, tc.SpanID, tc.Child().SpanID)
+	assert.Equal(t, "ana", tc.Child().Actor, "La cadena causal se conserva entre saltos")
+	assert.Equal(t, "acme", TenantID(NewContext(context.Background(), tc.Child())))
+}
+
+func TestValidTenantID(t *testing.T) {
+	assert.True(t, ValidTenantID("acme_eu-1"))
+	assert.False(t, ValidTenantID(""))
+	assert.False(t, ValidTenantID("acme.eu"), "Un punto haría ambiguo el prefijo del topic")
+	assert.False(t, ValidTenantID(strings.Repeat("a", 65)))
+}
+
+func TestContextAndLogger(t *testing.T) {
+	// Arrange
+	core, logs := observer.New(zapcore.InfoLevel)
+	tc := New()
+	ctx := NewContext(context.Background(), tc)
+
+	// Act
+	Logger(ctx, zap.New(core)).Info("hola")
+	Logger(context.Background(), zap.New(core)).Info("sin traza")
+
+	// Assert
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, tc.CorrelationID, entries[0].ContextMap()["correlation_id"])
+	assert.Equal(t, tc.TraceID, entries[0].ContextMap()["trace_id"])
+	assert.Empty(t, entries[1].Context)
+
+	assert.Equal(t, tc.Headers(), Metadata(ctx))
+	assert.Empty(t, Metadata(context.Background()))
+}
@@ -0,0 +1,132 @@
+// Package webhook firma los eventos que la aplicación envía por HTTP a sistemas externos y
+// ofrece a los receptores la verificación correspondiente. Cada envío lleva la cabecera
+// HeaderSignature con la marca de tiempo del envío y un HMAC-SHA256 de "<timestamp>.<cuerpo>"
+// calculado con el secreto de la suscripción:
+//
+//	X-Hexagolab-Signature: t=1735689600,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd
+//
+// Firmar la marca de tiempo junto al cuerpo impide reenviar una petición capturada pasado el
+// margen de tolerancia (DefaultTolerance). Dentro del margen, el receptor debe descartar los
+// eventos repetidos por su event_id, que también protege de los reintentos legítimos.
+//
+// Aún no hay un subsistema de entrega de webhooks: el paquete fija el formato para el que se
+// construya y para los receptores.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderSignature es la cabecera con la firma de un envío.
+const HeaderSignature = "X-Hexagolab-Signature"
+
+// DefaultTolerance es la diferencia máxima entre la marca de tiempo firmada y el reloj del
+// receptor: cubre el desfase de relojes y la latencia de la entrega.
+const DefaultTolerance = 5 * time.Minute
+
+// signatureScheme es la versión del esquema de firma. Un esquema nuevo se añadiría como otro
+// par (v2=...) sin romper a los receptores que solo conocen v1.
+const signatureScheme = "v1"
+
+var (
+	// ErrMissingSignature indica que la petición no trae la cabecera o le falta t o v1.
+	ErrMissingSignature = errors.New("missing webhook signature")
+	// ErrInvalidSignature indica que ninguna firma coincide con la de los secretos del receptor.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrTimestampOutOfTolerance indica una firma fuera del margen de tolerancia: una petición
+	// antigua reenviada o un reloj muy desfasado.
+	ErrTimestampOutOfTolerance = errors.New("webhook timestamp outside the tolerance window")
+)
+
+// Sign devuelve el valor de HeaderSignature para enviar 'payload' en el instante 'at' con el
+// secreto de la suscripción.
+func Sign(secret []byte, at time.Time, payload []byte) string {
+	timestamp := at.Unix()
+	return fmt.Sprintf("t=%d,%s=%s", timestamp, signatureScheme, hex.EncodeToString(mac(secret, timestamp, payload)))
+}
+
+// SignRequest añade a 'req' la firma de 'payload', que debe ser su cuerpo.
+func SignRequest(req *http.Request, secret []byte, at time.Time, payload []byte) {
+	req.Header.Set(HeaderSignature, Sign(secret, at, payload))
+}
+
+// Verify comprueba que 'header' (el valor de HeaderSignature) firma 'payload' con alguno de
+// 'secrets' y que su marca de tiempo no se aleja de 'now' más de 'tolerance' (DefaultTolerance si
+// es 0). Admitir varios secretos permite rotarlos: durante la rotación, el emisor puede enviar una
+// firma v1 por secreto y el receptor aceptar el nuevo y el antiguo.
+func Verify(header string, payload []byte, secrets [][]byte, now time.Time, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	timestamp, signatures, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: signed at %s", ErrTimestampOutOfTolerance, time.Unix(timestamp, 0).UTC().Format(time.RFC3339))
+	}
+	for _, secret := range secrets {
+		expected := mac(secret, timestamp, payload)
+		for _, signature := range signatures {
+			if hmac.Equal(signature, expected) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// VerifyRequest comprueba la firma de 'req', cuyo cuerpo ya leído es 'payload' (ver Verify).
+func VerifyRequest(req *http.Request, payload []byte, secrets [][]byte, now time.Time, tolerance time.Duration) error {
+	return Verify(req.Header.Get(HeaderSignature), payload, secrets, now, tolerance)
+}
+
+// parseHeader lee la marca de tiempo y las firmas v1 de la cabecera; ignora los esquemas que no
+// conoce.
+func parseHeader(header string) (int64, [][]byte, error) {
+	var (
+		timestamp  int64
+		hasTime    bool
+		signatures [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, nil, fmt.Errorf("%w: malformed timestamp %q", ErrMissingSignature, value)
+			}
+			timestamp, hasTime = t, true
+		case signatureScheme:
+			// Una firma mal codificada no puede coincidir: se descarta sin rechazar las demás.
+			if signature, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+	if !hasTime || len(signatures) == 0 {
+		return 0, nil, ErrMissingSignature
+	}
+	return timestamp, signatures, nil
+}
+
+// mac es el HMAC-SHA256 de "<timestamp>.<payload>".
+func mac(secret []byte, timestamp int64, payload []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	h.Write([]byte("."))
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign_KnownVector(t *testing.T) {
+	// Arrange
+	at := time.Unix(1735689600, 0)
+
+	// Act
+	header := Sign([]byte("whsec_test"), at, []byte(`{"type":"task.created"}`))
+
+	// Assert: el receptor la reproduce con cualquier librería de HMAC-SHA256
+	h := hmac.New(sha256.New, []byte("whsec_test"))
+	h.Write([]byte(`1735689600.{"type":"task.created"}`))
+	assert.Equal(t, "t=1735689600,v1="+hex.EncodeToString(h.Sum(nil)), header)
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("whsec_current")
+	payload := []byte(`{"event_id":"7b0c","type":"task.created"}`)
+	signedAt := time.Unix(1735689600, 0)
+	header := Sign(secret, signedAt, payload)
+
+	tests := []struct {
+		name    string
+		header  string
+		payload []byte
+		secrets [][]byte
+		now     time.Time
+		wantErr error
+	}{
+		{"valid", header, payload, [][]byte{secret}, signedAt.Add(time.Minute), nil},
+		{"rotated secret", header, payload, [][]byte{[]byte("whsec_new"), secret}, signedAt, nil},
+		{"tampered payload", header, []byte(`{"event_id":"7b0c","type":"task.deleted"}`), [][]byte{secret}, signedAt, ErrInvalidSignature},
+		{"wrong secret", header, payload, [][]byte{[]byte("whsec_other")}, signedAt, ErrInvalidSignature},
+		{"replayed late", header, payload, [][]byte{secret}, signedAt.Add(DefaultTolerance + time.Second), ErrTimestampOutOfTolerance},
+		{"from the future", header, payload, [][]byte{secret}, signedAt.Add(-DefaultTolerance - time.Second), ErrTimestampOutOfTolerance},
+		{"missing header", "", payload, [][]byte{secret}, signedAt, ErrMissingSignature},
+		{"missing signature", "t=1735689600", payload, [][]byte{secret}, signedAt, ErrMissingSignature},
+		{"malformed timestamp", strings.Replace(header, "t=1735689600", "t=yesterday", 1), payload, [][]byte{secret}, signedAt, ErrMissingSignature},
+		{"unknown scheme next to v1", "v2=abc," + header, payload, [][]byte{secret}, signedAt, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := Verify(tt.header, tt.payload, tt.secrets, tt.now, 0)
+
+			// Assert
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignRequest_VerifyRequest(t *testing.T) {
+	// Arrange
+	secret := []byte("whsec_test")
+	payload := []byte(`{"type":"user.created"}`)
+	now := time.Now()
+	req := httptest.NewRequest("POST", "https://receiver.example.com/hooks", bytes.NewReader(payload))
+
+	// Act
+	SignRequest(req, secret, now, payload)
+	err := VerifyRequest(req, payload, [][]byte{secret}, now, time.Minute)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(req.Header.Get(HeaderSignature), "t="))
+}
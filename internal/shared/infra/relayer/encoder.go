@@ -0,0 +1,127 @@
+package relayer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedDomainEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+)
+
+// ErrUnknownEventType indica que el tipo del evento no está en el registro.
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// PayloadError es el error de un payload que nunca se podrá publicar: no se puede descomprimir,
+// no cumple el esquema de su tipo o no se decodifica a él. Es un fallo del productor.
+type PayloadError struct {
+	Err error
+}
+
+func (e *PayloadError) Error() string { return e.Err.Error() }
+func (e *PayloadError) Unwrap() error { return e.Err }
+
+// Encoder convierte los eventos del outbox en los sobres que se publican en el bus. Lo comparten
+// el Worker y el change feed, para que ambos entreguen el mismo evento.
+type Encoder struct {
+	registry map[string]sharedDomainEvents.EventMetadata
+	// keys descifra los campos cifrados de los payloads (ver SetDecryption); nil no descifra.
+	keys *encryption.Keyring
+}
+
+// NewEncoder crea un Encoder para los tipos de evento de 'registry'.
+func NewEncoder(registry map[string]sharedDomainEvents.EventMetadata) *Encoder {
+	return &Encoder{registry: registry}
+}
+
+// SetDecryption descifra con 'keys', antes de validarlos, los campos de los payloads que el
+// repositorio guardó cifrados en el outbox.
+func (e *Encoder) SetDecryption(keys *encryption.Keyring) {
+	e.keys = keys
+}
+
+// Encode devuelve el sobre de 'evt' publicado en 'now', con la cadena causal de la petición de
+// origen y el payload con la forma exacta del contrato de su tipo. Devuelve ErrUnknownEventType
+// si el tipo no está registrado, un *PayloadError si el payload no es publicable y, si no se
+// puede descifrar, el error de descifrado.
+func (e *Encoder) Encode(evt sharedDomain.OutboxEvent, now time.Time) (sharedDomainEvents.IntegrationEvent, error) {
+	metadata, ok := e.registry[evt.EventType]
+	if !ok {
+		return sharedDomainEvents.IntegrationEvent{}, fmt.Errorf("%w: %s", ErrUnknownEventType, evt.EventType)
+	}
+
+	payloadBytes, err := compression.OutboxPayload(evt)
+	if err != nil {
+		return sharedDomainEvents.IntegrationEvent{}, &PayloadError{Err: err}
+	}
+	if e.keys != nil {
+		if payloadBytes, err = e.keys.DecryptJSON(payloadBytes); err != nil {
+			return sharedDomainEvents.IntegrationEvent{}, fmt.Errorf("decrypt payload: %w", err)
+		}
+	}
+	mode := sharedDomainEvents.PayloadMode(evt.Metadata[sharedDomain.MetadataPayloadMode])
+	data, err := encodePayload(metadata, mode, payloadBytes)
+	if err != nil {
+		return sharedDomainEvents.IntegrationEvent{}, &PayloadError{Err: err}
+	}
+
+	tc := tracing.FromMap(evt.Metadata)
+	envelope := sharedDomainEvents.IntegrationEvent{
+		EventID:       evt.ID,
+		Type:          evt.EventType,
+		Timestamp:     now.UTC(),
+		OccurredAt:    evt.CreatedAt,
+		CorrelationID: tc.CorrelationID,
+		CausationID:   tc.CausationID,
+		Actor:         tc.Actor,
+		TenantID:      tc.TenantID,
+		Data:          data,
+		PayloadMode:   mode,
+	}.WithPartitionKey(evt.AggregateID)
+	if evt.IsTombstone() {
+		envelope = envelope.AsTombstone()
+	}
+	return envelope, nil
+}
+
+// encodePayload valida el payload con el esquema de su tipo y devuelve los datos del sobre. Un
+// payload completo se vuelve a serializar con la forma exacta del contrato del tipo; uno parcial
+// (delta o solo el id) se valida sin exigir los campos obligatorios y se publica tal cual, porque
+// pasarlo por el tipo rellenaría con ceros los campos que no lleva.
+func encodePayload(metadata sharedDomainEvents.EventMetadata, mode sharedDomainEvents.PayloadMode, payload []byte) ([]byte, error) {
+	if mode.Partial() {
+		if metadata.Schema != nil {
+			if err := metadata.Schema.ValidatePartial(payload); err != nil {
+				return nil, err
+			}
+		}
+		var data bytes.Buffer
+		if err := json.Compact(&data, payload); err != nil {
+			return nil, fmt.Errorf("decode payload: %w", err)
+		}
+		return data.Bytes(), nil
+	}
+
+	if metadata.Schema != nil {
+		if err := metadata.Schema.Validate(payload); err != nil {
+			return nil, err
+		}
+	}
+	// Creamos una nueva instancia del tipo de evento (ej: &userDomain.User{}); al volver a
+	// serializarla el payload sale con la forma exacta del contrato del tipo.
+	eventPayload := reflect.New(metadata.Type).Interface()
+	if err := json.Unmarshal(payload, eventPayload); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+	data, err := json.Marshal(eventPayload)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload: %w", err)
+	}
+	return data, nil
+}
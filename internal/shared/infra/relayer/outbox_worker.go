@@ -2,25 +2,36 @@ package relayer
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"reflect"
+	"sync"
 	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
 	sharedDomainEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
 	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	"go.uber.org/zap"
 )
 
 // Worker procesa eventos pendientes de la tabla outbox de forma genérica.
 type Worker struct {
-	repo          sharedDomain.OutboxRepository
-	publisher     sharedBus.EventBus
-	eventRegistry map[string]sharedDomainEvents.EventMetadata
+	repo      sharedDomain.OutboxRepository
+	publisher sharedBus.EventBus
+	encoder   *Encoder
+	clock     sharedDomain.Clock
+	log       *zap.Logger
+
+	// interval y batchSize se pueden cambiar en caliente (ver SetInterval y SetBatchSize).
+	mu            sync.Mutex
 	interval      time.Duration
 	batchSize     int
-	log           *zap.Logger
+	resetInterval chan time.Duration
+
+	// polled se cierra tras el primer polling en el que se pudo leer el outbox.
+	polled     chan struct{}
+	polledOnce sync.Once
 }
 
 func NewOutboxWorker(
@@ -29,31 +40,83 @@ func NewOutboxWorker(
 	registry map[string]sharedDomainEvents.EventMetadata,
 	interval time.Duration,
 	batchSize int,
+	clock sharedDomain.Clock,
 	log *zap.Logger,
 ) *Worker {
 	return &Worker{
 		repo:          repo,
 		publisher:     publisher,
-		eventRegistry: registry,
+		encoder:       NewEncoder(registry),
+		clock:         clock,
 		interval:      interval,
 		batchSize:     batchSize,
+		resetInterval: make(chan time.Duration, 1),
+		polled:        make(chan struct{}),
 		log:           log,
 	}
 }
 
-// Start inicia el bucle de polling del worker.
+// SetDecryption descifra con 'keys', antes de validarlos y publicarlos, los campos de los
+// payloads que el repositorio guardó cifrados en el outbox. Se llama antes de Start.
+func (w *Worker) SetDecryption(keys *encryption.Keyring) {
+	w.encoder.SetDecryption(keys)
+}
+
+// SetInterval cambia el intervalo de polling; se aplica en el siguiente tick.
+func (w *Worker) SetInterval(interval time.Duration) {
+	w.mu.Lock()
+	w.interval = interval
+	w.mu.Unlock()
+
+	// Descartamos un cambio anterior aún no aplicado: solo importa el último.
+	select {
+	case <-w.resetInterval:
+	default:
+	}
+	w.resetInterval <- interval
+}
+
+// SetBatchSize cambia el número máximo de eventos procesados por lote.
+func (w *Worker) SetBatchSize(batchSize int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.batchSize = batchSize
+}
+
+func (w *Worker) currentBatchSize() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.batchSize
+}
+
+// Polled devuelve un canal que se cierra tras el primer polling en el que se pudo leer el
+// outbox, para no dar el servicio por listo mientras su almacenamiento no responde.
+func (w *Worker) Polled() <-chan struct{} {
+	return w.polled
+}
+
+// Start inicia el bucle de polling del worker. El primer polling se hace nada más arrancar.
 func (w *Worker) Start(ctx context.Context) {
-	ticker := time.NewTicker(w.interval)
+	w.mu.Lock()
+	interval := w.interval
+	w.mu.Unlock()
+
+	// El ticker sale del reloj inyectado para que los tests controlen el polling.
+	ticker := w.clock.NewTicker(interval)
 	defer ticker.Stop()
 
-	w.log.Info("🚀 Outbox worker iniciado", zap.Duration("interval", w.interval))
+	w.log.Info("🚀 Outbox worker iniciado", zap.Duration("interval", interval))
+	w.ProcessBatch(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
 			w.log.Info("🛑 Outbox worker detenido.")
 			return
-		case <-ticker.C:
+		case interval := <-w.resetInterval:
+			ticker.Reset(interval)
+			w.log.Info("🔧 Intervalo del outbox worker actualizado", zap.Duration("interval", interval))
+		case <-ticker.C():
 			w.log.Info("🔄 Ejecutando polling de outbox")
 			w.ProcessBatch(ctx)
 		}
@@ -61,55 +124,97 @@ func (w *Worker) Start(ctx context.Context) {
 }
 
 func (w *Worker) ProcessBatch(ctx context.Context) {
-	events, err := w.repo.FetchPendingOutbox(ctx, w.batchSize)
+	events, err := w.repo.FetchPendingOutbox(ctx, w.currentBatchSize())
 	if err != nil {
 		w.log.Warn("⚠️ Error al obtener eventos pendientes", zap.Error(err))
 		return
 	}
+	w.polledOnce.Do(func() { close(w.polled) })
 	if len(events) > 0 {
 		w.log.Info(fmt.Sprintf("📬 %d eventos encontrados para procesar", len(events)))
 	}
 
+	// Con un publicador asíncrono los envíos del lote van en paralelo; el lote termina cuando
+	// todos se han confirmado, para no volver a leer eventos que siguen en vuelo.
+	var inFlight sync.WaitGroup
 	for _, evt := range events {
-		w.publishAndMark(ctx, evt)
+		inFlight.Add(1)
+		w.publishAndMark(ctx, evt, inFlight.Done)
 	}
+	inFlight.Wait()
 }
 
-func (w *Worker) publishAndMark(ctx context.Context, evt sharedDomain.OutboxEvent) {
-	// 1. Usar el registro para decodificar el payload al tipo de evento correcto
-	metadata, ok := w.eventRegistry[evt.EventType]
-	if !ok {
-		w.log.Error("Tipo de evento desconocido en registro", zap.String("event_type", evt.EventType))
-		// Opcional: Marcar como procesado para no reintentar indefinidamente
-		// w.repo.MarkOutboxProcessed(ctx, evt.ID)
-		return
+// publishAndMark publica el evento y lo marca como procesado solo cuando el bus confirma la
+// entrega. Llama a 'finish' al terminar, se haya publicado o no.
+func (w *Worker) publishAndMark(ctx context.Context, evt sharedDomain.OutboxEvent, finish func()) {
+	// Recuperamos la traza de la petición que generó el evento para que viaje con el mensaje.
+	tc := tracing.FromMap(evt.Metadata)
+	if !tc.IsZero() {
+		ctx = tracing.NewContext(ctx, tc.Child())
 	}
+	log := tracing.Logger(ctx, w.log)
 
-	// Creamos una nueva instancia del tipo de evento (ej: &userDomain.User{})
-	eventPayload := reflect.New(metadata.Type).Interface()
-
-	payloadBytes, _ := json.Marshal(evt.Payload)
-	if err := json.Unmarshal(payloadBytes, eventPayload); err != nil {
-		w.log.Error("Error al decodificar payload del evento", zap.String("event_id", evt.ID.String()), zap.Error(err))
+	// 1. Construir el sobre con el payload en la forma del contrato de su tipo
+	envelope, err := w.encoder.Encode(evt, w.clock.Now())
+	var payloadErr *PayloadError
+	switch {
+	case errors.Is(err, ErrUnknownEventType):
+		log.Error("Tipo de evento desconocido en registro", zap.String("event_type", evt.EventType))
+		finish()
+		return
+	case errors.As(err, &payloadErr):
+		// Reintentarlo no lo arregla, así que se aparta en vez de publicarlo.
+		w.deadLetter(ctx, log, evt, payloadErr.Err)
+		finish()
+		return
+	case err != nil:
+		// Sin la clave con la que se cifró no se puede publicar, pero tampoco es un fallo del
+		// productor: el evento sigue pendiente hasta que se configure.
+		log.Error("No se pudo descifrar el payload del evento", zap.String("event_id", evt.ID.String()), zap.Error(err))
+		finish()
 		return
 	}
 
-	// 2. Publicar el evento fuertemente tipado
-	if err := w.publisher.Publish(ctx, eventPayload); err != nil {
-		w.log.Warn("⚠️ No se pudo publicar evento",
-			zap.String("event_id", evt.ID.String()),
-			zap.Error(err),
-		)
-		return // No lo marcamos como procesado para que se reintente
+	// 2. Publicar el evento
+	sharedBus.PublishAsync(ctx, w.publisher, envelope, func(err error) {
+		defer finish()
+		if err != nil {
+			log.Warn("⚠️ No se pudo publicar evento",
+				zap.String("event_id", evt.ID.String()),
+				zap.Error(err),
+			)
+			return // No lo marcamos como procesado para que se reintente
+		}
+
+		// 3. Marcar como procesado en la DB, ya con la entrega confirmada
+		if err := w.repo.MarkOutboxProcessed(ctx, evt.ID); err != nil {
+			log.Warn("⚠️ No se pudo marcar evento como procesado",
+				zap.String("event_id", evt.ID.String()),
+				zap.Error(err),
+			)
+		} else {
+			log.Info("✅ Evento publicado y marcado", zap.String("event_id", evt.ID.String()))
+		}
+	})
+}
+
+// deadLetter aparta un evento que no se puede publicar, si el outbox lo admite. Si no, el evento
+// sigue pendiente y se vuelve a intentar (y a registrar) en cada polling.
+func (w *Worker) deadLetter(ctx context.Context, log *zap.Logger, evt sharedDomain.OutboxEvent, cause error) {
+	fields := []zap.Field{
+		zap.String("event_id", evt.ID.String()),
+		zap.String("event_type", evt.EventType),
+		zap.Error(cause),
 	}
 
-	// 3. Marcar como procesado en la DB
-	if err := w.repo.MarkOutboxProcessed(ctx, evt.ID); err != nil {
-		w.log.Warn("⚠️ No se pudo marcar evento como procesado",
-			zap.String("event_id", evt.ID.String()),
-			zap.Error(err),
-		)
-	} else {
-		w.log.Info("✅ Evento publicado y marcado", zap.String("event_id", evt.ID.String()))
+	deadLetterer, ok := w.repo.(sharedDomain.OutboxDeadLetterer)
+	if !ok {
+		log.Error("Payload del evento no publicable; queda pendiente", fields...)
+		return
+	}
+	if err := deadLetterer.MarkOutboxDeadLetter(ctx, evt.ID, cause.Error()); err != nil {
+		log.Warn("⚠️ No se pudo apartar evento no publicable", append(fields, zap.NamedError("dead_letter_error", err))...)
+		return
 	}
+	log.Error("☠️ Evento no publicable apartado como dead letter", fields...)
 }
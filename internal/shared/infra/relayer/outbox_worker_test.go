@@ -2,15 +2,22 @@ package relayer
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
 	sharedDomainEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
-	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 
@@ -26,7 +33,7 @@ func TestOutboxWorker_ProcessBatch_Success(t *testing.T) {
 	testEvent := sharedDomain.OutboxEvent{
 		ID:        eventID,
 		EventType: userDomain.UserCreated, // Usamos la constante del dominio
-		Payload:   map[string]interface{}{"id": "some-uuid", "email": "test@example.com"},
+		Payload:   map[string]interface{}{"id": uuid.New().String(), "email": "test@example.com"},
 	}
 
 	// ✅ Creamos el registro con el struct EventMetadata correcto.
@@ -39,10 +46,10 @@ func TestOutboxWorker_ProcessBatch_Success(t *testing.T) {
 
 	// ✅ Definimos las expectativas con la nueva firma de Publish.
 	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{testEvent}, nil).Once()
-	publisher.On("Publish", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+	publisher.On("Publish", mock.Anything, mock.AnythingOfType("events.IntegrationEvent")).Return(nil).Once()
 	repo.On("MarkOutboxProcessed", mock.Anything, eventID).Return(nil).Once()
 
-	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, zap.NewNop())
+	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
 
 	// ACT
 	worker.ProcessBatch(context.Background())
@@ -71,7 +78,7 @@ func TestOutboxWorker_ProcessBatch_PublisherFails(t *testing.T) {
 	// ✅ Simulamos el fallo de Publish con la nueva firma.
 	publisher.On("Publish", mock.Anything, mock.Anything).Return(errors.New("kafka is down")).Once()
 
-	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, zap.NewNop())
+	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
 
 	// ACT
 	worker.ProcessBatch(context.Background())
@@ -82,6 +89,212 @@ func TestOutboxWorker_ProcessBatch_PublisherFails(t *testing.T) {
 	repo.AssertNotCalled(t, "MarkOutboxProcessed", mock.Anything, mock.Anything)
 }
 
+// asyncPublisher confirma las entregas cuando el test lo decide, como un writer de Kafka asíncrono.
+type asyncPublisher struct {
+	mocks.MockPublisher
+	dones chan func(error)
+}
+
+func (p *asyncPublisher) PublishAsync(_ context.Context, _ interface{}, done func(error)) {
+	p.dones <- done
+}
+
+func TestOutboxWorker_ProcessBatch_AsyncPublisher_MarksOnlyDelivered(t *testing.T) {
+	// ARRANGE
+	repo := new(mocks.MockOutboxRepository)
+	publisher := &asyncPublisher{dones: make(chan func(error), 2)}
+
+	delivered := sharedDomain.OutboxEvent{ID: uuid.New(), EventType: userDomain.UserCreated, Payload: map[string]interface{}{}}
+	failed := sharedDomain.OutboxEvent{ID: uuid.New(), EventType: userDomain.UserCreated, Payload: map[string]interface{}{}}
+	registry := map[string]sharedDomainEvents.EventMetadata{
+		userDomain.UserCreated: {Type: reflect.TypeOf(userDomain.User{}), Topic: userDomain.UserTopic},
+	}
+
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{delivered, failed}, nil).Once()
+	repo.On("MarkOutboxProcessed", mock.Anything, delivered.ID).Return(nil).Once()
+
+	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// ACT: los dos eventos se encolan sin esperar a la entrega del primero.
+	finished := make(chan struct{})
+	go func() {
+		worker.ProcessBatch(context.Background())
+		close(finished)
+	}()
+	first, second := <-publisher.dones, <-publisher.dones
+
+	// ASSERT: el lote no termina ni marca nada hasta que el bus confirma.
+	select {
+	case <-finished:
+		t.Fatal("el lote no debe terminar con entregas en vuelo")
+	case <-time.After(20 * time.Millisecond):
+	}
+	repo.AssertNotCalled(t, "MarkOutboxProcessed", mock.Anything, mock.Anything)
+
+	first(nil)
+	second(errors.New("kafka is down"))
+	<-finished
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkOutboxProcessed", mock.Anything, failed.ID)
+}
+
+func TestOutboxWorker_ProcessBatch_PropagatesTrace(t *testing.T) {
+	// ARRANGE: el evento guarda la traza de la petición que lo originó.
+	repo := new(mocks.MockOutboxRepository)
+	publisher := new(mocks.MockPublisher)
+
+	origin := tracing.New()
+	testEvent := sharedDomain.OutboxEvent{
+		ID:        uuid.New(),
+		EventType: userDomain.UserCreated,
+		Payload:   map[string]interface{}{},
+		Metadata:  origin.Headers(),
+	}
+	registry := map[string]sharedDomainEvents.EventMetadata{
+		userDomain.UserCreated: {Type: reflect.TypeOf(userDomain.User{}), Topic: userDomain.UserTopic},
+	}
+
+	sameTrace := mock.MatchedBy(func(ctx context.Context) bool {
+		tc, ok := tracing.FromContext(ctx)
+		return ok && tc.CorrelationID == origin.CorrelationID && tc.TraceID == origin.TraceID
+	})
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{testEvent}, nil).Once()
+	publisher.On("Publish", sameTrace, mock.Anything).Return(nil).Once()
+	repo.On("MarkOutboxProcessed", mock.Anything, testEvent.ID).Return(nil).Once()
+
+	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// ACT
+	worker.ProcessBatch(context.Background())
+
+	// ASSERT
+	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestOutboxWorker_ProcessBatch_PublishesEnvelope(t *testing.T) {
+	// ARRANGE: la petición de origen la provocó otro evento y la hizo un actor conocido.
+	repo := new(mocks.MockOutboxRepository)
+	publisher := new(mocks.MockPublisher)
+
+	origin := tracing.New()
+	origin.CausationID = uuid.NewString()
+	origin.Actor = "ana"
+	origin.TenantID = "acme"
+	userID := uuid.New()
+	createdAt := time.Date(2025, time.March, 1, 10, 30, 0, 0, time.UTC)
+	testEvent := sharedDomain.OutboxEvent{
+		ID:          uuid.New(),
+		AggregateID: userID.String(),
+		EventType:   userDomain.UserCreated,
+		Payload:     map[string]interface{}{"id": userID.String(), "email": "ana@example.com", "extra": "fuera del contrato"},
+		CreatedAt:   createdAt,
+		Metadata:    origin.Headers(),
+	}
+	registry := map[string]sharedDomainEvents.EventMetadata{
+		userDomain.UserCreated: {Type: reflect.TypeOf(userDomain.User{}), Topic: userDomain.UserTopic},
+	}
+
+	var published sharedDomainEvents.IntegrationEvent
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{testEvent}, nil).Once()
+	publisher.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		published = args.Get(1).(sharedDomainEvents.IntegrationEvent)
+	}).Return(nil).Once()
+	repo.On("MarkOutboxProcessed", mock.Anything, testEvent.ID).Return(nil).Once()
+
+	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// ACT
+	worker.ProcessBatch(context.Background())
+
+	// ASSERT
+	assert.Equal(t, testEvent.ID, published.EventID)
+	assert.Equal(t, userDomain.UserCreated, published.Type)
+	assert.Equal(t, createdAt, published.OccurredAt)
+	assert.False(t, published.Timestamp.IsZero())
+	assert.Equal(t, origin.CorrelationID, published.CorrelationID)
+	assert.Equal(t, origin.CausationID, published.CausationID)
+	assert.Equal(t, "ana", published.Actor)
+	assert.Equal(t, "acme", published.TenantID)
+	assert.Equal(t, userID.String(), published.PartitionKey())
+
+	var user userDomain.User
+	assert.NoError(t, json.Unmarshal(published.Data, &user))
+	assert.Equal(t, userID, user.ID)
+	assert.NotContains(t, string(published.Data), "extra", "El payload sale con la forma del contrato")
+}
+
+func TestOutboxWorker_ProcessBatch_DecryptsPayload(t *testing.T) {
+	// ARRANGE: el repositorio guardó el email cifrado en el outbox.
+	repo := new(mocks.MockOutboxRepository)
+	publisher := new(mocks.MockPublisher)
+
+	keys, err := encryption.NewKeyring([]string{"k1:" + base64.StdEncoding.EncodeToString(make([]byte, 32))})
+	assert.NoError(t, err)
+	userID := uuid.New()
+	testEvent := sharedDomain.OutboxEvent{
+		ID:        uuid.New(),
+		EventType: userDomain.UserCreated,
+		Payload:   map[string]interface{}{"id": userID.String(), "email": keys.Encrypt("ana@example.com")},
+	}
+	registry := map[string]sharedDomainEvents.EventMetadata{
+		userDomain.UserCreated: {Type: reflect.TypeOf(userDomain.User{}), Topic: userDomain.UserTopic},
+	}
+
+	var published sharedDomainEvents.IntegrationEvent
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{testEvent}, nil).Once()
+	publisher.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		published = args.Get(1).(sharedDomainEvents.IntegrationEvent)
+	}).Return(nil).Once()
+	repo.On("MarkOutboxProcessed", mock.Anything, testEvent.ID).Return(nil).Once()
+
+	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
+	worker.SetDecryption(keys)
+
+	// ACT
+	worker.ProcessBatch(context.Background())
+
+	// ASSERT
+	var user userDomain.User
+	assert.NoError(t, json.Unmarshal(published.Data, &user))
+	assert.Equal(t, "ana@example.com", user.Email)
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxWorker_ProcessBatch_PublishesPartialPayload(t *testing.T) {
+	// ARRANGE: el servicio guardó solo los campos que cambiaron (modo delta).
+	repo := new(mocks.MockOutboxRepository)
+	publisher := new(mocks.MockPublisher)
+
+	userID := uuid.New()
+	testEvent := sharedDomain.OutboxEvent{
+		ID:        uuid.New(),
+		EventType: userDomain.UserUpdated,
+		Payload:   map[string]interface{}{"id": userID.String(), "name": "Ana", "version": 2},
+		Metadata:  map[string]string{sharedDomain.MetadataPayloadMode: string(sharedDomainEvents.PayloadDelta)},
+	}
+	registry := map[string]sharedDomainEvents.EventMetadata{
+		userDomain.UserUpdated: {Type: reflect.TypeOf(userDomain.User{}), Topic: userDomain.UserTopic},
+	}
+
+	var published sharedDomainEvents.IntegrationEvent
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{testEvent}, nil).Once()
+	publisher.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		published = args.Get(1).(sharedDomainEvents.IntegrationEvent)
+	}).Return(nil).Once()
+	repo.On("MarkOutboxProcessed", mock.Anything, testEvent.ID).Return(nil).Once()
+
+	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// ACT
+	worker.ProcessBatch(context.Background())
+
+	// ASSERT: el payload sale tal cual, sin los campos que no cambiaron rellenos con ceros.
+	assert.Equal(t, sharedDomainEvents.PayloadDelta, published.PayloadMode)
+	assert.JSONEq(t, `{"id":"`+userID.String()+`","name":"Ana","version":2}`, string(published.Data))
+	repo.AssertExpectations(t)
+}
+
 func TestOutboxWorker_ProcessBatch_UnknownEventType(t *testing.T) {
 	// ARRANGE
 	repo := new(mocks.MockOutboxRepository)
@@ -93,7 +306,7 @@ func TestOutboxWorker_ProcessBatch_UnknownEventType(t *testing.T) {
 
 	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{testEvent}, nil).Once()
 
-	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, zap.NewNop())
+	worker := NewOutboxWorker(repo, publisher, registry, 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
 
 	// ACT
 	worker.ProcessBatch(context.Background())
@@ -104,6 +317,134 @@ func TestOutboxWorker_ProcessBatch_UnknownEventType(t *testing.T) {
 	repo.AssertNotCalled(t, "MarkOutboxProcessed", mock.Anything, mock.Anything)
 }
 
-// Verificación estática de que los mocks cumplen las interfaces.
-var _ sharedDomain.OutboxRepository = (*mocks.MockOutboxRepository)(nil)
-var _ sharedBus.EventBus = (*mocks.MockPublisher)(nil)
+func TestOutboxWorker_SetBatchSize(t *testing.T) {
+	// ARRANGE
+	repo := new(mocks.MockOutboxRepository)
+	publisher := new(mocks.MockPublisher)
+	repo.On("FetchPendingOutbox", mock.Anything, 50).Return([]sharedDomain.OutboxEvent{}, nil).Once()
+
+	worker := NewOutboxWorker(repo, publisher, nil, time.Second, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// ACT: el nuevo tamaño de lote se usa en el siguiente ProcessBatch
+	worker.SetBatchSize(50)
+	worker.SetInterval(5 * time.Second)
+	worker.ProcessBatch(context.Background())
+
+	// ASSERT
+	repo.AssertExpectations(t)
+	assert.Equal(t, 5*time.Second, <-worker.resetInterval)
+}
+
+func TestOutboxWorker_Polled(t *testing.T) {
+	// ARRANGE: el primer polling falla, así que el outbox aún no se ha leído.
+	repo := new(mocks.MockOutboxRepository)
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return(nil, errors.New("database is locked")).Once()
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{}, nil)
+
+	worker := NewOutboxWorker(repo, new(mocks.MockPublisher), nil, time.Second, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// ACT + ASSERT
+	worker.ProcessBatch(context.Background())
+	select {
+	case <-worker.Polled():
+		t.Fatal("Polled no debe cerrarse si no se pudo leer el outbox")
+	default:
+	}
+
+	worker.ProcessBatch(context.Background())
+	worker.ProcessBatch(context.Background()) // Cerrar el canal una segunda vez no debe entrar en pánico
+	select {
+	case <-worker.Polled():
+	default:
+		t.Fatal("Polled debe cerrarse tras el primer polling correcto")
+	}
+}
+
+func TestOutboxWorker_Start_PollsOnEveryTick(t *testing.T) {
+	// ARRANGE: el reloj falso decide cuándo dispara el ticker, sin esperas reales.
+	repo := new(mocks.MockOutboxRepository)
+	polls := make(chan struct{}, 10)
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{}, nil).
+		Run(func(mock.Arguments) { polls <- struct{}{} })
+	clock := mocks.NewFakeClock(time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC))
+	worker := NewOutboxWorker(repo, new(mocks.MockPublisher), nil, time.Minute, 10, clock, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Start(ctx)
+	<-polls // Polling inicial al arrancar
+	assert.Eventually(t, func() bool { return clock.Tickers() == 1 }, time.Second, time.Millisecond)
+
+	// ACT + ASSERT
+	clock.Advance(30 * time.Second)
+	select {
+	case <-polls:
+		t.Fatal("no debe hacer polling antes de que venza el intervalo")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case <-polls:
+	case <-time.After(time.Second):
+		t.Fatal("debe hacer polling cuando vence el intervalo")
+	}
+}
+
+func TestOutboxWorker_ProcessBatch_SchemaViolation(t *testing.T) {
+	// ARRANGE: un user.created sin email, que incumple el esquema del registro de usuarios.
+	repo := new(mocks.MockOutboxRepository)
+	publisher := new(mocks.MockPublisher)
+
+	eventID := uuid.New()
+	testEvent := sharedDomain.OutboxEvent{
+		ID:        eventID,
+		EventType: userDomain.UserCreated,
+		Payload:   map[string]interface{}{"id": uuid.New().String(), "nombre": "Ana", "birth_date": "1990-01-01T00:00:00Z"},
+	}
+
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{testEvent}, nil).Once()
+	repo.On("MarkOutboxDeadLetter", mock.Anything, eventID, mock.MatchedBy(func(reason string) bool {
+		return strings.Contains(reason, "$.email: is required")
+	})).Return(nil).Once()
+
+	worker := NewOutboxWorker(repo, publisher, userDomain.NewEventRegistry(), 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// ACT
+	worker.ProcessBatch(context.Background())
+
+	// ASSERT: se aparta sin publicarlo ni marcarlo como procesado.
+	repo.AssertExpectations(t)
+	publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "MarkOutboxProcessed", mock.Anything, mock.Anything)
+}
+
+func TestOutboxWorker_ProcessBatch_CompressedPayload(t *testing.T) {
+	// ARRANGE: un evento comprimido al guardarlo en el outbox.
+	repo := new(mocks.MockOutboxRepository)
+	publisher := new(mocks.MockPublisher)
+
+	user := userDomain.User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana", BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)}
+	testEvent, err := compression.Options{Encoding: compression.Snappy}.CompressOutbox(context.Background(), sharedDomain.OutboxEvent{
+		ID:        uuid.New(),
+		EventType: userDomain.UserCreated,
+		Payload:   userDomain.NewUserPayload(&user),
+	})
+	assert.NoError(t, err)
+
+	repo.On("FetchPendingOutbox", mock.Anything, 10).Return([]sharedDomain.OutboxEvent{testEvent}, nil).Once()
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(evt sharedDomainEvents.IntegrationEvent) bool {
+		var u userDomain.User
+		return json.Unmarshal(evt.Data, &u) == nil && u.ID == user.ID && u.Email == user.Email
+	})).Return(nil).Once()
+	repo.On("MarkOutboxProcessed", mock.Anything, testEvent.ID).Return(nil).Once()
+
+	worker := NewOutboxWorker(repo, publisher, userDomain.NewEventRegistry(), 0, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// ACT
+	worker.ProcessBatch(context.Background())
+
+	// ASSERT: se publica el payload descomprimido.
+	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
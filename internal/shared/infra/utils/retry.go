@@ -5,20 +5,28 @@ import (
 	"time"
 )
 
-// Retry ejecuta una función con reintentos configurables
+// Retry ejecuta una función con reintentos configurables. Respeta el plazo de ctx: no empieza
+// con el contexto ya terminado (y devuelve su error), y deja de reintentar, devolviendo el último
+// error de 'fn', en cuanto el contexto termina o el siguiente intento empezaría después del plazo.
 func Retry(ctx context.Context, attempts int, delay time.Duration, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	var err error
 	for i := 0; i < attempts; i++ {
 		err = fn()
-		if err == nil {
-			return nil
+		if err == nil || i == attempts-1 || ctx.Err() != nil {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
 		}
 
 		select {
 		case <-time.After(delay):
 			// espera antes del siguiente intento
 		case <-ctx.Done():
-			return ctx.Err()
+			return err
 		}
 	}
 	return err
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	errFlaky := errors.New("database is locked")
+
+	t.Run("retries until success", func(t *testing.T) {
+		// Arrange
+		calls := 0
+
+		// Act
+		err := Retry(context.Background(), 3, time.Millisecond, func() error {
+			calls++
+			if calls < 3 {
+				return errFlaky
+			}
+			return nil
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("does not start with an expired context", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		calls := 0
+
+		// Act
+		err := Retry(ctx, 3, time.Millisecond, func() error { calls++; return nil })
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("stops when the next attempt would miss the deadline", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		calls := 0
+
+		// Act
+		start := time.Now()
+		err := Retry(ctx, 3, time.Hour, func() error { calls++; return errFlaky })
+
+		// Assert
+		assert.ErrorIs(t, err, errFlaky)
+		assert.Equal(t, 1, calls)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("does not retry after the context ends", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+
+		// Act
+		err := Retry(ctx, 3, time.Millisecond, func() error {
+			calls++
+			cancel()
+			return ctx.Err()
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+}
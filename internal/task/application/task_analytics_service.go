@@ -0,0 +1,230 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/davicafu/hexagolab/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/xitongsys/parquet-go/writer"
+	"go.uber.org/zap"
+)
+
+// ExportFormat identifica el formato de salida de una exportación analítica.
+type ExportFormat string
+
+const (
+	ExportCSV     ExportFormat = "csv"
+	ExportParquet ExportFormat = "parquet"
+)
+
+// RowEncoder serializa filas del histórico de tareas en un formato concreto.
+type RowEncoder interface {
+	Encode(entry taskDomain.TaskLogEntry) error
+	// Close vuelca lo que quede en buffer (y el pie del fichero, si el formato lo tiene).
+	Close() error
+}
+
+// EncoderFactory crea un RowEncoder que escribe sobre 'w'.
+type EncoderFactory func(w io.Writer) (RowEncoder, error)
+
+// TaskAnalyticsService define los casos de uso de analítica de tareas.
+type TaskAnalyticsService struct {
+	repo     taskDomain.TaskAnalyticsRepository
+	blobs    taskDomain.BlobStorage
+	encoders map[ExportFormat]EncoderFactory
+	log      *zap.Logger
+}
+
+// NewTaskAnalyticsService es el constructor. 'blobs' puede ser nil si no hay almacenamiento configurado.
+func NewTaskAnalyticsService(repo taskDomain.TaskAnalyticsRepository, blobs taskDomain.BlobStorage, log *zap.Logger) *TaskAnalyticsService {
+	return &TaskAnalyticsService{
+		repo:  repo,
+		blobs: blobs,
+		encoders: map[ExportFormat]EncoderFactory{
+			ExportCSV:     newCSVEncoder,
+			ExportParquet: newParquetEncoder,
+		},
+		log: log,
+	}
+}
+
+// RegisterEncoder añade (o reemplaza) el encoder de un formato de exportación.
+func (s *TaskAnalyticsService) RegisterEncoder(format ExportFormat, factory EncoderFactory) {
+	s.encoders[format] = factory
+}
+
+// GetDailyTrend devuelve las tareas creadas y completadas por día en el rango.
+func (s *TaskAnalyticsService) GetDailyTrend(ctx context.Context, start, end time.Time) ([]taskDomain.DailyTaskTrend, error) {
+	return s.repo.GetDailyTrend(ctx, start, end)
+}
+
+// GetAverageCompletionTime devuelve el tiempo medio entre creación y finalización de las tareas del rango.
+func (s *TaskAnalyticsService) GetAverageCompletionTime(ctx context.Context, start, end time.Time) (time.Duration, error) {
+	return s.repo.GetAverageCompletionTime(ctx, start, end)
+}
+
+// GetAssigneeStats devuelve los contadores de productividad de un usuario en el rango.
+func (s *TaskAnalyticsService) GetAssigneeStats(ctx context.Context, assigneeID uuid.UUID, start, end time.Time) (taskDomain.AssigneeStats, error) {
+	return s.repo.GetAssigneeStats(ctx, assigneeID, start, end)
+}
+
+// SupportsFormat indica si hay un encoder registrado para el formato.
+func (s *TaskAnalyticsService) SupportsFormat(format ExportFormat) bool {
+	_, ok := s.encoders[format]
+	return ok
+}
+
+// ExportTaskLog vuelca el histórico de tareas del rango [start, end] sobre 'w' en el formato pedido.
+// Las filas se escriben a medida que llegan del repositorio.
+func (s *TaskAnalyticsService) ExportTaskLog(ctx context.Context, format ExportFormat, start, end time.Time, w io.Writer) error {
+	factory, ok := s.encoders[format]
+	if !ok {
+		return fmt.Errorf("%w: %s", taskDomain.ErrUnsupportedExportFormat, format)
+	}
+
+	enc, err := factory(w)
+	if err != nil {
+		return err
+	}
+
+	rows := 0
+	err = s.repo.StreamTaskLog(ctx, start, end, func(entry taskDomain.TaskLogEntry) error {
+		rows++
+		return enc.Encode(entry)
+	})
+	if err != nil {
+		logger.FromContext(ctx, s.log).Error("Failed to export task log", zap.String("format", string(format)), zap.Error(err))
+		return err
+	}
+
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, s.log).Info("Task log exported", zap.String("format", string(format)), zap.Int("rows", rows))
+	return nil
+}
+
+// ExportTaskLogToStorage exporta el histórico al BlobStorage y devuelve la clave del objeto creado.
+// Las exportaciones de un inquilino se guardan bajo exports/<inquilino>/.
+func (s *TaskAnalyticsService) ExportTaskLogToStorage(ctx context.Context, format ExportFormat, start, end time.Time) (string, error) {
+	if s.blobs == nil {
+		return "", sharedDomain.NewError(sharedDomain.CodeUnavailable, "blob storage not configured")
+	}
+	if !s.SupportsFormat(format) {
+		return "", fmt.Errorf("%w: %s", taskDomain.ErrUnsupportedExportFormat, format)
+	}
+
+	prefix := "exports/"
+	if tenant := tracing.TenantID(ctx); tenant != "" {
+		prefix += tenant + "/"
+	}
+	key := fmt.Sprintf("%stasks_log_%s_%s.%s", prefix,
+		start.UTC().Format("20060102T150405"), end.UTC().Format("20060102T150405"), format)
+
+	// El pipe conecta la exportación con el storage sin pasar por un fichero intermedio.
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(s.ExportTaskLog(ctx, format, start, end, pw))
+	}()
+
+	if err := s.blobs.Put(ctx, key, pr); err != nil {
+		pr.CloseWithError(err)
+		return "", err
+	}
+
+	return key, nil
+}
+
+// ---------------- Encoders ----------------
+
+var taskLogColumns = []string{"id", "title", "description", "assignee_id", "status", "created_at", "updated_at", "event_time"}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) (RowEncoder, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(taskLogColumns); err != nil {
+		return nil, err
+	}
+	return &csvEncoder{w: cw}, nil
+}
+
+func (e *csvEncoder) Encode(entry taskDomain.TaskLogEntry) error {
+	return e.w.Write([]string{
+		entry.ID.String(),
+		entry.Title,
+		entry.Description,
+		taskDomain.AssigneeText(entry.AssigneeID),
+		string(entry.Status),
+		entry.CreatedAt.UTC().Format(time.RFC3339),
+		entry.UpdatedAt.UTC().Format(time.RFC3339),
+		entry.EventTime.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// parquetRowGroupSize limita lo que el encoder Parquet acumula en memoria antes de volcar un
+// grupo de filas (el valor por defecto de la librería es 128 MiB).
+const parquetRowGroupSize = 16 * 1024 * 1024
+
+// parquetTaskLogRow es una fila del histórico en Parquet. Las columnas son las mismas que en CSV;
+// assignee_id es opcional y las fechas son timestamps en milisegundos UTC.
+type parquetTaskLogRow struct {
+	ID          string  `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Title       string  `parquet:"name=title, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Description string  `parquet:"name=description, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AssigneeID  *string `parquet:"name=assignee_id, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Status      string  `parquet:"name=status, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CreatedAt   int64   `parquet:"name=created_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	UpdatedAt   int64   `parquet:"name=updated_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	EventTime   int64   `parquet:"name=event_time, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+type parquetEncoder struct {
+	w *writer.ParquetWriter
+}
+
+func newParquetEncoder(w io.Writer) (RowEncoder, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetTaskLogRow), 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	return &parquetEncoder{w: pw}, nil
+}
+
+func (e *parquetEncoder) Encode(entry taskDomain.TaskLogEntry) error {
+	row := parquetTaskLogRow{
+		ID:          entry.ID.String(),
+		Title:       entry.Title,
+		Description: entry.Description,
+		Status:      string(entry.Status),
+		CreatedAt:   entry.CreatedAt.UnixMilli(),
+		UpdatedAt:   entry.UpdatedAt.UnixMilli(),
+		EventTime:   entry.EventTime.UnixMilli(),
+	}
+	if entry.AssigneeID != nil {
+		assignee := entry.AssigneeID.String()
+		row.AssigneeID = &assignee
+	}
+	return e.w.Write(row)
+}
+
+// Close escribe el último grupo de filas y el pie del fichero.
+func (e *parquetEncoder) Close() error {
+	return e.w.WriteStop()
+}
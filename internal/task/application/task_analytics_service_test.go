@@ -0,0 +1,154 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/davicafu/hexagolab/tests/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+	"go.uber.org/zap"
+)
+
+func TestExportTaskLog_CSV(t *testing.T) {
+	// Arrange
+	repo := mocks.NewInMemoryTaskAnalyticsRepo()
+	now := time.Now().UTC()
+	repo.Log = append(repo.Log,
+		mocks.NewTaskLogEntry("Dentro del rango", taskDomain.TaskPending, now.Add(-1*time.Hour)),
+		mocks.NewTaskLogEntry("Fuera del rango", taskDomain.TaskCompleted, now.AddDate(0, -2, 0)),
+	)
+	service := NewTaskAnalyticsService(repo, nil, zap.NewNop())
+
+	// Act
+	var buf bytes.Buffer
+	err := service.ExportTaskLog(context.Background(), ExportCSV, now.Add(-24*time.Hour), now, &buf)
+
+	// Assert
+	require.NoError(t, err)
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 2) // cabecera + 1 fila
+	assert.Equal(t, taskLogColumns, records[0])
+	assert.Equal(t, "Dentro del rango", records[1][1])
+	assert.Equal(t, "pending", records[1][4])
+}
+
+func TestExportTaskLog_Parquet(t *testing.T) {
+	// Arrange
+	repo := mocks.NewInMemoryTaskAnalyticsRepo()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	assigned := mocks.NewTaskLogEntry("Asignada", taskDomain.TaskCompleted, now.Add(-2*time.Hour))
+	unassigned := mocks.NewTaskLogEntry("Sin asignar", taskDomain.TaskPending, now.Add(-time.Hour))
+	unassigned.AssigneeID = nil
+	repo.Log = append(repo.Log, assigned, unassigned)
+	service := NewTaskAnalyticsService(repo, nil, zap.NewNop())
+
+	// Act
+	var buf bytes.Buffer
+	err := service.ExportTaskLog(context.Background(), ExportParquet, now.Add(-24*time.Hour), now, &buf)
+
+	// Assert
+	require.NoError(t, err)
+	pr, err := reader.NewParquetReader(buffer.NewBufferFileFromBytes(buf.Bytes()), new(parquetTaskLogRow), 1)
+	require.NoError(t, err)
+	defer pr.ReadStop()
+	require.EqualValues(t, 2, pr.GetNumRows())
+
+	rows := make([]parquetTaskLogRow, 2)
+	require.NoError(t, pr.Read(&rows))
+	assert.Equal(t, assigned.ID.String(), rows[0].ID)
+	assert.Equal(t, "Asignada", rows[0].Title)
+	assert.Equal(t, "completed", rows[0].Status)
+	require.NotNil(t, rows[0].AssigneeID)
+	assert.Equal(t, assigned.AssigneeID.String(), *rows[0].AssigneeID)
+	assert.Equal(t, assigned.EventTime, time.UnixMilli(rows[0].EventTime).UTC())
+	assert.Equal(t, "Sin asignar", rows[1].Title)
+	assert.Nil(t, rows[1].AssigneeID)
+}
+
+func TestExportTaskLog_UnsupportedFormat(t *testing.T) {
+	service := NewTaskAnalyticsService(mocks.NewInMemoryTaskAnalyticsRepo(), nil, zap.NewNop())
+
+	var buf bytes.Buffer
+	err := service.ExportTaskLog(context.Background(), ExportFormat("xlsx"), time.Now().Add(-time.Hour), time.Now(), &buf)
+
+	assert.ErrorIs(t, err, taskDomain.ErrUnsupportedExportFormat)
+	assert.Zero(t, buf.Len())
+}
+
+func TestExportTaskLogToStorage(t *testing.T) {
+	// Arrange
+	repo := mocks.NewInMemoryTaskAnalyticsRepo()
+	now := time.Now().UTC()
+	repo.Log = append(repo.Log, mocks.NewTaskLogEntry("Tarea", taskDomain.TaskCompleted, now.Add(-time.Minute)))
+	blobs := mocks.NewInMemoryBlobStorage()
+	service := NewTaskAnalyticsService(repo, blobs, zap.NewNop())
+
+	// Act
+	key, err := service.ExportTaskLogToStorage(context.Background(), ExportCSV, now.Add(-time.Hour), now)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, key, ".csv")
+	assert.Contains(t, string(blobs.Blobs[key]), "Tarea")
+}
+
+func TestExportTaskLogToStorage_TenantPrefix(t *testing.T) {
+	// Arrange
+	repo := mocks.NewInMemoryTaskAnalyticsRepo()
+	now := time.Now().UTC()
+	blobs := mocks.NewInMemoryBlobStorage()
+	service := NewTaskAnalyticsService(repo, blobs, zap.NewNop())
+	ctx := tracing.NewContext(context.Background(), tracing.Context{TenantID: "acme"})
+
+	// Act
+	key, err := service.ExportTaskLogToStorage(ctx, ExportCSV, now.Add(-time.Hour), now)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "exports/acme/"), "La exportación debería quedar bajo el prefijo del inquilino: %s", key)
+	assert.Contains(t, blobs.Blobs, key)
+}
+
+func TestGetAssigneeStats(t *testing.T) {
+	// Arrange
+	repo := mocks.NewInMemoryTaskAnalyticsRepo()
+	now := time.Now().UTC()
+	assignee := uuid.New()
+
+	done := mocks.NewTaskLogEntry("Completada", taskDomain.TaskPending, now.Add(-3*time.Hour))
+	done.AssigneeID = &assignee
+	doneLater := done
+	doneLater.Status = taskDomain.TaskCompleted
+	doneLater.UpdatedAt = done.CreatedAt.Add(2 * time.Hour)
+	doneLater.EventTime = doneLater.UpdatedAt
+
+	failed := mocks.NewTaskLogEntry("Fallida", taskDomain.TaskFailed, now.Add(-time.Hour))
+	failed.AssigneeID = &assignee
+
+	other := mocks.NewTaskLogEntry("De otro usuario", taskDomain.TaskCompleted, now.Add(-time.Hour))
+
+	repo.Log = append(repo.Log, done, doneLater, failed, other)
+	service := NewTaskAnalyticsService(repo, nil, zap.NewNop())
+
+	// Act
+	stats, err := service.GetAssigneeStats(context.Background(), assignee, now.Add(-24*time.Hour), now)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, assignee, stats.AssigneeID)
+	assert.Equal(t, 2, stats.CreatedCount)
+	assert.Equal(t, 1, stats.CompletedCount)
+	assert.Equal(t, 1, stats.FailedCount)
+	assert.Equal(t, 2*time.Hour, stats.AvgCompletionTime)
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// TaskCounterService mantiene y sirve la proyección user_task_counts: las tareas pendientes y
+// completadas de cada usuario, derivadas de los eventos de tareas en lugar de contarlas en cada
+// petición. Los consumidores de eventos la actualizan con Apply y el comando replay la
+// reconstruye desde el outbox con Rebuild.
+type TaskCounterService struct {
+	repo taskDomain.TaskCounterRepository
+}
+
+// NewTaskCounterService es el constructor.
+func NewTaskCounterService(repo taskDomain.TaskCounterRepository) *TaskCounterService {
+	return &TaskCounterService{repo: repo}
+}
+
+// Apply aplica a los contadores el cambio de una tarea que trae un evento. Los eventos repetidos
+// o que llegan tarde no los alteran (ver taskDomain.TaskCounterChange.Supersedes).
+func (s *TaskCounterService) Apply(ctx context.Context, change taskDomain.TaskCounterChange) error {
+	return s.repo.Apply(ctx, change)
+}
+
+// TaskSummary devuelve los contadores de tareas de 'userID'; a cero si no tiene ninguna.
+func (s *TaskCounterService) TaskSummary(ctx context.Context, userID uuid.UUID) (taskDomain.TaskSummary, error) {
+	return s.repo.Summary(ctx, userID)
+}
+
+// Rebuild vacía la proyección del inquilino de ctx y vuelve a aplicar, en orden, los cambios que
+// 'replay' pasa a su función. Devuelve cuántos cambios se han aplicado.
+func (s *TaskCounterService) Rebuild(ctx context.Context, replay func(apply func(taskDomain.TaskCounterChange) error) error) (int, error) {
+	if err := s.repo.Reset(ctx); err != nil {
+		return 0, err
+	}
+	applied := 0
+	err := replay(func(change taskDomain.TaskCounterChange) error {
+		if err := s.repo.Apply(ctx, change); err != nil {
+			return err
+		}
+		applied++
+		return nil
+	})
+	return applied, err
+}
@@ -4,14 +4,23 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	// --- Importaciones del dominio y compartidas ---
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	sharedBackground "github.com/davicafu/hexagolab/internal/shared/infra/platform/background"
 	sharedCache "github.com/davicafu/hexagolab/internal/shared/infra/platform/cache"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/payload"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/quota"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	sharedUtils "github.com/davicafu/hexagolab/internal/shared/infra/utils"
 	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/davicafu/hexagolab/pkg/logger"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -21,80 +30,393 @@ import (
 type TaskService struct {
 	repo  taskDomain.TaskRepository
 	cache sharedCache.Cache
+	async *sharedBackground.Pool
+	clock sharedDomain.Clock
+	ids   sharedDomain.IDGenerator
 	log   *zap.Logger
+
+	// compression comprime los payloads grandes del outbox (ver SetPayloadCompression).
+	compression atomic.Pointer[compression.Options]
+	// payloads es la forma de los payloads del outbox por tipo de evento (ver SetPayloadModes).
+	payloads atomic.Pointer[payload.Modes]
+	// quotas limita las altas de cada usuario (ver SetQuotas).
+	quotas *quota.Quotas
 }
 
-// NewTaskService es el constructor para el servicio de tareas.
-func NewTaskService(repo taskDomain.TaskRepository, cache sharedCache.Cache, log *zap.Logger) *TaskService {
+// NewTaskService es el constructor para el servicio de tareas. 'async' ejecuta las escrituras
+// en caché en segundo plano (nil las hace en el momento), 'clock' da las fechas de las tareas y
+// de sus eventos, e 'ids' sus identificadores.
+func NewTaskService(repo taskDomain.TaskRepository, cache sharedCache.Cache, async *sharedBackground.Pool, clock sharedDomain.Clock, ids sharedDomain.IDGenerator, log *zap.Logger) *TaskService {
 	return &TaskService{
 		repo:  repo,
 		cache: cache,
-		log:   log,
+		async: async,
+		clock: clock,
+		ids:   ids,
+		log:   log.Named("task"),
+	}
+}
+
+// SetPayloadCompression comprime a partir de ahora los payloads del outbox que superen el
+// umbral de 'opts'. Se puede cambiar en caliente.
+func (s *TaskService) SetPayloadCompression(opts compression.Options) {
+	s.compression.Store(&opts)
+}
+
+// SetPayloadModes da a partir de ahora a los payloads de task.created y task.updated la forma
+// que 'modes' configura para su tipo. Se puede cambiar en caliente.
+func (s *TaskService) SetPayloadModes(modes payload.Modes) {
+	s.payloads.Store(&modes)
+}
+
+// SetQuotas aplica 'quotas' al alta de tareas: pasada la cuota, el alta devuelve un
+// *quota.ExceededError. Se llama antes de servir peticiones.
+func (s *TaskService) SetQuotas(quotas *quota.Quotas) {
+	s.quotas = quotas
+}
+
+// taskPayloadKeys son las claves del id y la versión en el JSON de una tarea.
+var taskPayloadKeys = payload.Keys{ID: "ID", Version: "Version"}
+
+// payloadMode devuelve la forma configurada para los payloads de 'eventType'.
+func (s *TaskService) payloadMode(eventType string) sharedEvents.PayloadMode {
+	modes := s.payloads.Load()
+	if modes == nil {
+		return sharedEvents.PayloadSnapshot
+	}
+	return modes.Mode(eventType)
+}
+
+// shape da al payload del evento, la tarea tras el cambio, la forma configurada para su tipo.
+// 'before' es la tarea antes del cambio, que necesitan los deltas; nil en las altas.
+func (s *TaskService) shape(ctx context.Context, evt sharedDomain.OutboxEvent, before *taskDomain.Task) (sharedDomain.OutboxEvent, error) {
+	modes := s.payloads.Load()
+	if modes == nil {
+		return evt, nil
 	}
+	var previous any
+	if before != nil {
+		previous = before
+	}
+	return modes.Shape(ctx, evt, previous, taskPayloadKeys)
 }
 
-// CreateTask crea una nueva tarea, su evento de outbox y actualiza la caché.
-func (s *TaskService) CreateTask(ctx context.Context, title, description string, assigneeID uuid.UUID) (*taskDomain.Task, error) {
+// compress aplica al evento la compresión configurada, si la hay.
+func (s *TaskService) compress(ctx context.Context, evt sharedDomain.OutboxEvent) (sharedDomain.OutboxEvent, error) {
+	opts := s.compression.Load()
+	if opts == nil {
+		return evt, nil
+	}
+	return opts.CompressOutbox(ctx, evt)
+}
+
+// CreateTask crea una nueva tarea, su evento de outbox y actualiza la caché. Con 'assigneeID'
+// nil la tarea queda sin asignar.
+func (s *TaskService) CreateTask(ctx context.Context, title, description string, assigneeID *uuid.UUID) (*taskDomain.Task, error) {
+	now := s.clock.Now().UTC()
 	task := &taskDomain.Task{
-		ID:          uuid.New(),
+		ID:          s.ids.NewID(),
 		Title:       title,
 		Description: description,
 		AssigneeID:  assigneeID,
 		Status:      taskDomain.TaskPending,
-		CreatedAt:   time.Now().UTC(),
-		UpdatedAt:   time.Now().UTC(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Version:     1,
 	}
 
 	outboxEvent := sharedDomain.OutboxEvent{
-		ID:            uuid.New(),
+		ID:            s.ids.NewID(),
 		AggregateType: "task",
 		AggregateID:   task.ID.String(),
 		EventType:     taskDomain.TaskCreated,
-		Payload:       task, // El payload es la entidad completa
-		CreatedAt:     time.Now().UTC(),
+		Payload:       task, // La entidad completa, o la forma configurada (ver SetPayloadModes)
+		CreatedAt:     now,
 	}
 
+	outboxEvent, err := s.shape(ctx, outboxEvent, nil)
+	if err != nil {
+		return nil, err
+	}
+	outboxEvent, err = s.compress(ctx, outboxEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	// La cuota se descuenta justo antes del alta y se devuelve si el alta falla.
+	release, err := s.quotas.Consume(ctx, quota.ActionCreateTask)
+	if err != nil {
+		return nil, err
+	}
 	if err := s.repo.Create(ctx, task, outboxEvent); err != nil {
-		s.log.Error("Failed to create task", zap.Error(err))
+		release(ctx)
+		logger.FromContext(ctx, s.log).Error("Failed to create task", zap.Error(err))
 		return nil, err
 	}
 
 	// Actualizar caché en segundo plano
-	sharedCache.AsyncCacheSet(ctx, s.cache, taskDomain.TaskCacheKeyByID(task.ID), task, 60, s.log)
+	sharedCache.AsyncCacheSet(ctx, s.async, s.cache, sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(task.ID)), task, sharedCache.DefaultTTL, s.log)
 
 	return task, nil
 }
 
-// UpdateTask actualiza una tarea, crea un evento y actualiza la caché.
+// GetTasksByIDs devuelve las tareas de 'ids' en el orden pedido, sin las que no existen y sin
+// repetidas. Lee la caché con una sola operación y pide al repositorio solo las que faltan, que
+// se guardan en caché en segundo plano. Más de sharedQuery.MaxIDs ids es un sharedQuery.ErrInvalidQuery.
+func (s *TaskService) GetTasksByIDs(ctx context.Context, ids []uuid.UUID) ([]*taskDomain.Task, error) {
+	ids, err := sharedQuery.UniqueIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]*taskDomain.Task, len(ids))
+
+	// 1. Intentar obtener de la caché
+	missing := ids
+	if s.cache != nil && len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(id))
+		}
+		cached, err := sharedCache.GetMany[taskDomain.Task](ctx, s.cache, keys)
+		if err != nil {
+			logger.FromContext(ctx, s.log).Warn("Cache multi-get failed", zap.Error(err))
+		} else {
+			missing = make([]uuid.UUID, 0, len(ids)-len(cached))
+			for i, id := range ids {
+				if t, ok := cached[keys[i]]; ok {
+					found[id] = &t
+				} else {
+					missing = append(missing, id)
+				}
+			}
+		}
+	}
+
+	// 2. Las que falten, del repositorio en una sola consulta
+	if len(missing) > 0 {
+		var tasks []*taskDomain.Task
+		err := sharedUtils.Retry(ctx, 3, 100*time.Millisecond, func() error {
+			var errRetry error
+			tasks, errRetry = s.repo.GetByIDs(ctx, missing)
+			return errRetry
+		})
+		if err != nil {
+			logger.FromContext(ctx, s.log).Error("Failed to fetch tasks", zap.Int("count", len(missing)), zap.Error(err))
+			return nil, err
+		}
+
+		// 3. Actualizar caché en segundo plano para la próxima vez
+		items := make(map[string]interface{}, len(tasks))
+		for _, t := range tasks {
+			found[t.ID] = t
+			items[sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(t.ID))] = t
+		}
+		sharedCache.AsyncCacheSetMulti(ctx, s.async, s.cache, items, sharedCache.DefaultTTL, s.log)
+	}
+
+	tasks := make([]*taskDomain.Task, 0, len(found))
+	for _, id := range ids {
+		if t, ok := found[id]; ok {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+// updateAttempts son las veces que UpdateTask intenta escribir una tarea que otra escritura
+// cambia a la vez.
+const updateAttempts = 3
+
+// UpdateTask actualiza una tarea, crea un evento y actualiza la caché. Gana la última escritura:
+// si la tarea ha cambiado desde que se leyó, se escribe sobre la versión guardada. Para no pisar
+// cambios ajenos, ver CompareAndUpdateTask.
 func (s *TaskService) UpdateTask(ctx context.Context, t *taskDomain.Task) error {
+	for attempt := 1; ; attempt++ {
+		err := s.CompareAndUpdateTask(ctx, t, t.Version)
+		if !errors.Is(err, taskDomain.ErrTaskVersionConflict) || attempt == updateAttempts {
+			return err
+		}
+		// La versión leída (quizá de la caché) ya no es la guardada: se lee del repositorio.
+		current, err := s.repo.GetByID(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		t.Version = current.Version
+	}
+}
+
+// CompareAndUpdateTask actualiza la tarea como UpdateTask solo si su versión guardada sigue siendo
+// 'version' (la que leyó el cliente); si no, devuelve taskDomain.ErrTaskVersionConflict y la
+// tarea queda como estaba. Si se actualiza, t.Version pasa a ser la nueva versión. Con los
+// payloads de task.updated en modo delta, lee la tarea guardada para calcular qué cambia.
+func (s *TaskService) CompareAndUpdateTask(ctx context.Context, t *taskDomain.Task, version int64) error {
+	var before *taskDomain.Task
+	if s.payloadMode(taskDomain.TaskUpdated) == sharedEvents.PayloadDelta {
+		current, err := s.repo.GetByID(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		if current.Version != version {
+			return taskDomain.ErrTaskVersionConflict
+		}
+		before = current
+	}
+
+	t.Version = version + 1
 	evt := sharedDomain.OutboxEvent{
-		ID:            uuid.New(),
+		ID:            s.ids.NewID(),
 		AggregateType: "task",
 		AggregateID:   t.ID.String(),
 		EventType:     taskDomain.TaskUpdated,
 		Payload:       t,
-		CreatedAt:     time.Now().UTC(),
+		CreatedAt:     s.clock.Now().UTC(),
+	}
+
+	evt, err := s.shape(ctx, evt, before)
+	if err == nil {
+		evt, err = s.compress(ctx, evt)
+	}
+	if err != nil {
+		t.Version = version
+		return err
 	}
 
 	if err := s.repo.Update(ctx, t, evt); err != nil {
+		t.Version = version
 		return err
 	}
 
 	// Actualizar caché en segundo plano
-	sharedCache.AsyncCacheSet(ctx, s.cache, taskDomain.TaskCacheKeyByID(t.ID), t, 60, s.log)
+	sharedCache.AsyncCacheSet(ctx, s.async, s.cache, sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(t.ID)), t, sharedCache.DefaultTTL, s.log)
 
 	return nil
 }
 
+// StatusResult es el resultado de cambiar el estado de una tarea en BulkTransitionStatus: la
+// tarea actualizada, o el error por el que no se cambió.
+type StatusResult struct {
+	ID   uuid.UUID
+	Task *taskDomain.Task
+	Err  error
+}
+
+// BulkTransitionStatus pasa las tareas de 'ids' a 'status' con una sola escritura en el
+// repositorio y un evento TaskUpdated por tarea. Cada tarea se valida con la máquina de estados
+// y devuelve su propio resultado, en el orden pedido y sin repetidas: ErrTaskNotFound si no
+// existe, ErrTaskStatusTransition si no puede pasar a 'status' o ErrTaskVersionConflict si otra
+// escritura la cambió a la vez. Solo devuelve un error si falla la petición entera: un estado
+// inválido, demasiados ids o un fallo del repositorio.
+func (s *TaskService) BulkTransitionStatus(ctx context.Context, ids []uuid.UUID, status taskDomain.TaskStatus) ([]StatusResult, error) {
+	if !status.Valid() {
+		return nil, fmt.Errorf("%w %q", taskDomain.ErrInvalidTaskStatus, status)
+	}
+	ids, err := sharedQuery.UniqueIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Leer las tareas del repositorio (no de la caché: la versión debe ser la guardada)
+	var tasks []*taskDomain.Task
+	err = sharedUtils.Retry(ctx, 3, 100*time.Millisecond, func() error {
+		var errRetry error
+		tasks, errRetry = s.repo.GetByIDs(ctx, ids)
+		return errRetry
+	})
+	if err != nil {
+		logger.FromContext(ctx, s.log).Error("Failed to fetch tasks", zap.Int("count", len(ids)), zap.Error(err))
+		return nil, err
+	}
+	found := make(map[uuid.UUID]*taskDomain.Task, len(tasks))
+	for _, t := range tasks {
+		found[t.ID] = t
+	}
+
+	// 2. Validar cada transición y preparar su evento
+	now := s.clock.Now().UTC()
+	results := make([]StatusResult, len(ids))
+	var changed []*taskDomain.Task
+	var events []sharedDomain.OutboxEvent
+	for i, id := range ids {
+		results[i].ID = id
+		t, ok := found[id]
+		if !ok {
+			results[i].Err = taskDomain.ErrTaskNotFound
+			continue
+		}
+		before := *t
+		if err := t.TransitionTo(status, now); err != nil {
+			results[i].Err = err
+			continue
+		}
+		t.Version++
+		evt, err := s.shape(ctx, sharedDomain.OutboxEvent{
+			ID:            s.ids.NewID(),
+			AggregateType: "task",
+			AggregateID:   t.ID.String(),
+			EventType:     taskDomain.TaskUpdated,
+			Payload:       t,
+			CreatedAt:     now,
+		}, &before)
+		if err == nil {
+			evt, err = s.compress(ctx, evt)
+		}
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, t)
+		events = append(events, evt)
+	}
+	if len(changed) == 0 {
+		return results, nil
+	}
+
+	// 3. Una sola escritura para todas; las que otra escritura cambió a la vez no se guardan
+	updated, err := s.repo.UpdateStatus(ctx, changed, events)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Error("Failed to update task statuses", zap.Int("count", len(changed)), zap.Error(err))
+		return nil, err
+	}
+	saved := make(map[uuid.UUID]bool, len(updated))
+	for _, id := range updated {
+		saved[id] = true
+	}
+
+	// 4. Actualizar caché en segundo plano
+	items := make(map[string]interface{}, len(updated))
+	for i := range results {
+		t, ok := found[results[i].ID]
+		if !ok || results[i].Err != nil {
+			continue
+		}
+		if !saved[t.ID] {
+			results[i].Err = taskDomain.ErrTaskVersionConflict
+			continue
+		}
+		results[i].Task = t
+		items[sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(t.ID))] = t
+	}
+	sharedCache.AsyncCacheSetMulti(ctx, s.async, s.cache, items, sharedCache.DefaultTTL, s.log)
+
+	return results, nil
+}
+
 // DeleteTask elimina una tarea, crea un evento y limpia la caché.
 func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
 	evt := sharedDomain.OutboxEvent{
-		ID:            uuid.New(),
+		ID:            s.ids.NewID(),
 		AggregateType: "task",
 		AggregateID:   id.String(),
 		EventType:     taskDomain.TaskDeleted,
 		Payload:       map[string]interface{}{"id": id.String()},
-		CreatedAt:     time.Now().UTC(),
+		CreatedAt:     s.clock.Now().UTC(),
+		Metadata:      tracing.Metadata(ctx),
+	}
+	// Borra el agregado: en los topics compactados irá seguido de un tombstone.
+	evt.Metadata[sharedDomain.MetadataTombstone] = "true"
+
+	evt, err := s.compress(ctx, evt)
+	if err != nil {
+		return err
 	}
 
 	if err := s.repo.DeleteByID(ctx, id, evt); err != nil {
@@ -102,17 +424,24 @@ func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
 	}
 
 	// Eliminar de la caché en segundo plano
-	sharedCache.AsyncCacheDelete(ctx, s.cache, taskDomain.TaskCacheKeyByID(id), s.log)
+	sharedCache.AsyncCacheDelete(ctx, s.async, s.cache, sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(id)), s.log)
 
 	return nil
 }
 
+// GetTaskForUpdate lee la tarea del repositorio, sin pasar por la caché, para modificarla y
+// guardarla con UpdateTask o CompareAndUpdateTask: una copia antigua de la caché pisaría con sus
+// valores los cambios guardados después, aunque la versión de If-Match sea la actual.
+func (s *TaskService) GetTaskForUpdate(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
 // GetTaskByID obtiene una tarea, usando el patrón cache-aside con reintentos.
 func (s *TaskService) GetTaskByID(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error) {
 	// 1. Intentar obtener de la caché
 	if s.cache != nil {
 		var t taskDomain.Task
-		if hit, _ := s.cache.Get(ctx, taskDomain.TaskCacheKeyByID(id), &t); hit {
+		if hit, _ := s.cache.Get(ctx, sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(id)), &t); hit {
 			return &t, nil
 		}
 	}
@@ -127,36 +456,93 @@ func (s *TaskService) GetTaskByID(ctx context.Context, id uuid.UUID) (*taskDomai
 
 	if err != nil {
 		if errors.Is(err, taskDomain.ErrTaskNotFound) {
-			s.log.Warn("Task not found", zap.String("task_id", id.String()))
+			logger.FromContext(ctx, s.log).Warn("Task not found", zap.String("task_id", id.String()))
 		} else {
-			s.log.Error("Failed to fetch task", zap.String("task_id", id.String()), zap.Error(err))
+			logger.FromContext(ctx, s.log).Error("Failed to fetch task", zap.String("task_id", id.String()), zap.Error(err))
 		}
 		return nil, err
 	}
 
 	// 3. Actualizar caché en segundo plano para la próxima vez
-	if s.cache != nil {
-		go func(t *taskDomain.Task) {
-			cacheCtx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
-			defer cancel()
-			if err := s.cache.Set(cacheCtx, taskDomain.TaskCacheKeyByID(t.ID), t, 120); err != nil {
-				s.log.Warn("⚠️ Cache update failed for task",
-					zap.String("task_id", t.ID.String()),
-					zap.Error(err),
-				)
-			}
-		}(task)
-	}
+	sharedCache.AsyncCacheSet(ctx, s.async, s.cache, sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(task.ID)), task, sharedCache.DefaultTTL, s.log)
 
 	return task, nil
 }
 
-// ListTasks es un pass-through al repositorio para listados genéricos.
-func (s *TaskService) ListTasks(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts sharedQuery.Sort) ([]*taskDomain.Task, error) {
+// ListTasks es un pass-through al repositorio para listados genéricos, tras validar los filtros
+// y la ordenación contra taskDomain.TaskFields.
+func (s *TaskService) ListTasks(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*taskDomain.Task, error) {
+	if err := sharedQuery.Validate(taskDomain.TaskFields, criteria, sorts); err != nil {
+		return nil, err
+	}
 	return s.repo.ListByCriteria(ctx, criteria, pagination, sorts)
 }
 
-func (s *TaskService) ListPendingTasksForUser(ctx context.Context, userID uuid.UUID, pagination sharedQuery.Pagination, sorts sharedQuery.Sort) ([]*taskDomain.Task, error) {
+// ListTasksPage devuelve una página de tareas con los metadatos para pedir la siguiente.
+func (s *TaskService) ListTasksPage(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) (sharedQuery.PagedResult[*taskDomain.Task], error) {
+	if err := sharedQuery.Validate(taskDomain.TaskFields, criteria, sorts); err != nil {
+		return sharedQuery.PagedResult[*taskDomain.Task]{}, err
+	}
+	return sharedQuery.ListPage(pagination,
+		func(p sharedQuery.Pagination) ([]*taskDomain.Task, error) {
+			return s.repo.ListByCriteria(ctx, criteria, p, sorts)
+		},
+		func() (int, error) { return s.repo.CountByCriteria(ctx, criteria) },
+		func(t *taskDomain.Task) sharedQuery.Cursor {
+			return sharedQuery.NewCursorFor(sorts, t.ID, func(field string) any { return taskSortValue(t, field) })
+		},
+	)
+}
+
+// StreamTasks recorre todas las tareas que cumplen 'criteria', por fecha de alta, sin cargarlas
+// en memoria (ver taskDomain.TaskRepository.StreamByCriteria). Los filtros se validan antes de
+// la primera consulta, así que un ErrInvalidCriteria llega sin haber llamado nunca a 'fn'.
+func (s *TaskService) StreamTasks(ctx context.Context, criteria sharedDomain.Criteria, fn func(*taskDomain.Task) error) error {
+	if err := sharedQuery.Validate(taskDomain.TaskFields, criteria, taskDomain.StreamSorts); err != nil {
+		return err
+	}
+	return s.repo.StreamByCriteria(ctx, criteria, fn)
+}
+
+// taskWarmupSorts es el orden en que WarmCache precarga las tareas: las actualizadas más
+// recientemente primero.
+var taskWarmupSorts = []sharedQuery.Sort{{Field: "updated_at", Desc: true}}
+
+// WarmCache precarga en caché las 'limit' tareas con actividad más reciente (ver
+// sharedCache.Warm), con las claves del inquilino de ctx. Devuelve cuántas se han guardado.
+func (s *TaskService) WarmCache(ctx context.Context, limit int) (int, error) {
+	return sharedCache.Warm(ctx, s.cache, limit,
+		func(offset, limit int) ([]*taskDomain.Task, error) {
+			return s.repo.ListByCriteria(ctx, nil, sharedQuery.OffsetPagination{Limit: limit, Offset: offset}, taskWarmupSorts)
+		},
+		func(t *taskDomain.Task) string { return sharedCache.TenantKey(ctx, taskDomain.TaskCacheKeyByID(t.ID)) },
+	)
+}
+
+// taskSortValue devuelve el valor del campo de ordenación 'field' de 't', para el cursor.
+func taskSortValue(t *taskDomain.Task, field string) any {
+	switch field {
+	case "id":
+		return t.ID
+	case "title":
+		return t.Title
+	case "description":
+		return t.Description
+	case "assignee_id":
+		// Las tareas sin asignar no tienen valor (ver sharedQuery.Cursor.Nulls).
+		if t.AssigneeID == nil {
+			return nil
+		}
+		return *t.AssigneeID
+	case "status":
+		return string(t.Status)
+	case "updated_at":
+		return t.UpdatedAt
+	}
+	return t.CreatedAt
+}
+
+func (s *TaskService) ListPendingTasksForUser(ctx context.Context, userID uuid.UUID, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*taskDomain.Task, error) {
 	criteria := sharedDomain.And(
 		taskDomain.StatusCriteria{Status: taskDomain.TaskPending},
 		taskDomain.AssigneeIDCriteria{ID: userID},
@@ -164,7 +550,7 @@ func (s *TaskService) ListPendingTasksForUser(ctx context.Context, userID uuid.U
 	return s.repo.ListByCriteria(ctx, criteria, pagination, sorts)
 }
 
-func (s *TaskService) ListCompletedTasksForUser(ctx context.Context, userID uuid.UUID, pagination sharedQuery.Pagination, sorts sharedQuery.Sort) ([]*taskDomain.Task, error) {
+func (s *TaskService) ListCompletedTasksForUser(ctx context.Context, userID uuid.UUID, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*taskDomain.Task, error) {
 	criteria := sharedDomain.And(
 		taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted},
 		taskDomain.AssigneeIDCriteria{ID: userID},
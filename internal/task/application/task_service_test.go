@@ -3,11 +3,17 @@ package application
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/payload"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/quota"
 	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
 	"github.com/davicafu/hexagolab/tests/mocks" // Importamos nuestros mocks/fakes
 	"github.com/google/uuid"
@@ -23,11 +29,11 @@ func TestCreateTask_Success(t *testing.T) {
 	// Arrange
 	repo := mocks.NewInMemoryTaskRepo()
 	cache := mocks.NewDummyCache()
-	service := NewTaskService(repo, cache, zap.NewNop())
+	service := NewTaskService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 	assigneeID := uuid.New()
 
 	// Act
-	task, err := service.CreateTask(context.Background(), "Mi primera tarea", "Hacer algo importante", assigneeID)
+	task, err := service.CreateTask(context.Background(), "Mi primera tarea", "Hacer algo importante", &assigneeID)
 
 	// Assert
 	assert.NoError(t, err)
@@ -41,11 +47,106 @@ func TestCreateTask_Success(t *testing.T) {
 	assert.Equal(t, task.ID.String(), repo.Outbox[0].AggregateID)
 }
 
+func TestCreateTask_Quota(t *testing.T) {
+	// Arrange
+	clock := mocks.NewFakeClock(time.Date(2025, time.March, 1, 10, 30, 0, 0, time.UTC))
+	repo := mocks.NewInMemoryTaskRepo()
+	service := NewTaskService(repo, mocks.NewDummyCache(), nil, clock, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+	limits := []quota.Limit{{Action: quota.ActionCreateTask, Max: 1, Period: quota.Daily}}
+	service.SetQuotas(quota.New(quota.NewMemoryCounter(clock), limits, clock, zap.NewNop()))
+
+	// Act
+	_, first := service.CreateTask(context.Background(), "Primera", "", nil)
+	_, second := service.CreateTask(context.Background(), "Segunda", "", nil)
+
+	// Assert
+	assert.NoError(t, first)
+	assert.ErrorIs(t, second, quota.ErrQuotaExceeded)
+	assert.Len(t, repo.Outbox, 1)
+}
+
+func TestCreateTask_QuotaReleasedOnFailure(t *testing.T) {
+	// Arrange: la segunda alta repite el id de la primera y el repositorio la rechaza.
+	clock := mocks.NewFakeClock(time.Date(2025, time.March, 1, 10, 30, 0, 0, time.UTC))
+	repo := mocks.NewInMemoryTaskRepo()
+	taskID := uuid.New()
+	ids := mocks.NewFixedIDGenerator(taskID, uuid.New(), taskID, uuid.New(), uuid.New(), uuid.New())
+	service := NewTaskService(repo, mocks.NewDummyCache(), nil, clock, ids, zap.NewNop())
+	limits := []quota.Limit{{Action: quota.ActionCreateTask, Max: 2, Period: quota.Daily}}
+	service.SetQuotas(quota.New(quota.NewMemoryCounter(clock), limits, clock, zap.NewNop()))
+
+	// Act
+	_, first := service.CreateTask(context.Background(), "Primera", "", nil)
+	_, failed := service.CreateTask(context.Background(), "Repetida", "", nil)
+	_, third := service.CreateTask(context.Background(), "Segunda", "", nil)
+
+	// Assert: el alta fallida no gasta cuota, así que cabe una segunda.
+	assert.NoError(t, first)
+	assert.ErrorIs(t, failed, taskDomain.ErrTaskAlreadyExists)
+	assert.NoError(t, third)
+	assert.Len(t, repo.Outbox, 2)
+}
+
+func TestCreateTask_UsesClock(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, time.March, 1, 10, 30, 0, 0, time.UTC)
+	repo := mocks.NewInMemoryTaskRepo()
+	service := NewTaskService(repo, mocks.NewDummyCache(), nil, mocks.NewFakeClock(now), sharedDomain.RandomIDGenerator{}, zap.NewNop())
+
+	// Act
+	task, err := service.CreateTask(context.Background(), "Mi primera tarea", "Hacer algo importante", nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, now, task.CreatedAt)
+	assert.Equal(t, now, task.UpdatedAt)
+	assert.Equal(t, now, repo.Outbox[0].CreatedAt)
+}
+
+func TestCreateTask_CompressesLargePayloads(t *testing.T) {
+	// Arrange
+	repo := mocks.NewInMemoryTaskRepo()
+	service := NewTaskService(repo, mocks.NewDummyCache(), nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+	service.SetPayloadCompression(compression.Options{Encoding: compression.Gzip, Threshold: 512})
+
+	// Act
+	_, errSmall := service.CreateTask(context.Background(), "Corta", "Poca cosa", nil)
+	task, errLarge := service.CreateTask(context.Background(), "Larga", strings.Repeat("Mucho detalle. ", 100), nil)
+
+	// Assert: solo el payload grande se comprime, y se recupera la tarea completa.
+	assert.NoError(t, errSmall)
+	assert.NoError(t, errLarge)
+	assert.Empty(t, repo.Outbox[0].Metadata[compression.Header])
+	assert.Equal(t, compression.Gzip, repo.Outbox[1].Metadata[compression.Header])
+	payload, err := compression.OutboxPayload(repo.Outbox[1])
+	assert.NoError(t, err)
+	var decoded taskDomain.Task
+	assert.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.Equal(t, task.Description, decoded.Description)
+}
+
+func TestTaskService_UsesIDGenerator(t *testing.T) {
+	// Arrange
+	repo := mocks.NewInMemoryTaskRepo()
+	service := NewTaskService(repo, mocks.NewDummyCache(), nil, sharedDomain.SystemClock{}, mocks.NewSequentialIDGenerator(), zap.NewNop())
+
+	// Act
+	task, err := service.CreateTask(context.Background(), "Mi primera tarea", "Hacer algo importante", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, service.DeleteTask(context.Background(), task.ID))
+
+	// Assert: la tarea, su evento de alta y el de borrado, en orden.
+	assert.Equal(t, mocks.SequentialID(1), task.ID)
+	assert.Len(t, repo.Outbox, 2)
+	assert.Equal(t, mocks.SequentialID(2), repo.Outbox[0].ID)
+	assert.Equal(t, mocks.SequentialID(3), repo.Outbox[1].ID)
+}
+
 func TestGetTask_NotFound(t *testing.T) {
 	// Arrange
 	repo := mocks.NewInMemoryTaskRepo()
 	cache := mocks.NewDummyCache()
-	service := NewTaskService(repo, cache, zap.NewNop())
+	service := NewTaskService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	// Act
 	_, err := service.GetTaskByID(context.Background(), uuid.New())
@@ -58,9 +159,9 @@ func TestUpdateTask_Success(t *testing.T) {
 	// Arrange
 	repo := mocks.NewInMemoryTaskRepo()
 	cache := mocks.NewDummyCache()
-	service := NewTaskService(repo, cache, zap.NewNop())
+	service := NewTaskService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
-	task, _ := service.CreateTask(context.Background(), "Tarea original", "desc", uuid.New())
+	task, _ := service.CreateTask(context.Background(), "Tarea original", "desc", nil)
 	task.Title = "Título actualizado"
 	task.Complete() // Usamos el método de dominio
 
@@ -80,12 +181,41 @@ func TestUpdateTask_Success(t *testing.T) {
 	assert.Equal(t, "task.updated", repo.Outbox[1].EventType)
 }
 
+func TestUpdateTask_PayloadModes(t *testing.T) {
+	// Arrange: el alta solo anuncia el id y la actualización lleva los campos que cambian.
+	repo := mocks.NewInMemoryTaskRepo()
+	service := NewTaskService(repo, mocks.NewDummyCache(), nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+	service.SetPayloadModes(payload.Modes{
+		taskDomain.TaskCreated: sharedEvents.PayloadID,
+		taskDomain.TaskUpdated: sharedEvents.PayloadDelta,
+	})
+	task, err := service.CreateTask(context.Background(), "Tarea original", "desc", nil)
+	assert.NoError(t, err)
+	task.Title = "Título actualizado"
+
+	// Act
+	err = service.UpdateTask(context.Background(), task)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, repo.Outbox, 2)
+	created, err := compression.OutboxPayload(repo.Outbox[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"ID":"`+task.ID.String()+`"}`, string(created))
+	assert.Equal(t, string(sharedEvents.PayloadID), repo.Outbox[0].Metadata[sharedDomain.MetadataPayloadMode])
+
+	updated, err := compression.OutboxPayload(repo.Outbox[1])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"ID":"`+task.ID.String()+`","Title":"Título actualizado","Version":2}`, string(updated))
+	assert.Equal(t, string(sharedEvents.PayloadDelta), repo.Outbox[1].Metadata[sharedDomain.MetadataPayloadMode])
+}
+
 func TestDeleteTask_Success(t *testing.T) {
 	// Arrange
 	repo := mocks.NewInMemoryTaskRepo()
 	cache := mocks.NewDummyCache()
-	service := NewTaskService(repo, cache, zap.NewNop())
-	task, _ := service.CreateTask(context.Background(), "Tarea a borrar", "desc", uuid.New())
+	service := NewTaskService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+	task, _ := service.CreateTask(context.Background(), "Tarea a borrar", "desc", nil)
 
 	// Act
 	err := service.DeleteTask(context.Background(), task.ID)
@@ -100,6 +230,8 @@ func TestDeleteTask_Success(t *testing.T) {
 	// Verificar que se creó un evento Outbox de eliminación
 	assert.Len(t, repo.Outbox, 2)
 	assert.Equal(t, "task.deleted", repo.Outbox[1].EventType)
+	assert.True(t, repo.Outbox[1].IsTombstone(), "El borrado se marca para publicar su tombstone")
+	assert.False(t, repo.Outbox[0].IsTombstone())
 }
 
 // -------------------- GetTask con Cache --------------------
@@ -114,7 +246,7 @@ func TestGetTask_CacheHit(t *testing.T) {
 	cache := mocks.NewDummyCache()
 	cache.Set(context.Background(), taskDomain.TaskCacheKeyByID(taskID), task, 60)
 
-	service := NewTaskService(repo, cache, zap.NewNop())
+	service := NewTaskService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	// Act
 	fetchedTask, err := service.GetTaskByID(context.Background(), taskID)
@@ -134,7 +266,7 @@ func TestGetTask_CacheMiss(t *testing.T) {
 	repo.Create(context.Background(), task, sharedDomain.OutboxEvent{}) // Pre-populamos el repo
 	cache := mocks.NewDummyCache()                                      // La caché está vacía
 
-	service := NewTaskService(repo, cache, zap.NewNop())
+	service := NewTaskService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	// Act
 	fetchedTask, err := service.GetTaskByID(context.Background(), taskID)
@@ -152,39 +284,122 @@ func TestGetTask_CacheMiss(t *testing.T) {
 	}, 1*time.Second, 10*time.Millisecond, "La caché debería haberse populado tras el 'miss'")
 }
 
+func TestGetTasksByIDs_MixesCacheAndRepo(t *testing.T) {
+	// Arrange: una en caché, otra solo en el repositorio y una tercera que no existe.
+	cached := &taskDomain.Task{ID: uuid.New(), Title: "Tarea en caché"}
+	stored := &taskDomain.Task{ID: uuid.New(), Title: "Tarea en repo"}
+
+	repo := mocks.NewInMemoryTaskRepo()
+	repo.Create(context.Background(), stored, sharedDomain.OutboxEvent{})
+	cache := mocks.NewDummyCache()
+	cache.Set(context.Background(), taskDomain.TaskCacheKeyByID(cached.ID), cached, 60)
+
+	service := NewTaskService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+
+	// Act
+	tasks, err := service.GetTasksByIDs(context.Background(), []uuid.UUID{cached.ID, uuid.New(), stored.ID})
+
+	// Assert: en el orden pedido y sin la que falta; la del repositorio queda en caché.
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 2) {
+		assert.Equal(t, "Tarea en caché", tasks[0].Title)
+		assert.Equal(t, "Tarea en repo", tasks[1].Title)
+	}
+	assert.Eventually(t, func() bool {
+		var cachedTask taskDomain.Task
+		hit, _ := cache.Get(context.Background(), taskDomain.TaskCacheKeyByID(stored.ID), &cachedTask)
+		return hit
+	}, 1*time.Second, 10*time.Millisecond, "La caché debería haberse populado con las tareas del repositorio")
+}
+
+func TestWarmCache_MostRecentTasks(t *testing.T) {
+	// Arrange: tres tareas actualizadas en momentos distintos; solo caben dos.
+	ctx := context.Background()
+	base := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	repo := mocks.NewInMemoryTaskRepo()
+	var tasks []*taskDomain.Task
+	for i, title := range []string{"La más antigua", "La intermedia", "La más reciente"} {
+		task := &taskDomain.Task{ID: uuid.New(), Title: title, CreatedAt: base, UpdatedAt: base.Add(time.Duration(i) * time.Hour)}
+		repo.Create(ctx, task, sharedDomain.OutboxEvent{})
+		tasks = append(tasks, task)
+	}
+	cache := mocks.NewDummyCache()
+	service := NewTaskService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+
+	// Act
+	warmed, err := service.WarmCache(ctx, 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, warmed)
+	for i, wantHit := range []bool{false, true, true} {
+		var cached taskDomain.Task
+		hit, _ := cache.Get(ctx, taskDomain.TaskCacheKeyByID(tasks[i].ID), &cached)
+		assert.Equal(t, wantHit, hit, tasks[i].Title)
+	}
+}
+
 // ----------------- ListTasks / Search / Filter -----------------
 
 func TestListPendingTasksForUser_Filtering(t *testing.T) {
 	// Arrange
 	repo := mocks.NewInMemoryTaskRepo()
-	service := NewTaskService(repo, nil, zap.NewNop())
+	service := NewTaskService(repo, nil, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 	userA := uuid.New()
 	userB := uuid.New()
 
 	// Creamos un escenario mixto de tareas
-	repo.Create(context.Background(), &taskDomain.Task{ID: uuid.New(), AssigneeID: userA, Status: taskDomain.TaskPending}, sharedDomain.OutboxEvent{})
-	repo.Create(context.Background(), &taskDomain.Task{ID: uuid.New(), AssigneeID: userA, Status: taskDomain.TaskCompleted}, sharedDomain.OutboxEvent{})
-	repo.Create(context.Background(), &taskDomain.Task{ID: uuid.New(), AssigneeID: userB, Status: taskDomain.TaskPending}, sharedDomain.OutboxEvent{})
+	repo.Create(context.Background(), &taskDomain.Task{ID: uuid.New(), AssigneeID: &userA, Status: taskDomain.TaskPending}, sharedDomain.OutboxEvent{})
+	repo.Create(context.Background(), &taskDomain.Task{ID: uuid.New(), AssigneeID: &userA, Status: taskDomain.TaskCompleted}, sharedDomain.OutboxEvent{})
+	repo.Create(context.Background(), &taskDomain.Task{ID: uuid.New(), AssigneeID: &userB, Status: taskDomain.TaskPending}, sharedDomain.OutboxEvent{})
+	repo.Create(context.Background(), &taskDomain.Task{ID: uuid.New(), Status: taskDomain.TaskPending}, sharedDomain.OutboxEvent{})
 
 	// Act: Usamos el método específico del servicio
 	results, err := service.ListPendingTasksForUser(
 		context.Background(),
 		userA,
 		sharedQuery.OffsetPagination{Limit: 10, Offset: 0},
-		sharedQuery.Sort{},
+		nil,
 	)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
-	assert.Equal(t, userA, results[0].AssigneeID)
+	assert.Equal(t, &userA, results[0].AssigneeID)
 	assert.Equal(t, taskDomain.TaskPending, results[0].Status)
 }
 
+// rawCriteria filtra por una condición arbitraria, como haría un cliente con un campo mal escrito.
+type rawCriteria sharedDomain.Criterion
+
+func (c rawCriteria) ToConditions() []sharedDomain.Criterion {
+	return []sharedDomain.Criterion{sharedDomain.Criterion(c)}
+}
+
+func TestListTasks_RejectsUndeclaredFields(t *testing.T) {
+	// Arrange
+	repo := mocks.NewInMemoryTaskRepo()
+	service := NewTaskService(repo, nil, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+	typo := rawCriteria{Field: "assignee", Op: sharedDomain.OpEq, Value: uuid.New()}
+	badOp := rawCriteria{Field: "status", Op: sharedDomain.OpLike, Value: "pend%"}
+
+	// Act
+	_, typoErr := service.ListTasks(context.Background(), typo, sharedQuery.OffsetPagination{Limit: 10}, nil)
+	_, opErr := service.ListTasksPage(context.Background(), badOp, sharedQuery.OffsetPagination{Limit: 10}, nil)
+	_, sortErr := service.ListTasks(context.Background(), nil, sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "priority"}})
+
+	// Assert
+	var criteriaErr *sharedDomain.CriteriaError
+	assert.ErrorAs(t, typoErr, &criteriaErr)
+	assert.Equal(t, "assignee", criteriaErr.Field)
+	assert.ErrorIs(t, opErr, sharedDomain.ErrInvalidCriteria)
+	assert.ErrorIs(t, sortErr, sharedDomain.ErrInvalidCriteria)
+}
+
 func TestListTasks_PaginationAndSorting(t *testing.T) {
 	// Arrange
 	repo := mocks.NewInMemoryTaskRepo()
-	service := NewTaskService(repo, nil, zap.NewNop())
+	service := NewTaskService(repo, nil, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	// Creamos 5 tareas para probar
 	tasks := []*taskDomain.Task{
@@ -205,7 +420,7 @@ func TestListTasks_PaginationAndSorting(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 2, Offset: 0},
-		sharedQuery.Sort{Field: "title", Desc: false},
+		[]sharedQuery.Sort{{Field: "title", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, page1, 2)
@@ -216,7 +431,7 @@ func TestListTasks_PaginationAndSorting(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 2, Offset: 2},
-		sharedQuery.Sort{Field: "title", Desc: false},
+		[]sharedQuery.Sort{{Field: "title", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, page2, 2)
@@ -228,7 +443,7 @@ func TestListTasks_PaginationAndSorting(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 5, Offset: 0},
-		sharedQuery.Sort{Field: "title", Desc: true},
+		[]sharedQuery.Sort{{Field: "title", Desc: true}},
 	)
 	assert.NoError(t, err)
 	assert.Equal(t, "E - Tarea Epsilon", descTasks[0].Title)
@@ -9,6 +9,17 @@ import (
 	shared "github.com/davicafu/hexagolab/internal/shared/domain"
 )
 
+// TaskFields son los campos por los que se puede filtrar y ordenar tareas, con sus operadores.
+var TaskFields = shared.FieldRegistry{
+	"id":          {shared.OpEq},
+	"title":       {shared.OpEq, shared.OpLike, shared.OpILike},
+	"description": {shared.OpEq, shared.OpLike, shared.OpILike},
+	"assignee_id": {shared.OpEq, shared.OpIsNull},
+	"status":      {shared.OpEq},
+	"created_at":  {shared.OpEq, shared.OpGt, shared.OpGte, shared.OpLt, shared.OpLte},
+	"updated_at":  {shared.OpEq, shared.OpGt, shared.OpGte, shared.OpLt, shared.OpLte},
+}
+
 // --- Criterios Específicos para el Dominio Task ---
 
 // StatusCriteria busca tareas por su estado (pending, completed, etc.).
@@ -39,6 +50,18 @@ func (c AssigneeIDCriteria) ToConditions() []shared.Criterion {
 
 // -----------------------------------------------------------
 
+// UnassignedCriteria busca las tareas sin asignar.
+type UnassignedCriteria struct{}
+
+// ToConditions implementa la interfaz shared.Criteria.
+func (UnassignedCriteria) ToConditions() []shared.Criterion {
+	return []shared.Criterion{
+		{Field: "assignee_id", Op: shared.OpIsNull},
+	}
+}
+
+// -----------------------------------------------------------
+
 // TitleLikeCriteria busca tareas cuyo título contenga un texto.
 type TitleLikeCriteria struct {
 	Title string
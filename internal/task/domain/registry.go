@@ -15,19 +15,66 @@ const (
 
 const TaskTopic = "task"
 
+// taskSchema es el contrato de task.created y task.updated: la tarea completa. Task no lleva
+// etiquetas json, así que las claves son los nombres de los campos. AssigneeID es null en las
+// tareas sin asignar. Version es opcional porque los eventos anteriores a las versiones no la
+// llevan.
+var taskSchema = sharedEvents.MustCompileSchema(`{
+	"type": "object",
+	"required": ["ID", "Title", "Description", "AssigneeID", "Status", "CreatedAt", "UpdatedAt"],
+	"properties": {
+		"ID": {"type": "string", "format": "uuid"},
+		"Title": {"type": "string"},
+		"Description": {"type": "string"},
+		"AssigneeID": {"type": ["string", "null"], "format": "uuid"},
+		"Status": {"type": "string", "enum": ["pending", "completed", "failed"]},
+		"CreatedAt": {"type": "string", "format": "date-time"},
+		"UpdatedAt": {"type": "string", "format": "date-time"},
+		"Version": {"type": "integer"}
+	},
+	"additionalProperties": false,
+	"examples": [{
+		"ID": "0b8f4c1e-3d2a-4f6b-9c7e-5a1d2e3f4a5b",
+		"Title": "Revisar el informe",
+		"Description": "Revisar y aprobar el informe trimestral",
+		"AssigneeID": "6f1c3a52-9a0e-4d2c-8a4e-2b1f0c9d7e65",
+		"Status": "pending",
+		"CreatedAt": "2025-03-01T10:00:00Z",
+		"UpdatedAt": "2025-03-01T10:00:00Z",
+		"Version": 1
+	}]
+}`)
+
+// taskDeletedSchema es el contrato de task.deleted: solo el ID de la tarea borrada.
+var taskDeletedSchema = sharedEvents.MustCompileSchema(`{
+	"type": "object",
+	"required": ["id"],
+	"properties": {
+		"id": {"type": "string", "format": "uuid"}
+	},
+	"additionalProperties": false,
+	"examples": [{"id": "0b8f4c1e-3d2a-4f6b-9c7e-5a1d2e3f4a5b"}]
+}`)
+
 func NewEventRegistry() map[string]sharedEvents.EventMetadata {
 	return map[string]sharedEvents.EventMetadata{
 		TaskCreated: {
-			Type:  reflect.TypeOf(Task{}),
-			Topic: TaskTopic,
+			Type:    reflect.TypeOf(Task{}),
+			Topic:   TaskTopic,
+			Version: 1,
+			Schema:  taskSchema,
 		},
 		TaskUpdated: {
-			Type:  reflect.TypeOf(Task{}),
-			Topic: TaskTopic,
+			Type:    reflect.TypeOf(Task{}),
+			Topic:   TaskTopic,
+			Version: 1,
+			Schema:  taskSchema,
 		},
 		TaskDeleted: {
-			Type:  reflect.TypeOf(Task{}),
-			Topic: TaskTopic,
+			Type:    reflect.TypeOf(Task{}),
+			Topic:   TaskTopic,
+			Version: 1,
+			Schema:  taskDeletedSchema,
 		},
 	}
 }
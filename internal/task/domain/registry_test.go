@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventRegistry_Schemas(t *testing.T) {
+	// Arrange: los payloads que el servicio guarda en el outbox.
+	now := time.Now()
+	assigneeID := uuid.New()
+	task := &Task{ID: uuid.New(), Title: "Revisar", AssigneeID: &assigneeID, Status: TaskPending, CreatedAt: now, UpdatedAt: now}
+	taskPayload, err := json.Marshal(task)
+	require.NoError(t, err)
+	deletedPayload, err := json.Marshal(map[string]interface{}{"id": task.ID.String()})
+	require.NoError(t, err)
+	registry := NewEventRegistry()
+
+	// Act & Assert
+	assert.NoError(t, registry[TaskCreated].Schema.Validate(taskPayload))
+	assert.NoError(t, registry[TaskUpdated].Schema.Validate(taskPayload))
+	assert.NoError(t, registry[TaskDeleted].Schema.Validate(deletedPayload))
+	task.Status = "archived"
+	invalidPayload, err := json.Marshal(task)
+	require.NoError(t, err)
+	assert.Error(t, registry[TaskUpdated].Schema.Validate(invalidPayload))
+}
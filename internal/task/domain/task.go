@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"fmt"
+	"slices"
 	"time"
 
 	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
@@ -15,14 +17,46 @@ const (
 	TaskFailed    TaskStatus = "failed"
 )
 
+// transitions es la máquina de estados de una tarea: una pendiente se completa o falla, y una
+// fallida se puede reintentar (vuelve a pendiente). Una completada ya no cambia.
+var transitions = map[TaskStatus][]TaskStatus{
+	TaskPending: {TaskCompleted, TaskFailed},
+	TaskFailed:  {TaskPending},
+}
+
+// Valid indica si 's' es uno de los estados de una tarea.
+func (s TaskStatus) Valid() bool {
+	switch s {
+	case TaskPending, TaskCompleted, TaskFailed:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo indica si una tarea en el estado 's' puede pasar a 'to'.
+func (s TaskStatus) CanTransitionTo(to TaskStatus) bool {
+	return slices.Contains(transitions[s], to)
+}
+
 type Task struct {
 	ID          uuid.UUID
 	Title       string
 	Description string
-	AssigneeID  uuid.UUID
-	Status      TaskStatus
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// AssigneeID es el usuario asignado; nil si la tarea está sin asignar.
+	AssigneeID *uuid.UUID
+	Status     TaskStatus
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// Version empieza en 1 y aumenta con cada actualización (ver TaskRepository.Update).
+	Version int64
+}
+
+// AssigneeText devuelve el asignado 'id' como texto, o "" si la tarea está sin asignar.
+func AssigneeText(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
 }
 
 func (t *Task) PartitionKey() string {
@@ -40,6 +74,20 @@ func (t *Task) Fail() {
 	t.UpdatedAt = time.Now()
 }
 
+// TransitionTo pasa la tarea a 'status' en 'now' si la máquina de estados lo permite. Si no, la
+// tarea no cambia y devuelve ErrInvalidTaskStatus o ErrTaskStatusTransition.
+func (t *Task) TransitionTo(status TaskStatus, now time.Time) error {
+	if !status.Valid() {
+		return fmt.Errorf("%w %q", ErrInvalidTaskStatus, status)
+	}
+	if !t.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w from %s to %s", ErrTaskStatusTransition, t.Status, status)
+	}
+	t.Status = status
+	t.UpdatedAt = now
+	return nil
+}
+
 func (t *Task) Update(title, description string) {
 	t.Title = title
 	t.Description = description
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TaskCounts son las tareas pendientes y completadas de un usuario. Las fallidas no cuentan.
+type TaskCounts struct {
+	Pending   int `json:"pending"`
+	Completed int `json:"completed"`
+}
+
+// TaskSummary es la fila de la proyección user_task_counts de un usuario.
+type TaskSummary struct {
+	UserID uuid.UUID `json:"user_id"`
+	TaskCounts
+}
+
+// TaskCounterChange es el estado de una tarea según un evento task.*, lo que la proyección de
+// contadores necesita de él.
+type TaskCounterChange struct {
+	TaskID     uuid.UUID
+	AssigneeID *uuid.UUID
+	Status     TaskStatus
+	// Version es la de la tarea tras el evento; 0 en los eventos anteriores a las versiones.
+	Version int64
+	// Deleted indica un task.deleted, que no trae ni asignado ni estado.
+	Deleted bool
+}
+
+// Supersedes indica si 'c' se aplica sobre 'prev', el último cambio aplicado a la misma tarea
+// (nil si no se ha visto). Un evento repetido o que llega tarde (de una versión que no es
+// posterior) se descarta, y tras un borrado la tarea ya no cambia, así que aplicar los eventos
+// de nuevo, como hace la reconstrucción, no altera los contadores.
+func (c TaskCounterChange) Supersedes(prev *TaskCounterChange) bool {
+	switch {
+	case prev == nil:
+		return true
+	case prev.Deleted:
+		return false
+	case c.Deleted, c.Version == 0, prev.Version == 0:
+		return true
+	}
+	return c.Version > prev.Version
+}
+
+// Deltas devuelve cuánto cambian los contadores de cada usuario al pasar la tarea de 'prev'
+// (nil si no se había visto) a 'c'. Solo aparecen los usuarios cuyos contadores cambian.
+func (c TaskCounterChange) Deltas(prev *TaskCounterChange) map[uuid.UUID]TaskCounts {
+	deltas := make(map[uuid.UUID]TaskCounts, 2)
+	add := func(change *TaskCounterChange, sign int) {
+		if change == nil || change.Deleted || change.AssigneeID == nil {
+			return
+		}
+		d := deltas[*change.AssigneeID]
+		switch change.Status {
+		case TaskPending:
+			d.Pending += sign
+		case TaskCompleted:
+			d.Completed += sign
+		default:
+			return
+		}
+		deltas[*change.AssigneeID] = d
+	}
+	add(prev, -1)
+	add(&c, 1)
+	for user, d := range deltas {
+		if d == (TaskCounts{}) {
+			delete(deltas, user)
+		}
+	}
+	return deltas
+}
+
+// TaskCounterRepository guarda la proyección user_task_counts: las tareas pendientes y
+// completadas de cada asignado, mantenidas a partir de los eventos de tareas, junto con el
+// último cambio aplicado a cada tarea. Todo va por el inquilino de ctx.
+type TaskCounterRepository interface {
+	// Apply aplica 'change' si reemplaza al último cambio de la tarea (ver Supersedes): ajusta
+	// los contadores con sus Deltas y lo guarda como el último, en una sola transacción.
+	Apply(ctx context.Context, change TaskCounterChange) error
+
+	// Summary devuelve los contadores de 'userID'; a cero si no tiene tareas.
+	Summary(ctx context.Context, userID uuid.UUID) (TaskSummary, error)
+
+	// Reset vacía la proyección, antes de reconstruirla desde el outbox.
+	Reset(ctx context.Context) error
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskCounterChange_Deltas(t *testing.T) {
+	ana, luis := uuid.New(), uuid.New()
+	task := uuid.New()
+	state := func(assignee *uuid.UUID, status TaskStatus, version int64) *TaskCounterChange {
+		return &TaskCounterChange{TaskID: task, AssigneeID: assignee, Status: status, Version: version}
+	}
+
+	tests := []struct {
+		name           string
+		prev, change   *TaskCounterChange
+		wantSupersedes bool
+		wantDeltas     map[uuid.UUID]TaskCounts
+	}{
+		{name: "alta", change: state(&ana, TaskPending, 1), wantSupersedes: true, wantDeltas: map[uuid.UUID]TaskCounts{ana: {Pending: 1}}},
+		{name: "completada", prev: state(&ana, TaskPending, 1), change: state(&ana, TaskCompleted, 2), wantSupersedes: true, wantDeltas: map[uuid.UUID]TaskCounts{ana: {Pending: -1, Completed: 1}}},
+		{name: "reasignada", prev: state(&ana, TaskPending, 1), change: state(&luis, TaskPending, 2), wantSupersedes: true, wantDeltas: map[uuid.UUID]TaskCounts{ana: {Pending: -1}, luis: {Pending: 1}}},
+		{name: "fallida no cuenta", prev: state(&ana, TaskPending, 1), change: state(&ana, TaskFailed, 2), wantSupersedes: true, wantDeltas: map[uuid.UUID]TaskCounts{ana: {Pending: -1}}},
+		{name: "sin asignar", change: state(nil, TaskPending, 1), wantSupersedes: true, wantDeltas: map[uuid.UUID]TaskCounts{}},
+		{name: "solo cambia el título", prev: state(&ana, TaskPending, 1), change: state(&ana, TaskPending, 2), wantSupersedes: true, wantDeltas: map[uuid.UUID]TaskCounts{}},
+		{name: "borrada", prev: state(&ana, TaskCompleted, 2), change: &TaskCounterChange{TaskID: task, Deleted: true}, wantSupersedes: true, wantDeltas: map[uuid.UUID]TaskCounts{ana: {Completed: -1}}},
+		{name: "repetida", prev: state(&ana, TaskPending, 2), change: state(&ana, TaskPending, 2)},
+		{name: "llega tarde", prev: state(&ana, TaskCompleted, 3), change: state(&ana, TaskPending, 2)},
+		{name: "tras el borrado", prev: &TaskCounterChange{TaskID: task, Deleted: true}, change: state(&ana, TaskPending, 3)},
+		{name: "sin versiones", prev: state(&ana, TaskPending, 0), change: state(&ana, TaskCompleted, 0), wantSupersedes: true, wantDeltas: map[uuid.UUID]TaskCounts{ana: {Pending: -1, Completed: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			supersedes := tt.change.Supersedes(tt.prev)
+
+			// Assert
+			assert.Equal(t, tt.wantSupersedes, supersedes)
+			if supersedes {
+				assert.Equal(t, tt.wantDeltas, tt.change.Deltas(tt.prev))
+			}
+		})
+	}
+}
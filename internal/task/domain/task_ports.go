@@ -3,29 +3,69 @@ package domain
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
-	"errors"
-
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
 	"github.com/google/uuid"
 )
 
 var (
-	ErrTaskNotFound       = errors.New("task not found")
-	ErrTaskAlreadyExists  = errors.New("task already exists")
-	ErrInvalidTask        = errors.New("invalid task")
-	ErrTaskCannotComplete = errors.New("task cannot be marked as completed")
+	ErrTaskNotFound       = sharedDomain.NewError(sharedDomain.CodeNotFound, "task not found")
+	ErrTaskAlreadyExists  = sharedDomain.NewError(sharedDomain.CodeAlreadyExists, "task already exists")
+	ErrInvalidTask        = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid task")
+	ErrTaskCannotComplete = sharedDomain.NewError(sharedDomain.CodeFailedPrecondition, "task cannot be marked as completed")
+	ErrInvalidTaskStatus  = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid task status")
+	// ErrTaskStatusTransition es el de un cambio de estado que la máquina de estados no admite.
+	ErrTaskStatusTransition = sharedDomain.NewError(sharedDomain.CodeFailedPrecondition, "task status transition not allowed")
+	// ErrTaskVersionConflict es el de una actualización sobre una versión que ya no es la guardada.
+	ErrTaskVersionConflict = sharedDomain.NewError(sharedDomain.CodeAborted, "task version does not match")
+
+	ErrUnsupportedExportFormat = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "unsupported export format")
 )
 
 // --- Repositorio de Tasks ---
 type TaskRepository interface {
 	Create(ctx context.Context, t *Task, evt sharedDomain.OutboxEvent) error
+
+	// Update guarda 't', que trae ya su nueva versión, solo si la guardada es la anterior
+	// (t.Version-1): si es otra devuelve ErrTaskVersionConflict, y si la tarea no existe,
+	// ErrTaskNotFound.
 	Update(ctx context.Context, t *Task, evt sharedDomain.OutboxEvent) error
+
+	// UpdateStatus guarda en una sola escritura el estado de 'tasks', que traen ya el nuevo (el
+	// mismo en todas, con la misma fecha de actualización) y su nueva versión. Como en Update, cada
+	// tarea solo se guarda si la versión guardada es la anterior; las demás (han cambiado o no
+	// existen) se omiten sin error. Cada tarea guardada lleva su evento: el de 'events' cuyo
+	// AggregateID es su id. Devuelve los ids de las tareas guardadas.
+	UpdateStatus(ctx context.Context, tasks []*Task, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Task, error)
-	ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sort sharedQuery.Sort) ([]*Task, error)
+
+	// GetByIDs devuelve las tareas de 'ids' que existen, en cualquier orden y sin error por las que
+	// faltan. Sin ids no consulta nada.
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*Task, error)
+
+	ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*Task, error)
+	CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error)
 	DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error
+
+	// StreamByCriteria recorre las tareas que cumplen 'criteria' en el orden de StreamSorts sin
+	// cargarlas todas en memoria. Si 'fn' devuelve un error, la iteración se detiene y se propaga.
+	StreamByCriteria(ctx context.Context, criteria sharedDomain.Criteria, fn func(*Task) error) error
+}
+
+// StreamSorts es el orden de StreamByCriteria: por fecha de alta y, a igualdad, por id.
+var StreamSorts = []sharedQuery.Sort{{Field: "created_at"}}
+
+// StreamByPages implementa StreamByCriteria sobre ListByCriteria ('list'), página a página con
+// paginación por cursor (ver sharedQuery.Stream), para los repositorios que no tienen nada mejor.
+func StreamByPages(ctx context.Context, list func(context.Context, sharedDomain.Criteria, sharedQuery.Pagination, []sharedQuery.Sort) ([]*Task, error), criteria sharedDomain.Criteria, fn func(*Task) error) error {
+	return sharedQuery.Stream(ctx, sharedQuery.StreamPageSize,
+		func(p sharedQuery.CursorPagination) ([]*Task, error) { return list(ctx, criteria, p, StreamSorts) },
+		func(t *Task) sharedQuery.Cursor { return sharedQuery.NewCursor(t.ID, t.CreatedAt) },
+		fn,
+	)
 }
 
 // DTO para transportar los resultados de la consulta de tendencia.
@@ -35,10 +75,44 @@ type DailyTaskTrend struct {
 	CompletedCount int
 }
 
+// AssigneeStats resume la productividad de un usuario en un rango de fechas.
+type AssigneeStats struct {
+	AssigneeID        uuid.UUID
+	CreatedCount      int
+	CompletedCount    int
+	FailedCount       int
+	AvgCompletionTime time.Duration
+}
+
+// TaskLogEntry es una fila del histórico analítico de tareas (tasks_log).
+type TaskLogEntry struct {
+	ID          uuid.UUID
+	Title       string
+	Description string
+	AssigneeID  *uuid.UUID // nil si la tarea estaba sin asignar
+	Status      TaskStatus
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	EventTime   time.Time
+}
+
 type TaskAnalyticsRepository interface {
 	LogBatch(ctx context.Context, tasks []*Task) error
 	GetAverageCompletionTime(ctx context.Context, start, end time.Time) (time.Duration, error)
 	GetDailyTrend(ctx context.Context, start, end time.Time) ([]DailyTaskTrend, error)
+	GetAssigneeStats(ctx context.Context, assigneeID uuid.UUID, start, end time.Time) (AssigneeStats, error)
+
+	// StreamTaskLog recorre las filas del histórico en el rango dado sin cargarlas todas en memoria.
+	// Si 'fn' devuelve un error, la iteración se detiene y se propaga ese error.
+	StreamTaskLog(ctx context.Context, start, end time.Time, fn func(TaskLogEntry) error) error
+}
+
+// --- Almacenamiento de ficheros (exportaciones) ---
+
+// BlobStorage guarda objetos binarios (p.ej. exportaciones) bajo una clave.
+type BlobStorage interface {
+	// Put consume 'r' hasta EOF y lo guarda bajo 'key'.
+	Put(ctx context.Context, key string, r io.Reader) error
 }
 
 // ---------- Helpers comunes (cache keys, etc.) ----------
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestTask_Complete valida que el método Complete() funcione correctamente.
@@ -65,3 +66,42 @@ func TestTask_Update(t *testing.T) {
 	assert.Equal(t, newDescription, task.Description, "La descripción debería haberse actualizado")
 	assert.True(t, task.UpdatedAt.After(initialUpdateTime), "La fecha de actualización (UpdatedAt) debería haberse modificado")
 }
+
+// TestTask_TransitionTo valida la máquina de estados de TransitionTo.
+func TestTask_TransitionTo(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		from    TaskStatus
+		to      TaskStatus
+		wantErr error
+	}{
+		{name: "pendiente a completada", from: TaskPending, to: TaskCompleted},
+		{name: "pendiente a fallida", from: TaskPending, to: TaskFailed},
+		{name: "fallida a pendiente", from: TaskFailed, to: TaskPending},
+		{name: "completada no cambia", from: TaskCompleted, to: TaskPending, wantErr: ErrTaskStatusTransition},
+		{name: "mismo estado", from: TaskPending, to: TaskPending, wantErr: ErrTaskStatusTransition},
+		{name: "estado inválido", from: TaskPending, to: "archived", wantErr: ErrInvalidTaskStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			before := now.Add(-time.Hour)
+			task := &Task{ID: uuid.New(), Status: tt.from, UpdatedAt: before}
+
+			// Act
+			err := task.TransitionTo(tt.to, now)
+
+			// Assert
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.from, task.Status, "Una transición rechazada no cambia la tarea")
+				assert.Equal(t, before, task.UpdatedAt)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.to, task.Status)
+			assert.Equal(t, now, task.UpdatedAt)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors" // Necesario para la comprobación de errores
+	"fmt"
 	"time"
 
 	"go.uber.org/zap"
@@ -14,12 +15,19 @@ import (
 
 	// --- Importaciones compartidas ---
 	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/redact"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	sharedUtils "github.com/davicafu/hexagolab/internal/shared/infra/utils"
 )
 
+// errNothingToCreate indica que un evento de creación con solo el id (sharedEvents.PayloadID) no
+// trae los datos para crear una tarea que aquí no existe.
+var errNothingToCreate = errors.New("id-only payload carries no data to create the task")
+
 // TaskService es la interfaz que define los métodos que el consumidor necesita.
 type TaskService interface {
-	CreateTask(ctx context.Context, title, description string, assigneeID uuid.UUID) (*taskDomain.Task, error)
+	CreateTask(ctx context.Context, title, description string, assigneeID *uuid.UUID) (*taskDomain.Task, error)
 	UpdateTask(ctx context.Context, t *taskDomain.Task) error
 	GetTaskByID(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error)
 }
@@ -27,7 +35,9 @@ type TaskService interface {
 // TaskConsumer maneja la lógica para procesar eventos de Task.
 type TaskConsumer struct {
 	service TaskService
-	log     *zap.Logger
+	// counters es la proyección de contadores por usuario (ver SetCounters); nil no la mantiene.
+	counters CounterService
+	log      *zap.Logger
 }
 
 // NewTaskConsumer es el constructor.
@@ -40,26 +50,35 @@ func NewTaskConsumer(service TaskService, logger *zap.Logger) *TaskConsumer {
 
 // HandleMessage es el punto de entrada para un nuevo mensaje/evento.
 func (c *TaskConsumer) HandleMessage(ctx context.Context, key string, payload []byte) {
+	// Logger con la traza de la petición de origen (ver events.ConsumerAdapter).
+	log := tracing.Logger(ctx, c.log)
 	var base sharedEvents.IntegrationEvent
 	if err := json.Unmarshal(payload, &base); err != nil {
-		c.log.Warn("Failed to unmarshal integration event for task", zap.String("key", key), zap.Error(err))
+		log.Warn("Failed to unmarshal integration event for task", zap.String("key", key), zap.Error(err))
 		return
 	}
+	// Lo que hagamos al tratar el evento queda causado por él (ver infraEvents.EnvelopeContext).
+	ctx = infraEvents.EnvelopeContext(ctx, base)
+	log = tracing.Logger(ctx, c.log)
+	c.project(ctx, base)
 
 	// Usamos las constantes de eventos compartidas
 	switch base.Type {
 	case taskDomain.TaskCreated:
-		sharedUtils.UnmarshalAndHandle[sharedEvents.TaskCreated](c.log, base.Data, func(evt sharedEvents.TaskCreated) {
+		sharedUtils.UnmarshalAndHandle[sharedEvents.TaskCreated](log, base.Data, func(evt sharedEvents.TaskCreated) {
 			c.withContext(ctx, evt.ID, func(ctxTask context.Context) error {
 				// LÓGICA DE IDEMPOTENCIA: "Buscar antes de Crear"
 				_, err := c.service.GetTaskByID(ctxTask, evt.ID)
 				if err == nil {
-					c.log.Info("Evento 'TaskCreated' duplicado ignorado", zap.String("task_id", evt.ID.String()))
+					log.Info("Evento 'TaskCreated' duplicado ignorado", zap.String("task_id", evt.ID.String()))
 					return nil
 				}
 				if !errors.Is(err, taskDomain.ErrTaskNotFound) {
 					return err
 				}
+				if base.PayloadMode == sharedEvents.PayloadID {
+					return errNothingToCreate
+				}
 
 				// Si no existe, lo creamos.
 				_, err = c.service.CreateTask(ctxTask, evt.Title, evt.Description, evt.AssigneeID)
@@ -68,12 +87,21 @@ func (c *TaskConsumer) HandleMessage(ctx context.Context, key string, payload []
 		})
 
 	case taskDomain.TaskUpdated:
-		sharedUtils.UnmarshalAndHandle[sharedEvents.TaskUpdated](c.log, base.Data, func(evt sharedEvents.TaskUpdated) {
+		sharedUtils.UnmarshalAndHandle[sharedEvents.TaskUpdated](log, base.Data, func(evt sharedEvents.TaskUpdated) {
 			c.withContext(ctx, evt.ID, func(ctxTask context.Context) error {
 				task, err := c.service.GetTaskByID(ctxTask, evt.ID)
 				if err != nil {
 					return err
 				}
+				if evt, err = taskUpdate(task, base.Data); err != nil {
+					return err
+				}
+				// Sin cambios no se actualiza: evita que un servicio que consume sus propios
+				// eventos genere un 'task.updated' tras otro.
+				if task.Title == evt.Title && task.Description == evt.Description && string(task.Status) == evt.Status {
+					log.Info("Evento 'TaskUpdated' sin cambios ignorado", zap.String("task_id", evt.ID.String()))
+					return nil
+				}
 				// Aplicamos los cambios del evento a la entidad
 				task.Title = evt.Title
 				task.Description = evt.Description
@@ -83,38 +111,54 @@ func (c *TaskConsumer) HandleMessage(ctx context.Context, key string, payload []
 			}, "Task updated via event", evt)
 		})
 
+	case taskDomain.TaskDeleted:
+		// Solo lo usa la proyección de contadores.
+
 	default:
-		c.log.Warn("Unknown task event type", zap.String("type", base.Type), zap.String("key", key))
+		log.Warn("Unknown task event type", zap.String("type", base.Type), zap.String("key", key))
+	}
+}
+
+// taskUpdate devuelve el estado de 'task' con los campos de 'data' encima. Un payload completo
+// los trae todos; uno parcial (delta o solo el id) deja como están los que no lleva.
+func taskUpdate(task *taskDomain.Task, data json.RawMessage) (sharedEvents.TaskUpdated, error) {
+	evt := sharedEvents.TaskUpdated{ID: task.ID, Title: task.Title, Description: task.Description, Status: string(task.Status)}
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return evt, fmt.Errorf("decode task update: %w", err)
 	}
+	return evt, nil
 }
 
 // Helper para ejecutar acción con contexto limitado y log.
 func (c *TaskConsumer) withContext(ctx context.Context, id uuid.UUID, action func(ctx context.Context) error, successMsg string, evt interface{}) {
+	log := tracing.Logger(ctx, c.log)
 	ctxTask, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
 	defer cancel()
 
 	if err := action(ctxTask); err != nil {
 		// Alternativa de idempotencia: si el error es que ya existe, lo tratamos como un éxito.
 		if errors.Is(err, taskDomain.ErrTaskAlreadyExists) {
-			c.log.Info("Evento 'TaskCreated' duplicado gestionado por la BBDD", zap.String("task_id", id.String()))
+			log.Info("Evento 'TaskCreated' duplicado gestionado por la BBDD", zap.String("task_id", id.String()))
 			return
 		}
 
-		c.log.Warn("Failed to process task event",
+		log.Warn("Failed to process task event",
 			zap.String("task_id", id.String()),
-			zap.Any("event", evt),
+			redact.Any("event", evt),
 			zap.Error(err),
 		)
 	} else {
-		c.log.Info(successMsg,
+		log.Info(successMsg,
 			zap.String("task_id", id.String()),
-			zap.Any("event", evt),
+			redact.Any("event", evt),
 		)
 	}
 }
 
-// BackgroundConsumerChan inicia una goroutine para consumir eventos de un canal.
-func BackgroundConsumerChan(ctx context.Context, ch <-chan interface{}, consumer *TaskConsumer) {
+// BackgroundConsumerChan inicia una goroutine para consumir eventos de un canal, pasando cada
+// uno por los hooks de consumo de 'hooks'.
+func BackgroundConsumerChan(ctx context.Context, ch <-chan interface{}, consumer *TaskConsumer, hooks *infraEvents.Hooks) {
+	handler := infraEvents.InstrumentHandler(infraEvents.HookHandler(consumer, hooks, consumer.log), "memory", taskDomain.TaskTopic)
 	go func() {
 		for {
 			select {
@@ -125,7 +169,7 @@ func BackgroundConsumerChan(ctx context.Context, ch <-chan interface{}, consumer
 				// Hacemos una aserción de tipo para asegurarnos de que es un []byte
 				if payload, ok := msg.([]byte); ok {
 					// La 'key' no es relevante en el bus en memoria, pasamos una vacía.
-					consumer.HandleMessage(ctx, "", payload)
+					infraEvents.HandleWithRecovery(ctx, handler, "", payload, consumer.log)
 				}
 			}
 		}
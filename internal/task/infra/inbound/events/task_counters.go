@@ -0,0 +1,106 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// CounterService es lo que el consumidor necesita de la proyección de contadores por usuario.
+type CounterService interface {
+	Apply(ctx context.Context, change taskDomain.TaskCounterChange) error
+}
+
+// TaskLookup devuelve el estado actual de una tarea, o taskDomain.ErrTaskNotFound.
+type TaskLookup func(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error)
+
+// counterPayload son los campos de los payloads de task.* que usa la proyección: la tarea
+// completa en task.created y task.updated (o parte de ella, ver sharedEvents.PayloadMode), y
+// solo su id en task.deleted.
+type counterPayload struct {
+	ID         uuid.UUID
+	AssigneeID *uuid.UUID
+	Status     taskDomain.TaskStatus
+	Version    int64
+}
+
+// CounterChange traduce un evento de tareas al cambio que aplica la proyección de contadores.
+// Devuelve false si el tipo de evento no afecta a los contadores. Lo comparten el consumidor y
+// la reconstrucción desde el outbox, para que ambos cuenten igual.
+//
+// Un payload parcial puede no traer el asignado o el estado, así que el cambio se toma del
+// estado actual de la tarea según 'lookup', con su versión: los eventos anteriores que lleguen
+// después ya no la reemplazan. Si la tarea ya no existe, el evento se ignora; su task.deleted
+// la retirará de los contadores.
+func CounterChange(ctx context.Context, evt sharedEvents.IntegrationEvent, lookup TaskLookup) (taskDomain.TaskCounterChange, bool, error) {
+	switch evt.Type {
+	case taskDomain.TaskCreated, taskDomain.TaskUpdated, taskDomain.TaskDeleted:
+	default:
+		return taskDomain.TaskCounterChange{}, false, nil
+	}
+	var payload counterPayload
+	if err := json.Unmarshal(evt.Data, &payload); err != nil {
+		return taskDomain.TaskCounterChange{}, false, fmt.Errorf("decode %s payload: %w", evt.Type, err)
+	}
+	if evt.Type == taskDomain.TaskDeleted {
+		return taskDomain.TaskCounterChange{TaskID: payload.ID, Deleted: true}, true, nil
+	}
+	if evt.PayloadMode.Partial() {
+		task, err := lookup(ctx, payload.ID)
+		if errors.Is(err, taskDomain.ErrTaskNotFound) {
+			return taskDomain.TaskCounterChange{}, false, nil
+		}
+		if err != nil {
+			return taskDomain.TaskCounterChange{}, false, fmt.Errorf("look up task %s: %w", payload.ID, err)
+		}
+		return taskDomain.TaskCounterChange{
+			TaskID:     task.ID,
+			AssigneeID: task.AssigneeID,
+			Status:     task.Status,
+			Version:    task.Version,
+		}, true, nil
+	}
+	return taskDomain.TaskCounterChange{
+		TaskID:     payload.ID,
+		AssigneeID: payload.AssigneeID,
+		Status:     payload.Status,
+		Version:    payload.Version,
+	}, true, nil
+}
+
+// SetCounters hace que el consumidor mantenga también la proyección de contadores con cada
+// evento de tareas, incluidos los task.deleted.
+func (c *TaskConsumer) SetCounters(counters CounterService) {
+	c.counters = counters
+}
+
+// project aplica el evento a la proyección de contadores, si el consumidor la mantiene. Los
+// fallos solo quedan en el log: la proyección se puede reconstruir con el comando replay.
+func (c *TaskConsumer) project(ctx context.Context, evt sharedEvents.IntegrationEvent) {
+	if c.counters == nil {
+		return
+	}
+	log := tracing.Logger(ctx, c.log)
+	ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	change, ok, err := CounterChange(ctx, evt, c.service.GetTaskByID)
+	if err != nil {
+		log.Warn("Failed to decode task event for counters", zap.String("type", evt.Type), zap.Error(err))
+		return
+	}
+	if !ok {
+		return
+	}
+	if err := c.counters.Apply(ctx, change); err != nil {
+		log.Warn("Failed to update task counters", zap.String("task_id", change.TaskID.String()), zap.Error(err))
+	}
+}
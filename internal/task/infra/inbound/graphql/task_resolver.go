@@ -0,0 +1,395 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	sharedGraphql "github.com/davicafu/hexagolab/internal/shared/infra/inbound/graphql"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// defaultListLimit es el tamaño de página cuando la consulta no indica first, como en la API HTTP.
+const defaultListLimit = 50
+
+// Errores de los argumentos, con los mensajes de la API HTTP.
+var (
+	errInvalidTaskID     = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid task id")
+	errInvalidAssigneeID = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid assignee_id")
+)
+
+// Schema es la parte de tareas del esquema GraphQL.
+var Schema = sharedGraphql.Module{
+	Types: `
+type Task {
+	id: ID!
+	title: String!
+	description: String!
+	"pending, completed o failed."
+	status: String!
+	"null si la tarea está sin asignar."
+	assigneeId: ID
+	createdAt: Time!
+	updatedAt: Time!
+}
+
+type TaskConnection {
+	nodes: [Task!]!
+	pageInfo: PageInfo!
+	totalCount: Int!
+}
+
+input TaskFilter {
+	status: String
+	assigneeId: ID
+	"true para las tareas sin asignar."
+	unassigned: Boolean
+	"Texto contenido en el título, sin distinguir mayúsculas."
+	title: String
+	createdAfter: Time
+	createdBefore: Time
+}
+
+input CreateTaskInput {
+	title: String!
+	description: String
+	"Sin asignado, la tarea queda sin asignar."
+	assigneeId: ID
+}
+
+"Los campos que no se indican no cambian."
+input UpdateTaskInput {
+	title: String
+	description: String
+}
+
+"Un evento de tarea publicado en el bus."
+type TaskEvent {
+	id: ID!
+	"task.created, task.updated o task.deleted."
+	type: String!
+	occurredAt: Time!
+	taskId: ID!
+	"La tarea en el momento de recibir el evento; null si ya no existe."
+	task: Task
+}
+`,
+	Query: `
+	task(id: ID!): Task
+	"Tareas que cumplen el filtro, por páginas de first (50 por defecto) desde el cursor after. sort admite los campos de GET /tasks, p.ej. \"status,-created_at\"."
+	tasks(filter: TaskFilter, first: Int, after: String, sort: String): TaskConnection!
+`,
+	Mutation: `
+	createTask(input: CreateTaskInput!): Task!
+	updateTask(id: ID!, input: UpdateTaskInput!): Task!
+	deleteTask(id: ID!): Boolean!
+`,
+	Subscription: `
+	"Eventos de tarea del inquilino de la petición, desde que se suscribe."
+	taskEvents: TaskEvent!
+`,
+}
+
+// Resolver resuelve los campos de tareas del esquema con el servicio de aplicación, con las
+// mismas validaciones que la API HTTP.
+type Resolver struct {
+	service *application.TaskService
+	feed    *infraEvents.Feed
+}
+
+// NewResolver crea el resolver. 'feed' entrega los eventos de taskEvents.
+func NewResolver(service *application.TaskService, feed *infraEvents.Feed) *Resolver {
+	return &Resolver{service: service, feed: feed}
+}
+
+// ---------------- Tipos ----------------
+
+type taskNode struct {
+	ID          graphql.ID
+	Title       string
+	Description string
+	Status      string
+	AssigneeID  *graphql.ID
+	CreatedAt   graphql.Time
+	UpdatedAt   graphql.Time
+}
+
+func toTaskNode(t *taskDomain.Task) *taskNode {
+	node := &taskNode{
+		ID:          graphql.ID(t.ID.String()),
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      string(t.Status),
+		CreatedAt:   graphql.Time{Time: t.CreatedAt},
+		UpdatedAt:   graphql.Time{Time: t.UpdatedAt},
+	}
+	if t.AssigneeID != nil {
+		assigneeID := graphql.ID(t.AssigneeID.String())
+		node.AssigneeID = &assigneeID
+	}
+	return node
+}
+
+type taskConnection struct {
+	Nodes      []*taskNode
+	PageInfo   sharedGraphql.PageInfo
+	TotalCount int32
+}
+
+type taskFilter struct {
+	Status        *string
+	AssigneeID    *graphql.ID
+	Unassigned    *bool
+	Title         *string
+	CreatedAfter  *graphql.Time
+	CreatedBefore *graphql.Time
+}
+
+type createTaskInput struct {
+	Title       string
+	Description *string
+	AssigneeID  *graphql.ID
+}
+
+type updateTaskInput struct {
+	Title       *string
+	Description *string
+}
+
+// ---------------- Consultas ----------------
+
+// Task resuelve task(id): null si no existe.
+func (r *Resolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*taskNode, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, sharedGraphql.Error(errInvalidTaskID)
+	}
+	task, err := r.service.GetTaskByID(ctx, id)
+	if errors.Is(err, taskDomain.ErrTaskNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	return toTaskNode(task), nil
+}
+
+// Tasks resuelve tasks: pagina siempre por cursor.
+func (r *Resolver) Tasks(ctx context.Context, args struct {
+	Filter *taskFilter
+	First  *int32
+	After  *string
+	Sort   *string
+}) (*taskConnection, error) {
+	criteria, err := criteria(args.Filter)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	sorts := []sharedQuery.Sort{{Field: "created_at", Desc: true}}
+	if args.Sort != nil && *args.Sort != "" {
+		parsed, err := sharedQuery.ParseSorts(*args.Sort)
+		if err != nil {
+			return nil, sharedGraphql.Error(err)
+		}
+		sorts = parsed
+	}
+	pagination := sharedQuery.CursorPagination{Limit: defaultListLimit}
+	if args.First != nil {
+		pagination.Limit = sanitize.Limit(int(*args.First), defaultListLimit)
+	}
+	if args.After != nil {
+		pagination.Cursor = *args.After
+	}
+
+	page, err := r.service.ListTasksPage(ctx, criteria, pagination, sorts)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	conn := &taskConnection{
+		Nodes:      make([]*taskNode, 0, len(page.Items)),
+		PageInfo:   sharedGraphql.NewPageInfo(page.NextCursor, page.HasMore),
+		TotalCount: int32(page.Total),
+	}
+	for _, t := range page.Items {
+		conn.Nodes = append(conn.Nodes, toTaskNode(t))
+	}
+	return conn, nil
+}
+
+// criteria traduce el filtro de tasks a los criterios del dominio.
+func criteria(f *taskFilter) (sharedDomain.Criteria, error) {
+	var criterias []sharedDomain.Criteria
+	if f != nil {
+		if f.Status != nil {
+			switch status := taskDomain.TaskStatus(*f.Status); status {
+			case taskDomain.TaskPending, taskDomain.TaskCompleted, taskDomain.TaskFailed:
+				criterias = append(criterias, taskDomain.StatusCriteria{Status: status})
+			default:
+				return nil, sharedDomain.NewError(sharedDomain.CodeInvalidArgument, fmt.Sprintf("invalid status %q", *f.Status))
+			}
+		}
+		if f.AssigneeID != nil {
+			id, err := uuid.Parse(string(*f.AssigneeID))
+			if err != nil {
+				return nil, errInvalidAssigneeID
+			}
+			criterias = append(criterias, taskDomain.AssigneeIDCriteria{ID: id})
+		}
+		if f.Unassigned != nil && *f.Unassigned {
+			criterias = append(criterias, taskDomain.UnassignedCriteria{})
+		}
+		if f.Title != nil {
+			criterias = append(criterias, taskDomain.TitleLikeCriteria{Title: *f.Title})
+		}
+		if f.CreatedAfter != nil || f.CreatedBefore != nil {
+			var created taskDomain.CreatedAtRangeCriteria
+			if f.CreatedAfter != nil {
+				created.Start = &f.CreatedAfter.Time
+			}
+			if f.CreatedBefore != nil {
+				created.End = &f.CreatedBefore.Time
+			}
+			criterias = append(criterias, created)
+		}
+	}
+	return sharedDomain.CompositeCriteria{Operator: sharedDomain.OpAnd, Criterias: criterias}, nil
+}
+
+// ---------------- Mutaciones ----------------
+
+// CreateTask resuelve createTask, como POST /tasks.
+func (r *Resolver) CreateTask(ctx context.Context, args struct{ Input createTaskInput }) (*taskNode, error) {
+	var assigneeID *uuid.UUID
+	if args.Input.AssigneeID != nil {
+		id, err := uuid.Parse(string(*args.Input.AssigneeID))
+		if err != nil {
+			return nil, sharedGraphql.Error(errInvalidAssigneeID)
+		}
+		assigneeID = &id
+	}
+	title, err := sanitize.RequiredLine("title", args.Input.Title, sanitize.MaxTitleLength)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	var description string
+	if args.Input.Description != nil {
+		if description, err = sanitize.Text("description", *args.Input.Description, sanitize.MaxDescriptionLength); err != nil {
+			return nil, sharedGraphql.Error(err)
+		}
+	}
+
+	task, err := r.service.CreateTask(ctx, title, description, assigneeID)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	return toTaskNode(task), nil
+}
+
+// UpdateTask resuelve updateTask, como PUT /tasks/:id.
+func (r *Resolver) UpdateTask(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateTaskInput
+}) (*taskNode, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, sharedGraphql.Error(errInvalidTaskID)
+	}
+	task, err := r.service.GetTaskForUpdate(ctx, id)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+
+	title, description := task.Title, task.Description
+	if args.Input.Title != nil {
+		if title, err = sanitize.RequiredLine("title", *args.Input.Title, sanitize.MaxTitleLength); err != nil {
+			return nil, sharedGraphql.Error(err)
+		}
+	}
+	if args.Input.Description != nil {
+		if description, err = sanitize.Text("description", *args.Input.Description, sanitize.MaxDescriptionLength); err != nil {
+			return nil, sharedGraphql.Error(err)
+		}
+	}
+	task.Update(title, description)
+
+	if err := r.service.UpdateTask(ctx, task); err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	return toTaskNode(task), nil
+}
+
+// DeleteTask resuelve deleteTask, como DELETE /tasks/:id.
+func (r *Resolver) DeleteTask(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return false, sharedGraphql.Error(errInvalidTaskID)
+	}
+	if err := r.service.DeleteTask(ctx, id); err != nil {
+		return false, sharedGraphql.Error(err)
+	}
+	return true, nil
+}
+
+// ---------------- Suscripciones ----------------
+
+type taskEvent struct {
+	ID         graphql.ID
+	Type       string
+	OccurredAt graphql.Time
+	TaskID     graphql.ID
+
+	resolver *Resolver
+}
+
+// Task resuelve la tarea del evento al entregarlo.
+func (e *taskEvent) Task(ctx context.Context) (*taskNode, error) {
+	return e.resolver.Task(ctx, struct{ ID graphql.ID }{e.TaskID})
+}
+
+// TaskEvents resuelve taskEvents con los eventos del topic de tareas que recibe el feed.
+func (r *Resolver) TaskEvents(ctx context.Context) (<-chan *taskEvent, error) {
+	events := r.feed.Subscribe(ctx, taskDomain.TaskTopic)
+	out := make(chan *taskEvent)
+	go func() {
+		defer close(out)
+		for evt := range events {
+			node, ok := r.toTaskEvent(evt)
+			if !ok {
+				continue
+			}
+			select {
+			case out <- node:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// toTaskEvent traduce un evento del bus; descarta los que no llevan el id de la tarea.
+func (r *Resolver) toTaskEvent(evt sharedEvents.IntegrationEvent) (*taskEvent, bool) {
+	var data struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := json.Unmarshal(evt.Data, &data); err != nil || data.ID == uuid.Nil {
+		return nil, false
+	}
+	return &taskEvent{
+		ID:         graphql.ID(evt.EventID.String()),
+		Type:       evt.Type,
+		OccurredAt: graphql.Time{Time: evt.OccurredAt},
+		TaskID:     graphql.ID(data.ID.String()),
+		resolver:   r,
+	}, true
+}
@@ -2,16 +2,26 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/davicafu/hexagolab/internal/task/application"
 	"github.com/google/uuid"
 
 	// Importa el código generado por protoc
 	pb "github.com/davicafu/hexagolab/gen/go/task"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedGrpc "github.com/davicafu/hexagolab/internal/shared/infra/inbound/grpc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// defaultListLimit es el tamaño de página cuando la petición no indica limit, como en la API HTTP.
+const defaultListLimit = 50
+
 // GrpcTaskServer implementa la interfaz generada por gRPC.
 type GrpcTaskServer struct {
 	// Es necesario para la compatibilidad hacia adelante de gRPC.
@@ -23,18 +33,30 @@ func NewGrpcTaskServer(service *application.TaskService) *GrpcTaskServer {
 	return &GrpcTaskServer{service: service}
 }
 
-// CreateTask es la implementación del RPC.
+// CreateTask es la implementación del RPC. Sin assignee_id la tarea queda sin asignar.
 func (s *GrpcTaskServer) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.CreateTaskResponse, error) {
-	assigneeID, err := uuid.Parse(req.GetAssigneeId())
+	var assigneeID *uuid.UUID
+	if a := req.GetAssigneeId(); a != "" {
+		id, err := uuid.Parse(a)
+		if err != nil {
+			// gRPC tiene su propio sistema de errores detallados
+			return nil, status.Errorf(codes.InvalidArgument, "invalid assignee_id format")
+		}
+		assigneeID = &id
+	}
+	title, err := sanitize.RequiredLine("title", req.GetTitle(), sanitize.MaxTitleLength)
+	if err != nil {
+		return nil, sharedGrpc.Error(err)
+	}
+	description, err := sanitize.Text("description", req.GetDescription(), sanitize.MaxDescriptionLength)
 	if err != nil {
-		// gRPC tiene su propio sistema de errores detallados
-		return nil, status.Errorf(codes.InvalidArgument, "invalid assignee_id format")
+		return nil, sharedGrpc.Error(err)
 	}
 
 	// 1. Llama a tu lógica de aplicación (no cambia nada aquí)
-	task, err := s.service.CreateTask(ctx, req.GetTitle(), req.GetDescription(), assigneeID)
+	task, err := s.service.CreateTask(ctx, title, description, assigneeID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "could not create task: %v", err)
+		return nil, sharedGrpc.Error(fmt.Errorf("could not create task: %w", err))
 	}
 
 	// 2. Convierte la respuesta de tu dominio al formato de Protobuf
@@ -44,3 +66,71 @@ func (s *GrpcTaskServer) CreateTask(ctx context.Context, req *pb.CreateTaskReque
 		Status: string(task.Status),
 	}, nil
 }
+
+// ListTasks lista las tareas que cumplen los filtros, de más reciente a más antigua. Con cursor
+// pagina por cursor y next_cursor lleva el de la página siguiente (ver sharedGrpc.Pagination).
+func (s *GrpcTaskServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	var criterias []sharedDomain.Criteria
+	if st := req.GetStatus(); st != "" {
+		switch taskDomain.TaskStatus(st) {
+		case taskDomain.TaskPending, taskDomain.TaskCompleted, taskDomain.TaskFailed:
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid status %q", st)
+		}
+		criterias = append(criterias, taskDomain.StatusCriteria{Status: taskDomain.TaskStatus(st)})
+	}
+	if a := req.GetAssigneeId(); a != "" {
+		id, err := uuid.Parse(a)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid assignee_id format")
+		}
+		criterias = append(criterias, taskDomain.AssigneeIDCriteria{ID: id})
+	}
+	if req.GetUnassigned() {
+		criterias = append(criterias, taskDomain.UnassignedCriteria{})
+	}
+
+	limit := sanitize.Limit(int(req.GetLimit()), defaultListLimit)
+	page, err := s.service.ListTasksPage(ctx,
+		sharedDomain.CompositeCriteria{Operator: sharedDomain.OpAnd, Criterias: criterias},
+		sharedGrpc.Pagination(limit, req.GetOffset(), req.Cursor),
+		[]sharedQuery.Sort{{Field: "created_at", Desc: true}},
+	)
+	if err != nil {
+		return nil, sharedGrpc.Error(fmt.Errorf("could not list tasks: %w", err))
+	}
+
+	resp := &pb.ListTasksResponse{
+		Tasks:      make([]*pb.Task, 0, len(page.Items)),
+		HasMore:    page.HasMore,
+		Total:      int32(page.Total),
+		NextCursor: page.NextCursor,
+	}
+	for _, t := range page.Items {
+		resp.Tasks = append(resp.Tasks, &pb.Task{
+			Id:          t.ID.String(),
+			Title:       t.Title,
+			Description: t.Description,
+			AssigneeId:  taskDomain.AssigneeText(t.AssigneeID),
+			Status:      string(t.Status),
+			CreatedAt:   timestamppb.New(t.CreatedAt),
+			UpdatedAt:   timestamppb.New(t.UpdatedAt),
+		})
+	}
+	return resp, nil
+}
+
+// DeleteTask borra una tarea; el evento de borrado se publica a través del outbox.
+func (s *GrpcTaskServer) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid id format")
+	}
+	if err := s.service.DeleteTask(ctx, id); err != nil {
+		return nil, sharedGrpc.Error(err)
+	}
+	return &pb.DeleteTaskResponse{}, nil
+}
+
+// Verificación estática
+var _ pb.TaskServiceServer = (*GrpcTaskServer)(nil)
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/davicafu/hexagolab/gen/go/task"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
+	"github.com/davicafu/hexagolab/internal/task/application"
+	taskMemory "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/memory"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+func TestListTasks_CursorPagination(t *testing.T) {
+	// Arrange: tres tareas creadas con un minuto de diferencia.
+	ctx := context.Background()
+	clock := mocks.NewFakeClock(time.Date(2025, time.March, 1, 10, 30, 0, 0, time.UTC))
+	repo := taskMemory.NewTaskRepoMemory(sharedMemory.NewOutboxRepoMemory())
+	service := application.NewTaskService(repo, mocks.NewDummyCache(), nil, clock, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+	for _, title := range []string{"Revisar el backlog", "Preparar la demo", "Migrar el outbox"} {
+		_, err := service.CreateTask(ctx, title, "", nil)
+		require.NoError(t, err)
+		clock.Advance(time.Minute)
+	}
+	server := NewGrpcTaskServer(service)
+
+	// Act
+	first, err := server.ListTasks(ctx, &pb.ListTasksRequest{Limit: 2, Cursor: proto.String("")})
+	require.NoError(t, err)
+	second, err := server.ListTasks(ctx, &pb.ListTasksRequest{Limit: 2, Cursor: proto.String(first.GetNextCursor())})
+	require.NoError(t, err)
+	byOffset, err := server.ListTasks(ctx, &pb.ListTasksRequest{Limit: 2})
+	require.NoError(t, err)
+	_, invalidErr := server.ListTasks(ctx, &pb.ListTasksRequest{Cursor: proto.String("not-a-cursor")})
+
+	// Assert
+	assert.Equal(t, []string{"Migrar el outbox", "Preparar la demo"}, titles(first.GetTasks()))
+	assert.NotEmpty(t, first.GetNextCursor())
+	assert.True(t, first.GetHasMore())
+	assert.Equal(t, []string{"Revisar el backlog"}, titles(second.GetTasks()))
+	assert.Empty(t, second.GetNextCursor(), "La última página no lleva cursor")
+	assert.False(t, second.GetHasMore())
+	assert.Empty(t, byOffset.GetNextCursor(), "Sin cursor se pagina por offset")
+	assert.Equal(t, codes.InvalidArgument, status.Code(invalidErr))
+}
+
+func titles(tasks []*pb.Task) []string {
+	var out []string
+	for _, task := range tasks {
+		out = append(out, task.GetTitle())
+	}
+	return out
+}
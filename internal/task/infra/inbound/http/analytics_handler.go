@@ -0,0 +1,165 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	"github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// AnalyticsHandler encapsula los endpoints HTTP de analítica de tareas.
+type AnalyticsHandler struct {
+	service *application.TaskAnalyticsService
+	log     *zap.Logger
+}
+
+// NewAnalyticsHandler crea un nuevo AnalyticsHandler.
+func NewAnalyticsHandler(service *application.TaskAnalyticsService, log *zap.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service, log: log}
+}
+
+var exportContentTypes = map[application.ExportFormat]string{
+	application.ExportCSV:     "text/csv",
+	application.ExportParquet: "application/vnd.apache.parquet",
+}
+
+// GetDailyTrend endpoint GET /analytics/tasks/trend?start=...&end=...
+func (h *AnalyticsHandler) GetDailyTrend(c *gin.Context) {
+	start, end, err := parseTimeRange(c)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	trend, err := h.service.GetDailyTrend(c.Request.Context(), start, end)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
+
+// GetAverageCompletionTime endpoint GET /analytics/tasks/completion-time?start=...&end=...
+func (h *AnalyticsHandler) GetAverageCompletionTime(c *gin.Context) {
+	start, end, err := parseTimeRange(c)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	avg, err := h.service.GetAverageCompletionTime(c.Request.Context(), start, end)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"avg_completion_seconds": avg.Seconds()})
+}
+
+// GetAssigneeStats endpoint GET /analytics/tasks/assignees/:id/stats?start=...&end=...
+func (h *AnalyticsHandler) GetAssigneeStats(c *gin.Context) {
+	assigneeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		sharedHttp.WriteError(c, sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid assignee id"))
+		return
+	}
+
+	start, end, err := parseTimeRange(c)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	stats, err := h.service.GetAssigneeStats(c.Request.Context(), assigneeID, start, end)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"assignee_id":            stats.AssigneeID,
+		"created":                stats.CreatedCount,
+		"completed":              stats.CompletedCount,
+		"failed":                 stats.FailedCount,
+		"avg_completion_seconds": stats.AvgCompletionTime.Seconds(),
+	})
+}
+
+// ExportTasks endpoint GET /analytics/tasks/export?format=csv|parquet&start=...&end=...[&destination=storage]
+func (h *AnalyticsHandler) ExportTasks(c *gin.Context) {
+	format := application.ExportFormat(c.DefaultQuery("format", string(application.ExportCSV)))
+	if !h.service.SupportsFormat(format) {
+		sharedHttp.WriteError(c, fmt.Errorf("%w: %s", taskDomain.ErrUnsupportedExportFormat, format))
+		return
+	}
+
+	start, end, err := parseTimeRange(c)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	// Exportación al almacenamiento: devolvemos la clave del objeto generado.
+	if c.Query("destination") == "storage" {
+		key, err := h.service.ExportTaskLogToStorage(c.Request.Context(), format, start, end)
+		if err != nil {
+			sharedHttp.WriteError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"key": key})
+		return
+	}
+
+	// Exportación en streaming: a partir de aquí las cabeceras ya están enviadas,
+	// así que un error a mitad de la respuesta solo se puede registrar.
+	filename := fmt.Sprintf("tasks_log_%s_%s.%s", start.Format("20060102"), end.Format("20060102"), format)
+	c.Header("Content-Type", exportContentTypes[format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := h.service.ExportTaskLog(c.Request.Context(), format, start, end, c.Writer); err != nil {
+		h.log.Error("Task export aborted mid-stream", zap.Error(err))
+	}
+}
+
+// parseTimeRange lee 'start' y 'end' (RFC3339 o YYYY-MM-DD). Por defecto, los últimos 30 días.
+// Un rango inválido es un sharedDomain.CodeInvalidArgument.
+func parseTimeRange(c *gin.Context) (time.Time, time.Time, error) {
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -30)
+
+	if v := c.Query("start"); v != "" {
+		t, err := parseTimeParam(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, sharedDomain.Errorf(sharedDomain.CodeInvalidArgument, "invalid start: %w", err)
+		}
+		start = t
+	}
+	if v := c.Query("end"); v != "" {
+		t, err := parseTimeParam(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, sharedDomain.Errorf(sharedDomain.CodeInvalidArgument, "invalid end: %w", err)
+		}
+		end = t
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "end must be after start")
+	}
+	return start, end, nil
+}
+
+func parseTimeParam(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
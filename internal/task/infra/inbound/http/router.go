@@ -7,10 +7,35 @@ func RegisterTaskRoutes(r *gin.Engine, handler *TaskHandler) {
 	// Agrupamos todas las rutas de tareas bajo el prefijo "/tasks"
 	tasks := r.Group("/tasks")
 	{
-		tasks.POST("/", handler.CreateTask)      // Crear una nueva tarea
-		tasks.GET("/", handler.ListTasks)        // Listar todas las tareas
-		tasks.GET("/:id", handler.GetTask)       // Obtener una tarea por su ID
-		tasks.PUT("/:id", handler.UpdateTask)    // Actualizar una tarea existente
-		tasks.DELETE("/:id", handler.DeleteTask) // Eliminar una tarea
+		tasks.POST("/", handler.CreateTask)            // Crear una nueva tarea
+		tasks.GET("/", handler.ListTasks)              // Listar todas las tareas, o las de ?ids=a,b,c
+		tasks.GET("/stream", handler.StreamTasks)      // Todas las tareas filtradas, en NDJSON
+		tasks.GET("/export", handler.ExportTasks)      // Tareas filtradas, en NDJSON o CSV
+		tasks.POST("/import", handler.ImportTasks)     // Alta de una tarea por fila de NDJSON o CSV
+		tasks.POST("/bulk-status", handler.BulkStatus) // Cambiar el estado de varias tareas
+		tasks.GET("/:id", handler.GetTask)             // Obtener una tarea por su ID
+		tasks.PUT("/:id", handler.UpdateTask)          // Actualizar una tarea existente
+		tasks.DELETE("/:id", handler.DeleteTask)       // Eliminar una tarea
+	}
+}
+
+// RegisterTaskUpdatesRoutes registra el long polling de las tareas de un usuario.
+func RegisterTaskUpdatesRoutes(r *gin.Engine, handler *TaskUpdatesHandler) {
+	r.GET("/tasks/updates", handler.Updates) // Espera a los eventos de las tareas de ?assignee_id
+}
+
+// RegisterTaskSummaryRoutes registra los contadores de tareas de un usuario.
+func RegisterTaskSummaryRoutes(r *gin.Engine, handler *TaskSummaryHandler) {
+	r.GET("/users/:id/task-summary", handler.GetTaskSummary) // Tareas pendientes y completadas
+}
+
+// RegisterAnalyticsRoutes registra las rutas HTTP de analítica de tareas.
+func RegisterAnalyticsRoutes(r *gin.Engine, handler *AnalyticsHandler) {
+	analytics := r.Group("/analytics/tasks")
+	{
+		analytics.GET("/trend", handler.GetDailyTrend)                      // Creadas/completadas por día
+		analytics.GET("/completion-time", handler.GetAverageCompletionTime) // Tiempo medio de finalización
+		analytics.GET("/assignees/:id/stats", handler.GetAssigneeStats)     // Productividad por usuario
+		analytics.GET("/export", handler.ExportTasks)                       // Exportar el histórico (csv, parquet)
 	}
 }
@@ -2,78 +2,117 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
 	"github.com/davicafu/hexagolab/internal/task/application"
 	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/davicafu/hexagolab/pkg/dataio"
+	response "github.com/davicafu/hexagolab/pkg/utils"
+)
+
+// Errores de los parámetros de la petición.
+var (
+	errInvalidTaskID     = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid task id")
+	errInvalidAssigneeID = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid assignee_id")
 )
 
 // TaskHandler encapsula los endpoints HTTP relacionados con Task.
 type TaskHandler struct {
 	service *application.TaskService
+	filters *sharedHttp.CriteriaBinder
 }
 
-// NewTaskHandler crea un nuevo TaskHandler.
-func NewTaskHandler(service *application.TaskService) *TaskHandler {
-	return &TaskHandler{service: service}
+// NewTaskHandler crea un nuevo TaskHandler. 'clock' es la hora de referencia de los filtros
+// relativos (created_within, updated_before).
+func NewTaskHandler(service *application.TaskService, clock sharedDomain.Clock) *TaskHandler {
+	return &TaskHandler{service: service, filters: newTaskFilters(clock)}
 }
 
 // --- Handlers CRUD ---
 
-// CreateTask endpoint POST /tasks
+// CreateTask endpoint POST /tasks. Sin assignee_id (o con null) la tarea queda sin asignar; se
+// sigue aceptando el nombre antiguo, assigneeId.
 func (h *TaskHandler) CreateTask(c *gin.Context) {
 	var req struct {
-		Title       string    `json:"title" binding:"required"`
-		Description string    `json:"description"`
-		AssigneeID  uuid.UUID `json:"assigneeId" binding:"required"`
+		Title            string     `json:"title" binding:"required"`
+		Description      string     `json:"description"`
+		AssigneeID       *uuid.UUID `json:"assignee_id"`
+		LegacyAssigneeID *uuid.UUID `json:"assigneeId"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		sharedHttp.WriteError(c, sharedDomain.WrapError(sharedDomain.CodeInvalidArgument, err))
 		return
 	}
+	if req.AssigneeID == nil {
+		req.AssigneeID = req.LegacyAssigneeID
+	}
 
-	task, err := h.service.CreateTask(c.Request.Context(), req.Title, req.Description, req.AssigneeID)
+	task, err := h.createTask(c.Request.Context(), req.Title, req.Description, req.AssigneeID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, task)
+	c.JSON(http.StatusCreated, newTaskResponse(task))
+}
+
+// createTask valida los campos de un alta, de POST /tasks o de una fila importada, y crea la tarea.
+func (h *TaskHandler) createTask(ctx context.Context, rawTitle, rawDescription string, assigneeID *uuid.UUID) (*taskDomain.Task, error) {
+	title, err := sanitize.RequiredLine("title", rawTitle, sanitize.MaxTitleLength)
+	if err != nil {
+		return nil, err
+	}
+	description, err := sanitize.Text("description", rawDescription, sanitize.MaxDescriptionLength)
+	if err != nil {
+		return nil, err
+	}
+	return h.service.CreateTask(ctx, title, description, assigneeID)
 }
 
 // GetTask endpoint GET /tasks/:id
 func (h *TaskHandler) GetTask(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		sharedHttp.WriteError(c, errInvalidTaskID)
 		return
 	}
 
 	task, err := h.service.GetTaskByID(c.Request.Context(), id)
 	if err != nil {
-		if err == taskDomain.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
+	if sharedHttp.NotModified(c, sharedHttp.VersionETag(task.Version)) {
+		return
+	}
+	c.JSON(http.StatusOK, newTaskResponse(task))
 }
 
 // UpdateTask endpoint PUT /tasks/:id
+// Con If-Match (el ETag de GET /tasks/:id) solo actualiza si la tarea sigue en esa versión y, si
+// no, responde 412; sin él, gana la última escritura.
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		sharedHttp.WriteError(c, errInvalidTaskID)
+		return
+	}
+	version, conditional, err := sharedHttp.IfMatch(c)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
@@ -84,93 +123,355 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		sharedHttp.WriteError(c, sharedDomain.WrapError(sharedDomain.CodeInvalidArgument, err))
 		return
 	}
 
-	task, err := h.service.GetTaskByID(c.Request.Context(), id)
+	task, err := h.service.GetTaskForUpdate(c.Request.Context(), id)
 	if err != nil {
-		// Manejar error de "no encontrado"
-		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
 	// Aplicamos los cambios si se proporcionaron
 	if req.Title != nil {
-		task.Title = *req.Title
+		if task.Title, err = sanitize.RequiredLine("title", *req.Title, sanitize.MaxTitleLength); err != nil {
+			sharedHttp.WriteError(c, err)
+			return
+		}
 	}
 	if req.Description != nil {
-		task.Description = *req.Description
+		if task.Description, err = sanitize.Text("description", *req.Description, sanitize.MaxDescriptionLength); err != nil {
+			sharedHttp.WriteError(c, err)
+			return
+		}
 	}
 
 	// Llamamos al método Update del dominio
 	task.Update(task.Title, task.Description)
 
-	if err := h.service.UpdateTask(c.Request.Context(), task); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if conditional {
+		err = h.service.CompareAndUpdateTask(c.Request.Context(), task, version)
+	} else {
+		err = h.service.UpdateTask(c.Request.Context(), task)
+	}
+	if err != nil {
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
+	c.Header("ETag", sharedHttp.VersionETag(task.Version))
+	c.JSON(http.StatusOK, newTaskResponse(task))
 }
 
 // DeleteTask endpoint DELETE /tasks/:id
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		sharedHttp.WriteError(c, errInvalidTaskID)
 		return
 	}
 
 	if err := h.service.DeleteTask(c.Request.Context(), id); err != nil {
-		if err == taskDomain.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
-// ListTasks endpoint GET /tasks con filtros, paginación y ordenamiento
-func (h *TaskHandler) ListTasks(c *gin.Context) {
-	var criterias []sharedDomain.Criteria
+// taskResponse es una tarea en las respuestas de la API, con los nombres de campo en snake_case
+// como los de los usuarios. Una tarea sin asignar lleva assignee_id a null.
+type taskResponse struct {
+	ID          uuid.UUID             `json:"id"`
+	Title       string                `json:"title"`
+	Description string                `json:"description"`
+	AssigneeID  *uuid.UUID            `json:"assignee_id"`
+	Status      taskDomain.TaskStatus `json:"status"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+	Version     int64                 `json:"version"`
+}
 
-	// --- Filtros desde query params ---
-	if title := c.Query("title"); title != "" {
-		criterias = append(criterias, taskDomain.TitleLikeCriteria{Title: title})
+func newTaskResponse(t *taskDomain.Task) *taskResponse {
+	if t == nil {
+		return nil
+	}
+	return &taskResponse{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		AssigneeID:  t.AssigneeID,
+		Status:      t.Status,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		Version:     t.Version,
+	}
+}
+
+func newTaskResponses(tasks []*taskDomain.Task) []*taskResponse {
+	body := make([]*taskResponse, len(tasks))
+	for i, t := range tasks {
+		body[i] = newTaskResponse(t)
+	}
+	return body
+}
+
+// statusResult es el resultado de una tarea en la respuesta de POST /tasks/bulk-status.
+type statusResult struct {
+	ID    uuid.UUID               `json:"id"`
+	Task  *taskResponse           `json:"task,omitempty"`
+	Error *response.ErrorResponse `json:"error,omitempty"`
+}
+
+// BulkStatus endpoint POST /tasks/bulk-status {"ids": [...], "status": "completed"}: pasa las
+// tareas al estado pedido con una sola escritura, en lugar de un PUT por tarea. Responde 200 con
+// el resultado de cada id, en el orden pedido: la tarea actualizada o el error por el que no
+// cambió (not_found, failed_precondition si la transición no está permitida, aborted si otra
+// escritura la cambió a la vez). Un estado inválido o más de sharedQuery.MaxIDs ids es un 400.
+func (h *TaskHandler) BulkStatus(c *gin.Context) {
+	var req struct {
+		IDs    []uuid.UUID `json:"ids" binding:"required"`
+		Status string      `json:"status" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sharedHttp.WriteError(c, sharedDomain.WrapError(sharedDomain.CodeInvalidArgument, err))
+		return
 	}
-	if status := c.Query("status"); status != "" {
-		criterias = append(criterias, taskDomain.StatusCriteria{Status: taskDomain.TaskStatus(status)})
+
+	results, err := h.service.BulkTransitionStatus(c.Request.Context(), req.IDs, taskDomain.TaskStatus(req.Status))
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
 	}
-	if assigneeID := c.Query("assigneeId"); assigneeID != "" {
-		if id, err := uuid.Parse(assigneeID); err == nil {
-			criterias = append(criterias, taskDomain.AssigneeIDCriteria{ID: id})
+
+	body := make([]statusResult, len(results))
+	updated := 0
+	for i, r := range results {
+		body[i] = statusResult{ID: r.ID, Task: newTaskResponse(r.Task)}
+		if r.Err != nil {
+			errBody := sharedHttp.ErrorBody(r.Err)
+			body[i].Error = &errBody
+		} else {
+			updated++
 		}
 	}
+	c.JSON(http.StatusOK, gin.H{"results": body, "updated": updated})
+}
 
-	criteria := sharedDomain.And(criterias...)
+// newTaskFilters declara los parámetros de GET /tasks por los que se puede filtrar.
+func newTaskFilters(clock sharedDomain.Clock) *sharedHttp.CriteriaBinder {
+	return sharedHttp.NewCriteriaBinder(
+		sharedHttp.Filter{Param: "title", Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			return taskDomain.TitleLikeCriteria{Title: v.(string)}
+		}},
+		sharedHttp.Filter{
+			Param: "status",
+			Enum:  []string{string(taskDomain.TaskPending), string(taskDomain.TaskCompleted), string(taskDomain.TaskFailed)},
+			Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+				return taskDomain.StatusCriteria{Status: taskDomain.TaskStatus(v.(string))}
+			},
+		},
+		sharedHttp.Filter{Param: "assignee_id", Type: sharedHttp.FilterUUID, Criteria: assigneeFilter},
+		// Nombre antiguo de assignee_id, que se sigue aceptando.
+		sharedHttp.Filter{Param: "assigneeId", Type: sharedHttp.FilterUUID, Criteria: assigneeFilter},
+		sharedHttp.Filter{Param: "unassigned", Enum: []string{"true"}, Criteria: func(sharedDomain.Operator, any) sharedDomain.Criteria {
+			return taskDomain.UnassignedCriteria{}
+		}},
+		sharedHttp.Filter{
+			Param: "created_at",
+			Type:  sharedHttp.FilterTime,
+			Ops:   []sharedDomain.Operator{sharedDomain.OpGte, sharedDomain.OpLte},
+			Criteria: func(op sharedDomain.Operator, v any) sharedDomain.Criteria {
+				at := v.(time.Time)
+				if op == sharedDomain.OpGte {
+					return taskDomain.CreatedAtRangeCriteria{Start: &at}
+				}
+				return taskDomain.CreatedAtRangeCriteria{End: &at}
+			},
+		},
+		sharedHttp.Filter{Param: "created_within", Type: sharedHttp.FilterDuration, Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			return sharedDomain.CreatedWithinCriteria{Duration: v.(time.Duration), Clock: clock}
+		}},
+		sharedHttp.Filter{Param: "updated_before", Type: sharedHttp.FilterDuration, Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			return sharedDomain.UpdatedBeforeCriteria{Duration: v.(time.Duration), Clock: clock}
+		}},
+	)
+}
+
+// assigneeFilter es el criterio del filtro por asignado.
+func assigneeFilter(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+	return taskDomain.AssigneeIDCriteria{ID: v.(uuid.UUID)}
+}
+
+// bindFilters lee los filtros de la query. Si alguno es inválido responde 400 con el motivo de
+// cada parámetro y devuelve false.
+func (h *TaskHandler) bindFilters(c *gin.Context) (sharedDomain.Criteria, bool) {
+	criteria, err := h.filters.Bind(c.Request.URL.Query())
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return nil, false
+	}
+	return criteria, true
+}
+
+// ListTasks endpoint GET /tasks con filtros, paginación y ordenamiento. Con ?ids=a,b,c devuelve
+// esas tareas (ver getTasks). Con el parámetro cursor, aunque esté vacío (primera página), se
+// pagina por cursor, como en GET /users, y X-Next-Cursor lleva el de la página siguiente.
+func (h *TaskHandler) ListTasks(c *gin.Context) {
+	if ids, ok := c.GetQuery("ids"); ok {
+		h.getTasks(c, ids)
+		return
+	}
+
+	// --- Filtros desde query params ---
+	criteria, ok := h.bindFilters(c)
+	if !ok {
+		return
+	}
 
 	// --- Sort (lógica idéntica a la de User) ---
-	sortParam := sharedQuery.Sort{Field: "created_at", Desc: true}
-	if sortField := c.Query("sort_field"); sortField != "" {
-		sortParam.Field = sortField
-		sortParam.Desc = c.Query("sort_desc") == "true"
+	// "sort" admite varios campos (ej. sort=status,-created_at); sort_field y sort_desc, uno solo.
+	sorts := []sharedQuery.Sort{{Field: "created_at", Desc: true}}
+	if sortList := c.Query("sort"); sortList != "" {
+		parsed, err := sharedQuery.ParseSorts(sortList)
+		if err != nil {
+			sharedHttp.WriteError(c, err)
+			return
+		}
+		sorts = parsed
+	} else if sortField := c.Query("sort_field"); sortField != "" {
+		sorts[0] = sharedQuery.Sort{Field: sortField, Desc: c.Query("sort_desc") == "true"}
 	}
 
 	// --- Paginación (lógica idéntica a la de User) ---
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	pagination := sharedQuery.OffsetPagination{Limit: limit, Offset: offset}
+	limit = sanitize.Limit(limit, 50)
+	var pagination sharedQuery.Pagination
+	if cursor, byCursor := c.GetQuery("cursor"); byCursor {
+		pagination = sharedQuery.CursorPagination{Limit: limit, Cursor: cursor}
+	} else {
+		offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		pagination = sharedQuery.OffsetPagination{Limit: limit, Offset: offset}
+	}
 
 	// --- Llamada al servicio ---
-	tasks, err := h.service.ListTasks(c.Request.Context(), criteria, pagination, sortParam)
+	page, err := h.service.ListTasksPage(c.Request.Context(), criteria, pagination, sorts)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	// El listado de tareas responde con el array sin sobre, así que la paginación va en cabeceras.
+	response.SetPageHeaders(c, response.PageMeta{NextCursor: page.NextCursor, HasMore: page.HasMore, Total: page.Total})
+	c.JSON(http.StatusOK, newTaskResponses(page.Items))
+}
+
+// getTasks responde GET /tasks?ids=a,b,c: las tareas que existen, en el orden pedido, en una
+// sola petición en vez de un GET /tasks/:id por tarea. Las que no existen no aparecen.
+func (h *TaskHandler) getTasks(c *gin.Context, raw string) {
+	ids, err := sharedHttp.ParseIDs(raw)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	tasks, err := h.service.GetTasksByIDs(c.Request.Context(), ids)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		sharedHttp.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newTaskResponses(tasks))
+}
+
+// streamFlushEvery es cada cuántas tareas se vacía el buffer de GET /tasks/stream.
+const streamFlushEvery = 100
+
+// StreamTasks endpoint GET /tasks/stream: todas las tareas que cumplen los filtros de GET /tasks,
+// por fecha de alta, en NDJSON (una tarea en JSON por línea). El servidor las lee página a página,
+// así que sirve para exportar listados que no caben en memoria.
+func (h *TaskHandler) StreamTasks(c *gin.Context) {
+	criteria, ok := h.bindFilters(c)
+	if !ok {
+		return
+	}
+
+	// Las cabeceras se envían con la primera tarea: hasta entonces un error aún puede ser un 4xx/5xx.
+	started := false
+	start := func() {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		started = true
+	}
+	enc := json.NewEncoder(c.Writer)
+	written := 0
+	err := h.service.StreamTasks(c.Request.Context(), criteria, func(t *taskDomain.Task) error {
+		if !started {
+			start()
+		}
+		if err := enc.Encode(newTaskResponse(t)); err != nil {
+			return err
+		}
+		if written++; written%streamFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil && !started:
+		start()
+		c.Writer.WriteHeaderNow()
+	case err != nil && started:
+		// Con la respuesta a medias solo queda cortarla y dejar el error en el access log.
+		_ = c.Error(err)
+	case err != nil:
+		sharedHttp.WriteError(c, err)
+	}
+}
+
+// ---------------- Exportación e importación ----------------
+
+// taskColumns son las columnas de GET /tasks/export. POST /tasks/import lee title, description y
+// assignee_id (o assigneeId) e ignora las demás, así que una exportación se puede importar.
+var taskColumns = []string{"id", "title", "description", "status", "assignee_id", "created_at", "updated_at"}
+
+// ExportTasks endpoint GET /tasks/export?format=ndjson|csv: las tareas de GET /tasks/stream, con
+// columnas planas para abrirlas en una hoja de cálculo o volver a importarlas.
+func (h *TaskHandler) ExportTasks(c *gin.Context) {
+	format, ok := sharedHttp.DataFormat(c)
+	if !ok {
+		return
+	}
+	criteria, ok := h.bindFilters(c)
+	if !ok {
 		return
 	}
+	sharedHttp.Export(c, format, "tasks", taskColumns, func(write func([]string) error) error {
+		return h.service.StreamTasks(c.Request.Context(), criteria, func(t *taskDomain.Task) error {
+			return write([]string{
+				t.ID.String(), t.Title, t.Description, string(t.Status), taskDomain.AssigneeText(t.AssigneeID),
+				t.CreatedAt.UTC().Format(time.RFC3339Nano), t.UpdatedAt.UTC().Format(time.RFC3339Nano),
+			})
+		})
+	})
+}
 
-	c.JSON(http.StatusOK, tasks)
+// ImportTasks endpoint POST /tasks/import?format=ndjson|csv: crea una tarea por fila, con las
+// mismas validaciones y eventos que POST /tasks. Una fila con el asignado vacío crea una tarea
+// sin asignar. Responde con el número de altas y el error de cada fila que no se pudo importar.
+func (h *TaskHandler) ImportTasks(c *gin.Context) {
+	sharedHttp.Import(c, func(ctx context.Context, rec dataio.Record) error {
+		var assigneeID *uuid.UUID
+		if raw := rec.Get("assignee_id", "assigneeId"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return errInvalidAssigneeID
+			}
+			assigneeID = &id
+		}
+		_, err := h.createTask(ctx, rec.Get("title"), rec.Fields["description"], assigneeID)
+		return err
+	})
 }
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	"github.com/davicafu/hexagolab/internal/task/application"
+)
+
+var errInvalidUserID = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid user id")
+
+// TaskSummaryHandler sirve los contadores de tareas por usuario de la proyección user_task_counts.
+type TaskSummaryHandler struct {
+	service *application.TaskCounterService
+}
+
+// NewTaskSummaryHandler crea un nuevo TaskSummaryHandler.
+func NewTaskSummaryHandler(service *application.TaskCounterService) *TaskSummaryHandler {
+	return &TaskSummaryHandler{service: service}
+}
+
+// GetTaskSummary endpoint GET /users/:id/task-summary. Los contadores se actualizan al consumir
+// los eventos de tareas, así que pueden ir ligeramente por detrás de las escrituras.
+func (h *TaskSummaryHandler) GetTaskSummary(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		sharedHttp.WriteError(c, errInvalidUserID)
+		return
+	}
+
+	summary, err := h.service.TaskSummary(c.Request.Context(), userID)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
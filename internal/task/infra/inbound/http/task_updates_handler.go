@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	"github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// defaultUpdatesWait es lo que espera GET /tasks/updates si no se indica wait.
+const defaultUpdatesWait = 30 * time.Second
+
+var errInvalidWait = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid wait, use a non-negative duration such as 30s")
+
+// TaskUpdatesHandler sirve el long polling de las tareas de un usuario: una alternativa a las
+// suscripciones GraphQL por SSE para los clientes detrás de proxies que cortan o almacenan las
+// respuestas en streaming.
+type TaskUpdatesHandler struct {
+	service *application.TaskService
+	feed    *infraEvents.Feed
+	maxWait time.Duration
+}
+
+// NewTaskUpdatesHandler crea el handler. 'feed' entrega los eventos del bus y 'maxWait' limita el
+// parámetro wait.
+func NewTaskUpdatesHandler(service *application.TaskService, feed *infraEvents.Feed, maxWait time.Duration) *TaskUpdatesHandler {
+	return &TaskUpdatesHandler{service: service, feed: feed, maxWait: maxWait}
+}
+
+// TaskUpdates es la respuesta de GET /tasks/updates.
+type TaskUpdates struct {
+	Events []sharedEvents.IntegrationEvent `json:"events"`
+}
+
+// Updates endpoint GET /tasks/updates?assignee_id=<uuid>&wait=30s
+// Mantiene abierta la petición hasta que llega al bus un evento de una tarea asignada al usuario
+// (o hasta wait, como mucho http.long_poll.max_wait) y responde 200 con los eventos que han
+// llegado; si no llega ninguno, 204. Una tarea se relaciona con el usuario por el asignado del
+// evento o, si el evento no lo lleva, por el asignado actual de la tarea, así que los borrados no
+// se entregan. Los eventos publicados entre dos peticiones se pierden: el cliente que no puede
+// perder ninguno debe seguir GET /tasks/changes.
+func (h *TaskUpdatesHandler) Updates(c *gin.Context) {
+	assigneeID, err := uuid.Parse(c.Query("assignee_id"))
+	if err != nil {
+		sharedHttp.WriteError(c, errInvalidAssigneeID)
+		return
+	}
+	wait := defaultUpdatesWait
+	if raw := c.Query("wait"); raw != "" {
+		if wait, err = time.ParseDuration(raw); err != nil || wait < 0 {
+			sharedHttp.WriteError(c, errInvalidWait)
+			return
+		}
+	}
+	wait = min(wait, h.maxWait)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), wait)
+	defer cancel()
+	events := h.feed.Subscribe(ctx, taskDomain.TaskTopic)
+	updates := TaskUpdates{Events: []sharedEvents.IntegrationEvent{}}
+	for evt := range events {
+		if !h.matches(c.Request.Context(), evt, assigneeID) {
+			continue
+		}
+		updates.Events = append(updates.Events, evt)
+		// Se entregan también los que ya han llegado detrás de él.
+		updates.Events = append(updates.Events, h.pending(c.Request.Context(), events, assigneeID)...)
+		break
+	}
+
+	if len(updates.Events) == 0 {
+		if c.Request.Context().Err() == nil {
+			c.Status(http.StatusNoContent)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, updates)
+}
+
+// pending devuelve los eventos del usuario que esperan ya en 'events', sin bloquear.
+func (h *TaskUpdatesHandler) pending(ctx context.Context, events <-chan sharedEvents.IntegrationEvent, assigneeID uuid.UUID) []sharedEvents.IntegrationEvent {
+	var matched []sharedEvents.IntegrationEvent
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				return matched
+			}
+			if h.matches(ctx, evt, assigneeID) {
+				matched = append(matched, evt)
+			}
+		default:
+			return matched
+		}
+	}
+}
+
+// matches indica si 'evt' es de una tarea asignada a 'assigneeID'.
+func (h *TaskUpdatesHandler) matches(ctx context.Context, evt sharedEvents.IntegrationEvent, assigneeID uuid.UUID) bool {
+	var data struct {
+		ID         uuid.UUID  `json:"id"`
+		AssigneeID *uuid.UUID `json:"assigneeId"`
+	}
+	if err := json.Unmarshal(evt.Data, &data); err != nil || data.ID == uuid.Nil {
+		return false
+	}
+	if data.AssigneeID != nil {
+		return *data.AssigneeID == assigneeID
+	}
+	task, err := h.service.GetTaskByID(ctx, data.ID)
+	return err == nil && task.AssigneeID != nil && *task.AssigneeID == assigneeID
+}
@@ -4,39 +4,78 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/google/uuid"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 )
 
 // TaskAnalyticsRepo implementa la interfaz TaskAnalyticsRepository para ClickHouse.
 type TaskAnalyticsRepo struct {
-	db *sql.DB
+	db              *sql.DB
+	retentionMonths int
 }
 
+// Options agrupa la configuración del adaptador de ClickHouse.
+type Options struct {
+	Addr     string
+	Database string
+	// RetentionMonths es el número de meses de histórico que se conservan en tasks_log.
+	// 0 desactiva el TTL (la tabla crece sin límite).
+	RetentionMonths int
+	// MaxExecutionTime limita la duración de cada consulta (por defecto, 60s).
+	MaxExecutionTime time.Duration
+	// DialTimeout es el tiempo máximo para abrir una conexión (por defecto, el del driver).
+	DialTimeout time.Duration
+	// Compression es el método de compresión del protocolo nativo: "lz4", "zstd", "none"...
+	Compression string
+	// AsyncInsert activa async_insert: el servidor agrupa las inserciones pequeñas en un buffer
+	// en vez de crear una parte por cada INSERT.
+	AsyncInsert bool
+	// WaitForAsyncInsert hace que el INSERT espere a que el buffer se vuelque a disco.
+	// Sin él, un fallo del servidor puede perder inserciones ya confirmadas.
+	WaitForAsyncInsert bool
+}
+
+const defaultMaxExecutionTime = 60 * time.Second
+
 // NewTaskAnalyticsRepo es el constructor.
-func NewTaskAnalyticsRepo(addr string, dbName string) (*TaskAnalyticsRepo, error) {
+func NewTaskAnalyticsRepo(opts Options) (*TaskAnalyticsRepo, error) {
+	compression, err := compressionFor(opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+
 	conn := clickhouse.OpenDB(&clickhouse.Options{
-		Addr: []string{addr},
+		Addr: []string{opts.Addr},
 		Auth: clickhouse.Auth{
 			// ... tus credenciales si son necesarias
-			Database: dbName,
-		},
-		Settings: clickhouse.Settings{
-			"max_execution_time": 60,
+			Database: opts.Database,
 		},
+		Settings:    settingsFor(opts),
+		DialTimeout: opts.DialTimeout,
+		Compression: compression,
 	})
 
 	if err := conn.Ping(); err != nil {
 		return nil, fmt.Errorf("could not ping clickhouse: %w", err)
 	}
 
-	return &TaskAnalyticsRepo{db: conn}, nil
+	return &TaskAnalyticsRepo{db: conn, retentionMonths: opts.RetentionMonths}, nil
+}
+
+// Ping comprueba que ClickHouse responde.
+func (r *TaskAnalyticsRepo) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
 }
 
 // LogBatch inserta un lote de tareas en ClickHouse. Esta es la forma más eficiente.
+// Las filas se guardan con el inquilino de ctx.
 func (r *TaskAnalyticsRepo) LogBatch(ctx context.Context, tasks []*taskDomain.Task) error {
 	// ClickHouse funciona mejor con inserciones en lotes.
 	tx, err := r.db.Begin()
@@ -45,20 +84,22 @@ func (r *TaskAnalyticsRepo) LogBatch(ctx context.Context, tasks []*taskDomain.Ta
 	}
 
 	// Preparamos la sentencia de inserción.
-	stmt, err := tx.PrepareContext(ctx, "INSERT INTO tasks_log (id, title, description, assignee_id, status, created_at, updated_at, event_time)")
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO tasks_log (id, tenant_id, title, description, assignee_id, status, created_at, updated_at, event_time)")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
 	eventTime := time.Now()
+	tenant := tracing.TenantID(ctx)
 	for _, task := range tasks {
 		if _, err := stmt.ExecContext(
 			ctx,
 			task.ID,
+			tenant,
 			task.Title,
 			task.Description,
-			task.AssigneeID,
+			assigneeColumn(task.AssigneeID),
 			string(task.Status),
 			task.CreatedAt,
 			task.UpdatedAt,
@@ -82,11 +123,11 @@ func (r *TaskAnalyticsRepo) GetDailyTrend(ctx context.Context, start, end time.T
 			countIf(event_type = 'task.created') AS created,
 			countIf(status = 'completed' AND event_type = 'task.updated') AS completed
 		FROM tasks_log
-		WHERE event_time BETWEEN ? AND ?
+		WHERE tenant_id = ? AND event_time BETWEEN ? AND ?
 		GROUP BY day
 		ORDER BY day
 	`
-	rows, err := r.db.QueryContext(ctx, query, start, end)
+	rows, err := r.db.QueryContext(ctx, query, tracing.TenantID(ctx), start, end)
 	if err != nil {
 		return nil, err
 	}
@@ -115,15 +156,16 @@ func (r *TaskAnalyticsRepo) GetAverageCompletionTime(ctx context.Context, start,
 				minIf(updated_at, event_type = 'task.created') AS creation_time,
 				maxIf(updated_at, status = 'completed') AS completion_time
 			FROM tasks_log
-			WHERE id IN (
-				SELECT DISTINCT id FROM tasks_log WHERE status = 'completed' AND event_time BETWEEN ? AND ?
+			WHERE tenant_id = ? AND id IN (
+				SELECT DISTINCT id FROM tasks_log WHERE tenant_id = ? AND status = 'completed' AND event_time BETWEEN ? AND ?
 			)
 			GROUP BY id
 		)
 		WHERE creation_time > 0 AND completion_time > 0
 	`
+	tenant := tracing.TenantID(ctx)
 	var avgSeconds sql.NullFloat64
-	err := r.db.QueryRowContext(ctx, query, start, end).Scan(&avgSeconds)
+	err := r.db.QueryRowContext(ctx, query, tenant, tenant, start, end).Scan(&avgSeconds)
 	if err != nil {
 		return 0, err
 	}
@@ -134,13 +176,92 @@ func (r *TaskAnalyticsRepo) GetAverageCompletionTime(ctx context.Context, start,
 	return time.Duration(avgSeconds.Float64) * time.Second, nil
 }
 
-// InitSchema crea la tabla en ClickHouse si no existe.
+// GetAssigneeStats calcula los contadores de un usuario a partir del histórico del rango.
+// Cada tarea se cuenta una sola vez por estado aunque tenga varias filas en tasks_log.
+func (r *TaskAnalyticsRepo) GetAssigneeStats(ctx context.Context, assigneeID uuid.UUID, start, end time.Time) (taskDomain.AssigneeStats, error) {
+	query := `
+		SELECT
+			uniqIf(id, created_at BETWEEN ? AND ?) AS created,
+			uniqIf(id, status = 'completed') AS completed,
+			uniqIf(id, status = 'failed') AS failed,
+			avgIf(dateDiff('second', created_at, updated_at), status = 'completed') AS avg_completion_seconds
+		FROM tasks_log
+		WHERE tenant_id = ? AND assignee_id = ? AND event_time BETWEEN ? AND ?
+	`
+	stats := taskDomain.AssigneeStats{AssigneeID: assigneeID}
+	var created, completed, failed uint64
+	var avgSeconds float64
+	err := r.db.QueryRowContext(ctx, query, start, end, tracing.TenantID(ctx), assigneeID, start, end).
+		Scan(&created, &completed, &failed, &avgSeconds)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.CreatedCount = int(created)
+	stats.CompletedCount = int(completed)
+	stats.FailedCount = int(failed)
+	// avgIf devuelve NaN cuando no hay filas que cumplan la condición.
+	if !math.IsNaN(avgSeconds) {
+		stats.AvgCompletionTime = time.Duration(avgSeconds * float64(time.Second))
+	}
+	return stats, nil
+}
+
+// StreamTaskLog recorre las filas de tasks_log del inquilino de ctx en el rango dado, una a una.
+// ClickHouse devuelve los resultados en bloques, así que nunca se materializa el rango completo.
+func (r *TaskAnalyticsRepo) StreamTaskLog(ctx context.Context, start, end time.Time, fn func(taskDomain.TaskLogEntry) error) error {
+	query := `
+		SELECT id, title, description, assignee_id, status, created_at, updated_at, event_time
+		FROM tasks_log
+		WHERE tenant_id = ? AND event_time BETWEEN ? AND ?
+		ORDER BY event_time
+	`
+	rows, err := r.db.QueryContext(ctx, query, tracing.TenantID(ctx), start, end)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry taskDomain.TaskLogEntry
+		var status string
+		var assigneeID uuid.UUID
+		if err := rows.Scan(
+			&entry.ID, &entry.Title, &entry.Description, &assigneeID,
+			&status, &entry.CreatedAt, &entry.UpdatedAt, &entry.EventTime,
+		); err != nil {
+			return err
+		}
+		entry.Status = taskDomain.TaskStatus(status)
+		if assigneeID != uuid.Nil {
+			entry.AssigneeID = &assigneeID
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// assigneeColumn es el valor de assignee_id en tasks_log. La columna forma parte de la clave de
+// ordenación, que no admite Nullable: las tareas sin asignar se guardan con el UUID nulo.
+func assigneeColumn(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
+
+// InitSchema crea la tabla en ClickHouse si no existe y aplica la política de retención.
 func (r *TaskAnalyticsRepo) InitSchema() error {
 	// Esta tabla está optimizada para analítica.
-	// Se particiona por mes y se ordena por campos comunes de consulta.
+	// Se particiona por mes y se ordena por campos comunes de consulta, empezando por el inquilino,
+	// que filtra todas las lecturas.
 	query := `
 		CREATE TABLE IF NOT EXISTS tasks_log (
 			id          UUID,
+			tenant_id   String,
 			title       String,
 			description String,
 			assignee_id UUID,
@@ -150,10 +271,125 @@ func (r *TaskAnalyticsRepo) InitSchema() error {
 			event_time  DateTime64(3)
 		) ENGINE = MergeTree()
 		PARTITION BY toYYYYMM(event_time)
-		ORDER BY (assignee_id, status, event_time);
-	`
-	_, err := r.db.Exec(query)
-	return err
+		ORDER BY (tenant_id, assignee_id, status, event_time)
+	` + ttlClause(r.retentionMonths)
+	if _, err := r.db.Exec(query); err != nil {
+		return err
+	}
+
+	// Las tablas creadas antes de la multi-tenencia no tienen tenant_id: sus filas pasan al
+	// inquilino por defecto. La clave de ordenación de una tabla existente no se puede cambiar.
+	if _, err := r.db.Exec(`ALTER TABLE tasks_log ADD COLUMN IF NOT EXISTS tenant_id String DEFAULT '' AFTER id`); err != nil {
+		return fmt.Errorf("failed to add tasks_log tenant_id: %w", err)
+	}
+
+	// CREATE TABLE IF NOT EXISTS no toca tablas ya existentes, así que el TTL se (re)aplica aparte.
+	// ttl_only_drop_parts hace que ClickHouse borre particiones enteras en vez de reescribirlas.
+	if r.retentionMonths > 0 {
+		if _, err := r.db.Exec(`ALTER TABLE tasks_log MODIFY` + ttlClause(r.retentionMonths)); err != nil {
+			return fmt.Errorf("failed to apply tasks_log TTL: %w", err)
+		}
+		if _, err := r.db.Exec(`ALTER TABLE tasks_log MODIFY SETTING ttl_only_drop_parts = 1`); err != nil {
+			return fmt.Errorf("failed to apply tasks_log TTL settings: %w", err)
+		}
+	}
+	return nil
+}
+
+// DropPartitionsBefore elimina inmediatamente las particiones mensuales anteriores al mes de 'cutoff',
+// sin esperar al merge en segundo plano que aplica el TTL. Devuelve las particiones eliminadas.
+func (r *TaskAnalyticsRepo) DropPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT partition_id
+		FROM system.parts
+		WHERE database = currentDatabase() AND table = 'tasks_log' AND active AND partition_id < ?
+		ORDER BY partition_id
+	`, partitionID(cutoff))
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		partitions = append(partitions, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, p := range partitions {
+		if _, err := r.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE tasks_log DROP PARTITION ID '%s'", p)); err != nil {
+			return dropped, fmt.Errorf("failed to drop partition %s: %w", p, err)
+		}
+		dropped = append(dropped, p)
+	}
+	return dropped, nil
+}
+
+// EnforceRetention elimina las particiones que quedan fuera de la ventana de retención configurada.
+func (r *TaskAnalyticsRepo) EnforceRetention(ctx context.Context) ([]string, error) {
+	if r.retentionMonths <= 0 {
+		return nil, nil
+	}
+	return r.DropPartitionsBefore(ctx, time.Now().UTC().AddDate(0, -r.retentionMonths, 0))
+}
+
+// settingsFor traduce las opciones a settings de sesión de ClickHouse.
+func settingsFor(opts Options) clickhouse.Settings {
+	maxExecution := opts.MaxExecutionTime
+	if maxExecution <= 0 {
+		maxExecution = defaultMaxExecutionTime
+	}
+
+	settings := clickhouse.Settings{
+		"max_execution_time": int(maxExecution.Seconds()),
+	}
+	if opts.AsyncInsert {
+		settings["async_insert"] = 1
+		settings["wait_for_async_insert"] = 0
+		if opts.WaitForAsyncInsert {
+			settings["wait_for_async_insert"] = 1
+		}
+	}
+	return settings
+}
+
+// compressionFor devuelve la compresión del driver para el nombre dado; nil si está vacío.
+func compressionFor(name string) (*clickhouse.Compression, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return nil, nil
+	case "none":
+		return &clickhouse.Compression{Method: clickhouse.CompressionNone}, nil
+	case "lz4":
+		return &clickhouse.Compression{Method: clickhouse.CompressionLZ4}, nil
+	case "lz4hc":
+		return &clickhouse.Compression{Method: clickhouse.CompressionLZ4HC}, nil
+	case "zstd":
+		return &clickhouse.Compression{Method: clickhouse.CompressionZSTD}, nil
+	default:
+		return nil, fmt.Errorf("unsupported clickhouse compression: %q", name)
+	}
+}
+
+// ttlClause genera la cláusula TTL de tasks_log; vacía si la retención está desactivada.
+func ttlClause(months int) string {
+	if months <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" TTL toDateTime(event_time) + INTERVAL %d MONTH DELETE", months)
+}
+
+// partitionID devuelve el identificador de la partición mensual (toYYYYMM) que contiene 't'.
+func partitionID(t time.Time) string {
+	return t.UTC().Format("200601")
 }
 
 // Verificación estática de la interfaz.
@@ -0,0 +1,136 @@
+package clickhouse
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLClause(t *testing.T) {
+	assert.Equal(t, "", ttlClause(0), "Sin retención no debería generarse TTL")
+	assert.Equal(t, " TTL toDateTime(event_time) + INTERVAL 13 MONTH DELETE", ttlClause(13))
+}
+
+func TestPartitionID(t *testing.T) {
+	// La partición se calcula en UTC, igual que toYYYYMM sobre event_time.
+	madrid := time.FixedZone("CET", 3600)
+	assert.Equal(t, "202412", partitionID(time.Date(2025, 1, 1, 0, 30, 0, 0, madrid)))
+	assert.Equal(t, "202503", partitionID(time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestSettingsFor(t *testing.T) {
+	// Sin opciones se mantiene el comportamiento anterior: solo max_execution_time a 60s.
+	assert.Equal(t, clickhouse.Settings{"max_execution_time": 60}, settingsFor(Options{}))
+
+	settings := settingsFor(Options{MaxExecutionTime: 2 * time.Minute, AsyncInsert: true, WaitForAsyncInsert: true})
+	assert.Equal(t, 120, settings["max_execution_time"])
+	assert.Equal(t, 1, settings["async_insert"])
+	assert.Equal(t, 1, settings["wait_for_async_insert"])
+}
+
+func TestCompressionFor(t *testing.T) {
+	c, err := compressionFor("")
+	require.NoError(t, err)
+	assert.Nil(t, c, "Sin nombre se usa el valor por defecto del driver")
+
+	c, err = compressionFor("LZ4")
+	require.NoError(t, err)
+	assert.Equal(t, clickhouse.CompressionLZ4, c.Method)
+
+	_, err = compressionFor("snappy")
+	assert.Error(t, err)
+}
+
+// TestTaskAnalyticsRepo_TenantIsolation comprueba que cada inquilino solo lee sus filas de tasks_log.
+// Necesita un ClickHouse accesible en CLICKHOUSE_ADDR; si no, se omite.
+func TestTaskAnalyticsRepo_TenantIsolation(t *testing.T) {
+	addr := os.Getenv("CLICKHOUSE_ADDR")
+	if addr == "" {
+		t.Skip("CLICKHOUSE_ADDR not set, skipping ClickHouse integration test")
+	}
+
+	// Arrange
+	repo, err := NewTaskAnalyticsRepo(Options{Addr: addr, Database: "default"})
+	require.NoError(t, err)
+	require.NoError(t, repo.InitSchema())
+
+	start := time.Now().UTC().Add(-time.Minute)
+	acme := tracing.NewContext(context.Background(), tracing.Context{TenantID: "acme-" + uuid.NewString()[:8]})
+	globex := tracing.NewContext(context.Background(), tracing.Context{TenantID: "globex-" + uuid.NewString()[:8]})
+	assigneeID := uuid.New()
+	newTask := func(title string) *taskDomain.Task {
+		now := time.Now().UTC()
+		return &taskDomain.Task{
+			ID: uuid.New(), Title: title, AssigneeID: &assigneeID,
+			Status: taskDomain.TaskCompleted, CreatedAt: now.Add(-time.Hour), UpdatedAt: now,
+		}
+	}
+	require.NoError(t, repo.LogBatch(acme, []*taskDomain.Task{newTask("De acme")}))
+	require.NoError(t, repo.LogBatch(globex, []*taskDomain.Task{newTask("De globex"), newTask("De globex")}))
+	end := time.Now().UTC().Add(time.Minute)
+
+	// Act
+	var titles []string
+	err = repo.StreamTaskLog(acme, start, end, func(e taskDomain.TaskLogEntry) error {
+		titles = append(titles, e.Title)
+		return nil
+	})
+	require.NoError(t, err)
+	acmeStats, err := repo.GetAssigneeStats(acme, assigneeID, start, end)
+	require.NoError(t, err)
+	globexStats, err := repo.GetAssigneeStats(globex, assigneeID, start, end)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, []string{"De acme"}, titles)
+	assert.Equal(t, 1, acmeStats.CompletedCount)
+	assert.Equal(t, 2, globexStats.CompletedCount)
+}
+
+// BenchmarkLogBatch compara el throughput de inserción síncrona frente a async_insert.
+// Necesita un ClickHouse accesible en CLICKHOUSE_ADDR; si no, se omite.
+func BenchmarkLogBatch(b *testing.B) {
+	addr := os.Getenv("CLICKHOUSE_ADDR")
+	if addr == "" {
+		b.Skip("CLICKHOUSE_ADDR not set, skipping ClickHouse benchmark")
+	}
+
+	for _, async := range []bool{false, true} {
+		name := "sync"
+		if async {
+			name = "async"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			repo, err := NewTaskAnalyticsRepo(Options{Addr: addr, Database: "default", Compression: "lz4", AsyncInsert: async})
+			require.NoError(b, err)
+			require.NoError(b, repo.InitSchema())
+
+			// Lotes pequeños: es el caso en el que async_insert debería notarse.
+			batch := make([]*taskDomain.Task, 10)
+			for i := range batch {
+				now, assigneeID := time.Now().UTC(), uuid.New()
+				batch[i] = &taskDomain.Task{
+					ID: uuid.New(), Title: "Benchmark", AssigneeID: &assigneeID,
+					Status: taskDomain.TaskPending, CreatedAt: now, UpdatedAt: now,
+				}
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := repo.LogBatch(context.Background(), batch); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.N*len(batch))/b.Elapsed().Seconds(), "rows/s")
+		})
+	}
+}
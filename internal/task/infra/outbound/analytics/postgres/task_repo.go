@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/google/uuid"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // Driver de PostgreSQL
+)
+
+// TaskAnalyticsRepoPostgres implementa TaskAnalyticsRepository sobre las tablas transaccionales
+// (tasks y outbox) de Postgres. Es el fallback para despliegues sin ClickHouse:
+// no necesita ingesta propia porque el histórico ya está en la outbox. Como los repositorios
+// transaccionales, solo lee las filas del inquilino de ctx.
+type TaskAnalyticsRepoPostgres struct {
+	db *sql.DB
+}
+
+// NewTaskAnalyticsRepoPostgres es el constructor.
+func NewTaskAnalyticsRepoPostgres(db *sql.DB) *TaskAnalyticsRepoPostgres {
+	return &TaskAnalyticsRepoPostgres{db: db}
+}
+
+// LogBatch no hace nada: cada cambio de una tarea ya deja su evento en la outbox,
+// dentro de la misma transacción, y de ahí se leen los datos analíticos.
+func (r *TaskAnalyticsRepoPostgres) LogBatch(ctx context.Context, tasks []*taskDomain.Task) error {
+	return nil
+}
+
+// GetDailyTrend cuenta, por día, las tareas creadas según los eventos task.created de la outbox y
+// las completadas según la tabla tasks, por la fecha de su última actualización (como
+// GetAssigneeStats). El estado no se lee de los payloads de la outbox, que pueden estar
+// comprimidos.
+func (r *TaskAnalyticsRepoPostgres) GetDailyTrend(ctx context.Context, start, end time.Time) ([]taskDomain.DailyTaskTrend, error) {
+	query := `
+		SELECT day, SUM(created), SUM(completed)
+		FROM (
+			SELECT date_trunc('day', created_at) AS day, 1 AS created, 0 AS completed
+			FROM outbox
+			WHERE aggregate_type = 'task' AND event_type = $3 AND created_at BETWEEN $1 AND $2 AND tenant_id = $5
+			UNION ALL
+			SELECT date_trunc('day', updated_at), 0, 1
+			FROM tasks
+			WHERE status = $4 AND updated_at BETWEEN $1 AND $2 AND tenant_id = $5
+		) AS events
+		GROUP BY day
+		ORDER BY day
+	`
+	rows, err := r.db.QueryContext(ctx, query, start, end,
+		taskDomain.TaskCreated, string(taskDomain.TaskCompleted), tracing.TenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trends []taskDomain.DailyTaskTrend
+	for rows.Next() {
+		var trend taskDomain.DailyTaskTrend
+		if err := rows.Scan(&trend.Day, &trend.CreatedCount, &trend.CompletedCount); err != nil {
+			return nil, err
+		}
+		trends = append(trends, trend)
+	}
+	return trends, rows.Err()
+}
+
+// GetAverageCompletionTime calcula la media de (updated_at - created_at) de las tareas completadas en el rango.
+func (r *TaskAnalyticsRepoPostgres) GetAverageCompletionTime(ctx context.Context, start, end time.Time) (time.Duration, error) {
+	query := `
+		SELECT EXTRACT(EPOCH FROM AVG(updated_at - created_at))
+		FROM tasks
+		WHERE status = $1 AND updated_at BETWEEN $2 AND $3 AND tenant_id = $4
+	`
+	var avgSeconds sql.NullFloat64
+	if err := r.db.QueryRowContext(ctx, query, string(taskDomain.TaskCompleted), start, end, tracing.TenantID(ctx)).Scan(&avgSeconds); err != nil {
+		return 0, err
+	}
+	if !avgSeconds.Valid {
+		return 0, nil // No hay datos para calcular
+	}
+
+	return time.Duration(avgSeconds.Float64 * float64(time.Second)), nil
+}
+
+// GetAssigneeStats calcula los contadores de un usuario sobre la tabla tasks.
+// Las completadas/fallidas se cuentan por la fecha de su última actualización.
+func (r *TaskAnalyticsRepoPostgres) GetAssigneeStats(ctx context.Context, assigneeID uuid.UUID, start, end time.Time) (taskDomain.AssigneeStats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE created_at BETWEEN $2 AND $3),
+			COUNT(*) FILTER (WHERE status = $4 AND updated_at BETWEEN $2 AND $3),
+			COUNT(*) FILTER (WHERE status = $5 AND updated_at BETWEEN $2 AND $3),
+			EXTRACT(EPOCH FROM AVG(updated_at - created_at) FILTER (WHERE status = $4 AND updated_at BETWEEN $2 AND $3))
+		FROM tasks
+		WHERE assignee_id = $1 AND tenant_id = $6
+	`
+	stats := taskDomain.AssigneeStats{AssigneeID: assigneeID}
+	var avgSeconds sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, query, assigneeID, start, end,
+		string(taskDomain.TaskCompleted), string(taskDomain.TaskFailed), tracing.TenantID(ctx)).
+		Scan(&stats.CreatedCount, &stats.CompletedCount, &stats.FailedCount, &avgSeconds)
+	if err != nil {
+		return stats, err
+	}
+
+	if avgSeconds.Valid {
+		stats.AvgCompletionTime = time.Duration(avgSeconds.Float64 * float64(time.Second))
+	}
+	return stats, nil
+}
+
+// StreamTaskLog reconstruye el histórico a partir de los eventos de creación/actualización de la
+// outbox. Los payloads parciales (ver sharedEvents.PayloadMode) se completan: un delta, con el
+// estado de la tarea tras su evento anterior del rango o, en el primero, con su fila actual en
+// tasks; uno con solo el id, que no dice qué cambió, con la fila actual.
+func (r *TaskAnalyticsRepoPostgres) StreamTaskLog(ctx context.Context, start, end time.Time, fn func(taskDomain.TaskLogEntry) error) error {
+	query := `
+		SELECT o.aggregate_id::text, o.payload, o.metadata, o.created_at,
+			CASE WHEN t.id IS NOT NULL THEN json_build_object(
+				'ID', t.id, 'Title', t.title, 'Description', COALESCE(t.description, ''), 'AssigneeID', t.assignee_id,
+				'Status', t.status, 'CreatedAt', t.created_at, 'UpdatedAt', t.updated_at, 'Version', t.version)
+			END
+		FROM outbox o
+		LEFT JOIN tasks t ON t.id::text = o.aggregate_id::text AND t.tenant_id = o.tenant_id
+		WHERE o.aggregate_type = 'task' AND o.event_type IN ($3, $4) AND o.created_at BETWEEN $1 AND $2 AND o.tenant_id = $5
+		ORDER BY o.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, start, end, taskDomain.TaskCreated, taskDomain.TaskUpdated, tracing.TenantID(ctx))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	log := taskLog{}
+	for rows.Next() {
+		var id string
+		var payloadBytes, metadataBytes, current []byte
+		var eventTime time.Time
+		if err := rows.Scan(&id, &payloadBytes, &metadataBytes, &eventTime, &current); err != nil {
+			return err
+		}
+
+		payload, mode, err := outboxPayload(payloadBytes, metadataBytes)
+		if err != nil {
+			return err
+		}
+		t, err := log.apply(id, payload, mode, current)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(taskDomain.TaskLogEntry{
+			ID:          t.ID,
+			Title:       t.Title,
+			Description: t.Description,
+			AssigneeID:  t.AssigneeID,
+			Status:      t.Status,
+			CreatedAt:   t.CreatedAt,
+			UpdatedAt:   t.UpdatedAt,
+			EventTime:   eventTime,
+		}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// taskLog es el estado de cada tarea, por id, tras su último evento leído.
+type taskLog map[string]taskDomain.Task
+
+// apply devuelve el estado de la tarea 'id' tras un evento con 'payload'. Un payload completo es
+// el estado. Un delta se aplica sobre el estado tras el evento anterior o, si es el primero,
+// sobre 'current' (la fila actual de la tarea, nil si ya no existe); uno con solo el id, sobre
+// 'current' si la tarea existe.
+func (l taskLog) apply(id string, payload []byte, mode sharedEvents.PayloadMode, current []byte) (taskDomain.Task, error) {
+	var t taskDomain.Task
+	if mode.Partial() {
+		prev, ok := l[id]
+		if (!ok || mode == sharedEvents.PayloadID) && current != nil {
+			prev = taskDomain.Task{}
+			if err := json.Unmarshal(current, &prev); err != nil {
+				return t, fmt.Errorf("invalid task row: %w", err)
+			}
+		}
+		t = prev
+	}
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return t, fmt.Errorf("invalid task payload in outbox: %w", err)
+	}
+	l[id] = t
+	return t, nil
+}
+
+// outboxPayload devuelve el JSON del payload de una fila de la outbox, descomprimido si se guardó
+// comprimido (ver compression.CompressOutbox), y su forma.
+func outboxPayload(payload, metadata []byte) ([]byte, sharedEvents.PayloadMode, error) {
+	evt := sharedDomain.OutboxEvent{}
+	if err := json.Unmarshal(metadata, &evt.Metadata); err != nil {
+		return nil, "", fmt.Errorf("invalid task metadata in outbox: %w", err)
+	}
+	mode := sharedEvents.PayloadMode(evt.Metadata[sharedDomain.MetadataPayloadMode])
+	if evt.Metadata[compression.Header] == "" {
+		return payload, mode, nil
+	}
+	if err := json.Unmarshal(payload, &evt.Payload); err != nil {
+		return nil, "", fmt.Errorf("invalid task payload in outbox: %w", err)
+	}
+	payload, err := compression.OutboxPayload(evt)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid task payload in outbox: %w", err)
+	}
+	return payload, mode, nil
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.TaskAnalyticsRepository = (*TaskAnalyticsRepoPostgres)(nil)
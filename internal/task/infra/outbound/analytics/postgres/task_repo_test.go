@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+func TestOutboxPayload(t *testing.T) {
+	task := taskDomain.Task{ID: uuid.New(), Title: "Revisar el backlog", Description: strings.Repeat("Mucho detalle. ", 50), Status: taskDomain.TaskCompleted}
+	for _, encoding := range []string{compression.None, compression.Gzip, compression.Snappy} {
+		t.Run(encoding, func(t *testing.T) {
+			// Arrange: la fila como la guarda el repositorio, comprimida o no.
+			evt, err := compression.Options{Encoding: encoding, Threshold: 1}.CompressOutbox(context.Background(), sharedDomain.OutboxEvent{Payload: task})
+			require.NoError(t, err)
+			payloadBytes, err := json.Marshal(evt.Payload)
+			require.NoError(t, err)
+			metadataBytes, err := json.Marshal(evt.Metadata)
+			require.NoError(t, err)
+
+			// Act
+			payload, _, err := outboxPayload(payloadBytes, metadataBytes)
+
+			// Assert
+			require.NoError(t, err)
+			var decoded taskDomain.Task
+			require.NoError(t, json.Unmarshal(payload, &decoded))
+			assert.Equal(t, task.ID, decoded.ID)
+			assert.Equal(t, task.Description, decoded.Description)
+			assert.Equal(t, taskDomain.TaskCompleted, decoded.Status)
+		})
+	}
+}
+
+func TestTaskLog_Apply(t *testing.T) {
+	id := uuid.New()
+	assignee := uuid.New()
+	row := `{"ID":"` + id.String() + `","Title":"Título actual","Description":"desc","AssigneeID":"` + assignee.String() + `","Status":"completed","Version":3}`
+	tests := []struct {
+		name      string
+		seen      taskLog
+		payload   string
+		mode      sharedEvents.PayloadMode
+		current   string
+		wantTitle string
+		want      taskDomain.TaskStatus
+	}{
+		{name: "snapshot", payload: `{"ID":"` + id.String() + `","Title":"Nueva","Status":"pending"}`, mode: sharedEvents.PayloadSnapshot, current: row, wantTitle: "Nueva", want: taskDomain.TaskPending},
+		{name: "delta after an earlier event", seen: taskLog{id.String(): {ID: id, Title: "Nueva", Status: taskDomain.TaskPending}}, payload: `{"ID":"` + id.String() + `","Status":"completed"}`, mode: sharedEvents.PayloadDelta, current: row, wantTitle: "Nueva", want: taskDomain.TaskCompleted},
+		{name: "delta first in the range", payload: `{"ID":"` + id.String() + `","Title":"Renombrada"}`, mode: sharedEvents.PayloadDelta, current: row, wantTitle: "Renombrada", want: taskDomain.TaskCompleted},
+		{name: "id", seen: taskLog{id.String(): {ID: id, Title: "Nueva", Status: taskDomain.TaskPending}}, payload: `{"ID":"` + id.String() + `"}`, mode: sharedEvents.PayloadID, current: row, wantTitle: "Título actual", want: taskDomain.TaskCompleted},
+		{name: "id of a deleted task", payload: `{"ID":"` + id.String() + `"}`, mode: sharedEvents.PayloadID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			log := taskLog{}
+			for k, v := range tt.seen {
+				log[k] = v
+			}
+			var current []byte
+			if tt.current != "" {
+				current = []byte(tt.current)
+			}
+
+			// Act
+			task, err := log.apply(id.String(), []byte(tt.payload), tt.mode, current)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, id, task.ID)
+			assert.Equal(t, tt.wantTitle, task.Title)
+			assert.Equal(t, tt.want, task.Status)
+			assert.Equal(t, task, log[id.String()], "El estado queda para el evento siguiente")
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/mongodb"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/postgres"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlite"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskMemory "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/memory"
+	taskMongo "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/mongodb"
+	taskPostgres "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/postgre"
+	"github.com/davicafu/hexagolab/internal/task/infra/outbound/db/sqlcounters"
+	taskSQLite "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/sqlite"
+	taskFile "github.com/davicafu/hexagolab/internal/task/infra/outbound/filesystem"
+)
+
+// Stores agrupa el repositorio de tareas, el outbox que vive en su mismo almacenamiento y la
+// proyección de contadores de tareas por usuario. Con el backend file los contadores se guardan
+// en memoria.
+type Stores struct {
+	Tasks    taskDomain.TaskRepository
+	Outbox   sharedDomain.OutboxRepository
+	Counters taskDomain.TaskCounterRepository
+}
+
+// NewStores construye los adaptadores del dominio de tareas para el backend indicado
+// (sqlite, postgres, mongo, memory o file), inicializando su esquema cuando aplica.
+// Antes de devolverlos se comprueba que cada adaptador recibió el almacenamiento que espera
+// (ver preflight). El repositorio se devuelve instrumentado para exponer sus latencias en /metrics.
+func NewStores(ctx context.Context, backend string, conns *platformDB.Connections) (Stores, error) {
+	stores, err := newStores(ctx, backend, conns)
+	if err != nil {
+		return Stores{}, err
+	}
+	if err := preflight.Run(ctx, "task", stores.Tasks, stores.Outbox); err != nil {
+		return Stores{}, err
+	}
+	stores.Tasks = NewInstrumentedTaskRepository(stores.Tasks, backend)
+	return stores, nil
+}
+
+func newStores(ctx context.Context, backend string, conns *platformDB.Connections) (Stores, error) {
+	switch backend {
+	case platformDB.BackendSQLite:
+		db, err := conns.SQLite(ctx)
+		if err != nil {
+			return Stores{}, err
+		}
+		if err := taskSQLite.InitSQLiteTaskSchema(db); err != nil {
+			return Stores{}, fmt.Errorf("failed to initialize SQLite task schema: %w", err)
+		}
+		return Stores{Tasks: taskSQLite.NewTaskRepoSQLite(db), Outbox: sqlite.NewOutboxRepoSQLite(db), Counters: sqlcounters.New(db, sqlite.Engine)}, nil
+
+	case platformDB.BackendPostgres:
+		db, err := conns.Postgres(ctx)
+		if err != nil {
+			return Stores{}, err
+		}
+		if err := taskPostgres.InitPostgresTaskSchema(db); err != nil {
+			return Stores{}, fmt.Errorf("failed to initialize Postgres task schema: %w", err)
+		}
+		return Stores{Tasks: taskPostgres.NewTaskRepoPostgres(db), Outbox: postgres.NewOutboxRepoPostgres(db), Counters: sqlcounters.New(db, postgres.Engine)}, nil
+
+	case platformDB.BackendMongo:
+		client, dbName, err := conns.Mongo(ctx)
+		if err != nil {
+			return Stores{}, err
+		}
+		repo, err := taskMongo.NewTaskRepoMongoDB(ctx, client, dbName)
+		if err != nil {
+			return Stores{}, err
+		}
+		counters, err := taskMongo.NewTaskCountersMongoDB(ctx, client, dbName)
+		if err != nil {
+			return Stores{}, err
+		}
+		return Stores{Tasks: repo, Outbox: mongodb.NewOutboxRepoMongoDB(client, dbName), Counters: counters}, nil
+
+	case platformDB.BackendMemory:
+		outbox := sharedMemory.NewOutboxRepoMemory()
+		return Stores{Tasks: taskMemory.NewTaskRepoMemory(outbox), Outbox: outbox, Counters: taskMemory.NewTaskCountersMemory()}, nil
+
+	case platformDB.BackendFile:
+		path, keys := conns.File()
+		if path == "" {
+			return Stores{}, fmt.Errorf("task storage %q needs a file path", backend)
+		}
+		repo := taskFile.NewTaskRepoFile(path, keys)
+		return Stores{Tasks: repo, Outbox: repo.Outbox(), Counters: taskMemory.NewTaskCountersMemory()}, nil
+
+	default:
+		return Stores{}, fmt.Errorf("unsupported task storage backend: %q", backend)
+	}
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/querylog"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// instrumentedTaskRepository decora un TaskRepository midiendo la latencia de cada método.
+type instrumentedTaskRepository struct {
+	next    taskDomain.TaskRepository
+	backend string
+}
+
+// NewInstrumentedTaskRepository envuelve 'next' para exponer sus latencias en /metrics.
+func NewInstrumentedTaskRepository(next taskDomain.TaskRepository, backend string) taskDomain.TaskRepository {
+	return &instrumentedTaskRepository{next: next, backend: backend}
+}
+
+// tag etiqueta las consultas SQL del método para el log de consultas lentas.
+func (r *instrumentedTaskRepository) tag(ctx context.Context, method, criteria string) context.Context {
+	return querylog.WithTag(ctx, querylog.Tag{Repo: "task", Method: method, Criteria: criteria})
+}
+
+func (r *instrumentedTaskRepository) observe(method string, start time.Time, err error) {
+	metrics.ObserveDBQuery("task", r.backend, method, start, err)
+}
+
+func (r *instrumentedTaskRepository) Create(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
+	ctx = r.tag(ctx, "Create", "")
+	start := time.Now()
+	err := r.next.Create(ctx, t, evt)
+	r.observe("Create", start, err)
+	return err
+}
+
+func (r *instrumentedTaskRepository) Update(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
+	ctx = r.tag(ctx, "Update", "")
+	start := time.Now()
+	err := r.next.Update(ctx, t, evt)
+	r.observe("Update", start, err)
+	return err
+}
+
+func (r *instrumentedTaskRepository) UpdateStatus(ctx context.Context, tasks []*taskDomain.Task, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error) {
+	ctx = r.tag(ctx, "UpdateStatus", "")
+	start := time.Now()
+	updated, err := r.next.UpdateStatus(ctx, tasks, events)
+	r.observe("UpdateStatus", start, err)
+	return updated, err
+}
+
+func (r *instrumentedTaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error) {
+	ctx = r.tag(ctx, "GetByID", "")
+	start := time.Now()
+	t, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return t, err
+}
+
+func (r *instrumentedTaskRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*taskDomain.Task, error) {
+	ctx = r.tag(ctx, "GetByIDs", "")
+	start := time.Now()
+	tasks, err := r.next.GetByIDs(ctx, ids)
+	r.observe("GetByIDs", start, err)
+	return tasks, err
+}
+
+func (r *instrumentedTaskRepository) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*taskDomain.Task, error) {
+	ctx = r.tag(ctx, "ListByCriteria", querylog.SummarizeCriteria(criteria, sorts))
+	start := time.Now()
+	tasks, err := r.next.ListByCriteria(ctx, criteria, pagination, sorts)
+	r.observe("ListByCriteria", start, err)
+	return tasks, err
+}
+
+func (r *instrumentedTaskRepository) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	ctx = r.tag(ctx, "CountByCriteria", querylog.SummarizeCriteria(criteria, nil))
+	start := time.Now()
+	total, err := r.next.CountByCriteria(ctx, criteria)
+	r.observe("CountByCriteria", start, err)
+	return total, err
+}
+
+func (r *instrumentedTaskRepository) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	ctx = r.tag(ctx, "DeleteByID", "")
+	start := time.Now()
+	err := r.next.DeleteByID(ctx, id, evt)
+	r.observe("DeleteByID", start, err)
+	return err
+}
+
+// StreamByCriteria se mide entero: la duración incluye la de 'fn' con cada tarea.
+func (r *instrumentedTaskRepository) StreamByCriteria(ctx context.Context, criteria sharedDomain.Criteria, fn func(*taskDomain.Task) error) error {
+	ctx = r.tag(ctx, "StreamByCriteria", querylog.SummarizeCriteria(criteria, taskDomain.StreamSorts))
+	start := time.Now()
+	err := r.next.StreamByCriteria(ctx, criteria, fn)
+	r.observe("StreamByCriteria", start, err)
+	return err
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.TaskRepository = (*instrumentedTaskRepository)(nil)
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// TaskCountersMemory implementa TaskCounterRepository en memoria, para el modo demo y para los
+// almacenamientos sin tabla de contadores. Los contadores se pierden al parar el proceso.
+type TaskCountersMemory struct {
+	mu      sync.Mutex
+	tenants map[string]*tenantCounters
+}
+
+// tenantCounters es la proyección de un inquilino.
+type tenantCounters struct {
+	counts map[uuid.UUID]taskDomain.TaskCounts
+	last   map[uuid.UUID]taskDomain.TaskCounterChange // Último cambio aplicado a cada tarea
+}
+
+// NewTaskCountersMemory es el constructor.
+func NewTaskCountersMemory() *TaskCountersMemory {
+	return &TaskCountersMemory{tenants: make(map[string]*tenantCounters)}
+}
+
+func (r *TaskCountersMemory) Apply(ctx context.Context, change taskDomain.TaskCounterChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenant, ok := r.tenants[tracing.TenantID(ctx)]
+	if !ok {
+		tenant = &tenantCounters{counts: make(map[uuid.UUID]taskDomain.TaskCounts), last: make(map[uuid.UUID]taskDomain.TaskCounterChange)}
+		r.tenants[tracing.TenantID(ctx)] = tenant
+	}
+	var prev *taskDomain.TaskCounterChange
+	if last, ok := tenant.last[change.TaskID]; ok {
+		prev = &last
+	}
+	if !change.Supersedes(prev) {
+		return nil
+	}
+	for user, d := range change.Deltas(prev) {
+		counts := tenant.counts[user]
+		counts.Pending += d.Pending
+		counts.Completed += d.Completed
+		tenant.counts[user] = counts
+	}
+	tenant.last[change.TaskID] = change
+	return nil
+}
+
+func (r *TaskCountersMemory) Summary(ctx context.Context, userID uuid.UUID) (taskDomain.TaskSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := taskDomain.TaskSummary{UserID: userID}
+	if tenant, ok := r.tenants[tracing.TenantID(ctx)]; ok {
+		summary.TaskCounts = tenant.counts[userID]
+	}
+	return summary, nil
+}
+
+func (r *TaskCountersMemory) Reset(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tenants, tracing.TenantID(ctx))
+	return nil
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.TaskCounterRepository = (*TaskCountersMemory)(nil)
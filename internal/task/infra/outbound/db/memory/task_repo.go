@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// TaskRepoMemory implementa la interfaz TaskRepository en memoria, para el modo demo.
+// Los datos se pierden al parar el proceso. Como en SQL, cada operación solo ve las tareas del
+// inquilino de ctx.
+type TaskRepoMemory struct {
+	mu     sync.RWMutex
+	tasks  []taskRow // En orden de inserción, para que los empates al ordenar sean estables
+	outbox *sharedMemory.OutboxRepoMemory
+}
+
+// taskRow es una tarea guardada junto a su inquilino.
+type taskRow struct {
+	taskDomain.Task
+	tenant string
+}
+
+// NewTaskRepoMemory es el constructor del repositorio. Los eventos se guardan en 'outbox'.
+func NewTaskRepoMemory(outbox *sharedMemory.OutboxRepoMemory) *TaskRepoMemory {
+	return &TaskRepoMemory{outbox: outbox}
+}
+
+// Create guarda una tarea en el inquilino de ctx y su evento. Como en SQL, el ID es único entre
+// todos los inquilinos.
+func (r *TaskRepoMemory) Create(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.tasks, func(row taskRow) bool { return row.ID == t.ID }) {
+		return taskDomain.ErrTaskAlreadyExists
+	}
+	if err := r.outbox.Add(ctx, evt); err != nil {
+		return err
+	}
+	r.tasks = append(r.tasks, taskRow{Task: *t, tenant: tracing.TenantID(ctx)})
+	return nil
+}
+
+// Update actualiza una tarea, si la versión guardada es la anterior a la suya, y guarda su evento.
+func (r *TaskRepoMemory) Update(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, t.ID)
+	if i < 0 {
+		return taskDomain.ErrTaskNotFound
+	}
+	if r.tasks[i].Version != t.Version-1 {
+		return taskDomain.ErrTaskVersionConflict
+	}
+	if err := r.outbox.Add(ctx, evt); err != nil {
+		return err
+	}
+	// Como en SQL, la fecha de creación no se modifica.
+	updated := *t
+	updated.CreatedAt = r.tasks[i].CreatedAt
+	r.tasks[i].Task = updated
+	return nil
+}
+
+// UpdateStatus cambia el estado de las tareas que siguen en la versión anterior a la suya, con el
+// evento de cada una.
+func (r *TaskRepoMemory) UpdateStatus(ctx context.Context, tasks []*taskDomain.Task, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var updated []uuid.UUID
+	for _, t := range tasks {
+		i := r.indexOf(ctx, t.ID)
+		if i < 0 || r.tasks[i].Version != t.Version-1 {
+			continue
+		}
+		for _, evt := range events {
+			if evt.AggregateID == t.ID.String() {
+				if err := r.outbox.Add(ctx, evt); err != nil {
+					return updated, err
+				}
+			}
+		}
+		r.tasks[i].Status = t.Status
+		r.tasks[i].UpdatedAt = t.UpdatedAt
+		r.tasks[i].Version = t.Version
+		updated = append(updated, t.ID)
+	}
+	return updated, nil
+}
+
+// DeleteByID elimina una tarea y guarda su evento.
+func (r *TaskRepoMemory) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return taskDomain.ErrTaskNotFound
+	}
+	if err := r.outbox.Add(ctx, evt); err != nil {
+		return err
+	}
+	r.tasks = slices.Delete(r.tasks, i, i+1)
+	return nil
+}
+
+// GetByID recupera una tarea por su ID.
+func (r *TaskRepoMemory) GetByID(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return nil, taskDomain.ErrTaskNotFound
+	}
+	t := r.tasks[i].Task
+	return &t, nil
+}
+
+// GetByIDs recupera las tareas de 'ids' que existen.
+func (r *TaskRepoMemory) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*taskDomain.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tasks []*taskDomain.Task
+	for _, id := range ids {
+		if i := r.indexOf(ctx, id); i >= 0 {
+			t := r.tasks[i].Task
+			tasks = append(tasks, &t)
+		}
+	}
+	return tasks, nil
+}
+
+// ListByCriteria recupera una lista de tareas aplicando filtros, paginación y ordenamiento.
+func (r *TaskRepoMemory) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*taskDomain.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found, err := sharedMemory.Query(r.tasks, rowField, sharedQuery.TenantScope(ctx, criteria), pagination, sorts)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*taskDomain.Task, len(found))
+	for i := range found {
+		tasks[i] = &found[i].Task
+	}
+	return tasks, nil
+}
+
+// CountByCriteria cuenta las tareas que cumplen 'criteria'.
+func (r *TaskRepoMemory) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sharedMemory.Count(r.tasks, rowField, sharedQuery.TenantScope(ctx, criteria))
+}
+
+// StreamByCriteria recorre las tareas que cumplen 'criteria' página a página (ver taskDomain.StreamByPages).
+func (r *TaskRepoMemory) StreamByCriteria(ctx context.Context, criteria sharedDomain.Criteria, fn func(*taskDomain.Task) error) error {
+	return taskDomain.StreamByPages(ctx, r.ListByCriteria, criteria, fn)
+}
+
+// indexOf devuelve la posición de la tarea 'id' del inquilino de ctx, o -1.
+func (r *TaskRepoMemory) indexOf(ctx context.Context, id uuid.UUID) int {
+	tenant := tracing.TenantID(ctx)
+	return slices.IndexFunc(r.tasks, func(row taskRow) bool { return row.ID == id && row.tenant == tenant })
+}
+
+// rowField expone los campos de la tarea (TaskField) y la columna del inquilino.
+func rowField(row taskRow, field string) (any, bool) {
+	if field == sharedQuery.TenantColumn {
+		return row.tenant, true
+	}
+	return TaskField(row.Task, field)
+}
+
+// TaskField expone los campos de la tarea con los nombres de las columnas SQL, para evaluar
+// criterios en el proceso (ver sharedMemory.Query). Lo comparten los repositorios que no tienen
+// un motor de consultas, como el de fichero.
+func TaskField(t taskDomain.Task, field string) (any, bool) {
+	switch field {
+	case "id":
+		return t.ID, true
+	case "title":
+		return t.Title, true
+	case "description":
+		return t.Description, true
+	case "assignee_id":
+		return t.AssigneeID, true
+	case "status":
+		return t.Status, true
+	case "created_at":
+		return t.CreatedAt, true
+	case "updated_at":
+		return t.UpdatedAt, true
+	}
+	return nil, false
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.TaskRepository = (*TaskRepoMemory)(nil)
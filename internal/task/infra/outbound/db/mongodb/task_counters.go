@@ -0,0 +1,127 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// TaskCountersMongoDB implementa TaskCounterRepository sobre las colecciones user_task_counts
+// (los contadores de cada usuario) y task_counter_state (el último cambio aplicado a cada tarea).
+// Son posteriores a los inquilinos, así que todos sus documentos llevan tenantId.
+type TaskCountersMongoDB struct {
+	client     *mongo.Client
+	countsColl *mongo.Collection
+	stateColl  *mongo.Collection
+}
+
+// NewTaskCountersMongoDB es el constructor. Crea los índices únicos que impiden que dos
+// escrituras a la vez dupliquen el documento de un usuario o de una tarea.
+func NewTaskCountersMongoDB(ctx context.Context, client *mongo.Client, dbName string) (*TaskCountersMongoDB, error) {
+	db := client.Database(dbName)
+	r := &TaskCountersMongoDB{
+		client:     client,
+		countsColl: db.Collection("user_task_counts"),
+		stateColl:  db.Collection("task_counter_state"),
+	}
+	for coll, key := range map[*mongo.Collection]string{r.countsColl: "userId", r.stateColl: "taskId"} {
+		_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "tenantId", Value: 1}, {Key: key, Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s index: %w", coll.Name(), err)
+		}
+	}
+	return r, nil
+}
+
+type mongoTaskCounts struct {
+	Pending   int `bson:"pending"`
+	Completed int `bson:"completed"`
+}
+
+type mongoCounterState struct {
+	TenantID   string                `bson:"tenantId"`
+	TaskID     uuid.UUID             `bson:"taskId"`
+	AssigneeID *uuid.UUID            `bson:"assigneeId"`
+	Status     taskDomain.TaskStatus `bson:"status"`
+	Version    int64                 `bson:"version"`
+	Deleted    bool                  `bson:"deleted"`
+}
+
+func (r *TaskCountersMongoDB) Apply(ctx context.Context, change taskDomain.TaskCounterChange) error {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	tenant := tracing.TenantID(ctx)
+	stateFilter := bson.D{{Key: "tenantId", Value: tenant}, {Key: "taskId", Value: change.TaskID}}
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		var prev *taskDomain.TaskCounterChange
+		var doc mongoCounterState
+		err := r.stateColl.FindOne(sessCtx, stateFilter).Decode(&doc)
+		switch {
+		case err == nil:
+			prev = &taskDomain.TaskCounterChange{TaskID: doc.TaskID, AssigneeID: doc.AssigneeID, Status: doc.Status, Version: doc.Version, Deleted: doc.Deleted}
+		case !errors.Is(err, mongo.ErrNoDocuments):
+			return nil, fmt.Errorf("failed to read task counter state: %w", err)
+		}
+		if !change.Supersedes(prev) {
+			return nil, nil
+		}
+
+		for user, d := range change.Deltas(prev) {
+			_, err := r.countsColl.UpdateOne(sessCtx,
+				bson.D{{Key: "tenantId", Value: tenant}, {Key: "userId", Value: user}},
+				bson.M{"$inc": bson.M{"pending": d.Pending, "completed": d.Completed}},
+				options.Update().SetUpsert(true))
+			if err != nil {
+				return nil, fmt.Errorf("failed to update task counters: %w", err)
+			}
+		}
+		state := mongoCounterState{TenantID: tenant, TaskID: change.TaskID, AssigneeID: change.AssigneeID, Status: change.Status, Version: change.Version, Deleted: change.Deleted}
+		if _, err := r.stateColl.ReplaceOne(sessCtx, stateFilter, state, options.Replace().SetUpsert(true)); err != nil {
+			return nil, fmt.Errorf("failed to save task counter state: %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (r *TaskCountersMongoDB) Summary(ctx context.Context, userID uuid.UUID) (taskDomain.TaskSummary, error) {
+	summary := taskDomain.TaskSummary{UserID: userID}
+	var doc mongoTaskCounts
+	err := r.countsColl.FindOne(ctx, bson.D{{Key: "tenantId", Value: tracing.TenantID(ctx)}, {Key: "userId", Value: userID}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return summary, nil
+	}
+	if err != nil {
+		return taskDomain.TaskSummary{}, fmt.Errorf("failed to read task counters: %w", err)
+	}
+	summary.Pending, summary.Completed = doc.Pending, doc.Completed
+	return summary, nil
+}
+
+func (r *TaskCountersMongoDB) Reset(ctx context.Context) error {
+	tenant := bson.D{{Key: "tenantId", Value: tracing.TenantID(ctx)}}
+	for _, coll := range []*mongo.Collection{r.countsColl, r.stateColl} {
+		if _, err := coll.DeleteMany(ctx, tenant); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", coll.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.TaskCounterRepository = (*TaskCountersMongoDB)(nil)
@@ -5,12 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	// --- Importaciones del dominio y compartidas ---
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
 
 	"github.com/google/uuid"
@@ -50,10 +54,16 @@ type mongoTask struct {
 	ID          uuid.UUID             `bson:"_id"`
 	Title       string                `bson:"title"`
 	Description string                `bson:"description"`
-	AssigneeID  uuid.UUID             `bson:"assigneeId"`
+	AssigneeID  *uuid.UUID            `bson:"assigneeId"` // null si está sin asignar
 	Status      taskDomain.TaskStatus `bson:"status"`
 	CreatedAt   time.Time             `bson:"createdAt"`
 	UpdatedAt   time.Time             `bson:"updatedAt"`
+	// Version es la versión de la tarea. Los documentos anteriores a las versiones no la tienen y
+	// están en la primera (ver versionFilter).
+	Version int64 `bson:"version"`
+	// TenantID es el inquilino de la tarea. Los documentos anteriores a los inquilinos no lo
+	// tienen y pertenecen al inquilino por defecto (ver tenantFilter).
+	TenantID string `bson:"tenantId"`
 }
 
 type mongoOutboxEvent struct {
@@ -64,6 +74,9 @@ type mongoOutboxEvent struct {
 	Payload       interface{} `bson:"payload"`
 	CreatedAt     time.Time   `bson:"createdAt"`
 	Processed     bool        `bson:"processed"`
+	// Metadata lleva las cabeceras de traza de la petición de origen.
+	Metadata map[string]string `bson:"metadata,omitempty"`
+	TenantID string            `bson:"tenantId,omitempty"`
 }
 
 // --- CRUD Transaccional ---
@@ -78,17 +91,20 @@ func (r *TaskRepoMongoDB) Create(ctx context.Context, t *taskDomain.Task, evt sh
 	// La transacción asegura que ambas inserciones (tarea y evento) sean atómicas.
 	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
 		// 1. Insertar la tarea
-		mt := toMongoTask(t)
+		mt := toMongoTask(sessCtx, t)
 		if _, err := r.tasksColl.InsertOne(sessCtx, mt); err != nil {
 			return nil, err
 		}
 		// 2. Insertar el evento de outbox
-		mo := toMongoOutboxEvent(evt)
+		mo := toMongoOutboxEvent(sessCtx, evt)
 		if _, err := r.outboxColl.InsertOne(sessCtx, mo); err != nil {
 			return nil, err
 		}
 		return nil, nil
 	})
+	if platformDB.IsUniqueViolation(err) {
+		return taskDomain.ErrTaskAlreadyExists
+	}
 
 	return err
 }
@@ -101,19 +117,25 @@ func (r *TaskRepoMongoDB) Update(ctx context.Context, t *taskDomain.Task, evt sh
 	defer session.EndSession(ctx)
 
 	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
-		mt := toMongoTask(t)
-		filter := bson.M{"_id": mt.ID}
+		mt := toMongoTask(sessCtx, t)
+		filter := mongoAnd([]bson.D{{{Key: "_id", Value: mt.ID}}, tenantFilter(sessCtx)})
 		update := bson.M{"$set": mt}
 
-		res, err := r.tasksColl.UpdateOne(sessCtx, filter, update)
+		res, err := r.tasksColl.UpdateOne(sessCtx, mongoAnd([]bson.D{filter, versionFilter(t.Version - 1)}), update)
 		if err != nil {
 			return nil, err
 		}
 		if res.MatchedCount == 0 {
+			// La tarea existe con otra versión, o no existe.
+			if n, err := r.tasksColl.CountDocuments(sessCtx, filter); err != nil {
+				return nil, err
+			} else if n > 0 {
+				return nil, taskDomain.ErrTaskVersionConflict
+			}
 			return nil, taskDomain.ErrTaskNotFound
 		}
 
-		mo := toMongoOutboxEvent(evt)
+		mo := toMongoOutboxEvent(sessCtx, evt)
 		if _, err := r.outboxColl.InsertOne(sessCtx, mo); err != nil {
 			return nil, err
 		}
@@ -124,6 +146,71 @@ func (r *TaskRepoMongoDB) Update(ctx context.Context, t *taskDomain.Task, evt sh
 	return err
 }
 
+// UpdateStatus cambia el estado de las tareas en una transacción: busca las que siguen en la
+// versión anterior a la suya, las actualiza con un solo UpdateMany e inserta sus eventos.
+func (r *TaskRepoMongoDB) UpdateStatus(ctx context.Context, tasks []*taskDomain.Task, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error) {
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	session, err := r.client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var updated []uuid.UUID
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		updated = nil // WithTransaction puede repetir la función
+		current := make(bson.A, 0, len(tasks))
+		for _, t := range tasks {
+			current = append(current, mongoAnd([]bson.D{{{Key: "_id", Value: t.ID}}, versionFilter(t.Version - 1)}))
+		}
+		found, err := r.findTasks(sessCtx, mongoAnd([]bson.D{tenantFilter(sessCtx), {{Key: "$or", Value: current}}}),
+			options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, nil
+		}
+		for _, t := range found {
+			updated = append(updated, t.ID)
+		}
+
+		// Todas las tareas comparten estado y fecha; la versión se incrementa en cada documento.
+		set := bson.D{
+			{Key: "status", Value: tasks[0].Status},
+			{Key: "updatedAt", Value: tasks[0].UpdatedAt},
+			{Key: "version", Value: bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", int64(1)}}, int64(1)}}},
+		}
+		filter := mongoAnd([]bson.D{{{Key: "_id", Value: bson.M{"$in": updated}}}, tenantFilter(sessCtx)})
+		if _, err := r.tasksColl.UpdateMany(sessCtx, filter, bson.A{bson.M{"$set": set}}); err != nil {
+			return nil, err
+		}
+
+		written := make(map[string]bool, len(updated))
+		for _, id := range updated {
+			written[id.String()] = true
+		}
+		var docs []interface{}
+		for _, evt := range events {
+			if written[evt.AggregateID] {
+				docs = append(docs, toMongoOutboxEvent(sessCtx, evt))
+			}
+		}
+		if len(docs) > 0 {
+			if _, err := r.outboxColl.InsertMany(sessCtx, docs); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
 func (r *TaskRepoMongoDB) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
 	session, err := r.client.StartSession()
 	if err != nil {
@@ -132,7 +219,7 @@ func (r *TaskRepoMongoDB) DeleteByID(ctx context.Context, id uuid.UUID, evt shar
 	defer session.EndSession(ctx)
 
 	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
-		res, err := r.tasksColl.DeleteOne(sessCtx, bson.M{"_id": id})
+		res, err := r.tasksColl.DeleteOne(sessCtx, mongoAnd([]bson.D{{{Key: "_id", Value: id}}, tenantFilter(sessCtx)}))
 		if err != nil {
 			return nil, err
 		}
@@ -140,7 +227,7 @@ func (r *TaskRepoMongoDB) DeleteByID(ctx context.Context, id uuid.UUID, evt shar
 			return nil, taskDomain.ErrTaskNotFound
 		}
 
-		mo := toMongoOutboxEvent(evt)
+		mo := toMongoOutboxEvent(sessCtx, evt)
 		if _, err := r.outboxColl.InsertOne(sessCtx, mo); err != nil {
 			return nil, err
 		}
@@ -155,7 +242,7 @@ func (r *TaskRepoMongoDB) DeleteByID(ctx context.Context, id uuid.UUID, evt shar
 
 func (r *TaskRepoMongoDB) GetByID(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error) {
 	var mt mongoTask
-	err := r.tasksColl.FindOne(ctx, bson.M{"_id": id}).Decode(&mt)
+	err := r.tasksColl.FindOne(ctx, mongoAnd([]bson.D{{{Key: "_id", Value: id}}, tenantFilter(ctx)})).Decode(&mt)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, taskDomain.ErrTaskNotFound
@@ -165,26 +252,64 @@ func (r *TaskRepoMongoDB) GetByID(ctx context.Context, id uuid.UUID) (*taskDomai
 	return fromMongoTask(&mt), nil
 }
 
-func (r *TaskRepoMongoDB) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sort sharedQuery.Sort) ([]*taskDomain.Task, error) {
-	filter := criteriaToMongoFilter(criteria)
+func (r *TaskRepoMongoDB) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*taskDomain.Task, error) {
+	if err := taskFields.CheckSorts(sorts); err != nil {
+		return nil, err
+	}
+	filter, err := r.scopedFilter(ctx, criteria)
+	if err != nil {
+		return nil, err
+	}
 	opts := options.Find()
+	// Los campos de ordenación se desempatan por _id, como el ORDER BY de SQL.
+	order := sharedQuery.MarkNullable(sharedQuery.OrderBy(sorts), taskNullable)
 
 	// Paginación
-	if p, ok := pagination.(sharedQuery.OffsetPagination); ok {
+	switch p := pagination.(type) {
+	case sharedQuery.OffsetPagination:
 		opts.SetSkip(int64(p.Offset))
 		opts.SetLimit(int64(p.Limit))
+	case sharedQuery.CursorPagination:
+		if len(order) == 0 {
+			return nil, fmt.Errorf("%w: cursor pagination needs a sort field", sharedQuery.ErrInvalidQuery)
+		}
+		if p.Cursor != "" {
+			after, err := cursorFilter(p.Cursor, order)
+			if err != nil {
+				return nil, err
+			}
+			filter = mongoAnd([]bson.D{filter, after})
+		}
+		opts.SetLimit(int64(p.Limit))
 	}
 
 	// Ordenamiento
-	if sort.Field != "" {
-		sortDir := 1 // Ascendente por defecto
-		if sort.Desc {
-			sortDir = -1 // Descendente
+	if len(order) > 0 {
+		sortDoc := make(bson.D, 0, len(order))
+		for _, s := range order {
+			sortDir := 1 // Ascendente por defecto
+			if s.Desc {
+				sortDir = -1 // Descendente
+			}
+			sortDoc = append(sortDoc, bson.E{Key: mongoField(s.Field), Value: sortDir})
 		}
-		opts.SetSort(bson.D{{Key: sort.Field, Value: sortDir}})
+		opts.SetSort(sortDoc)
+	}
+
+	return r.findTasks(ctx, filter, opts)
+}
+
+// GetByIDs recupera en una sola consulta ($in) las tareas de 'ids' que existen en el inquilino de ctx.
+func (r *TaskRepoMongoDB) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*taskDomain.Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
+	return r.findTasks(ctx, mongoAnd([]bson.D{{{Key: "_id", Value: bson.M{"$in": ids}}}, tenantFilter(ctx)}))
+}
 
-	cursor, err := r.tasksColl.Find(ctx, filter, opts)
+// findTasks ejecuta un Find sobre tasks y decodifica los documentos.
+func (r *TaskRepoMongoDB) findTasks(ctx context.Context, filter interface{}, opts ...*options.FindOptions) ([]*taskDomain.Task, error) {
+	cursor, err := r.tasksColl.Find(ctx, filter, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -199,68 +324,319 @@ func (r *TaskRepoMongoDB) ListByCriteria(ctx context.Context, criteria sharedDom
 		tasks = append(tasks, fromMongoTask(&mt))
 	}
 
-	return tasks, nil
+	return tasks, cursor.Err()
+}
+
+// CountByCriteria cuenta las tareas que cumplen 'criteria', con el mismo filtro que ListByCriteria.
+func (r *TaskRepoMongoDB) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	filter, err := r.scopedFilter(ctx, criteria)
+	if err != nil {
+		return 0, err
+	}
+	total, err := r.tasksColl.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return int(total), nil
+}
+
+// StreamByCriteria recorre las tareas que cumplen 'criteria' página a página (ver taskDomain.StreamByPages).
+func (r *TaskRepoMongoDB) StreamByCriteria(ctx context.Context, criteria sharedDomain.Criteria, fn func(*taskDomain.Task) error) error {
+	return taskDomain.StreamByPages(ctx, r.ListByCriteria, criteria, fn)
+}
+
+// scopedFilter traduce 'criteria' a un filtro limitado al inquilino de ctx.
+func (r *TaskRepoMongoDB) scopedFilter(ctx context.Context, criteria sharedDomain.Criteria) (bson.D, error) {
+	filter, err := criteriaToMongoFilter(criteria)
+	if err != nil {
+		return nil, err
+	}
+	return mongoAnd([]bson.D{tenantFilter(ctx), filter}), nil
+}
+
+// tenantFilter es la condición de los documentos del inquilino de ctx. A diferencia de SQL, donde
+// la migración rellena la columna, los documentos anteriores a los inquilinos no tienen tenantId:
+// el inquilino por defecto los incluye.
+func tenantFilter(ctx context.Context) bson.D {
+	tenant := tracing.TenantID(ctx)
+	if tenant == "" {
+		return bson.D{{Key: "tenantId", Value: bson.M{"$in": bson.A{"", nil}}}}
+	}
+	return bson.D{{Key: "tenantId", Value: tenant}}
+}
+
+// versionFilter limita una escritura a la versión 'version' de la tarea: la primera incluye los
+// documentos anteriores a las versiones, que no tienen el campo.
+func versionFilter(version int64) bson.D {
+	if version == 1 {
+		return bson.D{{Key: "version", Value: bson.M{"$in": bson.A{int64(1), nil}}}}
+	}
+	return bson.D{{Key: "version", Value: version}}
 }
 
 // --- Helpers de Mapeo y Conversión ---
 
-func toMongoTask(t *taskDomain.Task) *mongoTask {
+// Preflight comprueba que MongoDB responde y que la colección tasks se puede leer.
+func (r *TaskRepoMongoDB) Preflight(ctx context.Context) error {
+	return preflight.Mongo(ctx, r.tasksColl)
+}
+
+// Verificación estática
+var _ preflight.Checker = (*TaskRepoMongoDB)(nil)
+
+func toMongoTask(ctx context.Context, t *taskDomain.Task) *mongoTask {
 	return &mongoTask{
 		ID: t.ID, Title: t.Title, Description: t.Description,
 		AssigneeID: t.AssigneeID, Status: t.Status, CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+		Version: t.Version, TenantID: tracing.TenantID(ctx),
 	}
 }
 
 func fromMongoTask(mt *mongoTask) *taskDomain.Task {
-	return &taskDomain.Task{
+	t := &taskDomain.Task{
 		ID: mt.ID, Title: mt.Title, Description: mt.Description,
 		AssigneeID: mt.AssigneeID, Status: mt.Status, CreatedAt: mt.CreatedAt, UpdatedAt: mt.UpdatedAt,
+		Version: mt.Version,
 	}
+	if t.Version == 0 {
+		t.Version = 1 // Documento anterior a las versiones
+	}
+	return t
 }
 
-func toMongoOutboxEvent(evt sharedDomain.OutboxEvent) *mongoOutboxEvent {
+func toMongoOutboxEvent(ctx context.Context, evt sharedDomain.OutboxEvent) *mongoOutboxEvent {
+	if evt.Metadata == nil {
+		evt.Metadata = tracing.Metadata(ctx)
+	}
 	return &mongoOutboxEvent{
 		ID: evt.ID, AggregateType: evt.AggregateType, AggregateID: evt.AggregateID,
 		EventType: evt.EventType, Payload: evt.Payload, CreatedAt: evt.CreatedAt, Processed: false,
+		Metadata: evt.Metadata, TenantID: tracing.FromMap(evt.Metadata).TenantID,
 	}
 }
 
-func criteriaToMongoFilter(criteria sharedDomain.Criteria) bson.D {
+// taskFields son los campos por los que se puede filtrar y ordenar, con los nombres de columna
+// de SQL. Validarlos evita que un campo como "$where" llegue al filtro como operador.
+var taskFields = sharedQuery.NewFields(taskDomain.TaskFields.Names()...)
+
+// taskNullable son los campos de taskFields que pueden no tener valor (assignee_id). MongoDB ya
+// pone los null antes que el resto, como pide sharedQuery.Sort.Nullable.
+var taskNullable = sharedQuery.NewFields(taskDomain.TaskFields.Nullable()...)
+
+// mongoOperators traduce los operadores de los criterios a MongoDB. LIKE e ILIKE no tienen
+// equivalente y se traducen a $regex (ver likeToRegex). IS NULL es {$eq: null}, que también
+// cumplen los documentos sin el campo, e IS NOT NULL, {$ne: null}.
+var mongoOperators = map[sharedDomain.Operator]string{
+	sharedDomain.OpEq:        "$eq",
+	sharedDomain.OpGt:        "$gt",
+	sharedDomain.OpGte:       "$gte",
+	sharedDomain.OpLt:        "$lt",
+	sharedDomain.OpLte:       "$lte",
+	sharedDomain.OpLike:      "$regex",
+	sharedDomain.OpILike:     "$regex",
+	sharedDomain.OpIsNull:    "$eq",
+	sharedDomain.OpIsNotNull: "$ne",
+}
+
+// criteriaToMongoFilter traduce el árbol de criterios a un filtro de MongoDB: los AND a un solo
+// documento (o $and si repiten campo), los OR a $or y los NotCriteria a $nor.
+func criteriaToMongoFilter(criteria sharedDomain.Criteria) (bson.D, error) {
 	if criteria == nil {
-		return bson.D{}
+		return bson.D{}, nil
 	}
-	conds := criteria.ToConditions()
-	if len(conds) == 0 {
-		return bson.D{}
+
+	if not, ok := criteria.(sharedDomain.NotCriteria); ok {
+		if not.Criteria == nil {
+			return bson.D{}, nil
+		}
+		inner, err := criteriaToMongoFilter(not.Criteria)
+		if err != nil || len(inner) == 0 {
+			return bson.D{}, err
+		}
+		return bson.D{{Key: "$nor", Value: bson.A{inner}}}, nil
+	}
+
+	composite, ok := criteria.(sharedDomain.CompositeCriteria)
+	if pointer, isPointer := criteria.(*sharedDomain.CompositeCriteria); isPointer && pointer != nil {
+		composite, ok = *pointer, true
+	}
+	if !ok {
+		return conditionsToMongoFilter(criteria.ToConditions())
 	}
 
-	filter := bson.D{}
+	switch composite.Operator {
+	case sharedDomain.OpAnd, sharedDomain.OpOr, "":
+	default:
+		return nil, fmt.Errorf("%w: unsupported logical operator %q", sharedQuery.ErrInvalidQuery, composite.Operator)
+	}
+	var parts []bson.D
+	for _, child := range composite.Criterias {
+		part, err := criteriaToMongoFilter(child)
+		if err != nil {
+			return nil, err
+		}
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+	if composite.Operator == sharedDomain.OpOr && len(parts) > 1 {
+		or := make(bson.A, 0, len(parts))
+		for _, part := range parts {
+			or = append(or, part)
+		}
+		return bson.D{{Key: "$or", Value: or}}, nil
+	}
+	return mongoAnd(parts), nil
+}
+
+// conditionsToMongoFilter traduce el AND de las condiciones de un criterio simple.
+func conditionsToMongoFilter(conds []sharedDomain.Criterion) (bson.D, error) {
+	parts := make([]bson.D, 0, len(conds))
 	for _, c := range conds {
-		// Mapeo de operadores genéricos a operadores de MongoDB
-		var mongoOp string
+		if err := taskFields.CheckCriterion(c); err != nil {
+			return nil, err
+		}
+		mongoOp, ok := mongoOperators[c.Op]
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported operator %q", sharedQuery.ErrInvalidQuery, c.Op)
+		}
+
+		// Los patrones LIKE se traducen a una expresión regular; para ILIKE, añadimos la
+		// opción 'i' de insensibilidad a mayúsculas
 		switch c.Op {
-		case sharedDomain.OpEq:
-			mongoOp = "$eq"
-		case sharedDomain.OpGt:
-			mongoOp = "$gt"
-		case sharedDomain.OpGte:
-			mongoOp = "$gte"
-		case sharedDomain.OpLt:
-			mongoOp = "$lt"
-		case sharedDomain.OpLte:
-			mongoOp = "$lte"
-		case sharedDomain.OpLike, sharedDomain.OpILike:
-			mongoOp = "$regex"
+		case sharedDomain.OpILike:
+			parts = append(parts, bson.D{{Key: mongoField(c.Field), Value: bson.M{mongoOp: likeToRegex(fmt.Sprint(c.Value)), "$options": "i"}}})
+		case sharedDomain.OpLike:
+			parts = append(parts, bson.D{{Key: mongoField(c.Field), Value: bson.M{mongoOp: likeToRegex(fmt.Sprint(c.Value))}}})
+		case sharedDomain.OpIsNull, sharedDomain.OpIsNotNull:
+			parts = append(parts, bson.D{{Key: mongoField(c.Field), Value: bson.M{mongoOp: nil}}})
 		default:
-			mongoOp = "$eq" // Operador por defecto
+			parts = append(parts, bson.D{{Key: mongoField(c.Field), Value: bson.M{mongoOp: c.Value}}})
 		}
+	}
+	return mongoAnd(parts), nil
+}
 
-		// Para ILIKE, añadimos la opción 'i' de insensibilidad a mayúsculas
-		if c.Op == sharedDomain.OpILike {
-			filter = append(filter, bson.E{Key: c.Field, Value: bson.M{mongoOp: strings.Trim(c.Value.(string), "%"), "$options": "i"}})
-		} else {
-			filter = append(filter, bson.E{Key: c.Field, Value: bson.M{mongoOp: c.Value}})
+// mongoAnd une 'parts' con AND: en un solo documento, la forma habitual de un filtro, salvo que
+// dos partes usen la misma clave (ej. un rango sobre createdAt o dos $or), porque en un
+// documento la segunda sustituiría a la primera. Entonces se usa $and.
+func mongoAnd(parts []bson.D) bson.D {
+	switch len(parts) {
+	case 0:
+		return bson.D{}
+	case 1:
+		return parts[0]
+	}
+	merged := bson.D{}
+	seen := map[string]bool{}
+	for _, part := range parts {
+		for _, e := range part {
+			if seen[e.Key] {
+				and := make(bson.A, 0, len(parts))
+				for _, p := range parts {
+					and = append(and, p)
+				}
+				return bson.D{{Key: "$and", Value: and}}
+			}
+			seen[e.Key] = true
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// cursorFilter devuelve la condición de los documentos que van detrás del cursor en el orden
+// 'order' (ver query.Keyset): un $or con una rama por campo de ordenación.
+func cursorFilter(token string, order []sharedQuery.Sort) (bson.D, error) {
+	cursor, err := sharedQuery.DecodeCursor(token)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := sharedQuery.Keyset(order, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	or := make(bson.A, 0, len(branches))
+	for _, branch := range branches {
+		doc := make(bson.D, 0, len(branch))
+		for _, c := range branch {
+			value := c.Value
+			if text, ok := value.(string); ok {
+				if value, err = cursorValue(c.Field, text); err != nil {
+					return nil, err
+				}
+			}
+			switch c.Op {
+			case sharedDomain.OpGt:
+				value = bson.M{"$gt": value}
+			case sharedDomain.OpLt:
+				value = bson.M{"$lt": value}
+			case sharedDomain.OpIsNull:
+				value = nil
+			case sharedDomain.OpIsNotNull:
+				value = bson.M{"$ne": nil}
+			}
+			doc = append(doc, bson.E{Key: mongoField(c.Field), Value: value})
+		}
+		or = append(or, doc)
+	}
+	if len(or) == 1 {
+		return or[0].(bson.D), nil
+	}
+	return bson.D{{Key: "$or", Value: or}}, nil
+}
+
+// cursorValue convierte el valor del cursor, que siempre es texto, al tipo con el que se guarda
+// el campo, porque Mongo no compara valores de tipos distintos.
+func cursorValue(field, text string) (any, error) {
+	switch field {
+	case "created_at", "updated_at":
+		t, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor value for %s", sharedQuery.ErrInvalidQuery, field)
+		}
+		return t, nil
+	case "assignee_id":
+		id, err := uuid.Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor value for %s", sharedQuery.ErrInvalidQuery, field)
+		}
+		return id, nil
+	}
+	return text, nil
+}
+
+// mongoFields traduce los nombres de columna que usan los criterios y la ordenación, que son
+// los de SQL, a los campos de los documentos.
+var mongoFields = map[string]string{
+	"id":          "_id",
+	"assignee_id": "assigneeId",
+	"created_at":  "createdAt",
+	"updated_at":  "updatedAt",
+}
+
+func mongoField(field string) string {
+	if f, ok := mongoFields[field]; ok {
+		return f
+	}
+	return field
+}
+
+// likeToRegex traduce un patrón LIKE (% y _ como comodines) a una expresión regular anclada.
+func likeToRegex(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
 		}
 	}
-	return filter
+	b.WriteString("$")
+	return b.String()
 }
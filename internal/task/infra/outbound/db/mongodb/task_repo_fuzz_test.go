@@ -0,0 +1,39 @@
+package mongodb
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// FuzzCriteriaToMongoFilter traduce condiciones arbitrarias: o se rechazan con ErrInvalidQuery, o
+// el filtro solo tiene campos de documento (nunca operadores como $where) y los patrones LIKE
+// son expresiones regulares válidas.
+func FuzzCriteriaToMongoFilter(f *testing.F) {
+	f.Add("title", "ILIKE", "%backlog%")
+	f.Add("status", "=", "pending")
+	f.Add("$where", "=", "sleep(1000)")
+	f.Add("title", "LIKE", "(a+)+$[")
+	f.Add("title", "$regex", ".*")
+
+	f.Fuzz(func(t *testing.T, field, op, value string) {
+		filter, err := criteriaToMongoFilter(mocks.Criteria{{Field: field, Op: sharedDomain.Operator(op), Value: value}})
+		if err != nil {
+			require.ErrorIs(t, err, sharedQuery.ErrInvalidQuery)
+			return
+		}
+		require.Len(t, filter, 1)
+		require.False(t, strings.HasPrefix(filter[0].Key, "$"), "operator %q used as a field", filter[0].Key)
+		if pattern, ok := filter[0].Value.(bson.M)["$regex"].(string); ok {
+			_, err := regexp.Compile(pattern)
+			require.NoError(t, err)
+		}
+	})
+}
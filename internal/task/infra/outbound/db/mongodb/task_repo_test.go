@@ -0,0 +1,140 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+func TestCursorFilter(t *testing.T) {
+	id, assignee := uuid.New(), uuid.New()
+	createdAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor sharedQuery.Cursor
+		sorts  []sharedQuery.Sort
+		want   bson.D
+	}{
+		{
+			name:   "ascending time",
+			cursor: sharedQuery.NewCursor(id, createdAt),
+			sorts:  []sharedQuery.Sort{{Field: "created_at"}},
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "createdAt", Value: bson.M{"$gt": createdAt}}},
+				bson.D{{Key: "createdAt", Value: createdAt}, {Key: "_id", Value: bson.M{"$gt": id}}},
+			}}},
+		},
+		{
+			name:   "descending uuid",
+			cursor: sharedQuery.NewCursor(id, assignee),
+			sorts:  []sharedQuery.Sort{{Field: "assignee_id", Desc: true}},
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "assigneeId", Value: bson.M{"$lt": assignee}}},
+				bson.D{{Key: "assigneeId", Value: nil}},
+				bson.D{{Key: "assigneeId", Value: assignee}, {Key: "_id", Value: bson.M{"$lt": id}}},
+			}}},
+		},
+		{
+			name:   "ascending from an unassigned task",
+			cursor: sharedQuery.NewCursor(id, nil),
+			sorts:  []sharedQuery.Sort{{Field: "assignee_id"}},
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "assigneeId", Value: bson.M{"$ne": nil}}},
+				bson.D{{Key: "assigneeId", Value: nil}, {Key: "_id", Value: bson.M{"$gt": id}}},
+			}}},
+		},
+		{
+			name:   "by id",
+			cursor: sharedQuery.NewCursor(id),
+			sorts:  []sharedQuery.Sort{{Field: "id"}},
+			want:   bson.D{{Key: "_id", Value: bson.M{"$gt": id}}},
+		},
+		{
+			name:   "several fields with mixed directions",
+			cursor: sharedQuery.NewCursor(id, "pending", createdAt),
+			sorts:  []sharedQuery.Sort{{Field: "status"}, {Field: "created_at", Desc: true}},
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "status", Value: bson.M{"$gt": "pending"}}},
+				bson.D{{Key: "status", Value: "pending"}, {Key: "createdAt", Value: bson.M{"$lt": createdAt}}},
+				bson.D{{Key: "status", Value: "pending"}, {Key: "createdAt", Value: createdAt}, {Key: "_id", Value: bson.M{"$lt": id}}},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := cursorFilter(tt.cursor.Encode(), sharedQuery.MarkNullable(sharedQuery.OrderBy(tt.sorts), taskNullable))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCursorFilter_Invalid(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name  string
+		token string
+		field string
+	}{
+		{"not a cursor", "2025-03-01T10:00:00Z|" + id.String(), "created_at"},
+		{"time field", sharedQuery.NewCursor(id, "yesterday").Encode(), "created_at"},
+		{"uuid field", sharedQuery.NewCursor(id, "nobody").Encode(), "assignee_id"},
+		{"missing value", sharedQuery.NewCursor(id).Encode(), "created_at"},
+	}
+	for _, tt := range tests {
+		_, err := cursorFilter(tt.token, sharedQuery.OrderBy([]sharedQuery.Sort{{Field: tt.field}}))
+		assert.ErrorIs(t, err, sharedQuery.ErrInvalidQuery, tt.name)
+	}
+}
+
+func TestCriteriaToMongoFilter(t *testing.T) {
+	// Arrange
+	assignee := uuid.New()
+	from, to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	completed := taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted}
+	assigned := taskDomain.AssigneeIDCriteria{ID: assignee}
+	statusDoc := bson.D{{Key: "status", Value: bson.M{"$eq": taskDomain.TaskCompleted}}}
+	assigneeDoc := bson.D{{Key: "assigneeId", Value: bson.M{"$eq": assignee}}}
+
+	tests := []struct {
+		name     string
+		criteria sharedDomain.Criteria
+		want     bson.D
+	}{
+		{"and in one document", sharedDomain.And(completed, assigned), append(append(bson.D{}, statusDoc...), assigneeDoc...)},
+		{"or", sharedDomain.Or(completed, assigned), bson.D{{Key: "$or", Value: bson.A{statusDoc, assigneeDoc}}}},
+		{"not completed and not assigned", sharedDomain.And(sharedDomain.Not(completed), sharedDomain.Not(assigned)), bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "$nor", Value: bson.A{statusDoc}}},
+			bson.D{{Key: "$nor", Value: bson.A{assigneeDoc}}},
+		}}}},
+		{"range on one field", taskDomain.CreatedAtRangeCriteria{Start: &from, End: &to}, bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "createdAt", Value: bson.M{"$gte": from}}},
+			bson.D{{Key: "createdAt", Value: bson.M{"$lte": to}}},
+		}}}},
+		{"empty groups", sharedDomain.Or(sharedDomain.And(), sharedDomain.Not(sharedDomain.And())), bson.D{}},
+		{"unassigned", taskDomain.UnassignedCriteria{}, bson.D{{Key: "assigneeId", Value: bson.M{"$eq": nil}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			filter, err := criteriaToMongoFilter(tt.criteria)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, filter)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// taskFields son las columnas de tasks por las que se puede filtrar y ordenar (taskDomain.TaskFields),
+// más la del inquilino, que añade sharedQuery.TenantScope.
+var taskFields = sharedQuery.NewFields(append(taskDomain.TaskFields.Names(), sharedQuery.TenantColumn)...)
+
+// taskNullable son las columnas de taskFields que admiten NULL (assignee_id).
+var taskNullable = sharedQuery.NewFields(taskDomain.TaskFields.Nullable()...)
+
+// taskMapper traduce las tareas a y desde la tabla tasks de Postgres (ver sqlrepo.Mapper).
+type taskMapper struct{}
+
+func (taskMapper) Table() sqlrepo.Table {
+	return sqlrepo.Table{
+		Name:            "tasks",
+		Columns:         []string{"id", "title", "description", "assignee_id", "status", "created_at", "updated_at", "version"},
+		Immutable:       []string{"created_at"},
+		Fields:          taskFields,
+		Nullable:        taskNullable,
+		NotFound:        taskDomain.ErrTaskNotFound,
+		Conflict:        taskDomain.ErrTaskAlreadyExists,
+		Version:         "version",
+		VersionConflict: taskDomain.ErrTaskVersionConflict,
+	}
+}
+
+func (taskMapper) Values(t *taskDomain.Task) []any {
+	return []any{t.ID, t.Title, t.Description, t.AssigneeID, t.Status, t.CreatedAt, t.UpdatedAt, t.Version}
+}
+
+// Scan lee una fila de tasks. Los ids (UUID) y las fechas (TIMESTAMPTZ) se leen en sus tipos
+// nativos, sin pasar por texto.
+func (taskMapper) Scan(row sqlrepo.Scanner) (*taskDomain.Task, error) {
+	var t taskDomain.Task
+	if err := row.Scan(&t.ID, &t.Title, &t.Description, &t.AssigneeID, &t.Status, &t.CreatedAt, &t.UpdatedAt, &t.Version); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Arg pasa los UUID como texto, que Postgres convierte al tipo de la columna.
+func (taskMapper) Arg(value any) any {
+	if id, ok := value.(uuid.UUID); ok {
+		return id.String()
+	}
+	return value
+}
+
+// Verificación estática
+var _ sqlrepo.Mapper[taskDomain.Task] = taskMapper{}
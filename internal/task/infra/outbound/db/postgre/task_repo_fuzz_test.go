@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+var placeholder = regexp.MustCompile(`\$\d+`)
+
+// FuzzListQuery construye el SELECT de ListByCriteria con campos, operadores, valores y
+// ordenación arbitrarios. Sin un Postgres a mano se comprueba la forma de la consulta: o se
+// rechaza con ErrInvalidQuery, o solo interpola campos conocidos y cada valor va en su parámetro.
+func FuzzListQuery(f *testing.F) {
+	f.Add("title", "ILIKE", "%backlog%", "created_at", true)
+	f.Add("assignee_id", "=", uuid.NewString(), "updated_at", false)
+	f.Add("title; DROP TABLE tasks; --", "=", "x", "created_at", false)
+	f.Add("status", "= '' OR 1=1 --", "x", "status", true)
+	f.Add("title", "LIKE", "$1", "title DESC, (SELECT 1)", false)
+	f.Add("id", "<", "\x00", "", false)
+
+	repo := NewTaskRepoPostgres(nil)
+	f.Fuzz(func(t *testing.T, field, op, value, sortField string, desc bool) {
+		criteria := mocks.Criteria{{Field: field, Op: sharedDomain.Operator(op), Value: value}}
+		sorts := []sharedQuery.Sort{{Field: sortField, Desc: desc}}
+
+		query, args, err := repo.ListQuery(criteria, sharedQuery.OffsetPagination{Limit: 10}, sorts)
+		if err != nil {
+			require.ErrorIs(t, err, sharedQuery.ErrInvalidQuery)
+			return
+		}
+		require.True(t, taskFields[field], "unknown field %q reached the SQL: %s", field, query)
+		require.True(t, sortField == "" || taskFields[sortField], "unknown sort field %q reached the SQL: %s", sortField, query)
+		requirePlaceholders(t, query, len(args))
+	})
+}
+
+// requirePlaceholders comprueba que la consulta usa exactamente los parámetros $1..$n y ningún
+// literal de texto.
+func requirePlaceholders(t *testing.T, query string, n int) {
+	t.Helper()
+	seen := map[string]bool{}
+	for _, p := range placeholder.FindAllString(query, -1) {
+		seen[p] = true
+	}
+	require.Len(t, seen, n, query)
+	for i := 1; i <= n; i++ {
+		require.True(t, seen[fmt.Sprintf("$%d", i)], "missing $%d in %s", i, query)
+	}
+	require.NotContains(t, query, "'", "values must be bound, not interpolated: %s", query)
+}
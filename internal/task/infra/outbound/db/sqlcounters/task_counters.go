@@ -0,0 +1,129 @@
+// Package sqlcounters guarda la proyección de contadores de tareas por usuario en SQLite o
+// Postgres. Las tablas las crea el esquema de tareas de cada motor.
+package sqlcounters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// TaskCountersSQL implementa TaskCounterRepository sobre las tablas user_task_counts (los
+// contadores de cada usuario) y task_counter_state (el último cambio aplicado a cada tarea),
+// ambas con el inquilino en tenant_id.
+type TaskCountersSQL struct {
+	db *sql.DB
+
+	lastSQL, upsertCountsSQL, upsertStateSQL, summarySQL string
+	resetSQL                                             []string
+}
+
+// New es el constructor; 'engine' pone los parámetros de las consultas del motor de 'db'.
+func New(db *sql.DB, engine sqlrepo.Engine) *TaskCountersSQL {
+	p := engine.Placeholder
+	return &TaskCountersSQL{
+		db: db,
+		lastSQL: `SELECT assignee_id, status, version, deleted FROM task_counter_state
+            WHERE tenant_id = ` + p(1) + ` AND task_id = ` + p(2),
+		upsertCountsSQL: `INSERT INTO user_task_counts (tenant_id, user_id, pending, completed)
+            VALUES (` + p(1) + `, ` + p(2) + `, ` + p(3) + `, ` + p(4) + `)
+            ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+                pending = user_task_counts.pending + excluded.pending,
+                completed = user_task_counts.completed + excluded.completed`,
+		upsertStateSQL: `INSERT INTO task_counter_state (tenant_id, task_id, assignee_id, status, version, deleted)
+            VALUES (` + p(1) + `, ` + p(2) + `, ` + p(3) + `, ` + p(4) + `, ` + p(5) + `, ` + p(6) + `)
+            ON CONFLICT (tenant_id, task_id) DO UPDATE SET
+                assignee_id = excluded.assignee_id, status = excluded.status,
+                version = excluded.version, deleted = excluded.deleted`,
+		summarySQL: `SELECT pending, completed FROM user_task_counts WHERE tenant_id = ` + p(1) + ` AND user_id = ` + p(2),
+		resetSQL: []string{
+			`DELETE FROM user_task_counts WHERE tenant_id = ` + p(1),
+			`DELETE FROM task_counter_state WHERE tenant_id = ` + p(1),
+		},
+	}
+}
+
+func (r *TaskCountersSQL) Apply(ctx context.Context, change taskDomain.TaskCounterChange) error {
+	tenant := tracing.TenantID(ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	prev, err := r.last(ctx, tx, tenant, change.TaskID)
+	if err != nil {
+		return err
+	}
+	if !change.Supersedes(prev) {
+		return nil
+	}
+	for user, d := range change.Deltas(prev) {
+		if _, err := tx.ExecContext(ctx, r.upsertCountsSQL, tenant, user, d.Pending, d.Completed); err != nil {
+			return fmt.Errorf("failed to update task counters: %w", err)
+		}
+	}
+	var assignee uuid.NullUUID
+	if change.AssigneeID != nil {
+		assignee = uuid.NullUUID{UUID: *change.AssigneeID, Valid: true}
+	}
+	_, err = tx.ExecContext(ctx, r.upsertStateSQL, tenant, change.TaskID, assignee, string(change.Status), change.Version, change.Deleted)
+	if err != nil {
+		return fmt.Errorf("failed to save task counter state: %w", err)
+	}
+	return tx.Commit()
+}
+
+// last devuelve el último cambio aplicado a la tarea, o nil si no se ha visto.
+func (r *TaskCountersSQL) last(ctx context.Context, tx *sql.Tx, tenant string, taskID uuid.UUID) (*taskDomain.TaskCounterChange, error) {
+	change := taskDomain.TaskCounterChange{TaskID: taskID}
+	var assignee uuid.NullUUID
+	var status string
+	err := tx.QueryRowContext(ctx, r.lastSQL, tenant, taskID).Scan(&assignee, &status, &change.Version, &change.Deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task counter state: %w", err)
+	}
+	if assignee.Valid {
+		change.AssigneeID = &assignee.UUID
+	}
+	change.Status = taskDomain.TaskStatus(status)
+	return &change, nil
+}
+
+func (r *TaskCountersSQL) Summary(ctx context.Context, userID uuid.UUID) (taskDomain.TaskSummary, error) {
+	summary := taskDomain.TaskSummary{UserID: userID}
+	err := r.db.QueryRowContext(ctx, r.summarySQL, tracing.TenantID(ctx), userID).Scan(&summary.Pending, &summary.Completed)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return taskDomain.TaskSummary{}, fmt.Errorf("failed to read task counters: %w", err)
+	}
+	return summary, nil
+}
+
+func (r *TaskCountersSQL) Reset(ctx context.Context) error {
+	tenant := tracing.TenantID(ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range r.resetSQL {
+		if _, err := tx.ExecContext(ctx, query, tenant); err != nil {
+			return fmt.Errorf("failed to reset task counters: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.TaskCounterRepository = (*TaskCountersSQL)(nil)
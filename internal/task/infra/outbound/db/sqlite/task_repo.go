@@ -0,0 +1,234 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	sharedSQLite "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlite"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// TaskRepoSQLite implementa la interfaz TaskRepository para SQLite sobre la base común de los
+// repositorios SQL (ver taskMapper). Pensado para desarrollo local: no necesita ningún servicio externo.
+type TaskRepoSQLite struct {
+	*sqlrepo.Repository[taskDomain.Task]
+}
+
+// NewTaskRepoSQLite es el constructor del repositorio.
+func NewTaskRepoSQLite(db *sql.DB) *TaskRepoSQLite {
+	return &TaskRepoSQLite{sqlrepo.New(db, sharedSQLite.Engine, taskMapper{})}
+}
+
+// UpdateStatus cambia el estado de las tareas con un solo UPDATE (ver sqlrepo.Repository.UpdateMany).
+func (r *TaskRepoSQLite) UpdateStatus(ctx context.Context, tasks []*taskDomain.Task, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error) {
+	return r.UpdateMany(ctx, []string{"status", "updated_at"}, tasks, events)
+}
+
+// StreamByCriteria recorre las tareas que cumplen 'criteria' página a página (ver taskDomain.StreamByPages).
+func (r *TaskRepoSQLite) StreamByCriteria(ctx context.Context, criteria sharedDomain.Criteria, fn func(*taskDomain.Task) error) error {
+	return taskDomain.StreamByPages(ctx, r.ListByCriteria, criteria, fn)
+}
+
+// taskFields son las columnas de tasks por las que se puede filtrar y ordenar (taskDomain.TaskFields),
+// más la del inquilino, que añade sharedQuery.TenantScope.
+var taskFields = sharedQuery.NewFields(append(taskDomain.TaskFields.Names(), sharedQuery.TenantColumn)...)
+
+// taskNullable son las columnas de taskFields que admiten NULL (assignee_id).
+var taskNullable = sharedQuery.NewFields(taskDomain.TaskFields.Nullable()...)
+
+// taskMapper traduce las tareas a y desde la tabla tasks de SQLite (ver sqlrepo.Mapper). Los
+// UUID, los estados y las fechas se guardan como texto; una tarea sin asignar, con assignee_id NULL.
+type taskMapper struct{}
+
+func (taskMapper) Table() sqlrepo.Table {
+	return sqlrepo.Table{
+		Name:            "tasks",
+		Columns:         []string{"id", "title", "description", "assignee_id", "status", "created_at", "updated_at", "version"},
+		Immutable:       []string{"created_at"},
+		Fields:          taskFields,
+		Nullable:        taskNullable,
+		NotFound:        taskDomain.ErrTaskNotFound,
+		Conflict:        taskDomain.ErrTaskAlreadyExists,
+		Version:         "version",
+		VersionConflict: taskDomain.ErrTaskVersionConflict,
+	}
+}
+
+func (taskMapper) Values(t *taskDomain.Task) []any {
+	return []any{
+		t.ID.String(), t.Title, t.Description, assigneeColumn(t.AssigneeID), string(t.Status),
+		formatTime(t.CreatedAt), formatTime(t.UpdatedAt), t.Version,
+	}
+}
+
+// Scan lee una fila de 'tasks', parseando los campos que SQLite guarda como texto.
+func (taskMapper) Scan(row sqlrepo.Scanner) (*taskDomain.Task, error) {
+	var t taskDomain.Task
+	var idStr, status, createdAtStr, updatedAtStr string
+	var assigneeStr sql.NullString
+	if err := row.Scan(&idStr, &t.Title, &t.Description, &assigneeStr, &status, &createdAtStr, &updatedAtStr, &t.Version); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if t.ID, err = uuid.Parse(idStr); err != nil {
+		return nil, fmt.Errorf("invalid task id: %w", err)
+	}
+	if assigneeStr.Valid {
+		assigneeID, err := uuid.Parse(assigneeStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid assignee_id: %w", err)
+		}
+		t.AssigneeID = &assigneeID
+	}
+	t.Status = taskDomain.TaskStatus(status)
+	if t.CreatedAt, err = time.Parse(sqliteTimeLayout, createdAtStr); err != nil {
+		return nil, fmt.Errorf("error parsing created_at: %w", err)
+	}
+	if t.UpdatedAt, err = time.Parse(sqliteTimeLayout, updatedAtStr); err != nil {
+		return nil, fmt.Errorf("error parsing updated_at: %w", err)
+	}
+	return &t, nil
+}
+
+// assigneeColumn es el valor de assignee_id: el UUID como texto o NULL si no hay asignado.
+func assigneeColumn(id *uuid.UUID) any {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+// Arg convierte un valor al texto con el que SQLite guarda UUIDs, estados y fechas.
+func (taskMapper) Arg(value any) any {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v.String()
+	case taskDomain.TaskStatus:
+		return string(v)
+	case time.Time:
+		return formatTime(v)
+	}
+	return value
+}
+
+// CursorValues convierte las fechas del cursor (ver parseCursorTimes).
+func (taskMapper) CursorValues(branches [][]sharedDomain.Criterion) error {
+	return parseCursorTimes(branches)
+}
+
+// parseCursorTimes convierte las fechas del cursor, que viajan en RFC 3339, a time.Time: SQLite
+// las compara como texto y solo el formato de formatTime respeta el orden cronológico.
+func parseCursorTimes(branches [][]sharedDomain.Criterion) error {
+	for _, branch := range branches {
+		for i, c := range branch {
+			text, ok := c.Value.(string)
+			if !ok || (c.Field != "created_at" && c.Field != "updated_at") {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return fmt.Errorf("%w: invalid cursor", sharedQuery.ErrInvalidQuery)
+			}
+			branch[i].Value = t
+		}
+	}
+	return nil
+}
+
+// formatTime serializa las fechas en UTC con ancho fijo, para que la comparación
+// de textos en SQLite respete el orden cronológico.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// ------------------ Inicialización de DB ------------------
+
+// InitSQLiteTaskSchema crea las tablas 'tasks' y 'outbox', y las de los contadores de tareas por
+// usuario (ver sqlcounters), si no existen.
+func InitSQLiteTaskSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+        CREATE TABLE IF NOT EXISTS tasks (
+            id TEXT PRIMARY KEY,
+            title TEXT NOT NULL,
+            description TEXT,
+            assignee_id TEXT,
+            status TEXT NOT NULL,
+            created_at TEXT NOT NULL,
+            updated_at TEXT NOT NULL,
+            tenant_id TEXT NOT NULL DEFAULT '',
+            version INTEGER NOT NULL DEFAULT 1
+        )
+    `)
+	if err != nil {
+		return fmt.Errorf("failed to create tasks table: %w", err)
+	}
+	// Las tareas anteriores a los inquilinos quedan en el inquilino por defecto, y las anteriores
+	// a las versiones, en la primera.
+	if err := sharedSQLite.MigrateColumns(db, "tasks",
+		sharedSQLite.Column{Name: "tenant_id", Definition: `tenant_id TEXT NOT NULL DEFAULT ''`},
+		sharedSQLite.Column{Name: "version", Definition: `version INTEGER NOT NULL DEFAULT 1`},
+	); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_tasks_tenant ON tasks (tenant_id)`); err != nil {
+		return fmt.Errorf("failed to create tasks tenant index: %w", err)
+	}
+
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS user_task_counts (
+            tenant_id TEXT NOT NULL DEFAULT '',
+            user_id TEXT NOT NULL,
+            pending INTEGER NOT NULL DEFAULT 0,
+            completed INTEGER NOT NULL DEFAULT 0,
+            PRIMARY KEY (tenant_id, user_id)
+        );
+        CREATE TABLE IF NOT EXISTS task_counter_state (
+            tenant_id TEXT NOT NULL DEFAULT '',
+            task_id TEXT NOT NULL,
+            assignee_id TEXT,
+            status TEXT NOT NULL,
+            version INTEGER NOT NULL,
+            deleted BOOLEAN NOT NULL DEFAULT 0,
+            PRIMARY KEY (tenant_id, task_id)
+        )
+    `)
+	if err != nil {
+		return fmt.Errorf("failed to create task counter tables: %w", err)
+	}
+
+	// Misma definición que la del dominio de usuarios: ambos comparten outbox si usan el mismo fichero.
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS outbox (
+            id TEXT PRIMARY KEY,
+            aggregate_type TEXT NOT NULL,
+            aggregate_id TEXT NOT NULL,
+            event_type TEXT NOT NULL,
+            payload TEXT NOT NULL,
+            metadata TEXT NOT NULL DEFAULT '{}',
+            created_at DATETIME NOT NULL,
+            processed BOOLEAN NOT NULL DEFAULT 0
+        )
+    `)
+	if err != nil {
+		return err
+	}
+	// Las tablas creadas antes de guardar metadatos de traza no tienen la columna.
+	return sharedSQLite.MigrateOutboxSchema(db)
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.TaskRepository = (*TaskRepoSQLite)(nil)
+var _ preflight.Checker = (*TaskRepoSQLite)(nil)
+var _ sqlrepo.CursorMapper = taskMapper{}
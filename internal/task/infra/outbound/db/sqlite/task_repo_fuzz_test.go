@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// FuzzListByCriteria ejecuta ListByCriteria contra un SQLite real con campos, operadores, valores
+// y ordenación arbitrarios: la consulta se rechaza con ErrInvalidQuery o es SQL válido que
+// SQLite ejecuta sin errores y sin tocar la tabla.
+func FuzzListByCriteria(f *testing.F) {
+	db, err := sql.Open("sqlite", filepath.Join(f.TempDir(), "tasks.db"))
+	require.NoError(f, err)
+	f.Cleanup(func() { db.Close() })
+	require.NoError(f, InitSQLiteTaskSchema(db))
+	repo := NewTaskRepoSQLite(db)
+	now := time.Now().UTC()
+	assigneeID := uuid.New()
+	task := &taskDomain.Task{ID: uuid.New(), Title: "Revisar el backlog", AssigneeID: &assigneeID, Status: taskDomain.TaskPending, CreatedAt: now, UpdatedAt: now}
+	require.NoError(f, repo.Create(context.Background(), task, sharedDomain.OutboxEvent{ID: uuid.New(), Payload: task, CreatedAt: now}))
+
+	f.Add("title", "ILIKE", "%backlog%", "created_at", true)
+	f.Add("status", "=", "pending", "title", false)
+	f.Add("assignee_id", "=", uuid.NewString(), "updated_at", false)
+	f.Add("title; DROP TABLE tasks; --", "=", "x", "created_at", false)
+	f.Add("status", "= '' OR 1=1 --", "x", "status", true)
+	f.Add("title", "LIKE", "%' OR '1'='1", "title DESC, (SELECT 1)", false)
+	f.Add("id", "<", "\x00", "", false)
+
+	f.Fuzz(func(t *testing.T, field, op, value, sortField string, desc bool) {
+		criteria := mocks.Criteria{{Field: field, Op: sharedDomain.Operator(op), Value: value}}
+		sorts := []sharedQuery.Sort{{Field: sortField, Desc: desc}}
+
+		_, err := repo.ListByCriteria(context.Background(), criteria, sharedQuery.OffsetPagination{Limit: 10}, sorts)
+		if err != nil {
+			require.ErrorIs(t, err, sharedQuery.ErrInvalidQuery, "the query reached SQLite and failed")
+		}
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count))
+		require.Equal(t, 1, count)
+	})
+}
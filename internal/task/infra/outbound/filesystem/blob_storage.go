@@ -0,0 +1,68 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// FileBlobStorage es un adaptador outbound que guarda blobs como ficheros dentro de un directorio base.
+type FileBlobStorage struct {
+	baseDir string
+}
+
+// NewFileBlobStorage es el constructor.
+func NewFileBlobStorage(baseDir string) *FileBlobStorage {
+	return &FileBlobStorage{baseDir: baseDir}
+}
+
+// Put escribe el contenido de 'r' en baseDir/key, sin dejar ficheros a medias (ver writeAtomic).
+func (s *FileBlobStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	path := filepath.Join(s.baseDir, filepath.Clean("/"+key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	err := writeAtomic(path, 0644, func(w io.Writer) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// writeAtomic escribe el fichero 'path' con lo que 'write' vuelque en él. Se escribe primero en un
+// fichero temporal del mismo directorio, se lleva a disco y se renombra al terminar: el rename es
+// atómico, así que tras una caída 'path' tiene el contenido anterior o el nuevo, nunca uno a medias.
+func writeAtomic(path string, perm os.FileMode, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op si el rename ha funcionado
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.BlobStorage = (*FileBlobStorage)(nil)
@@ -0,0 +1,149 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// OutboxRepoFile implementa shared.OutboxRepository sobre el fichero de TaskRepoFile, donde cada
+// escritura de una tarea guarda su evento. Como en el outbox en memoria, los eventos publicados
+// se descartan y los apartados como dead letter se conservan aparte, con su motivo.
+type OutboxRepoFile struct {
+	file *stateFile
+}
+
+// FetchPendingOutbox devuelve hasta 'limit' eventos pendientes, del más antiguo al más reciente.
+func (r *OutboxRepoFile) FetchPendingOutbox(ctx context.Context, limit int) ([]sharedDomain.OutboxEvent, error) {
+	s, err := r.file.read()
+	if err != nil {
+		return nil, err
+	}
+	events := s.Outbox
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	if limit >= 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// MarkOutboxProcessed descarta un evento ya publicado.
+func (r *OutboxRepoFile) MarkOutboxProcessed(ctx context.Context, id uuid.UUID) error {
+	return r.file.update(func(s *fileState) error {
+		i, err := s.eventIndex(id)
+		if err != nil {
+			return err
+		}
+		s.Outbox = slices.Delete(s.Outbox, i, i+1)
+		return nil
+	})
+}
+
+// MarkOutboxDeadLetter aparta un evento que no se puede publicar.
+func (r *OutboxRepoFile) MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.file.update(func(s *fileState) error {
+		i, err := s.eventIndex(id)
+		if err != nil {
+			return err
+		}
+		s.DeadLetters = append(s.DeadLetters, deadLetter{OutboxEvent: s.Outbox[i], Reason: reason})
+		s.Outbox = slices.Delete(s.Outbox, i, i+1)
+		return nil
+	})
+}
+
+// CountPendingOutbox devuelve cuántos eventos quedan por publicar.
+func (r *OutboxRepoFile) CountPendingOutbox(ctx context.Context) (int, error) {
+	s, err := r.file.read()
+	if err != nil {
+		return 0, err
+	}
+	return len(s.Outbox), nil
+}
+
+// OutboxStats cuenta los eventos pendientes y los apartados por tipo de evento.
+func (r *OutboxRepoFile) OutboxStats(ctx context.Context) (sharedDomain.OutboxStats, error) {
+	s, err := r.file.read()
+	if err != nil {
+		return sharedDomain.OutboxStats{}, err
+	}
+	stats := sharedDomain.NewOutboxStats()
+	for _, evt := range s.Outbox {
+		stats.Add(evt.EventType, false, 1)
+	}
+	for _, dl := range s.DeadLetters {
+		stats.Add(dl.EventType, true, 1)
+	}
+	return stats, nil
+}
+
+// ListDeadLetters devuelve hasta 'limit' eventos apartados (todos si es negativo), del más
+// antiguo al más reciente, con su motivo.
+func (r *OutboxRepoFile) ListDeadLetters(ctx context.Context, limit int) ([]sharedDomain.DeadLetter, error) {
+	s, err := r.file.read()
+	if err != nil {
+		return nil, err
+	}
+	deadLetters := make([]sharedDomain.DeadLetter, 0, len(s.DeadLetters))
+	for _, dl := range s.DeadLetters {
+		deadLetters = append(deadLetters, sharedDomain.DeadLetter{OutboxEvent: dl.OutboxEvent, Reason: dl.Reason})
+	}
+	sort.SliceStable(deadLetters, func(i, j int) bool { return deadLetters[i].CreatedAt.Before(deadLetters[j].CreatedAt) })
+	if limit >= 0 && len(deadLetters) > limit {
+		deadLetters = deadLetters[:limit]
+	}
+	return deadLetters, nil
+}
+
+// RequeueDeadLetters devuelve a pendientes los eventos apartados 'ids' (todos si está vacío).
+func (r *OutboxRepoFile) RequeueDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	return r.removeDeadLetters(ids, func(s *fileState, dl deadLetter) {
+		s.Outbox = append(s.Outbox, dl.OutboxEvent)
+	})
+}
+
+// PurgeDeadLetters borra los eventos apartados 'ids' (todos si está vacío).
+func (r *OutboxRepoFile) PurgeDeadLetters(ctx context.Context, ids []uuid.UUID) (int, error) {
+	return r.removeDeadLetters(ids, func(*fileState, deadLetter) {})
+}
+
+// removeDeadLetters saca de los apartados los eventos 'ids' (todos si está vacío), pasa cada uno
+// a 'fn' y devuelve cuántos sacó.
+func (r *OutboxRepoFile) removeDeadLetters(ids []uuid.UUID, fn func(*fileState, deadLetter)) (int, error) {
+	removed := 0
+	err := r.file.update(func(s *fileState) error {
+		kept := s.DeadLetters[:0]
+		for _, dl := range s.DeadLetters {
+			if len(ids) > 0 && !slices.Contains(ids, dl.ID) {
+				kept = append(kept, dl)
+				continue
+			}
+			fn(s, dl)
+			removed++
+		}
+		s.DeadLetters = kept
+		return nil
+	})
+	return removed, err
+}
+
+// eventIndex devuelve la posición del evento 'id' en el outbox.
+func (s *fileState) eventIndex(id uuid.UUID) (int, error) {
+	i := slices.IndexFunc(s.Outbox, func(evt sharedDomain.OutboxEvent) bool { return evt.ID == id })
+	if i < 0 {
+		return -1, fmt.Errorf("outbox event not found: %s", id)
+	}
+	return i, nil
+}
+
+// Verificación estática
+var _ sharedDomain.OutboxRepository = (*OutboxRepoFile)(nil)
+var _ sharedDomain.OutboxBacklogCounter = (*OutboxRepoFile)(nil)
+var _ sharedDomain.OutboxDeadLetterer = (*OutboxRepoFile)(nil)
+var _ sharedDomain.OutboxStatsReader = (*OutboxRepoFile)(nil)
+var _ sharedDomain.OutboxDeadLetterStore = (*OutboxRepoFile)(nil)
@@ -0,0 +1,293 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskMemory "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/memory"
+)
+
+// TaskRepoFile implementa la interfaz TaskRepository sobre un único fichero JSON que guarda las
+// tareas y su outbox (ver OutboxRepoFile), para despliegues pequeños y pruebas sin base de datos.
+// Cada escritura reescribe el fichero completo de forma atómica (ver writeAtomic), así que una
+// tarea y su evento se guardan a la vez o ninguno, como en una transacción. Los criterios, el
+// orden y la paginación se evalúan en el proceso (ver sharedMemory.Query) y, como en SQL, cada
+// operación solo ve las tareas del inquilino de ctx.
+//
+// El fichero se relee en cada operación y solo un proceso debe escribir en él.
+type TaskRepoFile struct {
+	file   *stateFile
+	outbox *OutboxRepoFile
+}
+
+// NewTaskRepoFile es el constructor del repositorio. Con 'keys', el fichero se guarda cifrado
+// como el de NewEncryptedJSONTaskStorage; nil lo guarda en claro.
+func NewTaskRepoFile(path string, keys *encryption.Keyring) *TaskRepoFile {
+	file := &stateFile{path: path, keys: keys}
+	return &TaskRepoFile{file: file, outbox: &OutboxRepoFile{file: file}}
+}
+
+// Outbox devuelve el outbox que vive en el mismo fichero que las tareas.
+func (r *TaskRepoFile) Outbox() *OutboxRepoFile {
+	return r.outbox
+}
+
+// ------------------ CRUD + Outbox ------------------
+
+// Create guarda una tarea en el inquilino de ctx y su evento. Como en SQL, el ID es único entre
+// todos los inquilinos.
+func (r *TaskRepoFile) Create(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
+	return r.file.update(func(s *fileState) error {
+		if slices.ContainsFunc(s.Tasks, func(row taskRow) bool { return row.ID == t.ID }) {
+			return taskDomain.ErrTaskAlreadyExists
+		}
+		s.Tasks = append(s.Tasks, taskRow{Task: *t, Tenant: tracing.TenantID(ctx)})
+		s.addEvent(ctx, evt)
+		return nil
+	})
+}
+
+// Update actualiza una tarea, si la versión guardada es la anterior a la suya, y guarda su evento.
+func (r *TaskRepoFile) Update(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
+	return r.file.update(func(s *fileState) error {
+		i := s.indexOf(ctx, t.ID)
+		if i < 0 {
+			return taskDomain.ErrTaskNotFound
+		}
+		if s.Tasks[i].Version != t.Version-1 {
+			return taskDomain.ErrTaskVersionConflict
+		}
+		// Como en SQL, la fecha de creación no se modifica.
+		updated := *t
+		updated.CreatedAt = s.Tasks[i].CreatedAt
+		s.Tasks[i].Task = updated
+		s.addEvent(ctx, evt)
+		return nil
+	})
+}
+
+// UpdateStatus cambia el estado de las tareas que siguen en la versión anterior a la suya, con el
+// evento de cada una, en una sola reescritura del fichero.
+func (r *TaskRepoFile) UpdateStatus(ctx context.Context, tasks []*taskDomain.Task, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error) {
+	var updated []uuid.UUID
+	err := r.file.update(func(s *fileState) error {
+		for _, t := range tasks {
+			i := s.indexOf(ctx, t.ID)
+			if i < 0 || s.Tasks[i].Version != t.Version-1 {
+				continue
+			}
+			s.Tasks[i].Status = t.Status
+			s.Tasks[i].UpdatedAt = t.UpdatedAt
+			s.Tasks[i].Version = t.Version
+			for _, evt := range events {
+				if evt.AggregateID == t.ID.String() {
+					s.addEvent(ctx, evt)
+				}
+			}
+			updated = append(updated, t.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteByID elimina una tarea y guarda su evento.
+func (r *TaskRepoFile) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	return r.file.update(func(s *fileState) error {
+		i := s.indexOf(ctx, id)
+		if i < 0 {
+			return taskDomain.ErrTaskNotFound
+		}
+		s.Tasks = slices.Delete(s.Tasks, i, i+1)
+		s.addEvent(ctx, evt)
+		return nil
+	})
+}
+
+// ------------------ Lectura ------------------
+
+// GetByID recupera una tarea por su ID.
+func (r *TaskRepoFile) GetByID(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error) {
+	s, err := r.file.read()
+	if err != nil {
+		return nil, err
+	}
+	i := s.indexOf(ctx, id)
+	if i < 0 {
+		return nil, taskDomain.ErrTaskNotFound
+	}
+	return &s.Tasks[i].Task, nil
+}
+
+// GetByIDs recupera las tareas de 'ids' que existen.
+func (r *TaskRepoFile) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*taskDomain.Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	s, err := r.file.read()
+	if err != nil {
+		return nil, err
+	}
+	var tasks []*taskDomain.Task
+	for _, id := range ids {
+		if i := s.indexOf(ctx, id); i >= 0 {
+			tasks = append(tasks, &s.Tasks[i].Task)
+		}
+	}
+	return tasks, nil
+}
+
+// ListByCriteria recupera una lista de tareas aplicando filtros, paginación y ordenamiento.
+func (r *TaskRepoFile) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*taskDomain.Task, error) {
+	s, err := r.file.read()
+	if err != nil {
+		return nil, err
+	}
+	found, err := sharedMemory.Query(s.Tasks, rowField, sharedQuery.TenantScope(ctx, criteria), pagination, sorts)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*taskDomain.Task, len(found))
+	for i := range found {
+		tasks[i] = &found[i].Task
+	}
+	return tasks, nil
+}
+
+// CountByCriteria cuenta las tareas que cumplen 'criteria'.
+func (r *TaskRepoFile) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	s, err := r.file.read()
+	if err != nil {
+		return 0, err
+	}
+	return sharedMemory.Count(s.Tasks, rowField, sharedQuery.TenantScope(ctx, criteria))
+}
+
+// StreamByCriteria recorre las tareas que cumplen 'criteria' página a página (ver taskDomain.StreamByPages).
+func (r *TaskRepoFile) StreamByCriteria(ctx context.Context, criteria sharedDomain.Criteria, fn func(*taskDomain.Task) error) error {
+	return taskDomain.StreamByPages(ctx, r.ListByCriteria, criteria, fn)
+}
+
+// Preflight comprueba que el fichero se puede leer y, si está cifrado, descifrar con las claves
+// configuradas.
+func (r *TaskRepoFile) Preflight(ctx context.Context) error {
+	_, err := r.file.read()
+	return err
+}
+
+// ------------------ Fichero de estado ------------------
+
+// fileState es el contenido del fichero: las tareas, en orden de inserción para que los empates
+// al ordenar sean estables, y los eventos del outbox.
+type fileState struct {
+	Tasks       []taskRow                  `json:"tasks"`
+	Outbox      []sharedDomain.OutboxEvent `json:"outbox"`
+	DeadLetters []deadLetter               `json:"dead_letters,omitempty"`
+}
+
+// taskRow es una tarea guardada junto a su inquilino.
+type taskRow struct {
+	taskDomain.Task
+	Tenant string `json:"tenant_id,omitempty"`
+}
+
+// deadLetter es un evento apartado con MarkOutboxDeadLetter y su motivo.
+type deadLetter struct {
+	sharedDomain.OutboxEvent
+	Reason string `json:"dead_letter_reason"`
+}
+
+// indexOf devuelve la posición de la tarea 'id' del inquilino de ctx, o -1.
+func (s *fileState) indexOf(ctx context.Context, id uuid.UUID) int {
+	tenant := tracing.TenantID(ctx)
+	return slices.IndexFunc(s.Tasks, func(row taskRow) bool { return row.ID == id && row.Tenant == tenant })
+}
+
+// addEvent añade 'evt' al outbox. Sin metadatos propios, el evento lleva los de la traza de ctx,
+// como en los outbox SQL.
+func (s *fileState) addEvent(ctx context.Context, evt sharedDomain.OutboxEvent) {
+	if evt.Metadata == nil {
+		evt.Metadata = tracing.Metadata(ctx)
+	}
+	evt.Processed = false
+	s.Outbox = append(s.Outbox, evt)
+}
+
+// rowField expone los campos de la tarea (taskMemory.TaskField) y la columna del inquilino.
+func rowField(row taskRow, field string) (any, bool) {
+	if field == sharedQuery.TenantColumn {
+		return row.Tenant, true
+	}
+	return taskMemory.TaskField(row.Task, field)
+}
+
+// stateFile serializa el acceso al fichero que comparten TaskRepoFile y OutboxRepoFile.
+type stateFile struct {
+	path string
+	keys *encryption.Keyring
+	mu   sync.Mutex
+}
+
+// read lee el estado actual; un fichero que aún no existe es un estado vacío.
+func (f *stateFile) read() (*fileState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.load()
+}
+
+// update aplica 'change' al estado y, si no falla, reescribe el fichero. Si falla, el fichero
+// queda como estaba.
+func (f *stateFile) update(change func(s *fileState) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, err := f.load()
+	if err != nil {
+		return err
+	}
+	if err := change(s); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal task file: %w", err)
+	}
+	if err := writeFile(f.path, f.keys, data); err != nil {
+		return fmt.Errorf("failed to write task file %s: %w", f.path, err)
+	}
+	return nil
+}
+
+func (f *stateFile) load() (*fileState, error) {
+	data, err := readFile(f.path, f.keys)
+	if err != nil {
+		return nil, err
+	}
+	var s fileState
+	if len(data) == 0 {
+		return &s, nil
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse task file %s: %w", f.path, err)
+	}
+	return &s, nil
+}
+
+// Verificación estática de la interfaz.
+var _ taskDomain.TaskRepository = (*TaskRepoFile)(nil)
+var _ preflight.Checker = (*TaskRepoFile)(nil)
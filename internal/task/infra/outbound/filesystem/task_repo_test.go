@@ -0,0 +1,79 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+func TestTaskRepoFile_SurvivesRestart(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	keys := newTestKeyring(t, 'a')
+	repo := NewTaskRepoFile(path, keys)
+	task := &taskDomain.Task{ID: uuid.New(), Title: "Revisar contrato confidencial", Status: taskDomain.TaskPending}
+	evt := sharedDomain.OutboxEvent{ID: uuid.New(), AggregateType: "task", AggregateID: task.ID.String(), EventType: "task.created", Payload: task}
+	require.NoError(t, repo.Create(ctx, task, evt))
+
+	// Act: otro proceso abre el mismo fichero
+	reopened := NewTaskRepoFile(path, keys)
+	got, err := reopened.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	pending, err := reopened.Outbox().FetchPendingOutbox(ctx, 10)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Outbox().MarkOutboxDeadLetter(ctx, evt.ID, "schema mismatch"))
+	remaining, err := repo.Outbox().CountPendingOutbox(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, task.Title, got.Title)
+	require.Len(t, pending, 1)
+	assert.Equal(t, evt.ID, pending[0].ID)
+	assert.Zero(t, remaining)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "confidencial")
+	assert.NoError(t, reopened.Preflight(ctx))
+	assert.ErrorContains(t, NewTaskRepoFile(path, nil).Preflight(ctx), "no encryption key is configured")
+}
+
+func TestOutboxRepoFile_DeadLetters(t *testing.T) {
+	// Arrange: un evento apartado
+	ctx := context.Background()
+	repo := NewTaskRepoFile(filepath.Join(t.TempDir(), "tasks.json"), newTestKeyring(t, 'a'))
+	task := &taskDomain.Task{ID: uuid.New(), Title: "Preparar la demo", Status: taskDomain.TaskPending}
+	evt := sharedDomain.OutboxEvent{ID: uuid.New(), AggregateType: "task", AggregateID: task.ID.String(), EventType: "task.created", Payload: task}
+	require.NoError(t, repo.Create(ctx, task, evt))
+	require.NoError(t, repo.Outbox().MarkOutboxDeadLetter(ctx, evt.ID, "schema mismatch"))
+	outbox := repo.Outbox()
+
+	// Act
+	deadLetters, err := outbox.ListDeadLetters(ctx, -1)
+	require.NoError(t, err)
+	stats, err := outbox.OutboxStats(ctx)
+	require.NoError(t, err)
+	requeued, err := outbox.RequeueDeadLetters(ctx, nil)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, evt.ID, deadLetters[0].ID)
+	assert.Equal(t, "schema mismatch", deadLetters[0].Reason)
+	assert.Equal(t, map[string]int{"task.created": 1}, stats.DeadLetters)
+	assert.Equal(t, 1, requeued)
+	pending, err := outbox.CountPendingOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+	purged, err := outbox.PurgeDeadLetters(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+}
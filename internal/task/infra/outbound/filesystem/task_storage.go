@@ -4,9 +4,12 @@ package filesystem
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"sync"
 
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
 	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
 	"github.com/google/uuid"
 )
@@ -14,7 +17,8 @@ import (
 // JSONTaskStorage es un adaptador outbound que guarda las tareas en un fichero JSON.
 type JSONTaskStorage struct {
 	filePath string
-	mu       sync.Mutex // Mutex para evitar race conditions al leer/escribir el archivo.
+	keys     *encryption.Keyring // nil guarda el JSON en claro
+	mu       sync.Mutex          // Mutex para evitar race conditions al leer/escribir el archivo.
 }
 
 // NewJSONTaskStorage es el constructor.
@@ -24,6 +28,17 @@ func NewJSONTaskStorage(filePath string) *JSONTaskStorage {
 	}
 }
 
+// NewEncryptedJSONTaskStorage guarda el fichero cifrado con AES-256-GCM con la clave activa de
+// 'keys' (encryption.keys). Lee tanto los ficheros cifrados con cualquier clave del anillo como
+// los escritos en claro antes de activar el cifrado, que se cifran en la siguiente escritura; del
+// mismo modo, tras rotar la clave, la siguiente escritura vuelve a cifrar con la nueva.
+func NewEncryptedJSONTaskStorage(filePath string, keys *encryption.Keyring) *JSONTaskStorage {
+	return &JSONTaskStorage{
+		filePath: filePath,
+		keys:     keys,
+	}
+}
+
 // Save añade una nueva tarea al fichero JSON.
 // Si el fichero no existe, lo crea.
 func (s *JSONTaskStorage) Save(ctx context.Context, task *taskDomain.Task) error {
@@ -46,8 +61,9 @@ func (s *JSONTaskStorage) Save(ctx context.Context, task *taskDomain.Task) error
 		return err
 	}
 
-	// 4. Escribir (sobrescribiendo) el fichero completo.
-	return os.WriteFile(s.filePath, data, 0644)
+	// 4. Cifrar si procede y sustituir el fichero completo de forma atómica: una caída a mitad de
+	// la escritura no puede dejarlo corrupto.
+	return writeFile(s.filePath, s.keys, data)
 }
 
 // GetAll recupera todas las tareas del fichero JSON.
@@ -79,16 +95,12 @@ func (s *JSONTaskStorage) GetTaskByID(ctx context.Context, id uuid.UUID) (*taskD
 
 // getAllTasksFromFile es un helper interno no concurrente.
 func (s *JSONTaskStorage) getAllTasksFromFile() ([]*taskDomain.Task, error) {
-	data, err := os.ReadFile(s.filePath)
+	data, err := readFile(s.filePath, s.keys)
 	if err != nil {
-		// Si el fichero no existe, devolvemos una lista vacía sin error.
-		if os.IsNotExist(err) {
-			return []*taskDomain.Task{}, nil
-		}
 		return nil, err
 	}
 
-	// Si el fichero está vacío, también devolvemos una lista vacía.
+	// Si el fichero no existe o está vacío, devolvemos una lista vacía sin error.
 	if len(data) == 0 {
 		return []*taskDomain.Task{}, nil
 	}
@@ -100,3 +112,38 @@ func (s *JSONTaskStorage) getAllTasksFromFile() ([]*taskDomain.Task, error) {
 
 	return tasks, nil
 }
+
+// readFile lee el fichero 'path', descifrándolo si está cifrado; uno en claro se lee tal cual (ver
+// NewEncryptedJSONTaskStorage). Si el fichero no existe devuelve nil sin error.
+func readFile(path string, keys *encryption.Keyring) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !encryption.IsEncrypted(string(data)) {
+		return data, nil
+	}
+	if keys == nil {
+		return nil, fmt.Errorf("task file %s is encrypted and no encryption key is configured", path)
+	}
+	plaintext, err := keys.Decrypt(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt task file %s: %w", path, err)
+	}
+	return []byte(plaintext), nil
+}
+
+// writeFile sustituye el fichero 'path' por 'data' de forma atómica (ver writeAtomic), cifrado
+// con la clave activa de 'keys' si no es nil.
+func writeFile(path string, keys *encryption.Keyring, data []byte) error {
+	if keys != nil {
+		data = []byte(keys.Encrypt(string(data)))
+	}
+	return writeAtomic(path, 0644, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
@@ -0,0 +1,90 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+)
+
+// newTestKeyring devuelve un anillo con la clave "k1" de 32 bytes iguales a 'b'.
+func newTestKeyring(t *testing.T, b byte) *encryption.Keyring {
+	keys, err := encryption.NewKeyring([]string{"k1:" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))})
+	require.NoError(t, err)
+	return keys
+}
+
+func TestJSONTaskStorage_Encrypted(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	storage := NewEncryptedJSONTaskStorage(path, newTestKeyring(t, 'a'))
+	task := &taskDomain.Task{ID: uuid.New(), Title: "Revisar contrato confidencial"}
+
+	// Act
+	require.NoError(t, storage.Save(ctx, task))
+	got, err := storage.GetTaskByID(ctx, task.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, task.Title, got.Title)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(string(raw)))
+	assert.NotContains(t, string(raw), "confidencial")
+
+	_, err = NewJSONTaskStorage(path).GetAll(ctx)
+	assert.ErrorContains(t, err, "no encryption key is configured")
+	_, err = NewEncryptedJSONTaskStorage(path, newTestKeyring(t, 'z')).GetAll(ctx)
+	assert.ErrorIs(t, err, encryption.ErrMalformed, "Una clave distinta con el mismo id no lo abre")
+}
+
+func TestJSONTaskStorage_EncryptsPlainFileOnNextWrite(t *testing.T) {
+	// Arrange: un fichero escrito en claro antes de activar el cifrado
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	legacy := &taskDomain.Task{ID: uuid.New(), Title: "Tarea antigua"}
+	require.NoError(t, NewJSONTaskStorage(path).Save(ctx, legacy))
+	storage := NewEncryptedJSONTaskStorage(path, newTestKeyring(t, 'a'))
+
+	// Act
+	before, err := storage.GetAll(ctx)
+	require.NoError(t, err)
+	require.NoError(t, storage.Save(ctx, &taskDomain.Task{ID: uuid.New(), Title: "Tarea nueva"}))
+	after, err := storage.GetAll(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, before, 1)
+	assert.Len(t, after, 2)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(string(raw)))
+}
+
+func TestJSONTaskStorage_Save_LeavesNoTempFiles(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage := NewJSONTaskStorage(filepath.Join(dir, "tasks.json"))
+
+	// Act
+	for i := 0; i < 3; i++ {
+		require.NoError(t, storage.Save(ctx, &taskDomain.Task{ID: uuid.New(), Title: "Tarea"}))
+	}
+
+	// Assert
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "tasks.json", entries[0].Name())
+}
@@ -3,13 +3,21 @@ package application
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	sharedBackground "github.com/davicafu/hexagolab/internal/shared/infra/platform/background"
 	sharedCache "github.com/davicafu/hexagolab/internal/shared/infra/platform/cache"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/payload"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/quota"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	sharedUtils "github.com/davicafu/hexagolab/internal/shared/infra/utils"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	"github.com/davicafu/hexagolab/pkg/logger"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -18,81 +26,285 @@ import (
 type UserService struct {
 	repo  userDomain.UserRepository
 	cache sharedCache.Cache
+	async *sharedBackground.Pool
+	clock sharedDomain.Clock
+	ids   sharedDomain.IDGenerator
 	log   *zap.Logger
+
+	// compression comprime los payloads grandes del outbox (ver SetPayloadCompression).
+	compression atomic.Pointer[compression.Options]
+	// payloads es la forma de los payloads del outbox por tipo de evento (ver SetPayloadModes).
+	payloads atomic.Pointer[payload.Modes]
+	// quotas limita las altas de cada usuario (ver SetQuotas).
+	quotas *quota.Quotas
 }
 
-// NewUserService constructor
-func NewUserService(repo userDomain.UserRepository, cache sharedCache.Cache, log *zap.Logger) *UserService {
+// NewUserService constructor. 'async' ejecuta las escrituras en caché en segundo plano (nil las
+// hace en el momento), 'clock' da la fecha de alta de los usuarios y de sus eventos, e 'ids' sus
+// identificadores.
+func NewUserService(repo userDomain.UserRepository, cache sharedCache.Cache, async *sharedBackground.Pool, clock sharedDomain.Clock, ids sharedDomain.IDGenerator, log *zap.Logger) *UserService {
 	return &UserService{
 		repo:  repo,
 		cache: cache,
-		log:   log,
+		async: async,
+		clock: clock,
+		ids:   ids,
+		log:   log.Named("user"),
+	}
+}
+
+// SetPayloadCompression comprime a partir de ahora los payloads del outbox que superen el
+// umbral de 'opts'. Se puede cambiar en caliente.
+func (s *UserService) SetPayloadCompression(opts compression.Options) {
+	s.compression.Store(&opts)
+}
+
+// SetPayloadModes da a partir de ahora a los payloads de user.created y user.updated la forma
+// que 'modes' configura para su tipo. Se puede cambiar en caliente.
+func (s *UserService) SetPayloadModes(modes payload.Modes) {
+	s.payloads.Store(&modes)
+}
+
+// SetQuotas aplica 'quotas' al alta de usuarios: pasada la cuota, el alta devuelve un
+// *quota.ExceededError. Se llama antes de servir peticiones.
+func (s *UserService) SetQuotas(quotas *quota.Quotas) {
+	s.quotas = quotas
+}
+
+// userPayloadKeys son las claves del id y la versión en el JSON de un usuario.
+var userPayloadKeys = payload.Keys{ID: "id", Version: "version"}
+
+// payloadMode devuelve la forma configurada para los payloads de 'eventType'.
+func (s *UserService) payloadMode(eventType string) sharedEvents.PayloadMode {
+	modes := s.payloads.Load()
+	if modes == nil {
+		return sharedEvents.PayloadSnapshot
 	}
+	return modes.Mode(eventType)
+}
+
+// shape da al payload del evento, el usuario tras el cambio, la forma configurada para su tipo.
+// 'before' es el usuario antes del cambio, que necesitan los deltas; nil en las altas.
+func (s *UserService) shape(ctx context.Context, evt sharedDomain.OutboxEvent, before *userDomain.User) (sharedDomain.OutboxEvent, error) {
+	modes := s.payloads.Load()
+	if modes == nil {
+		return evt, nil
+	}
+	var previous any
+	if before != nil {
+		previous = userDomain.NewUserPayload(before)
+	}
+	return modes.Shape(ctx, evt, previous, userPayloadKeys)
+}
+
+// compress aplica al evento la compresión configurada, si la hay.
+func (s *UserService) compress(ctx context.Context, evt sharedDomain.OutboxEvent) (sharedDomain.OutboxEvent, error) {
+	opts := s.compression.Load()
+	if opts == nil {
+		return evt, nil
+	}
+	return opts.CompressOutbox(ctx, evt)
 }
 
 func (s *UserService) CreateUser(ctx context.Context, email, nombre string, birthDate time.Time) (*userDomain.User, error) {
 	user := &userDomain.User{
-		ID:        uuid.New(),
+		ID:        s.ids.NewID(),
 		Email:     email,
-		Nombre:    nombre,
+		Name:      nombre,
 		BirthDate: birthDate,
-		CreatedAt: time.Now().UTC(),
+		CreatedAt: s.clock.Now().UTC(),
+		Version:   1,
 	}
 
 	outboxEvent := sharedDomain.OutboxEvent{
-		ID:            uuid.New(),
+		ID:            s.ids.NewID(),
 		AggregateType: "user",
 		AggregateID:   user.ID.String(),
 		EventType:     userDomain.UserCreated,
-		Payload:       user,
-		CreatedAt:     time.Now().UTC(),
+		Payload:       userDomain.NewUserPayload(user),
+		CreatedAt:     s.clock.Now().UTC(),
 		Processed:     false,
 	}
 
+	outboxEvent, err := s.shape(ctx, outboxEvent, nil)
+	if err != nil {
+		return nil, err
+	}
+	outboxEvent, err = s.compress(ctx, outboxEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	// La cuota se descuenta justo antes del alta y se devuelve si el alta falla (ej. un email repetido).
+	release, err := s.quotas.Consume(ctx, quota.ActionCreateUser)
+	if err != nil {
+		return nil, err
+	}
 	if err := s.repo.Create(ctx, user, outboxEvent); err != nil {
+		release(ctx)
 		return nil, err
 	}
 
-	sharedCache.AsyncCacheSet(ctx, s.cache, userDomain.UserCacheKeyByID(user.ID), user, 60, s.log)
+	sharedCache.AsyncCacheSet(ctx, s.async, s.cache, sharedCache.TenantKey(ctx, userDomain.UserCacheKeyByID(user.ID)), user, sharedCache.DefaultTTL, s.log)
 
 	return user, nil
 }
 
+// GetUsers devuelve los usuarios de 'ids' en el orden pedido, sin los que no existen y sin
+// repetidos. Lee la caché con una sola operación y pide al repositorio solo los que faltan, que
+// se guardan en caché en segundo plano. Más de sharedQuery.MaxIDs ids es un sharedQuery.ErrInvalidQuery.
+func (s *UserService) GetUsers(ctx context.Context, ids []uuid.UUID) ([]*userDomain.User, error) {
+	ids, err := sharedQuery.UniqueIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]*userDomain.User, len(ids))
+
+	// 1. Intentar cache
+	missing := ids
+	if s.cache != nil && len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = sharedCache.TenantKey(ctx, userDomain.UserCacheKeyByID(id))
+		}
+		cached, err := sharedCache.GetMany[userDomain.User](ctx, s.cache, keys)
+		if err != nil {
+			logger.FromContext(ctx, s.log).Warn("Cache multi-get failed", zap.Error(err))
+		} else {
+			missing = make([]uuid.UUID, 0, len(ids)-len(cached))
+			for i, id := range ids {
+				if u, ok := cached[keys[i]]; ok {
+					found[id] = &u
+				} else {
+					missing = append(missing, id)
+				}
+			}
+		}
+	}
+
+	// 2. Ir al repo, en una sola consulta, por los que faltan
+	if len(missing) > 0 {
+		var users []*userDomain.User
+		err := sharedUtils.Retry(ctx, 3, 100*time.Millisecond, func() error {
+			var err error
+			users, err = s.repo.GetByIDs(ctx, missing)
+			return err
+		})
+		if err != nil {
+			logger.FromContext(ctx, s.log).Error("Failed to fetch users", zap.Int("count", len(missing)), zap.Error(err))
+			return nil, err
+		}
+
+		// 3. Actualizar cache en background sin bloquear la respuesta
+		items := make(map[string]interface{}, len(users))
+		for _, u := range users {
+			found[u.ID] = u
+			items[sharedCache.TenantKey(ctx, userDomain.UserCacheKeyByID(u.ID))] = u
+		}
+		sharedCache.AsyncCacheSetMulti(ctx, s.async, s.cache, items, sharedCache.DefaultTTL, s.log)
+	}
+
+	users := make([]*userDomain.User, 0, len(found))
+	for _, id := range ids {
+		if u, ok := found[id]; ok {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// updateAttempts son las veces que UpdateUser intenta escribir un usuario que otra escritura
+// cambia a la vez.
+const updateAttempts = 3
+
+// UpdateUser actualiza un usuario, crea un evento y actualiza la caché. Gana la última escritura:
+// si el usuario ha cambiado desde que se leyó, se escribe sobre la versión guardada. Para no
+// pisar cambios ajenos, ver CompareAndUpdateUser.
 func (s *UserService) UpdateUser(ctx context.Context, u *userDomain.User) error {
+	for attempt := 1; ; attempt++ {
+		err := s.CompareAndUpdateUser(ctx, u, u.Version)
+		if !errors.Is(err, userDomain.ErrUserVersionConflict) || attempt == updateAttempts {
+			return err
+		}
+		// La versión leída (quizá de la caché) ya no es la guardada: se lee del repositorio.
+		current, err := s.repo.GetByID(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+		u.Version = current.Version
+	}
+}
+
+// CompareAndUpdateUser actualiza el usuario como UpdateUser solo si su versión guardada sigue
+// siendo 'version' (la que leyó el cliente); si no, devuelve userDomain.ErrUserVersionConflict y
+// el usuario queda como estaba. Si se actualiza, u.Version pasa a ser la nueva versión. Con los
+// payloads de user.updated en modo delta, lee el usuario guardado para calcular qué cambia.
+func (s *UserService) CompareAndUpdateUser(ctx context.Context, u *userDomain.User, version int64) error {
+	var before *userDomain.User
+	if s.payloadMode(userDomain.UserUpdated) == sharedEvents.PayloadDelta {
+		current, err := s.repo.GetByID(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+		if current.Version != version {
+			return userDomain.ErrUserVersionConflict
+		}
+		before = current
+	}
+
+	u.Version = version + 1
 	evt := sharedDomain.OutboxEvent{
-		ID:            uuid.New(),
+		ID:            s.ids.NewID(),
 		AggregateType: "user",
 		AggregateID:   u.ID.String(),
 		EventType:     userDomain.UserUpdated,
-		Payload:       u,
-		CreatedAt:     time.Now().UTC(),
+		Payload:       userDomain.NewUserPayload(u),
+		CreatedAt:     s.clock.Now().UTC(),
+	}
+
+	evt, err := s.shape(ctx, evt, before)
+	if err == nil {
+		evt, err = s.compress(ctx, evt)
+	}
+	if err != nil {
+		u.Version = version
+		return err
 	}
 
 	if err := s.repo.Update(ctx, u, evt); err != nil {
+		u.Version = version
 		return err
 	}
 
-	sharedCache.AsyncCacheSet(ctx, s.cache, userDomain.UserCacheKeyByID(u.ID), u, 60, s.log)
+	sharedCache.AsyncCacheSet(ctx, s.async, s.cache, sharedCache.TenantKey(ctx, userDomain.UserCacheKeyByID(u.ID)), u, sharedCache.DefaultTTL, s.log)
 
 	return nil
 }
 
 func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	evt := sharedDomain.OutboxEvent{
-		ID:            uuid.New(),
+		ID:            s.ids.NewID(),
 		AggregateType: "user",
 		AggregateID:   id.String(),
 		EventType:     userDomain.UserDeleted,
-		Payload:       id,
-		CreatedAt:     time.Now().UTC(),
+		Payload:       map[string]interface{}{"id": id.String()},
+		CreatedAt:     s.clock.Now().UTC(),
 		Processed:     false,
+		Metadata:      tracing.Metadata(ctx),
+	}
+	// Borra el agregado: en los topics compactados irá seguido de un tombstone.
+	evt.Metadata[sharedDomain.MetadataTombstone] = "true"
+
+	evt, err := s.compress(ctx, evt)
+	if err != nil {
+		return err
 	}
 
 	if err := s.repo.DeleteByID(ctx, id, evt); err != nil {
 		return err
 	}
 
-	sharedCache.AsyncCacheDelete(ctx, s.cache, userDomain.UserCacheKeyByID(id), s.log)
+	sharedCache.AsyncCacheDelete(ctx, s.async, s.cache, sharedCache.TenantKey(ctx, userDomain.UserCacheKeyByID(id)), s.log)
 
 	return nil
 }
@@ -102,7 +314,7 @@ func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*userDomain.Us
 	// 1. Intentar cache
 	if s.cache != nil {
 		var u userDomain.User
-		if ok, _ := s.cache.Get(ctx, userDomain.UserCacheKeyByID(id), &u); ok {
+		if ok, _ := s.cache.Get(ctx, sharedCache.TenantKey(ctx, userDomain.UserCacheKeyByID(id)), &u); ok {
 			return &u, nil
 		}
 	}
@@ -116,42 +328,107 @@ func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*userDomain.Us
 	})
 	if err != nil {
 		if errors.Is(err, userDomain.ErrUserNotFound) {
-			s.log.Warn("User not found", zap.String("user_id", id.String()))
+			logger.FromContext(ctx, s.log).Warn("User not found", zap.String("user_id", id.String()))
 		} else {
-			s.log.Error("Failed to fetch user", zap.String("user_id", id.String()), zap.Error(err))
+			logger.FromContext(ctx, s.log).Error("Failed to fetch user", zap.String("user_id", id.String()), zap.Error(err))
 		}
 		return nil, err
 	}
 
 	// 3. Actualizar cache en background sin bloquear la respuesta
-	if s.cache != nil {
-		go func(u *userDomain.User) {
-			ctxCache, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-			defer cancel()
-			if err := s.cache.Set(ctxCache, userDomain.UserCacheKeyByID(u.ID), u, 60); err != nil {
-				s.log.Warn("⚠️ Cache update failed",
-					zap.String("user_id", u.ID.String()),
-					zap.Error(err),
-				)
-			}
-		}(user)
-	}
+	sharedCache.AsyncCacheSet(ctx, s.async, s.cache, sharedCache.TenantKey(ctx, userDomain.UserCacheKeyByID(user.ID)), user, sharedCache.DefaultTTL, s.log)
 
 	return user, nil
 }
 
-// ListUsers devuelve todos los usuarios aplicando filtros.
-func (s *UserService) ListUsers(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sort sharedQuery.Sort) ([]*userDomain.User, error) {
-	return s.repo.ListByCriteria(ctx, criteria, pagination, sort)
+// GetUserForUpdate lee el usuario del repositorio, sin pasar por la caché, para modificarlo y
+// guardarlo con UpdateUser o CompareAndUpdateUser: una copia antigua de la caché pisaría con sus
+// valores los cambios guardados después, aunque la versión de If-Match sea la actual.
+func (s *UserService) GetUserForUpdate(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListUsers devuelve todos los usuarios aplicando filtros. Un campo o un operador que
+// userDomain.UserFields no declara es un sharedDomain.ErrInvalidCriteria.
+func (s *UserService) ListUsers(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*userDomain.User, error) {
+	if err := sharedQuery.Validate(userDomain.UserFields, criteria, sorts); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByCriteria(ctx, criteria, pagination, sorts)
+}
+
+// ListUsersPage devuelve una página de usuarios con los metadatos para pedir la siguiente.
+func (s *UserService) ListUsersPage(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) (sharedQuery.PagedResult[*userDomain.User], error) {
+	if err := sharedQuery.Validate(userDomain.UserFields, criteria, sorts); err != nil {
+		return sharedQuery.PagedResult[*userDomain.User]{}, err
+	}
+	return sharedQuery.ListPage(pagination,
+		func(p sharedQuery.Pagination) ([]*userDomain.User, error) {
+			return s.repo.ListByCriteria(ctx, criteria, p, sorts)
+		},
+		func() (int, error) { return s.repo.CountByCriteria(ctx, criteria) },
+		func(u *userDomain.User) sharedQuery.Cursor {
+			return sharedQuery.NewCursorFor(sorts, u.ID, func(field string) any { return userSortValue(u, field) })
+		},
+	)
+}
+
+// userStreamSorts es el orden de StreamUsers: por fecha de alta y, a igualdad, por id.
+var userStreamSorts = []sharedQuery.Sort{{Field: "created_at"}}
+
+// StreamUsers recorre todos los usuarios que cumplen 'criteria', por fecha de alta, página a
+// página (ver sharedQuery.Stream), sin cargarlos en memoria. Los filtros se validan antes de la
+// primera consulta, así que un ErrInvalidCriteria llega sin haber llamado nunca a 'fn'.
+func (s *UserService) StreamUsers(ctx context.Context, criteria sharedDomain.Criteria, fn func(*userDomain.User) error) error {
+	if err := sharedQuery.Validate(userDomain.UserFields, criteria, userStreamSorts); err != nil {
+		return err
+	}
+	return sharedQuery.Stream(ctx, sharedQuery.StreamPageSize,
+		func(p sharedQuery.CursorPagination) ([]*userDomain.User, error) {
+			return s.repo.ListByCriteria(ctx, criteria, p, userStreamSorts)
+		},
+		func(u *userDomain.User) sharedQuery.Cursor { return sharedQuery.NewCursor(u.ID, u.CreatedAt) },
+		fn,
+	)
+}
+
+// userWarmupSorts es el orden en que WarmCache precarga los usuarios: los usuarios no guardan su
+// última actividad, así que van primero las altas más recientes.
+var userWarmupSorts = []sharedQuery.Sort{{Field: "created_at", Desc: true}}
+
+// WarmCache precarga en caché los 'limit' usuarios dados de alta más recientemente (ver
+// sharedCache.Warm), con las claves del inquilino de ctx. Devuelve cuántos se han guardado.
+func (s *UserService) WarmCache(ctx context.Context, limit int) (int, error) {
+	return sharedCache.Warm(ctx, s.cache, limit,
+		func(offset, limit int) ([]*userDomain.User, error) {
+			return s.repo.ListByCriteria(ctx, nil, sharedQuery.OffsetPagination{Limit: limit, Offset: offset}, userWarmupSorts)
+		},
+		func(u *userDomain.User) string { return sharedCache.TenantKey(ctx, userDomain.UserCacheKeyByID(u.ID)) },
+	)
+}
+
+// userSortValue devuelve el valor del campo de ordenación 'field' de 'u', para el cursor.
+func userSortValue(u *userDomain.User, field string) any {
+	switch field {
+	case "id":
+		return u.ID
+	case "email":
+		return u.Email
+	case "name":
+		return u.Name
+	case "birth_date":
+		return u.BirthDate
+	}
+	return u.CreatedAt
 }
 
-func (s *UserService) ListAdultUsers(ctx context.Context, pagination sharedQuery.Pagination, sort sharedQuery.Sort) ([]*userDomain.User, error) {
+func (s *UserService) ListAdultUsers(ctx context.Context, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*userDomain.User, error) {
 	minAge := 18
 	criteria := sharedDomain.CompositeCriteria{
 		Operator: sharedDomain.OpAnd,
 		Criterias: []sharedDomain.Criteria{
-			userDomain.AgeRangeCriteria{Min: &minAge},
+			userDomain.AgeRangeCriteria{Min: &minAge, Now: s.clock.Now()},
 		},
 	}
-	return s.repo.ListByCriteria(ctx, criteria, pagination, sort)
+	return s.repo.ListByCriteria(ctx, criteria, pagination, sorts)
 }
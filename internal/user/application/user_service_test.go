@@ -2,7 +2,6 @@ package application
 
 import (
 	"context"
-	"fmt"
 	"testing"
 	"time"
 
@@ -18,13 +17,13 @@ import (
 func TestCreateUser_Success(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
 	cache := mocks.NewDummyCache()
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	user, err := service.CreateUser(context.Background(), "test@example.com", "Pepe", time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC))
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
 	assert.Equal(t, "test@example.com", user.Email)
-	assert.Equal(t, "Pepe", user.Nombre)
+	assert.Equal(t, "Pepe", user.Name)
 
 	// ✅ Verificar que se creó un evento Outbox
 	assert.Len(t, repo.Outbox, 1)
@@ -32,10 +31,27 @@ func TestCreateUser_Success(t *testing.T) {
 	assert.Equal(t, user.ID.String(), repo.Outbox[0].AggregateID)
 }
 
+func TestCreateUser_UsesIDGenerator(t *testing.T) {
+	// Arrange
+	userID, eventID := uuid.New(), uuid.New()
+	repo := mocks.NewInMemoryUserRepo()
+	service := NewUserService(repo, mocks.NewDummyCache(), nil, sharedDomain.SystemClock{}, mocks.NewFixedIDGenerator(userID, eventID), zap.NewNop())
+
+	// Act
+	user, err := service.CreateUser(context.Background(), "test@example.com", "Pepe", time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, userID, user.ID)
+	assert.Len(t, repo.Outbox, 1)
+	assert.Equal(t, eventID, repo.Outbox[0].ID)
+	assert.Equal(t, userID.String(), repo.Outbox[0].AggregateID)
+}
+
 func TestCreateUser_AlreadyExists(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
 	cache := mocks.NewDummyCache()
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	user, _ := service.CreateUser(context.Background(), "dup@example.com", "Juan", time.Now())
 	// Intentar crear de nuevo con el mismo ID usando repo directamente
@@ -53,7 +69,7 @@ func TestCreateUser_AlreadyExists(t *testing.T) {
 func TestGetUser_NotFound(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
 	cache := mocks.NewDummyCache()
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	_, err := service.GetUser(context.Background(), uuid.New())
 	assert.ErrorIs(t, err, userDomain.ErrUserNotFound)
@@ -62,17 +78,17 @@ func TestGetUser_NotFound(t *testing.T) {
 func TestUpdateUser_Success(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
 	cache := mocks.NewDummyCache()
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	user, _ := service.CreateUser(context.Background(), "update@example.com", "Ana", time.Now())
-	user.Nombre = "Ana Actualizada"
+	user.Name = "Ana Actualizada"
 
 	err := service.UpdateUser(context.Background(), user)
 	assert.NoError(t, err)
 
 	// Comprobar que se actualizó en el repo
 	u2, _ := repo.GetByID(context.Background(), user.ID)
-	assert.Equal(t, "Ana Actualizada", u2.Nombre)
+	assert.Equal(t, "Ana Actualizada", u2.Name)
 
 	// ✅ Verificar que se creó un evento Outbox adicional
 	assert.Len(t, repo.Outbox, 2)
@@ -83,7 +99,7 @@ func TestUpdateUser_Success(t *testing.T) {
 func TestDeleteUser_Success(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
 	cache := mocks.NewDummyCache()
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	user, _ := service.CreateUser(context.Background(), "delete@example.com", "Borrar", time.Now())
 
@@ -104,36 +120,36 @@ func TestDeleteUser_Success(t *testing.T) {
 func TestGetUser_CacheHit(t *testing.T) {
 	id := uuid.New()
 	user := &userDomain.User{
-		ID:     id,
-		Email:  "cache@example.com",
-		Nombre: "CacheUser",
+		ID:    id,
+		Email: "cache@example.com",
+		Name:  "CacheUser",
 	}
 
 	cache := mocks.NewDummyCache()
 	cache.Set(context.Background(), userDomain.UserCacheKeyByID(id), user, 60)
 
 	repo := mocks.NewInMemoryUserRepo()
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	u, err := service.GetUser(context.Background(), id)
 	assert.NoError(t, err)
 	assert.NotNil(t, u)
-	assert.Equal(t, "CacheUser", u.Nombre)
+	assert.Equal(t, "CacheUser", u.Name)
 }
 
 func TestGetUser_CacheMiss(t *testing.T) {
 	id := uuid.New()
 	user := &userDomain.User{
-		ID:     id,
-		Email:  "miss@example.com",
-		Nombre: "MissUser",
+		ID:    id,
+		Email: "miss@example.com",
+		Name:  "MissUser",
 	}
 
 	repo := mocks.NewInMemoryUserRepo()
 	repo.Create(context.Background(), user, sharedDomain.OutboxEvent{})
 	cache := mocks.NewDummyCache() // cache vacía
 
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	u, err := service.GetUser(context.Background(), id)
 	assert.NoError(t, err)
@@ -147,14 +163,54 @@ func TestGetUser_CacheMiss(t *testing.T) {
 	}, 1*time.Second, 10*time.Millisecond, "La caché de usuario debería haberse populado tras el 'miss'")
 }
 
+func TestGetUsers_MixesCacheAndRepo(t *testing.T) {
+	// Arrange: uno en caché, otro solo en el repositorio y un tercero que no existe.
+	cached := &userDomain.User{ID: uuid.New(), Email: "cached@example.com", Name: "CachedUser"}
+	stored := &userDomain.User{ID: uuid.New(), Email: "stored@example.com", Name: "StoredUser"}
+
+	cache := mocks.NewDummyCache()
+	cache.Set(context.Background(), userDomain.UserCacheKeyByID(cached.ID), cached, 60)
+	repo := mocks.NewInMemoryUserRepo()
+	repo.Create(context.Background(), stored, sharedDomain.OutboxEvent{})
+
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+
+	// Act
+	users, err := service.GetUsers(context.Background(), []uuid.UUID{stored.ID, uuid.New(), cached.ID, stored.ID})
+
+	// Assert: en el orden pedido, sin el que falta ni repetidos, y el del repositorio queda en caché.
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, "StoredUser", users[0].Name)
+		assert.Equal(t, "CachedUser", users[1].Name)
+	}
+	assert.Eventually(t, func() bool {
+		var cachedUser userDomain.User
+		hit, _ := cache.Get(context.Background(), userDomain.UserCacheKeyByID(stored.ID), &cachedUser)
+		return hit
+	}, 1*time.Second, 10*time.Millisecond)
+}
+
+func TestGetUsers_TooManyIDs(t *testing.T) {
+	service := NewUserService(mocks.NewInMemoryUserRepo(), nil, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+	ids := make([]uuid.UUID, sharedQuery.MaxIDs+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	_, err := service.GetUsers(context.Background(), ids)
+
+	assert.ErrorIs(t, err, sharedQuery.ErrInvalidQuery)
+}
+
 // ----------------- ListUsers / Search / Filter -----------------
 
 func TestListUsersByName(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
-	_ = repo.Create(context.Background(), &userDomain.User{ID: uuid.New(), Nombre: "Ana"}, sharedDomain.OutboxEvent{})
-	_ = repo.Create(context.Background(), &userDomain.User{ID: uuid.New(), Nombre: "Juan"}, sharedDomain.OutboxEvent{})
+	_ = repo.Create(context.Background(), &userDomain.User{ID: uuid.New(), Name: "Ana", Email: "ana@example.com"}, sharedDomain.OutboxEvent{})
+	_ = repo.Create(context.Background(), &userDomain.User{ID: uuid.New(), Name: "Juan", Email: "juan@example.com"}, sharedDomain.OutboxEvent{})
 
-	service := NewUserService(repo, nil, zap.NewNop())
+	service := NewUserService(repo, nil, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	criteria := sharedDomain.CompositeCriteria{
 		Operator: sharedDomain.OpAnd,
@@ -167,17 +223,17 @@ func TestListUsersByName(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 10, Offset: 0},
-		sharedQuery.Sort{Field: "created_at", Desc: false},
+		[]sharedQuery.Sort{{Field: "created_at", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
-	assert.Equal(t, "Ana", results[0].Nombre)
+	assert.Equal(t, "Ana", results[0].Name)
 }
 
 func TestListUsers(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
 	cache := mocks.NewDummyCache()
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	user1, _ := service.CreateUser(context.Background(), "a@example.com", "Ana", time.Now())
 	user2, _ := service.CreateUser(context.Background(), "b@example.com", "Bob", time.Now())
@@ -188,7 +244,7 @@ func TestListUsers(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 20, Offset: 0},
-		sharedQuery.Sort{Field: "created_at", Desc: false},
+		[]sharedQuery.Sort{{Field: "created_at", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, users, 2)
@@ -196,10 +252,47 @@ func TestListUsers(t *testing.T) {
 	assert.Contains(t, users, user2)
 }
 
+// rawCriteria filtra por una condición arbitraria, como haría un cliente con un campo mal escrito.
+type rawCriteria sharedDomain.Criterion
+
+func (c rawCriteria) ToConditions() []sharedDomain.Criterion {
+	return []sharedDomain.Criterion{sharedDomain.Criterion(c)}
+}
+
+func TestListUsers_RejectsUndeclaredFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria sharedDomain.Criteria
+		sorts    []sharedQuery.Sort
+	}{
+		{"unknown filter field", rawCriteria{Field: "nmbre", Op: sharedDomain.OpEq, Value: "Ana"}, nil},
+		{"unsupported operator", rawCriteria{Field: "id", Op: sharedDomain.OpGt, Value: uuid.New()}, nil},
+		{"unknown sort field", nil, []sharedQuery.Sort{{Field: "age"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := mocks.NewInMemoryUserRepo()
+			service := NewUserService(repo, mocks.NewDummyCache(), nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+			_, err := service.CreateUser(context.Background(), "a@example.com", "Ana", time.Now())
+			assert.NoError(t, err)
+
+			// Act
+			users, listErr := service.ListUsers(context.Background(), tt.criteria, sharedQuery.OffsetPagination{Limit: 10}, tt.sorts)
+			_, pageErr := service.ListUsersPage(context.Background(), tt.criteria, sharedQuery.OffsetPagination{Limit: 10}, tt.sorts)
+
+			// Assert: el error es de validación y no el de la consulta al repositorio.
+			assert.ErrorIs(t, listErr, sharedDomain.ErrInvalidCriteria)
+			assert.ErrorIs(t, pageErr, sharedDomain.ErrInvalidCriteria)
+			assert.Empty(t, users)
+		})
+	}
+}
+
 func TestListAdultUsers(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
 	cache := mocks.NewDummyCache()
-	service := NewUserService(repo, cache, zap.NewNop())
+	service := NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	// Crear usuarios de distintas edades
 	// BirthDate calculado para que user1 tenga 20 años y user2 tenga 15 años
@@ -210,7 +303,7 @@ func TestListAdultUsers(t *testing.T) {
 	users, err := service.ListAdultUsers(
 		context.Background(),
 		sharedQuery.OffsetPagination{Limit: 10, Offset: 0},
-		sharedQuery.Sort{Field: "created_at", Desc: false},
+		[]sharedQuery.Sort{{Field: "created_at", Desc: false}},
 	)
 	assert.NoError(t, err)
 
@@ -221,17 +314,38 @@ func TestListAdultUsers(t *testing.T) {
 	assert.NotContains(t, users, user2)
 }
 
+func TestListAdultUsers_UsesClock(t *testing.T) {
+	// Arrange: Ana cumple 18 años el día siguiente a la hora del reloj.
+	clock := mocks.NewFakeClock(time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC))
+	repo := mocks.NewInMemoryUserRepo()
+	service := NewUserService(repo, mocks.NewDummyCache(), nil, clock, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+	ana, _ := service.CreateUser(context.Background(), "ana@example.com", "Ana", time.Date(2007, time.March, 2, 10, 0, 0, 0, time.UTC))
+	page := sharedQuery.OffsetPagination{Limit: 10}
+	sorts := []sharedQuery.Sort{{Field: "created_at"}}
+
+	// Act
+	before, errBefore := service.ListAdultUsers(context.Background(), page, sorts)
+	clock.Advance(24 * time.Hour)
+	after, errAfter := service.ListAdultUsers(context.Background(), page, sorts)
+
+	// Assert
+	assert.NoError(t, errBefore)
+	assert.Empty(t, before)
+	assert.NoError(t, errAfter)
+	assert.Equal(t, []*userDomain.User{ana}, after)
+}
+
 func TestListUsers_PaginationOffsetAndSorting(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
-	service := NewUserService(repo, nil, zap.NewNop())
+	service := NewUserService(repo, nil, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	// Crear 5 usuarios con distintos nombres y emails
 	users := []*userDomain.User{
-		{ID: uuid.New(), Nombre: "Ana", Email: "ana@example.com", CreatedAt: time.Now().Add(-5 * time.Hour)},
-		{ID: uuid.New(), Nombre: "Bob", Email: "bob@example.com", CreatedAt: time.Now().Add(-4 * time.Hour)},
-		{ID: uuid.New(), Nombre: "Carlos", Email: "carlos@example.com", CreatedAt: time.Now().Add(-3 * time.Hour)},
-		{ID: uuid.New(), Nombre: "Dave", Email: "Dave@example.com", CreatedAt: time.Now().Add(-2 * time.Hour)},
-		{ID: uuid.New(), Nombre: "Eva", Email: "eva@example.com", CreatedAt: time.Now().Add(-1 * time.Hour)},
+		{ID: uuid.New(), Name: "Ana", Email: "ana@example.com", CreatedAt: time.Now().Add(-5 * time.Hour)},
+		{ID: uuid.New(), Name: "Bob", Email: "bob@example.com", CreatedAt: time.Now().Add(-4 * time.Hour)},
+		{ID: uuid.New(), Name: "Carlos", Email: "carlos@example.com", CreatedAt: time.Now().Add(-3 * time.Hour)},
+		{ID: uuid.New(), Name: "Dave", Email: "Dave@example.com", CreatedAt: time.Now().Add(-2 * time.Hour)},
+		{ID: uuid.New(), Name: "Eva", Email: "eva@example.com", CreatedAt: time.Now().Add(-1 * time.Hour)},
 	}
 	for _, u := range users {
 		_ = repo.Create(context.Background(), u, sharedDomain.OutboxEvent{})
@@ -244,37 +358,37 @@ func TestListUsers_PaginationOffsetAndSorting(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 2, Offset: 0},
-		sharedQuery.Sort{Field: "nombre", Desc: false},
+		[]sharedQuery.Sort{{Field: "name", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, page1, 2)
-	assert.Equal(t, "Ana", page1[0].Nombre)
-	assert.Equal(t, "Bob", page1[1].Nombre)
+	assert.Equal(t, "Ana", page1[0].Name)
+	assert.Equal(t, "Bob", page1[1].Name)
 
 	page2, err := service.ListUsers(
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 2, Offset: 2},
-		sharedQuery.Sort{Field: "nombre", Desc: false},
+		[]sharedQuery.Sort{{Field: "name", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, page2, 2)
-	assert.Equal(t, "Carlos", page2[0].Nombre)
-	assert.Equal(t, "Dave", page2[1].Nombre)
+	assert.Equal(t, "Carlos", page2[0].Name)
+	assert.Equal(t, "Dave", page2[1].Name)
 
 	// --- 2. Orden descendente ---
 	descUsers, err := service.ListUsers(
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 5, Offset: 0},
-		sharedQuery.Sort{Field: "nombre", Desc: true},
+		[]sharedQuery.Sort{{Field: "name", Desc: true}},
 	)
 	assert.NoError(t, err)
-	assert.Equal(t, "Eva", descUsers[0].Nombre)
-	assert.Equal(t, "Dave", descUsers[1].Nombre)
-	assert.Equal(t, "Carlos", descUsers[2].Nombre)
-	assert.Equal(t, "Bob", descUsers[3].Nombre)
-	assert.Equal(t, "Ana", descUsers[4].Nombre)
+	assert.Equal(t, "Eva", descUsers[0].Name)
+	assert.Equal(t, "Dave", descUsers[1].Name)
+	assert.Equal(t, "Carlos", descUsers[2].Name)
+	assert.Equal(t, "Bob", descUsers[3].Name)
+	assert.Equal(t, "Ana", descUsers[4].Name)
 
 	// --- 3. Filtro combinado + paginación ---
 	filterCriteria := sharedDomain.CompositeCriteria{
@@ -287,19 +401,19 @@ func TestListUsers_PaginationOffsetAndSorting(t *testing.T) {
 		context.Background(),
 		filterCriteria,
 		sharedQuery.OffsetPagination{Limit: 2, Offset: 1},
-		sharedQuery.Sort{Field: "nombre", Desc: false},
+		[]sharedQuery.Sort{{Field: "name", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, filteredPage, 2)
-	assert.Equal(t, "Carlos", filteredPage[0].Nombre)
-	assert.Equal(t, "Dave", filteredPage[1].Nombre)
+	assert.Equal(t, "Carlos", filteredPage[0].Name)
+	assert.Equal(t, "Dave", filteredPage[1].Name)
 
 	// --- 4. Offset fuera de rango → array vacío ---
 	outOfRange, err := service.ListUsers(
 		context.Background(),
 		criteria,
 		sharedQuery.OffsetPagination{Limit: 2, Offset: 10},
-		sharedQuery.Sort{Field: "nombre", Desc: false},
+		[]sharedQuery.Sort{{Field: "name", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, outOfRange, 0)
@@ -307,15 +421,15 @@ func TestListUsers_PaginationOffsetAndSorting(t *testing.T) {
 
 func TestListUsers_CursorPagination(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
-	service := NewUserService(repo, nil, zap.NewNop())
+	service := NewUserService(repo, nil, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	// Crear 5 usuarios con distintos nombres y created_at
 	users := []*userDomain.User{
-		{ID: uuid.New(), Nombre: "Ana", CreatedAt: time.Now().Add(-5 * time.Hour)},
-		{ID: uuid.New(), Nombre: "Bob", CreatedAt: time.Now().Add(-4 * time.Hour)},
-		{ID: uuid.New(), Nombre: "Carlos", CreatedAt: time.Now().Add(-3 * time.Hour)},
-		{ID: uuid.New(), Nombre: "Dave", CreatedAt: time.Now().Add(-2 * time.Hour)},
-		{ID: uuid.New(), Nombre: "Eva", CreatedAt: time.Now().Add(-1 * time.Hour)},
+		{ID: uuid.New(), Name: "Ana", Email: "ana@example.com", CreatedAt: time.Now().Add(-5 * time.Hour)},
+		{ID: uuid.New(), Name: "Bob", Email: "bob@example.com", CreatedAt: time.Now().Add(-4 * time.Hour)},
+		{ID: uuid.New(), Name: "Carlos", Email: "carlos@example.com", CreatedAt: time.Now().Add(-3 * time.Hour)},
+		{ID: uuid.New(), Name: "Dave", Email: "dave@example.com", CreatedAt: time.Now().Add(-2 * time.Hour)},
+		{ID: uuid.New(), Name: "Eva", Email: "eva@example.com", CreatedAt: time.Now().Add(-1 * time.Hour)},
 	}
 	for _, u := range users {
 		_ = repo.Create(context.Background(), u, sharedDomain.OutboxEvent{})
@@ -327,8 +441,8 @@ func TestListUsers_CursorPagination(t *testing.T) {
 	buildCursor := func(u *userDomain.User, sortField string) string {
 		var val string
 		switch sortField {
-		case "nombre":
-			val = u.Nombre
+		case "name":
+			val = u.Name
 		case "email":
 			val = u.Email
 		case "created_at":
@@ -336,7 +450,7 @@ func TestListUsers_CursorPagination(t *testing.T) {
 		default:
 			val = u.ID.String()
 		}
-		return fmt.Sprintf("%s|%s", val, u.ID.String())
+		return sharedQuery.NewCursor(u.ID, val).Encode()
 	}
 
 	// --- 1. Primer "page" usando cursor vacío ---
@@ -345,17 +459,15 @@ func TestListUsers_CursorPagination(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.CursorPagination{
-			Limit:     2,
-			Cursor:    cursor,
-			SortField: "created_at",
-			SortDesc:  false,
+			Limit:  2,
+			Cursor: cursor,
 		},
-		sharedQuery.Sort{Field: "created_at", Desc: false},
+		[]sharedQuery.Sort{{Field: "created_at", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, page1, 2)
-	assert.Equal(t, "Ana", page1[0].Nombre)
-	assert.Equal(t, "Bob", page1[1].Nombre)
+	assert.Equal(t, "Ana", page1[0].Name)
+	assert.Equal(t, "Bob", page1[1].Name)
 
 	// --- 2. Segunda "page" usando cursor del último elemento de la primera ---
 	cursor = buildCursor(page1[len(page1)-1], "created_at")
@@ -363,17 +475,15 @@ func TestListUsers_CursorPagination(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.CursorPagination{
-			Limit:     2,
-			Cursor:    cursor,
-			SortField: "created_at",
-			SortDesc:  false,
+			Limit:  2,
+			Cursor: cursor,
 		},
-		sharedQuery.Sort{Field: "created_at", Desc: false},
+		[]sharedQuery.Sort{{Field: "created_at", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, page2, 2)
-	assert.Equal(t, "Carlos", page2[0].Nombre)
-	assert.Equal(t, "Dave", page2[1].Nombre)
+	assert.Equal(t, "Carlos", page2[0].Name)
+	assert.Equal(t, "Dave", page2[1].Name)
 
 	// --- 3. Última "page" (menos elementos que limit) ---
 	cursor = buildCursor(page2[len(page2)-1], "created_at")
@@ -381,16 +491,14 @@ func TestListUsers_CursorPagination(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.CursorPagination{
-			Limit:     2,
-			Cursor:    cursor,
-			SortField: "created_at",
-			SortDesc:  false,
+			Limit:  2,
+			Cursor: cursor,
 		},
-		sharedQuery.Sort{Field: "created_at", Desc: false},
+		[]sharedQuery.Sort{{Field: "created_at", Desc: false}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, page3, 1)
-	assert.Equal(t, "Eva", page3[0].Nombre)
+	assert.Equal(t, "Eva", page3[0].Name)
 
 	// --- 4. Orden descendente usando cursor ---
 	cursor = ""
@@ -398,16 +506,14 @@ func TestListUsers_CursorPagination(t *testing.T) {
 		context.Background(),
 		criteria,
 		sharedQuery.CursorPagination{
-			Limit:     3,
-			Cursor:    cursor,
-			SortField: "created_at",
-			SortDesc:  true,
+			Limit:  3,
+			Cursor: cursor,
 		},
-		sharedQuery.Sort{Field: "created_at", Desc: true},
+		[]sharedQuery.Sort{{Field: "created_at", Desc: true}},
 	)
 	assert.NoError(t, err)
 	assert.Len(t, descPage, 3)
-	assert.Equal(t, "Eva", descPage[0].Nombre)
-	assert.Equal(t, "Dave", descPage[1].Nombre)
-	assert.Equal(t, "Carlos", descPage[2].Nombre)
+	assert.Equal(t, "Eva", descPage[0].Name)
+	assert.Equal(t, "Dave", descPage[1].Name)
+	assert.Equal(t, "Carlos", descPage[2].Name)
 }
@@ -15,19 +15,77 @@ const (
 
 const UserTopic = "user"
 
+// userSchema es el contrato de user.created y user.updated: el usuario completo. created_at y
+// version son opcionales porque los contratos de integración (sharedEvents.UserCreated) no los
+// llevan. name es
+// el nombre canónico; nombre se mantiene, obligatorio, para los consumidores existentes hasta
+// una versión 2 del evento.
+var userSchema = sharedEvents.MustCompileSchema(`{
+	"type": "object",
+	"required": ["id", "email", "nombre", "birth_date"],
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"email": {"type": "string", "format": "email"},
+		"name": {"type": "string"},
+		"nombre": {"type": "string", "deprecated": true},
+		"birth_date": {"type": "string", "format": "date-time"},
+		"created_at": {"type": "string", "format": "date-time"},
+		"version": {"type": "integer"}
+	},
+	"additionalProperties": false,
+	"examples": [{
+		"id": "6f1c3a52-9a0e-4d2c-8a4e-2b1f0c9d7e65",
+		"email": "ana@example.com",
+		"name": "Ana",
+		"nombre": "Ana",
+		"birth_date": "1990-05-17T00:00:00Z",
+		"created_at": "2025-03-01T10:00:00Z",
+		"version": 1
+	}]
+}`)
+
+// userDeletedSchema es el contrato de user.deleted: solo el ID del usuario borrado.
+var userDeletedSchema = sharedEvents.MustCompileSchema(`{
+	"type": "object",
+	"required": ["id"],
+	"properties": {
+		"id": {"type": "string", "format": "uuid"}
+	},
+	"additionalProperties": false,
+	"examples": [{"id": "6f1c3a52-9a0e-4d2c-8a4e-2b1f0c9d7e65"}]
+}`)
+
+// UserPayload es el payload de user.created y user.updated: el usuario con el campo name y, además,
+// el alias heredado nombre, que el contrato mantiene para los consumidores que aún lo leen.
+type UserPayload struct {
+	User
+	Nombre string `json:"nombre" log:"redact"`
+}
+
+// NewUserPayload devuelve el payload de los eventos de 'u'.
+func NewUserPayload(u *User) UserPayload {
+	return UserPayload{User: *u, Nombre: u.Name}
+}
+
 func NewEventRegistry() map[string]sharedEvents.EventMetadata {
 	return map[string]sharedEvents.EventMetadata{
 		UserCreated: {
-			Type:  reflect.TypeOf(User{}),
-			Topic: UserTopic,
+			Type:    reflect.TypeOf(UserPayload{}),
+			Topic:   UserTopic,
+			Version: 1,
+			Schema:  userSchema,
 		},
 		UserUpdated: {
-			Type:  reflect.TypeOf(User{}),
-			Topic: UserTopic,
+			Type:    reflect.TypeOf(UserPayload{}),
+			Topic:   UserTopic,
+			Version: 1,
+			Schema:  userSchema,
 		},
 		UserDeleted: {
-			Type:  reflect.TypeOf(User{}),
-			Topic: UserTopic,
+			Type:    reflect.TypeOf(User{}),
+			Topic:   UserTopic,
+			Version: 1,
+			Schema:  userDeletedSchema,
 		},
 	}
 }
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventRegistry_Schemas(t *testing.T) {
+	// Arrange: los payloads que el servicio guarda en el outbox.
+	user := &User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana", BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), CreatedAt: time.Now()}
+	userPayload, err := json.Marshal(NewUserPayload(user))
+	require.NoError(t, err)
+	deletedPayload, err := json.Marshal(map[string]interface{}{"id": user.ID.String()})
+	require.NoError(t, err)
+	registry := NewEventRegistry()
+
+	// Act & Assert
+	assert.NoError(t, registry[UserCreated].Schema.Validate(userPayload))
+	assert.NoError(t, registry[UserUpdated].Schema.Validate(userPayload))
+	assert.NoError(t, registry[UserDeleted].Schema.Validate(deletedPayload))
+	assert.Error(t, registry[UserCreated].Schema.Validate([]byte(`{"id": "1", "email": "ana"}`)))
+}
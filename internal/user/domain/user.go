@@ -7,13 +7,21 @@ import (
 	"github.com/google/uuid"
 )
 
-// User representa un usuario del sistema.
+// User representa un usuario del sistema. Los nombres canónicos de los campos, en JSON y en
+// SQL, están en inglés; cada adaptador traduce los alias heredados (nombre) en su capa de
+// mapeo.
 type User struct {
 	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Nombre    string    `json:"nombre"`
-	BirthDate time.Time `json:"birth_date"`
-	CreatedAt time.Time `json:"created_at"`
+	Email     string    `json:"email" log:"redact"`
+	Name      string    `json:"name" log:"redact"`
+	BirthDate time.Time `json:"birth_date" log:"redact"`
+	// SealedBirthDate es la fecha de nacimiento cifrada, tal como se guarda cuando el cifrado de
+	// datos personales está activo: el repositorio la escribe en lugar de BirthDate y la devuelve
+	// al leer, sin descifrar. Vacía, se guarda BirthDate en claro.
+	SealedBirthDate string    `json:"-" log:"redact"`
+	CreatedAt       time.Time `json:"created_at"`
+	// Version empieza en 1 y aumenta con cada actualización (ver UserRepository.Update).
+	Version int64 `json:"version"`
 }
 
 func (u *User) PartitionKey() string {
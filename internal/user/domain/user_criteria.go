@@ -7,6 +7,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// UserFields son los campos por los que se puede filtrar y ordenar usuarios, con sus operadores.
+var UserFields = sharedDomain.FieldRegistry{
+	"id":         {sharedDomain.OpEq},
+	"email":      {sharedDomain.OpEq, sharedDomain.OpLike, sharedDomain.OpILike},
+	"name":       {sharedDomain.OpEq, sharedDomain.OpLike, sharedDomain.OpILike},
+	"birth_date": {sharedDomain.OpEq, sharedDomain.OpGt, sharedDomain.OpGte, sharedDomain.OpLt, sharedDomain.OpLte},
+	"created_at": {sharedDomain.OpEq, sharedDomain.OpGt, sharedDomain.OpGte, sharedDomain.OpLt, sharedDomain.OpLte},
+}
+
 // ---------------- Implementaciones concretas ----------------
 
 // Filtrado por ID exacto
@@ -33,17 +42,22 @@ type NameLikeCriteria struct {
 }
 
 func (c NameLikeCriteria) ToConditions() []sharedDomain.Criterion {
-	return []sharedDomain.Criterion{{Field: "nombre", Op: sharedDomain.OpILike, Value: "%" + c.Name + "%"}}
+	return []sharedDomain.Criterion{{Field: "name", Op: sharedDomain.OpILike, Value: "%" + c.Name + "%"}}
 }
 
-// Filtrado por rango de edad
+// Filtrado por rango de edad. Now es el instante de referencia para calcular la edad; si es
+// cero se usa la hora actual.
 type AgeRangeCriteria struct {
 	Min *int
 	Max *int
+	Now time.Time
 }
 
 func (c AgeRangeCriteria) ToConditions() []sharedDomain.Criterion {
-	var now time.Time = time.Now()
+	now := c.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
 	var conds []sharedDomain.Criterion
 	if c.Min != nil {
 		conds = append(conds, sharedDomain.Criterion{
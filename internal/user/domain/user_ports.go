@@ -2,7 +2,6 @@ package domain
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
@@ -12,9 +11,11 @@ import (
 
 // ---------- Errores de dominio ----------
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrUserAlreadyExists = errors.New("user already exists")
-	ErrInvalidUser       = errors.New("invalid user")
+	ErrUserNotFound      = sharedDomain.NewError(sharedDomain.CodeNotFound, "user not found")
+	ErrUserAlreadyExists = sharedDomain.NewError(sharedDomain.CodeAlreadyExists, "user already exists")
+	ErrInvalidUser       = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid user")
+	// ErrUserVersionConflict es el de una actualización sobre una versión que ya no es la guardada.
+	ErrUserVersionConflict = sharedDomain.NewError(sharedDomain.CodeAborted, "user version does not match")
 )
 
 // ---------- Interfaces (Ports) ----------
@@ -27,7 +28,13 @@ type UserRepository interface {
 	// Debe devolver ErrUserNotFound si no existe.
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
 
-	// Debe devolver ErrUserNotFound si el usuario no existe.
+	// GetByIDs devuelve los usuarios de 'ids' que existen, en cualquier orden y sin error por los
+	// que faltan. Sin ids no consulta nada.
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*User, error)
+
+	// Update guarda 'u', que trae ya su nueva versión, solo si la guardada es la anterior
+	// (u.Version-1): si es otra debe devolver ErrUserVersionConflict, y si el usuario no existe,
+	// ErrUserNotFound.
 	Update(ctx context.Context, u *User, evt sharedDomain.OutboxEvent) error
 
 	// Debe devolver ErrUserNotFound si el usuario no existe.
@@ -35,12 +42,16 @@ type UserRepository interface {
 
 	// List devuelve una lista de usuarios según el filtro (paginación, búsqueda, orden).
 	// Si el filtro está vacío, debe devolver todos los usuarios.
-	ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sort sharedQuery.Sort) ([]*User, error)
+	ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*User, error)
+
+	// CountByCriteria devuelve cuántos usuarios cumplen el filtro, sin paginar.
+	CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error)
 }
 
 // ---------- Helpers comunes (cache keys, etc.) ----------
 
-// CacheKeyByID forma una key consistente para cache usando ID.
+// CacheKeyByID forma una key consistente para cache usando ID. El prefijo v2 separa las
+// entradas con el campo name de las antiguas, con nombre, que no caducan por sí solas.
 func UserCacheKeyByID(id uuid.UUID) string {
-	return fmt.Sprintf("user:id:%s", id.String())
+	return fmt.Sprintf("user:v2:id:%s", id.String())
 }
@@ -34,7 +34,7 @@ func TestUser_Age(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			user := &User{
 				Email:     "test@example.com",
-				Nombre:    "Test",
+				Name:      "Test",
 				BirthDate: tt.birth,
 			}
 			assert.Equal(t, tt.expected, user.Age())
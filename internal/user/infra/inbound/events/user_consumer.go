@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"errors" // Necesario para la comprobación de errores
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/redact"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	sharedUtils "github.com/davicafu/hexagolab/internal/shared/infra/utils"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
 )
@@ -19,6 +23,10 @@ type UserEvent struct {
 	Payload []byte
 }
 
+// errNothingToCreate indica que un evento de creación con solo el id (sharedEvents.PayloadID) no
+// trae los datos para crear un usuario que aquí no existe.
+var errNothingToCreate = errors.New("id-only payload carries no data to create the user")
+
 type UserService interface {
 	CreateUser(ctx context.Context, email, nombre string, birthDate time.Time) (*userDomain.User, error)
 	UpdateUser(ctx context.Context, u *userDomain.User) error
@@ -40,16 +48,21 @@ func NewUserConsumer(service UserService, logger *zap.Logger) *UserConsumer {
 }
 
 func (c *UserConsumer) HandleMessage(ctx context.Context, key string, payload []byte) {
+	// Logger con la traza de la petición de origen (ver events.ConsumerAdapter).
+	log := tracing.Logger(ctx, c.log)
 	var base sharedEvents.IntegrationEvent
 	if err := json.Unmarshal(payload, &base); err != nil {
-		c.log.Warn("Failed to unmarshal integration event", zap.String("key", key), zap.Error(err))
+		log.Warn("Failed to unmarshal integration event", zap.String("key", key), zap.Error(err))
 		return
 	}
+	// Lo que hagamos al tratar el evento queda causado por él (ver infraEvents.EnvelopeContext).
+	ctx = infraEvents.EnvelopeContext(ctx, base)
+	log = tracing.Logger(ctx, c.log)
 
 	// ✅ Usamos las constantes en lugar de strings
 	switch base.Type {
 	case userDomain.UserCreated:
-		sharedUtils.UnmarshalAndHandle[sharedEvents.UserCreated](c.log, base.Data, func(evt sharedEvents.UserCreated) {
+		sharedUtils.UnmarshalAndHandle[sharedEvents.UserCreated](log, base.Data, func(evt sharedEvents.UserCreated) {
 			c.withContext(ctx, evt.ID, func(ctxUser context.Context) error {
 
 				// ✅ LÓGICA DE IDEMPOTENCIA: "Buscar antes de Crear"
@@ -57,13 +70,16 @@ func (c *UserConsumer) HandleMessage(ctx context.Context, key string, payload []
 				_, err := c.service.GetUser(ctxUser, evt.ID)
 				if err == nil {
 					// El usuario ya existe, no hacemos nada. Es un evento duplicado.
-					c.log.Info("Evento 'UserCreated' duplicado ignorado", zap.String("user_id", evt.ID.String()))
+					log.Info("Evento 'UserCreated' duplicado ignorado", zap.String("user_id", evt.ID.String()))
 					return nil
 				}
 				// Si el error no es "no encontrado", es un error real que debemos devolver.
 				if !errors.Is(err, userDomain.ErrUserNotFound) {
 					return err
 				}
+				if base.PayloadMode == sharedEvents.PayloadID {
+					return errNothingToCreate
+				}
 
 				// 2. Si no existe, lo creamos.
 				_, err = c.service.CreateUser(ctxUser, evt.Email, evt.Nombre, evt.BirthDate)
@@ -73,50 +89,73 @@ func (c *UserConsumer) HandleMessage(ctx context.Context, key string, payload []
 		})
 
 	case userDomain.UserUpdated:
-		sharedUtils.UnmarshalAndHandle[sharedEvents.UserUpdated](c.log, base.Data, func(evt sharedEvents.UserUpdated) {
+		sharedUtils.UnmarshalAndHandle[sharedEvents.UserUpdated](log, base.Data, func(evt sharedEvents.UserUpdated) {
 			c.withContext(ctx, evt.ID, func(ctxUser context.Context) error {
 				user, err := c.service.GetUser(ctxUser, evt.ID)
 				if err != nil {
 					return err
 				}
+				if evt, err = userUpdate(user, base.Data); err != nil {
+					return err
+				}
+				// Sin cambios no se actualiza: evita que un servicio que consume sus propios
+				// eventos genere un 'user.updated' tras otro.
+				if user.Email == evt.Email && user.Name == evt.Nombre && user.BirthDate.Equal(evt.BirthDate) {
+					log.Info("Evento 'UserUpdated' sin cambios ignorado", zap.String("user_id", evt.ID.String()))
+					return nil
+				}
 				user.Email = evt.Email
-				user.Nombre = evt.Nombre
+				user.Name = evt.Nombre
 				user.BirthDate = evt.BirthDate
 				return c.service.UpdateUser(ctxUser, user)
 			}, "User updated via event", evt)
 		})
 
 	default:
-		c.log.Warn("Unknown event type", zap.String("type", base.Type))
+		log.Warn("Unknown event type", zap.String("type", base.Type))
+	}
+}
+
+// userUpdate devuelve el estado de 'user' con los campos de 'data' encima. Un payload completo
+// los trae todos; uno parcial (delta o solo el id) deja como están los que no lleva.
+func userUpdate(user *userDomain.User, data json.RawMessage) (sharedEvents.UserUpdated, error) {
+	evt := sharedEvents.UserUpdated{ID: user.ID, Email: user.Email, Nombre: user.Name, BirthDate: user.BirthDate}
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return evt, fmt.Errorf("decode user update: %w", err)
 	}
+	return evt, nil
 }
 
 // Helper para ejecutar acción con contexto limitado y log
 func (c *UserConsumer) withContext(ctx context.Context, id uuid.UUID, action func(ctx context.Context) error, successMsg string, evt interface{}) {
+	log := tracing.Logger(ctx, c.log)
 	ctxUser, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
 	defer cancel()
 
 	if err := action(ctxUser); err != nil {
 		// ✅ Si el error es que ya existe, lo tratamos como un éxito (alternativa de idempotencia)
 		if errors.Is(err, userDomain.ErrUserAlreadyExists) {
-			c.log.Info("Evento 'UserCreated' duplicado gestionado por la BBDD", zap.String("user_id", id.String()))
+			log.Info("Evento 'UserCreated' duplicado gestionado por la BBDD", zap.String("user_id", id.String()))
 			return
 		}
 
-		c.log.Warn("Failed to process user event",
+		log.Warn("Failed to process user event",
 			zap.String("user_id", id.String()),
-			zap.Any("event", evt),
+			redact.Any("event", evt),
 			zap.Error(err),
 		)
 	} else {
-		c.log.Info(successMsg,
+		log.Info(successMsg,
 			zap.String("user_id", id.String()),
-			zap.Any("event", evt),
+			redact.Any("event", evt),
 		)
 	}
 }
 
-func BackgroundConsumerChan(ctx context.Context, ch <-chan interface{}, consumer *UserConsumer) {
+// BackgroundConsumerChan inicia una goroutine para consumir eventos de un canal, pasando cada
+// uno por los hooks de consumo de 'hooks'.
+func BackgroundConsumerChan(ctx context.Context, ch <-chan interface{}, consumer *UserConsumer, hooks *infraEvents.Hooks) {
+	handler := infraEvents.InstrumentHandler(infraEvents.HookHandler(consumer, hooks, consumer.log), "memory", userDomain.UserTopic)
 	go func() {
 		for {
 			select {
@@ -127,7 +166,7 @@ func BackgroundConsumerChan(ctx context.Context, ch <-chan interface{}, consumer
 				// ✅ Esperamos recibir []byte, que es lo que el bus envía.
 				if payload, ok := msg.([]byte); ok {
 					// Le pasamos los bytes directamente al handler.
-					consumer.HandleMessage(ctx, "", payload)
+					infraEvents.HandleWithRecovery(ctx, handler, "", payload, consumer.log)
 				}
 			}
 		}
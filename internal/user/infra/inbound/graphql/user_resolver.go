@@ -0,0 +1,376 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	sharedGraphql "github.com/davicafu/hexagolab/internal/shared/infra/inbound/graphql"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/user/application"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// defaultListLimit es el tamaño de página cuando la consulta no indica first, como en la API HTTP.
+const defaultListLimit = 50
+
+// Errores de los argumentos, con los mensajes de la API HTTP.
+var (
+	errInvalidUserID    = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid user id")
+	errInvalidEmail     = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid email")
+	errInvalidBirthDate = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid birth_date format, use YYYY-MM-DD")
+)
+
+// Schema es la parte de usuarios del esquema GraphQL.
+var Schema = sharedGraphql.Module{
+	Types: `
+type User {
+	id: ID!
+	email: String!
+	name: String!
+	"Fecha de nacimiento, YYYY-MM-DD."
+	birthDate: String!
+	createdAt: Time!
+}
+
+type UserConnection {
+	nodes: [User!]!
+	pageInfo: PageInfo!
+	totalCount: Int!
+}
+
+input UserFilter {
+	"Texto contenido en el nombre, sin distinguir mayúsculas."
+	name: String
+	email: String
+	minAge: Int
+	maxAge: Int
+}
+
+input CreateUserInput {
+	email: String!
+	name: String!
+	birthDate: String!
+}
+
+"Los campos que no se indican no cambian."
+input UpdateUserInput {
+	email: String
+	name: String
+	birthDate: String
+}
+
+"Un evento de usuario publicado en el bus."
+type UserEvent {
+	id: ID!
+	"user.created, user.updated o user.deleted."
+	type: String!
+	occurredAt: Time!
+	userId: ID!
+	"El usuario en el momento de recibir el evento; null si ya no existe."
+	user: User
+}
+`,
+	Query: `
+	user(id: ID!): User
+	"Usuarios que cumplen el filtro, por páginas de first (50 por defecto) desde el cursor after. sort admite los campos de GET /users, p.ej. \"name,-created_at\"."
+	users(filter: UserFilter, first: Int, after: String, sort: String): UserConnection!
+`,
+	Mutation: `
+	createUser(input: CreateUserInput!): User!
+	updateUser(id: ID!, input: UpdateUserInput!): User!
+	deleteUser(id: ID!): Boolean!
+`,
+	Subscription: `
+	"Eventos de usuario del inquilino de la petición, desde que se suscribe."
+	userEvents: UserEvent!
+`,
+}
+
+// Resolver resuelve los campos de usuarios del esquema con el servicio de aplicación, con las
+// mismas validaciones que la API HTTP.
+type Resolver struct {
+	service *application.UserService
+	feed    *infraEvents.Feed
+	clock   sharedDomain.Clock
+}
+
+// NewResolver crea el resolver. 'feed' entrega los eventos de userEvents y 'clock' es la hora de
+// referencia de los filtros por edad.
+func NewResolver(service *application.UserService, feed *infraEvents.Feed, clock sharedDomain.Clock) *Resolver {
+	return &Resolver{service: service, feed: feed, clock: clock}
+}
+
+// ---------------- Tipos ----------------
+
+type userNode struct {
+	ID        graphql.ID
+	Email     string
+	Name      string
+	BirthDate string
+	CreatedAt graphql.Time
+}
+
+func toUserNode(u *userDomain.User) *userNode {
+	return &userNode{
+		ID:        graphql.ID(u.ID.String()),
+		Email:     u.Email,
+		Name:      u.Name,
+		BirthDate: u.BirthDate.Format("2006-01-02"),
+		CreatedAt: graphql.Time{Time: u.CreatedAt},
+	}
+}
+
+type userConnection struct {
+	Nodes      []*userNode
+	PageInfo   sharedGraphql.PageInfo
+	TotalCount int32
+}
+
+type userFilter struct {
+	Name   *string
+	Email  *string
+	MinAge *int32
+	MaxAge *int32
+}
+
+type createUserInput struct {
+	Email     string
+	Name      string
+	BirthDate string
+}
+
+type updateUserInput struct {
+	Email     *string
+	Name      *string
+	BirthDate *string
+}
+
+// ---------------- Consultas ----------------
+
+// User resuelve user(id): null si no existe.
+func (r *Resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userNode, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, sharedGraphql.Error(errInvalidUserID)
+	}
+	user, err := r.service.GetUser(ctx, id)
+	if errors.Is(err, userDomain.ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	return toUserNode(user), nil
+}
+
+// Users resuelve users: pagina siempre por cursor.
+func (r *Resolver) Users(ctx context.Context, args struct {
+	Filter *userFilter
+	First  *int32
+	After  *string
+	Sort   *string
+}) (*userConnection, error) {
+	sorts := []sharedQuery.Sort{{Field: "created_at", Desc: true}}
+	if args.Sort != nil && *args.Sort != "" {
+		parsed, err := sharedQuery.ParseSorts(*args.Sort)
+		if err != nil {
+			return nil, sharedGraphql.Error(err)
+		}
+		sorts = parsed
+	}
+	pagination := sharedQuery.CursorPagination{Limit: defaultListLimit}
+	if args.First != nil {
+		pagination.Limit = sanitize.Limit(int(*args.First), defaultListLimit)
+	}
+	if args.After != nil {
+		pagination.Cursor = *args.After
+	}
+
+	page, err := r.service.ListUsersPage(ctx, r.criteria(args.Filter), pagination, sorts)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	conn := &userConnection{
+		Nodes:      make([]*userNode, 0, len(page.Items)),
+		PageInfo:   sharedGraphql.NewPageInfo(page.NextCursor, page.HasMore),
+		TotalCount: int32(page.Total),
+	}
+	for _, u := range page.Items {
+		conn.Nodes = append(conn.Nodes, toUserNode(u))
+	}
+	return conn, nil
+}
+
+// criteria traduce el filtro de users a los criterios del dominio.
+func (r *Resolver) criteria(f *userFilter) sharedDomain.Criteria {
+	var criterias []sharedDomain.Criteria
+	if f != nil {
+		if f.Name != nil {
+			criterias = append(criterias, userDomain.NameLikeCriteria{Name: *f.Name})
+		}
+		if f.Email != nil {
+			criterias = append(criterias, userDomain.EmailCriteria{Email: *f.Email})
+		}
+		if f.MinAge != nil || f.MaxAge != nil {
+			ages := userDomain.AgeRangeCriteria{Now: r.clock.Now()}
+			if f.MinAge != nil {
+				minAge := int(*f.MinAge)
+				ages.Min = &minAge
+			}
+			if f.MaxAge != nil {
+				maxAge := int(*f.MaxAge)
+				ages.Max = &maxAge
+			}
+			criterias = append(criterias, ages)
+		}
+	}
+	return sharedDomain.CompositeCriteria{Operator: sharedDomain.OpAnd, Criterias: criterias}
+}
+
+// ---------------- Mutaciones ----------------
+
+// CreateUser resuelve createUser, como POST /users.
+func (r *Resolver) CreateUser(ctx context.Context, args struct{ Input createUserInput }) (*userNode, error) {
+	email, err := parseEmail(args.Input.Email)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	name, err := sanitize.RequiredLine("name", args.Input.Name, sanitize.MaxNameLength)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	birthDate, err := time.Parse("2006-01-02", args.Input.BirthDate)
+	if err != nil {
+		return nil, sharedGraphql.Error(errInvalidBirthDate)
+	}
+
+	user, err := r.service.CreateUser(ctx, email, name, birthDate)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	return toUserNode(user), nil
+}
+
+// UpdateUser resuelve updateUser, como PUT /users/:id.
+func (r *Resolver) UpdateUser(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateUserInput
+}) (*userNode, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, sharedGraphql.Error(errInvalidUserID)
+	}
+	user, err := r.service.GetUserForUpdate(ctx, id)
+	if err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+
+	in := args.Input
+	if in.Email != nil {
+		if user.Email, err = parseEmail(*in.Email); err != nil {
+			return nil, sharedGraphql.Error(err)
+		}
+	}
+	if in.Name != nil {
+		if user.Name, err = sanitize.RequiredLine("name", *in.Name, sanitize.MaxNameLength); err != nil {
+			return nil, sharedGraphql.Error(err)
+		}
+	}
+	if in.BirthDate != nil {
+		if user.BirthDate, err = time.Parse("2006-01-02", *in.BirthDate); err != nil {
+			return nil, sharedGraphql.Error(errInvalidBirthDate)
+		}
+	}
+
+	if err := r.service.UpdateUser(ctx, user); err != nil {
+		return nil, sharedGraphql.Error(err)
+	}
+	return toUserNode(user), nil
+}
+
+// DeleteUser resuelve deleteUser, como DELETE /users/:id.
+func (r *Resolver) DeleteUser(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return false, sharedGraphql.Error(errInvalidUserID)
+	}
+	if err := r.service.DeleteUser(ctx, id); err != nil {
+		return false, sharedGraphql.Error(err)
+	}
+	return true, nil
+}
+
+func parseEmail(raw string) (string, error) {
+	email, err := sanitize.Line("email", raw, sanitize.MaxEmailLength)
+	if err != nil {
+		return "", err
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return "", errInvalidEmail
+	}
+	return email, nil
+}
+
+// ---------------- Suscripciones ----------------
+
+type userEvent struct {
+	ID         graphql.ID
+	Type       string
+	OccurredAt graphql.Time
+	UserID     graphql.ID
+
+	resolver *Resolver
+}
+
+// User resuelve el usuario del evento al entregarlo.
+func (e *userEvent) User(ctx context.Context) (*userNode, error) {
+	return e.resolver.User(ctx, struct{ ID graphql.ID }{e.UserID})
+}
+
+// UserEvents resuelve userEvents con los eventos del topic de usuarios que recibe el feed.
+func (r *Resolver) UserEvents(ctx context.Context) (<-chan *userEvent, error) {
+	events := r.feed.Subscribe(ctx, userDomain.UserTopic)
+	out := make(chan *userEvent)
+	go func() {
+		defer close(out)
+		for evt := range events {
+			node, ok := r.toUserEvent(evt)
+			if !ok {
+				continue
+			}
+			select {
+			case out <- node:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// toUserEvent traduce un evento del bus; descarta los que no llevan el id del usuario.
+func (r *Resolver) toUserEvent(evt sharedEvents.IntegrationEvent) (*userEvent, bool) {
+	var data struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := json.Unmarshal(evt.Data, &data); err != nil || data.ID == uuid.Nil {
+		return nil, false
+	}
+	return &userEvent{
+		ID:         graphql.ID(evt.EventID.String()),
+		Type:       evt.Type,
+		OccurredAt: graphql.Time{Time: evt.OccurredAt},
+		UserID:     graphql.ID(data.ID.String()),
+		resolver:   r,
+	}, true
+}
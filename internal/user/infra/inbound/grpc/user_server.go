@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/davicafu/hexagolab/gen/go/user"
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedGrpc "github.com/davicafu/hexagolab/internal/shared/infra/inbound/grpc"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/user/application"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// defaultListLimit es el tamaño de página cuando la petición no indica limit, como en la API HTTP.
+const defaultListLimit = 50
+
+// GrpcUserServer implementa la interfaz generada por gRPC para el servicio de usuarios.
+type GrpcUserServer struct {
+	// Es necesario para la compatibilidad hacia adelante de gRPC.
+	pb.UnsafeUserServiceServer
+	service *application.UserService
+}
+
+func NewGrpcUserServer(service *application.UserService) *GrpcUserServer {
+	return &GrpcUserServer{service: service}
+}
+
+// CreateUser valida la petición igual que POST /users y crea el usuario.
+func (s *GrpcUserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	email, err := sanitize.Line("email", req.GetEmail(), sanitize.MaxEmailLength)
+	if err != nil {
+		return nil, sharedGrpc.Error(err)
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid email")
+	}
+	name, err := sanitize.RequiredLine("nombre", req.GetNombre(), sanitize.MaxNameLength)
+	if err != nil {
+		return nil, sharedGrpc.Error(err)
+	}
+	birthDate, err := time.Parse("2006-01-02", req.GetBirthDate())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid birth_date format, use YYYY-MM-DD")
+	}
+
+	user, err := s.service.CreateUser(ctx, email, name, birthDate)
+	if err != nil {
+		return nil, sharedGrpc.Error(fmt.Errorf("could not create user: %w", err))
+	}
+	return toProto(user), nil
+}
+
+// ListUsers lista los usuarios que cumplen los filtros, de más reciente a más antiguo. Con cursor
+// pagina por cursor y next_cursor lleva el de la página siguiente (ver sharedGrpc.Pagination).
+func (s *GrpcUserServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	var criterias []sharedDomain.Criteria
+	if email := req.GetEmail(); email != "" {
+		criterias = append(criterias, userDomain.EmailCriteria{Email: email})
+	}
+	if nombre := req.GetNombre(); nombre != "" {
+		criterias = append(criterias, userDomain.NameLikeCriteria{Name: nombre})
+	}
+
+	limit := sanitize.Limit(int(req.GetLimit()), defaultListLimit)
+	page, err := s.service.ListUsersPage(ctx,
+		sharedDomain.CompositeCriteria{Operator: sharedDomain.OpAnd, Criterias: criterias},
+		sharedGrpc.Pagination(limit, req.GetOffset(), req.Cursor),
+		[]sharedQuery.Sort{{Field: "created_at", Desc: true}},
+	)
+	if err != nil {
+		return nil, sharedGrpc.Error(fmt.Errorf("could not list users: %w", err))
+	}
+
+	resp := &pb.ListUsersResponse{
+		Users:      make([]*pb.User, 0, len(page.Items)),
+		HasMore:    page.HasMore,
+		Total:      int32(page.Total),
+		NextCursor: page.NextCursor,
+	}
+	for _, u := range page.Items {
+		resp.Users = append(resp.Users, toProto(u))
+	}
+	return resp, nil
+}
+
+// DeleteUser borra un usuario; el evento de borrado se publica a través del outbox.
+func (s *GrpcUserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid id format")
+	}
+	if err := s.service.DeleteUser(ctx, id); err != nil {
+		return nil, sharedGrpc.Error(err)
+	}
+	return &pb.DeleteUserResponse{}, nil
+}
+
+// toProto traduce el usuario del dominio al mensaje del proto. El proto conserva el campo
+// nombre: renombrarlo no cambiaría el formato binario, pero sí el JSON de protobuf y el código
+// generado de los clientes, así que su nombre se cambiará en una versión nueva del servicio.
+func toProto(u *userDomain.User) *pb.User {
+	return &pb.User{
+		Id:        u.ID.String(),
+		Email:     u.Email,
+		Nombre:    u.Name,
+		BirthDate: u.BirthDate.Format("2006-01-02"),
+		CreatedAt: timestamppb.New(u.CreatedAt),
+	}
+}
+
+// Verificación estática
+var _ pb.UserServiceServer = (*GrpcUserServer)(nil)
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/url"
+	"strings"
+
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// Mapeo entre los nombres de campo de la API y los del dominio. Los nombres canónicos están en
+// inglés (name); nombre es el alias de la primera versión de la API, que se sigue aceptando en
+// el cuerpo, los filtros y la ordenación, y se sigue enviando en las respuestas para no romper a
+// los clientes existentes. Es el único sitio del adaptador que conoce los alias.
+
+// legacyFields traduce cada alias heredado a su nombre canónico.
+var legacyFields = map[string]string{
+	"nombre": "name",
+}
+
+// userResponse es la representación JSON de un usuario: sus campos canónicos y el alias nombre.
+type userResponse struct {
+	*userDomain.User
+	Nombre string `json:"nombre"` // Obsoleto: usar name
+}
+
+func toUserResponse(u *userDomain.User) userResponse {
+	return userResponse{User: u, Nombre: u.Name}
+}
+
+func toUserResponses(users []*userDomain.User) []userResponse {
+	out := make([]userResponse, 0, len(users))
+	for _, u := range users {
+		out = append(out, toUserResponse(u))
+	}
+	return out
+}
+
+// canonicalField devuelve el nombre canónico de 'field', que puede ser un alias heredado.
+func canonicalField(field string) string {
+	if canonical, ok := legacyFields[field]; ok {
+		return canonical
+	}
+	return field
+}
+
+// canonicalQuery devuelve 'query' con los filtros de los alias heredados, con o sin operador
+// (nombre, nombre[eq]), renombrados a su nombre canónico.
+func canonicalQuery(query url.Values) url.Values {
+	out := make(url.Values, len(query))
+	for key, values := range query {
+		param, op, _ := strings.Cut(key, "[")
+		if canonical, ok := legacyFields[param]; ok {
+			key = canonical
+			if op != "" {
+				key += "[" + op
+			}
+		}
+		out[key] = append(out[key], values...)
+	}
+	return out
+}
+
+// canonicalSorts renombra los campos de ordenación que usan un alias heredado.
+func canonicalSorts(sorts []sharedQuery.Sort) []sharedQuery.Sort {
+	for i := range sorts {
+		sorts[i].Field = canonicalField(sorts[i].Field)
+	}
+	return sorts
+}
@@ -6,8 +6,10 @@ func RegisterUserRoutes(r *gin.Engine, handler *UserHandler) {
 	users := r.Group("/users")
 	{
 		users.POST("/", handler.CreateUser)
-		users.GET("/", handler.ListUsers)  // Listado de usuarios
-		users.GET("/:id", handler.GetUser) // Usuario por id
+		users.GET("/", handler.ListUsers)          // Listado de usuarios, o los de ?ids=a,b,c
+		users.GET("/export", handler.ExportUsers)  // Usuarios filtrados, en NDJSON o CSV
+		users.POST("/import", handler.ImportUsers) // Alta de un usuario por fila de NDJSON o CSV
+		users.GET("/:id", handler.GetUser)         // Usuario por id
 		users.PUT("/:id", handler.UpdateUser)
 		users.DELETE("/:id", handler.DeleteUser)
 	}
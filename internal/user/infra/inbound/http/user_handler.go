@@ -1,7 +1,9 @@
 package http
 
 import (
+	"context"
 	"net/http"
+	"net/mail"
 	"strconv"
 	"time"
 
@@ -9,20 +11,32 @@ import (
 	"github.com/google/uuid"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	"github.com/davicafu/hexagolab/internal/shared/infra/inbound/sanitize"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
 	"github.com/davicafu/hexagolab/internal/user/application"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	"github.com/davicafu/hexagolab/pkg/dataio"
 	response "github.com/davicafu/hexagolab/pkg/utils"
 )
 
+// Errores de los parámetros de la petición.
+var (
+	errInvalidUserID    = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid user id")
+	errInvalidEmail     = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid email")
+	errInvalidBirthDate = sharedDomain.NewError(sharedDomain.CodeInvalidArgument, "invalid birth_date format, use YYYY-MM-DD")
+)
+
 // UserHandler encapsula los endpoints HTTP relacionados con User
 type UserHandler struct {
 	service *application.UserService
+	filters *sharedHttp.CriteriaBinder
 }
 
-// NewUserHandler crea un nuevo UserHandler
-func NewUserHandler(service *application.UserService) *UserHandler {
-	return &UserHandler{service: service}
+// NewUserHandler crea un nuevo UserHandler. 'clock' es la hora de referencia de los filtros
+// relativos (edad, created_within).
+func NewUserHandler(service *application.UserService, clock sharedDomain.Clock) *UserHandler {
+	return &UserHandler{service: service, filters: newUserFilters(clock)}
 }
 
 // ---------------- Handlers ----------------
@@ -31,28 +45,47 @@ func NewUserHandler(service *application.UserService) *UserHandler {
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req struct {
 		Email     string `json:"email" binding:"required,email"`
-		Nombre    string `json:"nombre" binding:"required"`
+		Name      string `json:"name"`
+		Nombre    string `json:"nombre"`                        // Alias obsoleto de name
 		BirthDate string `json:"birth_date" binding:"required"` // ISO8601, ej: 2000-01-01
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		sharedHttp.WriteError(c, sharedDomain.WrapError(sharedDomain.CodeInvalidArgument, err))
 		return
 	}
+	if req.Name == "" {
+		req.Name = req.Nombre
+	}
 
-	birthDate, err := time.Parse("2006-01-02", req.BirthDate)
+	user, err := h.createUser(c.Request.Context(), req.Email, req.Name, req.BirthDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid birth_date format, use YYYY-MM-DD"})
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
-	user, err := h.service.CreateUser(c.Request.Context(), req.Email, req.Nombre, birthDate)
+	c.JSON(http.StatusCreated, toUserResponse(user))
+}
+
+// createUser valida los campos de un alta, de POST /users o de una fila importada, y crea el
+// usuario.
+func (h *UserHandler) createUser(ctx context.Context, rawEmail, rawName, rawBirthDate string) (*userDomain.User, error) {
+	email, err := sanitize.Line("email", rawEmail, sanitize.MaxEmailLength)
 	if err != nil {
-		response.SendInternalServerError(c, err.Error())
-		return
+		return nil, err
 	}
-
-	c.JSON(http.StatusCreated, user)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, errInvalidEmail
+	}
+	name, err := sanitize.RequiredLine("name", rawName, sanitize.MaxNameLength)
+	if err != nil {
+		return nil, err
+	}
+	birthDate, err := time.Parse("2006-01-02", rawBirthDate)
+	if err != nil {
+		return nil, errInvalidBirthDate
+	}
+	return h.service.CreateUser(ctx, email, name, birthDate)
 }
 
 // GetUser endpoint GET /users/:id
@@ -60,74 +93,92 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		sharedHttp.WriteError(c, errInvalidUserID)
 		return
 	}
 
 	user, err := h.service.GetUser(c.Request.Context(), id)
 	if err != nil {
-		if err == userDomain.ErrUserNotFound {
-			response.SendNotFound(c, "user not found")
-			return
-		}
-		response.SendInternalServerError(c, err.Error())
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
-	response.SendSuccess(c, http.StatusOK, user)
+	if sharedHttp.NotModified(c, sharedHttp.VersionETag(user.Version)) {
+		return
+	}
+	response.SendSuccess(c, http.StatusOK, toUserResponse(user))
 }
 
 // UpdateUser endpoint PUT /users/:id
+// Con If-Match (el ETag de GET /users/:id) solo actualiza si el usuario sigue en esa versión y,
+// si no, responde 412; sin él, gana la última escritura.
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		sharedHttp.WriteError(c, errInvalidUserID)
+		return
+	}
+	version, conditional, err := sharedHttp.IfMatch(c)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
 	var req struct {
 		Email     *string `json:"email,omitempty"`
-		Nombre    *string `json:"nombre,omitempty"`
+		Name      *string `json:"name,omitempty"`
+		Nombre    *string `json:"nombre,omitempty"`     // Alias obsoleto de name
 		BirthDate *string `json:"birth_date,omitempty"` // ISO8601
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		sharedHttp.WriteError(c, sharedDomain.WrapError(sharedDomain.CodeInvalidArgument, err))
 		return
 	}
+	if req.Name == nil {
+		req.Name = req.Nombre
+	}
 
-	user, err := h.service.GetUser(c.Request.Context(), id)
+	user, err := h.service.GetUserForUpdate(c.Request.Context(), id)
 	if err != nil {
-		if err == userDomain.ErrUserNotFound {
-			response.SendNotFound(c, "user not found")
-			return
-		}
-		response.SendInternalServerError(c, err.Error())
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
 	if req.Email != nil {
-		user.Email = *req.Email
+		if user.Email, err = sanitize.Line("email", *req.Email, sanitize.MaxEmailLength); err != nil {
+			sharedHttp.WriteError(c, err)
+			return
+		}
 	}
-	if req.Nombre != nil {
-		user.Nombre = *req.Nombre
+	if req.Name != nil {
+		if user.Name, err = sanitize.RequiredLine("name", *req.Name, sanitize.MaxNameLength); err != nil {
+			sharedHttp.WriteError(c, err)
+			return
+		}
 	}
 	if req.BirthDate != nil {
 		bd, err := time.Parse("2006-01-02", *req.BirthDate)
 		if err != nil {
-			response.SendBadRequest(c, "invalid birth_date format")
+			sharedHttp.WriteError(c, errInvalidBirthDate)
 			return
 		}
 		user.BirthDate = bd
 	}
 
-	if err := h.service.UpdateUser(c.Request.Context(), user); err != nil {
-		response.SendInternalServerError(c, err.Error())
+	if conditional {
+		err = h.service.CompareAndUpdateUser(c.Request.Context(), user, version)
+	} else {
+		err = h.service.UpdateUser(c.Request.Context(), user)
+	}
+	if err != nil {
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
-	response.SendSuccess(c, http.StatusOK, user)
+	c.Header("ETag", sharedHttp.VersionETag(user.Version))
+	response.SendSuccess(c, http.StatusOK, toUserResponse(user))
 }
 
 // DeleteUser endpoint DELETE /users/:id
@@ -135,70 +186,88 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		sharedHttp.WriteError(c, errInvalidUserID)
 		return
 	}
 
 	if err := h.service.DeleteUser(c.Request.Context(), id); err != nil {
-		if err == userDomain.ErrUserNotFound {
-			response.SendNotFound(c, "user not found")
-			return
-		}
-		response.SendInternalServerError(c, err.Error())
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
-func (h *UserHandler) ListUsers(c *gin.Context) {
-	var criterias []sharedDomain.Criteria
-
-	// --- Filtros desde query params ---
-	if nombre := c.Query("nombre"); nombre != "" {
-		criterias = append(criterias, userDomain.NameLikeCriteria{Name: nombre})
-	}
-
-	if email := c.Query("email"); email != "" {
-		criterias = append(criterias, userDomain.EmailCriteria{Email: email})
-	}
+// newUserFilters declara los parámetros de GET /users por los que se puede filtrar. Los alias
+// heredados (nombre) se traducen antes con canonicalQuery.
+func newUserFilters(clock sharedDomain.Clock) *sharedHttp.CriteriaBinder {
+	return sharedHttp.NewCriteriaBinder(
+		sharedHttp.Filter{Param: "name", Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			return userDomain.NameLikeCriteria{Name: v.(string)}
+		}},
+		sharedHttp.Filter{Param: "email", Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			return userDomain.EmailCriteria{Email: v.(string)}
+		}},
+		sharedHttp.Filter{Param: "id", Type: sharedHttp.FilterUUID, Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			return userDomain.IDCriteria{ID: v.(uuid.UUID)}
+		}},
+		sharedHttp.Filter{Param: "min_age", Type: sharedHttp.FilterInt, Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			age := v.(int)
+			return userDomain.AgeRangeCriteria{Min: &age, Now: clock.Now()}
+		}},
+		sharedHttp.Filter{Param: "max_age", Type: sharedHttp.FilterInt, Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			age := v.(int)
+			return userDomain.AgeRangeCriteria{Max: &age, Now: clock.Now()}
+		}},
+		sharedHttp.Filter{Param: "created_at", Type: sharedHttp.FilterTime, Ops: []sharedDomain.Operator{sharedDomain.OpGte, sharedDomain.OpLte}},
+		sharedHttp.Filter{Param: "created_within", Type: sharedHttp.FilterDuration, Criteria: func(_ sharedDomain.Operator, v any) sharedDomain.Criteria {
+			return sharedDomain.CreatedWithinCriteria{Duration: v.(time.Duration), Clock: clock}
+		}},
+	)
+}
 
-	if idStr := c.Query("id"); idStr != "" {
-		if id, err := uuid.Parse(idStr); err == nil {
-			criterias = append(criterias, userDomain.IDCriteria{ID: id})
-		}
+// bindFilters lee los filtros de la query, con los alias heredados ya traducidos. Si alguno es
+// inválido responde 400 con el motivo de cada parámetro y devuelve false.
+func (h *UserHandler) bindFilters(c *gin.Context) (sharedDomain.Criteria, bool) {
+	criteria, err := h.filters.Bind(canonicalQuery(c.Request.URL.Query()))
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return nil, false
 	}
+	return criteria, true
+}
 
-	// Edad
-	var min, max *int
-	if minAge := c.Query("min_age"); minAge != "" {
-		if v, err := strconv.Atoi(minAge); err == nil {
-			min = &v
-		}
-	}
-	if maxAge := c.Query("max_age"); maxAge != "" {
-		if v, err := strconv.Atoi(maxAge); err == nil {
-			max = &v
-		}
-	}
-	if min != nil || max != nil {
-		criterias = append(criterias, userDomain.AgeRangeCriteria{Min: min, Max: max})
+// ListUsers endpoint GET /users con filtros, paginación y ordenamiento. Con ?ids=a,b,c devuelve
+// esos usuarios (ver getUsers).
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	if ids, ok := c.GetQuery("ids"); ok {
+		h.getUsers(c, ids)
+		return
 	}
 
-	criteria := sharedDomain.CompositeCriteria{
-		Operator:  sharedDomain.OpAnd,
-		Criterias: criterias,
+	// --- Filtros desde query params ---
+	criteria, ok := h.bindFilters(c)
+	if !ok {
+		return
 	}
 
 	// --- Sort ---
-	sortParam := sharedQuery.Sort{
+	// "sort" admite varios campos (ej. sort=name,-created_at); sort_field y sort_desc, uno solo.
+	sorts := []sharedQuery.Sort{{
 		Field: "created_at",
 		Desc:  true,
-	}
-	if sortField := c.Query("sort_field"); sortField != "" {
-		sortParam.Field = sortField
+	}}
+	if sortList := c.Query("sort"); sortList != "" {
+		parsed, err := sharedQuery.ParseSorts(sortList)
+		if err != nil {
+			sharedHttp.WriteError(c, err)
+			return
+		}
+		sorts = canonicalSorts(parsed)
+	} else if sortField := c.Query("sort_field"); sortField != "" {
+		sorts[0].Field = canonicalField(sortField)
 		if sortDesc := c.Query("sort_desc"); sortDesc == "true" {
-			sortParam.Desc = true
+			sorts[0].Desc = true
 		}
 	}
 
@@ -207,16 +276,17 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	limit := 50
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if v, err := strconv.Atoi(limitStr); err == nil {
-			limit = v
+			limit = sanitize.Limit(v, limit)
 		}
 	}
 
-	if cursor := c.Query("cursor"); cursor != "" {
+	// Con el parámetro cursor, aunque esté vacío (primera página), se pagina por cursor y la
+	// respuesta incluye next_cursor mientras queden páginas.
+	cursor, byCursor := c.GetQuery("cursor")
+	if byCursor {
 		pagination = sharedQuery.CursorPagination{
-			Limit:     limit,
-			Cursor:    cursor,
-			SortField: sortParam.Field,
-			SortDesc:  sortParam.Desc,
+			Limit:  limit,
+			Cursor: cursor,
 		}
 	} else {
 		offset := 0
@@ -231,11 +301,68 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		}
 	}
 
-	users, err := h.service.ListUsers(c.Request.Context(), criteria, pagination, sortParam)
+	page, err := h.service.ListUsersPage(c.Request.Context(), criteria, pagination, sorts)
 	if err != nil {
-		response.SendInternalServerError(c, err.Error())
+		// Un campo de ordenación o un cursor inválidos son un error del cliente (400).
+		sharedHttp.WriteError(c, err)
 		return
 	}
 
-	response.SendSuccess(c, http.StatusOK, users)
+	response.SendPage(c, http.StatusOK, toUserResponses(page.Items), response.PageMeta{
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+		Total:      page.Total,
+	})
+}
+
+// getUsers responde GET /users?ids=a,b,c: los usuarios que existen, en el orden pedido, en una
+// sola petición en vez de un GET /users/:id por usuario. Los que no existen no aparecen.
+func (h *UserHandler) getUsers(c *gin.Context, raw string) {
+	ids, err := sharedHttp.ParseIDs(raw)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+
+	users, err := h.service.GetUsers(c.Request.Context(), ids)
+	if err != nil {
+		sharedHttp.WriteError(c, err)
+		return
+	}
+	response.SendSuccess(c, http.StatusOK, toUserResponses(users))
+}
+
+// ---------------- Exportación e importación ----------------
+
+// userColumns son las columnas de GET /users/export. POST /users/import lee email, name (o el
+// alias nombre) y birth_date e ignora las demás, así que una exportación se puede importar.
+var userColumns = []string{"id", "email", "name", "birth_date", "created_at"}
+
+// ExportUsers endpoint GET /users/export?format=ndjson|csv: todos los usuarios que cumplen los
+// filtros de GET /users, por fecha de alta. El servidor los lee página a página, así que sirve
+// para listados que no caben en memoria.
+func (h *UserHandler) ExportUsers(c *gin.Context) {
+	format, ok := sharedHttp.DataFormat(c)
+	if !ok {
+		return
+	}
+	criteria, ok := h.bindFilters(c)
+	if !ok {
+		return
+	}
+	sharedHttp.Export(c, format, "users", userColumns, func(write func([]string) error) error {
+		return h.service.StreamUsers(c.Request.Context(), criteria, func(u *userDomain.User) error {
+			return write([]string{u.ID.String(), u.Email, u.Name, u.BirthDate.Format("2006-01-02"), u.CreatedAt.UTC().Format(time.RFC3339Nano)})
+		})
+	})
+}
+
+// ImportUsers endpoint POST /users/import?format=ndjson|csv: da de alta un usuario por fila, con
+// las mismas validaciones y eventos que POST /users. Responde con el número de altas y el error
+// de cada fila que no se pudo importar.
+func (h *UserHandler) ImportUsers(c *gin.Context) {
+	sharedHttp.Import(c, func(ctx context.Context, rec dataio.Record) error {
+		_, err := h.createUser(ctx, rec.Get("email"), rec.Get("name", "nombre"), rec.Get("birth_date"))
+		return err
+	})
 }
@@ -1,44 +0,0 @@
-package cache
-
-import (
-	"context"
-	"encoding/json"
-	"time"
-
-	"github.com/go-redis/redis/v8"
-)
-
-type RedisCache struct {
-	client *redis.Client
-	ttl    time.Duration
-}
-
-func NewRedisCache(client *redis.Client, ttl time.Duration) *RedisCache {
-	return &RedisCache{client: client, ttl: ttl}
-}
-
-func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
-	data, err := c.client.Get(ctx, key).Bytes()
-	if err != nil {
-		if err == redis.Nil {
-			return false, nil // cache miss
-		}
-		return false, err
-	}
-	if err := json.Unmarshal(data, dest); err != nil {
-		return false, err
-	}
-	return true, nil
-}
-
-func (c *RedisCache) Set(ctx context.Context, key string, val interface{}, ttlSecs int) error {
-	data, err := json.Marshal(val)
-	if err != nil {
-		return err
-	}
-	return c.client.Set(ctx, key, data, time.Duration(ttlSecs)*time.Second).Err()
-}
-
-func (c *RedisCache) Delete(ctx context.Context, key string) error {
-	return c.client.Del(ctx, key).Err()
-}
@@ -0,0 +1,282 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// EncryptedOutboxFields son los campos de los payloads de eventos de usuario que se cifran en el
+// outbox. El relayer los descifra antes de publicarlos (ver relayer.Worker.SetDecryption).
+var EncryptedOutboxFields = []string{"email", "birth_date"}
+
+// encryptedUserRepository decora un UserRepository cifrando el email y la fecha de nacimiento de
+// cada usuario antes de guardarlos y descifrándolos al leerlos, y cifrando los datos personales
+// de los payloads del outbox. La fecha cifrada viaja al repositorio en User.SealedBirthDate.
+type encryptedUserRepository struct {
+	next userDomain.UserRepository
+	keys *encryption.Keyring
+}
+
+// NewEncryptedUserRepository envuelve 'next' para cifrar los datos personales con 'keys'. Como el
+// cifrado es determinista, el índice único del email y los filtros por igualdad siguen
+// funcionando; filtrar por email con LIKE, por rango de fechas de nacimiento (AgeRangeCriteria) u
+// ordenar por cualquiera de los dos deja de tener sentido y es un sharedDomain.ErrInvalidCriteria.
+// Los usuarios guardados con una clave antigua se vuelven a cifrar con la activa la próxima vez
+// que se actualizan; los guardados antes de activar el cifrado los cifra EncryptBirthDates.
+func NewEncryptedUserRepository(next userDomain.UserRepository, keys *encryption.Keyring) userDomain.UserRepository {
+	return &encryptedUserRepository{next: next, keys: keys}
+}
+
+func (r *encryptedUserRepository) Create(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
+	if err := r.checkEmailFree(ctx, u); err != nil {
+		return err
+	}
+	evt, err := r.sealOutbox(evt)
+	if err != nil {
+		return err
+	}
+	return r.next.Create(ctx, r.seal(u), evt)
+}
+
+func (r *encryptedUserRepository) Update(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
+	if err := r.checkEmailFree(ctx, u); err != nil {
+		return err
+	}
+	evt, err := r.sealOutbox(evt)
+	if err != nil {
+		return err
+	}
+	return r.next.Update(ctx, r.seal(u), evt)
+}
+
+func (r *encryptedUserRepository) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	evt, err := r.sealOutbox(evt)
+	if err != nil {
+		return err
+	}
+	return r.next.DeleteByID(ctx, id, evt)
+}
+
+func (r *encryptedUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	u, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(u)
+}
+
+func (r *encryptedUserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*userDomain.User, error) {
+	users, err := r.next.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return r.openAll(users)
+}
+
+func (r *encryptedUserRepository) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*userDomain.User, error) {
+	for _, s := range sorts {
+		if encryptedFields[s.Field] {
+			return nil, &sharedDomain.CriteriaError{Field: s.Field, Reason: "is encrypted and cannot be sorted"}
+		}
+	}
+	criteria, err := r.sealCriteria(criteria)
+	if err != nil {
+		return nil, err
+	}
+	users, err := r.next.ListByCriteria(ctx, criteria, pagination, sorts)
+	if err != nil {
+		return nil, err
+	}
+	return r.openAll(users)
+}
+
+func (r *encryptedUserRepository) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	criteria, err := r.sealCriteria(criteria)
+	if err != nil {
+		return 0, err
+	}
+	return r.next.CountByCriteria(ctx, criteria)
+}
+
+// checkEmailFree comprueba, mientras dura una rotación, que el email de 'u' no lo tiene ya otro
+// usuario guardado con una clave antigua: el índice único solo ve los cifrados con la misma clave.
+func (r *encryptedUserRepository) checkEmailFree(ctx context.Context, u *userDomain.User) error {
+	if !r.keys.Rotating() {
+		return nil
+	}
+	owners, err := r.next.ListByCriteria(ctx, r.equals("email", u.Email), sharedQuery.OffsetPagination{Limit: 2}, nil)
+	if err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		if owner.ID != u.ID {
+			return userDomain.ErrUserAlreadyExists
+		}
+	}
+	return nil
+}
+
+// seal devuelve una copia de 'u' con el email y la fecha de nacimiento cifrados con la clave
+// activa.
+func (r *encryptedUserRepository) seal(u *userDomain.User) *userDomain.User {
+	sealed := *u
+	sealed.Email = r.keys.Encrypt(u.Email)
+	sealed.BirthDate = time.Time{}
+	sealed.SealedBirthDate = r.keys.Encrypt(formatBirthDate(u.BirthDate))
+	return &sealed
+}
+
+// open descifra el email y la fecha de nacimiento de 'u', que el repositorio acaba de leer. Una
+// fecha sin cifrar (guardada antes de activar el cifrado) ya viene en BirthDate.
+func (r *encryptedUserRepository) open(u *userDomain.User) (*userDomain.User, error) {
+	email, err := r.keys.Decrypt(u.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt user %s: %w", u.ID, err)
+	}
+	u.Email = email
+	if u.SealedBirthDate == "" {
+		return u, nil
+	}
+	birthDate, err := r.keys.Decrypt(u.SealedBirthDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt user %s: %w", u.ID, err)
+	}
+	if u.BirthDate, err = time.Parse(time.RFC3339, birthDate); err != nil {
+		return nil, fmt.Errorf("failed to decrypt user %s: invalid birth_date: %w", u.ID, err)
+	}
+	u.SealedBirthDate = ""
+	return u, nil
+}
+
+func (r *encryptedUserRepository) openAll(users []*userDomain.User) ([]*userDomain.User, error) {
+	for _, u := range users {
+		if _, err := r.open(u); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// sealOutbox cifra los EncryptedOutboxFields del payload de 'evt', respetando su compresión.
+func (r *encryptedUserRepository) sealOutbox(evt sharedDomain.OutboxEvent) (sharedDomain.OutboxEvent, error) {
+	data, err := compression.OutboxPayload(evt)
+	if err != nil {
+		return evt, err
+	}
+	sealed, err := r.keys.EncryptJSON(data, EncryptedOutboxFields...)
+	if err != nil {
+		return evt, fmt.Errorf("failed to encrypt outbox payload: %w", err)
+	}
+
+	encoding := evt.Metadata[compression.Header]
+	if encoding == "" {
+		evt.Payload = json.RawMessage(sealed)
+		return evt, nil
+	}
+	compressed, _, err := compression.Options{Encoding: encoding}.Compress(sealed)
+	if err != nil {
+		return evt, err
+	}
+	evt.Payload = base64.StdEncoding.EncodeToString(compressed)
+	return evt, nil
+}
+
+// sealCriteria reescribe los filtros por email y fecha de nacimiento para que comparen con el
+// valor cifrado con cada clave del anillo. Solo admite la igualdad: los demás operadores no sirven
+// sobre texto cifrado.
+func (r *encryptedUserRepository) sealCriteria(criteria sharedDomain.Criteria) (sharedDomain.Criteria, error) {
+	switch c := criteria.(type) {
+	case nil:
+		return nil, nil
+	case sharedDomain.CompositeCriteria:
+		sealed := sharedDomain.CompositeCriteria{Operator: c.Operator, Criterias: make([]sharedDomain.Criteria, len(c.Criterias))}
+		for i, crit := range c.Criterias {
+			var err error
+			if sealed.Criterias[i], err = r.sealCriteria(crit); err != nil {
+				return nil, err
+			}
+		}
+		return sealed, nil
+	case sharedDomain.NotCriteria:
+		inner, err := r.sealCriteria(c.Criteria)
+		if err != nil {
+			return nil, err
+		}
+		return sharedDomain.Not(inner), nil
+	}
+
+	conditions := criteria.ToConditions()
+	touchesEncrypted := false
+	for _, cond := range conditions {
+		touchesEncrypted = touchesEncrypted || encryptedFields[cond.Field]
+	}
+	if !touchesEncrypted {
+		return criteria, nil
+	}
+
+	// Un criterio simple equivale al AND de sus condiciones.
+	sealed := make([]sharedDomain.Criteria, len(conditions))
+	for i, cond := range conditions {
+		if !encryptedFields[cond.Field] {
+			sealed[i] = condition(cond)
+			continue
+		}
+		plaintext, ok := plainValue(cond.Field, cond.Value)
+		if cond.Op != sharedDomain.OpEq || !ok {
+			return nil, &sharedDomain.CriteriaError{Field: cond.Field, Reason: fmt.Sprintf("is encrypted and only supports %s", sharedDomain.OpEq)}
+		}
+		sealed[i] = r.equals(cond.Field, plaintext)
+	}
+	return sharedDomain.And(sealed...), nil
+}
+
+// equals filtra los usuarios cuyo campo 'field' vale 'plaintext', cifrado con cualquiera de las
+// claves.
+func (r *encryptedUserRepository) equals(field, plaintext string) sharedDomain.Criteria {
+	var anyKey []sharedDomain.Criteria
+	for _, value := range r.keys.EncryptAll(plaintext) {
+		anyKey = append(anyKey, condition{Field: field, Op: sharedDomain.OpEq, Value: value})
+	}
+	return sharedDomain.Or(anyKey...)
+}
+
+// encryptedFields son los campos que se guardan cifrados.
+var encryptedFields = map[string]bool{"email": true, "birth_date": true}
+
+// plainValue devuelve el texto que se cifra para el valor de un filtro por 'field': el email tal
+// cual y la fecha de nacimiento en el formato de formatBirthDate.
+func plainValue(field string, value any) (string, bool) {
+	if field == "birth_date" {
+		t, ok := value.(time.Time)
+		return formatBirthDate(t), ok
+	}
+	s, ok := value.(string)
+	return s, ok
+}
+
+// formatBirthDate es el texto de la fecha de nacimiento que se cifra: el mismo que guardan en
+// claro los repositorios SQL, para que EncryptBirthDates pueda cifrar sus filas sin convertirlas.
+func formatBirthDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// condition es un criterio de una sola condición, ya reescrita.
+type condition sharedDomain.Criterion
+
+func (c condition) ToConditions() []sharedDomain.Criterion {
+	return []sharedDomain.Criterion{sharedDomain.Criterion(c)}
+}
+
+// Verificación estática
+var _ userDomain.UserRepository = (*encryptedUserRepository)(nil)
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userMemory "github.com/davicafu/hexagolab/internal/user/infra/outbound/db/memory"
+)
+
+func newTestKeyring(t *testing.T, ids ...string) *encryption.Keyring {
+	t.Helper()
+	specs := make([]string, len(ids))
+	for i, id := range ids {
+		specs[i] = id + ":" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat(id[len(id)-1:], 32)))
+	}
+	keys, err := encryption.NewKeyring(specs)
+	require.NoError(t, err)
+	return keys
+}
+
+func newUserEvent(u *userDomain.User) sharedDomain.OutboxEvent {
+	return sharedDomain.OutboxEvent{ID: uuid.New(), AggregateID: u.ID.String(), EventType: userDomain.UserCreated, Payload: userDomain.NewUserPayload(u)}
+}
+
+func TestEncryptedUserRepository_EncryptsAtRest(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	outbox := sharedMemory.NewOutboxRepoMemory()
+	plain := userMemory.NewUserRepoMemory(outbox)
+	repo := NewEncryptedUserRepository(plain, newTestKeyring(t, "k1"))
+	u := &userDomain.User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana", BirthDate: time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	require.NoError(t, repo.Create(ctx, u, newUserEvent(u)))
+	stored, err := plain.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+	read, err := repo.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+	pending, err := outbox.FetchPendingOutbox(ctx, 10)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, "ana@example.com", u.Email, "No modifica el usuario del llamante")
+	assert.True(t, encryption.IsEncrypted(stored.Email))
+	assert.True(t, encryption.IsEncrypted(stored.SealedBirthDate))
+	assert.True(t, stored.BirthDate.IsZero(), "La fecha de nacimiento solo se guarda cifrada")
+	assert.Equal(t, "ana@example.com", read.Email)
+	assert.True(t, u.BirthDate.Equal(read.BirthDate))
+	assert.Empty(t, read.SealedBirthDate)
+	require.Len(t, pending, 1)
+	payload, err := json.Marshal(pending[0].Payload)
+	require.NoError(t, err)
+	assert.NotContains(t, string(payload), "ana@example.com")
+	assert.NotContains(t, string(payload), "1990-05-01")
+	assert.Contains(t, string(payload), `"name":"Ana"`)
+}
+
+func TestEncryptedUserRepository_Criteria(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewEncryptedUserRepository(userMemory.NewUserRepoMemory(sharedMemory.NewOutboxRepoMemory()), newTestKeyring(t, "k1"))
+	birthDate := time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)
+	ana := &userDomain.User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana", BirthDate: birthDate}
+	bea := &userDomain.User{ID: uuid.New(), Email: "bea@example.com", Name: "Bea", BirthDate: birthDate.AddDate(1, 0, 0)}
+	require.NoError(t, repo.Create(ctx, ana, newUserEvent(ana)))
+	require.NoError(t, repo.Create(ctx, bea, newUserEvent(bea)))
+	maxAge := 40
+
+	// Act
+	found, err := repo.ListByCriteria(ctx, userDomain.EmailCriteria{Email: "bea@example.com"}, nil, nil)
+	require.NoError(t, err)
+	count, err := repo.CountByCriteria(ctx, sharedDomain.Not(userDomain.EmailCriteria{Email: "bea@example.com"}))
+	require.NoError(t, err)
+	born, err := repo.ListByCriteria(ctx, sharedDomain.And(condition{Field: "birth_date", Op: sharedDomain.OpEq, Value: birthDate}), nil, nil)
+	require.NoError(t, err)
+	_, likeErr := repo.ListByCriteria(ctx, sharedDomain.And(condition{Field: "email", Op: sharedDomain.OpLike, Value: "%bea%"}), nil, nil)
+	_, sortErr := repo.ListByCriteria(ctx, nil, nil, []sharedQuery.Sort{{Field: "email"}})
+	_, ageErr := repo.ListByCriteria(ctx, userDomain.AgeRangeCriteria{Max: &maxAge}, nil, nil)
+	_, birthSortErr := repo.ListByCriteria(ctx, nil, nil, []sharedQuery.Sort{{Field: "birth_date"}})
+
+	// Assert
+	require.Len(t, found, 1)
+	assert.Equal(t, "bea@example.com", found[0].Email)
+	assert.Equal(t, 1, count)
+	require.Len(t, born, 1)
+	assert.Equal(t, "Ana", born[0].Name)
+	assert.ErrorIs(t, likeErr, sharedDomain.ErrInvalidCriteria)
+	assert.ErrorIs(t, sortErr, sharedDomain.ErrInvalidCriteria)
+	assert.ErrorIs(t, ageErr, sharedDomain.ErrInvalidCriteria)
+	assert.ErrorIs(t, birthSortErr, sharedDomain.ErrInvalidCriteria)
+}
+
+// TestEncryptedUserRepository_SQLiteRawRow comprueba contra un SQLite real que en la tabla no
+// queda ni el email ni la fecha de nacimiento en claro, también en las filas guardadas antes de
+// activar el cifrado, que cifra EncryptBirthDates.
+func TestEncryptedUserRepository_SQLiteRawRow(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	conns := platformDB.NewConnections(platformDB.Options{SQLitePath: filepath.Join(t.TempDir(), "users.db")})
+	t.Cleanup(func() { conns.Close(ctx) })
+	stores, err := NewStores(ctx, platformDB.BackendSQLite, conns)
+	require.NoError(t, err)
+	db, err := conns.SQLite(ctx)
+	require.NoError(t, err)
+	keys := newTestKeyring(t, "k1")
+	legacy := &userDomain.User{ID: uuid.New(), Email: "luis@example.com", Name: "Luis", BirthDate: time.Date(1985, 3, 2, 0, 0, 0, 0, time.UTC)}
+	require.NoError(t, stores.Users.Create(ctx, legacy, newUserEvent(legacy)))
+	ana := &userDomain.User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana", BirthDate: time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)}
+	rawBirthDate := func(id uuid.UUID) string {
+		var birthDate string
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT birth_date FROM users WHERE id = ?", id.String()).Scan(&birthDate))
+		return birthDate
+	}
+
+	// Act
+	sealed, err := EncryptBirthDates(ctx, platformDB.BackendSQLite, conns, keys)
+	require.NoError(t, err)
+	again, err := EncryptBirthDates(ctx, platformDB.BackendSQLite, conns, keys)
+	require.NoError(t, err)
+	repo := NewEncryptedUserRepository(stores.Users, keys)
+	require.NoError(t, repo.Create(ctx, ana, newUserEvent(ana)))
+	var rawEmail string
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT email FROM users WHERE id = ?", ana.ID.String()).Scan(&rawEmail))
+	readLegacy, err := repo.GetByID(ctx, legacy.ID)
+	require.NoError(t, err)
+	readAna, err := repo.GetByID(ctx, ana.ID)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 1, sealed)
+	assert.Equal(t, 0, again, "Las filas ya cifradas no se tocan")
+	assert.True(t, encryption.IsEncrypted(rawEmail))
+	for _, u := range []*userDomain.User{legacy, ana} {
+		raw := rawBirthDate(u.ID)
+		assert.True(t, encryption.IsEncrypted(raw), raw)
+		assert.NotContains(t, raw, u.BirthDate.Format(time.DateOnly))
+	}
+	assert.True(t, legacy.BirthDate.Equal(readLegacy.BirthDate))
+	assert.True(t, ana.BirthDate.Equal(readAna.BirthDate))
+	assert.Equal(t, "ana@example.com", readAna.Email)
+}
+
+func TestEncryptedUserRepository_KeyRotation(t *testing.T) {
+	// Arrange: Ana se guardó con k1; ahora la activa es k2.
+	ctx := context.Background()
+	plain := userMemory.NewUserRepoMemory(sharedMemory.NewOutboxRepoMemory())
+	ana := &userDomain.User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana"}
+	require.NoError(t, NewEncryptedUserRepository(plain, newTestKeyring(t, "k1")).Create(ctx, ana, newUserEvent(ana)))
+	repo := NewEncryptedUserRepository(plain, newTestKeyring(t, "k2", "k1"))
+
+	// Act
+	found, err := repo.ListByCriteria(ctx, userDomain.EmailCriteria{Email: "ana@example.com"}, nil, nil)
+	require.NoError(t, err)
+	impostor := &userDomain.User{ID: uuid.New(), Email: "ana@example.com"}
+	dupErr := repo.Create(ctx, impostor, newUserEvent(impostor))
+	ana.Version++
+	require.NoError(t, repo.Update(ctx, ana, newUserEvent(ana)))
+	stored, err := plain.GetByID(ctx, ana.ID)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, found, 1, "Se encuentra con cualquiera de las claves del anillo")
+	assert.ErrorIs(t, dupErr, userDomain.ErrUserAlreadyExists)
+	assert.True(t, strings.HasPrefix(stored.Email, encryption.Prefix+"k2:"), "Al actualizarlo se vuelve a cifrar con la clave activa")
+}
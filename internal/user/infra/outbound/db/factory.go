@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/postgres"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlite"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userMemory "github.com/davicafu/hexagolab/internal/user/infra/outbound/db/memory"
+	userPostgres "github.com/davicafu/hexagolab/internal/user/infra/outbound/db/postgre"
+	userSQLite "github.com/davicafu/hexagolab/internal/user/infra/outbound/db/sqlite"
+)
+
+// Stores agrupa el repositorio de usuarios y el outbox que vive en su mismo almacenamiento.
+type Stores struct {
+	Users  userDomain.UserRepository
+	Outbox sharedDomain.OutboxRepository
+}
+
+// NewStores construye los adaptadores del dominio de usuarios para el backend indicado
+// (sqlite, postgres o memory), inicializando su esquema.
+// Antes de devolverlos se comprueba que cada adaptador recibió el almacenamiento que espera
+// (ver preflight). El repositorio se devuelve instrumentado para exponer sus latencias en /metrics.
+func NewStores(ctx context.Context, backend string, conns *platformDB.Connections) (Stores, error) {
+	stores, err := newStores(ctx, backend, conns)
+	if err != nil {
+		return Stores{}, err
+	}
+	if err := preflight.Run(ctx, "user", stores.Users, stores.Outbox); err != nil {
+		return Stores{}, err
+	}
+	stores.Users = NewInstrumentedUserRepository(stores.Users, backend)
+	return stores, nil
+}
+
+// EncryptBirthDates cifra con 'keys' las fechas de nacimiento que el backend guardó en claro
+// antes de activar el cifrado (ver NewEncryptedUserRepository), en todos los inquilinos, y
+// devuelve cuántas ha cifrado. El esquema ya tiene que estar inicializado (NewStores). En memoria
+// no hay datos anteriores que cifrar.
+func EncryptBirthDates(ctx context.Context, backend string, conns *platformDB.Connections, keys *encryption.Keyring) (int, error) {
+	switch backend {
+	case platformDB.BackendSQLite:
+		db, err := conns.SQLite(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return sqlrepo.EncryptColumn(ctx, db, sqlite.Engine, "users", "birth_date", keys)
+
+	case platformDB.BackendPostgres:
+		db, err := conns.Postgres(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return sqlrepo.EncryptColumn(ctx, db, postgres.Engine, "users", "birth_date", keys)
+
+	default:
+		return 0, nil
+	}
+}
+
+func newStores(ctx context.Context, backend string, conns *platformDB.Connections) (Stores, error) {
+	switch backend {
+	case platformDB.BackendSQLite:
+		db, err := conns.SQLite(ctx)
+		if err != nil {
+			return Stores{}, err
+		}
+		if err := userSQLite.InitSQLite(db); err != nil {
+			return Stores{}, fmt.Errorf("failed to initialize SQLite user schema: %w", err)
+		}
+		return Stores{Users: userSQLite.NewUserRepoSQLite(db), Outbox: sqlite.NewOutboxRepoSQLite(db)}, nil
+
+	case platformDB.BackendPostgres:
+		db, err := conns.Postgres(ctx)
+		if err != nil {
+			return Stores{}, err
+		}
+		if err := userPostgres.InitPostgres(db); err != nil {
+			return Stores{}, fmt.Errorf("failed to initialize Postgres user schema: %w", err)
+		}
+		return Stores{Users: userPostgres.NewUserRepoPostgres(db), Outbox: postgres.NewOutboxRepoPostgres(db)}, nil
+
+	case platformDB.BackendMemory:
+		outbox := sharedMemory.NewOutboxRepoMemory()
+		return Stores{Users: userMemory.NewUserRepoMemory(outbox), Outbox: outbox}, nil
+
+	default:
+		return Stores{}, fmt.Errorf("unsupported user storage backend: %q", backend)
+	}
+}
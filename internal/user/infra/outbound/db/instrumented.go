@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/querylog"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/metrics"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// instrumentedUserRepository decora un UserRepository midiendo la latencia de cada método.
+type instrumentedUserRepository struct {
+	next    userDomain.UserRepository
+	backend string
+}
+
+// NewInstrumentedUserRepository envuelve 'next' para exponer sus latencias en /metrics.
+func NewInstrumentedUserRepository(next userDomain.UserRepository, backend string) userDomain.UserRepository {
+	return &instrumentedUserRepository{next: next, backend: backend}
+}
+
+// tag etiqueta las consultas SQL del método para el log de consultas lentas.
+func (r *instrumentedUserRepository) tag(ctx context.Context, method, criteria string) context.Context {
+	return querylog.WithTag(ctx, querylog.Tag{Repo: "user", Method: method, Criteria: criteria})
+}
+
+func (r *instrumentedUserRepository) observe(method string, start time.Time, err error) {
+	metrics.ObserveDBQuery("user", r.backend, method, start, err)
+}
+
+func (r *instrumentedUserRepository) Create(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
+	ctx = r.tag(ctx, "Create", "")
+	start := time.Now()
+	err := r.next.Create(ctx, u, evt)
+	r.observe("Create", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) Update(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
+	ctx = r.tag(ctx, "Update", "")
+	start := time.Now()
+	err := r.next.Update(ctx, u, evt)
+	r.observe("Update", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	ctx = r.tag(ctx, "GetByID", "")
+	start := time.Now()
+	u, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return u, err
+}
+
+func (r *instrumentedUserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*userDomain.User, error) {
+	ctx = r.tag(ctx, "GetByIDs", "")
+	start := time.Now()
+	users, err := r.next.GetByIDs(ctx, ids)
+	r.observe("GetByIDs", start, err)
+	return users, err
+}
+
+func (r *instrumentedUserRepository) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*userDomain.User, error) {
+	ctx = r.tag(ctx, "ListByCriteria", querylog.SummarizeCriteria(criteria, sorts))
+	start := time.Now()
+	users, err := r.next.ListByCriteria(ctx, criteria, pagination, sorts)
+	r.observe("ListByCriteria", start, err)
+	return users, err
+}
+
+func (r *instrumentedUserRepository) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	ctx = r.tag(ctx, "CountByCriteria", querylog.SummarizeCriteria(criteria, nil))
+	start := time.Now()
+	total, err := r.next.CountByCriteria(ctx, criteria)
+	r.observe("CountByCriteria", start, err)
+	return total, err
+}
+
+func (r *instrumentedUserRepository) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	ctx = r.tag(ctx, "DeleteByID", "")
+	start := time.Now()
+	err := r.next.DeleteByID(ctx, id, evt)
+	r.observe("DeleteByID", start, err)
+	return err
+}
+
+// Verificación estática de la interfaz.
+var _ userDomain.UserRepository = (*instrumentedUserRepository)(nil)
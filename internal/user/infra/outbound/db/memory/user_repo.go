@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// UserRepoMemory implementa la interfaz UserRepository en memoria, para el modo demo.
+// Los datos se pierden al parar el proceso. Como en SQL, cada operación solo ve los usuarios del
+// inquilino de ctx.
+type UserRepoMemory struct {
+	mu     sync.RWMutex
+	users  []userRow // En orden de inserción, para que los empates al ordenar sean estables
+	outbox *sharedMemory.OutboxRepoMemory
+}
+
+// userRow es un usuario guardado junto a su inquilino.
+type userRow struct {
+	userDomain.User
+	tenant string
+}
+
+// NewUserRepoMemory es el constructor del repositorio. Los eventos se guardan en 'outbox'.
+func NewUserRepoMemory(outbox *sharedMemory.OutboxRepoMemory) *UserRepoMemory {
+	return &UserRepoMemory{outbox: outbox}
+}
+
+// Create guarda un usuario en el inquilino de ctx y su evento. Como en SQL, el ID es único entre
+// todos los inquilinos y el email dentro de cada uno.
+func (r *UserRepoMemory) Create(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.users, func(row userRow) bool { return row.ID == u.ID }) || r.emailTaken(ctx, u.Email, u.ID) {
+		return userDomain.ErrUserAlreadyExists
+	}
+	if err := r.outbox.Add(ctx, evt); err != nil {
+		return err
+	}
+	r.users = append(r.users, userRow{User: *u, tenant: tracing.TenantID(ctx)})
+	return nil
+}
+
+// GetByID recupera un usuario por su ID.
+func (r *UserRepoMemory) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return nil, userDomain.ErrUserNotFound
+	}
+	u := r.users[i].User
+	return &u, nil
+}
+
+// GetByIDs recupera los usuarios de 'ids' que existen.
+func (r *UserRepoMemory) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*userDomain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []*userDomain.User
+	for _, id := range ids {
+		if i := r.indexOf(ctx, id); i >= 0 {
+			u := r.users[i].User
+			users = append(users, &u)
+		}
+	}
+	return users, nil
+}
+
+// Update actualiza un usuario, si la versión guardada es la anterior a la suya, y guarda su evento.
+func (r *UserRepoMemory) Update(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, u.ID)
+	if i < 0 {
+		return userDomain.ErrUserNotFound
+	}
+	if r.users[i].Version != u.Version-1 {
+		return userDomain.ErrUserVersionConflict
+	}
+	if r.emailTaken(ctx, u.Email, u.ID) {
+		return userDomain.ErrUserAlreadyExists
+	}
+	if err := r.outbox.Add(ctx, evt); err != nil {
+		return err
+	}
+	// Como en SQL, la fecha de creación no se modifica.
+	updated := *u
+	updated.CreatedAt = r.users[i].CreatedAt
+	r.users[i].User = updated
+	return nil
+}
+
+// DeleteByID elimina un usuario y guarda su evento.
+func (r *UserRepoMemory) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return userDomain.ErrUserNotFound
+	}
+	if err := r.outbox.Add(ctx, evt); err != nil {
+		return err
+	}
+	r.users = slices.Delete(r.users, i, i+1)
+	return nil
+}
+
+// ListByCriteria recupera una lista de usuarios aplicando filtros, paginación y ordenamiento.
+func (r *UserRepoMemory) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*userDomain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found, err := sharedMemory.Query(r.users, rowField, sharedQuery.TenantScope(ctx, criteria), pagination, sorts)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*userDomain.User, len(found))
+	for i := range found {
+		users[i] = &found[i].User
+	}
+	return users, nil
+}
+
+// CountByCriteria cuenta los usuarios que cumplen 'criteria'.
+func (r *UserRepoMemory) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sharedMemory.Count(r.users, rowField, sharedQuery.TenantScope(ctx, criteria))
+}
+
+// indexOf devuelve la posición del usuario 'id' del inquilino de ctx, o -1.
+func (r *UserRepoMemory) indexOf(ctx context.Context, id uuid.UUID) int {
+	tenant := tracing.TenantID(ctx)
+	return slices.IndexFunc(r.users, func(row userRow) bool { return row.ID == id && row.tenant == tenant })
+}
+
+// emailTaken indica si otro usuario del inquilino de ctx distinto de 'id' ya usa el email.
+func (r *UserRepoMemory) emailTaken(ctx context.Context, email string, id uuid.UUID) bool {
+	tenant := tracing.TenantID(ctx)
+	return slices.ContainsFunc(r.users, func(row userRow) bool {
+		return row.tenant == tenant && row.ID != id && strings.EqualFold(row.Email, email)
+	})
+}
+
+// rowField expone los campos del usuario (userField) y la columna del inquilino.
+func rowField(row userRow, field string) (any, bool) {
+	if field == sharedQuery.TenantColumn {
+		return row.tenant, true
+	}
+	return userField(row.User, field)
+}
+
+// userField expone los campos del usuario con los nombres de las columnas SQL. Como en SQL, la
+// fecha de nacimiento cifrada se compara por su texto.
+func userField(u userDomain.User, field string) (any, bool) {
+	switch field {
+	case "id":
+		return u.ID, true
+	case "email":
+		return u.Email, true
+	case "name":
+		return u.Name, true
+	case "birth_date":
+		if u.SealedBirthDate != "" {
+			return u.SealedBirthDate, true
+		}
+		return u.BirthDate, true
+	case "created_at":
+		return u.CreatedAt, true
+	}
+	return nil, false
+}
+
+// Verificación estática de la interfaz.
+var _ userDomain.UserRepository = (*UserRepoMemory)(nil)
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// userFields son las columnas de users por las que se puede filtrar y ordenar (userDomain.UserFields),
+// más la del inquilino, que añade sharedQuery.TenantScope.
+var userFields = sharedQuery.NewFields(append(userDomain.UserFields.Names(), sharedQuery.TenantColumn)...)
+
+// userMapper traduce los usuarios a y desde la tabla users de Postgres (ver sqlrepo.Mapper). La
+// fecha de nacimiento se guarda como texto, para que quepa cifrada si el usuario trae
+// User.SealedBirthDate.
+type userMapper struct{}
+
+func (userMapper) Table() sqlrepo.Table {
+	return sqlrepo.Table{
+		Name:            "users",
+		Columns:         []string{"id", "email", "name", "birth_date", "created_at", "version"},
+		Immutable:       []string{"created_at"},
+		Fields:          userFields,
+		NotFound:        userDomain.ErrUserNotFound,
+		Conflict:        userDomain.ErrUserAlreadyExists,
+		Version:         "version",
+		VersionConflict: userDomain.ErrUserVersionConflict,
+	}
+}
+
+func (userMapper) Values(u *userDomain.User) []any {
+	return []any{u.ID, u.Email, u.Name, birthDate(u), u.CreatedAt, u.Version}
+}
+
+// Scan lee una fila de users. El id (UUID) y la fecha de creación (TIMESTAMP) se leen en sus
+// tipos nativos; la fecha de nacimiento, en texto, se parsea o, cifrada, se devuelve tal cual en
+// User.SealedBirthDate.
+func (userMapper) Scan(row sqlrepo.Scanner) (*userDomain.User, error) {
+	var u userDomain.User
+	var birthDateStr string
+	if err := row.Scan(&u.ID, &u.Email, &u.Name, &birthDateStr, &u.CreatedAt, &u.Version); err != nil {
+		return nil, err
+	}
+	if encryption.IsEncrypted(birthDateStr) {
+		u.SealedBirthDate = birthDateStr
+		return &u, nil
+	}
+	var err error
+	if u.BirthDate, err = time.Parse(time.RFC3339, birthDateStr); err != nil {
+		return nil, fmt.Errorf("error parsing birth_date: %w", err)
+	}
+	return &u, nil
+}
+
+// Arg pasa los UUID y las fechas como texto, que Postgres convierte al tipo de la columna. Las
+// fechas van en el formato de birth_date, para que la comparación de textos respete el orden
+// cronológico.
+func (userMapper) Arg(value any) any {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v.String()
+	case time.Time:
+		return formatTime(v)
+	default:
+		return v
+	}
+}
+
+// birthDate es el valor de la columna birth_date: la fecha cifrada o, si no la hay, en texto.
+func birthDate(u *userDomain.User) string {
+	if u.SealedBirthDate != "" {
+		return u.SealedBirthDate
+	}
+	return formatTime(u.BirthDate)
+}
+
+// formatTime serializa las fechas en UTC, como en la columna birth_date.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Verificación estática
+var _ sqlrepo.Mapper[userDomain.User] = userMapper{}
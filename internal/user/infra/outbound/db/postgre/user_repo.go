@@ -1,244 +1,70 @@
 package postgres
 
 import (
-	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"strings"
 
-	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
-	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
-	sharedUtils "github.com/davicafu/hexagolab/internal/shared/infra/utils"
+	sharedPostgres "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/postgres"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
-	"github.com/google/uuid"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// UserRepoPostgres implementa la interfaz UserRepository para PostgreSQL sobre la base común de
+// los repositorios SQL (ver userMapper).
 type UserRepoPostgres struct {
-	db *sql.DB
+	*sqlrepo.Repository[userDomain.User]
 }
 
 func NewUserRepoPostgres(db *sql.DB) *UserRepoPostgres {
-	return &UserRepoPostgres{db: db}
-}
-
-// ------------------ Helper DRY para insertar en outbox ------------------
-
-func insertOutboxTx(ctx context.Context, tx *sql.Tx, evt sharedDomain.OutboxEvent) error {
-	payloadBytes, err := json.Marshal(evt.Payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal outbox payload: %w", err)
-	}
-
-	_, err = tx.ExecContext(ctx,
-		`INSERT INTO outbox (id, aggregate_type, aggregate_id, event_type, payload, created_at, processed)
-		 VALUES ($1, $2, $3, $4, $5, $6, false)`,
-		evt.ID, evt.AggregateType, evt.AggregateID, evt.EventType, payloadBytes, evt.CreatedAt,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to insert outbox event: %w", err)
-	}
-	return nil
-}
-
-// ------------------ CRUD + Outbox ------------------
-
-// Create inserta usuario y evento en transacción
-func (r *UserRepoPostgres) Create(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	_, err = tx.ExecContext(ctx,
-		`INSERT INTO users (id, email, nombre, birth_date, created_at)
-		 VALUES ($1, $2, $3, $4, $5)`,
-		u.ID, u.Email, u.Nombre, u.BirthDate, u.CreatedAt,
-	)
-	if err != nil {
-		return err
-	}
-
-	if err := insertOutboxTx(ctx, tx, evt); err != nil {
-		return err
-	}
-
-	return tx.Commit()
-}
-
-// Update actualiza usuario y crea evento en transacción
-func (r *UserRepoPostgres) Update(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin tx: %w", err)
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	res, err := tx.ExecContext(ctx,
-		`UPDATE users SET email=$1, nombre=$2, birth_date=$3 WHERE id=$4`,
-		u.Email, u.Nombre, u.BirthDate, u.ID,
-	)
-	if err != nil {
-		return fmt.Errorf("db error: %w", err)
-	}
-
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return userDomain.ErrUserNotFound
-	}
-
-	if err := insertOutboxTx(ctx, tx, evt); err != nil {
-		return fmt.Errorf("failed to insert outbox: %w", err)
-	}
-
-	return tx.Commit()
-}
-
-// Delete elimina usuario y crea evento en transacción
-func (r *UserRepoPostgres) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin tx: %w", err)
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	res, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id=$1`, id)
-	if err != nil {
-		return fmt.Errorf("db error: %w", err)
-	}
-
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return userDomain.ErrUserNotFound
-	}
-
-	if err := insertOutboxTx(ctx, tx, evt); err != nil {
-		return fmt.Errorf("failed to insert outbox: %w", err)
-	}
-
-	return tx.Commit()
-}
-
-// ------------------ Lectura ------------------
-
-func (r *UserRepoPostgres) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
-	query := `SELECT id, email, nombre, birth_date, created_at FROM users WHERE id=$1`
-	row := r.db.QueryRowContext(ctx, query, id)
-
-	var u userDomain.User
-	var idStr string
-	if err := row.Scan(&idStr, &u.Email, &u.Nombre, &u.BirthDate, &u.CreatedAt); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, userDomain.ErrUserNotFound
-		}
-		return nil, fmt.Errorf("db error: %w", err)
-	}
-
-	parsedID, err := uuid.Parse(idStr)
-	if err != nil {
-		return nil, userDomain.ErrInvalidUser
-	}
-	u.ID = parsedID
-
-	return &u, nil
-}
-
-// Traduce criterios neutrales a SQL para Postgres ($1, $2...)
-func (r *UserRepoPostgres) applyCriteria(criteria sharedDomain.Criteria) (string, []interface{}) {
-	conds := criteria.ToConditions()
-	var clauses []string
-	var args []interface{}
-	for i, c := range conds {
-		clauses = append(clauses, fmt.Sprintf("%s %s $%d", c.Field, c.Op, i+1))
-		args = append(args, c.Value)
-	}
-	return strings.Join(clauses, " AND "), args
-}
-
-func (r *UserRepoPostgres) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sort sharedQuery.Sort) ([]*userDomain.User, error) {
-	whereSQL, args := r.applyCriteria(criteria)
-
-	query := "SELECT id, email, nombre, birth_date, created_at FROM users"
-	if whereSQL != "" {
-		query += " WHERE " + whereSQL
-	}
-
-	// --- Paginación según tipo ---
-	switch p := pagination.(type) {
-	case sharedQuery.OffsetPagination:
-		args = append(args, p.Limit, p.Offset)
-		query += fmt.Sprintf(" ORDER BY %s %s LIMIT $%d OFFSET $%d",
-			sort.Field, sharedUtils.Ternary(sort.Desc, "DESC", "ASC"), len(args)-1, len(args))
-	case sharedQuery.CursorPagination:
-		if p.Cursor != "" {
-			parts := strings.SplitN(p.Cursor, "|", 2)
-			cursorSort := parts[0]
-			cursorID := parts[1]
-
-			if whereSQL != "" {
-				query += fmt.Sprintf(" AND (%s, id) > ($%d, $%d)", sort.Field, len(args)+1, len(args)+2)
-			} else {
-				query += fmt.Sprintf(" WHERE (%s, id) > ($%d, $%d)", sort.Field, len(args)+1, len(args)+2)
-			}
-			args = append(args, cursorSort, cursorID)
-		}
-		query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT %d",
-			sort.Field, sharedUtils.Ternary(sort.Desc, "DESC", "ASC"),
-			sharedUtils.Ternary(sort.Desc, "DESC", "ASC"),
-			p.Limit,
-		)
-	}
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var users []*userDomain.User
-	for rows.Next() {
-		var u userDomain.User
-		var idStr string
-		if err := rows.Scan(&idStr, &u.Email, &u.Nombre, &u.BirthDate, &u.CreatedAt); err != nil {
-			return nil, err
-		}
-		u.ID, _ = uuid.Parse(idStr)
-		users = append(users, &u)
-	}
-
-	return users, nil
+	return &UserRepoPostgres{sqlrepo.New(db, sharedPostgres.Engine, userMapper{})}
 }
 
 // ------------------ Inicialización ------------------
 
+// InitPostgres crea las tablas users y outbox si no existen. El email es único dentro de cada
+// inquilino: en las tablas anteriores a los inquilinos se sustituye la restricción global.
 func InitPostgres(db *sql.DB) error {
 	_, err := db.Exec(`
 	CREATE TABLE IF NOT EXISTS users (
 		id UUID PRIMARY KEY,
-		email TEXT UNIQUE NOT NULL,
-		nombre TEXT NOT NULL,
-		birth_date DATE NOT NULL,
-		created_at TIMESTAMP NOT NULL
+		email TEXT NOT NULL,
+		name TEXT NOT NULL,
+		birth_date TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		tenant_id TEXT NOT NULL DEFAULT '',
+		version BIGINT NOT NULL DEFAULT 1
 	)`)
 	if err != nil {
 		return err
 	}
 
+	// Las tablas anteriores a los nombres de columna en inglés guardan el nombre en "nombre", los
+	// usuarios anteriores a los inquilinos quedan en el inquilino por defecto, y los anteriores a
+	// las versiones, en la primera. La fecha de nacimiento pasa de DATE a texto, en el formato de
+	// userMapper, para poder guardarla cifrada (ver db.EncryptBirthDates).
+	_, err = db.Exec(`
+	DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'nombre') THEN
+			ALTER TABLE users RENAME COLUMN nombre TO name;
+		END IF;
+		IF EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'birth_date' AND data_type = 'date') THEN
+			ALTER TABLE users ALTER COLUMN birth_date TYPE TEXT USING to_char(birth_date, 'YYYY-MM-DD"T00:00:00Z"');
+		END IF;
+	END $$;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+	ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+	CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_email_key ON users (tenant_id, email)`)
+	if err != nil {
+		return fmt.Errorf("failed to migrate users table: %w", err)
+	}
+
 	_, err = db.Exec(`
 	CREATE TABLE IF NOT EXISTS outbox (
 		id UUID PRIMARY KEY,
@@ -246,8 +72,17 @@ func InitPostgres(db *sql.DB) error {
 		aggregate_id UUID NOT NULL,
 		event_type TEXT NOT NULL,
 		payload JSONB NOT NULL,
+		metadata JSONB NOT NULL DEFAULT '{}',
 		created_at TIMESTAMP NOT NULL,
 		processed BOOLEAN NOT NULL DEFAULT FALSE
 	)`)
-	return err
+	if err != nil {
+		return err
+	}
+	// Las tablas creadas antes de guardar metadatos de traza no tienen la columna.
+	return sharedPostgres.MigrateOutboxSchema(db)
 }
+
+// Verificación estática de la interfaz.
+var _ userDomain.UserRepository = (*UserRepoPostgres)(nil)
+var _ preflight.Checker = (*UserRepoPostgres)(nil)
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+var placeholder = regexp.MustCompile(`\$\d+`)
+
+// FuzzListQuery construye el SELECT de ListByCriteria con campos, operadores, valores, ordenación
+// y cursores arbitrarios. Sin un Postgres a mano se comprueba la forma de la consulta: o se
+// rechaza con ErrInvalidQuery, o solo interpola campos conocidos y cada valor va en su parámetro.
+func FuzzListQuery(f *testing.F) {
+	f.Add("name", "ILIKE", "%an%", "created_at", true, "")
+	f.Add("email", "=", "ana@example.com", "email", false, sharedQuery.NewCursor(uuid.New(), "ana@example.com").Encode())
+	f.Add("email; DROP TABLE users; --", "=", "x", "created_at", false, "")
+	f.Add("email", "= '' OR 1=1 --", "x", "name", false, "")
+	f.Add("name", "LIKE", "$1", "id) > (0, 0) --", true, "x|y")
+	f.Add("id", "<", "\x00", "", false, "|"+uuid.NewString())
+	f.Add("name", "=", "Ana", "name", true, sharedQuery.NewCursor(uuid.New(), "' OR 1=1 --").Encode())
+
+	repo := NewUserRepoPostgres(nil)
+	f.Fuzz(func(t *testing.T, field, op, value, sortField string, desc bool, cursor string) {
+		criteria := mocks.Criteria{{Field: field, Op: sharedDomain.Operator(op), Value: value}}
+		sorts := []sharedQuery.Sort{{Field: sortField, Desc: desc}}
+		paginations := []sharedQuery.Pagination{
+			sharedQuery.OffsetPagination{Limit: 10},
+			sharedQuery.CursorPagination{Limit: 10, Cursor: cursor},
+		}
+
+		for _, pagination := range paginations {
+			query, args, err := repo.ListQuery(criteria, pagination, sorts)
+			if err != nil {
+				require.ErrorIs(t, err, sharedQuery.ErrInvalidQuery)
+				continue
+			}
+			require.True(t, userFields[field], "unknown field %q reached the SQL: %s", field, query)
+			require.True(t, sortField == "" || userFields[sortField], "unknown sort field %q reached the SQL: %s", sortField, query)
+			requirePlaceholders(t, query, len(args))
+		}
+	})
+}
+
+// requirePlaceholders comprueba que la consulta usa exactamente los parámetros $1..$n y ningún
+// literal de texto.
+func requirePlaceholders(t *testing.T, query string, n int) {
+	t.Helper()
+	seen := map[string]bool{}
+	for _, p := range placeholder.FindAllString(query, -1) {
+		seen[p] = true
+	}
+	require.Len(t, seen, n, query)
+	for i := 1; i <= n; i++ {
+		require.True(t, seen[fmt.Sprintf("$%d", i)], "missing $%d in %s", i, query)
+	}
+	require.NotContains(t, query, "'", "values must be bound, not interpolated: %s", query)
+}
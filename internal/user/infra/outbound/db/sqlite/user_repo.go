@@ -1,281 +1,144 @@
 package sqlite
 
 import (
-	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	// _ "github.com/mattn/go-sqlite3" // better performance but requires gcc
 	_ "modernc.org/sqlite"
 
-	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
+	sharedSQLite "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlite"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlrepo"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/encryption"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
-	sharedUtils "github.com/davicafu/hexagolab/internal/shared/infra/utils"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
 )
 
+// UserRepoSQLite implementa la interfaz UserRepository para SQLite sobre la base común de los
+// repositorios SQL (ver userMapper).
 type UserRepoSQLite struct {
-	db *sql.DB
+	*sqlrepo.Repository[userDomain.User]
 }
 
 func NewUserRepoSQLite(db *sql.DB) *UserRepoSQLite {
-	return &UserRepoSQLite{db: db}
+	return &UserRepoSQLite{sqlrepo.New(db, sharedSQLite.Engine, userMapper{})}
 }
 
-// ------------------ Helper DRY para insertar en outbox ------------------
-
-func insertOutboxTx(ctx context.Context, tx *sql.Tx, evt sharedDomain.OutboxEvent) error {
-	payloadBytes, err := json.Marshal(evt.Payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal outbox payload: %w", err)
-	}
-
-	_, err = tx.ExecContext(ctx,
-		`INSERT INTO outbox (id,aggregate_type,aggregate_id,event_type,payload,created_at,processed)
-		 VALUES (?,?,?,?,?,?,0)`,
-		evt.ID.String(), evt.AggregateType, evt.AggregateID, evt.EventType, string(payloadBytes), evt.CreatedAt,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to insert outbox event: %w", err)
-	}
-
-	return nil
-}
-
-// ------------------ CRUD + Outbox ------------------
-
-// Create inserta usuario y evento en transacción
-func (r *UserRepoSQLite) Create(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	if _, err := tx.ExecContext(ctx,
-		`INSERT INTO users (id,email,nombre,birth_date,created_at) VALUES (?,?,?,?,?)`,
-		u.ID.String(), u.Email, u.Nombre, u.BirthDate.Format(time.RFC3339), u.CreatedAt.Format(time.RFC3339),
-	); err != nil {
-		return err
+// userFields son las columnas de users por las que se puede filtrar y ordenar (userDomain.UserFields),
+// más la del inquilino, que añade sharedQuery.TenantScope.
+var userFields = sharedQuery.NewFields(append(userDomain.UserFields.Names(), sharedQuery.TenantColumn)...)
+
+// userMapper traduce los usuarios a y desde la tabla users de SQLite (ver sqlrepo.Mapper). Los
+// UUID y las fechas se guardan como texto; la fecha de nacimiento, cifrada si el usuario trae
+// User.SealedBirthDate.
+type userMapper struct{}
+
+func (userMapper) Table() sqlrepo.Table {
+	return sqlrepo.Table{
+		Name:            "users",
+		Columns:         []string{"id", "email", "name", "birth_date", "created_at", "version"},
+		Immutable:       []string{"created_at"},
+		Fields:          userFields,
+		NotFound:        userDomain.ErrUserNotFound,
+		Conflict:        userDomain.ErrUserAlreadyExists,
+		Version:         "version",
+		VersionConflict: userDomain.ErrUserVersionConflict,
 	}
-
-	if err := insertOutboxTx(ctx, tx, evt); err != nil {
-		return err
-	}
-
-	return tx.Commit()
 }
 
-// Update actualiza usuario y crea evento en transacción
-func (r *UserRepoSQLite) Update(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	res, err := tx.ExecContext(ctx,
-		`UPDATE users SET email=?, nombre=?, birth_date=? WHERE id=?`,
-		u.Email, u.Nombre, u.BirthDate.Format(time.RFC3339), u.ID.String(),
-	)
-	if err != nil {
-		return err
-	}
-
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return userDomain.ErrUserNotFound
-	}
-
-	if err := insertOutboxTx(ctx, tx, evt); err != nil {
-		return fmt.Errorf("failed to insert outbox: %w", err)
-	}
-
-	return tx.Commit()
+func (userMapper) Values(u *userDomain.User) []any {
+	return []any{u.ID.String(), u.Email, u.Name, birthDate(u), formatTime(u.CreatedAt), u.Version}
 }
 
-// Delete elimina usuario y crea evento en transacción
-func (r *UserRepoSQLite) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	res, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id=?`, id.String())
-	if err != nil {
-		return err
-	}
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return userDomain.ErrUserNotFound
-	}
-
-	if err := insertOutboxTx(ctx, tx, evt); err != nil {
-		return fmt.Errorf("failed to insert outbox: %w", err)
-	}
-
-	return tx.Commit()
-}
-
-// ------------------ Lectura ------------------
-
-func (r *UserRepoSQLite) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
-	query := `SELECT id, email, nombre, birth_date, created_at FROM users WHERE id = ?`
-	row := r.db.QueryRowContext(ctx, query, id.String())
-
+// Scan lee una fila de users: las fechas, en texto, se parsean a time.Time. Una fecha de
+// nacimiento cifrada se devuelve tal cual en User.SealedBirthDate.
+func (userMapper) Scan(row sqlrepo.Scanner) (*userDomain.User, error) {
 	var u userDomain.User
-	// ✅ 1. Leemos las fechas en variables de texto temporales
 	var birthDateStr, createdAtStr string
-
-	// ✅ 2. Usamos esas variables en el Scan
-	if err := row.Scan(&u.ID, &u.Email, &u.Nombre, &birthDateStr, &createdAtStr); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, userDomain.ErrUserNotFound
-		}
-		return nil, fmt.Errorf("db error: %w", err)
+	if err := row.Scan(&u.ID, &u.Email, &u.Name, &birthDateStr, &createdAtStr, &u.Version); err != nil {
+		return nil, err
 	}
 
-	// ✅ 3. Parseamos las fechas de texto a time.Time
 	var err error
-	u.BirthDate, err = time.Parse(time.RFC3339, birthDateStr)
-	if err != nil {
+	if encryption.IsEncrypted(birthDateStr) {
+		u.SealedBirthDate = birthDateStr
+	} else if u.BirthDate, err = time.Parse(time.RFC3339, birthDateStr); err != nil {
 		return nil, fmt.Errorf("error parsing birth_date: %w", err)
 	}
 	u.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing created_at: %w", err)
 	}
-
 	return &u, nil
 }
 
-// Traduce criterios neutrales a SQL para Postgres (?, ?...)
-func (r *UserRepoSQLite) applyCriteria(criteria sharedDomain.Criteria) (string, []interface{}) {
-	conds := criteria.ToConditions()
-	var clauses []string
-	var args []interface{}
-	for _, c := range conds {
-		clauses = append(clauses, fmt.Sprintf("%s %s ?", c.Field, c.Op))
-		args = append(args, c.Value)
+func (userMapper) Arg(value any) any {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v.String()
+	case time.Time:
+		return formatTime(v)
+	default:
+		return v
 	}
-	return strings.Join(clauses, " AND "), args
 }
 
-func (r *UserRepoSQLite) ListByCriteria(
-	ctx context.Context,
-	criteria sharedDomain.Criteria,
-	pagination sharedQuery.Pagination,
-	sort sharedQuery.Sort,
-) ([]*userDomain.User, error) {
-	whereSQL, args := r.applyCriteria(criteria)
-
-	query := "SELECT id, email, nombre, birth_date, created_at FROM users"
-	if whereSQL != "" {
-		query += " WHERE " + whereSQL
-	}
-
-	// --- Paginación según tipo ---
-	switch p := pagination.(type) {
-	case sharedQuery.OffsetPagination:
-		query += fmt.Sprintf(" ORDER BY %s %s LIMIT ? OFFSET ?",
-			sort.Field, sharedUtils.Ternary(sort.Desc, "DESC", "ASC"))
-		args = append(args, p.Limit, p.Offset)
-	case sharedQuery.CursorPagination:
-		if p.Cursor != "" {
-			// Separar p.Cursor en sortValue y ID
-			parts := strings.SplitN(p.Cursor, "|", 2)
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("invalid cursor format")
-			}
-			cursorSort := parts[0]
-			cursorID := parts[1]
-
-			// Construir la condición WHERE para cursor compuesto
-			condition := fmt.Sprintf("(%s, id) > (?, ?)", sort.Field)
-			if whereSQL != "" {
-				query += " AND " + condition
-			} else {
-				query += " WHERE " + condition
-			}
-
-			// Agregar los valores al args
-			args = append(args, cursorSort, cursorID)
-		}
-
-		// Ordenar primero por el sortField y luego por ID para mantener consistencia
-		query += fmt.Sprintf(
-			" ORDER BY %s %s, id %s LIMIT %d",
-			sort.Field,
-			sharedUtils.Ternary(sort.Desc, "DESC", "ASC"),
-			sharedUtils.Ternary(sort.Desc, "DESC", "ASC"),
-			p.Limit,
-		)
-	}
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var users []*userDomain.User
-	for rows.Next() {
-		var u userDomain.User
-		var idStr, birthDateStr, createdAtStr string
-
-		if err := rows.Scan(&idStr, &u.Email, &u.Nombre, &birthDateStr, &createdAtStr); err != nil {
-			return nil, err
-		}
-		u.ID, _ = uuid.Parse(idStr)
-		u.BirthDate, err = time.Parse(time.RFC3339, birthDateStr)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing birth_date: %w", err)
-		}
-		u.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing created_at: %w", err)
-		}
-
-		users = append(users, &u)
+// birthDate es el valor de la columna birth_date: la fecha cifrada o, si no la hay, en texto.
+func birthDate(u *userDomain.User) string {
+	if u.SealedBirthDate != "" {
+		return u.SealedBirthDate
 	}
+	return formatTime(u.BirthDate)
+}
 
-	return users, nil
+// formatTime serializa las fechas en UTC, para que la comparación de textos en SQLite
+// respete el orden cronológico.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
 }
 
 // ------------------ Inicialización de DB ------------------
 
-// InitSQLite crea la tabla users si no existe
+// InitSQLite crea la tabla users si no existe. El email es único dentro de cada inquilino; en las
+// tablas creadas antes de los inquilinos sigue siendo único en toda la tabla, porque SQLite no
+// permite quitar una restricción UNIQUE sin recrear la tabla. Por lo mismo, birth_date sigue
+// declarada DATE en las tablas anteriores al cifrado de la fecha de nacimiento: SQLite guarda
+// igualmente como texto los valores cifrados, que no parecen números.
 func InitSQLite(db *sql.DB) error {
 	// Tabla de usuarios
 	_, err := db.Exec(`
         CREATE TABLE IF NOT EXISTS users (
             id TEXT PRIMARY KEY,
-            email TEXT UNIQUE NOT NULL,
-            nombre TEXT NOT NULL,
-            birth_date DATE NOT NULL,
-            created_at DATETIME NOT NULL
+            email TEXT NOT NULL,
+            name TEXT NOT NULL,
+            birth_date TEXT NOT NULL,
+            created_at DATETIME NOT NULL,
+            tenant_id TEXT NOT NULL DEFAULT '',
+            version INTEGER NOT NULL DEFAULT 1,
+            UNIQUE (tenant_id, email)
         )
     `)
 	if err != nil {
 		return err
 	}
+	// Las tablas anteriores a los nombres de columna en inglés guardan el nombre en "nombre".
+	if err := sharedSQLite.RenameColumn(db, "users", "nombre", "name"); err != nil {
+		return err
+	}
+	// Los usuarios anteriores a los inquilinos quedan en el inquilino por defecto, y los
+	// anteriores a las versiones, en la primera.
+	if err := sharedSQLite.MigrateColumns(db, "users",
+		sharedSQLite.Column{Name: "tenant_id", Definition: `tenant_id TEXT NOT NULL DEFAULT ''`},
+		sharedSQLite.Column{Name: "version", Definition: `version INTEGER NOT NULL DEFAULT 1`},
+	); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_users_tenant ON users (tenant_id)`); err != nil {
+		return err
+	}
 
 	// Tabla de Outbox
 	_, err = db.Exec(`
@@ -285,9 +148,21 @@ func InitSQLite(db *sql.DB) error {
             aggregate_id TEXT NOT NULL,
             event_type TEXT NOT NULL,
             payload TEXT NOT NULL,
+            metadata TEXT NOT NULL DEFAULT '{}',
             created_at DATETIME NOT NULL,
             processed BOOLEAN NOT NULL DEFAULT 0
         )
     `)
-	return err
+	if err != nil {
+		return err
+	}
+	// Las tablas creadas antes de guardar metadatos de traza no tienen la columna.
+	return sharedSQLite.MigrateOutboxSchema(db)
 }
+
+// Verificación estática
+var (
+	_ userDomain.UserRepository       = (*UserRepoSQLite)(nil)
+	_ preflight.Checker               = (*UserRepoSQLite)(nil)
+	_ sqlrepo.Mapper[userDomain.User] = userMapper{}
+)
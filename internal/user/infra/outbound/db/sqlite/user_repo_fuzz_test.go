@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// FuzzListByCriteria ejecuta ListByCriteria contra un SQLite real con campos, operadores, valores,
+// ordenación y cursores arbitrarios: la consulta se rechaza con ErrInvalidQuery o es SQL válido
+// que SQLite ejecuta sin errores y sin tocar la tabla.
+func FuzzListByCriteria(f *testing.F) {
+	db, err := sql.Open("sqlite", filepath.Join(f.TempDir(), "users.db"))
+	require.NoError(f, err)
+	f.Cleanup(func() { db.Close() })
+	require.NoError(f, InitSQLite(db))
+	repo := NewUserRepoSQLite(db)
+	user := &userDomain.User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana", BirthDate: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), CreatedAt: time.Now().UTC()}
+	require.NoError(f, repo.Create(context.Background(), user, sharedDomain.OutboxEvent{ID: uuid.New(), Payload: user, CreatedAt: user.CreatedAt}))
+
+	f.Add("name", "ILIKE", "%an%", "created_at", true, "")
+	f.Add("email", "=", "ana@example.com", "email", false, sharedQuery.NewCursor(user.ID, "ana@example.com").Encode())
+	f.Add("created_at", ">=", "2025-01-01T00:00:00Z", "created_at", false, sharedQuery.NewCursor(uuid.New(), "2025-01-01T00:00:00Z").Encode())
+	f.Add("email; DROP TABLE users; --", "=", "x", "created_at", false, "")
+	f.Add("email", "= '' OR 1=1 --", "x", "name", false, "")
+	f.Add("name", "LIKE", "%' OR '1'='1", "id) > (0, 0) --", true, "x|y")
+	f.Add("id", "<", "\x00", "", false, "|"+uuid.NewString())
+	f.Add("name", "=", "Ana", "name", true, sharedQuery.NewCursor(uuid.New(), "' OR 1=1 --").Encode())
+
+	f.Fuzz(func(t *testing.T, field, op, value, sortField string, desc bool, cursor string) {
+		ctx := context.Background()
+		criteria := mocks.Criteria{{Field: field, Op: sharedDomain.Operator(op), Value: value}}
+		sorts := []sharedQuery.Sort{{Field: sortField, Desc: desc}}
+		paginations := []sharedQuery.Pagination{
+			sharedQuery.OffsetPagination{Limit: 10},
+			sharedQuery.CursorPagination{Limit: 10, Cursor: cursor},
+		}
+
+		for _, pagination := range paginations {
+			_, err := repo.ListByCriteria(ctx, criteria, pagination, sorts)
+			if err != nil {
+				require.ErrorIs(t, err, sharedQuery.ErrInvalidQuery, "the query reached SQLite and failed")
+			}
+		}
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count))
+		require.Equal(t, 1, count)
+	})
+}
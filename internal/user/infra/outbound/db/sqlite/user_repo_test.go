@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// TestListByCriteria_OrGroups comprueba contra un SQLite real que los OR anidados se agrupan con
+// paréntesis y no se mezclan con los AND de su alrededor ni con la condición del cursor.
+func TestListByCriteria_OrGroups(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "users.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, InitSQLite(db))
+	repo := NewUserRepoSQLite(db)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, nombre := range []string{"Ana", "Luis", "Marta", "Juan"} {
+		u := &userDomain.User{ID: uuid.New(), Email: nombre + "@example.com", Name: nombre, BirthDate: base.AddDate(-30, 0, 0), CreatedAt: base.Add(time.Duration(i) * time.Hour)}
+		require.NoError(t, repo.Create(ctx, u, sharedDomain.OutboxEvent{ID: uuid.New(), Payload: u, CreatedAt: u.CreatedAt}))
+	}
+	// (email de Ana OR nombre como "mart" OR nombre como "juan") AND edad <= 40
+	criteria := sharedDomain.And(
+		sharedDomain.Or(
+			userDomain.EmailCriteria{Email: "Ana@example.com"},
+			sharedDomain.Or(userDomain.NameLikeCriteria{Name: "mart"}, userDomain.NameLikeCriteria{Name: "juan"}),
+		),
+		sharedDomain.And(userDomain.AgeRangeCriteria{Max: intPtr(40), Now: base}),
+	)
+	sorts := []sharedQuery.Sort{{Field: "created_at"}}
+
+	// Act
+	all, err := repo.ListByCriteria(ctx, criteria, sharedQuery.OffsetPagination{Limit: 10}, sorts)
+	require.NoError(t, err)
+	total, err := repo.CountByCriteria(ctx, criteria)
+	require.NoError(t, err)
+	first, err := repo.ListByCriteria(ctx, criteria, sharedQuery.CursorPagination{Limit: 1}, sorts)
+	require.NoError(t, err)
+	cursor := sharedQuery.NewCursorFor(sharedQuery.OrderBy(sorts), first[0].ID, func(string) any { return first[0].CreatedAt })
+	rest, err := repo.ListByCriteria(ctx, criteria, sharedQuery.CursorPagination{Limit: 10, Cursor: cursor.Encode()}, sorts)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ana", "Marta", "Juan"}, names(all))
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []string{"Marta", "Juan"}, names(rest), "El cursor se aplica a todo el OR, no solo a su última rama")
+}
+
+// TestTenantIsolation comprueba que cada inquilino solo lee, cuenta, modifica y borra sus usuarios,
+// y que el email solo es único dentro de cada uno.
+func TestTenantIsolation(t *testing.T) {
+	// Arrange
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "users.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, InitSQLite(db))
+	repo := NewUserRepoSQLite(db)
+	acme := tracing.NewContext(context.Background(), tracing.Context{TenantID: "acme"})
+	globex := tracing.NewContext(context.Background(), tracing.Context{TenantID: "globex"})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newUser := func(ctx context.Context, nombre string) *userDomain.User {
+		u := &userDomain.User{ID: uuid.New(), Email: "ana@example.com", Name: nombre, BirthDate: now.AddDate(-30, 0, 0), CreatedAt: now}
+		require.NoError(t, repo.Create(ctx, u, sharedDomain.OutboxEvent{ID: uuid.New(), Payload: u, CreatedAt: now}))
+		return u
+	}
+	ana := newUser(acme, "Ana")
+	other := newUser(globex, "Ana de Globex") // Mismo email, otro inquilino
+
+	// Act
+	_, errGet := repo.GetByID(globex, ana.ID)
+	errUpdate := repo.Update(globex, &userDomain.User{ID: ana.ID, Email: "x@example.com", Name: "X", BirthDate: now}, sharedDomain.OutboxEvent{ID: uuid.New(), CreatedAt: now})
+	errDelete := repo.DeleteByID(globex, ana.ID, sharedDomain.OutboxEvent{ID: uuid.New(), CreatedAt: now})
+	listed, err := repo.ListByCriteria(acme, nil, sharedQuery.OffsetPagination{Limit: 10}, nil)
+	require.NoError(t, err)
+	total, err := repo.CountByCriteria(globex, nil)
+	require.NoError(t, err)
+	batch, err := repo.GetByIDs(acme, []uuid.UUID{ana.ID, other.ID})
+	require.NoError(t, err)
+	var outboxTenants []string
+	rows, err := db.Query(`SELECT tenant_id FROM outbox ORDER BY tenant_id`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var tenant string
+		require.NoError(t, rows.Scan(&tenant))
+		outboxTenants = append(outboxTenants, tenant)
+	}
+	require.NoError(t, rows.Close())
+
+	// Assert
+	assert.ErrorIs(t, errGet, userDomain.ErrUserNotFound)
+	assert.ErrorIs(t, errUpdate, userDomain.ErrUserNotFound)
+	assert.ErrorIs(t, errDelete, userDomain.ErrUserNotFound)
+	assert.Equal(t, []string{"Ana"}, names(listed))
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{"Ana"}, names(batch))
+	assert.Equal(t, []string{"acme", "globex"}, outboxTenants)
+	assert.ErrorIs(t, repo.Create(acme, &userDomain.User{ID: uuid.New(), Email: "ana@example.com", Name: "Otra", BirthDate: now, CreatedAt: now},
+		sharedDomain.OutboxEvent{ID: uuid.New(), CreatedAt: now}), userDomain.ErrUserAlreadyExists)
+}
+
+// TestInitSQLite_MigratesLegacyTable comprueba que los usuarios de una tabla anterior a los
+// inquilinos quedan en el inquilino por defecto y que la columna nombre pasa a llamarse name.
+func TestInitSQLite_MigratesLegacyTable(t *testing.T) {
+	// Arrange
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "users.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, email TEXT UNIQUE NOT NULL, nombre TEXT NOT NULL, birth_date DATE NOT NULL, created_at DATETIME NOT NULL)`)
+	require.NoError(t, err)
+	id := uuid.New()
+	_, err = db.Exec(`INSERT INTO users VALUES (?, 'ana@example.com', 'Ana', '1995-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, id.String())
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, InitSQLite(db))
+	require.NoError(t, InitSQLite(db)) // Idempotente
+	repo := NewUserRepoSQLite(db)
+	u, err := repo.GetByID(context.Background(), id)
+	_, errOther := repo.GetByID(tracing.NewContext(context.Background(), tracing.Context{TenantID: "acme"}), id)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Ana", u.Name)
+	assert.ErrorIs(t, errOther, userDomain.ErrUserNotFound)
+	assert.NoError(t, repo.Preflight(context.Background()))
+}
+
+func intPtr(n int) *int { return &n }
+
+func names(users []*userDomain.User) []string {
+	var out []string
+	for _, u := range users {
+		out = append(out, u.Name)
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	adminpb "github.com/davicafu/hexagolab/gen/go/admin"
+	taskpb "github.com/davicafu/hexagolab/gen/go/task"
+	userpb "github.com/davicafu/hexagolab/gen/go/user"
+)
+
+// DefaultAddr es la dirección del servidor gRPC con la configuración por defecto (grpc.port).
+const DefaultAddr = "localhost:9090"
+
+// Client agrupa los clientes gRPC de la API de hexagolab sobre una única conexión.
+type Client struct {
+	conn *grpc.ClientConn
+
+	Users userpb.UserServiceClient
+	Tasks taskpb.TaskServiceClient
+	Admin adminpb.AdminServiceClient
+}
+
+// New crea un cliente contra addr (host:puerto). La conexión se abre en la primera llamada.
+// Sin opciones usa una conexión sin TLS, pensada para la red interna del despliegue.
+func New(addr string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client for %s: %w", addr, err)
+	}
+	return &Client{
+		conn:  conn,
+		Users: userpb.NewUserServiceClient(conn),
+		Tasks: taskpb.NewTaskServiceClient(conn),
+		Admin: adminpb.NewAdminServiceClient(conn),
+	}, nil
+}
+
+// Close cierra la conexión con el servidor.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
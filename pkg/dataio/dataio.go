@@ -0,0 +1,251 @@
+// Package dataio lee y escribe registros tabulares en CSV o NDJSON, el formato de las
+// exportaciones e importaciones de usuarios y tareas, y lleva la cuenta de una importación fila
+// a fila (ver Import).
+package dataio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format es el formato de un fichero de registros.
+type Format string
+
+const (
+	// FormatNDJSON es un objeto JSON por línea, con un campo por columna.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV es una fila de cabecera con los nombres de las columnas y una fila por registro.
+	FormatCSV Format = "csv"
+)
+
+// ErrUnsupportedFormat es el error de ParseFormat con un formato desconocido.
+var ErrUnsupportedFormat = errors.New("unsupported format, use ndjson or csv")
+
+// ParseFormat interpreta el nombre de un formato; vacío es FormatNDJSON.
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case "", FormatNDJSON:
+		return FormatNDJSON, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, name)
+}
+
+// ContentType es el tipo MIME del formato.
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// ---------------- Escritura ----------------
+
+// Writer escribe registros con unas columnas fijas. En CSV, la cabecera se escribe con el primer
+// registro o, si no hay ninguno, con Flush.
+type Writer struct {
+	columns []string
+	out     io.Writer
+	csv     *csv.Writer
+	started bool
+}
+
+// NewWriter crea un Writer de 'format' con 'columns', en el orden en que se escriben los valores.
+func NewWriter(w io.Writer, format Format, columns []string) *Writer {
+	dw := &Writer{columns: columns, out: w}
+	if format == FormatCSV {
+		dw.csv = csv.NewWriter(w)
+	}
+	return dw
+}
+
+// Write escribe un registro; 'values' van en el orden de las columnas.
+func (w *Writer) Write(values []string) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("record has %d values for %d columns", len(values), len(w.columns))
+	}
+	if w.csv != nil {
+		if err := w.header(); err != nil {
+			return err
+		}
+		return w.csv.Write(values)
+	}
+
+	// El objeto se compone a mano para que los campos salgan en el orden de las columnas.
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range w.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(column)
+		value, _ := json.Marshal(values[i])
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteString("}\n")
+	_, err := w.out.Write(buf.Bytes())
+	return err
+}
+
+// Flush vacía lo que quede en el buffer del CSV.
+func (w *Writer) Flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	if err := w.header(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *Writer) header() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	return w.csv.Write(w.columns)
+}
+
+// ---------------- Lectura ----------------
+
+// Record es un registro leído: el valor de cada columna y la línea del fichero en que empieza.
+type Record struct {
+	Line   int
+	Fields map[string]string
+}
+
+// Get devuelve el valor de la primera de 'names' que tenga valor, para aceptar alias de columna.
+func (r Record) Get(names ...string) string {
+	for _, name := range names {
+		if v := strings.TrimSpace(r.Fields[name]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// RowError es el error de una fila que no se pudo leer o importar. Las demás filas siguen.
+type RowError struct {
+	Line   int    `json:"line"`
+	Reason string `json:"error"`
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// maxLineSize es la línea NDJSON más larga que admite Reader.
+const maxLineSize = 1 << 20
+
+// Reader lee registros de CSV o NDJSON. Una fila mal formada es un *RowError y la lectura puede
+// continuar con la siguiente.
+type Reader struct {
+	csv    *csv.Reader
+	header []string
+
+	lines *bufio.Scanner
+	line  int
+}
+
+// NewReader crea un Reader de 'format'.
+func NewReader(r io.Reader, format Format) *Reader {
+	dr := &Reader{}
+	if format == FormatCSV {
+		dr.csv = csv.NewReader(r)
+		dr.csv.FieldsPerRecord = -1
+		dr.csv.TrimLeadingSpace = true
+	} else {
+		dr.lines = bufio.NewScanner(r)
+		dr.lines.Buffer(make([]byte, 64*1024), maxLineSize)
+	}
+	return dr
+}
+
+// Read devuelve el siguiente registro, io.EOF al final o un *RowError si la fila está mal formada.
+// Cualquier otro error (de lectura, una cabecera CSV inválida) impide seguir leyendo.
+func (r *Reader) Read() (Record, error) {
+	if r.csv != nil {
+		return r.readCSV()
+	}
+	return r.readNDJSON()
+}
+
+func (r *Reader) readCSV() (Record, error) {
+	if r.header == nil {
+		header, err := r.csv.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return Record{}, io.EOF
+			}
+			return Record{}, fmt.Errorf("failed to read csv header: %w", err)
+		}
+		for i := range header {
+			header[i] = strings.TrimSpace(header[i])
+		}
+		r.header = header
+	}
+
+	values, err := r.csv.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Record{}, &RowError{Line: parseErr.StartLine, Reason: parseErr.Err.Error()}
+		}
+		return Record{}, err
+	}
+	line, _ := r.csv.FieldPos(0)
+	if len(values) != len(r.header) {
+		return Record{}, &RowError{Line: line, Reason: fmt.Sprintf("expected %d fields, got %d", len(r.header), len(values))}
+	}
+	fields := make(map[string]string, len(values))
+	for i, v := range values {
+		fields[r.header[i]] = v
+	}
+	return Record{Line: line, Fields: fields}, nil
+}
+
+func (r *Reader) readNDJSON() (Record, error) {
+	for r.lines.Scan() {
+		r.line++
+		raw := bytes.TrimSpace(r.lines.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var obj map[string]any
+		if err := dec.Decode(&obj); err != nil || obj == nil {
+			return Record{}, &RowError{Line: r.line, Reason: "line is not a JSON object"}
+		}
+		fields := make(map[string]string, len(obj))
+		for k, v := range obj {
+			switch v := v.(type) {
+			case nil:
+			case string:
+				fields[k] = v
+			case json.Number:
+				fields[k] = v.String()
+			case bool:
+				fields[k] = fmt.Sprint(v)
+			default:
+				return Record{}, &RowError{Line: r.line, Reason: fmt.Sprintf("field %s must be a string, number or boolean", k)}
+			}
+		}
+		return Record{Line: r.line, Fields: fields}, nil
+	}
+	if err := r.lines.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
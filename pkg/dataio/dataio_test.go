@@ -0,0 +1,117 @@
+package dataio_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/pkg/dataio"
+)
+
+func TestWriterAndReader_RoundTrip(t *testing.T) {
+	for _, format := range []dataio.Format{dataio.FormatNDJSON, dataio.FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			w := dataio.NewWriter(&buf, format, []string{"title", "description"})
+
+			// Act
+			require.NoError(t, w.Write([]string{"Revisar, \"el\" backlog", "línea 1\nlínea 2"}))
+			require.NoError(t, w.Write([]string{"Preparar la demo", ""}))
+			require.NoError(t, w.Flush())
+			r := dataio.NewReader(&buf, format)
+			first, err := r.Read()
+			require.NoError(t, err)
+			second, err := r.Read()
+			require.NoError(t, err)
+			_, eof := r.Read()
+
+			// Assert
+			assert.Equal(t, "Revisar, \"el\" backlog", first.Get("title"))
+			assert.Equal(t, "línea 1\nlínea 2", first.Fields["description"])
+			assert.Equal(t, "Preparar la demo", second.Get("title"))
+			assert.Equal(t, "", second.Get("description"))
+			assert.ErrorIs(t, eof, io.EOF)
+		})
+	}
+}
+
+func TestWriter_NDJSONKeepsColumnOrder(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	w := dataio.NewWriter(&buf, dataio.FormatNDJSON, []string{"id", "email", "name"})
+
+	// Act
+	require.NoError(t, w.Write([]string{"u1", "ana@example.com", "Ana"}))
+
+	// Assert
+	assert.Equal(t, `{"id":"u1","email":"ana@example.com","name":"Ana"}`+"\n", buf.String())
+}
+
+func TestImport_ReportsRowErrors(t *testing.T) {
+	// Arrange
+	input := strings.Join([]string{
+		`{"title": "Revisar el backlog", "points": 3}`,
+		`not json`,
+		``,
+		`{"title": ""}`,
+		`{"title": "Preparar la demo", "tags": ["a"]}`,
+		`{"title": "Cerrar el sprint", "done": false}`,
+	}, "\n")
+	var created []string
+
+	// Act
+	report, err := dataio.Import(context.Background(), dataio.NewReader(strings.NewReader(input), dataio.FormatNDJSON),
+		func(_ context.Context, rec dataio.Record) error {
+			if rec.Get("title") == "" {
+				return errors.New("title is required")
+			}
+			created = append(created, rec.Get("title")+"/"+rec.Get("points", "done"))
+			return nil
+		})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Revisar el backlog/3", "Cerrar el sprint/false"}, created)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 3, report.Failed)
+	assert.Equal(t, []dataio.RowError{
+		{Line: 2, Reason: "line is not a JSON object"},
+		{Line: 4, Reason: "title is required"},
+		{Line: 5, Reason: "field tags must be a string, number or boolean"},
+	}, report.Errors)
+}
+
+func TestImport_CSVFieldCountMismatch(t *testing.T) {
+	// Arrange
+	input := "title,description\nRevisar el backlog,Antes del lunes\nPreparar la demo\n"
+
+	// Act
+	report, err := dataio.Import(context.Background(), dataio.NewReader(strings.NewReader(input), dataio.FormatCSV),
+		func(context.Context, dataio.Record) error { return nil })
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, []dataio.RowError{{Line: 3, Reason: "expected 2 fields, got 1"}}, report.Errors)
+}
+
+func TestParseFormat(t *testing.T) {
+	// Act
+	empty, emptyErr := dataio.ParseFormat("")
+	csv, csvErr := dataio.ParseFormat("CSV")
+	_, xmlErr := dataio.ParseFormat("xml")
+
+	// Assert
+	require.NoError(t, emptyErr)
+	require.NoError(t, csvErr)
+	assert.Equal(t, dataio.FormatNDJSON, empty)
+	assert.Equal(t, dataio.FormatCSV, csv)
+	assert.ErrorIs(t, xmlErr, dataio.ErrUnsupportedFormat)
+}
@@ -0,0 +1,52 @@
+package dataio
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// MaxReportedErrors es el máximo de errores por fila que guarda un Report; los siguientes solo
+// cuentan en Failed.
+const MaxReportedErrors = 100
+
+// Report es el resultado de una importación: cuántas filas se crearon, cuántas fallaron y por qué.
+type Report struct {
+	Created int        `json:"created"`
+	Failed  int        `json:"failed"`
+	Errors  []RowError `json:"errors"`
+}
+
+// Import lee todos los registros de 'r' y llama a 'create' con cada uno. Una fila mal formada o
+// que 'create' rechaza se anota en el Report y la importación sigue con la siguiente; solo un
+// error de lectura o la cancelación de 'ctx' la detienen, y entonces se devuelve también lo
+// importado hasta ese momento.
+func Import(ctx context.Context, r *Reader, create func(ctx context.Context, rec Record) error) (Report, error) {
+	report := Report{Errors: []RowError{}}
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+		if err == nil {
+			err = create(ctx, rec)
+			if err == nil {
+				report.Created++
+				continue
+			}
+			err = &RowError{Line: rec.Line, Reason: err.Error()}
+		}
+
+		var rowErr *RowError
+		if !errors.As(err, &rowErr) {
+			return report, err
+		}
+		report.Failed++
+		if len(report.Errors) < MaxReportedErrors {
+			report.Errors = append(report.Errors, *rowErr)
+		}
+	}
+}
@@ -1,33 +1,171 @@
+// Package logger crea el logger de la aplicación: JSON estructurado, con un nivel global y
+// niveles por módulo que se cambian en caliente (ver Levels), y campos de la petición que viajan
+// en el contexto (ver WithFields y FromContext).
 package logger
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-var log *zap.Logger
+// Levels guarda el nivel mínimo global y el de cada módulo. Un módulo es el nombre que se da a
+// un logger con Named (relayer, http, ...); sus submódulos (relayer.kafka) heredan su nivel
+// salvo que tengan uno propio. Es seguro cambiarlos mientras se escriben logs.
+type Levels struct {
+	global  zap.AtomicLevel
+	modules atomic.Pointer[map[string]zapcore.Level]
+}
+
+// NewLevels crea los niveles con el global en info y sin niveles por módulo.
+func NewLevels() *Levels {
+	l := &Levels{global: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	l.modules.Store(&map[string]zapcore.Level{})
+	return l
+}
+
+// SetLevel cambia el nivel global ("debug", "info", "warn", "error").
+func (l *Levels) SetLevel(level string) error {
+	return l.global.UnmarshalText([]byte(level))
+}
+
+// SetModuleLevels sustituye los niveles por módulo por los de 'specs', pares módulo=nivel
+// (p.ej. relayer=debug). Si alguno es inválido no cambia nada.
+func (l *Levels) SetModuleLevels(specs []string) error {
+	modules, err := ParseModuleLevels(specs)
+	if err != nil {
+		return err
+	}
+	l.modules.Store(&modules)
+	return nil
+}
 
-// Init inicializa el logger global
-func Init() {
-	var err error
+// ParseModuleLevels interpreta pares módulo=nivel.
+func ParseModuleLevels(specs []string) (map[string]zapcore.Level, error) {
+	modules := make(map[string]zapcore.Level, len(specs))
+	for _, spec := range specs {
+		module, name, ok := strings.Cut(spec, "=")
+		module = strings.TrimSpace(module)
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid module level %q, use module=level", spec)
+		}
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+			return nil, fmt.Errorf("invalid level for module %s: %w", module, err)
+		}
+		modules[module] = level
+	}
+	return modules, nil
+}
+
+// Enabled indica si se escribe un log de 'level' del módulo 'module' ("" es el logger raíz).
+func (l *Levels) Enabled(module string, level zapcore.Level) bool {
+	return level >= l.levelOf(module)
+}
+
+// levelOf devuelve el nivel del módulo o del más cercano de sus padres que tenga uno propio y,
+// si ninguno lo tiene, el global.
+func (l *Levels) levelOf(module string) zapcore.Level {
+	modules := *l.modules.Load()
+	for module != "" {
+		if level, ok := modules[module]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(module, '.')
+		if i < 0 {
+			break
+		}
+		module = module[:i]
+	}
+	return l.global.Level()
+}
+
+// minLevel es el nivel más bajo que algún módulo puede escribir.
+func (l *Levels) minLevel() zapcore.Level {
+	min := l.global.Level()
+	for _, level := range *l.modules.Load() {
+		if level < min {
+			min = level
+		}
+	}
+	return min
+}
+
+// New crea el logger de la aplicación, que escribe JSON en stderr con los niveles de 'levels'.
+func New(levels *Levels) (*zap.Logger, error) {
 	cfg := zap.NewProductionConfig()
 	cfg.Encoding = "json"            // Logs estructurados en JSON
 	cfg.EncoderConfig.TimeKey = "ts" // timestamp
 	cfg.EncoderConfig.MessageKey = "msg"
 	cfg.EncoderConfig.LevelKey = "level"
 	cfg.EncoderConfig.CallerKey = "caller"
+	// El core deja pasar todo y levelCore decide con el módulo de cada entrada.
+	cfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
 
-	log, err = cfg.Build()
-	if err != nil {
-		panic(err)
+	return cfg.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return levelCore{Core: core, levels: levels}
+	}))
+}
+
+// levelCore filtra las entradas por el nivel de su módulo, que zap guarda en LoggerName.
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func (c levelCore) Enabled(level zapcore.Level) bool {
+	return level >= c.levels.minLevel()
+}
+
+func (c levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.levels.Enabled(entry.LoggerName, entry.Level) {
+		return c.Core.Check(entry, checked)
 	}
+	return checked
 }
 
-// Sugar retorna un logger más “friendly” para usar con printf-like
-func Sugar() *zap.SugaredLogger {
-	return log.Sugar()
+// ---------------- Contexto ----------------
+
+type fieldsKey struct{}
+
+// WithFields devuelve ctx con 'fields' añadidos a los que ya llevara, para que FromContext los
+// incluya en los logs de todo lo que se haga con ese contexto (p.ej. request id, inquilino y
+// actor de una petición). Un campo con la misma clave que uno anterior lo sustituye.
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	prev, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	all := make([]zap.Field, 0, len(prev)+len(fields))
+	for _, field := range prev {
+		if !hasKey(fields, field.Key) {
+			all = append(all, field)
+		}
+	}
+	all = append(all, fields...)
+	return context.WithValue(ctx, fieldsKey{}, all)
 }
 
-// Logger retorna el logger estructurado
-func Logger() *zap.Logger {
-	return log
+func hasKey(fields []zap.Field, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// FromContext devuelve 'log' con los campos de ctx (ver WithFields). Conserva el módulo y el
+// nivel de 'log', así que cada componente sigue usando el suyo.
+func FromContext(ctx context.Context, log *zap.Logger) *zap.Logger {
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	if len(fields) == 0 {
+		return log
+	}
+	return log.With(fields...)
 }
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevels_PerModule(t *testing.T) {
+	// Arrange
+	levels := NewLevels()
+	require.NoError(t, levels.SetLevel("warn"))
+	require.NoError(t, levels.SetModuleLevels([]string{"relayer=debug", " http = error "}))
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(levelCore{Core: core, levels: levels})
+
+	// Act
+	log.Info("raíz")
+	log.Named("relayer").Debug("relayer")
+	log.Named("relayer").Named("kafka").Debug("submódulo")
+	log.Named("http").Warn("http")
+	log.Named("cache").Warn("caché")
+
+	// Assert
+	var messages []string
+	for _, entry := range logs.AllUntimed() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"relayer", "submódulo", "caché"}, messages)
+}
+
+func TestLevels_InvalidSpecKeepsPreviousLevels(t *testing.T) {
+	// Arrange
+	levels := NewLevels()
+	require.NoError(t, levels.SetModuleLevels([]string{"relayer=debug"}))
+
+	// Act
+	errMissing := levels.SetModuleLevels([]string{"relayer"})
+	errLevel := levels.SetModuleLevels([]string{"http=verbose"})
+
+	// Assert
+	assert.Error(t, errMissing)
+	assert.Error(t, errLevel)
+	assert.True(t, levels.Enabled("relayer", zapcore.DebugLevel))
+	assert.False(t, levels.Enabled("http", zapcore.DebugLevel))
+}
+
+func TestFromContext(t *testing.T) {
+	// Arrange
+	core, logs := observer.New(zapcore.InfoLevel)
+	ctx := WithFields(context.Background(), zap.String("request_id", "r1"), zap.String("tenant_id", "acme"))
+	ctx = WithFields(ctx, zap.String("actor", "ana"), zap.String("tenant_id", "globex"))
+
+	// Act
+	FromContext(ctx, zap.New(core).Named("user")).Info("con campos")
+	FromContext(context.Background(), zap.New(core)).Info("sin campos")
+
+	// Assert
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "user", entries[0].LoggerName)
+	assert.Equal(t, map[string]interface{}{"request_id": "r1", "tenant_id": "globex", "actor": "ana"}, entries[0].ContextMap())
+	assert.Empty(t, entries[1].Context)
+}
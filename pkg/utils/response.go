@@ -3,14 +3,16 @@ package utils
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ErrorResponse define la estructura estándar para las respuestas de error.
 type ErrorResponse struct {
-	Message string `json:"message"`
-	// Code    string `json:"code,omitempty"` // Opcional: un código de error interno
+	Code    string            `json:"code,omitempty"` // Código del error (sharedDomain.Code)
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"` // Motivo por parámetro o campo inválido
 }
 
 // SendSuccess envía una respuesta exitosa con un payload de datos.
@@ -20,6 +22,36 @@ func SendSuccess(c *gin.Context, statusCode int, data interface{}) {
 	})
 }
 
+// PageMeta son los metadatos de paginación que acompañan a un listado.
+type PageMeta struct {
+	NextCursor string // Cursor de la página siguiente; vacío si no hay o no se pagina por cursor.
+	HasMore    bool
+	Total      int
+}
+
+// SendPage envía una página de un listado junto a sus metadatos de paginación.
+func SendPage(c *gin.Context, statusCode int, data interface{}, meta PageMeta) {
+	body := gin.H{
+		"data":     data,
+		"has_more": meta.HasMore,
+		"total":    meta.Total,
+	}
+	if meta.NextCursor != "" {
+		body["next_cursor"] = meta.NextCursor
+	}
+	c.JSON(statusCode, body)
+}
+
+// SetPageHeaders expone los metadatos de paginación como cabeceras (X-Total-Count, X-Has-More
+// y X-Next-Cursor), para los listados que responden con el array sin sobre.
+func SetPageHeaders(c *gin.Context, meta PageMeta) {
+	c.Header("X-Total-Count", strconv.Itoa(meta.Total))
+	c.Header("X-Has-More", strconv.FormatBool(meta.HasMore))
+	if meta.NextCursor != "" {
+		c.Header("X-Next-Cursor", meta.NextCursor)
+	}
+}
+
 // SendError envía una respuesta de error con un formato estandarizado.
 func SendError(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, gin.H{
@@ -35,6 +67,16 @@ func SendBadRequest(c *gin.Context, message string) {
 	SendError(c, http.StatusBadRequest, message)
 }
 
+// SendBadRequestDetails responde 400 indicando el motivo de cada parámetro inválido.
+func SendBadRequestDetails(c *gin.Context, message string, details map[string]string) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": ErrorResponse{
+			Message: message,
+			Details: details,
+		},
+	})
+}
+
 func SendNotFound(c *gin.Context, message string) {
 	SendError(c, http.StatusNotFound, message)
 }
@@ -0,0 +1,162 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/relayer"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+)
+
+// userBackend abre un almacenamiento de usuarios vacío con la misma factoría que la aplicación.
+type userBackend struct {
+	name string
+	opts func(b *testing.B) platformDB.Options
+}
+
+var userBackends = []userBackend{
+	{platformDB.BackendMemory, func(*testing.B) platformDB.Options { return platformDB.Options{} }},
+	// Sin fsync: lo que se mide son las consultas, y así sembrar 100k filas lleva segundos.
+	{platformDB.BackendSQLite, func(b *testing.B) platformDB.Options {
+		path := filepath.Join(b.TempDir(), "users.db")
+		return platformDB.Options{SQLitePath: "file:" + path + "?_pragma=synchronous(OFF)&_pragma=journal_mode(MEMORY)"}
+	}},
+}
+
+func (u userBackend) open(b *testing.B) userStore.Stores {
+	b.Helper()
+	ctx := context.Background()
+	conns := platformDB.NewConnections(u.opts(b))
+	b.Cleanup(func() { conns.Close(ctx) })
+
+	stores, err := userStore.NewStores(ctx, u.name, conns)
+	require.NoError(b, err)
+	return stores
+}
+
+// BenchmarkUserListByCriteria lee una página de 50 usuarios a mitad del resultado, por offset y
+// por cursor, sin filtros y con filtros de nombre y edad. La diferencia entre ambas paginaciones
+// crece con el número de filas en los backends que recorren el offset.
+func BenchmarkUserListByCriteria(b *testing.B) {
+	ctx := context.Background()
+	minAge, maxAge := 30, 60
+	criterias := []struct {
+		name     string
+		criteria sharedDomain.Criteria
+	}{
+		{"all", sharedDomain.And()},
+		{"filtered", sharedDomain.And(
+			userDomain.NameLikeCriteria{Name: "García"},
+			userDomain.AgeRangeCriteria{Min: &minAge, Max: &maxAge, Now: base},
+		)},
+	}
+	sorts := []sharedQuery.Sort{{Field: "created_at"}}
+	const pageSize = 50
+
+	for _, backend := range userBackends {
+		for _, rows := range []int{10_000, 100_000} {
+			b.Run(fmt.Sprintf("%s/rows=%d", backend.name, rows), func(b *testing.B) {
+				if rows > 10_000 && testing.Short() {
+					b.Skip("skipping the 100k rows dataset in short mode")
+				}
+				stores := backend.open(b)
+				require.NoError(b, Seed(ctx, stores.Users, Users(0, rows)))
+
+				for _, c := range criterias {
+					// La página empieza a mitad del resultado; el cursor apunta al usuario anterior.
+					matches, err := stores.Users.ListByCriteria(ctx, c.criteria, sharedQuery.OffsetPagination{Limit: rows}, sorts)
+					require.NoError(b, err)
+					offset := len(matches) / 2
+					prev := matches[offset-1]
+					cursor := sharedQuery.NewCursor(prev.ID, prev.CreatedAt).Encode()
+
+					paginations := []struct {
+						name       string
+						pagination sharedQuery.Pagination
+					}{
+						{"offset", sharedQuery.OffsetPagination{Limit: pageSize, Offset: offset}},
+						{"cursor", sharedQuery.CursorPagination{Limit: pageSize, Cursor: cursor}},
+					}
+					for _, p := range paginations {
+						b.Run(c.name+"/"+p.name, func(b *testing.B) {
+							for i := 0; i < b.N; i++ {
+								page, err := stores.Users.ListByCriteria(ctx, c.criteria, p.pagination, sorts)
+								if err != nil {
+									b.Fatal(err)
+								}
+								if len(page) == 0 || page[0].ID != matches[offset].ID {
+									b.Fatalf("%s pagination returned the wrong page", p.name)
+								}
+							}
+						})
+					}
+				}
+			})
+		}
+	}
+}
+
+// discardBus es un publicador que no hace nada, para medir solo el relay.
+type discardBus struct{}
+
+func (discardBus) Publish(context.Context, interface{}) error { return nil }
+
+// BenchmarkOutboxRelay mide el throughput del relayer: cada iteración publica y marca un lote
+// completo de eventos user.created. Sembrar el lote no cuenta en la medición.
+func BenchmarkOutboxRelay(b *testing.B) {
+	ctx := context.Background()
+
+	for _, backend := range userBackends {
+		for _, batch := range []int{1, 10, 100, 1000} {
+			b.Run(fmt.Sprintf("%s/batch=%d", backend.name, batch), func(b *testing.B) {
+				stores := backend.open(b)
+				worker := relayer.NewOutboxWorker(stores.Outbox, discardBus{}, userDomain.NewEventRegistry(), time.Second, batch, sharedDomain.SystemClock{}, zap.NewNop())
+
+				next := 0
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					require.NoError(b, Seed(ctx, stores.Users, Users(next, batch)))
+					next += batch
+					b.StartTimer()
+
+					worker.ProcessBatch(ctx)
+				}
+				b.StopTimer()
+
+				pending, err := stores.Outbox.FetchPendingOutbox(ctx, 1)
+				require.NoError(b, err)
+				require.Empty(b, pending, "the relayer left events unpublished")
+				b.ReportMetric(float64(b.N*batch)/b.Elapsed().Seconds(), "events/s")
+			})
+		}
+	}
+}
+
+func TestUsers_Deterministic(t *testing.T) {
+	// Act
+	first := Users(0, 100)
+	again := Users(50, 50)
+
+	// Assert: el mismo índice da el mismo usuario, y los emails y fechas no se repiten.
+	assert.Equal(t, first[50:], again)
+	emails := map[string]bool{}
+	for i, u := range first {
+		assert.False(t, emails[u.Email], "duplicated email %s", u.Email)
+		emails[u.Email] = true
+		if i > 0 {
+			assert.True(t, u.CreatedAt.After(first[i-1].CreatedAt))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Package benchmarks mide el coste de los listados por criterios y del relay del outbox sobre los
+// adaptadores de almacenamiento, para detectar regresiones en la traducción de criterios:
+//
+//	go test ./tests/benchmarks -run '^$' -bench . -benchmem
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// base es la fecha de alta del primer usuario generado; cada usuario se crea un segundo después
+// del anterior, de modo que created_at es único y el orden por fecha es el de generación.
+var base = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	firstNames = []string{"Ana", "Luis", "Marta", "Pablo", "Sara", "Javier", "Lucía", "Diego"}
+	lastNames  = []string{"García", "Pérez", "Díaz", "López", "Martín", "Sánchez", "Romero", "Navarro"}
+)
+
+// Users genera 'n' usuarios deterministas a partir del índice 'first': las mismas llamadas dan
+// siempre los mismos IDs, nombres y fechas, así que las mediciones son comparables entre
+// ejecuciones. Los emails son únicos para cualquier índice.
+func Users(first, n int) []*userDomain.User {
+	users := make([]*userDomain.User, n)
+	for i := range users {
+		idx := first + i
+		rng := rand.New(rand.NewSource(int64(idx)))
+		id, _ := uuid.NewRandomFromReader(rng)
+		users[i] = &userDomain.User{
+			ID:        id,
+			Email:     fmt.Sprintf("user%07d@example.com", idx),
+			Name:      firstNames[rng.Intn(len(firstNames))] + " " + lastNames[rng.Intn(len(lastNames))],
+			BirthDate: time.Date(1950+rng.Intn(60), time.Month(1+rng.Intn(12)), 1+rng.Intn(28), 0, 0, 0, 0, time.UTC),
+			CreatedAt: base.Add(time.Duration(idx) * time.Second),
+		}
+	}
+	return users
+}
+
+// Seed guarda los usuarios con su evento user.created, como lo haría el servicio, de modo que
+// cada usuario deja también un evento pendiente en el outbox.
+func Seed(ctx context.Context, repo userDomain.UserRepository, users []*userDomain.User) error {
+	for _, u := range users {
+		evt := sharedDomain.OutboxEvent{
+			ID:            u.ID,
+			AggregateType: "user",
+			AggregateID:   u.ID.String(),
+			EventType:     userDomain.UserCreated,
+			Payload:       u,
+			CreatedAt:     u.CreatedAt,
+		}
+		if err := repo.Create(ctx, u, evt); err != nil {
+			return fmt.Errorf("failed to seed user %s: %w", u.Email, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package chaos
+
+import (
+	"context"
+
+	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+	sharedCache "github.com/davicafu/hexagolab/internal/shared/infra/platform/cache"
+)
+
+// Cache decora una Cache con los fallos de un Injector, para comprobar que los servicios siguen
+// respondiendo desde el repositorio cuando la caché falla o tarda.
+type Cache struct {
+	inner  sharedCache.Cache
+	faults *Injector
+}
+
+func NewCache(inner sharedCache.Cache, faults *Injector) *Cache {
+	return &Cache{inner: inner, faults: faults}
+}
+
+func (c *Cache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	return call(ctx, c.faults, "Get", func() (bool, error) { return c.inner.Get(ctx, key, dest) })
+}
+
+func (c *Cache) Set(ctx context.Context, key string, val interface{}, ttlSecs int) error {
+	return exec(ctx, c.faults, "Set", func() error { return c.inner.Set(ctx, key, val, ttlSecs) })
+}
+
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return exec(ctx, c.faults, "Delete", func() error { return c.inner.Delete(ctx, key) })
+}
+
+func (c *Cache) GetMulti(ctx context.Context, keys []string, dest func(key string) interface{}) ([]string, error) {
+	return call(ctx, c.faults, "GetMulti", func() ([]string, error) { return c.inner.GetMulti(ctx, keys, dest) })
+}
+
+func (c *Cache) SetMulti(ctx context.Context, items map[string]interface{}, ttlSecs int) error {
+	return exec(ctx, c.faults, "SetMulti", func() error { return c.inner.SetMulti(ctx, items, ttlSecs) })
+}
+
+func (c *Cache) DeleteMulti(ctx context.Context, keys []string) error {
+	return exec(ctx, c.faults, "DeleteMulti", func() error { return c.inner.DeleteMulti(ctx, keys) })
+}
+
+// EventBus decora el publicador de eventos con los fallos de un Injector. Un fallo parcial
+// publica el evento y devuelve error, como un broker que confirma tarde: el relayer lo reintenta
+// y el consumidor lo recibe dos veces.
+type EventBus struct {
+	inner  sharedBus.EventBus
+	faults *Injector
+}
+
+func NewEventBus(inner sharedBus.EventBus, faults *Injector) *EventBus {
+	return &EventBus{inner: inner, faults: faults}
+}
+
+func (b *EventBus) Publish(ctx context.Context, event interface{}) error {
+	return exec(ctx, b.faults, "Publish", func() error { return b.inner.Publish(ctx, event) })
+}
+
+// Verificación estática
+var (
+	_ sharedCache.Cache  = (*Cache)(nil)
+	_ sharedBus.EventBus = (*EventBus)(nil)
+)
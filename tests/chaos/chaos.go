@@ -0,0 +1,150 @@
+// Package chaos contiene decoradores de inyección de fallos para los puertos de la aplicación
+// (repositorios, caché y bus de eventos). Envuelven al adaptador real y, según unas Faults
+// configurables, añaden latencia, fallan antes de llegar al adaptador o fallan después de que
+// la operación se haya aplicado (fallo parcial: el cliente ve un error pero el efecto existe),
+// para probar los reintentos y el relayer del outbox con fallos realistas.
+//
+// Los fallos aleatorios salen de una semilla, así que una ejecución es reproducible.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrInjected es el error por defecto de los fallos inyectados.
+var ErrInjected = errors.New("chaos: injected fault")
+
+// Faults describe los fallos a inyectar.
+type Faults struct {
+	// Latency se añade antes de cada operación afectada. Respeta la cancelación del contexto.
+	Latency time.Duration
+	// ErrorRate es la probabilidad (0..1) de fallar sin llamar al adaptador.
+	ErrorRate float64
+	// PartialRate es la probabilidad (0..1) de llamar al adaptador y devolver error igualmente.
+	PartialRate float64
+	// Err es el error devuelto en los fallos; ErrInjected si es nil.
+	Err error
+	// Ops limita los fallos a esas operaciones (p. ej. "GetByID", "Publish"); vacío, a todas.
+	Ops []string
+}
+
+// fault es la decisión tomada para una llamada.
+type fault int
+
+const (
+	faultNone fault = iota
+	faultError
+	faultPartial
+)
+
+// Injector decide qué llamadas fallan. Es seguro para concurrencia y se puede compartir entre
+// varios decoradores para que un mismo escenario afecte a todos.
+type Injector struct {
+	mu       sync.Mutex
+	faults   Faults
+	rnd      *rand.Rand
+	failNext int
+	calls    int
+	injected int
+}
+
+// NewInjector crea un Injector con las faults iniciales y la semilla de los fallos aleatorios.
+func NewInjector(faults Faults, seed int64) *Injector {
+	return &Injector{faults: faults, rnd: rand.New(rand.NewSource(seed))}
+}
+
+// SetFaults cambia las faults en caliente, p. ej. para simular que una dependencia se recupera.
+func (i *Injector) SetFaults(faults Faults) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = faults
+}
+
+// FailNext hace que las próximas n operaciones afectadas fallen sin llamar al adaptador,
+// independientemente de ErrorRate.
+func (i *Injector) FailNext(n int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.failNext = n
+}
+
+// Calls devuelve cuántas operaciones afectadas han pasado por el Injector.
+func (i *Injector) Calls() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.calls
+}
+
+// Injected devuelve cuántos fallos se han inyectado.
+func (i *Injector) Injected() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.injected
+}
+
+func (i *Injector) decide(op string) (time.Duration, fault, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(i.faults.Ops) > 0 && !slices.Contains(i.faults.Ops, op) {
+		return 0, faultNone, nil
+	}
+	i.calls++
+
+	err := i.faults.Err
+	if err == nil {
+		err = ErrInjected
+	}
+	decision := faultNone
+	switch {
+	case i.failNext > 0:
+		i.failNext--
+		decision = faultError
+	case i.faults.ErrorRate > 0 && i.rnd.Float64() < i.faults.ErrorRate:
+		decision = faultError
+	case i.faults.PartialRate > 0 && i.rnd.Float64() < i.faults.PartialRate:
+		decision = faultPartial
+	}
+	if decision != faultNone {
+		i.injected++
+	}
+	return i.faults.Latency, decision, err
+}
+
+// call ejecuta 'fn' (la llamada al adaptador decorado) aplicando las faults de 'op'.
+func call[T any](ctx context.Context, i *Injector, op string, fn func() (T, error)) (T, error) {
+	var zero T
+	latency, decision, injectedErr := i.decide(op)
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, ctx.Err()
+		}
+	}
+
+	switch decision {
+	case faultError:
+		return zero, fmt.Errorf("%s: %w", op, injectedErr)
+	case faultPartial:
+		if _, err := fn(); err != nil {
+			return zero, err
+		}
+		return zero, fmt.Errorf("%s (applied): %w", op, injectedErr)
+	}
+	return fn()
+}
+
+// exec es call para operaciones que solo devuelven error.
+func exec(ctx context.Context, i *Injector, op string, fn func() error) error {
+	_, err := call(ctx, i, op, func() (struct{}, error) { return struct{}{}, fn() })
+	return err
+}
@@ -0,0 +1,175 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/relayer"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+func newUser() *userDomain.User {
+	return &userDomain.User{
+		ID:        uuid.New(),
+		Email:     "ana@example.com",
+		Name:      "Ana",
+		BirthDate: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC),
+		CreatedAt: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func newCreatedEvent(u *userDomain.User) sharedDomain.OutboxEvent {
+	return sharedDomain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: "user",
+		AggregateID:   u.ID.String(),
+		EventType:     userDomain.UserCreated,
+		Payload:       userDomain.NewUserPayload(u),
+		CreatedAt:     u.CreatedAt,
+	}
+}
+
+func TestInjector_ErrorDoesNotReachAdapter(t *testing.T) {
+	// Arrange
+	inner := mocks.NewInMemoryUserRepo()
+	repo := NewUserRepository(inner, NewInjector(Faults{ErrorRate: 1}, 1))
+	u := newUser()
+
+	// Act
+	err := repo.Create(context.Background(), u, newCreatedEvent(u))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.Empty(t, inner.Users)
+	assert.Empty(t, inner.Outbox)
+}
+
+func TestInjector_PartialFailureIsApplied(t *testing.T) {
+	// Arrange
+	inner := mocks.NewInMemoryUserRepo()
+	unavailable := errors.New("connection reset")
+	repo := NewUserRepository(inner, NewInjector(Faults{PartialRate: 1, Err: unavailable}, 1))
+	u := newUser()
+
+	// Act
+	err := repo.Create(context.Background(), u, newCreatedEvent(u))
+
+	// Assert
+	assert.ErrorIs(t, err, unavailable)
+	assert.Contains(t, inner.Users, u.ID, "El fallo parcial aplica la escritura")
+	assert.Len(t, inner.Outbox, 1)
+}
+
+func TestInjector_OpsAndFailNext(t *testing.T) {
+	// Arrange
+	inner := mocks.NewInMemoryUserRepo()
+	faults := NewInjector(Faults{Ops: []string{"GetByID"}}, 1)
+	repo := NewUserRepository(inner, faults)
+	u := newUser()
+	faults.FailNext(2)
+
+	// Act
+	createErr := repo.Create(context.Background(), u, newCreatedEvent(u))
+	_, firstErr := repo.GetByID(context.Background(), u.ID)
+	_, secondErr := repo.GetByID(context.Background(), u.ID)
+	got, thirdErr := repo.GetByID(context.Background(), u.ID)
+
+	// Assert
+	assert.NoError(t, createErr, "Create no está en Ops")
+	assert.ErrorIs(t, firstErr, ErrInjected)
+	assert.ErrorIs(t, secondErr, ErrInjected)
+	require.NoError(t, thirdErr)
+	assert.Equal(t, u.ID, got.ID)
+	assert.Equal(t, 3, faults.Calls())
+	assert.Equal(t, 2, faults.Injected())
+}
+
+func TestInjector_SameSeedSameFailures(t *testing.T) {
+	run := func() []bool {
+		bus := NewEventBus(infraEvents.NewInMemoryEventBus("users"), NewInjector(Faults{ErrorRate: 0.5}, 42))
+		var failed []bool
+		for i := 0; i < 50; i++ {
+			failed = append(failed, bus.Publish(context.Background(), i) != nil)
+		}
+		return failed
+	}
+
+	first, second := run(), run()
+
+	assert.Equal(t, first, second)
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
+
+func TestInjector_LatencyHonoursContext(t *testing.T) {
+	// Arrange
+	cache := NewCache(mocks.NewDummyCache(), NewInjector(Faults{Latency: time.Minute}, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Act
+	start := time.Now()
+	err := cache.Set(ctx, "key", "value", 0)
+
+	// Assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestUserService_GetUser_RetriesTransientFailures(t *testing.T) {
+	// Arrange
+	inner := mocks.NewInMemoryUserRepo()
+	u := newUser()
+	require.NoError(t, inner.Create(context.Background(), u, newCreatedEvent(u)))
+	faults := NewInjector(Faults{Ops: []string{"GetByID"}}, 1)
+	cache := NewCache(mocks.NewDummyCache(), NewInjector(Faults{ErrorRate: 1}, 1))
+	service := userApp.NewUserService(NewUserRepository(inner, faults), cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+
+	// Act
+	faults.FailNext(2)
+	got, recovered := service.GetUser(context.Background(), u.ID)
+	faults.FailNext(3)
+	_, exhausted := service.GetUser(context.Background(), u.ID)
+
+	// Assert
+	require.NoError(t, recovered, "Retry absorbe dos fallos seguidos aunque la caché también falle")
+	assert.Equal(t, u.ID, got.ID)
+	assert.ErrorIs(t, exhausted, ErrInjected, "Con tres fallos seguidos se agotan los reintentos")
+}
+
+func TestOutboxWorker_PartialPublishFailure_RedeliversEvent(t *testing.T) {
+	// Arrange
+	outbox := mocks.NewInMemoryUserRepo()
+	u := newUser()
+	require.NoError(t, outbox.Create(context.Background(), u, newCreatedEvent(u)))
+	inner := infraEvents.NewInMemoryEventBus("users")
+	delivered := inner.Subscribe(10)
+	faults := NewInjector(Faults{PartialRate: 1}, 1)
+	worker := relayer.NewOutboxWorker(outbox, NewEventBus(inner, faults), userDomain.NewEventRegistry(), time.Second, 10, sharedDomain.SystemClock{}, zap.NewNop())
+
+	// Act
+	worker.ProcessBatch(context.Background())
+	pendingAfterFailure, err := outbox.CountPendingOutbox(context.Background())
+	require.NoError(t, err)
+	faults.SetFaults(Faults{})
+	worker.ProcessBatch(context.Background())
+
+	// Assert
+	assert.Equal(t, 1, pendingAfterFailure, "Un evento cuyo publish falla sigue pendiente")
+	pending, err := outbox.CountPendingOutbox(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+	assert.Eventually(t, func() bool { return len(delivered) == 2 }, time.Second, 10*time.Millisecond,
+		"El fallo parcial entrega el evento dos veces: los consumidores deben ser idempotentes")
+}
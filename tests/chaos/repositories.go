@@ -0,0 +1,106 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// UserRepository decora un UserRepository con los fallos de un Injector.
+type UserRepository struct {
+	inner  userDomain.UserRepository
+	faults *Injector
+}
+
+func NewUserRepository(inner userDomain.UserRepository, faults *Injector) *UserRepository {
+	return &UserRepository{inner: inner, faults: faults}
+}
+
+func (r *UserRepository) Create(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
+	return exec(ctx, r.faults, "Create", func() error { return r.inner.Create(ctx, u, evt) })
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	return call(ctx, r.faults, "GetByID", func() (*userDomain.User, error) { return r.inner.GetByID(ctx, id) })
+}
+
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*userDomain.User, error) {
+	return call(ctx, r.faults, "GetByIDs", func() ([]*userDomain.User, error) { return r.inner.GetByIDs(ctx, ids) })
+}
+
+func (r *UserRepository) Update(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
+	return exec(ctx, r.faults, "Update", func() error { return r.inner.Update(ctx, u, evt) })
+}
+
+func (r *UserRepository) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	return call(ctx, r.faults, "CountByCriteria", func() (int, error) { return r.inner.CountByCriteria(ctx, criteria) })
+}
+
+func (r *UserRepository) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	return exec(ctx, r.faults, "DeleteByID", func() error { return r.inner.DeleteByID(ctx, id, evt) })
+}
+
+func (r *UserRepository) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*userDomain.User, error) {
+	return call(ctx, r.faults, "ListByCriteria", func() ([]*userDomain.User, error) {
+		return r.inner.ListByCriteria(ctx, criteria, pagination, sorts)
+	})
+}
+
+// TaskRepository decora un TaskRepository con los fallos de un Injector.
+type TaskRepository struct {
+	inner  taskDomain.TaskRepository
+	faults *Injector
+}
+
+func NewTaskRepository(inner taskDomain.TaskRepository, faults *Injector) *TaskRepository {
+	return &TaskRepository{inner: inner, faults: faults}
+}
+
+func (r *TaskRepository) Create(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
+	return exec(ctx, r.faults, "Create", func() error { return r.inner.Create(ctx, t, evt) })
+}
+
+func (r *TaskRepository) Update(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
+	return exec(ctx, r.faults, "Update", func() error { return r.inner.Update(ctx, t, evt) })
+}
+
+func (r *TaskRepository) UpdateStatus(ctx context.Context, tasks []*taskDomain.Task, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error) {
+	return call(ctx, r.faults, "UpdateStatus", func() ([]uuid.UUID, error) { return r.inner.UpdateStatus(ctx, tasks, events) })
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error) {
+	return call(ctx, r.faults, "GetByID", func() (*taskDomain.Task, error) { return r.inner.GetByID(ctx, id) })
+}
+
+func (r *TaskRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*taskDomain.Task, error) {
+	return call(ctx, r.faults, "GetByIDs", func() ([]*taskDomain.Task, error) { return r.inner.GetByIDs(ctx, ids) })
+}
+
+func (r *TaskRepository) ListByCriteria(ctx context.Context, criteria sharedDomain.Criteria, pagination sharedQuery.Pagination, sorts []sharedQuery.Sort) ([]*taskDomain.Task, error) {
+	return call(ctx, r.faults, "ListByCriteria", func() ([]*taskDomain.Task, error) {
+		return r.inner.ListByCriteria(ctx, criteria, pagination, sorts)
+	})
+}
+
+func (r *TaskRepository) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	return call(ctx, r.faults, "CountByCriteria", func() (int, error) { return r.inner.CountByCriteria(ctx, criteria) })
+}
+
+func (r *TaskRepository) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
+	return exec(ctx, r.faults, "DeleteByID", func() error { return r.inner.DeleteByID(ctx, id, evt) })
+}
+
+func (r *TaskRepository) StreamByCriteria(ctx context.Context, criteria sharedDomain.Criteria, fn func(*taskDomain.Task) error) error {
+	return exec(ctx, r.faults, "StreamByCriteria", func() error { return r.inner.StreamByCriteria(ctx, criteria, fn) })
+}
+
+// Verificación estática
+var (
+	_ userDomain.UserRepository = (*UserRepository)(nil)
+	_ taskDomain.TaskRepository = (*TaskRepository)(nil)
+)
@@ -0,0 +1,55 @@
+// Package conformance contiene suites de tests reutilizables que todo adaptador de almacenamiento
+// debe pasar, para que SQLite, Postgres, Mongo, la memoria y los mocks no diverjan en silencio.
+// Un adaptador nuevo solo tiene que llamar a RunUserRepositoryTests o RunTaskRepositoryTests
+// desde un test con una factoría que le dé almacenamiento vacío.
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// base es la fecha de referencia de los datos de prueba. Se usan segundos enteros en UTC porque
+// es la precisión que guardan todos los backends.
+var base = time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+// fieldCriteria es un Criteria con condiciones arbitrarias, para probar operadores que los
+// criterios del dominio no usan.
+type fieldCriteria []sharedDomain.Criterion
+
+func (c fieldCriteria) ToConditions() []sharedDomain.Criterion { return c }
+
+func newEvent(aggregateType, eventType string, aggregateID uuid.UUID) sharedDomain.OutboxEvent {
+	return sharedDomain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID.String(),
+		EventType:     eventType,
+		Payload:       map[string]string{"id": aggregateID.String()},
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+// pendingEventIDs devuelve los IDs de los eventos pendientes del outbox.
+func pendingEventIDs(t *testing.T, outbox sharedDomain.OutboxRepository) []uuid.UUID {
+	t.Helper()
+	pending, err := outbox.FetchPendingOutbox(context.Background(), 1000)
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, 0, len(pending))
+	for _, evt := range pending {
+		ids = append(ids, evt.ID)
+	}
+	return ids
+}
+
+// requireSameTime compara instantes ignorando la zona horaria con la que los devuelve cada backend.
+func requireSameTime(t *testing.T, want, got time.Time, field string) {
+	t.Helper()
+	require.Truef(t, want.Equal(got), "%s: want %s, got %s", field, want, got)
+}
@@ -0,0 +1,622 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+)
+
+// TaskStoresFactory devuelve un repositorio de tareas y su outbox sobre almacenamiento vacío.
+// Se llama una vez por subtest.
+type TaskStoresFactory func(t *testing.T) taskStore.Stores
+
+// RunTaskRepositoryTests comprueba que un TaskRepository cumple el contrato común: CRUD, un evento
+// de outbox por escritura y ninguno si falla, traducción de criterios, paginación por offset,
+// StreamByCriteria y los errores del dominio. La paginación por cursor se comprueba aparte con
+// RunTaskCursorPaginationTests.
+func RunTaskRepositoryTests(t *testing.T, newStores TaskStoresFactory) {
+	t.Run("create and get", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		task := newTask("Revisar el backlog", uuid.New(), 0)
+		evt := newEvent("task", "task.created", task.ID)
+
+		// Act
+		require.NoError(t, stores.Tasks.Create(ctx, task, evt))
+		got, err := stores.Tasks.GetByID(ctx, task.ID)
+
+		// Assert
+		require.NoError(t, err)
+		requireSameTask(t, task, got)
+		assert.ElementsMatch(t, []uuid.UUID{evt.ID}, pendingEventIDs(t, stores.Outbox))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		task := newTask("Revisar el backlog", uuid.New(), 0)
+		created := newEvent("task", "task.created", task.ID)
+		require.NoError(t, stores.Tasks.Create(ctx, task, created))
+
+		// Act
+		changed := *task
+		changed.Title = "Revisar el backlog del sprint"
+		changed.Description = "Antes de la planificación"
+		reassigned := uuid.New()
+		changed.AssigneeID = &reassigned
+		changed.Status = taskDomain.TaskCompleted
+		changed.UpdatedAt = task.UpdatedAt.Add(time.Hour)
+		changed.Version = task.Version + 1
+		updated := newEvent("task", "task.updated", task.ID)
+		require.NoError(t, stores.Tasks.Update(ctx, &changed, updated))
+
+		// Assert
+		got, err := stores.Tasks.GetByID(ctx, task.ID)
+		require.NoError(t, err)
+		requireSameTask(t, &changed, got)
+		assert.ElementsMatch(t, []uuid.UUID{created.ID, updated.ID}, pendingEventIDs(t, stores.Outbox))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		task := newTask("Revisar el backlog", uuid.New(), 0)
+		created := newEvent("task", "task.created", task.ID)
+		require.NoError(t, stores.Tasks.Create(ctx, task, created))
+
+		// Act
+		deleted := newEvent("task", "task.deleted", task.ID)
+		require.NoError(t, stores.Tasks.DeleteByID(ctx, task.ID, deleted))
+
+		// Assert
+		_, err := stores.Tasks.GetByID(ctx, task.ID)
+		assert.ErrorIs(t, err, taskDomain.ErrTaskNotFound)
+		assert.ElementsMatch(t, []uuid.UUID{created.ID, deleted.ID}, pendingEventIDs(t, stores.Outbox))
+	})
+
+	t.Run("missing task", func(t *testing.T) {
+		ctx := context.Background()
+		stores := newStores(t)
+		missing := newTask("No existe", uuid.New(), 0)
+
+		_, err := stores.Tasks.GetByID(ctx, missing.ID)
+		assert.ErrorIs(t, err, taskDomain.ErrTaskNotFound)
+		err = stores.Tasks.Update(ctx, missing, newEvent("task", "task.updated", missing.ID))
+		assert.ErrorIs(t, err, taskDomain.ErrTaskNotFound)
+		err = stores.Tasks.DeleteByID(ctx, missing.ID, newEvent("task", "task.deleted", missing.ID))
+		assert.ErrorIs(t, err, taskDomain.ErrTaskNotFound)
+
+		assert.Empty(t, pendingEventIDs(t, stores.Outbox), "Las escrituras fallidas no dejan eventos")
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		// Arrange: dos escritores leen la versión 1 y el primero la actualiza.
+		ctx := context.Background()
+		stores := newStores(t)
+		task := newTask("Revisar el backlog", uuid.New(), 0)
+		created := newEvent("task", "task.created", task.ID)
+		require.NoError(t, stores.Tasks.Create(ctx, task, created))
+		first, second := *task, *task
+		first.Title, first.Version = "Primera escritura", 2
+		updated := newEvent("task", "task.updated", task.ID)
+		require.NoError(t, stores.Tasks.Update(ctx, &first, updated))
+
+		// Act
+		second.Title, second.Version = "Segunda escritura", 2
+		err := stores.Tasks.Update(ctx, &second, newEvent("task", "task.updated", task.ID))
+
+		// Assert
+		assert.ErrorIs(t, err, taskDomain.ErrTaskVersionConflict)
+		got, errGet := stores.Tasks.GetByID(ctx, task.ID)
+		require.NoError(t, errGet)
+		assert.Equal(t, "Primera escritura", got.Title)
+		assert.Equal(t, int64(2), got.Version)
+		assert.ElementsMatch(t, []uuid.UUID{created.ID, updated.ID}, pendingEventIDs(t, stores.Outbox), "Las escrituras fallidas no dejan eventos")
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		// Arrange: 'stale' cambió después de leerse y 'missing' no existe.
+		ctx := context.Background()
+		stores := newStores(t)
+		fresh := newTask("Revisar el backlog", uuid.New(), 0)
+		stale := newTask("Preparar la demo", uuid.New(), time.Minute)
+		missing := newTask("No existe", uuid.New(), 2*time.Minute)
+		require.NoError(t, stores.Tasks.Create(ctx, fresh, newEvent("task", "task.created", fresh.ID)))
+		require.NoError(t, stores.Tasks.Create(ctx, stale, newEvent("task", "task.created", stale.ID)))
+		renamed := *stale
+		renamed.Title, renamed.Version = "Preparar la demo del sprint", 2
+		require.NoError(t, stores.Tasks.Update(ctx, &renamed, newEvent("task", "task.updated", stale.ID)))
+		before := pendingEventIDs(t, stores.Outbox)
+
+		// Act
+		now := fresh.UpdatedAt.Add(time.Hour)
+		var tasks []*taskDomain.Task
+		var events []sharedDomain.OutboxEvent
+		for _, task := range []*taskDomain.Task{fresh, stale, missing} {
+			changed := *task
+			changed.Status, changed.UpdatedAt, changed.Version = taskDomain.TaskCompleted, now, 2
+			tasks = append(tasks, &changed)
+			events = append(events, newEvent("task", "task.updated", task.ID))
+		}
+		updated, err := stores.Tasks.UpdateStatus(ctx, tasks, events)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fresh.ID}, updated)
+		got, err := stores.Tasks.GetByID(ctx, fresh.ID)
+		require.NoError(t, err)
+		requireSameTask(t, tasks[0], got)
+		got, err = stores.Tasks.GetByID(ctx, stale.ID)
+		require.NoError(t, err)
+		requireSameTask(t, &renamed, got)
+		assert.ElementsMatch(t, append(before, events[0].ID), pendingEventIDs(t, stores.Outbox), "Solo las tareas guardadas dejan eventos")
+	})
+
+	t.Run("get by ids", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		first := newTask("Revisar el backlog", uuid.New(), 0)
+		second := newTask("Preparar la demo", uuid.New(), time.Minute)
+		for _, task := range []*taskDomain.Task{first, second, newTask("Otra", uuid.New(), 2*time.Minute)} {
+			require.NoError(t, stores.Tasks.Create(ctx, task, newEvent("task", "task.created", task.ID)))
+		}
+
+		// Act
+		got, err := stores.Tasks.GetByIDs(ctx, []uuid.UUID{second.ID, uuid.New(), first.ID})
+		empty, emptyErr := stores.Tasks.GetByIDs(ctx, nil)
+
+		// Assert: solo las que existen, en cualquier orden.
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		byID := map[uuid.UUID]*taskDomain.Task{got[0].ID: got[0], got[1].ID: got[1]}
+		requireSameTask(t, first, byID[first.ID])
+		requireSameTask(t, second, byID[second.ID])
+		require.NoError(t, emptyErr)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		task := newTask("Revisar el backlog", uuid.New(), 0)
+		created := newEvent("task", "task.created", task.ID)
+		require.NoError(t, stores.Tasks.Create(ctx, task, created))
+
+		// Act
+		again := newTask("Otra tarea", uuid.New(), time.Minute)
+		again.ID = task.ID
+		err := stores.Tasks.Create(ctx, again, newEvent("task", "task.created", task.ID))
+
+		// Assert
+		assert.ErrorIs(t, err, taskDomain.ErrTaskAlreadyExists)
+		assert.ElementsMatch(t, []uuid.UUID{created.ID}, pendingEventIDs(t, stores.Outbox), "Las escrituras fallidas no dejan eventos")
+		got, err := stores.Tasks.GetByID(ctx, task.ID)
+		require.NoError(t, err)
+		requireSameTask(t, task, got)
+	})
+
+	t.Run("unassigned", func(t *testing.T) {
+		// Arrange: una tarea sin asignar y otra que se desasigna al actualizarla
+		ctx := context.Background()
+		stores := newStores(t)
+		unassigned := newTask("Sin asignar", uuid.New(), 0)
+		unassigned.AssigneeID = nil
+		assigned := newTask("Asignada", uuid.New(), time.Minute)
+		require.NoError(t, stores.Tasks.Create(ctx, unassigned, newEvent("task", "task.created", unassigned.ID)))
+		require.NoError(t, stores.Tasks.Create(ctx, assigned, newEvent("task", "task.created", assigned.ID)))
+
+		// Act
+		got, err := stores.Tasks.GetByID(ctx, unassigned.ID)
+		require.NoError(t, err)
+		found, err := stores.Tasks.ListByCriteria(ctx, taskDomain.UnassignedCriteria{}, sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "created_at"}})
+		require.NoError(t, err)
+		others, err := stores.Tasks.ListByCriteria(ctx, sharedDomain.Not(taskDomain.UnassignedCriteria{}), sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "created_at"}})
+		require.NoError(t, err)
+		byAssignee, err := stores.Tasks.ListByCriteria(ctx, taskDomain.AssigneeIDCriteria{ID: *assigned.AssigneeID}, sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "created_at"}})
+		require.NoError(t, err)
+
+		// Assert
+		requireSameTask(t, unassigned, got)
+		assert.Nil(t, got.AssigneeID)
+		assert.Equal(t, []string{"Sin asignar"}, taskTitles(found))
+		assert.Equal(t, []string{"Asignada"}, taskTitles(others))
+		assert.Equal(t, []string{"Asignada"}, taskTitles(byAssignee))
+
+		assigned.AssigneeID = nil
+		assigned.Version++
+		require.NoError(t, stores.Tasks.Update(ctx, assigned, newEvent("task", "task.updated", assigned.ID)))
+		total, err := stores.Tasks.CountByCriteria(ctx, taskDomain.UnassignedCriteria{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+	})
+
+	t.Run("criteria", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		tasks := seedTasks(t, stores.Tasks)
+		from, to := tasks[1].CreatedAt, tasks[3].CreatedAt
+
+		tests := []struct {
+			name     string
+			criteria sharedDomain.Criteria
+			want     []string
+		}{
+			{"no conditions", sharedDomain.And(), []string{"Revisar el backlog", "Preparar la demo", "Revisar la caché", "Migrar el outbox", "Probar el backlog"}},
+			{"status", taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted}, []string{"Preparar la demo", "Migrar el outbox"}},
+			{"assignee", taskDomain.AssigneeIDCriteria{ID: *tasks[0].AssigneeID}, []string{"Revisar el backlog", "Revisar la caché", "Probar el backlog"}},
+			{"title ignores case", taskDomain.TitleLikeCriteria{Title: "BACKLOG"}, []string{"Revisar el backlog", "Probar el backlog"}},
+			{"ilike mixed case", fieldCriteria{{Field: "title", Op: sharedDomain.OpILike, Value: "rEVISAR%"}}, []string{"Revisar el backlog", "Revisar la caché"}},
+			{"like is anchored", fieldCriteria{{Field: "title", Op: sharedDomain.OpLike, Value: "Revisar%"}}, []string{"Revisar el backlog", "Revisar la caché"}},
+			{"created range", taskDomain.CreatedAtRangeCriteria{Start: &from, End: &to}, []string{"Preparar la demo", "Revisar la caché", "Migrar el outbox"}},
+			{"and", sharedDomain.And(taskDomain.StatusCriteria{Status: taskDomain.TaskPending}, taskDomain.AssigneeIDCriteria{ID: *tasks[0].AssigneeID}), []string{"Revisar el backlog", "Probar el backlog"}},
+			{"or", sharedDomain.Or(taskDomain.StatusCriteria{Status: taskDomain.TaskFailed}, taskDomain.TitleLikeCriteria{Title: "demo"}), []string{"Preparar la demo", "Revisar la caché"}},
+			{"or of and groups", sharedDomain.Or(sharedDomain.And(taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted}, taskDomain.AssigneeIDCriteria{ID: *tasks[1].AssigneeID}), taskDomain.TitleLikeCriteria{Title: "probar"}), []string{"Preparar la demo", "Migrar el outbox", "Probar el backlog"}},
+			{"not completed and not assigned", sharedDomain.And(sharedDomain.Not(taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted}), sharedDomain.Not(taskDomain.AssigneeIDCriteria{ID: *tasks[1].AssigneeID})), []string{"Revisar el backlog", "Revisar la caché", "Probar el backlog"}},
+			{"not or", sharedDomain.Not(sharedDomain.Or(taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted}, taskDomain.StatusCriteria{Status: taskDomain.TaskFailed})), []string{"Revisar el backlog", "Probar el backlog"}},
+			{"not range", sharedDomain.Not(taskDomain.CreatedAtRangeCriteria{Start: &from, End: &to}), []string{"Revisar el backlog", "Probar el backlog"}},
+			{"not nothing", sharedDomain.Not(sharedDomain.And()), []string{"Revisar el backlog", "Preparar la demo", "Revisar la caché", "Migrar el outbox", "Probar el backlog"}},
+			{"no match", taskDomain.TitleLikeCriteria{Title: "inexistente"}, nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Act
+				found, err := stores.Tasks.ListByCriteria(ctx, tt.criteria, sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "created_at"}})
+				require.NoError(t, err)
+				total, err := stores.Tasks.CountByCriteria(ctx, tt.criteria)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, taskTitles(found))
+				assert.Equal(t, len(tt.want), total, "CountByCriteria usa el mismo filtro que ListByCriteria")
+			})
+		}
+	})
+
+	t.Run("offset pagination", func(t *testing.T) {
+		ctx := context.Background()
+		stores := newStores(t)
+		seedTasks(t, stores.Tasks)
+		all := sharedDomain.And()
+
+		page, err := stores.Tasks.ListByCriteria(ctx, all, sharedQuery.OffsetPagination{Limit: 2, Offset: 1}, []sharedQuery.Sort{{Field: "created_at"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Preparar la demo", "Revisar la caché"}, taskTitles(page))
+
+		page, err = stores.Tasks.ListByCriteria(ctx, all, sharedQuery.OffsetPagination{Limit: 2, Offset: 0}, []sharedQuery.Sort{{Field: "created_at", Desc: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Probar el backlog", "Migrar el outbox"}, taskTitles(page))
+
+		page, err = stores.Tasks.ListByCriteria(ctx, all, sharedQuery.OffsetPagination{Limit: 2, Offset: 0}, []sharedQuery.Sort{{Field: "title"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Migrar el outbox", "Preparar la demo"}, taskTitles(page))
+
+		page, err = stores.Tasks.ListByCriteria(ctx, all, sharedQuery.OffsetPagination{Limit: 2, Offset: 10}, []sharedQuery.Sort{{Field: "created_at"}})
+		require.NoError(t, err)
+		assert.Empty(t, page)
+
+		// Varios campos: por estado y, dentro de cada estado, de la más reciente a la más antigua.
+		page, err = stores.Tasks.ListByCriteria(ctx, all, sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "status"}, {Field: "created_at", Desc: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Migrar el outbox", "Preparar la demo", "Revisar la caché", "Probar el backlog", "Revisar el backlog"}, taskTitles(page))
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		seedTasks(t, stores.Tasks)
+		criteria := sharedDomain.Or(taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted}, taskDomain.TitleLikeCriteria{Title: "backlog"})
+
+		// Act
+		var streamed []*taskDomain.Task
+		err := stores.Tasks.StreamByCriteria(ctx, criteria, func(task *taskDomain.Task) error {
+			streamed = append(streamed, task)
+			return nil
+		})
+
+		// Assert: las que cumplen el filtro, por fecha de alta.
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Revisar el backlog", "Preparar la demo", "Migrar el outbox", "Probar el backlog"}, taskTitles(streamed))
+
+		// Un error de 'fn' corta el recorrido y se propaga.
+		stop := errors.New("stop")
+		seen := 0
+		err = stores.Tasks.StreamByCriteria(ctx, criteria, func(*taskDomain.Task) error {
+			seen++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, seen)
+	})
+}
+
+// RunTaskCursorPaginationTests comprueba la paginación por cursor de un TaskRepository igual que
+// la de usuarios: recorrer las páginas con el cursor del último registro devuelve todo el
+// listado, en orden y desempatando por id.
+func RunTaskCursorPaginationTests(t *testing.T, newStores TaskStoresFactory) {
+	// Arrange: dos tareas con la misma fecha de alta, que se desempatan por id.
+	ctx := context.Background()
+	stores := newStores(t)
+	tasks := seedTasks(t, stores.Tasks)
+	twin := newTask("Tarea gemela", uuid.New(), 0)
+	twin.CreatedAt = tasks[1].CreatedAt
+	require.NoError(t, stores.Tasks.Create(ctx, twin, newEvent("task", "task.created", twin.ID)))
+	all := sharedDomain.And()
+
+	for _, sortBy := range [][]sharedQuery.Sort{
+		{{Field: "created_at"}},
+		{{Field: "created_at", Desc: true}},
+		{{Field: "status"}},
+		{{Field: "title", Desc: true}},
+		{{Field: "status"}, {Field: "created_at", Desc: true}},
+		{{Field: "status", Desc: true}, {Field: "title"}},
+	} {
+		t.Run(fmt.Sprint(sortBy), func(t *testing.T) {
+			want, err := stores.Tasks.ListByCriteria(ctx, all, sharedQuery.CursorPagination{Limit: 10}, sortBy)
+			require.NoError(t, err)
+			require.Len(t, want, len(tasks)+1)
+
+			// Act: se recorren páginas de dos en dos con el último registro como cursor.
+			var walked []*taskDomain.Task
+			page := sharedQuery.CursorPagination{Limit: 2}
+			for range len(want) {
+				found, err := stores.Tasks.ListByCriteria(ctx, all, page, sortBy)
+				require.NoError(t, err)
+				if len(found) == 0 {
+					break
+				}
+				walked = append(walked, found...)
+				last := found[len(found)-1]
+				page.Cursor = sharedQuery.NewCursorFor(sortBy, last.ID, func(field string) any { return taskSortValue(last, field) }).Encode()
+			}
+
+			// Assert
+			assert.Equal(t, taskIDs(want), taskIDs(walked))
+		})
+	}
+
+	t.Run("with an or filter", func(t *testing.T) {
+		// El filtro y la condición del cursor se combinan con AND sin mezclar el OR.
+		criteria := sharedDomain.Or(taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted}, taskDomain.TitleLikeCriteria{Title: "backlog"})
+		sortBy := []sharedQuery.Sort{{Field: "created_at"}}
+		var walked []*taskDomain.Task
+		page := sharedQuery.CursorPagination{Limit: 2}
+		for range len(tasks) {
+			found, err := stores.Tasks.ListByCriteria(ctx, criteria, page, sortBy)
+			require.NoError(t, err)
+			if len(found) == 0 {
+				break
+			}
+			walked = append(walked, found...)
+			page.Cursor = sharedQuery.NewCursor(found[len(found)-1].ID, found[len(found)-1].CreatedAt).Encode()
+		}
+		assert.Equal(t, []string{"Revisar el backlog", "Preparar la demo", "Migrar el outbox", "Probar el backlog"}, taskTitles(walked))
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		page := sharedQuery.CursorPagination{Limit: 2, Cursor: "2025-03-01T10:00:00Z|" + tasks[0].ID.String()}
+		_, err := stores.Tasks.ListByCriteria(ctx, all, page, []sharedQuery.Sort{{Field: "created_at"}})
+		assert.ErrorIs(t, err, sharedQuery.ErrInvalidQuery)
+
+		// Un cursor de un solo campo no sirve para un orden de dos.
+		page.Cursor = sharedQuery.NewCursor(tasks[0].ID, tasks[0].CreatedAt).Encode()
+		_, err = stores.Tasks.ListByCriteria(ctx, all, page, []sharedQuery.Sort{{Field: "status"}, {Field: "created_at"}})
+		assert.ErrorIs(t, err, sharedQuery.ErrInvalidQuery)
+	})
+
+	t.Run("unassigned tasks on a page boundary", func(t *testing.T) {
+		// Arrange: tres tareas sin asignar, que en páginas de dos caen a ambos lados de un corte.
+		for i := range 3 {
+			task := newTask(fmt.Sprintf("Sin asignar %d", i), uuid.Nil, time.Duration(i)*time.Second)
+			task.AssigneeID = nil
+			require.NoError(t, stores.Tasks.Create(ctx, task, newEvent("task", "task.created", task.ID)))
+		}
+
+		for _, sortBy := range [][]sharedQuery.Sort{
+			{{Field: "assignee_id"}},
+			{{Field: "assignee_id", Desc: true}},
+			{{Field: "assignee_id"}, {Field: "created_at", Desc: true}},
+		} {
+			t.Run(fmt.Sprint(sortBy), func(t *testing.T) {
+				want, err := stores.Tasks.ListByCriteria(ctx, all, sharedQuery.CursorPagination{Limit: 20}, sortBy)
+				require.NoError(t, err)
+				require.Len(t, want, len(tasks)+4)
+
+				// Act
+				var walked []*taskDomain.Task
+				page := sharedQuery.CursorPagination{Limit: 2}
+				for range len(want) {
+					found, err := stores.Tasks.ListByCriteria(ctx, all, page, sortBy)
+					require.NoError(t, err)
+					if len(found) == 0 {
+						break
+					}
+					walked = append(walked, found...)
+					last := found[len(found)-1]
+					page.Cursor = sharedQuery.NewCursorFor(sortBy, last.ID, func(field string) any { return taskSortValue(last, field) }).Encode()
+				}
+
+				// Assert: las tareas sin asignar van primero en ascendente y últimas en descendente.
+				assert.Equal(t, taskIDs(want), taskIDs(walked))
+				unassigned := want[:3]
+				if sortBy[0].Desc {
+					unassigned = want[len(want)-3:]
+				}
+				for _, task := range unassigned {
+					assert.Nil(t, task.AssigneeID, task.Title)
+				}
+			})
+		}
+	})
+}
+
+// newTask crea una tarea pendiente creada 'after' después de la fecha base.
+func newTask(title string, assignee uuid.UUID, after time.Duration) *taskDomain.Task {
+	return &taskDomain.Task{
+		ID:          uuid.New(),
+		Title:       title,
+		Description: "Descripción de " + title,
+		AssigneeID:  &assignee,
+		Status:      taskDomain.TaskPending,
+		CreatedAt:   base.Add(after),
+		UpdatedAt:   base.Add(after),
+		Version:     1,
+	}
+}
+
+// seedTasks crea cinco tareas entre dos asignados, en orden de fecha de creación.
+func seedTasks(t *testing.T, repo taskDomain.TaskRepository) []*taskDomain.Task {
+	t.Helper()
+	ana, luis := uuid.New(), uuid.New()
+	tasks := []*taskDomain.Task{
+		newTask("Revisar el backlog", ana, 0),
+		newTask("Preparar la demo", luis, time.Minute),
+		newTask("Revisar la caché", ana, 2*time.Minute),
+		newTask("Migrar el outbox", luis, 3*time.Minute),
+		newTask("Probar el backlog", ana, 4*time.Minute),
+	}
+	tasks[1].Status = taskDomain.TaskCompleted
+	tasks[2].Status = taskDomain.TaskFailed
+	tasks[3].Status = taskDomain.TaskCompleted
+	for _, task := range tasks {
+		require.NoError(t, repo.Create(context.Background(), task, newEvent("task", "task.created", task.ID)))
+	}
+	return tasks
+}
+
+func requireSameTask(t *testing.T, want, got *taskDomain.Task) {
+	t.Helper()
+	require.NotNil(t, got)
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.Title, got.Title)
+	assert.Equal(t, want.Description, got.Description)
+	assert.Equal(t, want.AssigneeID, got.AssigneeID)
+	assert.Equal(t, want.Status, got.Status)
+	requireSameTime(t, want.CreatedAt, got.CreatedAt, "created_at")
+	requireSameTime(t, want.UpdatedAt, got.UpdatedAt, "updated_at")
+	assert.Equal(t, want.Version, got.Version)
+}
+
+// taskSortValue devuelve el valor de 'field' que va en el cursor.
+func taskSortValue(task *taskDomain.Task, field string) any {
+	switch field {
+	case "title":
+		return task.Title
+	case "status":
+		return string(task.Status)
+	case "assignee_id":
+		if task.AssigneeID == nil {
+			return nil
+		}
+		return *task.AssigneeID
+	}
+	return task.CreatedAt
+}
+
+func taskIDs(tasks []*taskDomain.Task) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func taskTitles(tasks []*taskDomain.Task) []string {
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+// RunTaskCounterTests comprueba que un TaskCounterRepository mantiene los contadores por usuario
+// igual que el resto: aplica los cambios en orden de versión, ignora los repetidos y los que
+// llegan tarde o tras un borrado, separa los inquilinos y se vacía con Reset.
+func RunTaskCounterTests(t *testing.T, newStores TaskStoresFactory) {
+	ctx := context.Background()
+	ana, luis := uuid.New(), uuid.New()
+	change := func(task uuid.UUID, assignee uuid.UUID, status taskDomain.TaskStatus, version int64) taskDomain.TaskCounterChange {
+		return taskDomain.TaskCounterChange{TaskID: task, AssigneeID: &assignee, Status: status, Version: version}
+	}
+	counts := func(t *testing.T, ctx context.Context, repo taskDomain.TaskCounterRepository, user uuid.UUID) taskDomain.TaskCounts {
+		summary, err := repo.Summary(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, user, summary.UserID)
+		return summary.TaskCounts
+	}
+
+	t.Run("apply in order", func(t *testing.T) {
+		// Arrange
+		repo := newStores(t).Counters
+		first, second := uuid.New(), uuid.New()
+
+		// Act: la primera se reasigna a Luis y se completa; la segunda se borra.
+		for _, c := range []taskDomain.TaskCounterChange{
+			change(first, ana, taskDomain.TaskPending, 1),
+			change(second, ana, taskDomain.TaskPending, 1),
+			change(first, luis, taskDomain.TaskPending, 2),
+			change(first, luis, taskDomain.TaskCompleted, 3),
+			{TaskID: second, Deleted: true},
+		} {
+			require.NoError(t, repo.Apply(ctx, c))
+		}
+
+		// Assert
+		assert.Equal(t, taskDomain.TaskCounts{}, counts(t, ctx, repo, ana))
+		assert.Equal(t, taskDomain.TaskCounts{Completed: 1}, counts(t, ctx, repo, luis))
+		assert.Equal(t, taskDomain.TaskCounts{}, counts(t, ctx, repo, uuid.New()), "Sin tareas, todo a cero")
+	})
+
+	t.Run("stale and duplicate changes", func(t *testing.T) {
+		// Arrange
+		repo := newStores(t).Counters
+		task, deleted := uuid.New(), uuid.New()
+		require.NoError(t, repo.Apply(ctx, change(task, ana, taskDomain.TaskPending, 1)))
+		require.NoError(t, repo.Apply(ctx, change(task, ana, taskDomain.TaskCompleted, 2)))
+		require.NoError(t, repo.Apply(ctx, taskDomain.TaskCounterChange{TaskID: deleted, Deleted: true}))
+
+		// Act
+		require.NoError(t, repo.Apply(ctx, change(task, ana, taskDomain.TaskCompleted, 2)))
+		require.NoError(t, repo.Apply(ctx, change(task, ana, taskDomain.TaskPending, 1)))
+		require.NoError(t, repo.Apply(ctx, change(deleted, ana, taskDomain.TaskPending, 1)))
+
+		// Assert
+		assert.Equal(t, taskDomain.TaskCounts{Completed: 1}, counts(t, ctx, repo, ana))
+	})
+
+	t.Run("tenants and reset", func(t *testing.T) {
+		// Arrange
+		repo := newStores(t).Counters
+		acme := tracing.NewContext(ctx, tracing.Context{TenantID: "acme"})
+		task := uuid.New()
+		require.NoError(t, repo.Apply(ctx, change(uuid.New(), ana, taskDomain.TaskPending, 1)))
+		require.NoError(t, repo.Apply(acme, change(task, ana, taskDomain.TaskCompleted, 1)))
+
+		// Act
+		require.NoError(t, repo.Reset(acme))
+
+		// Assert
+		assert.Equal(t, taskDomain.TaskCounts{Pending: 1}, counts(t, ctx, repo, ana))
+		assert.Equal(t, taskDomain.TaskCounts{}, counts(t, acme, repo, ana))
+		require.NoError(t, repo.Apply(acme, change(task, ana, taskDomain.TaskCompleted, 1)))
+		assert.Equal(t, taskDomain.TaskCounts{Completed: 1}, counts(t, acme, repo, ana), "Tras Reset los eventos se vuelven a aplicar")
+	})
+}
@@ -0,0 +1,107 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// newTaskStores construye los adaptadores con la misma factoría que usa la aplicación.
+func newTaskStores(t *testing.T, backend string, opts platformDB.Options) taskStore.Stores {
+	t.Helper()
+	ctx := context.Background()
+	conns := platformDB.NewConnections(opts)
+	t.Cleanup(func() { conns.Close(ctx) })
+
+	stores, err := taskStore.NewStores(ctx, backend, conns)
+	require.NoError(t, err)
+	return stores
+}
+
+func memoryTaskStores(t *testing.T) taskStore.Stores {
+	return newTaskStores(t, platformDB.BackendMemory, platformDB.Options{})
+}
+
+func TestTaskRepository_Memory(t *testing.T) {
+	RunTaskRepositoryTests(t, memoryTaskStores)
+	t.Run("cursor pagination", func(t *testing.T) { RunTaskCursorPaginationTests(t, memoryTaskStores) })
+	t.Run("counters", func(t *testing.T) { RunTaskCounterTests(t, memoryTaskStores) })
+}
+
+func TestTaskRepository_SQLite(t *testing.T) {
+	sqliteTaskStores := func(t *testing.T) taskStore.Stores {
+		return newTaskStores(t, platformDB.BackendSQLite, platformDB.Options{SQLitePath: filepath.Join(t.TempDir(), "tasks.db")})
+	}
+	RunTaskRepositoryTests(t, sqliteTaskStores)
+	t.Run("cursor pagination", func(t *testing.T) { RunTaskCursorPaginationTests(t, sqliteTaskStores) })
+	t.Run("counters", func(t *testing.T) { RunTaskCounterTests(t, sqliteTaskStores) })
+}
+
+func TestTaskRepository_File(t *testing.T) {
+	fileTaskStores := func(t *testing.T) taskStore.Stores {
+		return newTaskStores(t, platformDB.BackendFile, platformDB.Options{FilePath: filepath.Join(t.TempDir(), "tasks.json")})
+	}
+	RunTaskRepositoryTests(t, fileTaskStores)
+	t.Run("cursor pagination", func(t *testing.T) { RunTaskCursorPaginationTests(t, fileTaskStores) })
+	t.Run("counters", func(t *testing.T) { RunTaskCounterTests(t, fileTaskStores) })
+}
+
+func TestTaskRepository_Postgres(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL no está configurada, saltando la suite de conformidad con Postgres")
+	}
+	postgresTaskStores := func(t *testing.T) taskStore.Stores {
+		stores := newTaskStores(t, platformDB.BackendPostgres, platformDB.Options{PostgresDSN: dsn})
+		truncatePostgres(t, dsn, "tasks", "outbox", "user_task_counts", "task_counter_state")
+		return stores
+	}
+	RunTaskRepositoryTests(t, postgresTaskStores)
+	t.Run("cursor pagination", func(t *testing.T) { RunTaskCursorPaginationTests(t, postgresTaskStores) })
+	t.Run("counters", func(t *testing.T) { RunTaskCounterTests(t, postgresTaskStores) })
+}
+
+// La suite de Mongo necesita un replica set, porque el repositorio usa transacciones.
+func TestTaskRepository_Mongo(t *testing.T) {
+	uri := os.Getenv("MONGO_URI")
+	if uri == "" {
+		t.Skip("MONGO_URI no está configurada, saltando la suite de conformidad con MongoDB")
+	}
+	mongoTaskStores := func(t *testing.T) taskStore.Stores {
+		// Cada subtest usa su propia base de datos, que se borra al terminar.
+		dbName := fmt.Sprintf("hexagolab_conformance_%s", uuid.NewString()[:8])
+		opts := platformDB.Options{MongoURI: uri, MongoDatabase: dbName}
+		stores := newTaskStores(t, platformDB.BackendMongo, opts)
+		t.Cleanup(func() {
+			ctx := context.Background()
+			conns := platformDB.NewConnections(opts)
+			defer conns.Close(ctx)
+			if client, _, err := conns.Mongo(ctx); err == nil {
+				_ = client.Database(dbName).Drop(ctx)
+			}
+		})
+		return stores
+	}
+	RunTaskRepositoryTests(t, mongoTaskStores)
+	t.Run("cursor pagination", func(t *testing.T) { RunTaskCursorPaginationTests(t, mongoTaskStores) })
+	t.Run("counters", func(t *testing.T) { RunTaskCounterTests(t, mongoTaskStores) })
+}
+
+func mockTaskStores(t *testing.T) taskStore.Stores {
+	repo := mocks.NewInMemoryTaskRepo()
+	return taskStore.Stores{Tasks: repo, Outbox: repo}
+}
+
+func TestTaskRepository_Mock(t *testing.T) {
+	RunTaskRepositoryTests(t, mockTaskStores)
+	t.Run("cursor pagination", func(t *testing.T) { RunTaskCursorPaginationTests(t, mockTaskStores) })
+}
@@ -0,0 +1,344 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+)
+
+// UserStoresFactory devuelve un repositorio de usuarios y su outbox sobre almacenamiento vacío.
+// Se llama una vez por subtest.
+type UserStoresFactory func(t *testing.T) userStore.Stores
+
+// RunUserRepositoryTests comprueba que un UserRepository cumple el contrato común: CRUD, un evento
+// de outbox por escritura y ninguno si falla, traducción de criterios, paginación por offset y
+// por cursor, y los errores del dominio.
+func RunUserRepositoryTests(t *testing.T, newStores UserStoresFactory) {
+	t.Run("create and get", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		user := newUser("ana@example.com", "Ana García", 30, 0)
+		evt := newEvent("user", "user.created", user.ID)
+
+		// Act
+		require.NoError(t, stores.Users.Create(ctx, user, evt))
+		got, err := stores.Users.GetByID(ctx, user.ID)
+
+		// Assert
+		require.NoError(t, err)
+		requireSameUser(t, user, got)
+		assert.ElementsMatch(t, []uuid.UUID{evt.ID}, pendingEventIDs(t, stores.Outbox))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		user := newUser("ana@example.com", "Ana García", 30, 0)
+		created := newEvent("user", "user.created", user.ID)
+		require.NoError(t, stores.Users.Create(ctx, user, created))
+
+		// Act
+		changed := *user
+		changed.Email = "ana.garcia@example.com"
+		changed.Name = "Ana García López"
+		changed.BirthDate = changed.BirthDate.AddDate(-1, 0, 0)
+		changed.Version = user.Version + 1
+		updated := newEvent("user", "user.updated", user.ID)
+		require.NoError(t, stores.Users.Update(ctx, &changed, updated))
+
+		// Assert
+		got, err := stores.Users.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		requireSameUser(t, &changed, got)
+		assert.ElementsMatch(t, []uuid.UUID{created.ID, updated.ID}, pendingEventIDs(t, stores.Outbox))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		user := newUser("ana@example.com", "Ana García", 30, 0)
+		created := newEvent("user", "user.created", user.ID)
+		require.NoError(t, stores.Users.Create(ctx, user, created))
+
+		// Act
+		deleted := newEvent("user", "user.deleted", user.ID)
+		require.NoError(t, stores.Users.DeleteByID(ctx, user.ID, deleted))
+
+		// Assert
+		_, err := stores.Users.GetByID(ctx, user.ID)
+		assert.ErrorIs(t, err, userDomain.ErrUserNotFound)
+		assert.ElementsMatch(t, []uuid.UUID{created.ID, deleted.ID}, pendingEventIDs(t, stores.Outbox))
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		ctx := context.Background()
+		stores := newStores(t)
+		missing := newUser("nadie@example.com", "Nadie", 30, 0)
+
+		_, err := stores.Users.GetByID(ctx, missing.ID)
+		assert.ErrorIs(t, err, userDomain.ErrUserNotFound)
+		err = stores.Users.Update(ctx, missing, newEvent("user", "user.updated", missing.ID))
+		assert.ErrorIs(t, err, userDomain.ErrUserNotFound)
+		err = stores.Users.DeleteByID(ctx, missing.ID, newEvent("user", "user.deleted", missing.ID))
+		assert.ErrorIs(t, err, userDomain.ErrUserNotFound)
+
+		assert.Empty(t, pendingEventIDs(t, stores.Outbox), "Las escrituras fallidas no dejan eventos")
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		// Arrange: dos escritores leen la versión 1 y el primero la actualiza.
+		ctx := context.Background()
+		stores := newStores(t)
+		user := newUser("ana@example.com", "Ana García", 30, 0)
+		created := newEvent("user", "user.created", user.ID)
+		require.NoError(t, stores.Users.Create(ctx, user, created))
+		first, second := *user, *user
+		first.Name, first.Version = "Primera escritura", 2
+		updated := newEvent("user", "user.updated", user.ID)
+		require.NoError(t, stores.Users.Update(ctx, &first, updated))
+
+		// Act
+		second.Name, second.Version = "Segunda escritura", 2
+		err := stores.Users.Update(ctx, &second, newEvent("user", "user.updated", user.ID))
+
+		// Assert
+		assert.ErrorIs(t, err, userDomain.ErrUserVersionConflict)
+		got, errGet := stores.Users.GetByID(ctx, user.ID)
+		require.NoError(t, errGet)
+		assert.Equal(t, "Primera escritura", got.Name)
+		assert.Equal(t, int64(2), got.Version)
+		assert.ElementsMatch(t, []uuid.UUID{created.ID, updated.ID}, pendingEventIDs(t, stores.Outbox), "Las escrituras fallidas no dejan eventos")
+	})
+
+	t.Run("get by ids", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		ana := newUser("ana@example.com", "Ana García", 30, 0)
+		luis := newUser("luis@example.com", "Luis Pérez", 40, time.Minute)
+		for _, user := range []*userDomain.User{ana, luis, newUser("eva@example.com", "Eva Ruiz", 25, 2*time.Minute)} {
+			require.NoError(t, stores.Users.Create(ctx, user, newEvent("user", "user.created", user.ID)))
+		}
+
+		// Act
+		got, err := stores.Users.GetByIDs(ctx, []uuid.UUID{luis.ID, uuid.New(), ana.ID})
+		empty, emptyErr := stores.Users.GetByIDs(ctx, nil)
+
+		// Assert: solo los que existen, en cualquier orden.
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		byID := map[uuid.UUID]*userDomain.User{got[0].ID: got[0], got[1].ID: got[1]}
+		requireSameUser(t, ana, byID[ana.ID])
+		requireSameUser(t, luis, byID[luis.ID])
+		require.NoError(t, emptyErr)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("duplicates", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		ana := newUser("ana@example.com", "Ana García", 30, 0)
+		luis := newUser("luis@example.com", "Luis Pérez", 40, time.Minute)
+		anaCreated := newEvent("user", "user.created", ana.ID)
+		luisCreated := newEvent("user", "user.created", luis.ID)
+		require.NoError(t, stores.Users.Create(ctx, ana, anaCreated))
+		require.NoError(t, stores.Users.Create(ctx, luis, luisCreated))
+
+		// Act
+		sameID := newUser("otra@example.com", "Otra", 20, 0)
+		sameID.ID = ana.ID
+		errSameID := stores.Users.Create(ctx, sameID, newEvent("user", "user.created", ana.ID))
+		sameEmail := newUser("ana@example.com", "Otra Ana", 20, 0)
+		errSameEmail := stores.Users.Create(ctx, sameEmail, newEvent("user", "user.created", sameEmail.ID))
+		takenEmail := *luis
+		takenEmail.Email = ana.Email
+		takenEmail.Version++
+		errUpdate := stores.Users.Update(ctx, &takenEmail, newEvent("user", "user.updated", luis.ID))
+
+		// Assert
+		assert.ErrorIs(t, errSameID, userDomain.ErrUserAlreadyExists)
+		assert.ErrorIs(t, errSameEmail, userDomain.ErrUserAlreadyExists)
+		assert.ErrorIs(t, errUpdate, userDomain.ErrUserAlreadyExists)
+		assert.ElementsMatch(t, []uuid.UUID{anaCreated.ID, luisCreated.ID}, pendingEventIDs(t, stores.Outbox), "Las escrituras fallidas no dejan eventos")
+		got, err := stores.Users.GetByID(ctx, luis.ID)
+		require.NoError(t, err)
+		requireSameUser(t, luis, got)
+	})
+
+	t.Run("criteria", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stores := newStores(t)
+		users := seedUsers(t, stores.Users)
+		minAge, maxAge := 30, 50
+
+		tests := []struct {
+			name     string
+			criteria sharedDomain.Criteria
+			want     []string
+		}{
+			{"no conditions", sharedDomain.And(), []string{"Ana García", "Luis Pérez", "Juana Ruiz", "Marta Díaz", "Ana López"}},
+			{"id", userDomain.IDCriteria{ID: users[2].ID}, []string{"Juana Ruiz"}},
+			{"email", userDomain.EmailCriteria{Email: "luis@example.com"}, []string{"Luis Pérez"}},
+			{"name ignores case", userDomain.NameLikeCriteria{Name: "ana"}, []string{"Ana García", "Juana Ruiz", "Ana López"}},
+			{"ilike ignores case on both sides", fieldCriteria{{Field: "name", Op: sharedDomain.OpILike, Value: "%GARC%"}}, []string{"Ana García"}},
+			{"ilike mixed case prefix", fieldCriteria{{Field: "email", Op: sharedDomain.OpILike, Value: "LuIs@%"}}, []string{"Luis Pérez"}},
+			{"like is anchored", fieldCriteria{{Field: "name", Op: sharedDomain.OpLike, Value: "Ana%"}}, []string{"Ana García", "Ana López"}},
+			{"like single char", fieldCriteria{{Field: "email", Op: sharedDomain.OpLike, Value: "l_is@%"}}, []string{"Luis Pérez"}},
+			{"age range", userDomain.AgeRangeCriteria{Min: &minAge, Max: &maxAge}, []string{"Luis Pérez", "Marta Díaz"}},
+			{"created after", fieldCriteria{{Field: "created_at", Op: sharedDomain.OpGt, Value: users[2].CreatedAt}}, []string{"Marta Díaz", "Ana López"}},
+			{"and", sharedDomain.And(userDomain.NameLikeCriteria{Name: "ana"}, fieldCriteria{{Field: "created_at", Op: sharedDomain.OpLt, Value: users[2].CreatedAt}}), []string{"Ana García"}},
+			{"or", sharedDomain.Or(userDomain.EmailCriteria{Email: "luis@example.com"}, userDomain.NameLikeCriteria{Name: "marta"}), []string{"Luis Pérez", "Marta Díaz"}},
+			{"not", sharedDomain.Not(userDomain.NameLikeCriteria{Name: "ana"}), []string{"Luis Pérez", "Marta Díaz"}},
+			{"and with not", sharedDomain.And(userDomain.NameLikeCriteria{Name: "ana"}, sharedDomain.Not(userDomain.AgeRangeCriteria{Max: &maxAge})), []string{"Juana Ruiz"}},
+			{"no match", userDomain.EmailCriteria{Email: "nadie@example.com"}, nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Act
+				found, err := stores.Users.ListByCriteria(ctx, tt.criteria, sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "created_at"}})
+				require.NoError(t, err)
+				total, err := stores.Users.CountByCriteria(ctx, tt.criteria)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, userNames(found))
+				assert.Equal(t, len(tt.want), total, "CountByCriteria usa el mismo filtro que ListByCriteria")
+			})
+		}
+	})
+
+	t.Run("offset pagination", func(t *testing.T) {
+		ctx := context.Background()
+		stores := newStores(t)
+		seedUsers(t, stores.Users)
+		all := sharedDomain.And()
+
+		page, err := stores.Users.ListByCriteria(ctx, all, sharedQuery.OffsetPagination{Limit: 2, Offset: 1}, []sharedQuery.Sort{{Field: "created_at"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Luis Pérez", "Juana Ruiz"}, userNames(page))
+
+		page, err = stores.Users.ListByCriteria(ctx, all, sharedQuery.OffsetPagination{Limit: 2, Offset: 0}, []sharedQuery.Sort{{Field: "name", Desc: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Marta Díaz", "Luis Pérez"}, userNames(page))
+
+		page, err = stores.Users.ListByCriteria(ctx, all, sharedQuery.OffsetPagination{Limit: 2, Offset: 10}, []sharedQuery.Sort{{Field: "created_at"}})
+		require.NoError(t, err)
+		assert.Empty(t, page)
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		// Arrange: dos usuarios con la misma fecha de alta, que se desempatan por id.
+		ctx := context.Background()
+		stores := newStores(t)
+		users := seedUsers(t, stores.Users)
+		twin := newUser("gemela@example.com", "Gemela", 25, 0)
+		twin.CreatedAt = users[1].CreatedAt
+		require.NoError(t, stores.Users.Create(ctx, twin, newEvent("user", "user.created", twin.ID)))
+		all := sharedDomain.And()
+
+		for _, desc := range []bool{false, true} {
+			t.Run(fmt.Sprintf("desc=%v", desc), func(t *testing.T) {
+				sortBy := []sharedQuery.Sort{{Field: "created_at", Desc: desc}}
+				want, err := stores.Users.ListByCriteria(ctx, all, sharedQuery.CursorPagination{Limit: 10}, sortBy)
+				require.NoError(t, err)
+				require.Len(t, want, len(users)+1)
+
+				// Act: se recorren páginas de dos en dos con el último registro como cursor.
+				var walked []*userDomain.User
+				page := sharedQuery.CursorPagination{Limit: 2}
+				for range len(want) {
+					found, err := stores.Users.ListByCriteria(ctx, all, page, sortBy)
+					require.NoError(t, err)
+					if len(found) == 0 {
+						break
+					}
+					walked = append(walked, found...)
+					last := found[len(found)-1]
+					page.Cursor = sharedQuery.NewCursor(last.ID, last.CreatedAt).Encode()
+				}
+
+				// Assert
+				assert.Equal(t, userIDs(want), userIDs(walked))
+				for i := 1; i < len(want); i++ {
+					if desc {
+						assert.False(t, want[i].CreatedAt.After(want[i-1].CreatedAt), "orden descendente")
+					} else {
+						assert.False(t, want[i].CreatedAt.Before(want[i-1].CreatedAt), "orden ascendente")
+					}
+				}
+			})
+		}
+	})
+}
+
+// newUser crea un usuario con 'age' años y dado de alta 'after' después de la fecha base.
+// La fecha de nacimiento es un día completo porque Postgres la guarda como DATE.
+func newUser(email, nombre string, age int, after time.Duration) *userDomain.User {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return &userDomain.User{
+		ID:        uuid.New(),
+		Email:     email,
+		Name:      nombre,
+		BirthDate: today.AddDate(-age, 0, -30),
+		CreatedAt: base.Add(after),
+		Version:   1,
+	}
+}
+
+// seedUsers crea cinco usuarios, en orden de fecha de alta.
+func seedUsers(t *testing.T, repo userDomain.UserRepository) []*userDomain.User {
+	t.Helper()
+	users := []*userDomain.User{
+		newUser("ana@example.com", "Ana García", 25, 0),
+		newUser("luis@example.com", "Luis Pérez", 35, time.Minute),
+		newUser("juana@example.com", "Juana Ruiz", 60, 2*time.Minute),
+		newUser("marta@example.com", "Marta Díaz", 45, 3*time.Minute),
+		newUser("ana.lopez@example.com", "Ana López", 18, 4*time.Minute),
+	}
+	for _, u := range users {
+		require.NoError(t, repo.Create(context.Background(), u, newEvent("user", "user.created", u.ID)))
+	}
+	return users
+}
+
+func requireSameUser(t *testing.T, want, got *userDomain.User) {
+	t.Helper()
+	require.NotNil(t, got)
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.Email, got.Email)
+	assert.Equal(t, want.Name, got.Name)
+	requireSameTime(t, want.BirthDate, got.BirthDate, "birth_date")
+	requireSameTime(t, want.CreatedAt, got.CreatedAt, "created_at")
+	assert.Equal(t, want.Version, got.Version)
+}
+
+func userNames(users []*userDomain.User) []string {
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	return names
+}
+
+func userIDs(users []*userDomain.User) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
@@ -0,0 +1,71 @@
+package conformance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// newUserStores construye los adaptadores con la misma factoría que usa la aplicación.
+func newUserStores(t *testing.T, backend string, opts platformDB.Options) userStore.Stores {
+	t.Helper()
+	ctx := context.Background()
+	conns := platformDB.NewConnections(opts)
+	t.Cleanup(func() { conns.Close(ctx) })
+
+	stores, err := userStore.NewStores(ctx, backend, conns)
+	require.NoError(t, err)
+	return stores
+}
+
+func TestUserRepository_Memory(t *testing.T) {
+	RunUserRepositoryTests(t, func(t *testing.T) userStore.Stores {
+		return newUserStores(t, platformDB.BackendMemory, platformDB.Options{})
+	})
+}
+
+func TestUserRepository_SQLite(t *testing.T) {
+	RunUserRepositoryTests(t, func(t *testing.T) userStore.Stores {
+		return newUserStores(t, platformDB.BackendSQLite, platformDB.Options{SQLitePath: filepath.Join(t.TempDir(), "users.db")})
+	})
+}
+
+func TestUserRepository_Postgres(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL no está configurada, saltando la suite de conformidad con Postgres")
+	}
+	RunUserRepositoryTests(t, func(t *testing.T) userStore.Stores {
+		stores := newUserStores(t, platformDB.BackendPostgres, platformDB.Options{PostgresDSN: dsn})
+		truncatePostgres(t, dsn, "users", "outbox")
+		return stores
+	})
+}
+
+func TestUserRepository_Mock(t *testing.T) {
+	RunUserRepositoryTests(t, func(t *testing.T) userStore.Stores {
+		repo := mocks.NewInMemoryUserRepo()
+		return userStore.Stores{Users: repo, Outbox: repo}
+	})
+}
+
+// truncatePostgres vacía las tablas para que cada subtest empiece sin datos.
+func truncatePostgres(t *testing.T, dsn string, tables ...string) {
+	t.Helper()
+	ctx := context.Background()
+	conns := platformDB.NewConnections(platformDB.Options{PostgresDSN: dsn})
+	defer conns.Close(ctx)
+	db, err := conns.Postgres(ctx)
+	require.NoError(t, err)
+	for _, table := range tables {
+		_, err := db.ExecContext(ctx, "TRUNCATE TABLE "+table)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,162 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/internal/shared/domain/events"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+)
+
+// Los ficheros golden se regeneran a propósito, revisando el diff, con:
+//
+//	go test ./tests/contracts -run TestEventSchemas -update
+var updateGoldens = flag.Bool("update", false, "rewrite the event schema golden files in testdata/events")
+
+const goldenDir = "testdata/events"
+
+var (
+	sampleUserID = uuid.MustParse("6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f")
+	sampleTaskID = uuid.MustParse("2b7d9e10-4f6a-4b8c-8d2e-3f4a5b6c7d8e")
+	sampleEvtID  = uuid.MustParse("9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+	sampleTime   = time.Date(2025, time.March, 1, 10, 30, 0, 0, time.UTC)
+	sampleBirth  = time.Date(1990, time.May, 17, 0, 0, 0, 0, time.UTC)
+)
+
+// samplePayloads son los valores canónicos de cada tipo que el relayer publica dentro del sobre
+// IntegrationEvent, indexados por el tipo registrado en los registros de eventos de cada dominio.
+var samplePayloads = map[reflect.Type]any{
+	reflect.TypeOf(userDomain.User{}): userDomain.User{
+		ID:        sampleUserID,
+		Email:     "ana.garcia@example.com",
+		Name:      "Ana García",
+		BirthDate: sampleBirth,
+		CreatedAt: sampleTime,
+		Version:   2,
+	},
+	reflect.TypeOf(userDomain.UserPayload{}): userDomain.NewUserPayload(&userDomain.User{
+		ID:        sampleUserID,
+		Email:     "ana.garcia@example.com",
+		Name:      "Ana García",
+		BirthDate: sampleBirth,
+		CreatedAt: sampleTime,
+		Version:   2,
+	}),
+	reflect.TypeOf(taskDomain.Task{}): taskDomain.Task{
+		ID:          sampleTaskID,
+		Title:       "Revisar el backlog",
+		Description: "Antes de la próxima release",
+		AssigneeID:  &sampleUserID,
+		Status:      taskDomain.TaskCompleted,
+		CreatedAt:   sampleTime,
+		UpdatedAt:   sampleTime.Add(time.Hour),
+		Version:     2,
+	},
+}
+
+// eventSchemas devuelve el JSON canónico de cada evento, por nombre de fichero golden: los
+// mensajes que publica el relayer para cada tipo de evento registrado y los contratos de
+// integración del paquete events, incluido el sobre IntegrationEvent.
+func eventSchemas(t *testing.T) map[string]any {
+	t.Helper()
+	schemas := map[string]any{
+		"events.UserCreated":      events.UserCreated{ID: sampleUserID, Email: "ana.garcia@example.com", Nombre: "Ana García", BirthDate: sampleBirth},
+		"events.UserUpdated":      events.UserUpdated{ID: sampleUserID, Email: "ana.garcia@example.com", Nombre: "Ana García López", BirthDate: sampleBirth},
+		"events.TaskCreated":      events.TaskCreated{ID: sampleTaskID, Title: "Revisar el backlog", Description: "Antes de la próxima release", AssigneeID: &sampleUserID},
+		"events.TaskUpdated":      events.TaskUpdated{ID: sampleTaskID, Title: "Revisar el backlog", Description: "Antes de la próxima release", Status: string(taskDomain.TaskCompleted)},
+		"events.IntegrationEvent": sampleEnvelope(userDomain.UserCreated, json.RawMessage(`{"id":"`+sampleUserID.String()+`"}`)),
+	}
+
+	registries := []map[string]events.EventMetadata{userDomain.NewEventRegistry(), taskDomain.NewEventRegistry()}
+	for _, registry := range registries {
+		for eventType, metadata := range registry {
+			sample, ok := samplePayloads[metadata.Type]
+			if !ok {
+				t.Errorf("no canonical sample for %s (%s): add one to samplePayloads", eventType, metadata.Type)
+				continue
+			}
+			data, err := json.Marshal(sample)
+			if err != nil {
+				t.Fatal(err)
+			}
+			schemas[eventType] = sampleEnvelope(eventType, data)
+		}
+	}
+	return schemas
+}
+
+// sampleEnvelope es el sobre canónico con el que el relayer publica 'data'.
+func sampleEnvelope(eventType string, data json.RawMessage) events.IntegrationEvent {
+	return events.IntegrationEvent{
+		EventID:       sampleEvtID,
+		Type:          eventType,
+		Timestamp:     sampleTime.Add(time.Second),
+		OccurredAt:    sampleTime,
+		CorrelationID: "req-7f3c",
+		CausationID:   "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a",
+		Actor:         "ana.garcia@example.com",
+		TenantID:      "acme",
+		Data:          data,
+	}
+}
+
+func TestEventSchemas(t *testing.T) {
+	schemas := eventSchemas(t)
+
+	for name, sample := range schemas {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(goldenDir, name+".json")
+
+			// Act
+			got, err := json.MarshalIndent(sample, "", "  ")
+			require.NoError(t, err)
+			got = append(got, '\n')
+
+			if *updateGoldens {
+				require.NoError(t, os.MkdirAll(goldenDir, 0o755))
+				require.NoError(t, os.WriteFile(path, got, 0o644))
+				return
+			}
+
+			// Assert
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "missing golden file; run with -update to create it")
+			// Los checkouts de Windows pueden convertir los finales de línea.
+			want = bytes.ReplaceAll(want, []byte("\r\n"), []byte("\n"))
+			assert.Equal(t, string(want), string(got),
+				"the JSON of %s changed shape, which breaks external consumers; if it is intentional, run with -update and review the diff", name)
+		})
+	}
+}
+
+// TestEventSchemas_NoOrphanGoldens evita que queden ficheros golden de eventos que ya no existen.
+func TestEventSchemas_NoOrphanGoldens(t *testing.T) {
+	schemas := eventSchemas(t)
+
+	files, err := filepath.Glob(filepath.Join(goldenDir, "*.json"))
+	require.NoError(t, err)
+
+	var orphans []string
+	for _, file := range files {
+		name := filepath.Base(file)
+		name = name[:len(name)-len(".json")]
+		if _, ok := schemas[name]; !ok {
+			orphans = append(orphans, file)
+		}
+	}
+	sort.Strings(orphans)
+	assert.Empty(t, orphans, "golden files without an event; delete them if the event was removed on purpose")
+}
@@ -7,58 +7,17 @@ import (
 	"time"
 
 	"github.com/davicafu/hexagolab/internal/shared/domain/events"
-	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
 	userConsumer "github.com/davicafu/hexagolab/internal/user/infra/inbound/events"
+	"github.com/davicafu/hexagolab/tests/mocks"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
 
-// --- FakeUserService para pruebas ---
-type FakeUserService struct {
-	Created []*userDomain.User
-	Updated []*userDomain.User
-	Users   map[uuid.UUID]*userDomain.User
-}
-
-func NewFakeUserService() *FakeUserService {
-	return &FakeUserService{
-		Created: []*userDomain.User{},
-		Updated: []*userDomain.User{},
-		Users:   make(map[uuid.UUID]*userDomain.User),
-	}
-}
-
-func (f *FakeUserService) CreateUser(ctx context.Context, email, nombre string, birthDate time.Time) (*userDomain.User, error) {
-	u := &userDomain.User{
-		ID:        uuid.New(),
-		Email:     email,
-		Nombre:    nombre,
-		BirthDate: birthDate,
-	}
-	f.Created = append(f.Created, u)
-	f.Users[u.ID] = u
-	return u, nil
-}
-
-func (f *FakeUserService) GetUser(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
-	u, ok := f.Users[id]
-	if !ok {
-		return nil, userDomain.ErrUserNotFound
-	}
-	return u, nil
-}
-
-func (f *FakeUserService) UpdateUser(ctx context.Context, u *userDomain.User) error {
-	f.Updated = append(f.Updated, u)
-	f.Users[u.ID] = u
-	return nil
-}
-
 // --- Test del UserConsumer ---
 func TestUserConsumer_HandleMessage(t *testing.T) {
 	ctx := context.Background()
-	fakeService := NewFakeUserService()
+	fakeService := mocks.NewFakeUserService()
 	consumer := userConsumer.NewUserConsumer(fakeService, zap.NewNop())
 
 	// Helper para crear IntegrationEvent con Data
@@ -84,7 +43,7 @@ func TestUserConsumer_HandleMessage(t *testing.T) {
 	consumer.HandleMessage(ctx, "user.created", payload)
 
 	assert.Len(t, fakeService.Created, 1)
-	assert.Equal(t, "Ana", fakeService.Created[0].Nombre)
+	assert.Equal(t, "Ana", fakeService.Created[0].Name)
 	assert.Equal(t, "ana@example.com", fakeService.Created[0].Email)
 
 	// --- 2. Evento UserUpdated válido ---
@@ -98,9 +57,13 @@ func TestUserConsumer_HandleMessage(t *testing.T) {
 	consumer.HandleMessage(ctx, "user.updated", payload)
 
 	assert.Len(t, fakeService.Updated, 1)
-	assert.Equal(t, "Ana Updated", fakeService.Updated[0].Nombre)
+	assert.Equal(t, "Ana Updated", fakeService.Updated[0].Name)
 	assert.Equal(t, "ana2@example.com", fakeService.Updated[0].Email)
 
+	// El mismo evento otra vez (ej. el servicio consume lo que él mismo publicó) no actualiza
+	consumer.HandleMessage(ctx, "user.updated", payload)
+	assert.Len(t, fakeService.Updated, 1)
+
 	// --- 3. Evento con payload malformado ---
 	badPayload := []byte(`{"Type": "user.created", "Data": "bad json"`)
 	consumer.HandleMessage(ctx, "user.created", badPayload)
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
-	"github.com/davicafu/hexagolab/internal/shared/domain/events"
 	"github.com/davicafu/hexagolab/internal/user/application"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
 	"github.com/davicafu/hexagolab/tests/mocks"
@@ -22,7 +21,7 @@ import (
 type userHTTPResponse struct {
 	ID        string `json:"id"`
 	Email     string `json:"email"`
-	Nombre    string `json:"nombre"`
+	Name      string `json:"name"`
 	BirthDate string `json:"birth_date"`
 	CreatedAt string `json:"created_at"`
 }
@@ -32,14 +31,14 @@ func TestGetUser_HTTPContract(t *testing.T) {
 	repo := mocks.NewInMemoryUserRepo()
 	cache := mocks.NewDummyCache()
 
-	service := application.NewUserService(repo, cache, zap.NewNop())
+	service := application.NewUserService(repo, cache, nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
 
 	// Crear usuario de prueba
 	userID := uuid.New()
 	u := &userDomain.User{
 		ID:        userID,
 		Email:     "test@example.com",
-		Nombre:    "Test User",
+		Name:      "Test User",
 		BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
 		CreatedAt: time.Now(),
 	}
@@ -65,7 +64,7 @@ func TestGetUser_HTTPContract(t *testing.T) {
 		json.NewEncoder(w).Encode(userHTTPResponse{
 			ID:        user.ID.String(),
 			Email:     user.Email,
-			Nombre:    user.Nombre,
+			Name:      user.Name,
 			BirthDate: user.BirthDate.Format("2006-01-02"),
 			CreatedAt: user.CreatedAt.Format(time.RFC3339),
 		})
@@ -82,7 +81,7 @@ func TestGetUser_HTTPContract(t *testing.T) {
 	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
 	assert.Equal(t, u.ID.String(), resp.ID)
 	assert.Equal(t, u.Email, resp.Email)
-	assert.Equal(t, u.Nombre, resp.Nombre)
+	assert.Equal(t, u.Name, resp.Name)
 	assert.Equal(t, u.BirthDate.Format("2006-01-02"), resp.BirthDate)
 
 	// Test: usuario no existente
@@ -94,17 +93,3 @@ func TestGetUser_HTTPContract(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rec2.Code)
 	assert.Contains(t, rec2.Body.String(), "user not found")
 }
-
-// --- MockPublisher captura eventos publicados ---
-type MockPublisher struct {
-	Published []events.IntegrationEvent
-}
-
-func (m *MockPublisher) Publish(ctx context.Context, eventType string, payload []byte) error {
-	var ie events.IntegrationEvent
-	if err := json.Unmarshal(payload, &ie); err != nil {
-		return err
-	}
-	m.Published = append(m.Published, ie)
-	return nil
-}
@@ -0,0 +1,191 @@
+// Package e2e prueba la API HTTP (REST y GraphQL) de punta a punta: el router Gin real, los
+// servicios y los adaptadores en memoria, compuestos igual que en el modo demo pero sin arrancar
+// la aplicación.
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	infraEvents "github.com/davicafu/hexagolab/internal/shared/infra/events"
+	sharedGraphql "github.com/davicafu/hexagolab/internal/shared/infra/inbound/graphql"
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	platformDB "github.com/davicafu/hexagolab/internal/shared/infra/platform/db"
+	"github.com/davicafu/hexagolab/internal/shared/infra/relayer"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskEvents "github.com/davicafu/hexagolab/internal/task/infra/inbound/events"
+	taskGraphql "github.com/davicafu/hexagolab/internal/task/infra/inbound/graphql"
+	taskHttp "github.com/davicafu/hexagolab/internal/task/infra/inbound/http"
+	taskStore "github.com/davicafu/hexagolab/internal/task/infra/outbound/db"
+	userApp "github.com/davicafu/hexagolab/internal/user/application"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userGraphql "github.com/davicafu/hexagolab/internal/user/infra/inbound/graphql"
+	userHttp "github.com/davicafu/hexagolab/internal/user/infra/inbound/http"
+	userStore "github.com/davicafu/hexagolab/internal/user/infra/outbound/db"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// App es una aplicación lista para recibir peticiones en un test.
+type App struct {
+	Router *gin.Engine
+	Users  userStore.Stores
+	Tasks  taskStore.Stores
+	// UserBus y TaskBus reciben lo que publica Relay.
+	UserBus *infraEvents.InMemoryEventBus
+	TaskBus *infraEvents.InMemoryEventBus
+
+	relayers []*relayer.Worker
+}
+
+// AppBuilder compone una App. Por defecto usa el reloj del sistema e IDs aleatorios.
+type AppBuilder struct {
+	clock sharedDomain.Clock
+	ids   sharedDomain.IDGenerator
+}
+
+func NewAppBuilder() *AppBuilder {
+	return &AppBuilder{clock: sharedDomain.SystemClock{}, ids: sharedDomain.RandomIDGenerator{}}
+}
+
+// WithClock fija el reloj de los servicios, p.ej. un mocks.FakeClock para controlar las fechas.
+func (b *AppBuilder) WithClock(clock sharedDomain.Clock) *AppBuilder {
+	b.clock = clock
+	return b
+}
+
+// WithIDs fija el generador de IDs, p.ej. un mocks.SequentialIDGenerator.
+func (b *AppBuilder) WithIDs(ids sharedDomain.IDGenerator) *AppBuilder {
+	b.ids = ids
+	return b
+}
+
+// Build crea los almacenes en memoria con las mismas factorías que la aplicación y registra las
+// rutas de usuarios, tareas, contadores de tareas, change feeds, long polling y GraphQL en un
+// router con los middlewares propios. Los contadores se mantienen con los eventos que publica Relay.
+func (b *AppBuilder) Build(t *testing.T) *App {
+	t.Helper()
+	ctx := context.Background()
+	log := zap.NewNop()
+
+	conns := platformDB.NewConnections(platformDB.Options{})
+	t.Cleanup(func() { conns.Close(ctx) })
+	users, err := userStore.NewStores(ctx, platformDB.BackendMemory, conns)
+	require.NoError(t, err)
+	tasks, err := taskStore.NewStores(ctx, platformDB.BackendMemory, conns)
+	require.NoError(t, err)
+
+	userService := userApp.NewUserService(users.Users, mocks.NewDummyCache(), nil, b.clock, b.ids, log)
+	taskService := taskApp.NewTaskService(tasks.Tasks, mocks.NewDummyCache(), nil, b.clock, b.ids, log)
+	counters := taskApp.NewTaskCounterService(tasks.Counters)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(sharedHttp.TracingMiddleware(), sharedHttp.RecoveryMiddleware(log))
+	userHttp.RegisterUserRoutes(router, userHttp.NewUserHandler(userService, b.clock))
+	taskHttp.RegisterTaskRoutes(router, taskHttp.NewTaskHandler(taskService, b.clock))
+	taskHttp.RegisterTaskSummaryRoutes(router, taskHttp.NewTaskSummaryHandler(counters))
+	router.GET("/users/changes", sharedHttp.NewChangeFeedHandler(users.Outbox.(sharedDomain.OutboxChangeReader), relayer.NewEncoder(userDomain.NewEventRegistry()), b.clock).Changes)
+	router.GET("/tasks/changes", sharedHttp.NewChangeFeedHandler(tasks.Outbox.(sharedDomain.OutboxChangeReader), relayer.NewEncoder(taskDomain.NewEventRegistry()), b.clock).Changes)
+
+	app := &App{
+		Router:  router,
+		Users:   users,
+		Tasks:   tasks,
+		UserBus: infraEvents.NewInMemoryEventBus(userDomain.UserTopic),
+		TaskBus: infraEvents.NewInMemoryEventBus(taskDomain.TaskTopic),
+	}
+	feed := app.feed(t, log)
+	app.consumeTasks(t, taskService, counters, log)
+	schema, err := sharedGraphql.NewSchema(&graphqlResolver{
+		userResolver: userGraphql.NewResolver(userService, feed, b.clock),
+		taskResolver: taskGraphql.NewResolver(taskService, feed),
+	}, 10, log, userGraphql.Schema, taskGraphql.Schema)
+	require.NoError(t, err)
+	graphqlHandler := sharedGraphql.NewHandler(schema)
+	router.GET("/graphql", graphqlHandler.Serve)
+	router.POST("/graphql", graphqlHandler.Serve)
+	taskHttp.RegisterTaskUpdatesRoutes(router, taskHttp.NewTaskUpdatesHandler(taskService, feed, time.Minute))
+	app.relayers = []*relayer.Worker{
+		relayer.NewOutboxWorker(users.Outbox, app.UserBus, userDomain.NewEventRegistry(), time.Second, 100, b.clock, log),
+		relayer.NewOutboxWorker(tasks.Outbox, app.TaskBus, taskDomain.NewEventRegistry(), time.Second, 100, b.clock, log),
+	}
+	return app
+}
+
+// Alias para embeber los resolvers de los módulos, que se llaman igual.
+type (
+	userResolver = userGraphql.Resolver
+	taskResolver = taskGraphql.Resolver
+)
+
+// graphqlResolver es el resolver raíz del esquema GraphQL, como el de la aplicación.
+type graphqlResolver struct {
+	*userResolver
+	*taskResolver
+}
+
+// feed crea el feed de las suscripciones GraphQL y del long polling con los eventos que Relay publica en UserBus y
+// TaskBus.
+func (a *App) feed(t *testing.T, log *zap.Logger) *infraEvents.Feed {
+	feed := infraEvents.NewFeed(log)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	for topic, bus := range map[string]*infraEvents.InMemoryEventBus{userDomain.UserTopic: a.UserBus, taskDomain.TaskTopic: a.TaskBus} {
+		ch, handler := bus.Subscribe(10), feed.Handler(topic)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg := <-ch:
+					handler.HandleMessage(ctx, "", msg.([]byte))
+				}
+			}
+		}()
+	}
+	return feed
+}
+
+// consumeTasks consume TaskBus con el consumidor de tareas de la aplicación, que mantiene los
+// contadores de tareas por usuario.
+func (a *App) consumeTasks(t *testing.T, service *taskApp.TaskService, counters *taskApp.TaskCounterService, log *zap.Logger) {
+	consumer := taskEvents.NewTaskConsumer(service, log)
+	consumer.SetCounters(counters)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	taskEvents.BackgroundConsumerChan(ctx, a.TaskBus.Subscribe(10), consumer, infraEvents.NewHooks())
+}
+
+// Client devuelve un cliente HTTP contra el router de la aplicación.
+func (a *App) Client(t *testing.T) *Client {
+	return &Client{t: t, handler: a.Router}
+}
+
+// PendingEvents devuelve los tipos de los eventos pendientes en los outbox de usuarios y tareas,
+// en el orden en que se escribieron.
+func (a *App) PendingEvents(t *testing.T) []string {
+	t.Helper()
+	var types []string
+	for _, outbox := range []sharedDomain.OutboxRepository{a.Users.Outbox, a.Tasks.Outbox} {
+		pending, err := outbox.FetchPendingOutbox(context.Background(), 1000)
+		require.NoError(t, err)
+		for _, evt := range pending {
+			types = append(types, evt.EventType)
+		}
+	}
+	return types
+}
+
+// Relay ejecuta una pasada de los relayers: publica los eventos pendientes en UserBus y TaskBus.
+func (a *App) Relay() {
+	for _, worker := range a.relayers {
+		worker.ProcessBatch(context.Background())
+	}
+}
@@ -0,0 +1,73 @@
+package e2e
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedHttp "github.com/davicafu/hexagolab/internal/shared/infra/inbound/http"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+func TestChangeFeed_PagesThroughEventsInOrder(t *testing.T) {
+	// Arrange: un alta y una actualización, una publicada en el bus y la otra pendiente
+	clock := mocks.NewFakeClock(now)
+	app := NewAppBuilder().WithClock(clock).WithIDs(mocks.NewSequentialIDGenerator()).Build(t)
+	client := app.Client(t)
+	created := createUser(client, "ada@example.com", "Ada", "1990-05-01")
+	app.Relay()
+	clock.Advance(time.Second)
+	client.PUT("/users/"+created.ID.String(), map[string]string{"name": "Ada Lovelace"}).Expect(http.StatusOK)
+	clock.Advance(time.Minute)
+
+	// Act: página a página, de un evento
+	var first, second, last sharedHttp.ChangeFeedPage
+	client.GET("/users/changes").Query("limit", "1").Expect(http.StatusOK).Data(&first)
+	client.GET("/users/changes").Query("limit", "1").Query("since", first.NextCursor).Expect(http.StatusOK).Data(&second)
+	client.GET("/users/changes").Query("since", second.NextCursor).Expect(http.StatusOK).Data(&last)
+
+	// Assert
+	require.Len(t, first.Events, 1)
+	assert.Equal(t, userDomain.UserCreated, first.Events[0].Type)
+	assert.True(t, first.HasMore)
+	require.Len(t, second.Events, 1)
+	assert.Equal(t, userDomain.UserUpdated, second.Events[0].Type)
+	assert.False(t, second.HasMore)
+	assert.Empty(t, last.Events)
+	assert.Equal(t, second.NextCursor, last.NextCursor, "an empty page keeps the cursor")
+
+	var tasks sharedHttp.ChangeFeedPage
+	client.GET("/tasks/changes").Expect(http.StatusOK).Data(&tasks)
+	assert.Empty(t, tasks.Events)
+}
+
+func TestChangeFeed_WithholdsUnsettledEvents(t *testing.T) {
+	// Arrange
+	clock := mocks.NewFakeClock(now)
+	app := NewAppBuilder().WithClock(clock).Build(t)
+	client := app.Client(t)
+	createUser(client, "ada@example.com", "Ada", "1990-05-01")
+
+	// Act + Assert: el evento recién escrito aún no se sirve
+	var page sharedHttp.ChangeFeedPage
+	client.GET("/users/changes").Expect(http.StatusOK).Data(&page)
+	assert.Empty(t, page.Events)
+	assert.Empty(t, page.NextCursor)
+
+	clock.Advance(3 * time.Second)
+	client.GET("/users/changes").Expect(http.StatusOK).Data(&page)
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, userDomain.UserCreated, page.Events[0].Type)
+}
+
+func TestChangeFeed_RejectsInvalidCursor(t *testing.T) {
+	// Arrange
+	client := NewAppBuilder().Build(t).Client(t)
+
+	// Act + Assert
+	client.GET("/users/changes").Query("since", "not-a-cursor").Expect(http.StatusBadRequest)
+}
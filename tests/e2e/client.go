@@ -0,0 +1,123 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Client hace peticiones contra un http.Handler con una API encadenable:
+//
+//	client.POST("/users/", body).Expect(http.StatusCreated).JSON(&user)
+type Client struct {
+	t       *testing.T
+	handler http.Handler
+}
+
+func (c *Client) GET(path string) *Request { return c.newRequest(http.MethodGet, path, nil) }
+func (c *Client) POST(path string, body any) *Request {
+	return c.newRequest(http.MethodPost, path, body)
+}
+func (c *Client) PUT(path string, body any) *Request { return c.newRequest(http.MethodPut, path, body) }
+func (c *Client) DELETE(path string) *Request        { return c.newRequest(http.MethodDelete, path, nil) }
+
+func (c *Client) newRequest(method, path string, body any) *Request {
+	return &Request{client: c, method: method, path: path, body: body, query: url.Values{}, header: http.Header{}}
+}
+
+// Request es una petición pendiente de enviar.
+type Request struct {
+	client *Client
+	method string
+	path   string
+	body   any
+	query  url.Values
+	header http.Header
+}
+
+// Query añade un parámetro a la query string.
+func (r *Request) Query(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// Header añade una cabecera a la petición.
+func (r *Request) Header(name, value string) *Request {
+	r.header.Add(name, value)
+	return r
+}
+
+// Expect envía la petición y falla el test si el código de estado no es 'status'. Un body de
+// tipo string se envía tal cual, para probar JSON mal formado; cualquier otro se serializa.
+func (r *Request) Expect(status int) *Response {
+	t := r.client.t
+	t.Helper()
+
+	var body io.Reader
+	switch b := r.body.(type) {
+	case nil:
+	case string:
+		body = bytes.NewBufferString(b)
+	default:
+		data, err := json.Marshal(b)
+		require.NoError(t, err)
+		body = bytes.NewReader(data)
+	}
+
+	target := r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+	req := httptest.NewRequest(r.method, target, body)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	r.client.handler.ServeHTTP(rec, req)
+
+	require.Equalf(t, status, rec.Code, "%s %s: %s", r.method, target, rec.Body.String())
+	return &Response{t: t, rec: rec}
+}
+
+// Response es la respuesta de una petición ya enviada.
+type Response struct {
+	t   *testing.T
+	rec *httptest.ResponseRecorder
+}
+
+// JSON decodifica el cuerpo en 'dest'.
+func (r *Response) JSON(dest any) *Response {
+	r.t.Helper()
+	require.NoError(r.t, json.Unmarshal(r.rec.Body.Bytes(), dest), r.rec.Body.String())
+	return r
+}
+
+// Data decodifica en 'dest' el campo "data" de las respuestas envueltas con utils.SendSuccess.
+func (r *Response) Data(dest any) *Response {
+	r.t.Helper()
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	r.JSON(&envelope)
+	require.NoError(r.t, json.Unmarshal(envelope.Data, dest), r.rec.Body.String())
+	return r
+}
+
+// Header devuelve la cabecera 'name' de la respuesta.
+func (r *Response) Header(name string) string {
+	return r.rec.Header().Get(name)
+}
+
+// Body devuelve el cuerpo sin decodificar.
+func (r *Response) Body() string {
+	return r.rec.Body.String()
+}
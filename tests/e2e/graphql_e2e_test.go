@@ -0,0 +1,183 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// graphqlResponse es la respuesta de /graphql.
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// graphql ejecuta 'query' con 'variables' y decodifica data en 'dest' (si no es nil).
+func graphql(client *Client, query string, variables map[string]any, dest any) graphqlResponse {
+	client.t.Helper()
+	var resp graphqlResponse
+	client.POST("/graphql", map[string]any{"query": query, "variables": variables}).Expect(http.StatusOK).JSON(&resp)
+	if dest != nil {
+		require.Empty(client.t, resp.Errors)
+		require.NoError(client.t, json.Unmarshal(resp.Data, dest))
+	}
+	return resp
+}
+
+func TestGraphQLAPI_UsersAndTasks(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().WithClock(mocks.NewFakeClock(now)).WithIDs(mocks.NewSequentialIDGenerator()).Build(t)
+	client := app.Client(t)
+
+	// Act + Assert: alta de un usuario y de dos tareas suyas
+	var created struct {
+		CreateUser struct{ ID, Name string }
+	}
+	graphql(client, `mutation($input: CreateUserInput!) { createUser(input: $input) { id name } }`,
+		map[string]any{"input": map[string]any{"email": "ada@example.com", "name": "Ada", "birthDate": "1990-05-01"}}, &created)
+	assert.Equal(t, mocks.SequentialID(1).String(), created.CreateUser.ID)
+	userID := created.CreateUser.ID
+
+	for _, title := range []string{"Revisar el backlog", "Preparar la demo"} {
+		graphql(client, `mutation($input: CreateTaskInput!) { createTask(input: $input) { id } }`,
+			map[string]any{"input": map[string]any{"title": title, "assigneeId": userID}}, &struct{}{})
+	}
+
+	// Listado por cursor, filtrado y en páginas de una tarea
+	const listTasks = `query($assignee: ID!, $after: String) {
+		tasks(filter: {assigneeId: $assignee, status: "pending"}, first: 1, after: $after, sort: "title") {
+			nodes { title status }
+			pageInfo { endCursor hasNextPage }
+			totalCount
+		}
+	}`
+	type tasksPage struct {
+		Tasks struct {
+			Nodes    []struct{ Title, Status string }
+			PageInfo struct {
+				EndCursor   string
+				HasNextPage bool
+			}
+			TotalCount int
+		}
+	}
+	var first, second tasksPage
+	graphql(client, listTasks, map[string]any{"assignee": userID}, &first)
+	require.Len(t, first.Tasks.Nodes, 1)
+	assert.Equal(t, "Preparar la demo", first.Tasks.Nodes[0].Title)
+	assert.True(t, first.Tasks.PageInfo.HasNextPage)
+	assert.Equal(t, 2, first.Tasks.TotalCount)
+	graphql(client, listTasks, map[string]any{"assignee": userID, "after": first.Tasks.PageInfo.EndCursor}, &second)
+	require.Len(t, second.Tasks.Nodes, 1)
+	assert.Equal(t, "Revisar el backlog", second.Tasks.Nodes[0].Title)
+	assert.False(t, second.Tasks.PageInfo.HasNextPage)
+
+	// Actualización parcial y borrado
+	var updated struct {
+		UpdateUser struct{ Name, Email string }
+	}
+	graphql(client, `mutation($id: ID!) { updateUser(id: $id, input: {name: "Ada Lovelace"}) { name email } }`,
+		map[string]any{"id": userID}, &updated)
+	assert.Equal(t, "Ada Lovelace", updated.UpdateUser.Name)
+	assert.Equal(t, "ada@example.com", updated.UpdateUser.Email)
+
+	var deleted struct{ DeleteUser bool }
+	graphql(client, `mutation($id: ID!) { deleteUser(id: $id) }`, map[string]any{"id": userID}, &deleted)
+	assert.True(t, deleted.DeleteUser)
+	var got struct{ User *struct{ ID string } }
+	graphql(client, `query($id: ID!) { user(id: $id) { id } }`, map[string]any{"id": userID}, &got)
+	assert.Nil(t, got.User)
+}
+
+func TestGraphQLAPI_Errors(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+
+	cases := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"invalid email", `mutation { createUser(input: {email: "nope", name: "Ada", birthDate: "1990-05-01"}) { id } }`, "invalid_argument"},
+		{"invalid status", `{ tasks(filter: {status: "archived"}) { totalCount } }`, "invalid_argument"},
+		{"unknown sort field", `{ users(sort: "password") { totalCount } }`, "invalid_argument"},
+		{"not found", `mutation { deleteTask(id: "` + mocks.SequentialID(99).String() + `") }`, "not_found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			resp := graphql(app.Client(t), tc.query, nil, nil)
+
+			// Assert
+			require.Len(t, resp.Errors, 1)
+			assert.Equal(t, tc.code, resp.Errors[0].Extensions["code"])
+		})
+	}
+
+	// Las peticiones que no se pueden ejecutar responden 400
+	client.POST("/graphql", map[string]any{"variables": map[string]any{}}).Expect(http.StatusBadRequest)
+	client.GET("/graphql").Query("query", "{ users { nodes { nope } } }").Expect(http.StatusOK)
+}
+
+func TestGraphQLAPI_SubscriptionOverSSE(t *testing.T) {
+	// Arrange: un servidor real, para leer el stream mientras llegan los eventos
+	app := NewAppBuilder().Build(t)
+	server := httptest.NewServer(app.Router)
+	t.Cleanup(server.Close)
+
+	body, err := json.Marshal(map[string]string{"query": `subscription { userEvents { type user { name } } }`})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/graphql", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Act: la suscripción ya está registrada cuando llegan las cabeceras
+	createUser(app.Client(t), "ada@example.com", "Ada", "1990-05-01")
+	app.Relay()
+
+	// Assert
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	var event, data string
+	for data == "" {
+		select {
+		case line, ok := <-lines:
+			require.True(t, ok, "stream closed before the event")
+			if v, found := strings.CutPrefix(line, "event: "); found {
+				event = v
+			} else if v, found := strings.CutPrefix(line, "data: "); found {
+				data = v
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("the user.created event was not delivered")
+		}
+	}
+	assert.Equal(t, "next", event)
+	assert.JSONEq(t, `{"data":{"userEvents":{"type":"user.created","user":{"name":"Ada"}}}}`, data)
+}
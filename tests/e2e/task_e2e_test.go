@@ -1 +1,564 @@
 package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskHttp "github.com/davicafu/hexagolab/internal/task/infra/inbound/http"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+type createTaskRequest struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	AssigneeID  uuid.UUID `json:"assignee_id"`
+}
+
+// taskResponse es una tarea en las respuestas HTTP de /tasks.
+type taskResponse struct {
+	ID          uuid.UUID             `json:"id"`
+	Title       string                `json:"title"`
+	Description string                `json:"description"`
+	AssigneeID  *uuid.UUID            `json:"assignee_id"`
+	Status      taskDomain.TaskStatus `json:"status"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+	Version     int64                 `json:"version"`
+}
+
+func createTask(client *Client, title string, assignee uuid.UUID) taskResponse {
+	var task taskResponse
+	client.POST("/tasks/", createTaskRequest{Title: title, Description: "Descripción de " + title, AssigneeID: assignee}).
+		Expect(http.StatusCreated).JSON(&task)
+	return task
+}
+
+func taskTitles(tasks []taskResponse) []string {
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+func TestTaskAPI_CRUD(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().WithClock(mocks.NewFakeClock(now)).WithIDs(mocks.NewSequentialIDGenerator()).Build(t)
+	client := app.Client(t)
+	assignee := uuid.New()
+
+	// Act + Assert: alta
+	created := createTask(client, "Revisar el backlog", assignee)
+	assert.Equal(t, mocks.SequentialID(1), created.ID)
+	assert.Equal(t, taskDomain.TaskPending, created.Status)
+	assert.Equal(t, &assignee, created.AssigneeID)
+	assert.True(t, now.Equal(created.CreatedAt))
+	path := "/tasks/" + created.ID.String()
+
+	// Lectura, con los nombres de campo en snake_case como los de los usuarios
+	var got taskResponse
+	var fields map[string]any
+	client.GET(path).Expect(http.StatusOK).JSON(&got).JSON(&fields)
+	assert.Equal(t, "Revisar el backlog", got.Title)
+	assert.Equal(t, "Descripción de Revisar el backlog", got.Description)
+	assert.ElementsMatch(t, []string{"id", "title", "description", "assignee_id", "status", "created_at", "updated_at", "version"}, slices.Collect(maps.Keys(fields)))
+
+	// Actualización parcial: solo cambia lo que se envía
+	var updated taskResponse
+	client.PUT(path, map[string]string{"title": "Revisar el backlog del sprint"}).Expect(http.StatusOK).JSON(&updated)
+	assert.Equal(t, "Revisar el backlog del sprint", updated.Title)
+	assert.Equal(t, "Descripción de Revisar el backlog", updated.Description)
+	client.GET(path).Expect(http.StatusOK).JSON(&got)
+	assert.Equal(t, "Revisar el backlog del sprint", got.Title)
+
+	// Borrado
+	client.DELETE(path).Expect(http.StatusNoContent)
+	client.GET(path).Expect(http.StatusNotFound)
+
+	assert.Equal(t, []string{taskDomain.TaskCreated, taskDomain.TaskUpdated, taskDomain.TaskDeleted}, app.PendingEvents(t))
+}
+
+func TestTaskAPI_Errors(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	createTask(app.Client(t), "Revisar el backlog", uuid.New())
+	unknown := "/tasks/" + mocks.SequentialID(99).String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+	}{
+		{"malformed json", http.MethodPost, "/tasks/", `{"title":`, http.StatusBadRequest},
+		{"missing title", http.MethodPost, "/tasks/", map[string]string{"assignee_id": uuid.NewString()}, http.StatusBadRequest},
+		{"invalid assignee", http.MethodPost, "/tasks/", map[string]string{"title": "Tarea", "assignee_id": "nadie"}, http.StatusBadRequest},
+		{"get invalid id", http.MethodGet, "/tasks/not-a-uuid", nil, http.StatusBadRequest},
+		{"get unknown", http.MethodGet, unknown, nil, http.StatusNotFound},
+		{"update invalid id", http.MethodPut, "/tasks/not-a-uuid", map[string]string{}, http.StatusBadRequest},
+		{"update malformed json", http.MethodPut, unknown, `{"title":`, http.StatusBadRequest},
+		{"update unknown", http.MethodPut, unknown, map[string]string{"title": "Nada"}, http.StatusNotFound},
+		{"delete invalid id", http.MethodDelete, "/tasks/not-a-uuid", nil, http.StatusBadRequest},
+		{"delete unknown", http.MethodDelete, unknown, nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.Client(t).newRequest(tt.method, tt.path, tt.body).Expect(tt.status)
+		})
+	}
+
+	// Las peticiones rechazadas no dejan eventos.
+	assert.Equal(t, []string{taskDomain.TaskCreated}, app.PendingEvents(t))
+}
+
+func TestTaskAPI_Unassigned(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	assigned := createTask(client, "Revisar el backlog", uuid.New())
+
+	// Act: alta sin assignee_id y con null
+	var withoutField, withNull taskResponse
+	var fields map[string]any
+	client.POST("/tasks/", map[string]string{"title": "Preparar la demo"}).Expect(http.StatusCreated).JSON(&withoutField).JSON(&fields)
+	client.POST("/tasks/", map[string]any{"title": "Revisar la caché", "assignee_id": nil}).Expect(http.StatusCreated).JSON(&withNull)
+
+	// Assert
+	assert.Nil(t, withoutField.AssigneeID)
+	assert.Contains(t, fields, "assignee_id", "Una tarea sin asignar lleva assignee_id a null")
+	assert.Nil(t, fields["assignee_id"])
+	assert.Nil(t, withNull.AssigneeID)
+	var got taskResponse
+	client.GET("/tasks/" + withoutField.ID.String()).Expect(http.StatusOK).JSON(&got)
+	assert.Nil(t, got.AssigneeID)
+
+	var unassigned, byAssignee, byLegacyName []taskResponse
+	client.GET("/tasks/").Query("unassigned", "true").Query("sort_field", "title").Expect(http.StatusOK).JSON(&unassigned)
+	assert.Equal(t, []string{"Preparar la demo", "Revisar la caché"}, taskTitles(unassigned))
+	client.GET("/tasks/").Query("assignee_id", assigned.AssigneeID.String()).Expect(http.StatusOK).JSON(&byAssignee)
+	assert.Equal(t, []string{"Revisar el backlog"}, taskTitles(byAssignee))
+	client.GET("/tasks/").Query("assigneeId", assigned.AssigneeID.String()).Expect(http.StatusOK).JSON(&byLegacyName)
+	assert.Equal(t, []string{"Revisar el backlog"}, taskTitles(byLegacyName), "Se sigue aceptando el nombre antiguo")
+	client.GET("/tasks/").Query("unassigned", "false").Expect(http.StatusBadRequest)
+
+	var gql struct {
+		Tasks struct {
+			Nodes []struct{ AssigneeID *string }
+		}
+	}
+	graphql(client, `{ tasks(filter: {unassigned: true}) { nodes { assigneeId } } }`, nil, &gql)
+	require.Len(t, gql.Tasks.Nodes, 2)
+	assert.Nil(t, gql.Tasks.Nodes[0].AssigneeID)
+}
+
+func TestTaskAPI_ListFiltersAndPagination(t *testing.T) {
+	// Arrange: cinco tareas de dos asignados creadas con un minuto de diferencia.
+	clock := mocks.NewFakeClock(now)
+	app := NewAppBuilder().WithClock(clock).Build(t)
+	client := app.Client(t)
+	ana, luis := uuid.New(), uuid.New()
+	for i, title := range []string{"Revisar el backlog", "Preparar la demo", "Revisar la caché", "Migrar el outbox", "Probar el backlog"} {
+		assignee := ana
+		if i%2 == 1 {
+			assignee = luis
+		}
+		createTask(client, title, assignee)
+		clock.Advance(time.Minute)
+	}
+
+	tests := []struct {
+		name  string
+		query map[string]string
+		want  []string
+	}{
+		{"newest first by default", map[string]string{}, []string{"Probar el backlog", "Migrar el outbox", "Revisar la caché", "Preparar la demo", "Revisar el backlog"}},
+		{"title ignores case", map[string]string{"title": "BACKLOG"}, []string{"Probar el backlog", "Revisar el backlog"}},
+		{"assignee", map[string]string{"assignee_id": luis.String()}, []string{"Migrar el outbox", "Preparar la demo"}},
+		{"status", map[string]string{"status": string(taskDomain.TaskCompleted)}, nil},
+		{"sort ascending", map[string]string{"sort_field": "title"}, []string{"Migrar el outbox", "Preparar la demo", "Probar el backlog", "Revisar el backlog", "Revisar la caché"}},
+		{"sort descending", map[string]string{"sort_field": "title", "sort_desc": "true", "limit": "2"}, []string{"Revisar la caché", "Revisar el backlog"}},
+		{"sort list", map[string]string{"sort": "-title", "limit": "2"}, []string{"Revisar la caché", "Revisar el backlog"}},
+		{"sort list with several fields", map[string]string{"sort": "status ASC, created_at DESC", "limit": "2"}, []string{"Probar el backlog", "Migrar el outbox"}},
+		{"offset", map[string]string{"limit": "2", "offset": "2"}, []string{"Revisar la caché", "Preparar la demo"}},
+		{"offset past the end", map[string]string{"offset": "10"}, nil},
+		{"created_at range", map[string]string{"created_at[gte]": now.Add(time.Minute).Format(time.RFC3339), "created_at[lte]": now.Add(2 * time.Minute).Format(time.RFC3339)}, []string{"Revisar la caché", "Preparar la demo"}},
+		{"created within, from the injected clock", map[string]string{"created_within": "150s"}, []string{"Probar el backlog", "Migrar el outbox"}},
+		{"created within days", map[string]string{"created_within": "1d"}, []string{"Probar el backlog", "Migrar el outbox", "Revisar la caché", "Preparar la demo", "Revisar el backlog"}},
+		{"not updated lately", map[string]string{"updated_before": "150s"}, []string{"Revisar la caché", "Preparar la demo", "Revisar el backlog"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			req := app.Client(t).GET("/tasks/")
+			for key, value := range tt.query {
+				req.Query(key, value)
+			}
+			var tasks []taskResponse
+			req.Expect(http.StatusOK).JSON(&tasks)
+
+			// Assert
+			assert.Equal(t, tt.want, taskTitles(tasks))
+		})
+	}
+
+	// Los metadatos de la página van en cabeceras para no cambiar el cuerpo, que es un array.
+	resp := client.GET("/tasks/").Query("assignee_id", ana.String()).Query("limit", "2").Expect(http.StatusOK)
+	assert.Equal(t, "3", resp.Header("X-Total-Count"))
+	assert.Equal(t, "true", resp.Header("X-Has-More"))
+	resp = client.GET("/tasks/").Query("assignee_id", ana.String()).Query("limit", "2").Query("offset", "2").Expect(http.StatusOK)
+	assert.Equal(t, "false", resp.Header("X-Has-More"))
+	assert.Empty(t, resp.Header("X-Next-Cursor"), "La paginación por offset no devuelve cursor")
+
+	// Con cursor (vacío en la primera página) se sigue X-Next-Cursor hasta que no queda ninguna.
+	var walked []taskResponse
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		var page []taskResponse
+		resp = client.GET("/tasks/").Query("assignee_id", ana.String()).Query("limit", "2").Query("cursor", cursor).Expect(http.StatusOK).JSON(&page)
+		walked = append(walked, page...)
+		if cursor = resp.Header("X-Next-Cursor"); cursor == "" {
+			assert.Equal(t, "false", resp.Header("X-Has-More"))
+			break
+		}
+	}
+	assert.Equal(t, []string{"Probar el backlog", "Revisar la caché", "Revisar el backlog"}, taskTitles(walked))
+	client.GET("/tasks/").Query("cursor", "not-a-cursor").Expect(http.StatusBadRequest)
+
+	// Un campo de ordenación desconocido se rechaza antes de llegar al SQL.
+	client.GET("/tasks/").Query("sort_field", "title; DROP TABLE tasks").Expect(http.StatusBadRequest)
+	client.GET("/tasks/").Query("sort", "status,nope").Expect(http.StatusBadRequest)
+	client.GET("/tasks/").Query("sort", "status sideways").Expect(http.StatusBadRequest)
+
+	// Los filtros inválidos se rechazan con el motivo de cada parámetro.
+	var invalid struct {
+		Error struct {
+			Code    string            `json:"code"`
+			Details map[string]string `json:"details"`
+		} `json:"error"`
+	}
+	client.GET("/tasks/").Query("status", "archived").Query("assignee_id", "nobody").Query("created_within", "-1h").Expect(http.StatusBadRequest).JSON(&invalid)
+	assert.Equal(t, "invalid_argument", invalid.Error.Code)
+	assert.Equal(t, map[string]string{
+		"status":         "must be one of pending, completed, failed",
+		"assignee_id":    "must be a UUID",
+		"created_within": "must be a positive duration such as 24h or 7d",
+	}, invalid.Error.Details)
+}
+
+func TestTaskAPI_Stream(t *testing.T) {
+	// Arrange
+	clock := mocks.NewFakeClock(now)
+	app := NewAppBuilder().WithClock(clock).Build(t)
+	client := app.Client(t)
+	ana, luis := uuid.New(), uuid.New()
+	for i, title := range []string{"Revisar el backlog", "Preparar la demo", "Revisar la caché"} {
+		assignee := ana
+		if i == 1 {
+			assignee = luis
+		}
+		createTask(client, title, assignee)
+		clock.Advance(time.Minute)
+	}
+
+	// Act
+	resp := client.GET("/tasks/stream").Query("assignee_id", ana.String()).Expect(http.StatusOK)
+
+	// Assert: una tarea por línea, de la más antigua a la más reciente.
+	assert.Equal(t, "application/x-ndjson", resp.Header("Content-Type"))
+	var tasks []taskResponse
+	dec := json.NewDecoder(strings.NewReader(resp.Body()))
+	for dec.More() {
+		var task taskResponse
+		require.NoError(t, dec.Decode(&task))
+		tasks = append(tasks, task)
+	}
+	assert.Equal(t, []string{"Revisar el backlog", "Revisar la caché"}, taskTitles(tasks))
+	assert.Equal(t, 2, strings.Count(resp.Body(), "\n"))
+
+	// Sin resultados la respuesta está vacía, y los filtros inválidos son un 400 como en GET /tasks.
+	resp = client.GET("/tasks/stream").Query("title", "inexistente").Expect(http.StatusOK)
+	assert.Empty(t, resp.Body())
+	client.GET("/tasks/stream").Query("status", "archived").Expect(http.StatusBadRequest)
+}
+
+func TestTaskAPI_ConditionalGet(t *testing.T) {
+	// Arrange
+	clock := mocks.NewFakeClock(now)
+	app := NewAppBuilder().WithClock(clock).Build(t)
+	client := app.Client(t)
+	path := "/tasks/" + createTask(client, "Revisar el backlog", uuid.New()).ID.String()
+	etag := client.GET(path).Expect(http.StatusOK).Header("ETag")
+	require.NotEmpty(t, etag)
+
+	// Act + Assert: sin cambios, 304 sin cuerpo.
+	resp := client.GET(path).Header("If-None-Match", etag).Expect(http.StatusNotModified)
+	assert.Empty(t, resp.Body())
+	assert.Equal(t, etag, resp.Header("ETag"))
+
+	// Tras una escritura la versión cambia y vuelve el cuerpo.
+	clock.Advance(time.Minute)
+	client.PUT(path, map[string]string{"title": "Revisar el backlog del sprint"}).Expect(http.StatusOK)
+	var got taskResponse
+	resp = client.GET(path).Header("If-None-Match", etag).Expect(http.StatusOK).JSON(&got)
+	assert.Equal(t, "Revisar el backlog del sprint", got.Title)
+	assert.NotEqual(t, etag, resp.Header("ETag"))
+}
+
+func TestTaskAPI_ConditionalUpdate(t *testing.T) {
+	// Arrange: dos clientes leen la misma versión de la tarea.
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	path := "/tasks/" + createTask(client, "Revisar el backlog", uuid.New()).ID.String()
+	etag := client.GET(path).Expect(http.StatusOK).Header("ETag")
+	assert.Equal(t, `"1"`, etag)
+
+	// Act: el primero escribe sobre esa versión; el segundo, que no ha visto su cambio, ya no.
+	var updated taskResponse
+	resp := client.PUT(path, map[string]string{"title": "Revisar el backlog del sprint"}).Header("If-Match", etag).
+		Expect(http.StatusOK).JSON(&updated)
+	var stale struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	client.PUT(path, map[string]string{"title": "Revisar la demo"}).Header("If-Match", etag).
+		Expect(http.StatusPreconditionFailed).JSON(&stale)
+
+	// Assert
+	assert.Equal(t, int64(2), updated.Version)
+	assert.Equal(t, `"2"`, resp.Header("ETag"))
+	assert.Equal(t, "aborted", stale.Error.Code)
+	var got taskResponse
+	client.GET(path).Expect(http.StatusOK).JSON(&got)
+	assert.Equal(t, "Revisar el backlog del sprint", got.Title)
+
+	// Con la versión nueva, con "*" o sin If-Match se escribe; un ETag que no es una versión, no.
+	client.PUT(path, map[string]string{"title": "Revisar la demo"}).Header("If-Match", resp.Header("ETag")).Expect(http.StatusOK)
+	client.PUT(path, map[string]string{"title": "Revisar la demo"}).Header("If-Match", "*").Expect(http.StatusOK)
+	client.PUT(path, map[string]string{"title": "Revisar la demo"}).Expect(http.StatusOK)
+	client.PUT(path, map[string]string{"title": "Revisar la demo"}).Header("If-Match", `W/"5"`).Expect(http.StatusPreconditionFailed)
+	assert.Equal(t, `"5"`, client.GET(path).Expect(http.StatusOK).Header("ETag"))
+}
+
+func TestTaskAPI_ConditionalUpdateIgnoresStaleCache(t *testing.T) {
+	// Arrange: otra instancia cambia la descripción y la caché de esta se queda en la versión 1.
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	created := createTask(client, "Revisar el backlog", uuid.New())
+	ctx := context.Background()
+	stored, err := app.Tasks.Tasks.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	stored.Description = "Cambiada en otra instancia"
+	stored.Version++
+	require.NoError(t, app.Tasks.Tasks.Update(ctx, stored, sharedDomain.OutboxEvent{
+		ID: uuid.New(), AggregateType: "task", AggregateID: stored.ID.String(), EventType: taskDomain.TaskUpdated, Payload: stored, CreatedAt: time.Now(),
+	}))
+
+	// Act: el cliente, que ya ha visto la versión 2, cambia solo el título.
+	client.PUT("/tasks/"+created.ID.String(), map[string]string{"title": "Revisar el backlog del sprint"}).
+		Header("If-Match", `"2"`).Expect(http.StatusOK)
+
+	// Assert: la descripción de la versión 2 no se pisa con la de la caché.
+	got, err := app.Tasks.Tasks.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Revisar el backlog del sprint", got.Title)
+	assert.Equal(t, "Cambiada en otra instancia", got.Description)
+	assert.Equal(t, int64(3), got.Version)
+}
+
+func TestTaskAPI_GetByIDs(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	first := createTask(client, "Revisar el backlog", uuid.New())
+	second := createTask(client, "Preparar la demo", uuid.New())
+
+	// Act
+	var got []taskResponse
+	client.GET("/tasks/?ids=" + second.ID.String() + "," + uuid.NewString() + "," + first.ID.String()).Expect(http.StatusOK).JSON(&got)
+
+	// Assert: las que existen, en el orden pedido.
+	require.Len(t, got, 2)
+	assert.Equal(t, second.ID, got[0].ID)
+	assert.Equal(t, first.ID, got[1].ID)
+
+	client.GET("/tasks/?ids=" + first.ID.String() + ",not-a-uuid").Expect(http.StatusBadRequest)
+}
+
+func TestTaskAPI_BulkStatus(t *testing.T) {
+	// Arrange: 'done' ya está completada, así que no puede volver a completarse.
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	done := createTask(client, "Revisar el backlog", uuid.New())
+	pending := createTask(client, "Preparar la demo", uuid.New())
+	missing := uuid.New()
+	client.POST("/tasks/bulk-status", map[string]any{"ids": []uuid.UUID{done.ID}, "status": "completed"}).Expect(http.StatusOK)
+
+	// Act
+	var resp struct {
+		Results []struct {
+			ID    uuid.UUID     `json:"id"`
+			Task  *taskResponse `json:"task"`
+			Error *struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		} `json:"results"`
+		Updated int `json:"updated"`
+	}
+	client.POST("/tasks/bulk-status", map[string]any{"ids": []uuid.UUID{done.ID, pending.ID, missing, pending.ID}, "status": "completed"}).
+		Expect(http.StatusOK).JSON(&resp)
+
+	// Assert: un resultado por id, en el orden pedido y sin repetidos.
+	assert.Equal(t, 1, resp.Updated)
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, done.ID, resp.Results[0].ID)
+	require.NotNil(t, resp.Results[0].Error)
+	assert.Equal(t, "failed_precondition", resp.Results[0].Error.Code)
+	assert.Equal(t, pending.ID, resp.Results[1].ID)
+	require.NotNil(t, resp.Results[1].Task)
+	assert.Equal(t, taskDomain.TaskCompleted, resp.Results[1].Task.Status)
+	assert.Equal(t, int64(2), resp.Results[1].Task.Version)
+	assert.Equal(t, missing, resp.Results[2].ID)
+	require.NotNil(t, resp.Results[2].Error)
+	assert.Equal(t, "not_found", resp.Results[2].Error.Code)
+
+	var got taskResponse
+	client.GET("/tasks/" + pending.ID.String()).Expect(http.StatusOK).JSON(&got)
+	assert.Equal(t, taskDomain.TaskCompleted, got.Status)
+
+	client.POST("/tasks/bulk-status", map[string]any{"ids": []uuid.UUID{pending.ID}, "status": "archived"}).Expect(http.StatusBadRequest)
+}
+
+func TestTaskAPI_EmitsEvents(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	received := app.TaskBus.Subscribe(10)
+
+	// Act
+	created := createTask(client, "Revisar el backlog", uuid.New())
+	app.Relay()
+
+	// Assert: el relayer publica la tarea y vacía el outbox
+	select {
+	case msg := <-received:
+		var envelope sharedEvents.IntegrationEvent
+		require.NoError(t, json.Unmarshal(msg.([]byte), &envelope))
+		assert.Equal(t, taskDomain.TaskCreated, envelope.Type)
+
+		var published taskDomain.Task
+		require.NoError(t, json.Unmarshal(envelope.Data, &published))
+		assert.Equal(t, created.ID, published.ID)
+		assert.Equal(t, "Revisar el backlog", published.Title)
+	case <-time.After(time.Second):
+		t.Fatal("the task.created event was not published")
+	}
+	assert.Empty(t, app.PendingEvents(t))
+}
+
+func TestTaskAPI_LongPollUpdates(t *testing.T) {
+	// Arrange: un servidor real, para seguir publicando mientras la petición espera
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	assignee := uuid.New()
+	task := createTask(client, "Revisar el backlog", assignee)
+	app.Relay()
+	server := httptest.NewServer(app.Router)
+	t.Cleanup(server.Close)
+
+	type result struct {
+		status  int
+		updates taskHttp.TaskUpdates
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var res result
+		resp, err := http.Get(server.URL + "/tasks/updates?wait=5s&assignee_id=" + assignee.String())
+		if err == nil {
+			defer resp.Body.Close()
+			res.status = resp.StatusCode
+			err = json.NewDecoder(resp.Body).Decode(&res.updates)
+		}
+		res.err = err
+		done <- res
+	}()
+
+	// Act: hasta que responde, se publican una tarea de otro usuario y una actualización de la del
+	// usuario; la suscripción puede registrarse después de las primeras
+	var res result
+	deadline := time.After(3 * time.Second)
+	for waiting := true; waiting; {
+		createTask(client, "Preparar la demo", uuid.New())
+		client.PUT("/tasks/"+task.ID.String(), map[string]string{"title": "Revisar el backlog de nuevo"}).Expect(http.StatusOK)
+		app.Relay()
+		select {
+		case res = <-done:
+			waiting = false
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("the long-poll request did not return")
+		}
+	}
+
+	// Assert: solo los eventos de la tarea del usuario
+	require.NoError(t, res.err)
+	require.Equal(t, http.StatusOK, res.status)
+	require.NotEmpty(t, res.updates.Events)
+	for _, evt := range res.updates.Events {
+		var data sharedEvents.TaskUpdated
+		require.NoError(t, json.Unmarshal(evt.Data, &data))
+		assert.Equal(t, taskDomain.TaskUpdated, evt.Type)
+		assert.Equal(t, task.ID, data.ID)
+	}
+
+	// Sin eventos, 204 al agotar la espera; los parámetros no válidos, 400
+	client.GET("/tasks/updates").Query("assignee_id", assignee.String()).Query("wait", "10ms").Expect(http.StatusNoContent)
+	client.GET("/tasks/updates").Query("assignee_id", "nope").Expect(http.StatusBadRequest)
+	client.GET("/tasks/updates").Query("assignee_id", assignee.String()).Query("wait", "-1s").Expect(http.StatusBadRequest)
+}
+
+func TestTaskAPI_TaskSummary(t *testing.T) {
+	// Arrange: Ana tiene una tarea completada, una pendiente y una borrada.
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	ana := uuid.New()
+	done := createTask(client, "Revisar el backlog", ana)
+	createTask(client, "Preparar la demo", ana)
+	deleted := createTask(client, "Documentar la API", ana)
+	client.POST("/tasks/bulk-status", map[string]any{"ids": []uuid.UUID{done.ID}, "status": "completed"}).Expect(http.StatusOK)
+	client.DELETE("/tasks/" + deleted.ID.String()).Expect(http.StatusNoContent)
+
+	// Act: los contadores se actualizan al consumir los eventos publicados.
+	app.Relay()
+
+	// Assert
+	want := taskDomain.TaskSummary{UserID: ana, TaskCounts: taskDomain.TaskCounts{Pending: 1, Completed: 1}}
+	require.Eventually(t, func() bool {
+		var got taskDomain.TaskSummary
+		client.GET("/users/" + ana.String() + "/task-summary").Expect(http.StatusOK).JSON(&got)
+		return got == want
+	}, 2*time.Second, 10*time.Millisecond)
+
+	var empty taskDomain.TaskSummary
+	other := uuid.New()
+	client.GET("/users/" + other.String() + "/task-summary").Expect(http.StatusOK).JSON(&empty)
+	assert.Equal(t, taskDomain.TaskSummary{UserID: other}, empty)
+	client.GET("/users/not-a-uuid/task-summary").Expect(http.StatusBadRequest)
+}
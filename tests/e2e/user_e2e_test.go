@@ -1 +1,394 @@
 package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	"github.com/davicafu/hexagolab/pkg/dataio"
+	"github.com/davicafu/hexagolab/tests/mocks"
+)
+
+// now es la hora del reloj falso de las apps de estos tests.
+var now = time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+type createUserRequest struct {
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	BirthDate string `json:"birth_date"`
+}
+
+func createUser(client *Client, email, name, birthDate string) userDomain.User {
+	var user userDomain.User
+	client.POST("/users/", createUserRequest{Email: email, Name: name, BirthDate: birthDate}).
+		Expect(http.StatusCreated).JSON(&user)
+	return user
+}
+
+func userNames(users []userDomain.User) []string {
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	return names
+}
+
+func TestUserAPI_CRUD(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().WithClock(mocks.NewFakeClock(now)).WithIDs(mocks.NewSequentialIDGenerator()).Build(t)
+	client := app.Client(t)
+
+	// Act + Assert: alta
+	created := createUser(client, "ana@example.com", "Ana García", "1990-05-17")
+	assert.Equal(t, mocks.SequentialID(1), created.ID)
+	assert.True(t, now.Equal(created.CreatedAt))
+	path := "/users/" + created.ID.String()
+
+	// Lectura
+	var got userDomain.User
+	client.GET(path).Expect(http.StatusOK).Data(&got)
+	assert.Equal(t, "ana@example.com", got.Email)
+	assert.Equal(t, "Ana García", got.Name)
+	assert.Equal(t, "1990-05-17", got.BirthDate.Format("2006-01-02"))
+
+	// Actualización parcial: solo cambia lo que se envía
+	var updated userDomain.User
+	client.PUT(path, map[string]string{"name": "Ana García López"}).Expect(http.StatusOK).Data(&updated)
+	assert.Equal(t, "Ana García López", updated.Name)
+	assert.Equal(t, "ana@example.com", updated.Email)
+	client.GET(path).Expect(http.StatusOK).Data(&got)
+	assert.Equal(t, "Ana García López", got.Name)
+
+	// Lectura condicional: sin cambios desde la última, 304 sin cuerpo
+	etag := client.GET(path).Expect(http.StatusOK).Header("ETag")
+	assert.Empty(t, client.GET(path).Header("If-None-Match", etag).Expect(http.StatusNotModified).Body())
+
+	// Borrado
+	client.DELETE(path).Expect(http.StatusNoContent)
+	client.GET(path).Expect(http.StatusNotFound)
+
+	assert.Equal(t, []string{userDomain.UserCreated, userDomain.UserUpdated, userDomain.UserDeleted}, app.PendingEvents(t))
+}
+
+func TestUserAPI_ConditionalUpdate(t *testing.T) {
+	// Arrange: dos clientes leen la misma versión del usuario.
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	path := "/users/" + createUser(client, "ana@example.com", "Ana García", "1990-05-17").ID.String()
+	etag := client.GET(path).Expect(http.StatusOK).Header("ETag")
+
+	// Act
+	var updated userDomain.User
+	resp := client.PUT(path, map[string]string{"name": "Ana García López"}).Header("If-Match", etag).
+		Expect(http.StatusOK).Data(&updated)
+	client.PUT(path, map[string]string{"name": "Ana López"}).Header("If-Match", etag).Expect(http.StatusPreconditionFailed)
+
+	// Assert
+	assert.Equal(t, `"1"`, etag)
+	assert.Equal(t, int64(2), updated.Version)
+	assert.Equal(t, `"2"`, resp.Header("ETag"))
+	var got userDomain.User
+	client.GET(path).Expect(http.StatusOK).Data(&got)
+	assert.Equal(t, "Ana García López", got.Name)
+	assert.Equal(t, []string{userDomain.UserCreated, userDomain.UserUpdated}, app.PendingEvents(t), "El 412 no deja evento")
+}
+
+func TestUserAPI_ConditionalUpdateIgnoresStaleCache(t *testing.T) {
+	// Arrange: otra instancia cambia el nombre y la caché de esta se queda en la versión 1.
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	created := createUser(client, "ana@example.com", "Ana García", "1990-05-17")
+	ctx := context.Background()
+	stored, err := app.Users.Users.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	stored.Name = "Ana García López"
+	stored.Version++
+	require.NoError(t, app.Users.Users.Update(ctx, stored, sharedDomain.OutboxEvent{
+		ID: uuid.New(), AggregateType: "user", AggregateID: stored.ID.String(), EventType: userDomain.UserUpdated, Payload: userDomain.NewUserPayload(stored), CreatedAt: time.Now(),
+	}))
+
+	// Act: el cliente, que ya ha visto la versión 2, cambia solo el email.
+	client.PUT("/users/"+created.ID.String(), map[string]string{"email": "ana.garcia@example.com"}).
+		Header("If-Match", `"2"`).Expect(http.StatusOK)
+
+	// Assert: el nombre de la versión 2 no se pisa con el de la caché.
+	got, err := app.Users.Users.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ana.garcia@example.com", got.Email)
+	assert.Equal(t, "Ana García López", got.Name)
+	assert.Equal(t, int64(3), got.Version)
+}
+
+// TestUserAPI_LegacyFieldNames comprueba que los clientes que aún usan el alias nombre siguen
+// funcionando en el cuerpo, los filtros y la ordenación, y que las respuestas lo mantienen.
+func TestUserAPI_LegacyFieldNames(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().WithClock(mocks.NewFakeClock(now)).Build(t)
+	client := app.Client(t)
+	createUser(client, "luis@example.com", "Luis Pérez", "2010-01-01")
+
+	// Act
+	var created map[string]any
+	client.POST("/users/", map[string]string{"email": "ana@example.com", "nombre": "Ana García", "birth_date": "1990-05-17"}).
+		Expect(http.StatusCreated).JSON(&created)
+	var updated map[string]any
+	client.PUT("/users/"+created["id"].(string), map[string]string{"nombre": "Ana García López"}).
+		Expect(http.StatusOK).Data(&updated)
+	var filtered, sorted, sortedByField []userDomain.User
+	client.GET("/users/").Query("nombre", "garcía").Expect(http.StatusOK).Data(&filtered)
+	client.GET("/users/").Query("sort", "nombre").Expect(http.StatusOK).Data(&sorted)
+	client.GET("/users/").Query("sort_field", "nombre").Query("sort_desc", "true").Expect(http.StatusOK).Data(&sortedByField)
+
+	// Assert
+	assert.Equal(t, "Ana García", created["name"])
+	assert.Equal(t, "Ana García", created["nombre"])
+	assert.Equal(t, "Ana García López", updated["name"])
+	assert.Equal(t, "Ana García López", updated["nombre"])
+	assert.Equal(t, []string{"Ana García López"}, userNames(filtered))
+	assert.Equal(t, []string{"Ana García López", "Luis Pérez"}, userNames(sorted))
+	assert.Equal(t, []string{"Luis Pérez", "Ana García López"}, userNames(sortedByField))
+}
+
+func TestUserAPI_Errors(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	existing := createUser(client, "ana@example.com", "Ana García", "1990-05-17")
+	unknown := "/users/" + mocks.SequentialID(99).String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+	}{
+		{"malformed json", http.MethodPost, "/users/", `{"email":`, http.StatusBadRequest},
+		{"missing fields", http.MethodPost, "/users/", map[string]string{"email": "luis@example.com"}, http.StatusBadRequest},
+		{"invalid email", http.MethodPost, "/users/", createUserRequest{Email: "luis", Name: "Luis", BirthDate: "1990-01-01"}, http.StatusBadRequest},
+		{"invalid birth date", http.MethodPost, "/users/", createUserRequest{Email: "luis@example.com", Name: "Luis", BirthDate: "01/01/1990"}, http.StatusBadRequest},
+		{"get invalid id", http.MethodGet, "/users/not-a-uuid", nil, http.StatusBadRequest},
+		{"get unknown", http.MethodGet, unknown, nil, http.StatusNotFound},
+		{"update invalid id", http.MethodPut, "/users/not-a-uuid", map[string]string{}, http.StatusBadRequest},
+		{"update unknown", http.MethodPut, unknown, map[string]string{"name": "Nadie"}, http.StatusNotFound},
+		{"update invalid birth date", http.MethodPut, "/users/" + existing.ID.String(), map[string]string{"birth_date": "ayer"}, http.StatusBadRequest},
+		{"delete invalid id", http.MethodDelete, "/users/not-a-uuid", nil, http.StatusBadRequest},
+		{"delete unknown", http.MethodDelete, unknown, nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.Client(t).newRequest(tt.method, tt.path, tt.body).Expect(tt.status)
+		})
+	}
+
+	// Las peticiones rechazadas no dejan eventos.
+	assert.Equal(t, []string{userDomain.UserCreated}, app.PendingEvents(t))
+}
+
+func TestUserAPI_Filters(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().WithClock(mocks.NewFakeClock(now)).Build(t)
+	client := app.Client(t)
+	createUser(client, "ana@example.com", "Ana García", "1990-05-17")
+	createUser(client, "luis@example.com", "Luis Pérez", "2010-01-01")
+	createUser(client, "marta@example.com", "Marta García", "1960-08-30")
+
+	tests := []struct {
+		name  string
+		query map[string]string
+		want  []string
+	}{
+		{"name ignores case", map[string]string{"name": "garcía"}, []string{"Ana García", "Marta García"}},
+		{"email", map[string]string{"email": "luis@example.com"}, []string{"Luis Pérez"}},
+		{"min age", map[string]string{"min_age": "18"}, []string{"Ana García", "Marta García"}},
+		{"age range", map[string]string{"min_age": "18", "max_age": "40"}, []string{"Ana García"}},
+		{"no match", map[string]string{"name": "inexistente"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			req := app.Client(t).GET("/users/")
+			for key, value := range tt.query {
+				req.Query(key, value)
+			}
+			var users []userDomain.User
+			req.Expect(http.StatusOK).Data(&users)
+
+			// Assert
+			assert.ElementsMatch(t, tt.want, userNames(users))
+		})
+	}
+
+	// Un filtro inválido es un 400 con el motivo de cada parámetro, en lugar de ignorarse.
+	var body struct {
+		Error struct {
+			Details map[string]string `json:"details"`
+		} `json:"error"`
+	}
+	client.GET("/users/").Query("min_age", "dieciocho").Query("id", "not-a-uuid").Query("created_at", "2025-01-01").
+		Expect(http.StatusBadRequest).JSON(&body)
+	assert.Equal(t, map[string]string{
+		"min_age":    "must be an integer",
+		"id":         "must be a UUID",
+		"created_at": "operator = is not allowed",
+	}, body.Error.Details)
+}
+
+func TestUserAPI_GetByIDs(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	ana := createUser(client, "ana@example.com", "Ana García", "1990-05-17")
+	luis := createUser(client, "luis@example.com", "Luis Pérez", "2010-01-01")
+
+	// Act
+	var users []userDomain.User
+	client.GET("/users/").Query("ids", luis.ID.String()+","+uuid.NewString()+","+ana.ID.String()).
+		Expect(http.StatusOK).Data(&users)
+
+	// Assert: los que existen, en el orden pedido.
+	assert.Equal(t, []string{"Luis Pérez", "Ana García"}, userNames(users))
+
+	client.GET("/users/").Query("ids", "not-a-uuid").Expect(http.StatusBadRequest)
+}
+
+func TestUserAPI_Pagination(t *testing.T) {
+	// Arrange: cinco usuarios creados con un minuto de diferencia.
+	clock := mocks.NewFakeClock(now)
+	app := NewAppBuilder().WithClock(clock).Build(t)
+	client := app.Client(t)
+	var users []userDomain.User
+	for _, name := range []string{"Ana", "Luis", "Marta", "Pablo", "Sara"} {
+		users = append(users, createUser(client, name+"@example.com", name, "1990-01-01"))
+		clock.Advance(time.Minute)
+	}
+
+	// Act + Assert: por defecto, del más reciente al más antiguo
+	var page []userDomain.User
+	client.GET("/users/").Query("limit", "2").Expect(http.StatusOK).Data(&page)
+	assert.Equal(t, []string{"Sara", "Pablo"}, userNames(page))
+
+	var meta struct {
+		HasMore bool `json:"has_more"`
+		Total   int  `json:"total"`
+	}
+	client.GET("/users/").Query("limit", "2").Query("offset", "2").Expect(http.StatusOK).Data(&page).JSON(&meta)
+	assert.Equal(t, []string{"Marta", "Luis"}, userNames(page))
+	assert.True(t, meta.HasMore)
+	assert.Equal(t, len(users), meta.Total)
+
+	client.GET("/users/").Query("limit", "2").Query("offset", "4").Expect(http.StatusOK).Data(&page).JSON(&meta)
+	assert.Equal(t, []string{"Ana"}, userNames(page))
+	assert.False(t, meta.HasMore, "La última página no tiene siguiente")
+
+	client.GET("/users/").Query("limit", "2").Query("offset", "10").Expect(http.StatusOK).Data(&page)
+	assert.Empty(t, page)
+
+	// Cursor: un cursor vacío pide la primera página y cada respuesta trae el de la siguiente.
+	var walked []userDomain.User
+	cursor := ""
+	for range len(users) {
+		var body struct {
+			Data       []userDomain.User `json:"data"`
+			NextCursor string            `json:"next_cursor"`
+		}
+		client.GET("/users/").Query("limit", "2").Query("cursor", cursor).Expect(http.StatusOK).JSON(&body)
+		walked = append(walked, body.Data...)
+		if body.NextCursor == "" {
+			break
+		}
+		cursor = body.NextCursor
+	}
+	assert.Equal(t, []string{"Sara", "Pablo", "Marta", "Luis", "Ana"}, userNames(walked))
+
+	last := users[3]
+	legacy := last.CreatedAt.Format(time.RFC3339Nano) + "|" + last.ID.String()
+	client.GET("/users/").Query("limit", "2").Query("cursor", legacy).Expect(http.StatusBadRequest)
+	client.GET("/users/").Query("limit", "2").Query("cursor", "not-a-cursor").Expect(http.StatusBadRequest)
+	client.GET("/users/").Query("sort_field", "email; DROP TABLE users").Expect(http.StatusBadRequest)
+}
+
+func TestUserAPI_EmitsEvents(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	received := app.UserBus.Subscribe(10)
+
+	// Act
+	var created userDomain.User
+	client.POST("/users/", createUserRequest{Email: "ana@example.com", Name: "Ana García", BirthDate: "1990-05-17"}).
+		Header("X-Correlation-ID", "req-ana").
+		Header("X-Actor", "admin@example.com").
+		Header("X-Tenant-ID", "acme").
+		Expect(http.StatusCreated).JSON(&created)
+	client.POST("/users/", createUserRequest{Email: "eve@example.com", Name: "Eve", BirthDate: "1990-05-17"}).
+		Header("X-Tenant-ID", "acme.eu").
+		Expect(http.StatusBadRequest)
+	app.Relay()
+
+	// Assert: el relayer publica el usuario, con la cadena causal de la petición, y vacía el outbox
+	select {
+	case msg := <-received:
+		var envelope sharedEvents.IntegrationEvent
+		require.NoError(t, json.Unmarshal(msg.([]byte), &envelope))
+		assert.Equal(t, userDomain.UserCreated, envelope.Type)
+		assert.NotEqual(t, uuid.Nil, envelope.EventID)
+		assert.Equal(t, "req-ana", envelope.CorrelationID)
+		assert.Equal(t, "admin@example.com", envelope.Actor)
+		assert.Equal(t, "acme", envelope.TenantID)
+
+		var published userDomain.User
+		require.NoError(t, json.Unmarshal(envelope.Data, &published))
+		assert.Equal(t, created.ID, published.ID)
+		assert.Equal(t, "ana@example.com", published.Email)
+	case <-time.After(time.Second):
+		t.Fatal("the user.created event was not published")
+	}
+	assert.Empty(t, app.PendingEvents(t))
+}
+
+func TestUserAPI_ImportAndExport(t *testing.T) {
+	// Arrange
+	app := NewAppBuilder().Build(t)
+	client := app.Client(t)
+	file := "email,nombre,birth_date\n" +
+		"ana@example.com,Ana García,1990-05-17\n" +
+		"not-an-email,Luis,1985-01-01\n" +
+		"eve@example.com,Eve,17/05/1990\n" +
+		"bob@example.com,Bob,2001-09-30\n"
+
+	// Act
+	var report dataio.Report
+	client.POST("/users/import?format=csv", file).Expect(http.StatusOK).JSON(&report)
+	exported := client.GET("/users/export").Query("format", "csv").Expect(http.StatusOK)
+
+	// Assert: las filas válidas se crean con su evento y las demás se informan con su línea.
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, []dataio.RowError{
+		{Line: 3, Reason: "invalid email"},
+		{Line: 4, Reason: "invalid birth_date format, use YYYY-MM-DD"},
+	}, report.Errors)
+	assert.Equal(t, []string{userDomain.UserCreated, userDomain.UserCreated}, app.PendingEvents(t))
+
+	assert.Equal(t, "text/csv", exported.Header("Content-Type"))
+	assert.Equal(t, `attachment; filename="users.csv"`, exported.Header("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(exported.Body()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "id,email,name,birth_date,created_at", lines[0])
+	assert.Contains(t, exported.Body(), ",ana@example.com,Ana García,1990-05-17,")
+	assert.Contains(t, exported.Body(), ",bob@example.com,Bob,2001-09-30,")
+
+	// Un formato desconocido es un 400.
+	client.GET("/users/export").Query("format", "xml").Expect(http.StatusBadRequest)
+}
@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedPostgres "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/postgres"
+	infraTask "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/postgre"
+)
+
+// insertOutboxRow guarda directamente un evento en el outbox de Postgres.
+func insertOutboxRow(t *testing.T, db *sql.DB, createdAt time.Time, processed bool) uuid.UUID {
+	t.Helper()
+	id := uuid.New()
+	_, err := db.Exec(`INSERT INTO outbox (id, aggregate_type, aggregate_id, event_type, payload, created_at, processed)
+		VALUES ($1, 'Task', $2, 'TaskCreated', '{}', $3, $4)`, id, uuid.NewString(), createdAt, processed)
+	require.NoError(t, err)
+	return id
+}
+
+func TestOutboxPartitionerPostgresIntegration(t *testing.T) {
+	// Arrange: un outbox sin particionar con un evento antiguo ya publicado.
+	db := setupPostgresTestDB(t)
+	defer db.Close()
+	_, err := db.Exec(`DROP TABLE IF EXISTS outbox, outbox_archive_legacy CASCADE`)
+	require.NoError(t, err)
+	require.NoError(t, infraTask.InitPostgresTaskSchema(db))
+	t.Cleanup(func() {
+		// Los demás tests crean de nuevo un outbox sin particionar.
+		_, _ = db.Exec(`DROP TABLE IF EXISTS outbox, outbox_archive_legacy CASCADE`)
+	})
+	ctx := context.Background()
+	now := time.Now().UTC()
+	insertOutboxRow(t, db, now.AddDate(0, -1, 0), true)
+	partitioner := sharedPostgres.NewOutboxPartitioner(db, sharedPostgres.PartitionOptions{Ahead: 2, Retention: 48 * time.Hour})
+
+	// Act: la primera pasada convierte la tabla; la de dentro de tres días archiva lo antiguo.
+	first, err := partitioner.Maintain(ctx, now)
+	require.NoError(t, err)
+	pending := insertOutboxRow(t, db, now, false)
+	later, err := partitioner.Maintain(ctx, now.AddDate(0, 0, 3))
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, first.Converted)
+	assert.Len(t, first.Created, 3)
+	assert.Empty(t, first.Archived)
+	assert.False(t, later.Converted)
+	assert.Equal(t, []string{"outbox_archive_legacy"}, later.Archived, "La partición de hoy tiene un evento pendiente")
+	var archivedRows int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM outbox_archive_legacy`).Scan(&archivedRows))
+	assert.Equal(t, 1, archivedRows)
+	events, err := sharedPostgres.NewOutboxRepoPostgres(db).FetchPendingOutbox(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, pending, events[0].ID)
+}
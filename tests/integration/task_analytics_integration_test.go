@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedEvents "github.com/davicafu/hexagolab/internal/shared/domain/events"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/compression"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/payload"
+	taskApp "github.com/davicafu/hexagolab/internal/task/application"
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	analyticsSQL "github.com/davicafu/hexagolab/internal/task/infra/outbound/analytics/postgres"
+	infraTask "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/postgre"
+
+	"github.com/davicafu/hexagolab/tests/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// taskOutboxEvent construye el evento igual que lo hace TaskService (payload = entidad completa).
+func taskOutboxEvent(t *taskDomain.Task, eventType string) sharedDomain.OutboxEvent {
+	return sharedDomain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: "task",
+		AggregateID:   t.ID.String(),
+		EventType:     eventType,
+		Payload:       t,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+func TestTaskAnalyticsPostgresIntegration(t *testing.T) {
+	// Los payloads grandes se guardan comprimidos (outbox.compression); el resultado no cambia.
+	for _, encoding := range []string{compression.None, compression.Gzip} {
+		t.Run(encoding, func(t *testing.T) {
+			db := setupPostgresTestDB(t)
+			defer db.Close()
+
+			repo := infraTask.NewTaskRepoPostgres(db)
+			analytics := analyticsSQL.NewTaskAnalyticsRepoPostgres(db)
+			ctx := context.Background()
+			event := func(task *taskDomain.Task, eventType string) sharedDomain.OutboxEvent {
+				evt, err := compression.Options{Encoding: encoding, Threshold: 1}.CompressOutbox(ctx, taskOutboxEvent(task, eventType))
+				require.NoError(t, err)
+				return evt
+			}
+
+			// --- Escenario: dos tareas creadas, una de ellas completada ---
+			createdAt := time.Now().UTC().Add(-2 * time.Hour)
+			doneAssignee, openAssignee := uuid.New(), uuid.New()
+			done := &taskDomain.Task{ID: uuid.New(), Title: "Completada", AssigneeID: &doneAssignee, Status: taskDomain.TaskPending, CreatedAt: createdAt, UpdatedAt: createdAt}
+			open := &taskDomain.Task{ID: uuid.New(), Title: "Abierta", AssigneeID: &openAssignee, Status: taskDomain.TaskPending, CreatedAt: createdAt, UpdatedAt: createdAt}
+			require.NoError(t, repo.Create(ctx, done, event(done, taskDomain.TaskCreated)))
+			require.NoError(t, repo.Create(ctx, open, event(open, taskDomain.TaskCreated)))
+
+			done.Status = taskDomain.TaskCompleted
+			done.UpdatedAt = createdAt.Add(90 * time.Minute)
+			done.Version++
+			require.NoError(t, repo.Update(ctx, done, event(done, taskDomain.TaskUpdated)))
+
+			start, end := time.Now().UTC().Add(-24*time.Hour), time.Now().UTC().Add(time.Minute)
+
+			// --- Tendencia diaria ---
+			trend, err := analytics.GetDailyTrend(ctx, start, end)
+			require.NoError(t, err)
+			require.NotEmpty(t, trend)
+			var created, completed int
+			for _, day := range trend {
+				created += day.CreatedCount
+				completed += day.CompletedCount
+			}
+			assert.Equal(t, 2, created)
+			assert.Equal(t, 1, completed)
+
+			// --- Tiempo medio de finalización ---
+			avg, err := analytics.GetAverageCompletionTime(ctx, start, end)
+			require.NoError(t, err)
+			assert.InDelta(t, (90 * time.Minute).Seconds(), avg.Seconds(), 1)
+
+			// --- Histórico reconstruido desde la outbox ---
+			var entries []taskDomain.TaskLogEntry
+			err = analytics.StreamTaskLog(ctx, start, end, func(e taskDomain.TaskLogEntry) error {
+				entries = append(entries, e)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Len(t, entries, 3)
+			assert.Equal(t, taskDomain.TaskCompleted, entries[2].Status)
+		})
+	}
+}
+
+func TestTaskAnalyticsPostgresIntegration_PayloadModes(t *testing.T) {
+	// Con payloads parciales el histórico se completa con el evento anterior o la fila actual.
+	for _, mode := range []sharedEvents.PayloadMode{sharedEvents.PayloadSnapshot, sharedEvents.PayloadDelta, sharedEvents.PayloadID} {
+		t.Run(string(mode), func(t *testing.T) {
+			db := setupPostgresTestDB(t)
+			defer db.Close()
+
+			service := taskApp.NewTaskService(infraTask.NewTaskRepoPostgres(db), mocks.NewDummyCache(), nil, sharedDomain.SystemClock{}, sharedDomain.RandomIDGenerator{}, zap.NewNop())
+			service.SetPayloadModes(payload.Modes{taskDomain.TaskUpdated: mode})
+			analytics := analyticsSQL.NewTaskAnalyticsRepoPostgres(db)
+			ctx := context.Background()
+			start := time.Now().UTC().Add(-time.Minute)
+
+			// --- Escenario: dos tareas creadas; una se completa y la otra se renombra ---
+			assignee := uuid.New()
+			done, err := service.CreateTask(ctx, "Completada", "desc", &assignee)
+			require.NoError(t, err)
+			renamed, err := service.CreateTask(ctx, "Abierta", "desc", &assignee)
+			require.NoError(t, err)
+			_, err = service.BulkTransitionStatus(ctx, []uuid.UUID{done.ID}, taskDomain.TaskCompleted)
+			require.NoError(t, err)
+			renamed.Title = "Renombrada"
+			require.NoError(t, service.UpdateTask(ctx, renamed))
+			end := time.Now().UTC().Add(time.Minute)
+
+			// --- Tendencia diaria ---
+			trend, err := analytics.GetDailyTrend(ctx, start, end)
+			require.NoError(t, err)
+			var created, completed int
+			for _, day := range trend {
+				created += day.CreatedCount
+				completed += day.CompletedCount
+			}
+			assert.Equal(t, 2, created)
+			assert.Equal(t, 1, completed)
+
+			// --- Histórico: los eventos parciales salen con la tarea completa ---
+			last := make(map[uuid.UUID]taskDomain.TaskLogEntry)
+			err = analytics.StreamTaskLog(ctx, start, end, func(e taskDomain.TaskLogEntry) error {
+				last[e.ID] = e
+				return nil
+			})
+			require.NoError(t, err)
+			require.Len(t, last, 2)
+			assert.Equal(t, taskDomain.TaskCompleted, last[done.ID].Status)
+			assert.Equal(t, "Completada", last[done.ID].Title)
+			assert.Equal(t, &assignee, last[done.ID].AssigneeID)
+			assert.Equal(t, "Renombrada", last[renamed.ID].Title)
+			assert.Equal(t, taskDomain.TaskPending, last[renamed.ID].Status)
+		})
+	}
+}
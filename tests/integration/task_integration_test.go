@@ -10,9 +10,12 @@ import (
 	// --- Importaciones del dominio Task ---
 	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
 	infraTask "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/postgre"
+	taskSQLite "github.com/davicafu/hexagolab/internal/task/infra/outbound/db/sqlite"
 
 	// --- Importaciones compartidas ---
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedPostgres "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/postgres"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/db/preflight"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
 
 	"github.com/google/uuid"
@@ -44,7 +47,8 @@ func setupPostgresTestDB(t *testing.T) *sql.DB {
 			assignee_id UUID,
 			status TEXT NOT NULL,
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			version BIGINT NOT NULL DEFAULT 1
 		)
 	`)
 	require.NoError(t, err)
@@ -62,6 +66,7 @@ func setupPostgresTestDB(t *testing.T) *sql.DB {
 		)
 	`)
 	require.NoError(t, err)
+	require.NoError(t, sharedPostgres.MigrateOutboxSchema(db))
 
 	// ❗ MUY IMPORTANTE: Limpiar las tablas antes de cada test para asegurar el aislamiento
 	_, err = db.Exec(`TRUNCATE TABLE tasks, outbox RESTART IDENTITY`)
@@ -98,7 +103,7 @@ func TestTaskPostgresIntegration_CRUD(t *testing.T) {
 		ID:          uuid.New(),
 		Title:       "Tarea de integración en Postgres",
 		Description: "Descripción inicial",
-		AssigneeID:  assigneeID,
+		AssigneeID:  &assigneeID,
 		Status:      taskDomain.TaskPending,
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
@@ -126,6 +131,7 @@ func TestTaskPostgresIntegration_CRUD(t *testing.T) {
 	// --- 3. Actualizar Tarea y su evento ---
 	task.Complete()
 	task.Title = "Tarea completada en Postgres"
+	task.Version++
 	updatedEvent := sharedDomain.OutboxEvent{
 		ID:            uuid.New(),
 		AggregateType: "Task",
@@ -148,7 +154,7 @@ func TestTaskPostgresIntegration_CRUD(t *testing.T) {
 		ctx,
 		sharedDomain.CompositeCriteria{},
 		sharedQuery.OffsetPagination{Limit: 10},
-		sharedQuery.Sort{Field: "created_at"},
+		[]sharedQuery.Sort{{Field: "created_at"}},
 	)
 	require.NoError(t, err)
 	assert.Len(t, tasks, 1)
@@ -169,3 +175,114 @@ func TestTaskPostgresIntegration_CRUD(t *testing.T) {
 	assert.ErrorIs(t, err, taskDomain.ErrTaskNotFound)
 	verifyOutboxEventPostgres(t, db, task.ID.String(), "TaskDeleted", 3)
 }
+
+// TestTaskPreflight_RejectsPostgresRepoOnSQLite reproduce un cableado erróneo: el repositorio
+// de Postgres sobre una conexión SQLite con el esquema de tareas ya creado.
+func TestTaskPreflight_RejectsPostgresRepoOnSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	require.NoError(t, taskSQLite.InitSQLiteTaskSchema(db))
+
+	err = preflight.Run(context.Background(), "task", infraTask.NewTaskRepoPostgres(db))
+	assert.ErrorIs(t, err, preflight.ErrDialectMismatch)
+	assert.ErrorContains(t, err, "*postgres.TaskRepoPostgres")
+
+	assert.NoError(t, preflight.Run(context.Background(), "task", taskSQLite.NewTaskRepoSQLite(db)))
+}
+
+func TestTaskSQLiteIntegration_CRUD(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // Cada conexión a :memory: es una base de datos distinta
+	require.NoError(t, taskSQLite.InitSQLiteTaskSchema(db))
+
+	repo := taskSQLite.NewTaskRepoSQLite(db)
+	ctx := context.Background()
+	assigneeID := uuid.New()
+
+	// --- 1. Crear Tarea y su evento ---
+	task := &taskDomain.Task{
+		ID:          uuid.New(),
+		Title:       "Tarea de integración en SQLite",
+		Description: "Descripción inicial",
+		AssigneeID:  &assigneeID,
+		Status:      taskDomain.TaskPending,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	createdEvent := sharedDomain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: "Task",
+		AggregateID:   task.ID.String(),
+		EventType:     "TaskCreated",
+		Payload:       map[string]interface{}{"title": task.Title, "assigneeId": task.AssigneeID.String()},
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	err = repo.Create(ctx, task, createdEvent)
+	require.NoError(t, err)
+	verifyOutboxEvent(t, db, task.ID.String(), "TaskCreated", 1)
+
+	// --- 2. Obtener Tarea (Read) ---
+	got, err := repo.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.Title, got.Title)
+	assert.Equal(t, &assigneeID, got.AssigneeID)
+	assert.True(t, task.CreatedAt.Equal(got.CreatedAt))
+
+	// --- 3. Actualizar Tarea y su evento ---
+	task.Complete()
+	task.Version++
+	time.Sleep(2 * time.Millisecond)
+	updatedEvent := sharedDomain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: "Task",
+		AggregateID:   task.ID.String(),
+		EventType:     "TaskUpdated",
+		Payload:       map[string]interface{}{"status": string(task.Status)},
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	err = repo.Update(ctx, task, updatedEvent)
+	require.NoError(t, err)
+	got, err = repo.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, taskDomain.TaskCompleted, got.Status)
+	verifyOutboxEvent(t, db, task.ID.String(), "TaskUpdated", 2)
+
+	// --- 4. Listar Tareas por Criterio ---
+	since := task.CreatedAt.Add(-time.Minute)
+	tasks, err := repo.ListByCriteria(
+		ctx,
+		sharedDomain.CompositeCriteria{Criterias: []sharedDomain.Criteria{
+			taskDomain.StatusCriteria{Status: taskDomain.TaskCompleted},
+			taskDomain.AssigneeIDCriteria{ID: assigneeID},
+			taskDomain.TitleLikeCriteria{Title: "sqlite"},
+			taskDomain.CreatedAtRangeCriteria{Start: &since},
+		}},
+		sharedQuery.OffsetPagination{Limit: 10},
+		[]sharedQuery.Sort{{Field: "created_at"}},
+	)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+
+	// --- 5. Eliminar Tarea y su evento ---
+	time.Sleep(2 * time.Millisecond)
+	deletedEvent := sharedDomain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: "Task",
+		AggregateID:   task.ID.String(),
+		EventType:     "TaskDeleted",
+		Payload:       map[string]interface{}{"id": task.ID.String()},
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	err = repo.DeleteByID(ctx, task.ID, deletedEvent)
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, task.ID)
+	assert.ErrorIs(t, err, taskDomain.ErrTaskNotFound)
+	verifyOutboxEvent(t, db, task.ID.String(), "TaskDeleted", 3)
+}
@@ -3,11 +3,14 @@ package integration
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedSQLite "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/sqlite"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
+	"github.com/davicafu/hexagolab/internal/shared/infra/platform/tracing"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
 	"github.com/davicafu/hexagolab/internal/user/infra/outbound/db/sqlite"
 	"github.com/google/uuid"
@@ -25,9 +28,11 @@ func setupTestDB(t *testing.T) *sql.DB {
 		CREATE TABLE users (
 			id TEXT PRIMARY KEY,
 			email TEXT NOT NULL,
-			nombre TEXT NOT NULL,
+			name TEXT NOT NULL,
 			birth_date TEXT NOT NULL,
-			created_at TEXT NOT NULL
+			created_at TEXT NOT NULL,
+			tenant_id TEXT NOT NULL DEFAULT '',
+			version INTEGER NOT NULL DEFAULT 1
 		)
 	`)
 	require.NoError(t, err)
@@ -45,6 +50,8 @@ func setupTestDB(t *testing.T) *sql.DB {
 		)
 	`)
 	require.NoError(t, err)
+	// La tabla se crea con el esquema antiguo para cubrir también la migración.
+	require.NoError(t, sharedSQLite.MigrateOutboxSchema(db))
 
 	return db
 }
@@ -74,7 +81,7 @@ func TestUserSQLiteIntegration_CreateGetUpdateDelete(t *testing.T) {
 	user := &userDomain.User{
 		ID:        uuid.New(),
 		Email:     "integration@example.com",
-		Nombre:    "Integrado",
+		Name:      "Integrado",
 		BirthDate: time.Date(1992, 6, 15, 0, 0, 0, 0, time.UTC),
 		CreatedAt: time.Now().UTC(),
 	}
@@ -84,7 +91,7 @@ func TestUserSQLiteIntegration_CreateGetUpdateDelete(t *testing.T) {
 		AggregateType: "User",
 		AggregateID:   user.ID.String(),
 		EventType:     "UserCreated",
-		Payload:       map[string]interface{}{"email": user.Email, "nombre": user.Nombre},
+		Payload:       map[string]interface{}{"email": user.Email, "name": user.Name},
 		CreatedAt:     time.Now().UTC(),
 	}
 
@@ -99,14 +106,15 @@ func TestUserSQLiteIntegration_CreateGetUpdateDelete(t *testing.T) {
 	assert.Equal(t, user.Email, got.Email)
 
 	// --- 3. Actualizar usuario y su evento ---
-	user.Nombre = "Actualizado"
+	user.Name = "Actualizado"
+	user.Version++
 	time.Sleep(2 * time.Millisecond)
 	updatedEvent := sharedDomain.OutboxEvent{
 		ID:            uuid.New(),
 		AggregateType: "User",
 		AggregateID:   user.ID.String(),
 		EventType:     "UserUpdated",
-		Payload:       map[string]interface{}{"nombre": user.Nombre},
+		Payload:       map[string]interface{}{"name": user.Name},
 		CreatedAt:     time.Now().UTC(),
 	}
 
@@ -115,12 +123,12 @@ func TestUserSQLiteIntegration_CreateGetUpdateDelete(t *testing.T) {
 	got, err = repo.GetByID(ctx, user.ID)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Actualizado", got.Nombre)
+	assert.Equal(t, "Actualizado", got.Name)
 	// Verificar que AHORA hay dos eventos y el último es "UserUpdated"
 	verifyOutboxEvent(t, db, user.ID.String(), "UserUpdated", 2)
 
 	// --- 4. Listar usuarios (sin cambios) ---
-	users, err := repo.ListByCriteria(ctx, sharedDomain.CompositeCriteria{}, sharedQuery.OffsetPagination{Limit: 10}, sharedQuery.Sort{Field: "created_at"})
+	users, err := repo.ListByCriteria(ctx, sharedDomain.CompositeCriteria{}, sharedQuery.OffsetPagination{Limit: 10}, []sharedQuery.Sort{{Field: "created_at"}})
 	assert.NoError(t, err)
 	assert.Len(t, users, 1)
 
@@ -142,3 +150,159 @@ func TestUserSQLiteIntegration_CreateGetUpdateDelete(t *testing.T) {
 	// Verificar que AHORA hay tres eventos y el último es "UserDeleted"
 	verifyOutboxEvent(t, db, user.ID.String(), "UserDeleted", 3)
 }
+
+func TestOutboxSQLiteIntegration_PropagatesTraceMetadata(t *testing.T) {
+	// Arrange: el contexto lleva la traza de la petición HTTP que origina el cambio.
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := sqlite.NewUserRepoSQLite(db)
+	outbox := sharedSQLite.NewOutboxRepoSQLite(db)
+	tc := tracing.New()
+	ctx := tracing.NewContext(context.Background(), tc)
+
+	user := &userDomain.User{
+		ID:        uuid.New(),
+		Email:     "traced@example.com",
+		Name:      "Trazado",
+		BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt: time.Now().UTC(),
+	}
+	evt := sharedDomain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: "User",
+		AggregateID:   user.ID.String(),
+		EventType:     "UserCreated",
+		Payload:       map[string]interface{}{"email": user.Email},
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	// Act
+	require.NoError(t, repo.Create(ctx, user, evt))
+	pending, err := outbox.FetchPendingOutbox(context.Background(), 10)
+
+	// Assert: el relayer recupera la traza aunque publique desde otro contexto.
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	got := tracing.FromMap(pending[0].Metadata)
+	assert.Equal(t, tc.CorrelationID, got.CorrelationID)
+	assert.Equal(t, tc.TraceID, got.TraceID)
+}
+
+func TestOutboxSQLiteIntegration_Stats(t *testing.T) {
+	// Arrange: dos altas y una baja pendientes; una de las altas acaba apartada.
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := sqlite.NewUserRepoSQLite(db)
+	outbox := sharedSQLite.NewOutboxRepoSQLite(db)
+	ctx := context.Background()
+	var ids []uuid.UUID
+	for i, eventType := range []string{"UserCreated", "UserCreated", "UserDeleted"} {
+		user := &userDomain.User{
+			ID:        uuid.New(),
+			Email:     fmt.Sprintf("stats%d@example.com", i),
+			Name:      "Stats",
+			BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			CreatedAt: time.Now().UTC(),
+		}
+		evt := sharedDomain.OutboxEvent{ID: uuid.New(), AggregateType: "User", AggregateID: user.ID.String(), EventType: eventType, Payload: map[string]any{}, CreatedAt: time.Now().UTC()}
+		require.NoError(t, repo.Create(ctx, user, evt))
+		ids = append(ids, evt.ID)
+	}
+	require.NoError(t, outbox.MarkOutboxDeadLetter(ctx, ids[0], "schema violation"))
+
+	// Act
+	stats, err := outbox.OutboxStats(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"UserCreated": 1, "UserDeleted": 1}, stats.Pending)
+	assert.Equal(t, map[string]int{"UserCreated": 1}, stats.DeadLetters)
+}
+
+func TestOutboxSQLiteIntegration_DeadLetters(t *testing.T) {
+	// Arrange: tres altas apartadas
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := sqlite.NewUserRepoSQLite(db)
+	outbox := sharedSQLite.NewOutboxRepoSQLite(db)
+	ctx := context.Background()
+	var ids []uuid.UUID
+	for i := 0; i < 3; i++ {
+		user := &userDomain.User{
+			ID:        uuid.New(),
+			Email:     fmt.Sprintf("dead%d@example.com", i),
+			Name:      "Dead",
+			BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			CreatedAt: time.Now().UTC(),
+		}
+		evt := sharedDomain.OutboxEvent{ID: uuid.New(), AggregateType: "User", AggregateID: user.ID.String(), EventType: "UserCreated", Payload: map[string]any{}, CreatedAt: time.Now().UTC().Add(time.Duration(i) * time.Second)}
+		require.NoError(t, repo.Create(ctx, user, evt))
+		require.NoError(t, outbox.MarkOutboxDeadLetter(ctx, evt.ID, "schema violation"))
+		ids = append(ids, evt.ID)
+	}
+
+	// Act
+	listed, err := outbox.ListDeadLetters(ctx, 2)
+	require.NoError(t, err)
+	requeued, err := outbox.RequeueDeadLetters(ctx, []uuid.UUID{ids[1]})
+	require.NoError(t, err)
+	purged, err := outbox.PurgeDeadLetters(ctx, nil)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, listed, 2)
+	assert.Equal(t, ids[0], listed[0].ID)
+	assert.Equal(t, "schema violation", listed[0].Reason)
+	assert.Equal(t, 1, requeued)
+	assert.Equal(t, 2, purged)
+	pending, err := outbox.FetchPendingOutbox(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, ids[1], pending[0].ID)
+	remaining, err := outbox.ListDeadLetters(ctx, -1)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestOutboxSQLiteIntegration_ReadChanges(t *testing.T) {
+	// Arrange: cuatro altas en el mismo segundo; una publicada y otra apartada
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := sqlite.NewUserRepoSQLite(db)
+	outbox := sharedSQLite.NewOutboxRepoSQLite(db)
+	ctx := context.Background()
+	base := time.Now().UTC().Truncate(time.Second)
+	var ids []uuid.UUID
+	for i := 0; i < 4; i++ {
+		user := &userDomain.User{
+			ID:        uuid.New(),
+			Email:     fmt.Sprintf("change%d@example.com", i),
+			Name:      "Change",
+			BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			CreatedAt: base,
+		}
+		evt := sharedDomain.OutboxEvent{ID: uuid.New(), AggregateType: "User", AggregateID: user.ID.String(), EventType: "UserCreated", Payload: map[string]any{}, CreatedAt: base.Add(time.Duration(i) * 100 * time.Millisecond)}
+		require.NoError(t, repo.Create(ctx, user, evt))
+		ids = append(ids, evt.ID)
+	}
+	require.NoError(t, outbox.MarkOutboxProcessed(ctx, ids[0]))
+	require.NoError(t, outbox.MarkOutboxDeadLetter(ctx, ids[2], "schema violation"))
+
+	// Act
+	all, err := outbox.ReadChanges(ctx, sharedDomain.OutboxPosition{}, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	after, err := outbox.ReadChanges(ctx, all[0].Position(), 1)
+	require.NoError(t, err)
+
+	// Assert: en orden, publicados o no, sin la dead letter
+	assert.Equal(t, []uuid.UUID{ids[0], ids[1], ids[3]}, []uuid.UUID{all[0].ID, all[1].ID, all[2].ID})
+	assert.True(t, all[0].Processed)
+	assert.False(t, all[1].Processed)
+	require.Len(t, after, 1)
+	assert.Equal(t, ids[1], after[0].ID)
+}
@@ -60,3 +60,39 @@ func (c *DummyCache) Delete(ctx context.Context, key string) error {
 	delete(c.store, key)
 	return nil
 }
+
+func (c *DummyCache) GetMulti(ctx context.Context, keys []string, dest func(key string) interface{}) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var found []string
+	for _, key := range keys {
+		data, ok := c.store[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(data, dest(key)); err != nil {
+			return nil, err
+		}
+		found = append(found, key)
+	}
+	return found, nil
+}
+
+func (c *DummyCache) SetMulti(ctx context.Context, items map[string]interface{}, ttlSecs int) error {
+	for key, val := range items {
+		if err := c.Set(ctx, key, val, ttlSecs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *DummyCache) DeleteMulti(ctx context.Context, keys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.store, key)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package mocks
+
+import sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+
+// Criteria es un Criteria con condiciones arbitrarias, para probar campos y operadores que los
+// criterios del dominio no generan.
+type Criteria []sharedDomain.Criterion
+
+func (c Criteria) ToConditions() []sharedDomain.Criterion { return c }
+
+// Verificación estática
+var _ sharedDomain.Criteria = Criteria(nil)
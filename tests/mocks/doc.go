@@ -0,0 +1,12 @@
+// Package mocks reúne los dobles de prueba de todos los puertos de la aplicación, importando
+// cada interfaz desde su paquete de origen (domain, platform o el adaptador de entrada que la
+// declara). Hay dos familias:
+//
+//   - Fakes con estado (InMemory*, Fake*, DummyCache): implementaciones en memoria que se
+//     comportan como el adaptador real; los repositorios pasan la suite de tests/conformance.
+//   - Mocks de testify (Mock*): para verificar interacciones concretas con On/AssertExpectations.
+//
+// Cada doble termina con su verificación estática contra la interfaz que implementa, así que
+// añadir un método a un puerto rompe la compilación de este paquete hasta que el doble lo
+// implementa.
+package mocks
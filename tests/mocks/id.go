@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/google/uuid"
+
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+)
+
+// SequentialIDGenerator genera los UUID 1, 2, 3... en orden, para que los tests sepan qué ID
+// recibirá cada agregado y cada evento sin tener que recuperarlo después.
+type SequentialIDGenerator struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+func NewSequentialIDGenerator() *SequentialIDGenerator {
+	return &SequentialIDGenerator{}
+}
+
+func (g *SequentialIDGenerator) NewID() uuid.UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.next++
+	return SequentialID(g.next)
+}
+
+// SequentialID devuelve el n-ésimo UUID de un SequentialIDGenerator, empezando en 1:
+// 00000000-0000-0000-0000-00000000000n.
+func SequentialID(n uint64) uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], n)
+	return id
+}
+
+// FixedIDGenerator devuelve los IDs indicados en orden y entra en pánico si se agotan, para que
+// un test falle si el código genera más IDs de los esperados.
+type FixedIDGenerator struct {
+	mu  sync.Mutex
+	ids []uuid.UUID
+}
+
+func NewFixedIDGenerator(ids ...uuid.UUID) *FixedIDGenerator {
+	return &FixedIDGenerator{ids: ids}
+}
+
+func (g *FixedIDGenerator) NewID() uuid.UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.ids) == 0 {
+		panic("mocks.FixedIDGenerator: no quedan IDs")
+	}
+	id := g.ids[0]
+	g.ids = g.ids[1:]
+	return id
+}
+
+// Verificación estática
+var _ sharedDomain.IDGenerator = (*SequentialIDGenerator)(nil)
+var _ sharedDomain.IDGenerator = (*FixedIDGenerator)(nil)
@@ -3,14 +3,14 @@ package mocks
 import (
 	"context"
 
-	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
-)
 
-// --- Mocks Correctos ---
+	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedBus "github.com/davicafu/hexagolab/internal/shared/infra/platform/bus"
+)
 
-// MockOutboxRepository solo implementa la interfaz que el Worker necesita.
+// MockOutboxRepository es un mock de testify del OutboxRepository que consume el worker.
 type MockOutboxRepository struct {
 	mock.Mock
 }
@@ -28,13 +28,24 @@ func (m *MockOutboxRepository) MarkOutboxProcessed(ctx context.Context, id uuid.
 	return args.Error(0)
 }
 
-// MockPublisher simula el publicador de eventos con la firma correcta.
+func (m *MockOutboxRepository) MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+// MockPublisher es un mock de testify del EventBus al que publica el worker.
 type MockPublisher struct {
 	mock.Mock
 }
 
-// ✅ Firma del método Publish corregida (sin 'topic').
 func (m *MockPublisher) Publish(ctx context.Context, event interface{}) error {
 	args := m.Called(ctx, event)
 	return args.Error(0)
 }
+
+// Verificación estática
+var (
+	_ sharedDomain.OutboxRepository   = (*MockOutboxRepository)(nil)
+	_ sharedDomain.OutboxDeadLetterer = (*MockOutboxRepository)(nil)
+	_ sharedBus.EventBus              = (*MockPublisher)(nil)
+)
@@ -3,22 +3,24 @@ package mocks
 import (
 	"context"
 	"fmt"
-	"sort"
-	"strings"
 	"sync"
-	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
 	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	taskConsumer "github.com/davicafu/hexagolab/internal/task/infra/inbound/events"
 	"github.com/google/uuid"
 )
 
-// InMemoryTaskRepo simula TaskRepository con outbox incluido.
+// InMemoryTaskRepo simula TaskRepository con outbox incluido. Como un repositorio real, guarda y
+// devuelve copias de las tareas: cambiar una leída no cambia la guardada.
 type InMemoryTaskRepo struct {
 	Tasks  map[uuid.UUID]*taskDomain.Task
 	Outbox []sharedDomain.OutboxEvent
-	mu     sync.Mutex
+	// DeadLetters son los eventos apartados con MarkOutboxDeadLetter.
+	DeadLetters []sharedDomain.OutboxEvent
+	mu          sync.Mutex
 }
 
 func NewInMemoryTaskRepo() *InMemoryTaskRepo {
@@ -36,7 +38,8 @@ func (r *InMemoryTaskRepo) Create(ctx context.Context, t *taskDomain.Task, evt s
 	if _, ok := r.Tasks[t.ID]; ok {
 		return taskDomain.ErrTaskAlreadyExists
 	}
-	r.Tasks[t.ID] = t
+	stored := *t
+	r.Tasks[t.ID] = &stored
 	r.Outbox = append(r.Outbox, evt)
 	return nil
 }
@@ -48,20 +51,59 @@ func (r *InMemoryTaskRepo) GetByID(ctx context.Context, id uuid.UUID) (*taskDoma
 	if !ok {
 		return nil, taskDomain.ErrTaskNotFound
 	}
-	return t, nil
+	found := *t
+	return &found, nil
+}
+
+func (r *InMemoryTaskRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*taskDomain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tasks []*taskDomain.Task
+	for _, id := range ids {
+		if t, ok := r.Tasks[id]; ok {
+			found := *t
+			tasks = append(tasks, &found)
+		}
+	}
+	return tasks, nil
 }
 
 func (r *InMemoryTaskRepo) Update(ctx context.Context, t *taskDomain.Task, evt sharedDomain.OutboxEvent) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.Tasks[t.ID]; !ok {
+	current, ok := r.Tasks[t.ID]
+	if !ok {
 		return taskDomain.ErrTaskNotFound
 	}
-	r.Tasks[t.ID] = t
+	if current.Version != t.Version-1 {
+		return taskDomain.ErrTaskVersionConflict
+	}
+	stored := *t
+	r.Tasks[t.ID] = &stored
 	r.Outbox = append(r.Outbox, evt)
 	return nil
 }
 
+func (r *InMemoryTaskRepo) UpdateStatus(ctx context.Context, tasks []*taskDomain.Task, events []sharedDomain.OutboxEvent) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var updated []uuid.UUID
+	for _, t := range tasks {
+		current, ok := r.Tasks[t.ID]
+		if !ok || current.Version != t.Version-1 {
+			continue
+		}
+		current.Status, current.UpdatedAt, current.Version = t.Status, t.UpdatedAt, t.Version
+		updated = append(updated, t.ID)
+		for _, evt := range events {
+			if evt.AggregateID == t.ID.String() {
+				r.Outbox = append(r.Outbox, evt)
+			}
+		}
+	}
+	return updated, nil
+}
+
 func (r *InMemoryTaskRepo) DeleteByID(ctx context.Context, id uuid.UUID, evt sharedDomain.OutboxEvent) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -73,100 +115,58 @@ func (r *InMemoryTaskRepo) DeleteByID(ctx context.Context, id uuid.UUID, evt sha
 	return nil
 }
 
+// ListByCriteria filtra, ordena y pagina igual que los repositorios reales (ver tests/conformance).
 func (r *InMemoryTaskRepo) ListByCriteria(
 	ctx context.Context,
 	criteria sharedDomain.Criteria,
 	pagination sharedQuery.Pagination,
-	sorts sharedQuery.Sort,
+	sorts []sharedQuery.Sort,
 ) ([]*taskDomain.Task, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	var list []*taskDomain.Task
+	list := make([]*taskDomain.Task, 0, len(r.Tasks))
 	for _, task := range r.Tasks {
-		if criteria == nil || matchTaskCriterion(task, criteria.ToConditions()) {
-			list = append(list, task)
-		}
+		found := *task
+		list = append(list, &found)
 	}
+	return sharedMemory.Query(list, taskField, criteria, pagination, sorts)
+}
 
-	// Ordenar
-	s := sorts
-	sort.SliceStable(list, func(i, j int) bool {
-		return compareTasks(list[i], list[j], s.Field, s.Desc)
-	})
+func (r *InMemoryTaskRepo) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	// Paginar
-	if p, ok := pagination.(sharedQuery.OffsetPagination); ok {
-		start := p.Offset
-		if start > len(list) {
-			return []*taskDomain.Task{}, nil
-		}
-		end := start + p.Limit
-		if end > len(list) {
-			end = len(list)
-		}
-		return list[start:end], nil
+	list := make([]*taskDomain.Task, 0, len(r.Tasks))
+	for _, item := range r.Tasks {
+		list = append(list, item)
 	}
-
-	return list, nil // Devuelve sin paginar si no es OffsetPagination
+	return sharedMemory.Count(list, taskField, criteria)
 }
 
-// --- Lógica de filtrado y ordenamiento del mock ---
-
-func matchTaskCriterion(t *taskDomain.Task, conds []sharedDomain.Criterion) bool {
-	for _, cond := range conds {
-		field := strings.ToLower(cond.Field)
-		op := strings.ToUpper(string(cond.Op))
-		val := cond.Value
-
-		var match bool
-		switch field {
-		case "status":
-			match = string(t.Status) == fmt.Sprintf("%v", val)
-		case "assignee_id":
-			assigneeID, ok := val.(uuid.UUID)
-			match = ok && t.AssigneeID == assigneeID
-		case "title":
-			title, ok := val.(string)
-			if ok && (op == "ILIKE" || op == "LIKE") {
-				pattern := strings.Trim(title, "%")
-				match = strings.Contains(strings.ToLower(t.Title), strings.ToLower(pattern))
-			}
-		case "created_at":
-			valTime, ok := val.(time.Time)
-			if ok {
-				if op == ">=" {
-					match = t.CreatedAt.After(valTime) || t.CreatedAt.Equal(valTime)
-				}
-				if op == "<=" {
-					match = t.CreatedAt.Before(valTime) || t.CreatedAt.Equal(valTime)
-				}
-			}
-		}
-
-		if !match {
-			return false // Si una condición no coincide, el registro no pasa el filtro
-		}
-	}
-	return true
+func (r *InMemoryTaskRepo) StreamByCriteria(ctx context.Context, criteria sharedDomain.Criteria, fn func(*taskDomain.Task) error) error {
+	return taskDomain.StreamByPages(ctx, r.ListByCriteria, criteria, fn)
 }
 
-func compareTasks(t1, t2 *taskDomain.Task, field string, desc bool) bool {
-	var result bool
-	switch strings.ToLower(field) {
+// taskField expone los campos de la tarea con los nombres de las columnas SQL.
+func taskField(t *taskDomain.Task, field string) (any, bool) {
+	switch field {
+	case "id":
+		return t.ID, true
 	case "title":
-		result = t1.Title < t2.Title
+		return t.Title, true
+	case "description":
+		return t.Description, true
+	case "assignee_id":
+		return t.AssigneeID, true
 	case "status":
-		result = t1.Status < t2.Status
+		return t.Status, true
 	case "created_at":
-		result = t1.CreatedAt.Before(t2.CreatedAt)
-	default: // Orden por defecto
-		result = t1.ID.String() < t2.ID.String()
+		return t.CreatedAt, true
+	case "updated_at":
+		return t.UpdatedAt, true
 	}
-	if desc {
-		return !result
-	}
-	return result
+	return nil, false
 }
 
 // --- Métodos de Outbox del mock ---
@@ -198,3 +198,82 @@ func (r *InMemoryTaskRepo) MarkOutboxProcessed(ctx context.Context, id uuid.UUID
 	}
 	return fmt.Errorf("outbox event not found: %s", id) // Error genérico
 }
+
+func (r *InMemoryTaskRepo) CountPendingOutbox(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.Outbox), nil
+}
+
+func (r *InMemoryTaskRepo) MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, evt := range r.Outbox {
+		if evt.ID == id {
+			r.DeadLetters = append(r.DeadLetters, evt)
+			r.Outbox = append(r.Outbox[:i], r.Outbox[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("outbox event not found: %s", id)
+}
+
+// --- TaskService del consumidor ---
+
+// FakeTaskService simula el TaskService que consume TaskConsumer y registra las llamadas.
+type FakeTaskService struct {
+	Created []*taskDomain.Task
+	Updated []*taskDomain.Task
+	Tasks   map[uuid.UUID]*taskDomain.Task
+	mu      sync.Mutex
+}
+
+func NewFakeTaskService() *FakeTaskService {
+	return &FakeTaskService{
+		Created: []*taskDomain.Task{},
+		Updated: []*taskDomain.Task{},
+		Tasks:   make(map[uuid.UUID]*taskDomain.Task),
+	}
+}
+
+func (f *FakeTaskService) CreateTask(ctx context.Context, title, description string, assigneeID *uuid.UUID) (*taskDomain.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &taskDomain.Task{
+		ID:          uuid.New(),
+		Title:       title,
+		Description: description,
+		AssigneeID:  assigneeID,
+		Status:      taskDomain.TaskPending,
+	}
+	f.Created = append(f.Created, t)
+	f.Tasks[t.ID] = t
+	return t, nil
+}
+
+func (f *FakeTaskService) UpdateTask(ctx context.Context, t *taskDomain.Task) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Updated = append(f.Updated, t)
+	f.Tasks[t.ID] = t
+	return nil
+}
+
+func (f *FakeTaskService) GetTaskByID(ctx context.Context, id uuid.UUID) (*taskDomain.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.Tasks[id]
+	if !ok {
+		return nil, taskDomain.ErrTaskNotFound
+	}
+	return t, nil
+}
+
+// Verificación estática
+var (
+	_ taskDomain.TaskRepository         = (*InMemoryTaskRepo)(nil)
+	_ sharedDomain.OutboxRepository     = (*InMemoryTaskRepo)(nil)
+	_ sharedDomain.OutboxBacklogCounter = (*InMemoryTaskRepo)(nil)
+	_ sharedDomain.OutboxDeadLetterer   = (*InMemoryTaskRepo)(nil)
+	_ taskConsumer.TaskService          = (*FakeTaskService)(nil)
+)
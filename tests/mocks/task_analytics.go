@@ -0,0 +1,178 @@
+package mocks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	taskDomain "github.com/davicafu/hexagolab/internal/task/domain"
+	"github.com/google/uuid"
+)
+
+// InMemoryTaskAnalyticsRepo simula TaskAnalyticsRepository guardando el histórico en un slice.
+type InMemoryTaskAnalyticsRepo struct {
+	Log []taskDomain.TaskLogEntry
+	mu  sync.Mutex
+}
+
+func NewInMemoryTaskAnalyticsRepo() *InMemoryTaskAnalyticsRepo {
+	return &InMemoryTaskAnalyticsRepo{Log: []taskDomain.TaskLogEntry{}}
+}
+
+func (r *InMemoryTaskAnalyticsRepo) LogBatch(ctx context.Context, tasks []*taskDomain.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	eventTime := time.Now().UTC()
+	for _, t := range tasks {
+		r.Log = append(r.Log, taskDomain.TaskLogEntry{
+			ID: t.ID, Title: t.Title, Description: t.Description, AssigneeID: t.AssigneeID,
+			Status: t.Status, CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt, EventTime: eventTime,
+		})
+	}
+	return nil
+}
+
+func (r *InMemoryTaskAnalyticsRepo) GetAverageCompletionTime(ctx context.Context, start, end time.Time) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var total time.Duration
+	var count int
+	for _, e := range r.inRange(start, end) {
+		if e.Status == taskDomain.TaskCompleted {
+			total += e.UpdatedAt.Sub(e.CreatedAt)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return total / time.Duration(count), nil
+}
+
+func (r *InMemoryTaskAnalyticsRepo) GetDailyTrend(ctx context.Context, start, end time.Time) ([]taskDomain.DailyTaskTrend, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byDay := map[time.Time]*taskDomain.DailyTaskTrend{}
+	for _, e := range r.inRange(start, end) {
+		day := e.EventTime.Truncate(24 * time.Hour)
+		trend, ok := byDay[day]
+		if !ok {
+			trend = &taskDomain.DailyTaskTrend{Day: day}
+			byDay[day] = trend
+		}
+		switch e.Status {
+		case taskDomain.TaskPending:
+			trend.CreatedCount++
+		case taskDomain.TaskCompleted:
+			trend.CompletedCount++
+		}
+	}
+
+	trends := make([]taskDomain.DailyTaskTrend, 0, len(byDay))
+	for _, t := range byDay {
+		trends = append(trends, *t)
+	}
+	sort.Slice(trends, func(i, j int) bool { return trends[i].Day.Before(trends[j].Day) })
+	return trends, nil
+}
+
+func (r *InMemoryTaskAnalyticsRepo) GetAssigneeStats(ctx context.Context, assigneeID uuid.UUID, start, end time.Time) (taskDomain.AssigneeStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := taskDomain.AssigneeStats{AssigneeID: assigneeID}
+	created := map[uuid.UUID]bool{}
+	completed := map[uuid.UUID]time.Duration{}
+	failed := map[uuid.UUID]bool{}
+	for _, e := range r.inRange(start, end) {
+		if e.AssigneeID == nil || *e.AssigneeID != assigneeID {
+			continue
+		}
+		if !e.CreatedAt.Before(start) && !e.CreatedAt.After(end) {
+			created[e.ID] = true
+		}
+		switch e.Status {
+		case taskDomain.TaskCompleted:
+			completed[e.ID] = e.UpdatedAt.Sub(e.CreatedAt)
+		case taskDomain.TaskFailed:
+			failed[e.ID] = true
+		}
+	}
+
+	stats.CreatedCount = len(created)
+	stats.CompletedCount = len(completed)
+	stats.FailedCount = len(failed)
+	if len(completed) > 0 {
+		var total time.Duration
+		for _, d := range completed {
+			total += d
+		}
+		stats.AvgCompletionTime = total / time.Duration(len(completed))
+	}
+	return stats, nil
+}
+
+func (r *InMemoryTaskAnalyticsRepo) StreamTaskLog(ctx context.Context, start, end time.Time, fn func(taskDomain.TaskLogEntry) error) error {
+	r.mu.Lock()
+	entries := r.inRange(start, end)
+	r.mu.Unlock()
+
+	for _, e := range entries {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inRange devuelve las entradas con event_time en [start, end]. Requiere tener el lock.
+func (r *InMemoryTaskAnalyticsRepo) inRange(start, end time.Time) []taskDomain.TaskLogEntry {
+	var out []taskDomain.TaskLogEntry
+	for _, e := range r.Log {
+		if !e.EventTime.Before(start) && !e.EventTime.After(end) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// NewTaskLogEntry es un helper para construir entradas de histórico en los tests.
+func NewTaskLogEntry(title string, status taskDomain.TaskStatus, eventTime time.Time) taskDomain.TaskLogEntry {
+	assigneeID := uuid.New()
+	return taskDomain.TaskLogEntry{
+		ID: uuid.New(), Title: title, AssigneeID: &assigneeID, Status: status,
+		CreatedAt: eventTime, UpdatedAt: eventTime, EventTime: eventTime,
+	}
+}
+
+// InMemoryBlobStorage simula BlobStorage guardando los objetos en un mapa.
+type InMemoryBlobStorage struct {
+	Blobs map[string][]byte
+	mu    sync.Mutex
+}
+
+func NewInMemoryBlobStorage() *InMemoryBlobStorage {
+	return &InMemoryBlobStorage{Blobs: make(map[string][]byte)}
+}
+
+func (s *InMemoryBlobStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Blobs[key] = buf.Bytes()
+	return nil
+}
+
+// Verificación estática
+var (
+	_ taskDomain.TaskAnalyticsRepository = (*InMemoryTaskAnalyticsRepo)(nil)
+	_ taskDomain.BlobStorage             = (*InMemoryBlobStorage)(nil)
+)
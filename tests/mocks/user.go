@@ -2,25 +2,26 @@ package mocks
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"log"
-	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	sharedDomain "github.com/davicafu/hexagolab/internal/shared/domain"
+	sharedMemory "github.com/davicafu/hexagolab/internal/shared/infra/platform/db/memory"
 	sharedQuery "github.com/davicafu/hexagolab/internal/shared/infra/platform/query"
 	userDomain "github.com/davicafu/hexagolab/internal/user/domain"
+	userConsumer "github.com/davicafu/hexagolab/internal/user/infra/inbound/events"
 	"github.com/google/uuid"
 )
 
-// InMemoryUserRepo simula UserRepository con outbox incluido.
+// InMemoryUserRepo simula UserRepository con outbox incluido. Como un repositorio real, guarda y
+// devuelve copias de los usuarios: cambiar uno leído no cambia el guardado.
 type InMemoryUserRepo struct {
 	Users  map[uuid.UUID]*userDomain.User
 	Outbox []sharedDomain.OutboxEvent
-	mu     sync.Mutex
+	// DeadLetters son los eventos apartados con MarkOutboxDeadLetter.
+	DeadLetters []sharedDomain.OutboxEvent
+	mu          sync.Mutex
 }
 
 func NewInMemoryUserRepo() *InMemoryUserRepo {
@@ -34,10 +35,11 @@ func NewInMemoryUserRepo() *InMemoryUserRepo {
 func (r *InMemoryUserRepo) Create(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.Users[u.ID]; ok {
+	if _, ok := r.Users[u.ID]; ok || r.emailTaken(u.Email, u.ID) {
 		return userDomain.ErrUserAlreadyExists
 	}
-	r.Users[u.ID] = u
+	stored := *u
+	r.Users[u.ID] = &stored
 	r.Outbox = append(r.Outbox, evt)
 	return nil
 }
@@ -50,17 +52,40 @@ func (r *InMemoryUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*userDoma
 	if !ok {
 		return nil, userDomain.ErrUserNotFound
 	}
-	return u, nil
+	found := *u
+	return &found, nil
+}
+
+// GetByIDs
+func (r *InMemoryUserRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*userDomain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var users []*userDomain.User
+	for _, id := range ids {
+		if u, ok := r.Users[id]; ok {
+			found := *u
+			users = append(users, &found)
+		}
+	}
+	return users, nil
 }
 
 // Update con outbox
 func (r *InMemoryUserRepo) Update(ctx context.Context, u *userDomain.User, evt sharedDomain.OutboxEvent) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.Users[u.ID]; !ok {
+	current, ok := r.Users[u.ID]
+	if !ok {
 		return userDomain.ErrUserNotFound
 	}
-	r.Users[u.ID] = u
+	if current.Version != u.Version-1 {
+		return userDomain.ErrUserVersionConflict
+	}
+	if r.emailTaken(u.Email, u.ID) {
+		return userDomain.ErrUserAlreadyExists
+	}
+	stored := *u
+	r.Users[u.ID] = &stored
 	r.Outbox = append(r.Outbox, evt)
 	return nil
 }
@@ -77,241 +102,61 @@ func (r *InMemoryUserRepo) DeleteByID(ctx context.Context, id uuid.UUID, evt sha
 	return nil
 }
 
-// ListByCriteria en el mock (mocks package)
+// ListByCriteria en el mock (mocks package). Filtra, ordena y pagina igual que los repositorios
+// reales (ver tests/conformance).
 func (r *InMemoryUserRepo) ListByCriteria(
 	ctx context.Context,
 	criteria sharedDomain.Criteria,
 	pagination sharedQuery.Pagination,
-	s sharedQuery.Sort, // renombrado para no colisionar con package sort
+	sorts []sharedQuery.Sort,
 ) ([]*userDomain.User, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	var list []*userDomain.User
+	list := make([]*userDomain.User, 0, len(r.Users))
 	for _, u := range r.Users {
-		// Si no hay criterio, consideramos que coincide todo
-		if criteria == nil {
-			list = append(list, u)
-			continue
-		}
-
-		conds := criteria.ToConditions()
-		matchesAll := true
-		for _, cond := range conds {
-			if !matchCriterion(u, cond) {
-				matchesAll = false
-				break
-			}
-		}
-		if matchesAll {
-			list = append(list, u)
-		}
+		found := *u
+		list = append(list, &found)
 	}
+	return sharedMemory.Query(list, userField, criteria, pagination, sorts)
+}
 
-	// ordenar
-	if s.Field != "" {
-		switch s.Field {
-		case "nombre":
-			sort.Slice(list, func(i, j int) bool {
-				if s.Desc {
-					return list[i].Nombre > list[j].Nombre
-				}
-				return list[i].Nombre < list[j].Nombre
-			})
-		case "email":
-			sort.Slice(list, func(i, j int) bool {
-				if s.Desc {
-					return list[i].Email > list[j].Email
-				}
-				return list[i].Email < list[j].Email
-			})
-		case "created_at":
-			sort.Slice(list, func(i, j int) bool {
-				if s.Desc {
-					return list[i].CreatedAt.After(list[j].CreatedAt)
-				}
-				return list[i].CreatedAt.Before(list[j].CreatedAt)
-			})
-		}
-	}
-
-	// paginación
-	switch p := pagination.(type) {
-	case sharedQuery.OffsetPagination:
-		start := p.Offset
-		if start > len(list) {
-			return []*userDomain.User{}, nil
-		}
-		end := start + p.Limit
-		if end > len(list) {
-			end = len(list)
-		}
-		return list[start:end], nil
-
-	case sharedQuery.CursorPagination:
-		// 1️⃣ Ordenar la lista según sortField y SortDesc, usando ID como tie-breaker
-		sort.SliceStable(list, func(i, j int) bool {
-			var vi, vj string
-			switch p.SortField {
-			case "created_at":
-				vi = list[i].CreatedAt.Format(time.RFC3339Nano)
-				vj = list[j].CreatedAt.Format(time.RFC3339Nano)
-			case "nombre":
-				vi = list[i].Nombre
-				vj = list[j].Nombre
-			case "email":
-				vi = list[i].Email
-				vj = list[j].Email
-			default:
-				vi = list[i].ID.String()
-				vj = list[j].ID.String()
-			}
-
-			if p.SortDesc {
-				if vi != vj {
-					return vi > vj
-				}
-				return list[i].ID.String() > list[j].ID.String()
-			}
-			if vi != vj {
-				return vi < vj
-			}
-			return list[i].ID.String() < list[j].ID.String()
-		})
-
-		// 2️⃣ Filtrar según cursor compuesto
-		filtered := []*userDomain.User{}
-		startCollect := p.Cursor == ""
-		var cursorSort, cursorID string
-		if p.Cursor != "" {
-			parts := strings.SplitN(p.Cursor, "|", 2)
-			cursorSort = parts[0]
-			cursorID = parts[1]
-		}
-
-		for _, u := range list {
-			if !startCollect {
-				uSort := ""
-				switch p.SortField {
-				case "created_at":
-					uSort = u.CreatedAt.Format(time.RFC3339Nano)
-				case "nombre":
-					uSort = u.Nombre
-				case "email":
-					uSort = u.Email
-				default:
-					uSort = u.ID.String()
-				}
-
-				if !p.SortDesc {
-					if uSort > cursorSort || (uSort == cursorSort && u.ID.String() > cursorID) {
-						startCollect = true
-					}
-				} else {
-					if uSort < cursorSort || (uSort == cursorSort && u.ID.String() < cursorID) {
-						startCollect = true
-					}
-				}
-				if !startCollect {
-					continue
-				}
-			}
-
-			filtered = append(filtered, u)
-			if len(filtered) >= p.Limit {
-				break
-			}
-		}
-
-		return filtered, nil
+func (r *InMemoryUserRepo) CountByCriteria(ctx context.Context, criteria sharedDomain.Criteria) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	default:
-		// si no se reconoce, devolvemos todo (sin paginar)
-		return list, nil
+	list := make([]*userDomain.User, 0, len(r.Users))
+	for _, item := range r.Users {
+		list = append(list, item)
 	}
+	return sharedMemory.Count(list, userField, criteria)
 }
 
-// matchCriterion evalúa un domain.Criterion contra un usuario en memoria.
-func matchCriterion(u *userDomain.User, crit sharedDomain.Criterion) bool {
-	op := strings.ToUpper(strings.TrimSpace(string(crit.Op)))
-	field := crit.Field
-
+// userField expone los campos del usuario con los nombres de las columnas SQL.
+func userField(u *userDomain.User, field string) (any, bool) {
 	switch field {
 	case "id":
-		// puede venir como uuid.UUID o como string
-		switch v := crit.Value.(type) {
-		case uuid.UUID:
-			return u.ID == v
-		case string:
-			return u.ID.String() == v
-		default:
-			// intentar comparar por formato string
-			return u.ID.String() == fmt.Sprintf("%v", crit.Value)
-		}
-
+		return u.ID, true
 	case "email":
-		val := fmt.Sprintf("%v", crit.Value)
-		if op == "ILIKE" || op == "LIKE" {
-			// pattern esperado con %...% -> hacer Contains
-			p := strings.Trim(val, "%")
-			if op == "ILIKE" {
-				return strings.Contains(strings.ToLower(u.Email), strings.ToLower(p))
-			}
-			return strings.Contains(u.Email, p)
-		}
-		// igualdad simple
-		return u.Email == val
-
-	case "nombre":
-		val := fmt.Sprintf("%v", crit.Value)
-		if op == "ILIKE" || op == "LIKE" {
-			p := strings.Trim(val, "%")
-			if op == "ILIKE" {
-				return strings.Contains(strings.ToLower(u.Nombre), strings.ToLower(p))
-			}
-			return strings.Contains(u.Nombre, p)
-		}
-		return u.Nombre == val
-
-	case "birth_date", "birthdate":
-		// Value esperado time.Time
-		valTime, ok := crit.Value.(time.Time)
-		if !ok {
-			// intentar parsear si viene como string
-			if s, ok2 := crit.Value.(string); ok2 {
-				t, err := time.Parse(time.RFC3339, s)
-				if err == nil {
-					valTime = t
-					ok = true
-				}
-			}
-		}
-		if !ok {
-			// no sabemos comparar -> asumir que coincide
-			return true
-		}
+		return u.Email, true
+	case "name":
+		return u.Name, true
+	case "birth_date":
+		return u.BirthDate, true
+	case "created_at":
+		return u.CreatedAt, true
+	}
+	return nil, false
+}
 
-		switch op {
-		case "<", "<=":
-			if u.BirthDate.Before(valTime) || u.BirthDate.Equal(valTime) {
-				return true
-			}
-			return false
-		case ">", ">=":
-			if u.BirthDate.After(valTime) || u.BirthDate.Equal(valTime) {
-				return true
-			}
-			return false
-		case "=":
-			return u.BirthDate.Equal(valTime)
-		default:
+// emailTaken indica si otro usuario distinto de 'id' ya usa el email, como el UNIQUE de SQL.
+func (r *InMemoryUserRepo) emailTaken(email string, id uuid.UUID) bool {
+	for _, u := range r.Users {
+		if u.ID != id && strings.EqualFold(u.Email, email) {
 			return true
 		}
-
-	default:
-		// criterio desconocido: no filtrar (mejor ser permisivo en mock)
-		return true
 	}
+	return false
 }
 
 // ------------------- Outbox -------------------
@@ -341,20 +186,82 @@ func (r *InMemoryUserRepo) MarkOutboxProcessed(ctx context.Context, id uuid.UUID
 	return userDomain.ErrUserNotFound
 }
 
-// ------------------- EventPublisher -------------------
+// CountPendingOutbox
+func (r *InMemoryUserRepo) CountPendingOutbox(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.Outbox), nil
+}
 
-type DummyPublisher struct {
-	Published []string
-	mu        sync.Mutex
+// MarkOutboxDeadLetter
+func (r *InMemoryUserRepo) MarkOutboxDeadLetter(ctx context.Context, id uuid.UUID, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, evt := range r.Outbox {
+		if evt.ID == id {
+			r.DeadLetters = append(r.DeadLetters, evt)
+			r.Outbox = append(r.Outbox[:i], r.Outbox[i+1:]...)
+			return nil
+		}
+	}
+	return userDomain.ErrUserNotFound
+}
+
+// ------------------- UserService -------------------
+
+// FakeUserService simula el UserService que consume UserConsumer y registra las llamadas.
+type FakeUserService struct {
+	Created []*userDomain.User
+	Updated []*userDomain.User
+	Users   map[uuid.UUID]*userDomain.User
+	mu      sync.Mutex
+}
+
+func NewFakeUserService() *FakeUserService {
+	return &FakeUserService{
+		Created: []*userDomain.User{},
+		Updated: []*userDomain.User{},
+		Users:   make(map[uuid.UUID]*userDomain.User),
+	}
+}
+
+func (f *FakeUserService) CreateUser(ctx context.Context, email, nombre string, birthDate time.Time) (*userDomain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u := &userDomain.User{
+		ID:        uuid.New(),
+		Email:     email,
+		Name:      nombre,
+		BirthDate: birthDate,
+	}
+	f.Created = append(f.Created, u)
+	f.Users[u.ID] = u
+	return u, nil
 }
 
-func (p *DummyPublisher) Publish(ctx context.Context, topic string, event interface{}) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+func (f *FakeUserService) GetUser(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.Users[id]
+	if !ok {
+		return nil, userDomain.ErrUserNotFound
+	}
+	return u, nil
+}
 
-	// Guardar una versión JSON como evidencia
-	data, _ := json.Marshal(event)
-	p.Published = append(p.Published, string(data))
-	log.Printf("Mock Published to %s: %s", topic, data)
+func (f *FakeUserService) UpdateUser(ctx context.Context, u *userDomain.User) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Updated = append(f.Updated, u)
+	f.Users[u.ID] = u
 	return nil
 }
+
+// Verificación estática
+var (
+	_ userDomain.UserRepository         = (*InMemoryUserRepo)(nil)
+	_ sharedDomain.OutboxRepository     = (*InMemoryUserRepo)(nil)
+	_ sharedDomain.OutboxBacklogCounter = (*InMemoryUserRepo)(nil)
+	_ sharedDomain.OutboxDeadLetterer   = (*InMemoryUserRepo)(nil)
+	_ userConsumer.UserService          = (*FakeUserService)(nil)
+)